@@ -0,0 +1,10 @@
+// Package api holds the OpenAPI description of the client API, the source
+// the Swift and TypeScript SDKs are generated from.
+package api
+
+import _ "embed"
+
+// OpenAPI is the contents of openapi.yaml.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// appConfig is the Fiber configuration for the server. c.IP() is the
+// client's address from cfg.ProxyHeader when the request comes from one of
+// cfg.TrustedProxies, and the connection's address otherwise.
+func appConfig(cfg *config.Config) fiber.Config {
+	var proxies []string
+	for _, p := range strings.Split(cfg.TrustedProxies, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+
+	return fiber.Config{
+		BodyLimit:    4 * 1024 * 1024, // 4MB
+		ErrorHandler: middleware.ErrorHandler(cfg),
+		// Unknown fields are ignored and request defaults applied, so
+		// clients one version apart keep working during a rolling deploy.
+		JSONDecoder: dto.DecodeJSON,
+
+		ProxyHeader:             cfg.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          proxies,
+		// A header that holds no valid IP falls back to the connection's.
+		EnableIPValidation: true,
+	}
+}
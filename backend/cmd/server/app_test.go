@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/middleware"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/ratelimit"
+	"github.com/gofiber/fiber/v2"
+)
+
+// rateLimitedApp allows one request per client IP. Test requests come from
+// 0.0.0.0.
+func rateLimitedApp(trustedProxies string) *fiber.App {
+	app := fiber.New(appConfig(&config.Config{TrustedProxies: trustedProxies, ProxyHeader: "X-Real-IP"}))
+	app.Get("/", middleware.RateLimit(ratelimit.NewMemoryLimiter(), middleware.RateLimitConfig{
+		Name:  "test",
+		PerIP: ratelimit.Rate{Limit: 1, Period: time.Minute},
+	}), func(c *fiber.Ctx) error {
+		return c.SendString(c.IP())
+	})
+	return app
+}
+
+func statusFrom(t *testing.T, app *fiber.App, clientIP string) int {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set("X-Real-IP", clientIP)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+func TestForwardedClientsGetSeparateRateLimits(t *testing.T) {
+	app := rateLimitedApp("0.0.0.0/32")
+	for _, ip := range []string{"203.0.113.1", "203.0.113.2"} {
+		if got := statusFrom(t, app, ip); got != fiber.StatusOK {
+			t.Fatalf("first request from %s: status %d", ip, got)
+		}
+	}
+	if got := statusFrom(t, app, "203.0.113.1"); got != fiber.StatusTooManyRequests {
+		t.Errorf("second request from 203.0.113.1: status %d, want 429", got)
+	}
+}
+
+func TestProxyHeaderIgnoredFromUntrustedPeers(t *testing.T) {
+	app := rateLimitedApp("")
+	if got := statusFrom(t, app, "203.0.113.1"); got != fiber.StatusOK {
+		t.Fatalf("first request: status %d", got)
+	}
+	if got := statusFrom(t, app, "203.0.113.2"); got != fiber.StatusTooManyRequests {
+		t.Errorf("a spoofed X-Real-IP got its own bucket: status %d", got)
+	}
+}
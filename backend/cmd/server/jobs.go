@@ -0,0 +1,31 @@
+package main
+
+import (
+	"log"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/scheduler"
+	"gorm.io/gorm"
+)
+
+// scheduledJob is a job the scheduler runs, with its schedule in UTC.
+type scheduledJob struct {
+	name string
+	spec string
+	run  scheduler.Job
+}
+
+// newScheduler registers the jobs on a scheduler whose locks are Postgres
+// advisory locks, so any number of scheduler processes can run it.
+func newScheduler(db *gorm.DB, jobs []scheduledJob) *scheduler.Scheduler {
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("Failed to get database handle: %v", err)
+	}
+	sched := scheduler.New(scheduler.NewAdvisoryLocker(sqlDB))
+	for _, job := range jobs {
+		if err := sched.Register(job.name, job.spec, job.run); err != nil {
+			log.Fatalf("Failed to schedule jobs: %v", err)
+		}
+	}
+	return sched
+}
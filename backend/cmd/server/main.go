@@ -1,28 +1,52 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/cache"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/emails"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/handlers"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/middleware"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/queue"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/ratelimit"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/replay"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/routes"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/tracing"
+	"github.com/gofiber/contrib/otelfiber/v2"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
 	fiberlogger "github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"time"
 )
 
 func main() {
 	cfg := config.Load()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(cfg, os.Args[2:])
+		return
+	}
+
+	flags := flag.NewFlagSet("server", flag.ExitOnError)
+	modeFlag := flags.String("mode", cfg.RunMode, "what to run: all, or a comma-separated list of api, worker and scheduler")
+	flags.Parse(os.Args[1:])
+	mode, err := parseRunMode(*modeFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := mode.checkDataDirs(cfg.SharedDataDirs); err != nil {
+		log.Fatal(err)
+	}
+
 	if cfg.JWTSecret == "" {
 		log.Fatal("JWT_SECRET environment variable is required")
 	}
@@ -30,51 +54,228 @@ func main() {
 		log.Fatal("DB_PASSWORD environment variable is required")
 	}
 
+	// Tracing (before the database so GORM spans use the configured provider)
+	shutdownTracing, err := tracing.Init(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
 	// Database
 	db := database.InitDB(cfg)
 
 	// Services
-	authService := services.NewAuthService(db, cfg)
 	subscriptionService := services.NewSubscriptionService(db)
-	moderationService := services.NewModerationService(db)
-	auraService := services.NewAuraService(db, cfg)
-	auraMatchService := services.NewAuraMatchService(db, cfg)
-	streakService := services.NewStreakService(db)
+	moderationService := services.NewModerationService(db, cfg)
+	objectStore, err := services.NewObjectStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure object store: %v", err)
+	}
+	analyticsSink, err := services.NewAnalyticsSink(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure analytics: %v", err)
+	}
+	analytics := services.NewAnalytics(analyticsSink)
+	photoStorageService := services.NewPhotoStorageService(db, cfg, objectStore)
+	auraService := services.NewAuraService(db, cfg, photoStorageService, analytics)
+	streakService := services.NewStreakService(db, analytics)
+	shareService := services.NewShareService(db, cfg)
+	memoryService := services.NewMemoryService(db)
+	userService := services.NewUserService(db, cfg)
+	contactDiscoveryService := services.NewContactDiscoveryService(db, cfg)
+	emailRenderer, err := emails.NewRenderer()
+	if err != nil {
+		log.Fatalf("Failed to load email templates: %v", err)
+	}
+	mailer, err := services.NewMailer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure mailer: %v", err)
+	}
+	emailService := services.NewEmailService(db, emailRenderer, mailer)
+	authService := services.NewAuthService(db, cfg, emailService)
+	notificationService := services.NewNotificationService(db, cfg)
+	notificationService.RegisterSender(emailService.NotificationSender(db))
+	pushSender, err := services.NewPushSender(db, cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure push: %v", err)
+	}
+	if pushSender != nil {
+		notificationService.RegisterSender(pushSender)
+	}
+	auraMatchService := services.NewAuraMatchService(db, cfg, notificationService)
+	adminUserService := services.NewAdminUserService(db, cfg, notificationService)
+	reminderService := services.NewReminderService(db, cfg, notificationService)
+	probeService := services.NewProbeService(db, cfg, auraService)
+	dataMigrationService := services.NewDataMigrationService(db)
+	forecastService := services.NewForecastService(db, cfg, analytics)
+	embedder, err := services.NewEmbedder(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure embeddings: %v", err)
+	}
+	readingEmbeddingService := services.NewReadingEmbeddingService(db, cfg, embedder)
+	insightService := services.NewInsightService(db, cfg, embedder, readingEmbeddingService, notificationService)
+	friendService := services.NewFriendService(db, cfg, notificationService, emailService)
+	dataExportService := services.NewDataExportService(db, cfg, friendService, notificationService)
+	researchExportService := services.NewResearchExportService(db, cfg, objectStore)
+	stripeService := services.NewStripeService(db, cfg)
+	entitlementService := services.NewEntitlementService(db, cfg)
+	fieldEncryptionService := services.NewFieldEncryptionService(db)
+
+	// AI spend tracking and the daily AI budget
+	aiUsageService := services.NewAIUsageService(db, cfg)
+	auraService.UseAIUsage(aiUsageService)
+	auraMatchService.UseAIUsage(aiUsageService)
+	forecastService.UseAIUsage(aiUsageService)
+	promptTemplateService := services.NewPromptTemplateService(db)
+	auraService.UsePromptTemplates(promptTemplateService)
+	experimentService := services.NewExperimentService(db)
+	auraService.UseExperiments(experimentService)
+
+	// Task queue between the API and the workers
+	taskQueue, err := queue.New(cfg, db)
+	if err != nil {
+		log.Fatalf("Failed to configure task queue: %v", err)
+	}
+	auraService.UseQueue(taskQueue)
+	notificationService.UseQueue(taskQueue)
+	dataExportService.UseQueue(taskQueue)
+
+	// Scheduled jobs
+	jobs := []scheduledJob{
+		{"account-purge", "@hourly", authService.AccountPurgeJob},
+		{"reminders", "*/5 * * * *", reminderService.ReminderJob},
+		{"forecasts", "*/15 * * * *", forecastService.ForecastJob},
+		{"insights", "*/15 * * * *", insightService.InsightJob},
+		{"aggregate-reconcile", "30 3 * * *", auraService.AggregateReconcileJob},
+	}
+	if objectStore != nil {
+		jobs = append(jobs, scheduledJob{"research-export", "@hourly", researchExportService.ResearchExportJob})
+	}
+	sched := newScheduler(db, jobs)
 
 	// Handlers
 	authHandler := handlers.NewAuthHandler(authService)
-	healthHandler := handlers.NewHealthHandler()
-	webhookHandler := handlers.NewWebhookHandler(subscriptionService, cfg)
+	healthService := services.NewHealthService(cfg, objectStore)
+	healthHandler := handlers.NewHealthHandler(healthService)
+	webhookHandler := handlers.NewWebhookHandler(subscriptionService, emailService, services.NewProcessedEventService(db), cfg)
 	moderationHandler := handlers.NewModerationHandler(moderationService)
-	auraHandler := handlers.NewAuraHandler(auraService)
+	auraHandler := handlers.NewAuraHandler(auraService, entitlementService, readingEmbeddingService)
 	auraMatchHandler := handlers.NewAuraMatchHandler(auraMatchService)
 	streakHandler := handlers.NewStreakHandler(streakService)
 	legalHandler := handlers.NewLegalHandler()
+	shareHandler := handlers.NewShareHandler(shareService)
+	memoryHandler := handlers.NewMemoryHandler(memoryService)
+	aiHistoryHandler := handlers.NewAIHistoryHandler(services.NewAIHistoryService(db))
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	emailHandler := handlers.NewEmailHandler(emailService)
+	adminUserHandler := handlers.NewAdminUserHandler(adminUserService, services.NewAdminMetricsService(db, cfg))
+	userHandler := handlers.NewUserHandler(userService)
+	contactDiscoveryHandler := handlers.NewContactDiscoveryHandler(contactDiscoveryService)
+	friendHandler := handlers.NewFriendHandler(friendService)
+	exportHandler := handlers.NewExportHandler(dataExportService)
+	onboardingHandler := handlers.NewOnboardingHandler(services.NewOnboardingService(db))
+	surveyHandler := handlers.NewSurveyHandler(services.NewSurveyService(db))
+	forecastHandler := handlers.NewForecastHandler(forecastService)
+	insightHandler := handlers.NewInsightHandler(insightService)
+	similarDiscoveryHandler := handlers.NewSimilarDiscoveryHandler(services.NewSimilarDiscoveryService(db, cfg, readingEmbeddingService))
+	usageHandler := handlers.NewUsageHandler(photoStorageService)
+	researchExportHandler := handlers.NewResearchExportHandler(researchExportService)
+	billingHandler := handlers.NewBillingHandler(stripeService, entitlementService)
+	responseCache := cache.New()
+	cdn, err := services.NewCDN(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure CDN: %v", err)
+	}
+	cacheHandler := handlers.NewCacheHandler(responseCache, cdn)
+	auditHandler := handlers.NewAuditHandler(services.NewAuditService(db))
+	requestLogService := services.NewRequestLogService(db, cfg)
+	requestLogHandler := handlers.NewRequestLogHandler(requestLogService)
+	promptTemplateHandler := handlers.NewPromptTemplateHandler(promptTemplateService)
+	experimentHandler := handlers.NewExperimentHandler(experimentService)
+	dataMigrationHandler := handlers.NewDataMigrationHandler(dataMigrationService)
 
 	// Fiber app
-	app := fiber.New(fiber.Config{
-		BodyLimit:    4 * 1024 * 1024, // 4MB
-		ErrorHandler: customErrorHandler,
-	})
+	app := fiber.New(appConfig(cfg))
 
 	// Global middleware
 	app.Use(recover.New())
-	app.Use(requestid.New())
-	app.Use(fiberlogger.New(fiberlogger.Config{
-		Format: "${time} | ${status} | ${latency} | ${ip} | ${method} | ${path}\n",
-	}))
-	app.Use(middleware.CORS(cfg))
-
-	// Rate limiter on auth endpoints
-	authLimiter := limiter.New(limiter.Config{
-		Max:               20,
-		Expiration:        1 * time.Minute,
-		LimiterMiddleware: limiter.SlidingWindow{},
-	})
-	app.Use("/api/auth", authLimiter)
-
-	// Routes
-	routes.Setup(app, cfg, authHandler, healthHandler, webhookHandler, moderationHandler, auraHandler, auraMatchHandler, streakHandler, legalHandler)
+	app.Use(middleware.Draining(healthService.Draining))
+	if mode.api {
+		app.Use(otelfiber.Middleware(otelfiber.WithNext(func(c *fiber.Ctx) bool {
+			return c.Path() == "/metrics"
+		})))
+		app.Use(middleware.Metrics())
+		app.Use(middleware.RequestID())
+		app.Use(middleware.VersionSkew())
+		app.Use(middleware.RecordFailedRequests(requestLogService.Record))
+		app.Use(fiberlogger.New(fiberlogger.Config{
+			Format: "${time} | ${status} | ${latency} | ${ip} | ${method} | ${path} | ${locals:requestid}\n",
+		}))
+		app.Use(middleware.CORS(cfg))
+		app.Use(middleware.Compress(cfg))
+	}
+
+	// Background work for the run mode. Analytics are queued by every mode.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	go analytics.RunAnalyticsWorker(workerCtx)
+	if mode.api {
+		if n, err := auraService.ReleaseStalePending(); err != nil {
+			log.Printf("Failed to release pending readings: %v", err)
+		} else if n > 0 {
+			log.Printf("Released %d readings left pending by a previous run", n)
+		}
+		go requestLogService.RunRequestLogWorker(workerCtx, time.Hour)
+	}
+	if mode.workers {
+		if n, err := moderationService.BackfillCases(); err != nil {
+			log.Printf("Failed to group reports into moderation cases: %v", err)
+		} else if n > 0 {
+			log.Printf("Grouped %d pending reports into moderation cases", n)
+		}
+		go notificationService.RunDigestWorker(workerCtx, time.Minute)
+		go subscriptionService.RunWebhookWorker(workerCtx, 15*time.Second)
+		go dataExportService.RunExportWorker(workerCtx, 30*time.Second)
+		if readingEmbeddingService.Enabled() {
+			go readingEmbeddingService.RunEmbeddingWorker(workerCtx, 5*time.Minute)
+		}
+		go fieldEncryptionService.RunReencryptWorker(workerCtx, 10*time.Minute)
+		go dataMigrationService.RunMigrationWorker(workerCtx, 30*time.Second)
+		if cfg.SyntheticProbeInterval > 0 {
+			go probeService.RunProbeWorker(workerCtx, cfg.SyntheticProbeInterval)
+		}
+		if objectStore != nil {
+			go photoStorageService.RunPhotoArchiveWorker(workerCtx, 15*time.Minute)
+		}
+	}
+	// Queued tasks and scheduled jobs are waited for on shutdown.
+	var background sync.WaitGroup
+	if mode.workers {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			err := taskQueue.Consume(workerCtx, map[string]queue.Handler{
+				services.TaskScanAnalyze:      auraService.HandleScanTask,
+				services.TaskExportBuild:      dataExportService.HandleExportTask,
+				services.TaskNotificationSend: notificationService.HandleNotificationTask,
+			})
+			if err != nil {
+				log.Printf("Task queue consumer failed: %v", err)
+			}
+		}()
+	}
+	if mode.scheduler {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			sched.Run(workerCtx)
+		}()
+	}
+
+	// Routes; without the API only metrics and health checks are served
+	if mode.api {
+		routes.Setup(app, cfg, ratelimit.New(cfg), replay.New(cfg), authHandler, healthHandler, webhookHandler, moderationHandler, auraHandler, auraMatchHandler, streakHandler, legalHandler, shareHandler, memoryHandler, notificationHandler, emailHandler, adminUserHandler, userHandler, contactDiscoveryHandler, friendHandler, exportHandler, onboardingHandler, surveyHandler, forecastHandler, usageHandler, researchExportHandler, billingHandler, responseCache, cacheHandler, auditHandler, aiHistoryHandler, handlers.NewDocsHandler(), dataMigrationHandler, insightHandler, similarDiscoveryHandler, requestLogHandler, promptTemplateHandler, experimentHandler)
+	} else {
+		routes.SetupOps(app, cfg, healthHandler)
+	}
 
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
@@ -86,23 +287,24 @@ func main() {
 		}
 	}()
 
-	log.Printf("Server running on port %s", cfg.Port)
+	log.Printf("Server running on port %s (mode %s)", cfg.Port, mode)
 
 	<-quit
+	// Fail readiness first and keep serving while load balancers notice,
+	// so a rolling deploy does not cut off requests they still route here.
+	healthService.Drain()
+	log.Printf("Draining for %s before shutting down...", cfg.ShutdownDrainDelay)
+	time.Sleep(cfg.ShutdownDrainDelay)
 	log.Println("Shutting down server...")
-	if err := app.Shutdown(); err != nil {
-		log.Fatalf("Server shutdown error: %v", err)
+	stopWorkers()
+	if err := app.ShutdownWithTimeout(cfg.ShutdownTimeout); err != nil {
+		log.Printf("Server shutdown error: %v", err)
 	}
-	log.Println("Server stopped")
-}
-
-func customErrorHandler(c *fiber.Ctx, err error) error {
-	code := fiber.StatusInternalServerError
-	if e, ok := err.(*fiber.Error); ok {
-		code = e.Code
+	background.Wait()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Tracing shutdown error: %v", err)
 	}
-	return c.Status(code).JSON(fiber.Map{
-		"error":   true,
-		"message": err.Error(),
-	})
+	log.Println("Server stopped")
 }
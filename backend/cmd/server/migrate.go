@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
+)
+
+const migrateUsage = `Usage: server migrate <command> [args]
+
+Commands:
+  up                 apply every pending migration
+  up-to VERSION      apply migrations up to VERSION
+  down               roll back the latest migration
+  down-to VERSION    roll back to VERSION
+  redo               roll back and reapply the latest migration
+  status             list migrations and whether they are applied
+  version            print the schema version
+  create NAME        write a new SQL migration (run from backend/)
+`
+
+// runMigrate is the `server migrate` subcommand. It connects without
+// starting the API, so deploys can apply migrations before the new release
+// serves traffic.
+func runMigrate(cfg *config.Config, args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dir := fs.String("dir", database.MigrationsDir, "directory new migrations are written to")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, migrateUsage) }
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	command, rest := fs.Arg(0), fs.Args()[1:]
+
+	if command == "create" {
+		if len(rest) != 1 {
+			log.Fatal("migrate create needs a NAME")
+		}
+		if err := database.CreateMigration(*dir, rest[0]); err != nil {
+			log.Fatalf("migrate create: %v", err)
+		}
+		return
+	}
+
+	if cfg.DBPassword == "" {
+		log.Fatal("DB_PASSWORD environment variable is required")
+	}
+	db := database.Open(cfg)
+	if err := database.Migrate(context.Background(), db, command, rest...); err != nil {
+		log.Fatalf("migrate %s: %v", command, err)
+	}
+}
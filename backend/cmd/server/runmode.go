@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// runMode is what one server process runs. Every mode shares the binary
+// and config, so the HTTP tier, the background workers and the scheduler
+// can be deployed and scaled apart.
+type runMode struct {
+	api       bool // the HTTP API
+	workers   bool // the task queue consumer and the background workers
+	scheduler bool // the cron-scheduled jobs
+}
+
+// parseRunMode reads RUN_MODE or -mode: "all", or a comma-separated list
+// of api, worker and scheduler.
+func parseRunMode(s string) (runMode, error) {
+	var mode runMode
+	for _, part := range strings.Split(s, ",") {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "all":
+			mode = runMode{api: true, workers: true, scheduler: true}
+		case "api":
+			mode.api = true
+		case "worker", "workers":
+			mode.workers = true
+		case "scheduler":
+			mode.scheduler = true
+		default:
+			return runMode{}, fmt.Errorf("unknown run mode %q: want all, or a comma-separated list of api, worker and scheduler", part)
+		}
+	}
+	return mode, nil
+}
+
+// errUnsharedDataDirs refuses a split deployment whose processes would each
+// see their own EXPORT_DIR and PHOTO_DIR: workers write exports the API
+// serves, and the scheduler cleans up and archives both.
+var errUnsharedDataDirs = errors.New("RUN_MODE splits the server across processes: mount EXPORT_DIR and PHOTO_DIR on a volume every process shares and set SHARED_DATA_DIRS=true")
+
+// checkDataDirs fails unless the mode runs everything in one process or the
+// data directories are declared shared.
+func (m runMode) checkDataDirs(shared bool) error {
+	if m.api && m.workers && m.scheduler || shared {
+		return nil
+	}
+	return errUnsharedDataDirs
+}
+
+func (m runMode) String() string {
+	var parts []string
+	if m.api {
+		parts = append(parts, "api")
+	}
+	if m.workers {
+		parts = append(parts, "worker")
+	}
+	if m.scheduler {
+		parts = append(parts, "scheduler")
+	}
+	if len(parts) == 3 {
+		return "all"
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestParseRunMode(t *testing.T) {
+	cases := map[string]runMode{
+		"all":              {api: true, workers: true, scheduler: true},
+		"api":              {api: true},
+		"worker":           {workers: true},
+		" Scheduler ":      {scheduler: true},
+		"api,scheduler":    {api: true, scheduler: true},
+		"worker,scheduler": {workers: true, scheduler: true},
+	}
+	for s, want := range cases {
+		got, err := parseRunMode(s)
+		if err != nil || got != want {
+			t.Errorf("parseRunMode(%q) = %+v, %v; want %+v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "web", "api,"} {
+		if _, err := parseRunMode(s); err == nil {
+			t.Errorf("parseRunMode(%q) accepted", s)
+		}
+	}
+	if got := (runMode{api: true, workers: true, scheduler: true}).String(); got != "all" {
+		t.Errorf("String() = %q, want all", got)
+	}
+}
+
+func TestSplitModeNeedsSharedDataDirs(t *testing.T) {
+	if err := (runMode{api: true, workers: true, scheduler: true}).checkDataDirs(false); err != nil {
+		t.Errorf("all in one process: %v", err)
+	}
+	if err := (runMode{api: true}).checkDataDirs(false); err == nil {
+		t.Error("an api-only process without shared data dirs was accepted")
+	}
+	if err := (runMode{workers: true}).checkDataDirs(true); err != nil {
+		t.Errorf("worker with shared data dirs: %v", err)
+	}
+}
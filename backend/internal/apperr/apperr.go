@@ -0,0 +1,163 @@
+// Package apperr is the API's error model. Handlers and middleware return
+// an *Error, or a service error registered here, and the app's error
+// handler writes it as the JSON envelope every endpoint shares:
+//
+//	{"error": true, "message": "reading not found", "code": "reading_not_found"}
+//
+// Code is stable and machine-readable; clients branch on it, never on the
+// message. Anything else returned from a handler is an internal error whose
+// details stay in the logs.
+package apperr
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Codes shared by many endpoints. Errors without a specific code get the
+// one of their status.
+const (
+	CodeBadRequest       = "bad_request"
+	CodeUnauthorized     = "unauthorized"
+	CodePaymentRequired  = "payment_required"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeConflict         = "conflict"
+	CodeGone             = "gone"
+	CodePayloadTooLarge  = "payload_too_large"
+	CodeValidationFailed = "validation_failed"
+	CodeRateLimited      = "rate_limited"
+	CodeInternal         = "internal_error"
+	CodeBadGateway       = "bad_gateway"
+	CodeUnavailable      = "unavailable"
+)
+
+var statusCodes = map[int]string{
+	fiber.StatusBadRequest:            CodeBadRequest,
+	fiber.StatusUnauthorized:          CodeUnauthorized,
+	fiber.StatusPaymentRequired:       CodePaymentRequired,
+	fiber.StatusForbidden:             CodeForbidden,
+	fiber.StatusNotFound:              CodeNotFound,
+	fiber.StatusConflict:              CodeConflict,
+	fiber.StatusGone:                  CodeGone,
+	fiber.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	fiber.StatusTooManyRequests:       CodeRateLimited,
+	fiber.StatusInternalServerError:   CodeInternal,
+	fiber.StatusBadGateway:            CodeBadGateway,
+	fiber.StatusServiceUnavailable:    CodeUnavailable,
+}
+
+// StatusCode is the generic code of an HTTP status, e.g. "not_found" for
+// 404; statuses without one use their lower-cased status text.
+func StatusCode(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if text := http.StatusText(status); text != "" {
+		return strings.ReplaceAll(strings.ToLower(strings.ReplaceAll(text, "-", " ")), " ", "_")
+	}
+	return CodeInternal
+}
+
+// Error is an error the client sees: a status, a code and a message safe to
+// show. Err is the cause, logged but never sent.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	// Fields lists the failed fields of a validation error.
+	Fields []dto.FieldError
+	Err    error
+}
+
+// New returns an error with status's generic code.
+func New(status int, message string) *Error {
+	return &Error{Status: status, Code: StatusCode(status), Message: message}
+}
+
+// Wrap reports err with status and err's own message, which the caller has
+// checked is meant for clients. A registered err keeps its code.
+func Wrap(status int, err error) *Error {
+	e := New(status, err.Error())
+	if r, ok := lookup(err); ok {
+		e.Code = r.code
+	}
+	e.Err = err
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// WithCode returns a copy of e with a specific code.
+func (e *Error) WithCode(code string) *Error {
+	c := *e
+	c.Code = code
+	return &c
+}
+
+// WithCause returns a copy of e recording err as its cause.
+func (e *Error) WithCause(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+type registration struct {
+	target error
+	status int
+	code   string
+}
+
+var (
+	mu         sync.RWMutex
+	registered []registration
+)
+
+// Register maps a sentinel error, and anything wrapping it, to the status
+// and code it is reported with. Its message is shown as is.
+func Register(target error, status int, code string) {
+	mu.Lock()
+	defer mu.Unlock()
+	registered = append(registered, registration{target: target, status: status, code: code})
+}
+
+func lookup(err error) (registration, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, r := range registered {
+		if errors.Is(err, r.target) {
+			return r, true
+		}
+	}
+	return registration{}, false
+}
+
+// From resolves any error a handler returned. Public reports whether its
+// message may be shown: false for errors nothing here knows, which are
+// internal and reported as a 500.
+func From(err error) (e *Error, public bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	if r, ok := lookup(err); ok {
+		return &Error{Status: r.status, Code: r.code, Message: err.Error(), Err: err}, true
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return &Error{Status: fiberErr.Code, Code: StatusCode(fiberErr.Code), Message: fiberErr.Message, Err: err}, true
+	}
+	return &Error{Status: fiber.StatusInternalServerError, Code: CodeInternal, Message: err.Error(), Err: err}, false
+}
@@ -0,0 +1,52 @@
+// Package buildinfo identifies the running build, for the version endpoint
+// and the version skew headers. Version, Commit and BuiltAt are set at
+// build time:
+//
+//	go build -ldflags "-X github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/buildinfo.Version=1.4.0 ..."
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// APIVersion is the version of the request and response shapes. Bump it
+// when a DTO changes in a way an older client cannot ignore; the server
+// keeps serving clients one version behind (see middleware.VersionSkew).
+const APIVersion = 1
+
+var (
+	Version = "dev"
+	Commit  = ""
+	BuiltAt = ""
+)
+
+// Info is the running build.
+type Info struct {
+	Version    string
+	Commit     string
+	BuiltAt    string
+	GoVersion  string
+	APIVersion int
+}
+
+// Get returns the running build. Without a -ldflags commit, the VCS
+// revision go build embedded is used.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuiltAt: BuiltAt, GoVersion: runtime.Version(), APIVersion: APIVersion}
+	if info.Commit == "" {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			for _, s := range bi.Settings {
+				switch s.Key {
+				case "vcs.revision":
+					info.Commit = s.Value
+				case "vcs.time":
+					if info.BuiltAt == "" {
+						info.BuiltAt = s.Value
+					}
+				}
+			}
+		}
+	}
+	return info
+}
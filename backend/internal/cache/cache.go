@@ -0,0 +1,160 @@
+// Package cache is an in-process HTTP response cache with TTL and
+// stale-while-revalidate semantics. Entries are tagged so content edits can
+// purge every cached response built from the content they changed.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Entry is a cached response.
+type Entry struct {
+	Status      int
+	ContentType string
+	Body        []byte
+	StoredAt    time.Time
+}
+
+// Freshness of an entry at a point in time.
+type Freshness int
+
+const (
+	Missing Freshness = iota
+	Fresh
+	Stale // past its TTL but inside the stale-while-revalidate window
+)
+
+type entry struct {
+	Entry
+	ttl, swr     time.Duration
+	tags         []string
+	revalidating bool
+}
+
+// Store keeps responses in process memory, so each instance has its own
+// cache and purges only reach the instance that handled the edit.
+type Store struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+	tags    map[string]map[string]struct{}
+	now     func() time.Time
+}
+
+func New() *Store {
+	return &Store{
+		entries: make(map[string]*entry),
+		tags:    make(map[string]map[string]struct{}),
+		now:     time.Now,
+	}
+}
+
+// Get returns the entry under key and how fresh it is. Entries past their
+// stale window are dropped.
+func (s *Store) Get(key string) (*Entry, Freshness) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, Missing
+	}
+	age := s.now().Sub(e.StoredAt)
+	switch {
+	case age < e.ttl:
+		return &e.Entry, Fresh
+	case age < e.ttl+e.swr:
+		return &e.Entry, Stale
+	}
+	s.remove(key)
+	return nil, Missing
+}
+
+// StartRevalidate marks a stale entry as being refreshed. It returns false
+// when a refresh is already running, so only one request revalidates.
+func (s *Store) StartRevalidate(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || e.revalidating {
+		return false
+	}
+	e.revalidating = true
+	return true
+}
+
+// EndRevalidate clears the refresh mark after a failed refresh, so a later
+// request can try again.
+func (s *Store) EndRevalidate(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		e.revalidating = false
+	}
+}
+
+// Set stores a response, fresh for ttl and then servable stale for swr.
+func (s *Store) Set(key string, value Entry, ttl, swr time.Duration, tags ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(key)
+	value.StoredAt = s.now()
+	s.entries[key] = &entry{Entry: value, ttl: ttl, swr: swr, tags: tags}
+	for _, tag := range tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			s.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+// Purge drops every entry carrying one of the tags and returns how many
+// were removed.
+func (s *Store) Purge(tags ...string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			if _, ok := s.entries[key]; ok {
+				s.remove(key)
+				n++
+			}
+		}
+		delete(s.tags, tag)
+	}
+	return n
+}
+
+// PurgeAll empties the cache.
+func (s *Store) PurgeAll() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.entries)
+	s.entries = make(map[string]*entry)
+	s.tags = make(map[string]map[string]struct{})
+	return n
+}
+
+func (s *Store) remove(key string) {
+	e, ok := s.entries[key]
+	if !ok {
+		return
+	}
+	delete(s.entries, key)
+	for _, tag := range e.tags {
+		if keys, ok := s.tags[tag]; ok {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(s.tags, tag)
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreFreshness(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := New()
+	s.now = func() time.Time { return now }
+
+	s.Set("/api/terms", Entry{Status: 200, Body: []byte("terms")}, time.Minute, 5*time.Minute, "legal")
+	if _, f := s.Get("/api/terms"); f != Fresh {
+		t.Fatalf("freshness = %v, want Fresh", f)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, f := s.Get("/api/terms"); f != Stale {
+		t.Fatalf("freshness = %v, want Stale", f)
+	}
+	if !s.StartRevalidate("/api/terms") {
+		t.Fatal("first revalidation refused")
+	}
+	if s.StartRevalidate("/api/terms") {
+		t.Fatal("second concurrent revalidation allowed")
+	}
+
+	now = now.Add(10 * time.Minute)
+	if _, f := s.Get("/api/terms"); f != Missing {
+		t.Fatalf("freshness = %v, want Missing after the stale window", f)
+	}
+}
+
+func TestStorePurgeByTag(t *testing.T) {
+	s := New()
+	s.Set("a", Entry{Status: 200}, time.Hour, 0, "legal")
+	s.Set("b", Entry{Status: 200}, time.Hour, 0, "legal", "terms")
+	s.Set("c", Entry{Status: 200}, time.Hour, 0, "other")
+
+	if n := s.Purge("legal"); n != 2 {
+		t.Fatalf("Purge removed %d entries, want 2", n)
+	}
+	if _, f := s.Get("c"); f != Fresh {
+		t.Error("untagged entry was purged")
+	}
+	if n := s.Purge("terms"); n != 0 {
+		t.Errorf("Purge of already removed entry removed %d", n)
+	}
+}
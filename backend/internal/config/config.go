@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,16 +14,105 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	DBSSLMode  string
+	// DBAutoMigrate lets GORM migrate the models at startup. Turn it off in
+	// production and apply versioned migrations with `server migrate up`.
+	DBAutoMigrate bool
+	// DBReplicaDSNs are comma-separated DSNs of read replicas that read-heavy
+	// queries (lists, stats, dashboards) use; empty means the primary serves
+	// everything.
+	DBReplicaDSNs string
+	// Connection pool limits, applied to the primary and to each replica.
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
 
 	JWTSecret        string
 	JWTAccessExpiry  time.Duration
 	JWTRefreshExpiry time.Duration
+	// JWTPreviousSecrets (comma-separated) still verify access tokens after
+	// JWT_SECRET is rotated; new tokens are always signed with JWT_SECRET.
+	JWTPreviousSecrets string
+
+	// FieldEncryptionKey (base64, 32 bytes) encrypts sensitive text columns;
+	// empty leaves them in plain text. Retired keys stay in
+	// FieldEncryptionPreviousKeys until re-encryption has moved every row.
+	FieldEncryptionKey          string
+	FieldEncryptionPreviousKeys string
+
+	PasswordResetURL string
+	PasswordResetTTL time.Duration
+	EmailChangeURL   string
+	EmailChangeTTL   time.Duration
+	SupportEmail     string
+
+	EmailVerifyURL       string
+	EmailVerifyTTL       time.Duration
+	RequireVerifiedEmail bool
+
+	HandleRenameCooldown time.Duration
+	// TimezoneChangeCooldown is the minimum time between changes of the
+	// profile timezone, which sets the day of the daily scan quota.
+	TimezoneChangeCooldown time.Duration
+
+	ContactDiscoverySalt string
+	RateLimitDiscover    string
+	// InviteLinkBaseURL prefixes friend invite codes to form deep links.
+	InviteLinkBaseURL string
+
+	// ExportDir holds generated data exports; ExportTTL is how long they stay downloadable.
+	ExportDir string
+	ExportTTL time.Duration
+
+	// PhotoDir holds scan photos and thumbnails; free accounts keep the
+	// originals of their FreePhotoLimit most recent scans.
+	PhotoDir       string
+	FreePhotoLimit int
+	// Originals older than PhotoColdAfter move to the object store in
+	// PhotoColdStorageClass (0 or no object store keeps them on disk).
+	// Restored originals stay readable for PhotoRestoreDays days.
+	PhotoColdAfter        time.Duration
+	PhotoColdStorageClass string
+	PhotoRestoreDays      int
+
+	// Object store for internal datasets: an S3 bucket, or a local directory
+	// in development. Research exports are disabled when neither is set.
+	ObjectStoreBucket string
+	ObjectStorePrefix string
+	ObjectStoreDir    string
+	// ResearchIDSecret keys the pseudonymous participant IDs in research
+	// exports; rotating it unlinks participants across exports.
+	ResearchIDSecret string
+	// ResearchMinRegionUsers folds regions with fewer consenting users on a
+	// day into "other" so small groups can't be singled out.
+	ResearchMinRegionUsers int
+	// AccountDeletionGrace is how long a deleted account can be restored before it is purged.
+	AccountDeletionGrace time.Duration
 
 	AppleClientIDs string
 
 	AdminEmails  string
 	AdminUserIDs string
 	AdminToken   string
+	// ModerationEscalationThreshold is the report count at which an open
+	// moderation case is escalated automatically (0 disables).
+	ModerationEscalationThreshold int
+	// AppealSLA is how long admins have to review a moderation appeal.
+	AppealSLA time.Duration
+
+	// Stripe billing for web users; disabled while StripeSecretKey is empty.
+	// StripePriceIDs lists the prices checkout may sell, the first is the default.
+	StripeSecretKey     string
+	StripeWebhookSecret string
+	StripePriceIDs      string
+	StripeSuccessURL    string
+	StripeCancelURL     string
+	StripePortalReturn  string
+
+	// PlusProductIDs lists the store product / Stripe price IDs of the plus
+	// tier (comma-separated). Active subscriptions to any other product get
+	// the pro tier, as premium did before tiers existed.
+	PlusProductIDs string
 
 	RevenueCatWebhookAuth string
 	GLMAPIKey             string
@@ -31,12 +122,177 @@ type Config struct {
 	DeepSeekAPIURL        string
 	DeepSeekModel         string
 	AuraAITimeout         time.Duration
+	AuraAITemperature     float64
+	AuraAISeed            int64
+
+	AuraPromptHistory        bool
+	AuraPromptHistoryRollout int
+
+	// AuraScanWorkers is how many async scan analyses run at once; the rest
+	// wait in line and can poll or watch their position.
+	AuraScanWorkers int
+	// AuraScanDispatch is where async scan analyses run: "local" in the API
+	// process that took the scan, or "queue" on the task queue's workers.
+	// Queue positions are only known for local analyses.
+	AuraScanDispatch string
+
+	// AIScanCosts estimates the spend of one analysis per provider, as
+	// provider=USD pairs, for the admin metrics overview.
+	AIScanCosts string
+	// AITokenPrices prices provider calls by model, as
+	// model=input/output pairs in USD per million tokens, for the ai_usage
+	// spend. Models not listed cost nothing.
+	AITokenPrices string
+	// AIDailyBudgetUSD caps a UTC day's AI spend; past it scans and matches
+	// fall back to the deterministic engine and templates. 0 is no cap.
+	AIDailyBudgetUSD float64
+	// ForceMockMode skips the AI for scans, face detection included, and
+	// stores deterministic mock readings: for staging environments that
+	// should not spend on providers.
+	ForceMockMode bool
 
 	OpenAIAPIKey string
 	OpenAIModel  string
 
+	// ImageModeration screens scan photos before AI analysis: "openai" uses
+	// the OpenAI moderation endpoint when OPENAI_API_KEY is set, "off"
+	// disables it. Photos flagged in any of ImageModerationCategories are
+	// rejected.
+	ImageModeration           string
+	ImageModerationCategories string
+	// ImageModerationFailClosed rejects scans while the moderation provider
+	// is failing instead of letting them through.
+	ImageModerationFailClosed bool
+	// ImageViolationEscalation is how many rejected photos within
+	// ImageViolationWindow put a user in the moderation queue (0 disables).
+	ImageViolationEscalation int
+	ImageViolationWindow     time.Duration
+	// FaceDetection rejects scan photos without a human face: "openai" asks
+	// OPENAI_MODEL for a face count, "off" disables the check.
+	FaceDetection string
+
+	ShareSecret   string
+	ShareLinkTTL  time.Duration
+	PublicBaseURL string
+
+	MetricsToken string
+
+	// On SIGTERM the instance reports draining for ShutdownDrainDelay, so
+	// load balancers stop sending it requests, then gives in-flight
+	// requests up to ShutdownTimeout to finish.
+	ShutdownDrainDelay time.Duration
+	ShutdownTimeout    time.Duration
+	// DeployHookToken enables POST /api/health/drain, a pre-stop hook for
+	// deploy tooling, behind this bearer token.
+	DeployHookToken string
+
+	RedisURL          string
+	RateLimitAuth     string
+	RateLimitScanIP   string
+	RateLimitScanUser string
+	// Scan submissions are refused without X-Request-Nonce, when replayed, or
+	// when their timestamp is more than ReplayWindow off. Setting
+	// ReplayNonceRequired to false accepts scans without a nonce, for app
+	// versions released before nonces; the setting is for the rollout only
+	// and is removed on 2026-12-01.
+	ReplayWindow        time.Duration
+	ReplayNonceRequired bool
+
+	MailDriver   string
+	MailFrom     string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	// SES driver; credentials come from the default AWS chain.
+	SESRegion           string
+	SESConfigurationSet string
+	// EmailWebhookSecret authenticates provider bounce/complaint webhooks.
+	EmailWebhookSecret string
+
+	NotificationDailyCap     int
+	NotificationDigestWindow time.Duration
+
+	// APNs token auth (.p8 key); push is disabled for iOS when unset.
+	APNsKeyFile    string
+	APNsKeyID      string
+	APNsTeamID     string
+	APNsTopic      string
+	APNsProduction bool
+	// FCMCredentialsFile is a Firebase service account JSON; FCMProjectID overrides its project_id.
+	FCMCredentialsFile string
+	FCMProjectID       string
+	// Local hours at which daily-reading and streak-at-risk reminders go out.
+	ReminderDailyHour  int
+	ReminderStreakHour int
+
+	// AnalyticsDriver is "posthog", "amplitude", or empty to drop product events.
+	AnalyticsDriver   string
+	PostHogAPIKey     string
+	PostHogHost       string
+	AmplitudeAPIKey   string
+	AmplitudeEndpoint string
+
+	// EmbeddingsDriver is "openai", or empty to detect recurring themes by
+	// keyword extraction.
+	EmbeddingsDriver string
+	// VectorSearch stores an embedding per reading in pgvector for similar
+	// readings and theme clustering. It needs an embeddings driver and is
+	// turned off at startup when the extension is unavailable.
+	VectorSearch bool
+
+	// CDNDriver is "cloudflare", "fastly", or empty when no CDN fronts the API.
+	CDNDriver          string
+	CloudflareZoneID   string
+	CloudflareAPIToken string
+	FastlyServiceID    string
+	FastlyAPIToken     string
+
+	// SyntheticProbeInterval schedules the monitoring scan probe; zero disables it.
+	SyntheticProbeInterval time.Duration
+
+	OTelEndpoint    string
+	OTelServiceName string
+	OTelSampleRatio float64
+	// TraceURLTemplate links a trace in the tracing UI; "{trace_id}" is
+	// replaced with the trace ID.
+	TraceURLTemplate string
+	// RequestLogRetention is how long failed requests are kept for support
+	// lookups by request ID.
+	RequestLogRetention time.Duration
+
+	// APIDocs serves the OpenAPI spec and Swagger UI at /api/docs; keep it
+	// off in production.
+	APIDocs bool
+
+	// Compression is the response compression level: speed, default, best or off.
+	Compression string
+
+	// Environment is "production" unless set otherwise; outside production
+	// internal error details are returned to clients to ease debugging.
+	Environment string
+
 	Port        string
 	CORSOrigins string
+	// TrustedProxies lists the addresses or CIDR ranges of the reverse
+	// proxies in front of the API, comma separated. Only requests from them
+	// take the client IP from ProxyHeader; the client IP keys per-IP rate
+	// limits and audit entries.
+	TrustedProxies string
+	ProxyHeader    string
+
+	// RunMode is what the process runs: "all", or a comma-separated list of
+	// api, worker and scheduler. The -mode flag overrides it.
+	RunMode string
+	// SharedDataDirs declares that EXPORT_DIR and PHOTO_DIR are on a volume
+	// every process sees, which a RunMode other than "all" requires.
+	SharedDataDirs bool
+
+	// QueueDriver carries background tasks (queued scans, exports,
+	// notifications) to the workers: "postgres", or "redis" for asynq on
+	// REDIS_URL. QueueConcurrency is how many tasks a worker runs at once.
+	QueueDriver      string
+	QueueConcurrency int
 }
 
 func Load() *Config {
@@ -47,10 +303,62 @@ func Load() *Config {
 		DBPassword: getEnv("DB_PASSWORD", ""),
 		DBName:     getEnv("DB_NAME", "app_db"),
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
+		// Off in production: the server then only checks that `server migrate
+		// up` has applied every versioned migration.
+		DBAutoMigrate: parseBool(getEnv("DB_AUTO_MIGRATE", "true")),
+
+		DBReplicaDSNs:     getEnv("DB_REPLICA_DSNS", ""),
+		DBMaxOpenConns:    int(parseInt64(getEnv("DB_MAX_OPEN_CONNS", "25"), 25)),
+		DBMaxIdleConns:    int(parseInt64(getEnv("DB_MAX_IDLE_CONNS", "10"), 10)),
+		DBConnMaxLifetime: parseDuration(getEnv("DB_CONN_MAX_LIFETIME", "30m")),
+		DBConnMaxIdleTime: parseDuration(getEnv("DB_CONN_MAX_IDLE_TIME", "5m")),
 
 		JWTSecret:        getEnv("JWT_SECRET", ""),
 		JWTAccessExpiry:  parseDuration(getEnv("JWT_ACCESS_EXPIRY", "15m")),
 		JWTRefreshExpiry: parseDuration(getEnv("JWT_REFRESH_EXPIRY", "168h")),
+		// Retired secrets stay here for at least JWT_ACCESS_EXPIRY after a rotation.
+		JWTPreviousSecrets: getEnv("JWT_PREVIOUS_SECRETS", ""),
+
+		FieldEncryptionKey:          getEnv("FIELD_ENCRYPTION_KEY", ""),
+		FieldEncryptionPreviousKeys: getEnv("FIELD_ENCRYPTION_PREVIOUS_KEYS", ""),
+
+		// Reset links open this page with ?token=...; the app/web form posts it back.
+		PasswordResetURL: getEnv("PASSWORD_RESET_URL", "https://aurasnap.app/reset-password"),
+		PasswordResetTTL: parseDuration(getEnv("PASSWORD_RESET_TTL", "1h")),
+		EmailChangeURL:   getEnv("EMAIL_CHANGE_URL", "https://aurasnap.app/confirm-email"),
+		EmailChangeTTL:   parseDuration(getEnv("EMAIL_CHANGE_TTL", "24h")),
+		SupportEmail:     getEnv("SUPPORT_EMAIL", "support@aurasnap.app"),
+
+		EmailVerifyURL:       getEnv("EMAIL_VERIFY_URL", "https://aurasnap.app/verify-email"),
+		EmailVerifyTTL:       parseDuration(getEnv("EMAIL_VERIFY_TTL", "48h")),
+		RequireVerifiedEmail: parseBool(getEnv("REQUIRE_VERIFIED_EMAIL", "false")),
+
+		HandleRenameCooldown:   parseDuration(getEnv("HANDLE_RENAME_COOLDOWN", "720h")),
+		TimezoneChangeCooldown: parseDuration(getEnv("TIMEZONE_CHANGE_COOLDOWN", "24h")),
+
+		// Shared with clients so they can hash contacts before upload.
+		ContactDiscoverySalt: getEnv("CONTACT_DISCOVERY_SALT", "aurasnap-contacts-v1"),
+		RateLimitDiscover:    getEnv("RATE_LIMIT_DISCOVER", "10/1h"),
+		InviteLinkBaseURL:    getEnv("INVITE_LINK_BASE_URL", "https://aurasnap.app/invite/"),
+
+		// Generated data exports are kept on local disk until EXPORT_TTL passes.
+		ExportDir: getEnv("EXPORT_DIR", "./data/exports"),
+		ExportTTL: parseDuration(getEnv("EXPORT_TTL", "168h")),
+
+		PhotoDir:       getEnv("PHOTO_DIR", "./data/photos"),
+		FreePhotoLimit: int(parseInt64(getEnv("FREE_PHOTO_LIMIT", "30"), 30)),
+		// Originals move to a cold storage class after 90 days.
+		PhotoColdAfter:        parseDuration(getEnv("PHOTO_COLD_AFTER", "2160h")),
+		PhotoColdStorageClass: getEnv("PHOTO_COLD_STORAGE_CLASS", "GLACIER"),
+		PhotoRestoreDays:      int(parseInt64(getEnv("PHOTO_RESTORE_DAYS", "7"), 7)),
+
+		ObjectStoreBucket:      getEnv("OBJECT_STORE_BUCKET", ""),
+		ObjectStorePrefix:      getEnv("OBJECT_STORE_PREFIX", ""),
+		ObjectStoreDir:         getEnv("OBJECT_STORE_DIR", ""),
+		ResearchIDSecret:       getEnv("RESEARCH_ID_SECRET", getEnv("JWT_SECRET", "")),
+		ResearchMinRegionUsers: int(parseInt64(getEnv("RESEARCH_MIN_REGION_USERS", "5"), 5)),
+
+		AccountDeletionGrace: parseDuration(getEnv("ACCOUNT_DELETION_GRACE", "720h")),
 
 		AppleClientIDs: getEnv("APPLE_CLIENT_IDS", getEnv("APPLE_CLIENT_ID", "")),
 
@@ -58,6 +366,18 @@ func Load() *Config {
 		AdminUserIDs: getEnv("ADMIN_USER_IDS", ""),
 		AdminToken:   getEnv("ADMIN_TOKEN", ""),
 
+		ModerationEscalationThreshold: int(parseInt64(getEnv("MODERATION_ESCALATION_THRESHOLD", "5"), 5)),
+		AppealSLA:                     parseDuration(getEnv("APPEAL_SLA", "72h")),
+
+		StripeSecretKey:     getEnv("STRIPE_SECRET_KEY", ""),
+		StripeWebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
+		StripePriceIDs:      getEnv("STRIPE_PRICE_IDS", ""),
+		StripeSuccessURL:    getEnv("STRIPE_SUCCESS_URL", "https://aurasnap.app/billing/success"),
+		StripeCancelURL:     getEnv("STRIPE_CANCEL_URL", "https://aurasnap.app/billing/cancel"),
+		StripePortalReturn:  getEnv("STRIPE_PORTAL_RETURN_URL", "https://aurasnap.app/account"),
+
+		PlusProductIDs: getEnv("PLUS_PRODUCT_IDS", ""),
+
 		RevenueCatWebhookAuth: getEnv("REVENUECAT_WEBHOOK_AUTH", ""),
 		// GLM is primary provider.
 		GLMAPIKey: getEnv("GLM_API_KEY", getEnv("AURA_GLM_API_KEY", "")),
@@ -68,15 +388,136 @@ func Load() *Config {
 		DeepSeekAPIURL: getEnv("DEEPSEEK_API_URL", getEnv("AURA_DEEPSEEK_API_URL", "https://api.deepseek.com/chat/completions")),
 		DeepSeekModel:  getEnv("DEEPSEEK_MODEL", getEnv("AURA_DEEPSEEK_MODEL", "deepseek-chat")),
 		AuraAITimeout:  parseDuration(getEnv("AURA_AI_TIMEOUT", "20s")),
+		// Seed is only sent to providers that support it; 0 leaves it unset.
+		AuraAITemperature: parseFloat(getEnv("AURA_AI_TEMPERATURE", "0.2"), 0.2),
+		AuraAISeed:        parseInt64(getEnv("AURA_AI_SEED", "0"), 0),
+		// Prior readings in the prompt, rolled out to a stable percentage of users.
+		AuraPromptHistory:        parseBool(getEnv("AURA_PROMPT_HISTORY", "false")),
+		AuraPromptHistoryRollout: int(parseInt64(getEnv("AURA_PROMPT_HISTORY_ROLLOUT", "100"), 100)),
+		AIScanCosts:              getEnv("AI_SCAN_COSTS", "glm=0.002,deepseek=0.001,openai=0.003"),
+		AITokenPrices:            getEnv("AI_TOKEN_PRICES", "gpt-4o-mini=0.15/0.60"),
+		AIDailyBudgetUSD:         parseFloat(getEnv("AI_DAILY_BUDGET_USD", "0"), 0),
+		ForceMockMode:            parseBool(getEnv("FORCE_MOCK_MODE", "false")),
+		AuraScanWorkers:          int(parseInt64(getEnv("AURA_SCAN_WORKERS", "8"), 8)),
+		AuraScanDispatch:         getEnv("AURA_SCAN_DISPATCH", "local"),
 
 		OpenAIAPIKey: getEnv("OPENAI_API_KEY", ""),
 		OpenAIModel:  getEnv("OPENAI_MODEL", "gpt-4o-mini"),
 
+		ImageModeration:           getEnv("IMAGE_MODERATION", "openai"),
+		ImageModerationCategories: getEnv("IMAGE_MODERATION_CATEGORIES", "sexual,sexual/minors,violence,violence/graphic"),
+		ImageModerationFailClosed: parseBool(getEnv("IMAGE_MODERATION_FAIL_CLOSED", "false")),
+		ImageViolationEscalation:  int(parseInt64(getEnv("IMAGE_VIOLATION_ESCALATION", "3"), 3)),
+		ImageViolationWindow:      parseDuration(getEnv("IMAGE_VIOLATION_WINDOW", "720h")),
+		FaceDetection:             getEnv("FACE_DETECTION", "openai"),
+
+		// Share links are signed with their own secret when provided, JWT secret otherwise.
+		ShareSecret:   getEnv("SHARE_SECRET", getEnv("JWT_SECRET", "")),
+		ShareLinkTTL:  parseDuration(getEnv("SHARE_LINK_TTL", "720h")),
+		PublicBaseURL: getEnv("PUBLIC_BASE_URL", ""),
+
+		MetricsToken: getEnv("METRICS_TOKEN", ""),
+
+		ShutdownDrainDelay: parseDuration(getEnv("SHUTDOWN_DRAIN_DELAY", "15s")),
+		ShutdownTimeout:    parseDuration(getEnv("SHUTDOWN_TIMEOUT", "30s")),
+		DeployHookToken:    getEnv("DEPLOY_HOOK_TOKEN", ""),
+
+		// Rate limits are "count/period"; buckets live in Redis when REDIS_URL is set.
+		RedisURL:          getEnv("REDIS_URL", ""),
+		RateLimitAuth:     getEnv("RATE_LIMIT_AUTH", "20/1m"),
+		RateLimitScanIP:   getEnv("RATE_LIMIT_SCAN_IP", "30/1m"),
+		RateLimitScanUser: getEnv("RATE_LIMIT_SCAN_USER", "10/1m"),
+
+		// Scan replay protection; nonces share REDIS_URL with the rate limiter.
+		ReplayWindow:        parseDuration(getEnv("REPLAY_WINDOW", "5m")),
+		ReplayNonceRequired: parseBool(getEnv("REPLAY_NONCE_REQUIRED", "true")),
+
+		// Mail driver: "smtp", "ses", or empty to log emails instead of sending.
+		MailDriver:   getEnv("MAIL_DRIVER", ""),
+		MailFrom:     getEnv("MAIL_FROM", "AuraSnap <no-reply@aurasnap.app>"),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+
+		SESRegion:           getEnv("AWS_REGION", ""),
+		SESConfigurationSet: getEnv("SES_CONFIGURATION_SET", ""),
+		EmailWebhookSecret:  getEnv("EMAIL_WEBHOOK_SECRET", ""),
+
+		// Max push/email notifications per user per local day (security alerts exempt); 0 disables.
+		NotificationDailyCap: int(parseInt64(getEnv("NOTIFICATION_DAILY_CAP", "5"), 5)),
+		// Social events in digest mode are batched for this long after the first one.
+		NotificationDigestWindow: parseDuration(getEnv("NOTIFICATION_DIGEST_WINDOW", "3h")),
+
+		APNsKeyFile:        getEnv("APNS_KEY_FILE", ""),
+		APNsKeyID:          getEnv("APNS_KEY_ID", ""),
+		APNsTeamID:         getEnv("APNS_TEAM_ID", ""),
+		APNsTopic:          getEnv("APNS_TOPIC", ""),
+		APNsProduction:     parseBool(getEnv("APNS_PRODUCTION", "false")),
+		FCMCredentialsFile: getEnv("FCM_CREDENTIALS_FILE", ""),
+		FCMProjectID:       getEnv("FCM_PROJECT_ID", ""),
+		ReminderDailyHour:  int(parseInt64(getEnv("REMINDER_DAILY_HOUR", "9"), 9)),
+		ReminderStreakHour: int(parseInt64(getEnv("REMINDER_STREAK_HOUR", "20"), 20)),
+
+		// Product analytics sink: "posthog", "amplitude", or empty to drop events.
+		AnalyticsDriver:   getEnv("ANALYTICS_DRIVER", ""),
+		PostHogAPIKey:     getEnv("POSTHOG_API_KEY", ""),
+		PostHogHost:       getEnv("POSTHOG_HOST", ""),
+		AmplitudeAPIKey:   getEnv("AMPLITUDE_API_KEY", ""),
+		AmplitudeEndpoint: getEnv("AMPLITUDE_ENDPOINT", ""),
+
+		// Embeddings for recurring-theme clustering: "openai", or empty for keywords.
+		EmbeddingsDriver: getEnv("EMBEDDINGS_DRIVER", ""),
+		VectorSearch:     parseBool(getEnv("VECTOR_SEARCH", "false")),
+
+		// CDN purged by DELETE /api/admin/cache: "cloudflare", "fastly", or empty.
+		CDNDriver:          getEnv("CDN_DRIVER", ""),
+		CloudflareZoneID:   getEnv("CLOUDFLARE_ZONE_ID", ""),
+		CloudflareAPIToken: getEnv("CLOUDFLARE_API_TOKEN", ""),
+		FastlyServiceID:    getEnv("FASTLY_SERVICE_ID", ""),
+		FastlyAPIToken:     getEnv("FASTLY_API_TOKEN", ""),
+
+		// Synthetic monitoring: scan a bundled image as the probe user every
+		// interval and report the outcome in synthetic_probe_* metrics.
+		SyntheticProbeInterval: parseDuration(getEnv("SYNTHETIC_PROBE_INTERVAL", "0")),
+
+		// Tracing is off unless an OTLP endpoint is set; the exporter reads the
+		// remaining OTEL_EXPORTER_OTLP_* variables itself.
+		OTelEndpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", getEnv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")),
+		OTelServiceName: getEnv("OTEL_SERVICE_NAME", "aurasnap-backend"),
+		OTelSampleRatio: parseFloat(getEnv("OTEL_TRACES_SAMPLER_ARG", "1"), 1),
+
+		TraceURLTemplate:    getEnv("TRACE_URL_TEMPLATE", ""),
+		RequestLogRetention: parseDuration(getEnv("REQUEST_LOG_RETENTION", "336h")),
+
+		APIDocs:     parseBool(getEnv("API_DOCS", "false")),
+		Compression: getEnv("COMPRESSION", "default"),
+		Environment: getEnv("APP_ENV", "production"),
+
 		Port:        getEnv("PORT", "8080"),
 		CORSOrigins: getEnv("CORS_ORIGINS", "*"),
+
+		TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
+		ProxyHeader:    getEnv("PROXY_HEADER", "X-Real-IP"),
+
+		RunMode:        getEnv("RUN_MODE", "all"),
+		SharedDataDirs: parseBool(getEnv("SHARED_DATA_DIRS", "false")),
+
+		QueueDriver:      getEnv("QUEUE_DRIVER", "postgres"),
+		QueueConcurrency: int(parseInt64(getEnv("QUEUE_CONCURRENCY", "8"), 8)),
 	}
 }
 
+// Production reports whether the server runs in production: any
+// environment but a development or test one, so a typo fails closed.
+func (c *Config) Production() bool {
+	switch strings.ToLower(strings.TrimSpace(c.Environment)) {
+	case "development", "dev", "local", "test":
+		return false
+	}
+	return true
+}
+
 func (c *Config) DSN() string {
 	return "host=" + c.DBHost +
 		" user=" + c.DBUser +
@@ -101,3 +542,24 @@ func parseDuration(s string) time.Duration {
 	}
 	return d
 }
+
+func parseFloat(s string, fallback float64) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func parseBool(s string) bool {
+	v, err := strconv.ParseBool(s)
+	return err == nil && v
+}
+
+func parseInt64(s string, fallback int64) int64 {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fallback
+	}
+	return v
+}
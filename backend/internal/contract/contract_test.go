@@ -0,0 +1,287 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/handlers"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/jwtkeys"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/middleware"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/ratelimit"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/replay"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/routes"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const contractSecret = "contract-test-secret"
+
+type contractFile struct {
+	Consumer     string        `json:"consumer"`
+	Interactions []interaction `json:"interactions"`
+	Payloads     []payload     `json:"payloads"`
+}
+
+type interaction struct {
+	Description string `json:"description"`
+	Request     struct {
+		Method string          `json:"method"`
+		Path   string          `json:"path"`
+		Auth   string          `json:"auth"` // "", "expired" or "unknown-key"
+		Body   json.RawMessage `json:"body"`
+	} `json:"request"`
+	Response struct {
+		Status int `json:"status"`
+		Body   any `json:"body"`
+	} `json:"response"`
+}
+
+type payload struct {
+	Name string `json:"name"`
+	Body any    `json:"body"`
+}
+
+// samples are the values the handlers serialize for each payload name.
+// Scalars are left zero and slices empty, so a field gaining omitempty
+// shows up as missing.
+var samples = map[string]any{
+	"AuthResponse":    dto.AuthResponse{},
+	"ProfileResponse": dto.ProfileResponse{ID: uuid.Nil.String()},
+	"AuraReading": models.AuraReading{
+		Strengths:  []string{},
+		Challenges: []string{},
+	},
+	"AuraListResponse": dto.AuraListResponse{
+		Data: []dto.AuraReadingResponse{{Strengths: []string{}, Challenges: []string{}}},
+	},
+	"ScanEligibilityResponse": dto.ScanEligibilityResponse{},
+	"AuraStatsResponse":       dto.AuraStatsResponse{ColorDistribution: map[string]int{}},
+	"StreakResponse":          dto.StreakResponse{UnlockedColors: []string{}},
+}
+
+func TestContracts(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no contracts found in testdata: %v", err)
+	}
+	app := newApp()
+
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var contract contractFile
+		if err := json.Unmarshal(raw, &contract); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+
+		for _, it := range contract.Interactions {
+			t.Run(name+"/"+it.Description, func(t *testing.T) {
+				verifyInteraction(t, app, it)
+			})
+		}
+		for _, p := range contract.Payloads {
+			t.Run(name+"/payload "+p.Name, func(t *testing.T) {
+				sample, ok := samples[p.Name]
+				if !ok {
+					t.Fatalf("no sample registered for payload %q", p.Name)
+				}
+				body, err := json.Marshal(sample)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var actual any
+				if err := json.Unmarshal(body, &actual); err != nil {
+					t.Fatal(err)
+				}
+				for _, problem := range matchShape("$", p.Body, actual, true) {
+					t.Error(problem)
+				}
+			})
+		}
+	}
+}
+
+// newApp builds the real router. Only handlers an interaction reaches
+// without a database are constructed; the others are never called.
+func newApp() *fiber.App {
+	cfg := &config.Config{JWTSecret: contractSecret}
+	authHandler := handlers.NewAuthHandler(services.NewAuthService(nil, cfg, nil))
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(cfg)})
+	routes.Setup(app, cfg, ratelimit.NewMemoryLimiter(), replay.NewMemoryStore(), authHandler, handlers.NewHealthHandler(nil),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	return app
+}
+
+func verifyInteraction(t *testing.T, app *fiber.App, it interaction) {
+	t.Helper()
+
+	var body io.Reader
+	if len(it.Request.Body) > 0 {
+		// A JSON string is sent verbatim so contracts can send malformed bodies.
+		var verbatim string
+		if json.Unmarshal(it.Request.Body, &verbatim) == nil {
+			body = strings.NewReader(verbatim)
+		} else {
+			body = bytes.NewReader(it.Request.Body)
+		}
+	}
+	req := httptest.NewRequest(it.Request.Method, it.Request.Path, body)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if it.Request.Auth != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+testToken(t, it.Request.Auth))
+	}
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != it.Response.Status {
+		t.Fatalf("status = %d, want %d (body %s)", resp.StatusCode, it.Response.Status, raw)
+	}
+	if it.Response.Body == nil {
+		return
+	}
+	var actual any
+	if err := json.Unmarshal(raw, &actual); err != nil {
+		t.Fatalf("response is not JSON: %s", raw)
+	}
+	for _, problem := range matchShape("$", it.Response.Body, actual, true) {
+		t.Error(problem)
+	}
+}
+
+func testToken(t *testing.T, kind string) string {
+	t.Helper()
+	claims := jwt.MapClaims{
+		"sub": uuid.NewString(),
+		"iat": time.Now().Add(-time.Hour).Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	keys := jwtkeys.New(contractSecret, "")
+	switch kind {
+	case "expired":
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	case "unknown-key":
+		keys = jwtkeys.New("some-other-secret", "")
+	default:
+		t.Fatalf("unknown auth kind %q", kind)
+	}
+	token, err := keys.Sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// matchShape compares a decoded JSON value with a contract shape and
+// returns one message per mismatch, prefixed with its JSON path. present
+// is false when the field is missing from its object.
+func matchShape(path string, shape, actual any, present bool) []string {
+	switch s := shape.(type) {
+	case map[string]any:
+		obj, ok := actual.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %s, want object", path, describe(actual, present))}
+		}
+		keys := make([]string, 0, len(s))
+		for key := range s {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var problems []string
+		for _, key := range keys {
+			value, ok := obj[key]
+			problems = append(problems, matchShape(path+"."+key, s[key], value, ok)...)
+		}
+		return problems
+
+	case []any:
+		arr, ok := actual.([]any)
+		if !ok || len(s) != 1 {
+			return []string{fmt.Sprintf("%s: got %s, want array", path, describe(actual, present))}
+		}
+		var problems []string
+		for i, elem := range arr {
+			problems = append(problems, matchShape(fmt.Sprintf("%s[%d]", path, i), s[0], elem, true)...)
+		}
+		return problems
+
+	case string:
+		kind, optional := strings.CutSuffix(s, "?")
+		if !present || actual == nil {
+			if optional {
+				return nil
+			}
+			return []string{fmt.Sprintf("%s: got %s, want %s", path, describe(actual, present), kind)}
+		}
+		if !hasKind(kind, actual) {
+			return []string{fmt.Sprintf("%s: got %s, want %s", path, describe(actual, present), kind)}
+		}
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: invalid shape %v in contract", path, shape)}
+}
+
+func hasKind(kind string, v any) bool {
+	switch kind {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "uuid":
+		s, ok := v.(string)
+		return ok && uuid.Validate(s) == nil
+	case "datetime":
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	}
+	return false
+}
+
+func describe(v any, present bool) string {
+	if !present {
+		return "nothing (field missing)"
+	}
+	if v == nil {
+		return "null"
+	}
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
@@ -0,0 +1,17 @@
+// Package contract verifies the backend against the expectations of its
+// clients. Each file in testdata is written from a client's point of view
+// (currently the iOS app) and lists interactions, requests sent to the real
+// router with the status and response shape the client relies on, and
+// payloads, the response bodies of endpoints that need a database, checked
+// against the DTOs their handlers serialize.
+//
+// Shapes name the JSON type of each field the client reads: "string",
+// "boolean", "number", "integer", "uuid", "datetime" or "object"; a
+// trailing "?" marks a field that may be absent or null, and a one-element
+// array describes every element. Fields the client ignores are not listed,
+// so adding fields never breaks a contract; renaming, removing or retyping
+// one does.
+//
+// When a client starts reading a new field, add it to its contract in the
+// same change.
+package contract
@@ -1,19 +1,28 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/fieldcrypt"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
 var DB *gorm.DB
 
 func Ping() error {
+	return PingContext(context.Background())
+}
+
+// PingContext is Ping bounded by ctx, for probes with a deadline.
+func PingContext(ctx context.Context) error {
 	if DB == nil {
 		return fmt.Errorf("database not initialized")
 	}
@@ -21,10 +30,44 @@ func Ping() error {
 	if err != nil {
 		return err
 	}
-	return sqlDB.Ping()
+	return sqlDB.PingContext(ctx)
 }
 
+// InitDB connects to the database and brings its schema up to date: with
+// AutoMigrate on, GORM migrates the models and the versioned migrations run
+// after it; with it off, the server refuses to start until `server migrate
+// up` has applied every migration.
 func InitDB(cfg *config.Config) *gorm.DB {
+	db := Open(cfg)
+
+	if cfg.DBAutoMigrate {
+		autoMigrate(db)
+		if err := Migrate(context.Background(), db, "up"); err != nil {
+			log.Fatalf("Failed to run migrations: %v", err)
+		}
+	} else {
+		pending, err := PendingMigrations(context.Background(), db)
+		if err != nil {
+			log.Fatalf("Failed to check migrations: %v", err)
+		}
+		if pending > 0 {
+			log.Fatalf("Database schema is %d migration(s) behind; run `server migrate up`", pending)
+		}
+	}
+
+	log.Println("Database connected and migrated successfully")
+	DB = db
+	return db
+}
+
+// Open connects to the database without touching its schema.
+func Open(cfg *config.Config) *gorm.DB {
+	keyring, err := fieldcrypt.New(cfg.FieldEncryptionKey, cfg.FieldEncryptionPreviousKeys)
+	if err != nil {
+		log.Fatalf("Failed to load field encryption keys: %v", err)
+	}
+	fieldcrypt.Use(keyring)
+
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
 		cfg.DBHost,
 		cfg.DBUser,
@@ -41,22 +84,104 @@ func InitDB(cfg *config.Config) *gorm.DB {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// AutoMigrate schemas
-	err = db.AutoMigrate(
-		&models.User{},
-		&models.RefreshToken{},
-		&models.Subscription{},
-		&models.Block{},
-		&models.Report{},
-		&models.AuraReading{},
-		&models.AuraMatch{},
-		&models.AuraStreak{},
-	)
-	if err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		log.Fatalf("Failed to register database metrics: %v", err)
+	}
+	if err := db.Use(tracing.NewPlugin(tracing.WithoutQueryVariables(), tracing.WithoutMetrics())); err != nil {
+		log.Fatalf("Failed to register database tracing: %v", err)
+	}
+	if err := configurePool(db, cfg); err != nil {
+		log.Fatalf("Failed to configure database pool: %v", err)
 	}
 
-	log.Println("Database connected and migrated successfully")
-	DB = db
 	return db
-}
\ No newline at end of file
+}
+
+// autoMigrate lets GORM create and alter the tables of every model.
+func autoMigrate(db *gorm.DB) {
+	if err := db.AutoMigrate(autoMigrateModels...); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+	for _, stmt := range auditLogImmutable {
+		if err := db.Exec(stmt).Error; err != nil {
+			log.Fatalf("Failed to protect audit log: %v", err)
+		}
+	}
+}
+
+// autoMigrateModels are the models GORM migrates. A model added here also
+// needs a versioned migration creating its table.
+var autoMigrateModels = []any{
+	&models.User{},
+	&models.RefreshToken{},
+	&models.Subscription{},
+	&models.Block{},
+	&models.Report{},
+	&models.ModerationCase{},
+	&models.ModerationAction{},
+	&models.Appeal{},
+	&models.AuditLog{},
+	&models.WebhookEvent{},
+	&models.ProcessedEvent{},
+	&models.ImageViolation{},
+	&models.AIInteraction{},
+	&models.AIUsage{},
+	&models.AuraReading{},
+	&models.AuraMatch{},
+	&models.AuraStreak{},
+	&models.UserMemory{},
+	&models.NotificationPreference{},
+	&models.Notification{},
+	&models.NotificationDispatch{},
+	&models.PendingNotification{},
+	&models.EmailSuppression{},
+	&models.AuthToken{},
+	&models.ContactHash{},
+	&models.Friendship{},
+	&models.FriendInviteCode{},
+	&models.DataExport{}, &models.OnboardingProgress{}, &models.Survey{}, &models.SurveyResponse{}, &models.DeviceToken{}, &models.ReminderLog{}, &models.AuraForecast{}, &models.GroupReading{}, &models.StoredPhoto{}, &models.ResearchExport{}, &models.ReadingCorrection{}, &models.ReadingVersion{}, &models.AdminMessage{}, &models.UserAggregate{}, &models.DataMigration{}, &models.Trait{}, &models.ReadingTrait{}, &models.RecurringTheme{}, &models.ThemeAnalysis{}, &models.RequestLog{}, &models.QueueTask{}, &models.PromptTemplate{}, &models.Experiment{}, &models.ScanBatch{},
+}
+
+// auditLogImmutable makes audit_logs append-only: updates and deletes are
+// rejected by the database, not just avoided by the application.
+var auditLogImmutable = []string{
+	`CREATE OR REPLACE FUNCTION audit_logs_immutable() RETURNS trigger AS $$
+	BEGIN
+		RAISE EXCEPTION 'audit_logs is append-only';
+	END;
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS audit_logs_immutable ON audit_logs`,
+	`CREATE TRIGGER audit_logs_immutable BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE FUNCTION audit_logs_immutable()`,
+}
+
+// ReadingEmbeddingDims is the width of stored reading embeddings, that of
+// OpenAI's text-embedding-3-small.
+const ReadingEmbeddingDims = 1536
+
+// vectorStoreSchema creates the pgvector-backed reading_embeddings table.
+// It is not part of AutoMigrate because the extension is optional.
+var vectorStoreSchema = []string{
+	`CREATE EXTENSION IF NOT EXISTS vector`,
+	fmt.Sprintf(`CREATE TABLE IF NOT EXISTS reading_embeddings (
+		reading_id uuid PRIMARY KEY REFERENCES aura_readings(id) ON DELETE CASCADE,
+		user_id uuid NOT NULL,
+		model varchar(100) NOT NULL,
+		embedding vector(%d) NOT NULL,
+		created_at timestamptz NOT NULL DEFAULT now()
+	)`, ReadingEmbeddingDims),
+	`CREATE INDEX IF NOT EXISTS idx_reading_embeddings_user ON reading_embeddings (user_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_reading_embeddings_hnsw ON reading_embeddings USING hnsw (embedding vector_cosine_ops)`,
+}
+
+// EnableVectorStore installs the pgvector extension and the
+// reading_embeddings table. It fails when the extension is not available
+// on the server, or the database user may not create it.
+func EnableVectorStore(db *gorm.DB) error {
+	for _, stmt := range vectorStoreSchema {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database/migrations"
+	"github.com/pressly/goose/v3"
+	"gorm.io/gorm"
+)
+
+// MigrationsDir is where `server migrate create` writes new migrations,
+// relative to the backend directory.
+const MigrationsDir = "internal/database/migrations"
+
+var gooseOnce sync.Once
+
+func useEmbeddedMigrations() {
+	gooseOnce.Do(func() {
+		goose.SetBaseFS(migrations.FS)
+		if err := goose.SetDialect("postgres"); err != nil {
+			panic(err)
+		}
+	})
+}
+
+// Migrate runs a goose command ("up", "up-to", "down", "down-to", "redo",
+// "status", "version", ...) against the embedded migrations.
+func Migrate(ctx context.Context, db *gorm.DB, command string, args ...string) error {
+	useEmbeddedMigrations()
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return goose.RunContext(ctx, command, sqlDB, ".", args...)
+}
+
+// PendingMigrations counts the embedded migrations not yet applied.
+func PendingMigrations(ctx context.Context, db *gorm.DB) (int, error) {
+	useEmbeddedMigrations()
+	sqlDB, err := db.DB()
+	if err != nil {
+		return 0, err
+	}
+	all, err := goose.CollectMigrations(".", 0, goose.MaxVersion)
+	if err != nil {
+		return 0, fmt.Errorf("collect migrations: %w", err)
+	}
+	current, err := goose.GetDBVersionContext(ctx, sqlDB)
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	pending := 0
+	for _, m := range all {
+		if m.Version > current {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// CreateMigration writes an empty SQL migration named after name into dir.
+func CreateMigration(dir, name string) error {
+	return goose.Create(nil, dir, name, "sql")
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database/migrations"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/fieldcrypt"
+	"github.com/pressly/goose/v3"
+	"gorm.io/gorm/schema"
+)
+
+func TestMigrationsAreVersioned(t *testing.T) {
+	useEmbeddedMigrations()
+	all, err := goose.CollectMigrations(".", 0, goose.MaxVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) == 0 || all[0].Version != 1 {
+		t.Fatalf("migrations = %v, want the baseline as version 1", all)
+	}
+	for i, m := range all {
+		if m.Version != int64(i+1) {
+			t.Errorf("%s: version %d, want %d; versions must be sequential", m.Source, m.Version, i+1)
+		}
+	}
+}
+
+// TestMigrationsCoverModels keeps AutoMigrate and the versioned migrations
+// from drifting apart: every table and column GORM would create must be
+// created by some migration.
+func TestMigrationsCoverModels(t *testing.T) {
+	keyring, err := fieldcrypt.New("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fieldcrypt.Use(keyring)
+
+	var sql strings.Builder
+	files, _ := fs.Glob(migrations.FS, "*.sql")
+	for _, name := range files {
+		body, _ := fs.ReadFile(migrations.FS, name)
+		sql.Write(body)
+	}
+	text := sql.String()
+
+	cache := &sync.Map{}
+	for _, model := range autoMigrateModels {
+		s, err := schema.Parse(model, cache, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(text, `CREATE TABLE IF NOT EXISTS "`+s.Table+`"`) {
+			t.Errorf("no migration creates table %s", s.Table)
+			continue
+		}
+		for _, f := range s.Fields {
+			if f.DBName != "" && !strings.Contains(text, `"`+f.DBName+`"`) {
+				t.Errorf("no migration creates column %s.%s", s.Table, f.DBName)
+			}
+		}
+	}
+}
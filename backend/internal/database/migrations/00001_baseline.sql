@@ -0,0 +1,817 @@
+-- +goose Up
+-- The schema as GORM AutoMigrate created it before versioned migrations.
+-- Every statement is idempotent, so databases AutoMigrate already manages
+-- adopt this version without changes.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "email" varchar(255) NOT NULL,
+    "apple_sub" varchar(255),
+    "handle" varchar(20),
+    "handle_changed_at" timestamptz,
+    "discoverable_by_handle" boolean NOT NULL DEFAULT true,
+    "leaderboard_opt_in" boolean NOT NULL DEFAULT false,
+    "leaderboard_name" varchar(40),
+    "leaderboard_name_locked" boolean NOT NULL DEFAULT false,
+    "discoverable_by_contacts" boolean NOT NULL DEFAULT false,
+    "password" text NOT NULL,
+    "timezone" varchar(64) NOT NULL DEFAULT 'UTC',
+    "email_verified_at" timestamptz,
+    "ai_memory_enabled" boolean NOT NULL DEFAULT false,
+    "research_consent" boolean NOT NULL DEFAULT false,
+    "sessions_revoked_at" timestamptz,
+    "quota_reset_at" timestamptz,
+    "stripe_customer_id" varchar(255),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_stripe_customer_id" ON "users" ("stripe_customer_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_leaderboard_name" ON "users" ("leaderboard_name");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_handle" ON "users" ("handle");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_apple_sub" ON "users" ("apple_sub");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+
+CREATE TABLE IF NOT EXISTS "refresh_tokens" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "family_id" uuid,
+    "token_hash" varchar(64) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "revoked" boolean DEFAULT false,
+    "revoked_reason" varchar(30),
+    "revoked_at" timestamptz,
+    "device_name" varchar(100),
+    "platform" varchar(20),
+    "app_version" varchar(30),
+    "user_agent" varchar(255),
+    "ip_address" varchar(45),
+    "last_used_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_refresh_tokens_token_hash" ON "refresh_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_refresh_tokens_family_id" ON "refresh_tokens" ("family_id");
+CREATE INDEX IF NOT EXISTS "idx_refresh_tokens_user_id" ON "refresh_tokens" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "subscriptions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid,
+    "revenue_cat_id" varchar(255),
+    "original_app_user_id" varchar(255),
+    "transaction_id" varchar(255),
+    "original_transaction_id" varchar(255),
+    "stripe_subscription_id" varchar(255),
+    "source" varchar(20) NOT NULL DEFAULT 'revenuecat',
+    "product_id" varchar(255),
+    "status" varchar(50) NOT NULL DEFAULT 'inactive',
+    "current_period_start" timestamptz,
+    "current_period_end" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_subscriptions_stripe_subscription_id" ON "subscriptions" ("stripe_subscription_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_subscriptions_original_transaction_id" ON "subscriptions" ("original_transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_transaction_id" ON "subscriptions" ("transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_original_app_user_id" ON "subscriptions" ("original_app_user_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_revenue_cat_id" ON "subscriptions" ("revenue_cat_id");
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_user_id" ON "subscriptions" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "blocks" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "blocker_id" uuid NOT NULL,
+    "blocked_id" uuid NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_blocks_blocked_id" ON "blocks" ("blocked_id");
+CREATE INDEX IF NOT EXISTS "idx_blocks_blocker_id" ON "blocks" ("blocker_id");
+
+CREATE TABLE IF NOT EXISTS "reports" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "reporter_id" uuid NOT NULL,
+    "content_type" varchar(50) NOT NULL,
+    "content_id" varchar(255) NOT NULL,
+    "reason" varchar(500) NOT NULL,
+    "status" varchar(50) NOT NULL DEFAULT 'pending',
+    "admin_note" varchar(1000),
+    "case_id" uuid,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_reports_case_id" ON "reports" ("case_id");
+CREATE INDEX IF NOT EXISTS "idx_reports_content_id" ON "reports" ("content_id");
+CREATE INDEX IF NOT EXISTS "idx_reports_reporter_id" ON "reports" ("reporter_id");
+
+CREATE TABLE IF NOT EXISTS "moderation_cases" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "content_type" varchar(50) NOT NULL,
+    "content_id" varchar(255) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'open',
+    "report_count" bigint NOT NULL DEFAULT 0,
+    "admin_note" varchar(1000),
+    "last_reported_at" timestamptz,
+    "escalated_at" timestamptz,
+    "resolved_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_moderation_cases_status" ON "moderation_cases" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_case_open_content" ON "moderation_cases" ("content_type","content_id") WHERE status IN ('open','escalated');
+
+CREATE TABLE IF NOT EXISTS "moderation_actions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "type" varchar(20) NOT NULL,
+    "reason" varchar(1000) NOT NULL,
+    "case_id" uuid,
+    "created_by" uuid NOT NULL,
+    "expires_at" timestamptz,
+    "revoked_at" timestamptz,
+    "revoked_by" uuid,
+    "revoke_reason" varchar(1000),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_moderation_actions_user_id" ON "moderation_actions" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "appeals" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "action_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "message" text NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "admin_note" varchar(1000),
+    "reviewed_by" uuid,
+    "reviewed_at" timestamptz,
+    "due_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_appeals_due_at" ON "appeals" ("due_at");
+CREATE INDEX IF NOT EXISTS "idx_appeals_status" ON "appeals" ("status");
+CREATE INDEX IF NOT EXISTS "idx_appeals_user_id" ON "appeals" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_appeals_action_id" ON "appeals" ("action_id");
+
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "actor_id" uuid,
+    "action" varchar(100) NOT NULL,
+    "target_type" varchar(30) NOT NULL,
+    "target_id" varchar(64) NOT NULL,
+    "details" jsonb,
+    "ip_address" varchar(45),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_created_at" ON "audit_logs" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_audit_target" ON "audit_logs" ("target_type","target_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_action" ON "audit_logs" ("action");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_actor_id" ON "audit_logs" ("actor_id");
+
+CREATE TABLE IF NOT EXISTS "webhook_events" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "provider" varchar(30) NOT NULL,
+    "event_id" varchar(255) NOT NULL,
+    "event_type" varchar(100),
+    "payload" text NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamptz,
+    "last_error" varchar(1000),
+    "processed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_webhook_events_next_attempt_at" ON "webhook_events" ("next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_webhook_events_status" ON "webhook_events" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_webhook_event_provider_id" ON "webhook_events" ("provider","event_id") WHERE event_id <> '';
+
+CREATE TABLE IF NOT EXISTS "processed_events" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "source" varchar(30) NOT NULL,
+    "event_id" varchar(255) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "last_error" varchar(1000),
+    "payload" text,
+    "processed_at" timestamptz,
+    "quarantined_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_processed_events_status" ON "processed_events" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_processed_event_source_id" ON "processed_events" ("source","event_id");
+
+CREATE TABLE IF NOT EXISTS "image_violations" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "provider" varchar(20) NOT NULL,
+    "categories" jsonb,
+    "image_ref" text,
+    "case_id" uuid,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_image_violation_user_time" ON "image_violations" ("user_id","created_at");
+
+CREATE TABLE IF NOT EXISTS "ai_interactions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "purpose" varchar(30) NOT NULL,
+    "provider" varchar(20) NOT NULL,
+    "model" varchar(100),
+    "outcome" varchar(10) NOT NULL,
+    "duration_ms" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_ai_interaction_user_time" ON "ai_interactions" ("user_id","created_at");
+
+CREATE TABLE IF NOT EXISTS "aura_readings" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "image_url" text NOT NULL,
+    "aura_color" varchar(50) NOT NULL,
+    "secondary_color" varchar(50) DEFAULT NULL,
+    "energy_level" integer,
+    "mood_score" integer,
+    "personality" text,
+    "strengths" jsonb,
+    "challenges" jsonb,
+    "daily_advice" text,
+    "provenance" jsonb,
+    "palette" jsonb,
+    "prompt_variant" varchar(20),
+    "language" varchar(10) NOT NULL DEFAULT 'en',
+    "status" varchar(10) NOT NULL DEFAULT 'ready',
+    "group_reading_id" uuid,
+    "group_position" smallint,
+    "share_count" bigint NOT NULL DEFAULT 0,
+    "rating" smallint,
+    "rating_tags" jsonb,
+    "rated_at" timestamptz,
+    "note" text,
+    "mood_tags" jsonb,
+    "noted_at" timestamptz,
+    "pinned" boolean NOT NULL DEFAULT false,
+    "pinned_at" timestamptz,
+    "synthetic" boolean NOT NULL DEFAULT false,
+    "analyzed_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chk_aura_readings_rating" CHECK (rating IS NULL OR (rating >= 1 AND rating <= 5)),
+    CONSTRAINT "chk_aura_readings_mood_score" CHECK (mood_score >= 1 AND mood_score <= 10),
+    CONSTRAINT "chk_aura_readings_energy_level" CHECK (energy_level >= 1 AND energy_level <= 100)
+);
+CREATE INDEX IF NOT EXISTS "idx_aura_readings_deleted_at" ON "aura_readings" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_aura_readings_group_reading_id" ON "aura_readings" ("group_reading_id");
+CREATE INDEX IF NOT EXISTS "idx_aura_readings_prompt_variant" ON "aura_readings" ("prompt_variant");
+CREATE INDEX IF NOT EXISTS "idx_aura_user_mood" ON "aura_readings" ("user_id","mood_score");
+CREATE INDEX IF NOT EXISTS "idx_aura_user_energy" ON "aura_readings" ("user_id","energy_level");
+CREATE INDEX IF NOT EXISTS "idx_aura_user_color" ON "aura_readings" ("user_id","aura_color","created_at");
+CREATE INDEX IF NOT EXISTS "idx_aura_readings_user_id" ON "aura_readings" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_aura_user_created" ON "aura_readings" ("user_id","created_at","id");
+
+CREATE TABLE IF NOT EXISTS "aura_matches" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "friend_id" uuid NOT NULL,
+    "user_aura_id" uuid NOT NULL,
+    "friend_aura_id" uuid NOT NULL,
+    "compatibility_score" integer,
+    "synergy" text,
+    "tension" text,
+    "advice" text,
+    "color_affinity" bigint NOT NULL DEFAULT 0,
+    "energy_alignment" bigint NOT NULL DEFAULT 0,
+    "mood_correlation" bigint NOT NULL DEFAULT 0,
+    "blurb" text,
+    "narrative" text,
+    "narrative_user_aura_id" uuid,
+    "narrative_friend_aura_id" uuid,
+    "narrative_generated_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chk_aura_matches_compatibility_score" CHECK (compatibility_score >= 0 AND compatibility_score <= 100)
+);
+CREATE INDEX IF NOT EXISTS "idx_aura_matches_friend_id" ON "aura_matches" ("friend_id");
+CREATE INDEX IF NOT EXISTS "idx_aura_matches_user_id" ON "aura_matches" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "aura_streaks" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "current_streak" integer DEFAULT 0,
+    "longest_streak" integer DEFAULT 0,
+    "total_scans" integer DEFAULT 0,
+    "last_scan_date" date,
+    "unlocked_colors" jsonb DEFAULT '[]',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_aura_streaks_user_id" ON "aura_streaks" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "user_memories" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "category" varchar(30) NOT NULL,
+    "content" text NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_memories_user_id" ON "user_memories" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "notification_preferences" (
+    "user_id" uuid,
+    "matrix" jsonb,
+    "quiet_hours_enabled" boolean NOT NULL DEFAULT true,
+    "quiet_hours_start" varchar(5) NOT NULL DEFAULT '22:00',
+    "quiet_hours_end" varchar(5) NOT NULL DEFAULT '08:00',
+    "social_delivery" varchar(10) NOT NULL DEFAULT 'digest',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("user_id")
+);
+
+CREATE TABLE IF NOT EXISTS "notifications" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "category" varchar(30) NOT NULL,
+    "title" varchar(200) NOT NULL,
+    "body" text,
+    "data" jsonb,
+    "read_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_notifications_created_at" ON "notifications" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_notifications_user_id" ON "notifications" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "notification_dispatches" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "category" varchar(30) NOT NULL,
+    "priority" varchar(10) NOT NULL,
+    "channels" jsonb,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_notification_dispatch_user_time" ON "notification_dispatches" ("user_id","created_at");
+
+CREATE TABLE IF NOT EXISTS "pending_notifications" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "category" varchar(30) NOT NULL,
+    "title" varchar(200) NOT NULL,
+    "body" text,
+    "data" jsonb,
+    "due_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_pending_notifications_due_at" ON "pending_notifications" ("due_at");
+CREATE INDEX IF NOT EXISTS "idx_pending_notifications_user_id" ON "pending_notifications" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "email_suppressions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "email" varchar(255) NOT NULL,
+    "reason" varchar(20) NOT NULL,
+    "source" varchar(20),
+    "detail" varchar(500),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_email_suppressions_email" ON "email_suppressions" ("email");
+
+CREATE TABLE IF NOT EXISTS "auth_tokens" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "purpose" varchar(30) NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "payload" varchar(255),
+    "expires_at" timestamptz NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_auth_tokens_token_hash" ON "auth_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_auth_tokens_purpose" ON "auth_tokens" ("purpose");
+CREATE INDEX IF NOT EXISTS "idx_auth_tokens_user_id" ON "auth_tokens" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "contact_hashes" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "kind" varchar(10) NOT NULL,
+    "hash" varchar(64) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_contact_hashes_hash" ON "contact_hashes" ("hash");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_contact_hash_user_kind" ON "contact_hashes" ("user_id","kind");
+
+CREATE TABLE IF NOT EXISTS "friendships" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "requester_id" uuid NOT NULL,
+    "addressee_id" uuid NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'accepted',
+    "source" varchar(20),
+    "accepted_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_friendships_addressee_id" ON "friendships" ("addressee_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_friendship_pair" ON "friendships" ("requester_id","addressee_id");
+
+CREATE TABLE IF NOT EXISTS "friend_invite_codes" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "code" varchar(16) NOT NULL,
+    "max_uses" bigint NOT NULL DEFAULT 1,
+    "use_count" bigint NOT NULL DEFAULT 0,
+    "expires_at" timestamptz NOT NULL,
+    "revoked_at" timestamptz,
+    "invited_email_hash" varchar(64),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_friend_invite_codes_invited_email_hash" ON "friend_invite_codes" ("invited_email_hash");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_friend_invite_codes_code" ON "friend_invite_codes" ("code");
+CREATE INDEX IF NOT EXISTS "idx_friend_invite_codes_user_id" ON "friend_invite_codes" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "data_exports" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "format" varchar(10) NOT NULL,
+    "file_path" varchar(500),
+    "size_bytes" bigint,
+    "error" varchar(500),
+    "expires_at" timestamptz,
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_data_exports_status" ON "data_exports" ("status");
+CREATE INDEX IF NOT EXISTS "idx_data_exports_user_id" ON "data_exports" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "onboarding_progress" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "step_id" varchar(40) NOT NULL,
+    "step_version" bigint NOT NULL DEFAULT 1,
+    "status" varchar(10) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_onboarding_user_step" ON "onboarding_progress" ("user_id","step_id");
+
+CREATE TABLE IF NOT EXISTS "surveys" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "kind" varchar(20) NOT NULL,
+    "title" varchar(120) NOT NULL,
+    "description" text,
+    "questions" jsonb NOT NULL,
+    "segment" jsonb,
+    "active" boolean NOT NULL DEFAULT false,
+    "starts_at" timestamptz,
+    "ends_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_surveys_active" ON "surveys" ("active");
+CREATE INDEX IF NOT EXISTS "idx_surveys_kind" ON "surveys" ("kind");
+
+CREATE TABLE IF NOT EXISTS "survey_responses" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "survey_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "reading_id" uuid,
+    "answers" jsonb,
+    "score" decimal,
+    "prompt_variant" varchar(20),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_survey_responses_prompt_variant" ON "survey_responses" ("prompt_variant");
+CREATE INDEX IF NOT EXISTS "idx_survey_responses_reading_id" ON "survey_responses" ("reading_id");
+CREATE INDEX IF NOT EXISTS "idx_survey_response_user" ON "survey_responses" ("survey_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "device_tokens" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "token" varchar(512) NOT NULL,
+    "platform" varchar(10) NOT NULL,
+    "provider" varchar(10) NOT NULL,
+    "app_version" varchar(32),
+    "last_seen_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_device_tokens_token" ON "device_tokens" ("token");
+CREATE INDEX IF NOT EXISTS "idx_device_tokens_user_id" ON "device_tokens" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "reminder_logs" (
+    "user_id" uuid,
+    "kind" varchar(30),
+    "day" varchar(10),
+    "created_at" timestamptz,
+    PRIMARY KEY ("user_id","kind","day")
+);
+
+CREATE TABLE IF NOT EXISTS "aura_forecasts" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "day" varchar(10) NOT NULL,
+    "dominant_color" varchar(50) NOT NULL,
+    "energy_outlook" varchar(10) NOT NULL,
+    "headline" varchar(200) NOT NULL,
+    "forecast" text NOT NULL,
+    "focus" varchar(100),
+    "lucky_color" varchar(50),
+    "source" varchar(10) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_forecast_user_day" ON "aura_forecasts" ("user_id","day");
+
+CREATE TABLE IF NOT EXISTS "group_readings" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "image_url" text NOT NULL,
+    "people_count" bigint NOT NULL,
+    "dominant_color" varchar(50) NOT NULL,
+    "harmony" bigint NOT NULL,
+    "group_energy" text,
+    "provenance" jsonb,
+    "created_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_group_readings_deleted_at" ON "group_readings" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_group_readings_user_id" ON "group_readings" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "stored_photos" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "reading_id" uuid NOT NULL,
+    "content_type" varchar(20) NOT NULL,
+    "original_path" text,
+    "original_bytes" bigint NOT NULL DEFAULT 0,
+    "thumbnail_path" text,
+    "thumbnail_bytes" bigint NOT NULL DEFAULT 0,
+    "evicted_at" timestamptz,
+    "cold_key" text,
+    "archived_at" timestamptz,
+    "restore_requested_at" timestamptz,
+    "restored_at" timestamptz,
+    "purge_requested_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_stored_photos_purge_requested_at" ON "stored_photos" ("purge_requested_at");
+CREATE INDEX IF NOT EXISTS "idx_stored_photos_restore_requested_at" ON "stored_photos" ("restore_requested_at");
+CREATE INDEX IF NOT EXISTS "idx_stored_photos_archived_at" ON "stored_photos" ("archived_at");
+CREATE INDEX IF NOT EXISTS "idx_stored_photos_evicted_at" ON "stored_photos" ("evicted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_stored_photos_reading_id" ON "stored_photos" ("reading_id");
+CREATE INDEX IF NOT EXISTS "idx_stored_photos_user_id" ON "stored_photos" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "research_exports" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "schema_version" bigint NOT NULL,
+    "day" varchar(10) NOT NULL,
+    "status" varchar(20) NOT NULL,
+    "object_key" text,
+    "rows" bigint NOT NULL DEFAULT 0,
+    "participants" bigint NOT NULL DEFAULT 0,
+    "error" text,
+    "completed_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_research_export_day" ON "research_exports" ("schema_version","day");
+
+CREATE TABLE IF NOT EXISTS "reading_corrections" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "reading_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "admin_id" uuid NOT NULL,
+    "reason" text NOT NULL,
+    "original" jsonb,
+    "corrected" jsonb,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_reading_corrections_user_id" ON "reading_corrections" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_reading_corrections_reading_id" ON "reading_corrections" ("reading_id");
+
+CREATE TABLE IF NOT EXISTS "reading_versions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "reading_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "version" bigint NOT NULL,
+    "aura_color" varchar(50) NOT NULL,
+    "secondary_color" varchar(50),
+    "energy_level" bigint,
+    "mood_score" bigint,
+    "personality" text,
+    "strengths" jsonb,
+    "challenges" jsonb,
+    "daily_advice" text,
+    "provenance" jsonb,
+    "language" varchar(10),
+    "analyzed_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_reading_version_user_time" ON "reading_versions" ("user_id","created_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_reading_version" ON "reading_versions" ("reading_id","version");
+
+CREATE TABLE IF NOT EXISTS "admin_messages" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "admin_id" uuid NOT NULL,
+    "template" varchar(50) NOT NULL,
+    "locale" varchar(10) NOT NULL,
+    "title" text NOT NULL,
+    "body" text NOT NULL,
+    "ticket_type" varchar(20),
+    "ticket_id" varchar(100),
+    "channels" jsonb,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_admin_message_ticket" ON "admin_messages" ("ticket_type","ticket_id");
+CREATE INDEX IF NOT EXISTS "idx_admin_messages_user_id" ON "admin_messages" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "user_aggregates" (
+    "user_id" uuid,
+    "total_readings" bigint NOT NULL DEFAULT 0,
+    "dominant_color" varchar(50),
+    "color_counts" jsonb,
+    "average_energy" decimal NOT NULL DEFAULT 0,
+    "average_mood" decimal NOT NULL DEFAULT 0,
+    "last_scan_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("user_id")
+);
+
+CREATE TABLE IF NOT EXISTS "data_migrations" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "name" varchar(100) NOT NULL,
+    "phase" varchar(20) NOT NULL DEFAULT 'pending',
+    "total" bigint NOT NULL DEFAULT 0,
+    "backfilled" bigint NOT NULL DEFAULT 0,
+    "backfill_cursor" text,
+    "backfilled_at" timestamptz,
+    "verified" bigint NOT NULL DEFAULT 0,
+    "mismatches" bigint NOT NULL DEFAULT 0,
+    "verify_cursor" text,
+    "verified_at" timestamptz,
+    "last_error" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_data_migrations_name" ON "data_migrations" ("name");
+
+CREATE TABLE IF NOT EXISTS "traits" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "kind" varchar(20) NOT NULL,
+    "slug" varchar(120) NOT NULL,
+    "name" varchar(200) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_trait_kind_slug" ON "traits" ("kind","slug");
+
+CREATE TABLE IF NOT EXISTS "reading_traits" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "reading_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "kind" varchar(20) NOT NULL,
+    "position" bigint NOT NULL,
+    "text" text NOT NULL,
+    "trait_id" uuid,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_reading_traits_trait_id" ON "reading_traits" ("trait_id");
+CREATE INDEX IF NOT EXISTS "idx_reading_traits_user_id" ON "reading_traits" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_reading_trait_position" ON "reading_traits" ("reading_id","kind","position");
+
+CREATE TABLE IF NOT EXISTS "recurring_themes" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "label" varchar(100) NOT NULL,
+    "keywords" jsonb,
+    "readings" bigint NOT NULL,
+    "share" decimal NOT NULL,
+    "first_seen" timestamptz NOT NULL,
+    "last_seen" timestamptz NOT NULL,
+    "rank" bigint NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_recurring_themes_user_id" ON "recurring_themes" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "theme_analyses" (
+    "user_id" uuid,
+    "readings" bigint NOT NULL,
+    "latest_reading_at" timestamptz,
+    "method" varchar(20) NOT NULL,
+    "analyzed_at" timestamptz NOT NULL,
+    PRIMARY KEY ("user_id")
+);
+
+-- +goose StatementBegin
+DO $$ BEGIN
+    ALTER TABLE "refresh_tokens" ADD CONSTRAINT "fk_refresh_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id");
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DO $$ BEGIN
+    ALTER TABLE "subscriptions" ADD CONSTRAINT "fk_subscriptions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id");
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DO $$ BEGIN
+    ALTER TABLE "blocks" ADD CONSTRAINT "fk_blocks_blocker" FOREIGN KEY ("blocker_id") REFERENCES "users"("id");
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DO $$ BEGIN
+    ALTER TABLE "blocks" ADD CONSTRAINT "fk_blocks_blocked" FOREIGN KEY ("blocked_id") REFERENCES "users"("id");
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DO $$ BEGIN
+    ALTER TABLE "reports" ADD CONSTRAINT "fk_reports_reporter" FOREIGN KEY ("reporter_id") REFERENCES "users"("id");
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DO $$ BEGIN
+    ALTER TABLE "reports" ADD CONSTRAINT "fk_moderation_cases_reports" FOREIGN KEY ("case_id") REFERENCES "moderation_cases"("id");
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DO $$ BEGIN
+    ALTER TABLE "appeals" ADD CONSTRAINT "fk_appeals_action" FOREIGN KEY ("action_id") REFERENCES "moderation_actions"("id");
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DO $$ BEGIN
+    ALTER TABLE "aura_readings" ADD CONSTRAINT "fk_group_readings_people" FOREIGN KEY ("group_reading_id") REFERENCES "group_readings"("id");
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DO $$ BEGIN
+    ALTER TABLE "reading_versions" ADD CONSTRAINT "fk_aura_readings_versions" FOREIGN KEY ("reading_id") REFERENCES "aura_readings"("id");
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+-- +goose StatementEnd
+
+-- audit_logs is append-only: updates and deletes are rejected by the
+-- database, not just avoided by the application.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION audit_logs_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+DROP TRIGGER IF EXISTS audit_logs_immutable ON audit_logs;
+CREATE TRIGGER audit_logs_immutable BEFORE UPDATE OR DELETE ON audit_logs
+FOR EACH ROW EXECUTE FUNCTION audit_logs_immutable();
+
+-- +goose Down
+-- The baseline is not reversible; restore a backup instead.
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "similar_discovery_opt_in" boolean NOT NULL DEFAULT false;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "birth_year" bigint;
+
+-- +goose Down
+ALTER TABLE "users" DROP COLUMN IF EXISTS "birth_year";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "similar_discovery_opt_in";
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "request_logs" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "request_id" varchar(32) NOT NULL,
+    "traced" boolean NOT NULL DEFAULT false,
+    "user_id" uuid,
+    "method" varchar(10) NOT NULL,
+    "route" varchar(255) NOT NULL,
+    "target_id" varchar(255),
+    "status" bigint NOT NULL,
+    "code" varchar(64),
+    "error" text,
+    "latency_ms" bigint NOT NULL DEFAULT 0,
+    "platform" varchar(20),
+    "app_version" varchar(32),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_request_logs_request_id" ON "request_logs" ("request_id");
+CREATE INDEX IF NOT EXISTS "idx_request_logs_user_id" ON "request_logs" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_request_logs_created_at" ON "request_logs" ("created_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "request_logs";
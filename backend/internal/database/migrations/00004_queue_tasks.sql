@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "queue_tasks" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "kind" varchar(64) NOT NULL,
+    "payload" bytea NOT NULL,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "run_at" timestamptz NOT NULL,
+    "locked_until" timestamptz,
+    "last_error" text,
+    "failed_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_queue_tasks_run_at" ON "queue_tasks" ("run_at");
+CREATE INDEX IF NOT EXISTS "idx_queue_tasks_failed_at" ON "queue_tasks" ("failed_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "queue_tasks";
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "ai_usage" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid,
+    "purpose" varchar(30) NOT NULL,
+    "provider" varchar(20) NOT NULL,
+    "model" varchar(100),
+    "prompt_tokens" bigint NOT NULL DEFAULT 0,
+    "completion_tokens" bigint NOT NULL DEFAULT 0,
+    "cost_usd" decimal NOT NULL DEFAULT 0,
+    "latency_ms" bigint NOT NULL DEFAULT 0,
+    "outcome" varchar(10) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_ai_usage_user_id" ON "ai_usage" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_ai_usage_created_at" ON "ai_usage" ("created_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "ai_usage";
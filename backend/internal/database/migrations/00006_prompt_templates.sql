@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "prompt_templates" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "name" varchar(50) NOT NULL,
+    "provider" varchar(20) NOT NULL DEFAULT '',
+    "version" bigint NOT NULL,
+    "system" text NOT NULL,
+    "user" text NOT NULL,
+    "notes" text,
+    "active" boolean NOT NULL DEFAULT false,
+    "activated_at" timestamptz,
+    "created_by" uuid,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_prompt_template_version" ON "prompt_templates" ("name", "provider", "version");
+CREATE INDEX IF NOT EXISTS "idx_prompt_templates_active" ON "prompt_templates" ("active");
+
+ALTER TABLE "aura_readings" ADD COLUMN IF NOT EXISTS "prompt_template_id" uuid;
+CREATE INDEX IF NOT EXISTS "idx_aura_readings_prompt_template_id" ON "aura_readings" ("prompt_template_id");
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_aura_readings_prompt_template_id";
+ALTER TABLE "aura_readings" DROP COLUMN IF EXISTS "prompt_template_id";
+DROP TABLE IF EXISTS "prompt_templates";
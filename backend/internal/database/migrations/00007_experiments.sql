@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "experiments" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "key" varchar(50) NOT NULL,
+    "description" text,
+    "status" varchar(20) NOT NULL DEFAULT 'draft',
+    "variants" jsonb NOT NULL,
+    "created_by" uuid,
+    "started_at" timestamptz,
+    "ended_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_experiments_key" ON "experiments" ("key");
+CREATE INDEX IF NOT EXISTS "idx_experiments_status" ON "experiments" ("status");
+
+ALTER TABLE "aura_readings" ADD COLUMN IF NOT EXISTS "experiment_id" uuid;
+ALTER TABLE "aura_readings" ADD COLUMN IF NOT EXISTS "experiment_variant" varchar(50);
+CREATE INDEX IF NOT EXISTS "idx_aura_readings_experiment_id" ON "aura_readings" ("experiment_id");
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_aura_readings_experiment_id";
+ALTER TABLE "aura_readings" DROP COLUMN IF EXISTS "experiment_variant";
+ALTER TABLE "aura_readings" DROP COLUMN IF EXISTS "experiment_id";
+DROP TABLE IF EXISTS "experiments";
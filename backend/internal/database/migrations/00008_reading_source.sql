@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE "aura_readings" ADD COLUMN IF NOT EXISTS "source" varchar(20) NOT NULL DEFAULT '';
+ALTER TABLE "aura_readings" ADD COLUMN IF NOT EXISTS "provider_latency_ms" bigint;
+
+-- +goose Down
+ALTER TABLE "aura_readings" DROP COLUMN IF EXISTS "provider_latency_ms";
+ALTER TABLE "aura_readings" DROP COLUMN IF EXISTS "source";
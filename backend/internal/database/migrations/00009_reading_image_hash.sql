@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE "aura_readings" ADD COLUMN IF NOT EXISTS "image_hash" varchar(64);
+
+-- +goose Down
+ALTER TABLE "aura_readings" DROP COLUMN IF EXISTS "image_hash";
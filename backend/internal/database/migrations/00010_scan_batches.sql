@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "scan_batches" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "image_count" bigint NOT NULL,
+    "rejected" jsonb,
+    "created_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_scan_batches_user_id" ON "scan_batches" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_scan_batches_deleted_at" ON "scan_batches" ("deleted_at");
+
+ALTER TABLE "aura_readings" ADD COLUMN IF NOT EXISTS "scan_batch_id" uuid;
+CREATE INDEX IF NOT EXISTS "idx_aura_readings_scan_batch_id" ON "aura_readings" ("scan_batch_id");
+
+-- +goose Down
+DROP INDEX IF EXISTS "idx_aura_readings_scan_batch_id";
+ALTER TABLE "aura_readings" DROP COLUMN IF EXISTS "scan_batch_id";
+DROP TABLE IF EXISTS "scan_batches";
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "timezone_changed_at" timestamptz;
+
+-- +goose Down
+ALTER TABLE "users" DROP COLUMN IF EXISTS "timezone_changed_at";
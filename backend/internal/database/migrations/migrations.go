@@ -0,0 +1,13 @@
+// Package migrations embeds the versioned SQL migrations applied by
+// `server migrate up`. Files are named NNNNN_description.sql and hold
+// goose "-- +goose Up" and "-- +goose Down" sections.
+//
+// While DB_AUTO_MIGRATE is on, GORM may already have made a change before
+// its migration runs, so write migrations idempotently (IF NOT EXISTS,
+// IF EXISTS).
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
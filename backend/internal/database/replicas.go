@@ -0,0 +1,66 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// replicaResolver names the resolver that sends reads to the replicas.
+// Queries opt in with Replica; nothing is routed to a replica implicitly.
+const replicaResolver = "replica"
+
+// Replica is a scope that runs a read on a read replica when replicas are
+// configured, and on the primary otherwise. Replicas lag the primary
+// slightly, so only reads that can show data a moment old use it: lists,
+// stats and dashboards, never a read that follows its own write.
+func Replica(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Use(replicaResolver))
+}
+
+// replicaDSNs splits DB_REPLICA_DSNS.
+func replicaDSNs(list string) []string {
+	var dsns []string
+	for _, dsn := range strings.Split(list, ",") {
+		if dsn = strings.TrimSpace(dsn); dsn != "" {
+			dsns = append(dsns, dsn)
+		}
+	}
+	return dsns
+}
+
+// configurePool registers the read replicas, if any, and applies the pool
+// limits to every connection pool.
+func configurePool(db *gorm.DB, cfg *config.Config) error {
+	dsns := replicaDSNs(cfg.DBReplicaDSNs)
+	if len(dsns) == 0 {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+		sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+		return nil
+	}
+
+	replicas := make([]gorm.Dialector, len(dsns))
+	for i, dsn := range dsns {
+		replicas[i] = postgres.Open(dsn)
+	}
+	resolver := dbresolver.Register(dbresolver.Config{Replicas: replicas, Policy: dbresolver.RandomPolicy{}}, replicaResolver)
+	if err := db.Use(resolver); err != nil {
+		return fmt.Errorf("connect to read replicas: %w", err)
+	}
+	// The resolver applies the limits to the primary and every replica.
+	resolver.SetMaxOpenConns(cfg.DBMaxOpenConns).
+		SetMaxIdleConns(cfg.DBMaxIdleConns).
+		SetConnMaxLifetime(cfg.DBConnMaxLifetime).
+		SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+	return nil
+}
@@ -0,0 +1,20 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplicaDSNs(t *testing.T) {
+	cases := map[string][]string{
+		"":                           nil,
+		" , ":                        nil,
+		"host=r1":                    {"host=r1"},
+		"host=r1 dbname=a, host=r2,": {"host=r1 dbname=a", "host=r2"},
+	}
+	for list, want := range cases {
+		if got := replicaDSNs(list); !reflect.DeepEqual(got, want) {
+			t.Errorf("replicaDSNs(%q) = %q, want %q", list, got, want)
+		}
+	}
+}
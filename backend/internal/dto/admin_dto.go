@@ -0,0 +1,130 @@
+package dto
+
+import "time"
+
+// AdminDailyMetrics is one UTC day of the admin metrics overview
+type AdminDailyMetrics struct {
+	Day               string  `json:"day"` // YYYY-MM-DD (UTC)
+	ActiveUsers       int64   `json:"active_users"`
+	Scans             int64   `json:"scans"`
+	FallbackScans     int64   `json:"fallback_scans"`
+	FallbackRate      float64 `json:"fallback_rate"`
+	EstimatedSpendUSD float64 `json:"estimated_spend_usd"`
+	AISpendUSD        float64 `json:"ai_spend_usd"` // tracked, from ai_usage
+}
+
+// AdminMetricsOverview summarizes usage and revenue over the last Days days
+type AdminMetricsOverview struct {
+	Days                int                 `json:"days"`
+	Daily               []AdminDailyMetrics `json:"daily"`
+	AverageDAU          float64             `json:"average_dau"`
+	Scans               int64               `json:"scans"`
+	FallbackRate        float64             `json:"fallback_rate"`
+	ScansByProvider     map[string]int64    `json:"scans_by_provider"`
+	EstimatedSpendUSD   float64             `json:"estimated_spend_usd"`
+	ActiveSubscriptions map[string]int64    `json:"active_subscriptions"` // by billing source
+	NewSubscriptions    int64               `json:"new_subscriptions"`
+	AIUsage             AdminAIUsage        `json:"ai_usage"`
+}
+
+// AdminAIUsage is the tracked AI provider usage and spend over the overview
+// window, with today's standing against the daily budget
+type AdminAIUsage struct {
+	Calls            int64                           `json:"calls"`
+	FailedCalls      int64                           `json:"failed_calls"`
+	PromptTokens     int64                           `json:"prompt_tokens"`
+	CompletionTokens int64                           `json:"completion_tokens"`
+	SpendUSD         float64                         `json:"spend_usd"`
+	ByProvider       map[string]AdminAIProviderUsage `json:"by_provider"`
+	TodaySpendUSD    float64                         `json:"today_spend_usd"`
+	DailyBudgetUSD   float64                         `json:"daily_budget_usd"` // 0 = no cap
+	BudgetExceeded   bool                            `json:"budget_exceeded"`
+}
+
+// AdminAIProviderUsage is one provider's share of AdminAIUsage
+type AdminAIProviderUsage struct {
+	Calls            int64   `json:"calls"`
+	FailedCalls      int64   `json:"failed_calls"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	SpendUSD         float64 `json:"spend_usd"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms"`
+}
+
+// AdminUserDetail is the admin view of one user
+type AdminUserDetail struct {
+	AdminUserResponse
+	EmailVerified  bool                   `json:"email_verified"`
+	Deactivated    bool                   `json:"deactivated"`
+	ReadingsCount  int64                  `json:"readings_count"`
+	LastReadingAt  *time.Time             `json:"last_reading_at,omitempty"`
+	ActiveSessions int64                  `json:"active_sessions"`
+	Banned         bool                   `json:"banned"`
+	Tier           string                 `json:"tier"`
+	Subscriptions  []AdminSubscriptionRow `json:"subscriptions"`
+}
+
+// AdminSubscriptionRow is one of a user's subscriptions in the admin detail
+type AdminSubscriptionRow struct {
+	ID               string    `json:"id"`
+	Source           string    `json:"source"`
+	ProductID        string    `json:"product_id"`
+	Status           string    `json:"status"`
+	CurrentPeriodEnd time.Time `json:"current_period_end"`
+}
+
+// AdminUserFilter narrows the admin user list
+type AdminUserFilter struct {
+	Search      string // email or handle substring
+	InvalidOnly bool   // only addresses on the suppression list
+	Type        string // guest or registered
+	Subscribed  *bool
+}
+
+// GrantPremiumRequest gives a user complimentary premium for Days days
+type GrantPremiumRequest struct {
+	Days   int    `json:"days" validate:"required,min=1,max=365"`
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// AdminMessageRequest sends a user a templated message, optionally as the
+// answer to a ticket (support, report, moderation_case or appeal)
+type AdminMessageRequest struct {
+	Template   string `json:"template" validate:"max=64"`
+	Locale     string `json:"locale" validate:"omitempty,max=35"`
+	Message    string `json:"message" validate:"max=4000"`
+	TicketType string `json:"ticket_type" validate:"max=64"`
+	TicketID   string `json:"ticket_id" validate:"max=128"`
+}
+
+// LeaderboardNameOverride replaces a user's leaderboard name; an empty
+// display name assigns an anonymous one. Lock defaults to true
+type LeaderboardNameOverride struct {
+	DisplayName string `json:"display_name" validate:"max=64"`
+	Lock        *bool  `json:"lock"`
+	Reason      string `json:"reason" validate:"max=500"`
+}
+
+// DataMigrationStatus is one data migration on the admin dashboard
+type DataMigrationStatus struct {
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	Phase           string     `json:"phase"`
+	NextPhase       string     `json:"next_phase,omitempty"`
+	Total           int64      `json:"total"`
+	Backfilled      int64      `json:"backfilled"`
+	BackfillPercent float64    `json:"backfill_percent"`
+	BackfilledAt    *time.Time `json:"backfilled_at,omitempty"`
+	Verified        int64      `json:"verified"`
+	VerifyPercent   float64    `json:"verify_percent"`
+	Mismatches      int64      `json:"mismatches"`
+	VerifiedAt      *time.Time `json:"verified_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// DataMigrationPhaseRequest moves a data migration to another phase
+type DataMigrationPhaseRequest struct {
+	Phase  string `json:"phase" validate:"required,oneof=pending double_write backfill verify cutover complete"`
+	Reason string `json:"reason" validate:"max=500"`
+}
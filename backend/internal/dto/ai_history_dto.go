@@ -0,0 +1,11 @@
+package dto
+
+import "github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+
+// AIHistoryResponse lists the AI calls made on the user's behalf
+type AIHistoryResponse struct {
+	Data       []models.AIInteraction `json:"data"`
+	Page       int                    `json:"page"`
+	PageSize   int                    `json:"page_size"`
+	TotalCount int64                  `json:"total_count"`
+}
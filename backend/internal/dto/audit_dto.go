@@ -0,0 +1,26 @@
+package dto
+
+import "github.com/google/uuid"
+
+// AuditEntry is an audit record from outside the services, such as the
+// admin request trail written by middleware.AuditAdminRequests
+type AuditEntry struct {
+	ActorID    uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   string
+	IP         string
+	Details    map[string]any
+}
+
+// AuditLogFilter narrows GET /admin/audit-logs. Action matches as a prefix
+// ("appeal." finds every appeal decision); Since and Until are RFC 3339.
+type AuditLogFilter struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	IP         string
+	Since      string
+	Until      string
+}
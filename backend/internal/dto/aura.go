@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,25 +9,87 @@ import (
 
 // CreateAuraRequest defines the request body for aura scan
 type CreateAuraRequest struct {
-	ImageURL  string `json:"image_url"`
-	ImageData string `json:"image_data"`
+	ImageURL  string `json:"image_url" validate:"required_without=ImageData,omitempty,http_url,max=2048"`
+	ImageData string `json:"image_data" validate:"max=3145728"`
+	// Async returns a pending reading with an instant provisional color
+	// immediately and finishes the AI analysis in the background
+	Async bool `json:"async"`
+	// Locale is the language for the reading text; empty uses the
+	// Accept-Language header
+	Locale string `json:"locale" validate:"omitempty,max=35"`
+}
+
+// ApplyDefaults treats a blank locale as omitted, so the Accept-Language
+// fallback applies.
+func (r *CreateAuraRequest) ApplyDefaults() {
+	r.Locale = strings.TrimSpace(r.Locale)
+}
+
+// CreateGroupAuraRequest defines the request body for a group aura scan.
+// PeopleCount is an optional hint used only when face detection is
+// unavailable, so a group reading can still be produced.
+type CreateGroupAuraRequest struct {
+	ImageURL    string `json:"image_url" validate:"required_without=ImageData,omitempty,http_url,max=2048"`
+	ImageData   string `json:"image_data" validate:"max=3145728"`
+	PeopleCount int    `json:"people_count" validate:"omitempty,min=2,max=8"`
+}
+
+// CreateScanBatchRequest imports up to 10 photos as async scans. The whole
+// body shares the server's 4MB limit, so send image URLs or downscaled
+// image data.
+type CreateScanBatchRequest struct {
+	Images []ScanBatchImage `json:"images" validate:"required,min=1,max=10,dive"`
+	// Locale is the language for the readings' text; empty uses the
+	// Accept-Language header
+	Locale string `json:"locale" validate:"omitempty,max=35"`
+}
+
+// ScanBatchImage is one photo of a batch, sent like a single scan's.
+type ScanBatchImage struct {
+	ImageURL  string `json:"image_url" validate:"required_without=ImageData,omitempty,http_url,max=2048"`
+	ImageData string `json:"image_data" validate:"max=3145728"`
+}
+
+// ApplyDefaults treats a blank locale as omitted, so the Accept-Language
+// fallback applies.
+func (r *CreateScanBatchRequest) ApplyDefaults() {
+	r.Locale = strings.TrimSpace(r.Locale)
+}
+
+// ScanJobPosition reports where an async scan's analysis stands. The job ID
+// is the pending reading's ID. Position is the place in line while queued
+// (1 = next) and 0 once processing or done.
+type ScanJobPosition struct {
+	JobID                string `json:"job_id"`
+	Status               string `json:"status"` // queued, processing or done
+	Position             int    `json:"position"`
+	QueueLength          int    `json:"queue_length"`
+	EstimatedWaitSeconds int    `json:"estimated_wait_seconds"`
 }
 
 // AuraReadingResponse defines the response for an aura reading
 type AuraReadingResponse struct {
-	ID             uuid.UUID  `json:"id"`
-	UserID         uuid.UUID  `json:"user_id"`
-	AuraColor      string     `json:"aura_color"`
-	SecondaryColor *string    `json:"secondary_color,omitempty"`
-	EnergyLevel    int        `json:"energy_level"`
-	MoodScore      int        `json:"mood_score"`
-	Personality    string     `json:"personality"`
-	Strengths      []string   `json:"strengths"`
-	Challenges     []string   `json:"challenges"`
-	DailyAdvice    string     `json:"daily_advice"`
-	ImageURL       string     `json:"image_url"`
-	AnalyzedAt     time.Time  `json:"analyzed_at"`
-	CreatedAt      time.Time  `json:"created_at"`
+	ID             uuid.UUID         `json:"id"`
+	UserID         uuid.UUID         `json:"user_id"`
+	AuraColor      string            `json:"aura_color"`
+	SecondaryColor *string           `json:"secondary_color,omitempty"`
+	EnergyLevel    int               `json:"energy_level"`
+	MoodScore      int               `json:"mood_score"`
+	Personality    string            `json:"personality"`
+	Strengths      []string          `json:"strengths"`
+	Challenges     []string          `json:"challenges"`
+	DailyAdvice    string            `json:"daily_advice"`
+	Provenance     map[string]string `json:"provenance,omitempty"`
+	ImageURL       string            `json:"image_url"`
+	Status         string            `json:"status"`
+	Language       string            `json:"language"`
+	Note           string            `json:"note,omitempty"`
+	MoodTags       []string          `json:"mood_tags,omitempty"`
+	NotedAt        *time.Time        `json:"noted_at,omitempty"`
+	Pinned         bool              `json:"pinned"`
+	PinnedAt       *time.Time        `json:"pinned_at,omitempty"`
+	AnalyzedAt     time.Time         `json:"analyzed_at"`
+	CreatedAt      time.Time         `json:"created_at"`
 }
 
 // AuraListResponse defines the paginated list of aura readings
@@ -37,17 +100,204 @@ type AuraListResponse struct {
 	TotalCount int64                 `json:"total_count"`
 }
 
+// AuraCursorResponse is a page of readings in cursor mode. NextCursor is
+// empty on the last page.
+type AuraCursorResponse struct {
+	Data       []AuraReadingResponse `json:"data"`
+	PageSize   int                   `json:"page_size"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+// AuraListFilter narrows and orders GET /aura. From and To are dates
+// (2026-01-31, To inclusive) or RFC 3339 timestamps. Sort is date, energy or
+// mood and Order asc or desc; without a sort, pinned readings come first.
+type AuraListFilter struct {
+	Color      string
+	From       string
+	To         string
+	MinEnergy  string
+	Sort       string
+	Order      string
+	PinnedOnly bool
+	// Trait is a trait slug or name; only readings naming it match
+	Trait string
+}
+
 // AuraStatsResponse defines the aggregated stats for aura readings
 type AuraStatsResponse struct {
 	ColorDistribution map[string]int `json:"color_distribution"`
 	TotalReadings     int64          `json:"total_readings"`
 	AverageEnergy     float64        `json:"average_energy"`
 	AverageMood       float64        `json:"average_mood"`
+	DominantColor     string         `json:"dominant_color,omitempty"`
+	LastScanAt        *time.Time     `json:"last_scan_at,omitempty"`
 }
 
 // ScanEligibilityResponse defines the response structure for scan eligibility checks
 type ScanEligibilityResponse struct {
-	CanScan      bool `json:"canScan"`
-	Remaining    int  `json:"remaining"`
-	IsSubscribed bool `json:"isSubscribed"`
+	CanScan      bool   `json:"canScan"`
+	Remaining    int    `json:"remaining"`
+	IsSubscribed bool   `json:"isSubscribed"`
+	Tier         string `json:"tier"`
+}
+
+// AdminAnalyzeRequest runs the analysis pipeline without storing a reading.
+// Deterministic forces temperature 0 and a stable seed for replay comparisons.
+type AdminAnalyzeRequest struct {
+	ImageURL      string   `json:"image_url" validate:"required,http_url,max=2048"`
+	UserID        string   `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	Temperature   *float64 `json:"temperature,omitempty" validate:"omitempty,min=0,max=2"`
+	Seed          *int64   `json:"seed,omitempty"`
+	Deterministic bool     `json:"deterministic"`
+}
+
+// ReadingCorrectionRequest sets new values for a reading's fields; omitted
+// fields are left as they are. SecondaryColor "" clears it. Reason is required
+// and kept with the correction history.
+type ReadingCorrectionRequest struct {
+	AuraColor      *string  `json:"aura_color" validate:"omitempty,max=32"`
+	SecondaryColor *string  `json:"secondary_color" validate:"omitempty,max=32"`
+	EnergyLevel    *int     `json:"energy_level" validate:"omitempty,min=1,max=100"`
+	MoodScore      *int     `json:"mood_score" validate:"omitempty,min=1,max=10"`
+	Personality    *string  `json:"personality"`
+	Strengths      []string `json:"strengths"`
+	Challenges     []string `json:"challenges"`
+	DailyAdvice    *string  `json:"daily_advice"`
+	Reason         string   `json:"reason" validate:"required,max=500"`
+}
+
+// AuraAnalysisPreview is the unsaved result of an admin analysis run
+type AuraAnalysisPreview struct {
+	AuraColor         string            `json:"aura_color"`
+	SecondaryColor    *string           `json:"secondary_color,omitempty"`
+	EnergyLevel       int               `json:"energy_level"`
+	MoodScore         int               `json:"mood_score"`
+	Personality       string            `json:"personality"`
+	Strengths         []string          `json:"strengths"`
+	Challenges        []string          `json:"challenges"`
+	DailyAdvice       string            `json:"daily_advice"`
+	Provenance        map[string]string `json:"provenance"`
+	Deterministic     bool              `json:"deterministic"`
+	Source            string            `json:"source"`
+	ProviderLatencyMs *int              `json:"provider_latency_ms,omitempty"`
+	AIError           string            `json:"ai_error,omitempty"`
+}
+
+// ReadingTheme blends the aura color with the photo's palette for share cards
+// and in-app backgrounds. Background is a gradient, first stop on top.
+type ReadingTheme struct {
+	AuraColor  string   `json:"aura_color"`
+	Accent     string   `json:"accent"`
+	Palette    []string `json:"palette"`
+	Background []string `json:"background"`
+	TextColor  string   `json:"text_color"`
+}
+
+// PromptVariantStats compares engagement between prompt variants
+type PromptVariantStats struct {
+	Variant        string  `json:"variant"`
+	Readings       int64   `json:"readings"`
+	SharedReadings int64   `json:"shared_readings"`
+	ShareRate      float64 `json:"share_rate"`
+	// Ratings are post-scan self-ratings (1-5 stars), the primary quality signal
+	Ratings       int64    `json:"ratings"`
+	AverageRating *float64 `json:"average_rating,omitempty"`
+	// Leading marks the best-rated variant once every variant has enough ratings to compare
+	Leading bool `json:"leading"`
+	// Accuracy comes from in-app accuracy survey responses about the variant's readings
+	AccuracyResponses int64    `json:"accuracy_responses"`
+	AverageAccuracy   *float64 `json:"average_accuracy,omitempty"`
+}
+
+// PromptTemplateRequest saves the next version of a prompt template. System
+// and User are text/template sources; Provider is empty for every provider.
+type PromptTemplateRequest struct {
+	Name     string `json:"name" validate:"required,max=50"`
+	Provider string `json:"provider" validate:"max=20"`
+	System   string `json:"system" validate:"required,max=8000"`
+	User     string `json:"user" validate:"required,max=16000"`
+	Notes    string `json:"notes" validate:"max=2000"`
+	Activate bool   `json:"activate"`
+}
+
+// PromptTemplateStats compares the readings produced by one prompt template
+// version. Version 0, with no template ID, is the built-in prompt
+type PromptTemplateStats struct {
+	TemplateID     *uuid.UUID `json:"template_id,omitempty"`
+	Name           string     `json:"name"`
+	Provider       string     `json:"provider,omitempty"`
+	Version        int        `json:"version"`
+	Active         bool       `json:"active"`
+	Readings       int64      `json:"readings"`
+	SharedReadings int64      `json:"shared_readings"`
+	ShareRate      float64    `json:"share_rate"`
+	Ratings        int64      `json:"ratings"`
+	AverageRating  *float64   `json:"average_rating,omitempty"`
+}
+
+// ReadingNoteRequest sets the private journal note and mood tags of a
+// reading; an empty note with no tags clears them
+type ReadingNoteRequest struct {
+	Note     string   `json:"note" validate:"max=2000"`
+	MoodTags []string `json:"mood_tags" validate:"max=5,dive,max=32"`
+}
+
+// DeleteReadingsRequest lists readings to delete in one request
+type DeleteReadingsRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100,dive,uuid"`
+}
+
+// ClearHistoryRequest confirms deleting every reading with the token from a
+// first, unconfirmed DELETE /aura/all
+type ClearHistoryRequest struct {
+	ConfirmationToken string `json:"confirmation_token" validate:"max=256"`
+}
+
+// ClearHistoryConfirmation is returned when DELETE /aura/all is called
+// without a token; repeat the call with the token before it expires
+type ClearHistoryConfirmation struct {
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	Readings          int64     `json:"readings"`
+}
+
+// DeleteReadingsResponse reports how many readings were deleted
+type DeleteReadingsResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// RateReadingRequest is the post-scan accuracy self-rating
+type RateReadingRequest struct {
+	Stars int      `json:"stars" validate:"required,min=1,max=5"`
+	Tags  []string `json:"tags" validate:"max=4,dive,max=32"`
+}
+
+// ReadingRatingStats aggregates self-ratings for one prompt variant and provider
+type ReadingRatingStats struct {
+	Variant       string           `json:"variant"`
+	Provider      string           `json:"provider"`
+	Ratings       int64            `json:"ratings"`
+	AverageRating float64          `json:"average_rating"`
+	Distribution  map[int]int64    `json:"distribution"`
+	Tags          map[string]int64 `json:"tags"`
+}
+
+// TraitStat is one recurring strength or challenge of a user's history
+type TraitStat struct {
+	ID   uuid.UUID `json:"id"`
+	Slug string    `json:"slug"`
+	// Name is localized for the request's language
+	Name       string    `json:"name"`
+	Readings   int64     `json:"readings"`
+	Share      float64   `json:"share"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// TraitStatsResponse is GET /aura/traits: the user's strengths and
+// challenges, most recurring first. Share is the fraction of the user's
+// readings that named the trait.
+type TraitStatsResponse struct {
+	TotalReadings int64       `json:"total_readings"`
+	Strengths     []TraitStat `json:"strengths"`
+	Challenges    []TraitStat `json:"challenges"`
 }
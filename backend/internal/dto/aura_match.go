@@ -20,7 +20,44 @@ type AuraMatchResponse struct {
 	Synergy            string    `json:"synergy"`
 	Tension            string    `json:"tension"`
 	Advice             string    `json:"advice"`
-	UserAuraColor      string    `json:"user_aura_color"`
-	FriendAuraColor    string    `json:"friend_aura_color"`
-	CreatedAt          time.Time `json:"created_at"`
+	Blurb              string    `json:"blurb,omitempty"`
+	// Breakdown is omitted for matches scored before the engine existed
+	Breakdown       *CompatibilityBreakdown `json:"breakdown,omitempty"`
+	UserAuraColor   string                  `json:"user_aura_color"`
+	FriendAuraColor string                  `json:"friend_aura_color"`
+	// Narrative is only returned to premium users
+	Narrative            string     `json:"narrative,omitempty"`
+	NarrativeGeneratedAt *time.Time `json:"narrative_generated_at,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
+
+// CompatibilityBreakdown holds the weighted components of a compatibility score
+type CompatibilityBreakdown struct {
+	ColorAffinity   int `json:"color_affinity"`
+	EnergyAlignment int `json:"energy_alignment"`
+	MoodCorrelation int `json:"mood_correlation"`
+}
+
+// ColorHarmony is one aura color and how it relates to the user's color
+type ColorHarmony struct {
+	Color    string `json:"color"`
+	Relation string `json:"relation"`
+}
+
+// FriendColorMatch is the friend whose current aura pairs best with the user's
+type FriendColorMatch struct {
+	ID           string `json:"id"`
+	Handle       string `json:"handle,omitempty"`
+	AuraColor    string `json:"aura_color"`
+	Relation     string `json:"relation"`
+	ScannedToday bool   `json:"scanned_today"`
+}
+
+// CompatibilityTodayResponse is returned by GET /api/aura/compatibility/today
+type CompatibilityTodayResponse struct {
+	Date         string            `json:"date"`
+	CurrentColor string            `json:"current_color"`
+	Harmonious   []ColorHarmony    `json:"harmonious"`
+	Challenging  []string          `json:"challenging"`
+	BestFriend   *FriendColorMatch `json:"best_friend,omitempty"`
 }
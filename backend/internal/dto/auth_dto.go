@@ -1,28 +1,86 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
 
+	"github.com/google/uuid"
+)
+
+// RegisterRequest has no password length tag: the shipped apps expect the
+// service's 400 for a short password, not a 422.
 type RegisterRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Password string `json:"password" validate:"required,max=72"`
+	Locale   string `json:"locale" validate:"omitempty,max=35"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+// RestoreAccountRequest reactivates an account pending deletion. Password
+// accounts send email and password, Apple accounts an identity token.
+type RestoreAccountRequest struct {
+	Email         string `json:"email,omitempty" validate:"required_without=IdentityToken,omitempty,email,max=254"`
+	Password      string `json:"password,omitempty" validate:"required_without=IdentityToken,omitempty,max=72"`
+	IdentityToken string `json:"identity_token,omitempty" validate:"omitempty,max=8192"`
 }
 
 type ClaimGuestRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email,max=254"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	Locale   string `json:"locale" validate:"omitempty,max=35"`
+}
+
+type ForgotPasswordRequest struct {
+	Email  string `json:"email" validate:"required,email,max=254"`
+	Locale string `json:"locale" validate:"omitempty,max=35"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required,max=512"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type ChangeEmailRequest struct {
+	NewEmail        string `json:"new_email" validate:"required,email,max=254"`
+	CurrentPassword string `json:"current_password" validate:"max=72"`
+	Locale          string `json:"locale" validate:"omitempty,max=35"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required,max=512"`
+}
+
+type ResendVerificationRequest struct {
+	Locale string `json:"locale" validate:"omitempty,max=35"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required,max=512"`
+}
+
+type UpdateTimezoneRequest struct {
+	Timezone string `json:"timezone" validate:"required,max=64"`
 }
 
 type RefreshRequest struct {
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token" validate:"required,max=512"`
 }
 
 type LogoutRequest struct {
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token" validate:"max=512"`
+}
+
+// DeviceInfo describes the client a session was issued to; handlers fill
+// it from X-Device-Name, X-Platform, X-App-Version, User-Agent and the IP
+type DeviceInfo struct {
+	Name       string
+	Platform   string
+	AppVersion string
+	UserAgent  string
+	IP         string
 }
 
 type AuthResponse struct {
@@ -32,13 +90,58 @@ type AuthResponse struct {
 }
 
 type UserResponse struct {
-	ID    uuid.UUID `json:"id"`
-	Email string    `json:"email"`
+	ID            uuid.UUID `json:"id"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+}
+
+// ProfileResponse is GET /auth/profile. Its keys are camelCase, unlike the
+// rest of the API, because the apps shipped reading them that way.
+type ProfileResponse struct {
+	ID                  string     `json:"id"`
+	Email               string     `json:"email"`
+	Handle              *string    `json:"handle"`
+	Discoverable        bool       `json:"discoverable"`
+	ContactDiscoverable bool       `json:"contactDiscoverable"`
+	PendingEmail        string     `json:"pendingEmail"`
+	EmailVerified       bool       `json:"emailVerified"`
+	Timezone            string     `json:"timezone"`
+	SubscriptionStatus  string     `json:"subscriptionStatus"`
+	CurrentStreak       int        `json:"currentStreak"`
+	TotalReadings       int64      `json:"totalReadings"`
+	DominantColor       string     `json:"dominantColor,omitempty"`
+	LastScanAt          *time.Time `json:"lastScanAt,omitempty"`
 }
 
 type ErrorResponse struct {
 	Error   bool   `json:"error"`
 	Message string `json:"message"`
+	// Code is a stable reason clients can branch on, set for errors that
+	// need a specific prompt.
+	Code string `json:"code,omitempty"`
+	// RequestID identifies the request for support; it is also returned in
+	// the X-Request-ID header.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ValidationErrorResponse is the 422 body for a request that parsed but
+// broke its DTO's validate tags. Code is always "validation_failed".
+type ValidationErrorResponse struct {
+	Error     bool         `json:"error"`
+	Message   string       `json:"message"`
+	Code      string       `json:"code"`
+	Fields    []FieldError `json:"fields"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// FieldError is one failed rule. Field is the JSON path ("ids[2]"), Code a
+// stable reason the apps map to a localized message, Param the rule's
+// limit or choices where it has one, and Message an English fallback.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
 }
 
 type HealthResponse struct {
@@ -46,3 +149,50 @@ type HealthResponse struct {
 	Timestamp string `json:"timestamp"`
 	DB        string `json:"db"`
 }
+
+// LivenessResponse answers /api/health/live without touching any
+// dependency.
+type LivenessResponse struct {
+	Status    string `json:"status"`
+	Timestamp string `json:"timestamp"`
+}
+
+// ReadinessResponse is /api/health/ready: "ok", "degraded" when an optional
+// dependency is down, "unavailable" when a critical one is, or "draining"
+// while the instance shuts down.
+type ReadinessResponse struct {
+	Status    string                     `json:"status"`
+	Timestamp string                     `json:"timestamp"`
+	Checks    map[string]DependencyCheck `json:"checks"`
+}
+
+// DependencyCheck is the outcome of probing one dependency.
+type DependencyCheck struct {
+	Status    string `json:"status"` // "ok" or "down"
+	LatencyMs int64  `json:"latency_ms"`
+	Critical  bool   `json:"critical"`
+}
+
+// VersionResponse is /api/version, the build serving the request.
+// APIVersion is the request and response shape version; clients more than
+// one behind are refused with 426.
+type VersionResponse struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	BuiltAt    string `json:"built_at,omitempty"`
+	GoVersion  string `json:"go_version"`
+	APIVersion int    `json:"api_version"`
+}
+
+// SessionResponse is one signed-in device (a refresh token family)
+type SessionResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	DeviceName string     `json:"device_name,omitempty"`
+	Platform   string     `json:"platform,omitempty"`
+	AppVersion string     `json:"app_version,omitempty"`
+	UserAgent  string     `json:"user_agent,omitempty"`
+	IPAddress  string     `json:"ip_address,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	SignedInAt time.Time  `json:"signed_in_at"`
+	Current    bool       `json:"current"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+type CheckoutSessionRequest struct {
+	PriceID string `json:"price_id,omitempty" validate:"max=255"` // defaults to the first STRIPE_PRICE_IDS entry
+}
+
+// BillingSessionResponse is a hosted Stripe page (checkout or customer
+// portal) the client should open.
+type BillingSessionResponse struct {
+	URL string `json:"url"`
+}
+
+// Entitlements are the features a plan tier unlocks. -1 means unlimited.
+type Entitlements struct {
+	Tier           string `json:"tier"` // free, plus, pro
+	DailyScans     int    `json:"daily_scans"`
+	GroupScans     bool   `json:"group_scans"`
+	BatchScans     bool   `json:"batch_scans"`
+	Forecast       bool   `json:"forecast"`
+	MatchesPerDay  int    `json:"matches_per_day"`
+	MatchNarrative bool   `json:"match_narrative"`
+}
+
+type EntitlementsResponse struct {
+	Entitlements
+	// Tiers lists every plan so the paywall can compare them.
+	Tiers []Entitlements `json:"tiers"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "encoding/json"
+
+// Defaulter is implemented by request bodies with defaulting rules: fields
+// an older client omits or spells differently are filled in or normalized
+// after decoding, before validation.
+type Defaulter interface {
+	ApplyDefaults()
+}
+
+// DecodeJSON is the app's JSON decoder (fiber.Config.JSONDecoder), used by
+// BodyParser. Unknown fields are ignored rather than rejected, so a client
+// one version ahead or behind can still talk to this server during a
+// rolling deploy; bodies implementing Defaulter then get their defaults.
+func DecodeJSON(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	if d, ok := v.(Defaulter); ok {
+		d.ApplyDefaults()
+	}
+	return nil
+}
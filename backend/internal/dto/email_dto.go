@@ -0,0 +1,71 @@
+package dto
+
+import "time"
+
+// EmailPreviewRequest renders a template with custom data instead of samples
+type EmailPreviewRequest struct {
+	Locale string         `json:"locale" validate:"omitempty,max=35"`
+	Data   map[string]any `json:"data"`
+}
+
+// EmailSendTestRequest sends a rendered template to a test address
+type EmailSendTestRequest struct {
+	Template string         `json:"template" validate:"required,max=64"`
+	Locale   string         `json:"locale" validate:"omitempty,max=35"`
+	To       string         `json:"to" validate:"required,email,max=254"`
+	Data     map[string]any `json:"data"`
+}
+
+type EmailPreviewResponse struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+// SNSMessage is the Amazon SNS envelope used to deliver SES feedback
+type SNSMessage struct {
+	Type         string `json:"Type"` // SubscriptionConfirmation, Notification, UnsubscribeConfirmation
+	MessageID    string `json:"MessageId"`
+	TopicArn     string `json:"TopicArn"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+// SESNotification is an SES bounce/complaint notification or event
+// (the SNS Message field, JSON-decoded)
+type SESNotification struct {
+	NotificationType string        `json:"notificationType"`
+	EventType        string        `json:"eventType"` // event publishing uses eventType instead
+	Bounce           *SESBounce    `json:"bounce"`
+	Complaint        *SESComplaint `json:"complaint"`
+}
+
+type SESBounce struct {
+	BounceType        string         `json:"bounceType"` // Permanent, Transient, Undetermined
+	BounceSubType     string         `json:"bounceSubType"`
+	BouncedRecipients []SESRecipient `json:"bouncedRecipients"`
+}
+
+type SESComplaint struct {
+	ComplaintFeedbackType string         `json:"complaintFeedbackType"`
+	ComplainedRecipients  []SESRecipient `json:"complainedRecipients"`
+}
+
+type SESRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	DiagnosticCode string `json:"diagnosticCode"`
+}
+
+// AdminUserResponse is a row in the admin user view
+type AdminUserResponse struct {
+	ID                string     `json:"id"`
+	Email             string     `json:"email"`
+	Handle            string     `json:"handle,omitempty"`
+	IsGuest           bool       `json:"is_guest"`
+	Subscribed        bool       `json:"subscribed"`
+	Timezone          string     `json:"timezone"`
+	CreatedAt         time.Time  `json:"created_at"`
+	EmailInvalid      bool       `json:"email_invalid"`
+	EmailStatusReason string     `json:"email_status_reason,omitempty"`
+	EmailFlaggedAt    *time.Time `json:"email_flagged_at,omitempty"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+
+// ExperimentRequest defines or edits a draft experiment
+type ExperimentRequest struct {
+	Key         string                     `json:"key" validate:"required,max=50"`
+	Description string                     `json:"description" validate:"max=2000"`
+	Variants    []models.ExperimentVariant `json:"variants" validate:"required,min=2,max=10"`
+}
+
+// ExperimentResults breaks an experiment's readings down by variant
+type ExperimentResults struct {
+	Experiment models.Experiment        `json:"experiment"`
+	Variants   []ExperimentVariantStats `json:"variants"`
+}
+
+// ExperimentVariantStats is the engagement and conversion of the users
+// assigned to one variant. Users are counted from their first reading in
+// the experiment; a conversion is a subscription started after it.
+type ExperimentVariantStats struct {
+	Variant         string   `json:"variant"`
+	Weight          int      `json:"weight"`
+	Users           int64    `json:"users"`
+	Readings        int64    `json:"readings"`
+	ReadingsPerUser float64  `json:"readings_per_user"`
+	ReturningUsers  int64    `json:"returning_users"`
+	ReturnRate      float64  `json:"return_rate"`
+	SharedReadings  int64    `json:"shared_readings"`
+	ShareRate       float64  `json:"share_rate"`
+	Ratings         int64    `json:"ratings"`
+	AverageRating   *float64 `json:"average_rating,omitempty"`
+	Conversions     int64    `json:"conversions"`
+	ConversionRate  float64  `json:"conversion_rate"`
+}
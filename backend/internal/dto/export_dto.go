@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+// DataExportResponse describes the status of a data export job
+type DataExportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Format      string     `json:"format"`
+	SizeBytes   int64      `json:"size_bytes,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// UserDataExport is the data.json document inside an export
+type UserDataExport struct {
+	ExportedAt   time.Time                        `json:"exported_at"`
+	Profile      ExportProfile                    `json:"profile"`
+	AuraReadings []models.AuraReading             `json:"aura_readings"`
+	AuraMatches  []models.AuraMatch               `json:"aura_matches"`
+	Streak       *models.AuraStreak               `json:"streak,omitempty"`
+	ScanDays     []string                         `json:"scan_days"`
+	Memories     []models.UserMemory              `json:"ai_memories"`
+	AIHistory    []models.AIInteraction           `json:"ai_history"`
+	Friends      []FriendResponse                 `json:"friends"`
+	Notification *NotificationPreferencesResponse `json:"notification_preferences,omitempty"`
+	Images       []ExportImage                    `json:"images,omitempty"`
+}
+
+// ExportProfile is the account section of a data export
+type ExportProfile struct {
+	ID                     string     `json:"id"`
+	Email                  string     `json:"email"`
+	Handle                 string     `json:"handle,omitempty"`
+	Timezone               string     `json:"timezone"`
+	EmailVerifiedAt        *time.Time `json:"email_verified_at,omitempty"`
+	AIMemoryEnabled        bool       `json:"ai_memory_enabled"`
+	ResearchConsent        bool       `json:"research_consent"`
+	DiscoverableByHandle   bool       `json:"discoverable_by_handle"`
+	DiscoverableByContacts bool       `json:"discoverable_by_contacts"`
+	LeaderboardOptIn       bool       `json:"leaderboard_opt_in"`
+	LeaderboardName        string     `json:"leaderboard_name,omitempty"`
+	SimilarDiscoveryOptIn  bool       `json:"similar_discovery_opt_in"`
+	BirthYear              *int       `json:"birth_year,omitempty"`
+	SignInWithApple        bool       `json:"sign_in_with_apple"`
+	CreatedAt              time.Time  `json:"created_at"`
+}
+
+// ExportImage records where a reading's photo was placed in a ZIP export
+type ExportImage struct {
+	ReadingID string `json:"reading_id"`
+	SourceURL string `json:"source_url"`
+	File      string `json:"file,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+type CreateInviteCodeRequest struct {
+	MaxUses    int `json:"max_uses" validate:"gte=0"`
+	TTLMinutes int `json:"ttl_minutes" validate:"gte=0"`
+}
+
+type InviteCodeResponse struct {
+	Code      string    `json:"code"`
+	DeepLink  string    `json:"deep_link"`
+	QRURL     string    `json:"qr_url"`
+	MaxUses   int       `json:"max_uses"`
+	UseCount  int       `json:"use_count"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type FriendResponse struct {
+	ID           string    `json:"id"`
+	Handle       string    `json:"handle,omitempty"`
+	FriendsSince time.Time `json:"friends_since"`
+}
+
+// FriendInviteRequest invites by exactly one of: someone's invite code, an
+// email address, or a user ID (e.g. from handle search or contact matches).
+type FriendInviteRequest struct {
+	Code   string `json:"code" validate:"max=32"`
+	Email  string `json:"email" validate:"omitempty,email,max=254"`
+	UserID string `json:"user_id" validate:"max=64"`
+}
+
+// FriendInviteResponse reports the outcome: "friends" when the friendship now
+// exists, "requested" when a request awaits the other user, or "sent" for
+// email invites (which never reveal whether the address has an account).
+type FriendInviteResponse struct {
+	Status string          `json:"status"`
+	Friend *FriendResponse `json:"friend,omitempty"`
+}
+
+type FriendRequestResponse struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Handle    string    `json:"handle,omitempty"`
+	Direction string    `json:"direction"` // incoming, outgoing
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FriendRequestsResponse struct {
+	Incoming []FriendRequestResponse `json:"incoming"`
+	Outgoing []FriendRequestResponse `json:"outgoing"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+// RecurringThemesResponse lists the themes that recur across a user's
+// readings and what they were computed from.
+type RecurringThemesResponse struct {
+	Themes []models.RecurringTheme `json:"themes"`
+	// Readings is how many readings the user had at the analysis; Method is
+	// "embeddings" or "keywords".
+	Readings   int64     `json:"readings"`
+	Method     string    `json:"method"`
+	AnalyzedAt time.Time `json:"analyzed_at"`
+}
+
+// SimilarReading is one of the user's readings close in meaning to another.
+type SimilarReading struct {
+	Reading models.AuraReading `json:"reading"`
+	// Similarity is the cosine similarity of the readings' texts, up to 1.
+	Similarity float64 `json:"similarity"`
+	// NearDuplicate marks a reading that says practically the same thing.
+	NearDuplicate bool `json:"near_duplicate"`
+}
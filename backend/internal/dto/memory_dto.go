@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+type MemoryRequest struct {
+	Category string `json:"category" validate:"required,max=32"` // "job", "goals", "interests", "relationships", "wellbeing", "other"
+	Content  string `json:"content" validate:"required,max=280"`
+}
+
+type MemorySettingsRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type MemoryListResponse struct {
+	Enabled bool                `json:"enabled"`
+	Data    []models.UserMemory `json:"data"`
+}
+
+// MemoryExport is the downloadable copy of everything stored for personalization
+type MemoryExport struct {
+	UserID     string              `json:"user_id"`
+	Enabled    bool                `json:"enabled"`
+	ExportedAt time.Time           `json:"exported_at"`
+	Memories   []models.UserMemory `json:"memories"`
+}
@@ -5,9 +5,9 @@ import "github.com/google/uuid"
 // --- Report DTOs ---
 
 type CreateReportRequest struct {
-	ContentType string `json:"content_type"` // "user", "post", "comment"
-	ContentID   string `json:"content_id"`
-	Reason      string `json:"reason"`
+	ContentType string `json:"content_type" validate:"required,oneof=user post comment"` // "user", "post", "comment"
+	ContentID   string `json:"content_id" validate:"required,max=128"`
+	Reason      string `json:"reason" validate:"required,max=2000"`
 }
 
 type ReportResponse struct {
@@ -22,14 +22,60 @@ type ReportResponse struct {
 }
 
 type ActionReportRequest struct {
-	Status    string `json:"status"`     // "reviewed", "actioned", "dismissed"
-	AdminNote string `json:"admin_note"`
+	Status    string `json:"status" validate:"required,oneof=reviewed actioned dismissed"` // "reviewed", "actioned", "dismissed"
+	AdminNote string `json:"admin_note" validate:"max=2000"`
+}
+
+// BulkCaseRequest resolves or escalates several moderation cases at once.
+// Status is only used when resolving: "actioned" or "dismissed".
+type BulkCaseRequest struct {
+	CaseIDs   []uuid.UUID `json:"case_ids" validate:"required,min=1,max=100"`
+	Status    string      `json:"status,omitempty" validate:"omitempty,oneof=actioned dismissed"`
+	AdminNote string      `json:"admin_note" validate:"max=2000"`
+}
+
+type BulkCaseResponse struct {
+	Updated int64       `json:"updated"`
+	Skipped []uuid.UUID `json:"skipped"`
+}
+
+// --- Moderation action and appeal DTOs ---
+
+type ModerationActionRequest struct {
+	Type     string     `json:"type" validate:"required,oneof=ban suspend shadow_ban"` // "ban", "suspend", "shadow_ban"
+	Reason   string     `json:"reason" validate:"required,max=2000"`
+	Duration string     `json:"duration,omitempty" validate:"max=32"` // e.g. "7d", "2w" or "72h", at most 365 days; required for suspend
+	CaseID   *uuid.UUID `json:"case_id,omitempty"`
+}
+
+type RevokeActionRequest struct {
+	Reason string `json:"reason" validate:"max=2000"`
+}
+
+type CreateAppealRequest struct {
+	ActionID uuid.UUID `json:"action_id" validate:"required"`
+	Message  string    `json:"message" validate:"required,max=2000"`
+}
+
+type ReviewAppealRequest struct {
+	Decision  string `json:"decision" validate:"required,oneof=approve reject"` // "approve", "reject"
+	AdminNote string `json:"admin_note" validate:"max=2000"`
+}
+
+// AppealSLAStats summarizes review times against APPEAL_SLA. The reviewed
+// figures cover appeals decided in the last 30 days.
+type AppealSLAStats struct {
+	Pending            int64   `json:"pending"`
+	Overdue            int64   `json:"overdue"`
+	Reviewed           int64   `json:"reviewed"`
+	ReviewedInSLA      int64   `json:"reviewed_in_sla"`
+	AverageReviewHours float64 `json:"average_review_hours"`
 }
 
 // --- Block DTOs ---
 
 type BlockUserRequest struct {
-	BlockedID uuid.UUID `json:"blocked_id"`
+	BlockedID uuid.UUID `json:"blocked_id" validate:"required"`
 }
 
 // --- Account Deletion DTOs ---
@@ -41,8 +87,8 @@ type DeleteAccountRequest struct {
 // --- Apple Sign-In DTOs ---
 
 type AppleSignInRequest struct {
-	IdentityToken string `json:"identity_token"` // JWT from Apple
-	AuthCode      string `json:"authorization_code"`
-	FullName      string `json:"full_name,omitempty"`
-	Email         string `json:"email,omitempty"` // Only sent on first sign-in
+	IdentityToken string `json:"identity_token" validate:"required,max=8192"` // JWT from Apple
+	AuthCode      string `json:"authorization_code" validate:"max=1024"`
+	FullName      string `json:"full_name,omitempty" validate:"max=100"`
+	Email         string `json:"email,omitempty" validate:"omitempty,max=254"` // Only sent on first sign-in
 }
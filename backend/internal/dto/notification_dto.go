@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+// NotificationPreferencesRequest updates any subset of the channel × category
+// matrix, e.g. {"preferences": {"email": {"marketing": true}}}.
+type NotificationPreferencesRequest struct {
+	Preferences map[string]map[string]bool `json:"preferences"`
+	QuietHours  *QuietHoursSettings        `json:"quiet_hours,omitempty"`
+	// SocialDelivery is "immediate" or "digest" (batched within a rolling window)
+	SocialDelivery string `json:"social_delivery,omitempty" validate:"omitempty,oneof=immediate digest"`
+}
+
+// ApplyDefaults accepts social_delivery in any casing, so "Digest" from a
+// client that capitalizes its enums is not refused.
+func (r *NotificationPreferencesRequest) ApplyDefaults() {
+	r.SocialDelivery = strings.ToLower(strings.TrimSpace(r.SocialDelivery))
+}
+
+// QuietHoursSettings uses "HH:MM" in the user's local timezone; the window may
+// wrap past midnight (22:00-08:00).
+type QuietHoursSettings struct {
+	Enabled bool   `json:"enabled"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+// NotificationPreferencesResponse is the full, effective matrix
+type NotificationPreferencesResponse struct {
+	Preferences    map[string]map[string]bool `json:"preferences"`
+	QuietHours     QuietHoursSettings         `json:"quiet_hours"`
+	DailyCap       int                        `json:"daily_cap"`
+	SocialDelivery string                     `json:"social_delivery"`
+}
+
+type NotificationListResponse struct {
+	Data        []models.Notification `json:"data"`
+	UnreadCount int64                 `json:"unread_count"`
+}
+
+// RegisterDeviceRequest registers a push token. Platform is "ios" (APNs) or
+// "android" (FCM).
+type RegisterDeviceRequest struct {
+	Token      string `json:"token" validate:"required,max=4096"`
+	Platform   string `json:"platform" validate:"max=16"`
+	AppVersion string `json:"app_version" validate:"max=32"`
+}
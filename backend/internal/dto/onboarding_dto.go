@@ -0,0 +1,23 @@
+package dto
+
+// OnboardingStep is one server-defined onboarding screen
+type OnboardingStep struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Required  bool              `json:"required"`
+	Skippable bool              `json:"skippable"`
+	Version   int               `json:"version"`
+	Params    map[string]string `json:"params,omitempty"`
+	// Status is pending, completed or skipped for the requesting user
+	Status string `json:"status"`
+}
+
+// OnboardingResponse is the ordered onboarding flow for the current user
+type OnboardingResponse struct {
+	Version   int              `json:"version"`
+	Steps     []OnboardingStep `json:"steps"`
+	NextStep  string           `json:"next_step,omitempty"`
+	Completed bool             `json:"completed"`
+}
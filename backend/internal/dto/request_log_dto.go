@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// RequestLogEntry is a failed request as middleware.RecordFailedRequests
+// saw it
+type RequestLogEntry struct {
+	RequestID  string
+	Traced     bool
+	UserID     *uuid.UUID
+	Method     string
+	Route      string
+	TargetID   string
+	Status     int
+	Code       string
+	Error      string
+	LatencyMs  int64
+	Platform   string
+	AppVersion string
+}
+
+// RequestLookupResponse is what support sees for a request ID: the
+// recorded failure and, when the request was traced, a link to its spans
+type RequestLookupResponse struct {
+	RequestID string              `json:"request_id"`
+	TraceURL  string              `json:"trace_url,omitempty"`
+	Requests  []models.RequestLog `json:"requests"`
+}
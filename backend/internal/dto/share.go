@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// ShareLinkResponse is returned when a user creates a public link for a reading
+type ShareLinkResponse struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PublicReadingResponse is the sanitized reading served to anyone holding a share link.
+// It intentionally omits user ID, email, image URL and reading ID.
+type PublicReadingResponse struct {
+	AuraColor      string            `json:"aura_color"`
+	SecondaryColor *string           `json:"secondary_color,omitempty"`
+	EnergyLevel    int               `json:"energy_level"`
+	MoodScore      int               `json:"mood_score"`
+	Personality    string            `json:"personality"`
+	Strengths      []string          `json:"strengths"`
+	Challenges     []string          `json:"challenges"`
+	DailyAdvice    string            `json:"daily_advice"`
+	AnalyzedAt     time.Time         `json:"analyzed_at"`
+	Theme          ReadingTheme      `json:"theme"`
+	OpenGraph      map[string]string `json:"open_graph"`
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+// SurveyRequest creates or replaces an admin-defined survey
+type SurveyRequest struct {
+	Kind        string                  `json:"kind"`
+	Title       string                  `json:"title" validate:"required,max=120"`
+	Description string                  `json:"description" validate:"max=2000"`
+	Questions   []models.SurveyQuestion `json:"questions"`
+	Segment     models.SurveySegment    `json:"segment"`
+	Active      bool                    `json:"active"`
+	StartsAt    *time.Time              `json:"starts_at"`
+	EndsAt      *time.Time              `json:"ends_at"`
+}
+
+// PendingSurvey is a survey the user should be shown. ReadingID is set for
+// accuracy surveys and names the reading being rated.
+type PendingSurvey struct {
+	ID          string                  `json:"id"`
+	Kind        string                  `json:"kind"`
+	Title       string                  `json:"title"`
+	Description string                  `json:"description,omitempty"`
+	Questions   []models.SurveyQuestion `json:"questions"`
+	ReadingID   string                  `json:"reading_id,omitempty"`
+}
+
+// SubmitSurveyRequest carries answers keyed by question ID
+type SubmitSurveyRequest struct {
+	ReadingID string                     `json:"reading_id" validate:"omitempty,uuid"`
+	Answers   map[string]json.RawMessage `json:"answers"`
+}
+
+// SurveyQuestionReport aggregates the answers to one question
+type SurveyQuestionReport struct {
+	ID           string           `json:"id"`
+	Type         string           `json:"type"`
+	Answers      int64            `json:"answers"`
+	Average      *float64         `json:"average,omitempty"`
+	NPS          *float64         `json:"nps,omitempty"`
+	Distribution map[string]int64 `json:"distribution,omitempty"`
+}
+
+// SurveyVariantScore is the mean score of responses about one prompt variant
+type SurveyVariantScore struct {
+	Variant      string  `json:"variant"`
+	Responses    int64   `json:"responses"`
+	AverageScore float64 `json:"average_score"`
+}
+
+// SurveyReport is the admin aggregate view of a survey
+type SurveyReport struct {
+	SurveyID        string                 `json:"survey_id"`
+	Kind            string                 `json:"kind"`
+	Responses       int64                  `json:"responses"`
+	Questions       []SurveyQuestionReport `json:"questions"`
+	ByPromptVariant []SurveyVariantScore   `json:"by_prompt_variant,omitempty"`
+}
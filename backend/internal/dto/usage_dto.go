@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// UsageResponse summarizes what the user's plan allows and how much of it
+// they are using.
+type UsageResponse struct {
+	Plan    string       `json:"plan"` // free, premium
+	Storage StorageUsage `json:"storage"`
+}
+
+// StorageUsage reports stored scan photos. PhotoLimit is nil when the plan
+// keeps every original.
+type StorageUsage struct {
+	Bytes          int64 `json:"bytes"`
+	OriginalBytes  int64 `json:"original_bytes"`
+	ThumbnailBytes int64 `json:"thumbnail_bytes"`
+	Photos         int64 `json:"photos"`
+	PhotoLimit     *int  `json:"photo_limit"`
+	EvictedPhotos  int64 `json:"evicted_photos"`
+}
+
+// PhotoRestoreResponse reports a restore of an original from cold storage:
+// available once it can be viewed again, restoring while it is on its way.
+type PhotoRestoreResponse struct {
+	Status      string     `json:"status"`
+	RequestedAt *time.Time `json:"requested_at,omitempty"`
+}
@@ -0,0 +1,97 @@
+package dto
+
+import "time"
+
+type UpdateHandleRequest struct {
+	Handle string `json:"handle" validate:"max=32"`
+}
+
+type HandleResponse struct {
+	Handle          string     `json:"handle"`
+	NextChangeAfter *time.Time `json:"next_change_after,omitempty"`
+}
+
+type DiscoverySettingsRequest struct {
+	DiscoverableByHandle   *bool `json:"discoverable_by_handle"`
+	DiscoverableByContacts *bool `json:"discoverable_by_contacts"`
+	// Phone (E.164) is hashed on receipt and only the hash is stored; "" removes it.
+	Phone *string `json:"phone" validate:"omitempty,max=32"`
+}
+
+type DiscoverySettingsResponse struct {
+	DiscoverableByHandle   bool `json:"discoverable_by_handle"`
+	DiscoverableByContacts bool `json:"discoverable_by_contacts"`
+	PhoneRegistered        bool `json:"phone_registered"`
+}
+
+// ResearchConsentRequest opts in or out of anonymized research datasets
+type ResearchConsentRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// ContactDiscoverRequest carries hex SHA-256 hashes of normalized contact
+// identifiers, salted as described by ContactDiscoverySaltResponse
+type ContactDiscoverRequest struct {
+	Hashes []string `json:"hashes" validate:"max=500,dive,max=128"`
+}
+
+type ContactDiscoverySaltResponse struct {
+	Salt      string `json:"salt"`
+	Algorithm string `json:"algorithm"`
+	Format    string `json:"format"`
+	MaxHashes int    `json:"max_hashes"`
+}
+
+// ContactMatch pairs a discovered user with the hash the client sent, so the
+// client can label the match from its local address book
+type ContactMatch struct {
+	ID          string `json:"id"`
+	Handle      string `json:"handle,omitempty"`
+	MatchedHash string `json:"matched_hash"`
+}
+
+// UserSearchResult is the public card shown in friend search
+type UserSearchResult struct {
+	ID     string `json:"id"`
+	Handle string `json:"handle"`
+}
+
+// LeaderboardSettingsRequest opts in or out of public leaderboards. An
+// empty display name uses an anonymous one.
+type LeaderboardSettingsRequest struct {
+	OptIn       *bool   `json:"opt_in"`
+	DisplayName *string `json:"display_name" validate:"omitempty,max=64"`
+}
+
+type LeaderboardSettingsResponse struct {
+	OptIn       bool   `json:"opt_in"`
+	DisplayName string `json:"display_name"`
+	Anonymous   bool   `json:"anonymous"`
+	// Locked is set while an admin-chosen name is in place.
+	Locked bool `json:"locked"`
+}
+
+// SimilarDiscoverySettingsRequest opts in or out of similar-aura discovery.
+// Opting in needs a birth year showing the user is an adult.
+type SimilarDiscoverySettingsRequest struct {
+	OptIn     *bool `json:"opt_in"`
+	BirthYear *int  `json:"birth_year" validate:"omitempty,min=1900,max=2100"`
+}
+
+type SimilarDiscoverySettingsResponse struct {
+	OptIn     bool `json:"opt_in"`
+	BirthYear *int `json:"birth_year,omitempty"`
+	// DisplayName is the anonymous name other users see.
+	DisplayName string `json:"display_name"`
+}
+
+// SimilarProfile is the anonymized card of a user whose aura trajectory
+// resembles the caller's. It carries no account identifiers.
+type SimilarProfile struct {
+	DisplayName   string  `json:"display_name"`
+	Region        string  `json:"region"`
+	DominantColor string  `json:"dominant_color"`
+	CurrentColor  string  `json:"current_color"`
+	EnergyTrend   string  `json:"energy_trend"`
+	Similarity    float64 `json:"similarity"`
+}
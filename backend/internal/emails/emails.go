@@ -0,0 +1,170 @@
+// Package emails renders localized HTML emails from embedded templates.
+//
+// Layout: templates/layout.html holds the shared frame and partials; each
+// locale directory has shared.html (footer) and one file per email defining
+// "subject" and "content". Missing locales fall back to English.
+package emails
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+const DefaultLocale = "en"
+
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// Message is a rendered email.
+type Message struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Renderer holds parsed templates keyed by locale and name.
+type Renderer struct {
+	templates map[string]map[string]*htmltemplate.Template
+}
+
+type renderContext struct {
+	Locale string
+	Data   map[string]any
+}
+
+type buttonData struct {
+	URL   string
+	Label string
+}
+
+var funcs = htmltemplate.FuncMap{
+	"button": func(url any, label string) buttonData {
+		return buttonData{URL: fmt.Sprint(url), Label: label}
+	},
+}
+
+// NewRenderer parses every embedded template. It fails on any template error so
+// broken emails are caught at startup rather than at send time.
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{templates: make(map[string]map[string]*htmltemplate.Template)}
+
+	locales, err := fs.ReadDir(templateFS, "templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range locales {
+		if !entry.IsDir() {
+			continue
+		}
+		locale := entry.Name()
+		files, err := fs.Glob(templateFS, path.Join("templates", locale, "*.html"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			name := strings.TrimSuffix(path.Base(file), ".html")
+			if name == "shared" {
+				continue
+			}
+			tmpl, err := htmltemplate.New(name).Funcs(funcs).ParseFS(templateFS,
+				"templates/layout.html",
+				path.Join("templates", locale, "shared.html"),
+				file,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s/%s: %w", locale, name, err)
+			}
+			if r.templates[locale] == nil {
+				r.templates[locale] = make(map[string]*htmltemplate.Template)
+			}
+			r.templates[locale][name] = tmpl
+		}
+	}
+	return r, nil
+}
+
+// Render executes the named template for locale ("tr-TR" matches "tr").
+func (r *Renderer) Render(name, locale string, data map[string]any) (*Message, error) {
+	locale = r.resolveLocale(name, locale)
+	tmpl, ok := r.templates[locale][name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	ctx := renderContext{Locale: locale, Data: data}
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", ctx); err != nil {
+		return nil, err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "layout", ctx); err != nil {
+		return nil, err
+	}
+
+	var content bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&content, "content", ctx); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		Subject: html.UnescapeString(strings.TrimSpace(subject.String())),
+		HTML:    body.String(),
+		Text:    htmlToText(content.String()),
+	}, nil
+}
+
+// Templates lists template names with the locales each is available in.
+func (r *Renderer) Templates() map[string][]string {
+	out := make(map[string][]string)
+	for locale, byName := range r.templates {
+		for name := range byName {
+			out[name] = append(out[name], locale)
+		}
+	}
+	for name := range out {
+		sort.Strings(out[name])
+	}
+	return out
+}
+
+func (r *Renderer) resolveLocale(name, locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	if _, ok := r.templates[locale][name]; ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+var (
+	blockEnd   = regexp.MustCompile(`(?i)</(p|h[1-6]|li|tr|div)>|<br\s*/?>`)
+	anchor     = regexp.MustCompile(`(?is)<a [^>]*href="([^"]+)"[^>]*>(.*?)</a>`)
+	anyTag     = regexp.MustCompile(`(?s)<[^>]+>`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText builds the plain-text alternative from rendered content.
+func htmlToText(s string) string {
+	s = anchor.ReplaceAllString(s, "$2 ($1)")
+	s = blockEnd.ReplaceAllString(s, "\n\n")
+	s = anyTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
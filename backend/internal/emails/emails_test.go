@@ -0,0 +1,45 @@
+package emails
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderAllTemplates(t *testing.T) {
+	r, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+
+	for name, locales := range r.Templates() {
+		for _, locale := range locales {
+			msg, err := r.Render(name, locale, SampleData(name))
+			if err != nil {
+				t.Fatalf("render %s/%s: %v", locale, name, err)
+			}
+			if msg.Subject == "" || !strings.Contains(msg.HTML, "<html") || msg.Text == "" {
+				t.Fatalf("incomplete render for %s/%s: %+v", locale, name, msg)
+			}
+		}
+	}
+}
+
+func TestRenderLocaleFallbackAndEscaping(t *testing.T) {
+	r, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+
+	tr, err := r.Render("welcome", "tr-TR", map[string]any{"Name": "Ayşe"})
+	if err != nil || !strings.Contains(tr.Subject, "hoş geldin") {
+		t.Fatalf("expected Turkish welcome, got %+v (%v)", tr, err)
+	}
+
+	de, err := r.Render("welcome", "de", map[string]any{"Name": "<script>x</script>"})
+	if err != nil || !strings.HasPrefix(de.Subject, "Welcome") {
+		t.Fatalf("expected English fallback, got %+v (%v)", de, err)
+	}
+	if strings.Contains(de.HTML, "<script>") {
+		t.Fatal("template data must be HTML-escaped")
+	}
+}
@@ -0,0 +1,31 @@
+package emails
+
+// SampleData returns representative data for previewing a template.
+func SampleData(name string) map[string]any {
+	switch name {
+	case "welcome":
+		return map[string]any{"Name": "Ada", "AppURL": "https://aurasnap.app/open"}
+	case "password_reset":
+		return map[string]any{"ResetURL": "https://aurasnap.app/reset-password?token=sample", "ExpiresMinutes": 60}
+	case "verify_email":
+		return map[string]any{"VerifyURL": "https://aurasnap.app/verify-email?token=sample", "ExpiresHours": 48}
+	case "email_change":
+		return map[string]any{"ConfirmURL": "https://aurasnap.app/confirm-email?token=sample", "ExpiresHours": 24}
+	case "email_changed":
+		return map[string]any{"NewEmail": "a***@example.com", "SupportEmail": "support@aurasnap.app"}
+	case "friend_invite":
+		return map[string]any{
+			"InviterHandle": "lin",
+			"InviteURL":     "https://aurasnap.app/invite/ABCD2345",
+			"Code":          "ABCD2345",
+			"ExpiresDays":   7,
+		}
+	case "notification":
+		return map[string]any{
+			"Title": "3 friends scanned their aura today",
+			"Body":  "Lin scanned their aura and 2 more. See how your energies match.",
+			"URL":   "https://aurasnap.app/open/friends",
+		}
+	}
+	return map[string]any{}
+}
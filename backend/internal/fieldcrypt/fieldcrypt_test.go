@@ -0,0 +1,80 @@
+package fieldcrypt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestRotation(t *testing.T) {
+	oldKey, newKeyValue := newKey(t), newKey(t)
+	old, err := New(oldKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := old.Encrypt("I want to change careers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sealed, "careers") || !strings.HasPrefix(sealed, old.CurrentPrefix()) {
+		t.Fatalf("sealed = %q", sealed)
+	}
+
+	rotated, err := New(newKeyValue, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := rotated.Decrypt(sealed)
+	if err != nil || plain != "I want to change careers" {
+		t.Fatalf("decrypt with previous key = %q, %v", plain, err)
+	}
+	if strings.HasPrefix(sealed, rotated.CurrentPrefix()) {
+		t.Error("value from previous key reported as current")
+	}
+
+	dropped, _ := New(newKeyValue, "")
+	if _, err := dropped.Decrypt(sealed); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("err = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestDecryptPlainAndTampered(t *testing.T) {
+	k, _ := New(newKey(t), "")
+	if plain, err := k.Decrypt("written before encryption"); err != nil || plain != "written before encryption" {
+		t.Errorf("plain text = %q, %v", plain, err)
+	}
+
+	var off *Keyring
+	sealed, _ := k.Encrypt("secret")
+	if _, err := off.Decrypt(sealed); !errors.Is(err, ErrNoKey) {
+		t.Errorf("without keys: err = %v, want ErrNoKey", err)
+	}
+
+	tampered := sealed[:len(sealed)-4] + "AAAA"
+	if _, err := k.Decrypt(tampered); !errors.Is(err, ErrMalformed) {
+		t.Errorf("tampered: err = %v, want ErrMalformed", err)
+	}
+}
+
+func TestNewRejectsBadKeys(t *testing.T) {
+	if k, err := New("", "whatever"); k != nil || err != nil {
+		t.Errorf("empty key = %v, %v; want encryption off", k, err)
+	}
+	if _, err := New(base64.StdEncoding.EncodeToString([]byte("short")), ""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("short key: err = %v", err)
+	}
+	if _, err := New(newKey(t), "not base64!"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("bad previous key: err = %v", err)
+	}
+}
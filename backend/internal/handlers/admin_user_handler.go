@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AdminUserHandler serves the admin user view and dashboard metrics
+type AdminUserHandler struct {
+	adminUserService    *services.AdminUserService
+	adminMetricsService *services.AdminMetricsService
+}
+
+// NewAdminUserHandler creates a new AdminUserHandler instance
+func NewAdminUserHandler(adminUserService *services.AdminUserService, adminMetricsService *services.AdminMetricsService) *AdminUserHandler {
+	return &AdminUserHandler{adminUserService: adminUserService, adminMetricsService: adminMetricsService}
+}
+
+// ListUsers returns users with their email deliverability flag.
+// ?q= filters by email or handle, ?email_status=invalid shows only flagged
+// accounts, ?type=guest|registered and ?subscribed=true|false narrow further.
+func (h *AdminUserHandler) ListUsers(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	filter := dto.AdminUserFilter{
+		Search:      c.Query("q"),
+		InvalidOnly: c.Query("email_status") == "invalid",
+		Type:        c.Query("type"),
+	}
+	if filter.Type != "" && filter.Type != "guest" && filter.Type != "registered" {
+		return apperr.New(fiber.StatusBadRequest, "type must be guest or registered")
+	}
+	if v := c.Query("subscribed"); v != "" {
+		subscribed, err := strconv.ParseBool(v)
+		if err != nil {
+			return apperr.New(fiber.StatusBadRequest, "subscribed must be true or false")
+		}
+		filter.Subscribed = &subscribed
+	}
+
+	users, total, err := h.adminUserService.ListUsers(filter, limit, offset)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch users").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"users":  users,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetUser returns one user's account, usage and subscription detail.
+func (h *AdminUserHandler) GetUser(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	user, err := h.adminUserService.GetUser(userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch user").WithCause(err)
+	}
+
+	return c.JSON(user)
+}
+
+// MetricsOverview returns DAU, scans per day, the AI fallback rate and the
+// estimated provider spend. ?days= sets the window (default 14, max 90).
+func (h *AdminUserHandler) MetricsOverview(c *fiber.Ctx) error {
+	days, _ := strconv.Atoi(c.Query("days", "14"))
+
+	overview, err := h.adminMetricsService.Overview(days)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to load metrics").WithCause(err)
+	}
+
+	return c.JSON(overview)
+}
+
+// ForceLogout signs a user out of all sessions immediately.
+func (h *AdminUserHandler) ForceLogout(c *fiber.Ctx) error {
+	adminID, userID, err := adminAndTarget(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.adminUserService.ForceLogout(adminID, userID); err != nil {
+		return adminUserError(c, err, "Failed to sign user out")
+	}
+	return c.JSON(fiber.Map{"message": "User signed out of all sessions"})
+}
+
+// GrantPremium gives a user complimentary premium for a number of days.
+func (h *AdminUserHandler) GrantPremium(c *fiber.Ctx) error {
+	adminID, userID, err := adminAndTarget(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.GrantPremiumRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	sub, err := h.adminUserService.GrantPremium(adminID, userID, &req)
+	if err != nil {
+		return adminUserError(c, err, "Failed to grant premium")
+	}
+	return c.Status(fiber.StatusCreated).JSON(sub)
+}
+
+// ResetQuota restarts a user's daily scan and match allowance.
+func (h *AdminUserHandler) ResetQuota(c *fiber.Ctx) error {
+	adminID, userID, err := adminAndTarget(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.adminUserService.ResetQuota(adminID, userID); err != nil {
+		return adminUserError(c, err, "Failed to reset quota")
+	}
+	return c.JSON(fiber.Map{"message": "Daily quota reset"})
+}
+
+// SendMessage delivers a templated message to a user's inbox, email and
+// push, threaded with the ticket it answers.
+func (h *AdminUserHandler) SendMessage(c *fiber.Ctx) error {
+	adminID, userID, err := adminAndTarget(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.AdminMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	msg, err := h.adminUserService.SendMessage(adminID, userID, &req)
+	if err != nil {
+		return adminUserError(c, err, "Failed to send message")
+	}
+	return c.Status(fiber.StatusCreated).JSON(msg)
+}
+
+// OverrideLeaderboardName replaces a user's public leaderboard name and,
+// unless lock is false, stops the user from changing it.
+func (h *AdminUserHandler) OverrideLeaderboardName(c *fiber.Ctx) error {
+	adminID, userID, err := adminAndTarget(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.LeaderboardNameOverride
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	resp, err := h.adminUserService.OverrideLeaderboardName(adminID, userID, &req)
+	if err != nil {
+		return adminUserError(c, err, "Failed to override leaderboard name")
+	}
+	return c.JSON(resp)
+}
+
+// adminAndTarget reads the acting admin and the :id user. On failure the
+// returned error is for the handler to pass on.
+func adminAndTarget(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	adminID, err := extractUserID(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperr.New(fiber.StatusBadRequest, "Invalid user ID")
+	}
+	return adminID, userID, nil
+}
+
+func adminUserError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		return apperr.New(fiber.StatusNotFound, "User not found")
+	case errors.Is(err, services.ErrTicketNotFound):
+		return apperr.Wrap(fiber.StatusNotFound, err)
+	case errors.Is(err, services.ErrInvalidGrant), errors.Is(err, services.ErrInvalidAdminMessage),
+		errors.Is(err, services.ErrInvalidDisplayName), errors.Is(err, services.ErrDisplayNameProfanity):
+		return apperr.Wrap(fiber.StatusBadRequest, err)
+	case errors.Is(err, services.ErrDisplayNameTaken):
+		return apperr.Wrap(fiber.StatusConflict, err)
+	}
+	return apperr.New(fiber.StatusInternalServerError, fallback).WithCause(err)
+}
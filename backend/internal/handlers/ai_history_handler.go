@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AIHistoryHandler shows users the AI calls made on their behalf
+type AIHistoryHandler struct {
+	aiHistoryService *services.AIHistoryService
+}
+
+// NewAIHistoryHandler creates a new AIHistoryHandler instance
+func NewAIHistoryHandler(aiHistoryService *services.AIHistoryService) *AIHistoryHandler {
+	return &AIHistoryHandler{aiHistoryService: aiHistoryService}
+}
+
+// List returns the user's AI interactions (purpose, provider, model,
+// outcome, time), newest first
+func (h *AIHistoryHandler) List(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	history, err := h.aiHistoryService.List(userID, c.QueryInt("page", 1), c.QueryInt("page_size", 0))
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch AI history").WithCause(err)
+	}
+
+	return c.JSON(history)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditHandler serves the audit log to admins and records the admin
+// request trail.
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// List returns audit entries filtered by ?actor_id=, ?action= (prefix),
+// ?target_type=, ?target_id=, ?ip=, ?since= and ?until= (admin).
+func (h *AuditHandler) List(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	entries, total, err := h.auditService.List(dto.AuditLogFilter{
+		ActorID:    c.Query("actor_id"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		IP:         c.Query("ip"),
+		Since:      c.Query("since"),
+		Until:      c.Query("until"),
+	}, limit, offset)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAuditFilter) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch audit log").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"entries": entries,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// Record writes an entry for middleware.AuditAdminRequests. The request has
+// already been handled, so a failure is logged rather than returned.
+func (h *AuditHandler) Record(entry dto.AuditEntry) {
+	if err := h.auditService.Record(entry); err != nil {
+		log.Printf("audit: recording %s by %s: %v", entry.Action, entry.ActorID, err)
+	}
+}
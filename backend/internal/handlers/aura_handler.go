@@ -1,25 +1,42 @@
 package handlers
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
+const (
+	// scanJobWatchTimeout closes scan position sockets left open too long.
+	scanJobWatchTimeout = 5 * time.Minute
+	// scanJobPollInterval is how often a socket re-reads the position of a
+	// job another instance is analyzing.
+	scanJobPollInterval = 2 * time.Second
+)
+
 // AuraHandler handles HTTP requests related to Aura scanning
 type AuraHandler struct {
-	auraService *services.AuraService
+	auraService        *services.AuraService
+	entitlementService *services.EntitlementService
+	embeddingService   *services.ReadingEmbeddingService
 }
 
 // NewAuraHandler creates a new AuraHandler instance
-func NewAuraHandler(auraService *services.AuraService) *AuraHandler {
-	return &AuraHandler{auraService: auraService}
+func NewAuraHandler(auraService *services.AuraService, entitlementService *services.EntitlementService, embeddingService *services.ReadingEmbeddingService) *AuraHandler {
+	return &AuraHandler{auraService: auraService, entitlementService: entitlementService, embeddingService: embeddingService}
 }
 
 // CheckScanEligibility checks if the user can perform a scan
@@ -27,20 +44,21 @@ func (h *AuraHandler) CheckScanEligibility(c *fiber.Ctx) error {
 	userIDStr := c.Locals("userID").(string)
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid user ID")
 	}
 
-	isSubscribed := h.auraService.IsSubscribed(userID)
+	ent := h.entitlementService.For(c.UserContext(), userID)
 
-	allowed, remaining, err := h.auraService.CanScan(userID, isSubscribed)
+	allowed, remaining, err := h.auraService.CanScan(c.UserContext(), userID, ent.DailyScans)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check eligibility"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to check eligibility").WithCause(err)
 	}
 
 	return c.JSON(dto.ScanEligibilityResponse{
 		CanScan:      allowed,
 		Remaining:    remaining,
-		IsSubscribed: isSubscribed,
+		IsSubscribed: ent.Tier != services.TierFree,
+		Tier:         ent.Tier,
 	})
 }
 
@@ -49,38 +67,52 @@ func (h *AuraHandler) Scan(c *fiber.Ctx) error {
 	userIDStr := c.Locals("userID").(string)
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid user ID")
 	}
 
 	// Rate limit check
-	isSubscribed := h.auraService.IsSubscribed(userID)
-	allowed, _, err := h.auraService.CanScan(userID, isSubscribed)
+	ent := h.entitlementService.For(c.UserContext(), userID)
+	allowed, _, err := h.auraService.CanScan(c.UserContext(), userID, ent.DailyScans)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to verify scan eligibility"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to verify scan eligibility").WithCause(err)
 	}
 	if !allowed {
-		return c.Status(429).JSON(fiber.Map{"error": "Daily scan limit reached. Upgrade to Premium for unlimited scans."})
+		metrics.QuotaRejectionsTotal.WithLabelValues("daily_scans").Inc()
+		return apperr.New(fiber.StatusTooManyRequests, "Daily scan limit reached. Upgrade your plan for more scans.")
 	}
 
 	// Parse request
 	var req dto.CreateAuraRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
 	}
-
-	// Validate base64 size (max ~3MB base64 = ~2.25MB image)
-	if req.ImageData != "" && len(req.ImageData) > 3*1024*1024 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Image data too large. Maximum 3MB base64."})
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
 	}
 
-	if req.ImageData == "" && req.ImageURL == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Either image_data or image_url is required"})
+	req.Async = req.Async || c.QueryBool("async")
+	return h.createReading(c, userID, req)
+}
+
+// createReading runs the scan synchronously (201 with the final reading) or,
+// in async mode, returns 202 with a pending provisional reading to poll
+func (h *AuraHandler) createReading(c *fiber.Ctx, userID uuid.UUID, req dto.CreateAuraRequest) error {
+	if req.Locale == "" {
+		req.Locale = c.Get(fiber.HeaderAcceptLanguage)
+	}
+	if req.Async {
+		reading, err := h.auraService.CreateInstant(c.UserContext(), userID, req)
+		if err != nil {
+			// Rejected photos are a 422 with a code telling the app why, so
+			// it can ask for a different photo; see serviceErrors.
+			return err
+		}
+		return c.Status(fiber.StatusAccepted).JSON(reading)
 	}
 
-	// Create aura reading
-	reading, err := h.auraService.Create(userID, req)
+	reading, err := h.auraService.Create(c.UserContext(), userID, req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(reading)
@@ -91,46 +123,47 @@ func (h *AuraHandler) ScanWithUpload(c *fiber.Ctx) error {
 	userIDStr := c.Locals("userID").(string)
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid user ID")
 	}
 
 	// Rate limit check
-	isSubscribed := h.auraService.IsSubscribed(userID)
-	allowed, _, err := h.auraService.CanScan(userID, isSubscribed)
+	ent := h.entitlementService.For(c.UserContext(), userID)
+	allowed, _, err := h.auraService.CanScan(c.UserContext(), userID, ent.DailyScans)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to verify scan eligibility"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to verify scan eligibility").WithCause(err)
 	}
 	if !allowed {
-		return c.Status(429).JSON(fiber.Map{"error": "Daily scan limit reached. Upgrade to Premium for unlimited scans."})
+		metrics.QuotaRejectionsTotal.WithLabelValues("daily_scans").Inc()
+		return apperr.New(fiber.StatusTooManyRequests, "Daily scan limit reached. Upgrade your plan for more scans.")
 	}
 
 	// Get file from form
 	file, err := c.FormFile("image")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Image file is required"})
+		return apperr.New(fiber.StatusBadRequest, "Image file is required")
 	}
 
 	// Validate file type
 	contentType := file.Header.Get("Content-Type")
 	if !strings.HasPrefix(contentType, "image/jpeg") && !strings.HasPrefix(contentType, "image/png") {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Only JPEG and PNG images are supported"})
+		return apperr.New(fiber.StatusBadRequest, "Only JPEG and PNG images are supported")
 	}
 
 	// Validate file size (4MB max)
 	if file.Size > 4*1024*1024 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Image too large. Maximum 4MB."})
+		return apperr.New(fiber.StatusBadRequest, "Image too large. Maximum 4MB.")
 	}
 
 	// Read file content
 	f, err := file.Open()
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read image"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to read image").WithCause(err)
 	}
 	defer f.Close()
 
 	fileBytes, err := io.ReadAll(f)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read image data"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to read image data").WithCause(err)
 	}
 
 	// Encode to base64
@@ -138,14 +171,239 @@ func (h *AuraHandler) ScanWithUpload(c *fiber.Ctx) error {
 
 	req := dto.CreateAuraRequest{
 		ImageData: b64Data,
+		Async:     c.FormValue("async") == "true" || c.QueryBool("async"),
 	}
 
-	reading, err := h.auraService.Create(userID, req)
+	return h.createReading(c, userID, req)
+}
+
+// ScanGroup reads the aura of every person in a multi-person photo
+func (h *AuraHandler) ScanGroup(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(reading)
+	ent := h.entitlementService.For(c.UserContext(), userID)
+	if !ent.GroupScans {
+		return apperr.New(fiber.StatusForbidden, "Group scans are included in Plus and Pro")
+	}
+	allowed, _, err := h.auraService.CanScan(c.UserContext(), userID, ent.DailyScans)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to verify scan eligibility").WithCause(err)
+	}
+	if !allowed {
+		metrics.QuotaRejectionsTotal.WithLabelValues("daily_scans").Inc()
+		return apperr.New(fiber.StatusTooManyRequests, "Daily scan limit reached. Upgrade your plan for more scans.")
+	}
+
+	var req dto.CreateGroupAuraRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	group, err := h.auraService.CreateGroup(c.UserContext(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotAGroupPhoto), errors.Is(err, services.ErrImageRejected):
+			return apperr.Wrap(fiber.StatusUnprocessableEntity, err)
+		case errors.Is(err, services.ErrGroupScanUnavailable), errors.Is(err, services.ErrImageModerationUnavailable):
+			return apperr.Wrap(fiber.StatusServiceUnavailable, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to scan group photo").WithCause(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(group)
+}
+
+// ScanBatch imports up to 10 photos as async scans (Plus and Pro)
+func (h *AuraHandler) ScanBatch(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	ent := h.entitlementService.For(c.UserContext(), userID)
+	if !ent.BatchScans {
+		return apperr.New(fiber.StatusForbidden, "Batch scans are included in Plus and Pro")
+	}
+
+	var req dto.CreateScanBatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	// Every photo uses a scan, so the whole batch has to fit in what is left
+	// of today's quota.
+	allowed, remaining, err := h.auraService.CanScan(c.UserContext(), userID, ent.DailyScans)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to verify scan eligibility").WithCause(err)
+	}
+	if !allowed {
+		metrics.QuotaRejectionsTotal.WithLabelValues("daily_scans").Inc()
+		return apperr.New(fiber.StatusTooManyRequests, "Daily scan limit reached. Upgrade your plan for more scans.")
+	}
+	if remaining >= 0 && remaining < len(req.Images) {
+		metrics.QuotaRejectionsTotal.WithLabelValues("daily_scans").Inc()
+		return apperr.New(fiber.StatusTooManyRequests, "Only "+strconv.Itoa(remaining)+" scans left today; send fewer photos.")
+	}
+
+	if req.Locale == "" {
+		req.Locale = c.Get(fiber.HeaderAcceptLanguage)
+	}
+	batch, err := h.auraService.CreateBatch(c.UserContext(), userID, req)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusAccepted).JSON(batch)
+}
+
+// GetScanBatch returns a scan batch's readings and how many are still pending
+func (h *AuraHandler) GetScanBatch(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	batchID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid batch ID")
+	}
+
+	batch, err := h.auraService.GetBatch(c.UserContext(), userID, batchID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(batch)
+}
+
+// ScanJobPosition returns an async scan's place in the analysis queue and
+// its estimated wait
+func (h *AuraHandler) ScanJobPosition(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid job ID")
+	}
+
+	pos, err := h.auraService.ScanJobPosition(c.UserContext(), userID, jobID)
+	if err != nil {
+		if errors.Is(err, services.ErrScanJobNotFound) {
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to get scan position").WithCause(err)
+	}
+	return c.JSON(pos)
+}
+
+// WatchScanJob upgrades to a WebSocket that pushes the scan job's position
+// whenever it changes and closes once the job is done
+func (h *AuraHandler) WatchScanJob(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return apperr.New(fiber.StatusUpgradeRequired, "WebSocket upgrade required")
+	}
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	jobID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid job ID")
+	}
+
+	pos, err := h.auraService.ScanJobPosition(c.UserContext(), userID, jobID)
+	if err != nil {
+		if errors.Is(err, services.ErrScanJobNotFound) {
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to get scan position").WithCause(err)
+	}
+
+	return websocket.New(func(conn *websocket.Conn) {
+		h.streamScanJob(conn, userID, pos)
+	})(c)
+}
+
+// streamScanJob writes pos and every later change until the job is done,
+// the client goes away or scanJobWatchTimeout passes. Jobs analyzed by
+// another instance cannot be watched and are polled instead.
+func (h *AuraHandler) streamScanJob(conn *websocket.Conn, userID uuid.UUID, pos *dto.ScanJobPosition) {
+	if err := conn.WriteJSON(pos); err != nil || pos.Status == services.ScanJobDone {
+		return
+	}
+	jobID, _ := uuid.Parse(pos.JobID)
+
+	updates, stop, watching := h.auraService.WatchScanJob(jobID)
+	defer stop()
+	var poll <-chan time.Time
+	if !watching {
+		ticker := time.NewTicker(scanJobPollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	timeout := time.NewTimer(scanJobWatchTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case next, open := <-updates:
+			if !open {
+				return
+			}
+			pos = &next
+		case <-poll:
+			next, err := h.auraService.ScanJobPosition(context.Background(), userID, jobID)
+			if err != nil {
+				return
+			}
+			pos = next
+		case <-gone:
+			return
+		case <-timeout.C:
+			return
+		}
+		if err := conn.WriteJSON(pos); err != nil || pos.Status == services.ScanJobDone {
+			return
+		}
+	}
+}
+
+// GetGroup retrieves a group reading with each person's aura
+func (h *AuraHandler) GetGroup(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	groupID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid group reading ID")
+	}
+
+	group, err := h.auraService.GetGroup(userID, groupID)
+	if err != nil {
+		return apperr.New(fiber.StatusNotFound, "Group reading not found")
+	}
+
+	return c.JSON(group)
 }
 
 // GetByID retrieves a single aura reading
@@ -153,38 +411,246 @@ func (h *AuraHandler) GetByID(c *fiber.Ctx) error {
 	userIDStr := c.Locals("userID").(string)
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid user ID")
 	}
 
 	readingID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid reading ID"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
 	}
 
 	reading, err := h.auraService.GetByID(userID, readingID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Reading not found"})
+		return apperr.New(fiber.StatusNotFound, "Reading not found")
 	}
 
 	return c.JSON(reading)
 }
 
-// List returns paginated aura readings for the user
+// Theme returns the reading's aura color blended with its photo palette
+func (h *AuraHandler) Theme(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	readingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
+	}
+
+	theme, err := h.auraService.Theme(userID, readingID)
+	if err != nil {
+		if errors.Is(err, services.ErrReadingNotFound) {
+			return apperr.New(fiber.StatusNotFound, "Reading not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to build theme").WithCause(err)
+	}
+
+	return c.JSON(theme)
+}
+
+// Similar returns the user's readings closest in meaning to a reading
+func (h *AuraHandler) Similar(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	readingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
+	}
+
+	similar, err := h.embeddingService.Similar(c.UserContext(), userID, readingID, c.QueryInt("limit", 10))
+	if err != nil {
+		if errors.Is(err, services.ErrReadingNotFound) {
+			return apperr.New(fiber.StatusNotFound, "Reading not found")
+		}
+		return err
+	}
+
+	return c.JSON(fiber.Map{"data": similar})
+}
+
+// Photo serves the original photo stored with a reading
+func (h *AuraHandler) Photo(c *fiber.Ctx) error {
+	return h.servePhoto(c, false)
+}
+
+// Thumbnail serves the reading's photo thumbnail, which outlives evicted originals
+func (h *AuraHandler) Thumbnail(c *fiber.Ctx) error {
+	return h.servePhoto(c, true)
+}
+
+func (h *AuraHandler) servePhoto(c *fiber.Ctx, thumbnail bool) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	readingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
+	}
+
+	path, contentType, err := h.auraService.Photo(userID, readingID, thumbnail)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPhotoEvicted):
+			return apperr.Wrap(fiber.StatusGone, err)
+		case errors.Is(err, services.ErrPhotoArchived):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		case errors.Is(err, services.ErrPhotoNotFound):
+			return apperr.New(fiber.StatusNotFound, "Photo not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to load photo").WithCause(err)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=86400")
+	return c.SendFile(path)
+}
+
+// RestorePhoto requests an archived original back from cold storage; 202
+// while the restore runs, 200 once the photo can be viewed
+func (h *AuraHandler) RestorePhoto(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	readingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
+	}
+
+	restore, err := h.auraService.RequestPhotoRestore(c.UserContext(), userID, readingID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPhotoEvicted):
+			return apperr.Wrap(fiber.StatusGone, err)
+		case errors.Is(err, services.ErrPhotoNotFound):
+			return apperr.New(fiber.StatusNotFound, "Photo not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to restore photo").WithCause(err)
+	}
+	if restore.Status == services.PhotoRestoring {
+		return c.Status(fiber.StatusAccepted).JSON(restore)
+	}
+	return c.JSON(restore)
+}
+
+// Regenerate re-runs the AI on a reading's stored image (Plus and Pro). It
+// uses one of the day's scans and keeps the previous result as a version
+func (h *AuraHandler) Regenerate(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	readingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
+	}
+
+	ent := h.entitlementService.For(c.UserContext(), userID)
+	if ent.Tier == services.TierFree {
+		return apperr.New(fiber.StatusForbidden, "Regenerating readings is included in Plus and Pro")
+	}
+	allowed, _, err := h.auraService.CanScan(c.UserContext(), userID, ent.DailyScans)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to verify scan eligibility").WithCause(err)
+	}
+	if !allowed {
+		metrics.QuotaRejectionsTotal.WithLabelValues("daily_scans").Inc()
+		return apperr.New(fiber.StatusTooManyRequests, "Daily scan limit reached. Upgrade your plan for more scans.")
+	}
+
+	reading, err := h.auraService.Regenerate(c.UserContext(), userID, readingID, c.Get(fiber.HeaderAcceptLanguage))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUpgradeRequired):
+			return apperr.New(fiber.StatusForbidden, "Regenerating readings is included in Plus and Pro")
+		case errors.Is(err, services.ErrReadingNotFound):
+			return apperr.New(fiber.StatusNotFound, "Reading not found")
+		case errors.Is(err, services.ErrReadingPending), errors.Is(err, services.ErrGroupReadingRegenerate):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		case errors.Is(err, services.ErrRegenerateUnavailable):
+			return apperr.Wrap(fiber.StatusServiceUnavailable, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to regenerate reading").WithCause(err)
+	}
+
+	return c.JSON(reading)
+}
+
+// List returns paginated aura readings for the user. A cursor parameter
+// (empty for the first page) switches from page numbers to cursor pages.
 func (h *AuraHandler) List(c *fiber.Ctx) error {
 	userIDStr := c.Locals("userID").(string)
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid user ID")
 	}
 
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
 
-	readings, total, err := h.auraService.List(userID, page, pageSize)
+	filter := dto.AuraListFilter{
+		Color:      c.Query("color"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+		MinEnergy:  c.Query("min_energy"),
+		Sort:       c.Query("sort"),
+		Order:      c.Query("order"),
+		PinnedOnly: c.QueryBool("pinned"),
+		Trait:      c.Query("trait"),
+	}
+
+	if c.Context().QueryArgs().Has("cursor") {
+		if pageSize < 1 || pageSize > 100 {
+			pageSize = 20
+		}
+		readings, next, err := h.auraService.ListByCursor(userID, c.Query("cursor"), pageSize, filter)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidListFilter) || errors.Is(err, services.ErrInvalidCursor) ||
+				errors.Is(err, services.ErrCursorSortUnsupported) {
+				return apperr.Wrap(fiber.StatusBadRequest, err)
+			}
+			if errors.Is(err, services.ErrTraitIndexNotReady) {
+				return apperr.Wrap(fiber.StatusServiceUnavailable, err)
+			}
+			return apperr.New(fiber.StatusInternalServerError, "Failed to fetch readings").WithCause(err)
+		}
+		return c.JSON(dto.AuraCursorResponse{
+			Data:       readingListItems(readings),
+			PageSize:   pageSize,
+			NextCursor: next,
+		})
+	}
+
+	readings, total, err := h.auraService.List(userID, page, pageSize, filter)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch readings"})
+		if errors.Is(err, services.ErrInvalidListFilter) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		if errors.Is(err, services.ErrTraitIndexNotReady) {
+			return apperr.Wrap(fiber.StatusServiceUnavailable, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch readings").WithCause(err)
 	}
 
+	return c.JSON(dto.AuraListResponse{
+		Data:       readingListItems(readings),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: total,
+	})
+}
+
+// readingListItems converts readings to their list representation.
+func readingListItems(readings []models.AuraReading) []dto.AuraReadingResponse {
 	items := make([]dto.AuraReadingResponse, 0, len(readings))
 	for _, r := range readings {
 		items = append(items, dto.AuraReadingResponse{
@@ -198,18 +664,21 @@ func (h *AuraHandler) List(c *fiber.Ctx) error {
 			Strengths:      r.Strengths,
 			Challenges:     r.Challenges,
 			DailyAdvice:    r.DailyAdvice,
+			Provenance:     r.Provenance,
 			ImageURL:       r.ImageURL,
+			Status:         r.Status,
+			Language:       r.Language,
+			Note:           r.Note,
+			MoodTags:       r.MoodTags,
+			NotedAt:        r.NotedAt,
+			Pinned:         r.Pinned,
+			PinnedAt:       r.PinnedAt,
 			AnalyzedAt:     r.AnalyzedAt,
 			CreatedAt:      r.CreatedAt,
 		})
 	}
 
-	return c.JSON(dto.AuraListResponse{
-		Data:       items,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalCount: total,
-	})
+	return items
 }
 
 // Stats returns aggregated stats for the user's aura readings
@@ -217,13 +686,297 @@ func (h *AuraHandler) Stats(c *fiber.Ctx) error {
 	userIDStr := c.Locals("userID").(string)
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid user ID")
 	}
 
 	stats, err := h.auraService.GetStats(userID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch stats"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch stats").WithCause(err)
 	}
 
 	return c.JSON(stats)
 }
+
+// Traits returns the user's most recurring strengths and challenges, named
+// in the request's language
+func (h *AuraHandler) Traits(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	limit := c.QueryInt("limit", 10)
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+	stats, err := h.auraService.TraitStats(userID, requestLocale(c, c.Query("locale")), limit)
+	if err != nil {
+		if errors.Is(err, services.ErrTraitIndexNotReady) {
+			return apperr.Wrap(fiber.StatusServiceUnavailable, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch trait stats").WithCause(err)
+	}
+	return c.JSON(stats)
+}
+
+// Rate stores the owner's post-scan accuracy rating for a reading
+func (h *AuraHandler) Rate(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	readingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
+	}
+
+	var req dto.RateReadingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	reading, err := h.auraService.RateReading(userID, readingID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRating):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		case errors.Is(err, services.ErrReadingNotFound):
+			return apperr.New(fiber.StatusNotFound, "Reading not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to save rating").WithCause(err)
+	}
+
+	return c.JSON(reading)
+}
+
+// Pin keeps a reading at the top of the user's history
+func (h *AuraHandler) Pin(c *fiber.Ctx) error {
+	return h.setPinned(c, true)
+}
+
+// Unpin returns a pinned reading to its place in the history
+func (h *AuraHandler) Unpin(c *fiber.Ctx) error {
+	return h.setPinned(c, false)
+}
+
+func (h *AuraHandler) setPinned(c *fiber.Ctx, pinned bool) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	readingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
+	}
+
+	reading, err := h.auraService.SetPinned(userID, readingID, pinned)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTooManyPins):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		case errors.Is(err, services.ErrReadingNotFound):
+			return apperr.New(fiber.StatusNotFound, "Reading not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to update pin").WithCause(err)
+	}
+
+	return c.JSON(reading)
+}
+
+// DeleteMany deletes up to 100 of the user's readings listed in the body
+func (h *AuraHandler) DeleteMany(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.DeleteReadingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	deleted, err := h.auraService.DeleteMany(userID, req.IDs)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBulkDelete) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to delete readings").WithCause(err)
+	}
+
+	return c.JSON(dto.DeleteReadingsResponse{Deleted: deleted})
+}
+
+// DeleteAll clears the user's whole reading history. Without a
+// confirmation token it deletes nothing and answers 428 with a token to
+// send back
+func (h *AuraHandler) DeleteAll(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.ClearHistoryRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+		}
+		if fields := validateRequest(&req); fields != nil {
+			return validationFailed(fields)
+		}
+	}
+
+	if req.ConfirmationToken == "" {
+		confirmation, err := h.auraService.ClearHistoryConfirmation(userID)
+		if err != nil {
+			return clearHistoryError(c, err)
+		}
+		return c.Status(fiber.StatusPreconditionRequired).JSON(confirmation)
+	}
+
+	deleted, err := h.auraService.DeleteAll(userID, req.ConfirmationToken)
+	if err != nil {
+		return clearHistoryError(c, err)
+	}
+	return c.JSON(dto.DeleteReadingsResponse{Deleted: deleted})
+}
+
+func clearHistoryError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidClearHistory):
+		return apperr.Wrap(fiber.StatusForbidden, err)
+	case errors.Is(err, services.ErrClearHistoryDisabled):
+		return apperr.Wrap(fiber.StatusServiceUnavailable, err)
+	}
+	return apperr.New(fiber.StatusInternalServerError, "Failed to clear history").WithCause(err)
+}
+
+// SetNote saves the user's private journal note and mood tags on a reading
+func (h *AuraHandler) SetNote(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	readingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
+	}
+
+	var req dto.ReadingNoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	reading, err := h.auraService.SetNote(userID, readingID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidNote):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		case errors.Is(err, services.ErrReadingNotFound):
+			return apperr.New(fiber.StatusNotFound, "Reading not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to save note").WithCause(err)
+	}
+
+	return c.JSON(reading)
+}
+
+// RatingStats aggregates reading self-ratings per prompt variant and provider (admin only)
+func (h *AuraHandler) RatingStats(c *fiber.Ctx) error {
+	stats, err := h.auraService.RatingStats()
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to load rating stats").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"data": stats})
+}
+
+// PromptVariantStats compares share rates between prompt variants (admin only)
+func (h *AuraHandler) PromptVariantStats(c *fiber.Ctx) error {
+	stats, err := h.auraService.PromptVariantStats()
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to load prompt variant stats").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"data": stats})
+}
+
+// AdminAnalyze runs an unsaved analysis with sampling overrides (admin/testing only)
+func (h *AuraHandler) AdminAnalyze(c *fiber.Ctx) error {
+	var req dto.AdminAnalyzeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	preview, err := h.auraService.Analyze(c.UserContext(), req)
+	if err != nil {
+		return apperr.Wrap(fiber.StatusBadRequest, err)
+	}
+
+	return c.JSON(preview)
+}
+
+// AdminCorrectReading overwrites fields of a reading, keeping the previous values as history
+func (h *AuraHandler) AdminCorrectReading(c *fiber.Ctx) error {
+	adminID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	readingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
+	}
+
+	var req dto.ReadingCorrectionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	reading, err := h.auraService.CorrectReading(c.UserContext(), adminID, readingID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrReadingNotFound):
+			return apperr.New(fiber.StatusNotFound, "Reading not found")
+		case errors.Is(err, services.ErrReadingPending):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		case errors.Is(err, services.ErrInvalidCorrection):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to correct reading").WithCause(err)
+	}
+
+	return c.JSON(reading)
+}
+
+// AdminListCorrections returns a reading's correction history
+func (h *AuraHandler) AdminListCorrections(c *fiber.Ctx) error {
+	readingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
+	}
+
+	corrections, err := h.auraService.ListCorrections(readingID)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch corrections").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"corrections": corrections})
+}
@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
@@ -20,17 +23,27 @@ func (h *AuraMatchHandler) CreateMatch(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": true, "message": "Invalid user ID"})
+		return apperr.New(fiber.StatusUnauthorized, "Invalid user ID")
 	}
 
 	var req dto.CreateMatchRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": true, "message": "Invalid request body"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
 	}
 
-	match, err := h.matchService.Create(parsedUserID, req)
+	match, err := h.matchService.Create(c.UserContext(), parsedUserID, req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": true, "message": err.Error()})
+		switch {
+		case errors.Is(err, services.ErrMatchLimitReached):
+			return apperr.New(fiber.StatusTooManyRequests, "Daily match limit reached. Upgrade your plan for more matches.")
+		case errors.Is(err, services.ErrNoAuraReading), errors.Is(err, services.ErrFriendNoAuraReading):
+			// A 404 would read as "no such friend" in the app.
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(match)
@@ -40,12 +53,12 @@ func (h *AuraMatchHandler) GetMatches(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": true, "message": "Invalid user ID"})
+		return apperr.New(fiber.StatusUnauthorized, "Invalid user ID")
 	}
 
 	matches, err := h.matchService.List(parsedUserID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": true, "message": "Failed to fetch matches"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch matches").WithCause(err)
 	}
 
 	return c.JSON(fiber.Map{"data": matches})
@@ -55,22 +68,69 @@ func (h *AuraMatchHandler) GetMatchByFriend(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": true, "message": "Invalid user ID"})
+		return apperr.New(fiber.StatusUnauthorized, "Invalid user ID")
 	}
 
 	friendIDStr := c.Params("friend_id")
 	friendID, err := uuid.Parse(friendIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": true, "message": "Invalid friend ID"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid friend ID")
 	}
 
 	match, err := h.matchService.GetByFriend(parsedUserID, friendID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": true, "message": "No match found with this friend"})
+			return apperr.New(fiber.StatusNotFound, "No match found with this friend")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": true, "message": "Failed to fetch match"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch match").WithCause(err)
 	}
 
 	return c.JSON(match)
 }
+
+// GetMatchNarrative returns the match with its premium LLM narrative
+func (h *AuraMatchHandler) GetMatchNarrative(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Invalid user ID")
+	}
+
+	friendID, err := uuid.Parse(c.Params("friend_id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid friend ID")
+	}
+
+	match, err := h.matchService.Narrative(c.UserContext(), parsedUserID, friendID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPremiumRequired):
+			return apperr.Wrap(fiber.StatusPaymentRequired, err)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return apperr.New(fiber.StatusNotFound, "No match found with this friend")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to generate narrative").WithCause(err)
+	}
+
+	return c.JSON(match)
+}
+
+// GetCompatibilityToday returns the colors that harmonize with the user's
+// current aura and the friend who pairs best with it today
+func (h *AuraMatchHandler) GetCompatibilityToday(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Invalid user ID")
+	}
+
+	result, err := h.matchService.CompatibilityToday(c.UserContext(), parsedUserID, c.Get(timezoneHeader))
+	if err != nil {
+		if errors.Is(err, services.ErrNoAuraReading) {
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to compute compatibility").WithCause(err)
+	}
+
+	return c.JSON(result)
+}
@@ -2,10 +2,14 @@ package handlers
 
 import (
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 // AuthHandler handles authentication requests
@@ -22,15 +26,19 @@ func NewAuthHandler(authService *services.AuthService) *AuthHandler {
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	var req dto.RegisterRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: true, Message: "Invalid request body"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
 	}
 
-	resp, err := h.authService.Register(&req)
+	req.Locale = requestLocale(c, req.Locale)
+	resp, err := h.authService.Register(&req, deviceInfo(c))
 	if err != nil {
-		if errors.Is(err, services.ErrEmailTaken) {
-			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{Error: true, Message: err.Error()})
+		if errors.Is(err, services.ErrEmailTaken) || errors.Is(err, services.ErrAccountDeactivated) {
+			return apperr.Wrap(fiber.StatusConflict, err)
 		}
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: true, Message: err.Error()})
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(resp)
@@ -40,44 +48,96 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	var req dto.LoginRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: true, Message: "Invalid request body"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
 	}
 
-	resp, err := h.authService.Login(&req)
+	resp, err := h.authService.Login(&req, deviceInfo(c))
 	if err != nil {
 		if errors.Is(err, services.ErrInvalidCredentials) {
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{Error: true, Message: err.Error()})
+			return apperr.Wrap(fiber.StatusUnauthorized, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{Error: true, Message: "Login failed"})
+		if errors.Is(err, services.ErrAccountDeactivated) {
+			return apperr.Wrap(fiber.StatusConflict, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Login failed").WithCause(err)
 	}
 
 	return c.JSON(resp)
 }
 
+// ForgotPassword emails a password reset link. Always answers 200 so the
+// endpoint doesn't reveal which emails have accounts.
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req dto.ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+	req.Locale = requestLocale(c, req.Locale)
+
+	if err := h.authService.ForgotPassword(&req); err != nil {
+		if errors.Is(err, services.ErrInvalidEmailAddress) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to start password reset").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"message": "If an account exists for that email, a reset link has been sent"})
+}
+
+// ResetPassword sets a new password using a token from the reset email
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req dto.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	if err := h.authService.ResetPassword(&req); err != nil {
+		if errors.Is(err, services.ErrInvalidAuthToken) || errors.Is(err, services.ErrPasswordTooShort) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to reset password").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Password has been reset. Please sign in again."})
+}
+
 // ClaimGuest upgrades an authenticated anonymous guest account into a real account.
 func (h *AuthHandler) ClaimGuest(c *fiber.Ctx) error {
 	userID, err := extractUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{Error: true, Message: "Unauthorized"})
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	var req dto.ClaimGuestRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: true, Message: "Invalid request body"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
 	}
 
-	resp, err := h.authService.ClaimGuest(userID, &req)
+	req.Locale = requestLocale(c, req.Locale)
+	resp, err := h.authService.ClaimGuest(userID, &req, deviceInfo(c))
 	if err != nil {
 		if errors.Is(err, services.ErrEmailTaken) {
-			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{Error: true, Message: err.Error()})
+			return apperr.Wrap(fiber.StatusConflict, err)
 		}
 		if errors.Is(err, services.ErrGuestOnlyAction) {
-			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: true, Message: err.Error()})
+			return apperr.Wrap(fiber.StatusBadRequest, err)
 		}
 		if errors.Is(err, services.ErrUserNotFound) {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{Error: true, Message: "User not found"})
+			return apperr.New(fiber.StatusNotFound, "User not found")
 		}
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: true, Message: err.Error()})
+		return err
 	}
 
 	return c.JSON(resp)
@@ -87,15 +147,18 @@ func (h *AuthHandler) ClaimGuest(c *fiber.Ctx) error {
 func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	var req dto.RefreshRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: true, Message: "Invalid request body"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
 	}
 
-	resp, err := h.authService.Refresh(&req)
+	resp, err := h.authService.Refresh(&req, deviceInfo(c))
 	if err != nil {
-		if errors.Is(err, services.ErrInvalidToken) {
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{Error: true, Message: err.Error()})
+		if errors.Is(err, services.ErrInvalidToken) || errors.Is(err, services.ErrTokenReuse) {
+			return apperr.Wrap(fiber.StatusUnauthorized, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{Error: true, Message: "Token refresh failed"})
+		return apperr.New(fiber.StatusInternalServerError, "Token refresh failed").WithCause(err)
 	}
 
 	return c.JSON(resp)
@@ -105,21 +168,61 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 	var req dto.LogoutRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: true, Message: "Invalid request body"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
 	}
 
 	if err := h.authService.Logout(&req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{Error: true, Message: "Logout failed"})
+		return apperr.New(fiber.StatusInternalServerError, "Logout failed").WithCause(err)
 	}
 
 	return c.JSON(fiber.Map{"message": "Logged out successfully"})
 }
 
+// ListSessions returns the user's signed-in devices
+func (h *AuthHandler) ListSessions(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	sessions, err := h.authService.ListSessions(userID, extractSessionID(c))
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch sessions").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"sessions": sessions})
+}
+
+// RevokeSession signs out one device, e.g. a lost phone
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	sessionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid session ID")
+	}
+
+	if err := h.authService.RevokeSession(userID, sessionID); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to revoke session").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Session revoked"})
+}
+
 // DeleteAccount implements Apple Guideline 5.1.1
 func (h *AuthHandler) DeleteAccount(c *fiber.Ctx) error {
 	userID, err := extractUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{Error: true, Message: "Unauthorized"})
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	var body struct {
@@ -127,48 +230,273 @@ func (h *AuthHandler) DeleteAccount(c *fiber.Ctx) error {
 	}
 	c.BodyParser(&body)
 
-	if err := h.authService.DeleteAccount(userID, body.Password); err != nil {
+	purgeAt, err := h.authService.DeleteAccount(userID, body.Password, deviceInfo(c))
+	if err != nil {
 		if errors.Is(err, services.ErrInvalidCredentials) {
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{Error: true, Message: "Invalid password"})
+			return apperr.New(fiber.StatusUnauthorized, "Invalid password")
 		}
 		if errors.Is(err, services.ErrUserNotFound) {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{Error: true, Message: "User not found"})
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to delete account").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message":  "Account deactivated. It will be permanently deleted unless restored before purge_at.",
+		"purge_at": purgeAt,
+	})
+}
+
+// RestoreAccount reactivates an account within its deletion grace period
+func (h *AuthHandler) RestoreAccount(c *fiber.Ctx) error {
+	var req dto.RestoreAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	resp, err := h.authService.RestoreAccount(&req, deviceInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCredentials):
+			return apperr.Wrap(fiber.StatusUnauthorized, err)
+		case errors.Is(err, services.ErrNothingToRestore):
+			return apperr.Wrap(fiber.StatusNotFound, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{Error: true, Message: "Failed to delete account"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to restore account").WithCause(err)
 	}
 
-	return c.JSON(fiber.Map{"message": "Account deleted successfully"})
+	return c.JSON(resp)
 }
 
 // AppleSignIn handles Sign in with Apple (Guideline 4.8)
 func (h *AuthHandler) AppleSignIn(c *fiber.Ctx) error {
 	var req dto.AppleSignInRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: true, Message: "Invalid request body"})
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
 	}
 
-	resp, err := h.authService.AppleSignIn(&req)
+	resp, err := h.authService.AppleSignIn(&req, deviceInfo(c))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: true, Message: err.Error()})
+		if errors.Is(err, services.ErrAccountDeactivated) {
+			return apperr.Wrap(fiber.StatusConflict, err)
+		}
+		return err
 	}
 
 	return c.JSON(resp)
 }
 
+// VerifyEmail confirms the user's address from the emailed link
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req dto.VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	user, err := h.authService.VerifyEmail(&req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAuthToken) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		if errors.Is(err, services.ErrUserNotFound) {
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to verify email").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Email verified", "user": user})
+}
+
+// ResendVerification mails a new verification link to the signed-in user
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.ResendVerificationRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+		}
+		if fields := validateRequest(&req); fields != nil {
+			return validationFailed(fields)
+		}
+	}
+
+	if err := h.authService.ResendVerification(userID, requestLocale(c, req.Locale)); err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmailAlreadyVerified):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		case errors.Is(err, services.ErrGuestOnlyAction):
+			return apperr.New(fiber.StatusBadRequest, "Guest accounts must be claimed before verifying an email")
+		case errors.Is(err, services.ErrUserNotFound):
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to send verification email").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Verification email sent"})
+}
+
+// EmailVerified reports whether a user has verified their email (used by
+// the RequireVerifiedEmail middleware)
+func (h *AuthHandler) EmailVerified(userID uuid.UUID) bool {
+	return h.authService.IsEmailVerified(userID)
+}
+
+// AccessTokenValid reports whether the account still accepts a token of the
+// session issued at issuedAt (used by the JWTProtected middleware)
+func (h *AuthHandler) AccessTokenValid(userID, sessionID uuid.UUID, issuedAt time.Time) bool {
+	return h.authService.AccessTokenValid(userID, sessionID, issuedAt)
+}
+
+// AccountEmail resolves the user's email for admin checks.
+func (h *AuthHandler) AccountEmail(userID uuid.UUID) string {
+	return h.authService.AccountEmail(userID)
+}
+
+// ChangeEmail starts an email change: the current password is required and
+// the new address must be confirmed via the emailed link
+func (h *AuthHandler) ChangeEmail(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.ChangeEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+	req.Locale = requestLocale(c, req.Locale)
+
+	pending, err := h.authService.RequestEmailChange(userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCredentials):
+			return apperr.New(fiber.StatusUnauthorized, "Current password is incorrect")
+		case errors.Is(err, services.ErrEmailTaken):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		case errors.Is(err, services.ErrInvalidEmailAddress), errors.Is(err, services.ErrEmailUnchanged),
+			errors.Is(err, services.ErrEmailSuppressed), errors.Is(err, services.ErrGuestOnlyAction):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		case errors.Is(err, services.ErrUserNotFound):
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to start email change").WithCause(err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message":       "Check your new inbox to confirm the change",
+		"pending_email": pending,
+	})
+}
+
+// ConfirmEmailChange applies a pending email change from the confirmation link
+func (h *AuthHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	var req dto.ConfirmEmailChangeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	user, err := h.authService.ConfirmEmailChange(&req, requestLocale(c, ""))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidAuthToken):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		case errors.Is(err, services.ErrEmailTaken):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		case errors.Is(err, services.ErrUserNotFound):
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to confirm email change").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Email updated", "user": user})
+}
+
+// UpdateTimezone sets the IANA timezone used for the user's daily scan limit and streaks
+func (h *AuthHandler) UpdateTimezone(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.UpdateTimezoneRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	timezone, err := h.authService.UpdateTimezone(userID, req.Timezone)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTimezone) {
+			return apperr.New(fiber.StatusBadRequest, "Timezone must be a valid IANA name, e.g. Europe/Istanbul")
+		}
+		if errors.Is(err, services.ErrUserNotFound) {
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		if errors.Is(err, services.ErrTimezoneCooldown) {
+			return err
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to update timezone").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"timezone": timezone})
+}
+
 // GetProfile retrieves the user's profile information
 func (h *AuthHandler) GetProfile(c *fiber.Ctx) error {
 	userID, err := extractUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{Error: true, Message: "Unauthorized"})
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	profile, err := h.authService.GetProfile(userID)
 	if err != nil {
 		if errors.Is(err, services.ErrUserNotFound) {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{Error: true, Message: "User not found"})
+			return apperr.New(fiber.StatusNotFound, "User not found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{Error: true, Message: "Failed to fetch profile"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch profile").WithCause(err)
 	}
 
 	return c.JSON(profile)
 }
+
+// requestLocale prefers an explicit locale and falls back to the first
+// Accept-Language tag, e.g. "tr-TR,tr;q=0.9" -> "tr-TR".
+func requestLocale(c *fiber.Ctx, explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	locale, _, _ := strings.Cut(c.Get(fiber.HeaderAcceptLanguage), ",")
+	return strings.TrimSpace(locale)
+}
+
+// deviceInfo collects the session metadata stored with refresh tokens.
+func deviceInfo(c *fiber.Ctx) dto.DeviceInfo {
+	return dto.DeviceInfo{
+		Name:       c.Get("X-Device-Name"),
+		Platform:   c.Get("X-Platform"),
+		AppVersion: c.Get("X-App-Version"),
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+		IP:         c.IP(),
+	}
+}
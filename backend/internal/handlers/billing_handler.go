@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// BillingHandler serves plan entitlements and Stripe billing for web users.
+type BillingHandler struct {
+	stripeService      *services.StripeService
+	entitlementService *services.EntitlementService
+}
+
+func NewBillingHandler(stripeService *services.StripeService, entitlementService *services.EntitlementService) *BillingHandler {
+	return &BillingHandler{stripeService: stripeService, entitlementService: entitlementService}
+}
+
+// GetEntitlements returns what the user's plan includes and the limits of every tier
+func (h *BillingHandler) GetEntitlements(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	return c.JSON(h.entitlementService.Response(c.UserContext(), userID))
+}
+
+// CreateCheckout returns a Stripe Checkout URL for a web subscription
+func (h *BillingHandler) CreateCheckout(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.CheckoutSessionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+		}
+		if fields := validateRequest(&req); fields != nil {
+			return validationFailed(fields)
+		}
+	}
+
+	url, err := h.stripeService.CreateCheckoutSession(c.UserContext(), userID, req.PriceID)
+	if err != nil {
+		return billingError(c, err)
+	}
+	return c.JSON(dto.BillingSessionResponse{URL: url})
+}
+
+// CreatePortal returns a Stripe customer portal URL for managing the web subscription
+func (h *BillingHandler) CreatePortal(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	url, err := h.stripeService.PortalURL(c.UserContext(), userID)
+	if err != nil {
+		return billingError(c, err)
+	}
+	return c.JSON(dto.BillingSessionResponse{URL: url})
+}
+
+func billingError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrStripeDisabled):
+		return apperr.Wrap(fiber.StatusServiceUnavailable, err)
+	case errors.Is(err, services.ErrUnknownPrice):
+		return apperr.Wrap(fiber.StatusBadRequest, err)
+	case errors.Is(err, services.ErrAlreadySubscribed):
+		return apperr.Wrap(fiber.StatusConflict, err)
+	case errors.Is(err, services.ErrNoStripeCustomer), errors.Is(err, services.ErrUserNotFound):
+		return apperr.Wrap(fiber.StatusNotFound, err)
+	}
+	log.Printf("billing: %v", err)
+	return apperr.New(fiber.StatusBadGateway, "Billing provider unavailable, please try again")
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/cache"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CacheHandler lets admins purge the response cache and the CDN after
+// editing content.
+type CacheHandler struct {
+	store *cache.Store
+	cdn   services.CDN
+}
+
+func NewCacheHandler(store *cache.Store, cdn services.CDN) *CacheHandler {
+	return &CacheHandler{store: store, cdn: cdn}
+}
+
+// Purge drops cached responses carrying any of ?tag=a,b, or everything when
+// no tag is given, here and at the CDN
+func (h *CacheHandler) Purge(c *fiber.Ctx) error {
+	var tags []string
+	for _, tag := range strings.Split(c.Query("tag"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	var purged int
+	var err error
+	if len(tags) == 0 {
+		purged = h.store.PurgeAll()
+		err = h.cdn.PurgeAll(c.UserContext())
+	} else {
+		purged = h.store.Purge(tags...)
+		err = h.cdn.PurgeTags(c.UserContext(), tags)
+	}
+	if err != nil {
+		log.Printf("cdn(%s): purge %v: %v", h.cdn.Name(), tags, err)
+		return apperr.New(fiber.StatusBadGateway, "Local cache purged, but the CDN purge failed").WithCause(err)
+	}
+	return c.JSON(fiber.Map{"purged": purged, "cdn": h.cdn.Name()})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContactDiscoveryHandler matches hashed address books to AuraSnap users
+type ContactDiscoveryHandler struct {
+	discoveryService *services.ContactDiscoveryService
+}
+
+// NewContactDiscoveryHandler creates a new ContactDiscoveryHandler instance
+func NewContactDiscoveryHandler(discoveryService *services.ContactDiscoveryService) *ContactDiscoveryHandler {
+	return &ContactDiscoveryHandler{discoveryService: discoveryService}
+}
+
+// Salt returns the hashing parameters clients must use before uploading
+func (h *ContactDiscoveryHandler) Salt(c *fiber.Ctx) error {
+	return c.JSON(h.discoveryService.Salt())
+}
+
+// Discover returns opted-in users matching the uploaded contact hashes
+func (h *ContactDiscoveryHandler) Discover(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.ContactDiscoverRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	matches, err := h.discoveryService.Discover(userID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyContacts) || errors.Is(err, services.ErrInvalidContactHash) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to match contacts").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"matches": matches})
+}
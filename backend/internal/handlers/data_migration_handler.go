@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DataMigrationHandler is the admin dashboard for online data migrations
+type DataMigrationHandler struct {
+	migrationService *services.DataMigrationService
+}
+
+// NewDataMigrationHandler creates a new DataMigrationHandler instance
+func NewDataMigrationHandler(migrationService *services.DataMigrationService) *DataMigrationHandler {
+	return &DataMigrationHandler{migrationService: migrationService}
+}
+
+// List returns every data migration with its phase and progress
+func (h *DataMigrationHandler) List(c *fiber.Ctx) error {
+	migrations, err := h.migrationService.List()
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch data migrations").WithCause(err)
+	}
+	return c.JSON(fiber.Map{"migrations": migrations})
+}
+
+// SetPhase moves a data migration forward or back
+func (h *DataMigrationHandler) SetPhase(c *fiber.Ctx) error {
+	adminID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.DataMigrationPhaseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	status, err := h.migrationService.SetPhase(adminID, c.Params("name"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMigrationNotFound):
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		case errors.Is(err, services.ErrInvalidMigrationPhase):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		case errors.Is(err, services.ErrMigrationPhaseBlocked):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to change migration phase").WithCause(err)
+	}
+	return c.JSON(status)
+}
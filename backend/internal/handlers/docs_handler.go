@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/api"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/gofiber/fiber/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// swaggerUIVersion pins the Swagger UI bundle loaded from unpkg.
+const swaggerUIVersion = "5.17.14"
+
+var docsPage = `<!DOCTYPE html><html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width,initial-scale=1">
+<title>AuraSnap API</title><link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css"></head>
+<body><div id="swagger-ui"></div><script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js"></script>
+<script>window.ui = SwaggerUIBundle({url: "docs/openapi.json", dom_id: "#swagger-ui", persistAuthorization: true});</script></body></html>`
+
+// DocsHandler serves the client API description (api/openapi.yaml) and a
+// Swagger UI for it. The spec is written by hand alongside the routes and
+// DTOs, which TestOpenAPIPathsAreRouted keeps honest; SDKs are generated
+// from the same file.
+type DocsHandler struct {
+	jsonOnce sync.Once
+	json     []byte
+	jsonErr  error
+}
+
+func NewDocsHandler() *DocsHandler { return &DocsHandler{} }
+
+// UI serves Swagger UI pointed at the JSON spec
+func (h *DocsHandler) UI(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(docsPage)
+}
+
+// SpecYAML serves the spec as written
+func (h *DocsHandler) SpecYAML(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, "application/yaml")
+	return c.Send(api.OpenAPI)
+}
+
+// SpecJSON serves the spec converted to JSON, for tools that only read JSON
+func (h *DocsHandler) SpecJSON(c *fiber.Ctx) error {
+	h.jsonOnce.Do(func() {
+		var spec any
+		if h.jsonErr = yaml.Unmarshal(api.OpenAPI, &spec); h.jsonErr == nil {
+			h.json, h.jsonErr = json.Marshal(spec)
+		}
+	})
+	if h.jsonErr != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to convert the API description").WithCause(h.jsonErr)
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(h.json)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/emails"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EmailHandler exposes admin tools for previewing and test-sending emails
+type EmailHandler struct {
+	emailService *services.EmailService
+}
+
+// NewEmailHandler creates a new EmailHandler instance
+func NewEmailHandler(emailService *services.EmailService) *EmailHandler {
+	return &EmailHandler{emailService: emailService}
+}
+
+// ListTemplates returns template names with their available locales
+func (h *EmailHandler) ListTemplates(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"templates": h.emailService.Templates()})
+}
+
+// Preview renders a template. GET uses sample data; POST accepts {locale, data}.
+// ?format=html returns the raw page so designers can open it in a browser.
+func (h *EmailHandler) Preview(c *fiber.Ctx) error {
+	req := dto.EmailPreviewRequest{Locale: c.Query("locale")}
+	if c.Method() == fiber.MethodPost {
+		if err := c.BodyParser(&req); err != nil {
+			return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+		}
+		if fields := validateRequest(&req); fields != nil {
+			return validationFailed(fields)
+		}
+	}
+
+	msg, err := h.emailService.Preview(c.Params("name"), req.Locale, req.Data)
+	if err != nil {
+		return h.emailError(c, err)
+	}
+
+	switch c.Query("format") {
+	case "html":
+		c.Set("Content-Type", "text/html; charset=utf-8")
+		return c.SendString(msg.HTML)
+	case "text":
+		return c.SendString(msg.Text)
+	}
+	return c.JSON(dto.EmailPreviewResponse{Subject: msg.Subject, HTML: msg.HTML, Text: msg.Text})
+}
+
+// SendTest delivers a rendered template to the given address
+func (h *EmailHandler) SendTest(c *fiber.Ctx) error {
+	var req dto.EmailSendTestRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	if err := h.emailService.SendTest(req.To, req.Template, req.Locale, req.Data); err != nil {
+		return h.emailError(c, err)
+	}
+
+	return c.JSON(fiber.Map{"sent": true, "to": req.To})
+}
+
+func (h *EmailHandler) emailError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, emails.ErrTemplateNotFound):
+		return apperr.Wrap(fiber.StatusNotFound, err)
+	case errors.Is(err, services.ErrInvalidEmailAddress), errors.Is(err, services.ErrEmailSuppressed):
+		return apperr.Wrap(fiber.StatusBadRequest, err)
+	}
+	return apperr.New(fiber.StatusBadGateway, "Failed to render or send email").WithCause(err)
+}
+
+// ListSuppressions returns addresses blocked after bounces or complaints
+func (h *EmailHandler) ListSuppressions(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	rows, total, err := h.emailService.ListSuppressions(limit, offset)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch suppressions").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"suppressions": rows, "total": total, "limit": limit, "offset": offset})
+}
+
+// DeleteSuppression re-enables sending to an address
+func (h *EmailHandler) DeleteSuppression(c *fiber.Ctx) error {
+	if err := h.emailService.Unsuppress(c.Params("email")); err != nil {
+		if errors.Is(err, services.ErrSuppressionNotFound) {
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to delete suppression").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Suppression removed"})
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// serviceErrors maps the services' sentinel errors to the status and code
+// they are reported with when a handler returns them, or wraps them with
+// apperr.Wrap. Codes are part of the API: add new ones freely, but never
+// rename one the apps may branch on.
+var serviceErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{services.ErrBirthYearRequired, fiber.StatusBadRequest, "birth_year_required"},
+	{services.ErrCursorSortUnsupported, fiber.StatusBadRequest, "cursor_sort_unsupported"},
+	{services.ErrDisplayNameProfanity, fiber.StatusBadRequest, "display_name_profanity"},
+	{services.ErrEmailSuppressed, fiber.StatusBadRequest, "email_suppressed"},
+	{services.ErrEmailUnchanged, fiber.StatusBadRequest, "email_unchanged"},
+	{services.ErrEventQuarantined, fiber.StatusBadRequest, "event_quarantined"},
+	{services.ErrFriendNoAuraReading, fiber.StatusBadRequest, "friend_no_aura_reading"},
+	{services.ErrGuestOnlyAction, fiber.StatusBadRequest, "guest_only_action"},
+	{services.ErrHandleProfanity, fiber.StatusBadRequest, "handle_profanity"},
+	{services.ErrHandleReserved, fiber.StatusBadRequest, "handle_reserved"},
+	{services.ErrInvalidAction, fiber.StatusBadRequest, "invalid_action"},
+	{services.ErrInvalidAdminMessage, fiber.StatusBadRequest, "invalid_admin_message"},
+	{services.ErrInvalidAppeal, fiber.StatusBadRequest, "invalid_appeal"},
+	{services.ErrInvalidAppealReply, fiber.StatusBadRequest, "invalid_appeal_reply"},
+	{services.ErrInvalidAppleToken, fiber.StatusBadRequest, "invalid_apple_token"},
+	{services.ErrInvalidAuditFilter, fiber.StatusBadRequest, "invalid_audit_filter"},
+	{services.ErrInvalidAuthToken, fiber.StatusBadRequest, "invalid_auth_token"},
+	{services.ErrInvalidBirthYear, fiber.StatusBadRequest, "invalid_birth_year"},
+	{services.ErrInvalidBulkCases, fiber.StatusBadRequest, "invalid_bulk_cases"},
+	{services.ErrInvalidBulkDelete, fiber.StatusBadRequest, "invalid_bulk_delete"},
+	{services.ErrInvalidCaseState, fiber.StatusBadRequest, "invalid_case_state"},
+	{services.ErrInvalidContactHash, fiber.StatusBadRequest, "invalid_contact_hash"},
+	{services.ErrInvalidCorrection, fiber.StatusBadRequest, "invalid_correction"},
+	{services.ErrInvalidCursor, fiber.StatusBadRequest, "invalid_cursor"},
+	{services.ErrInvalidDevice, fiber.StatusBadRequest, "invalid_device"},
+	{services.ErrInvalidDisplayName, fiber.StatusBadRequest, "invalid_display_name"},
+	{services.ErrInvalidDuration, fiber.StatusBadRequest, "invalid_duration"},
+	{services.ErrInvalidEmailAddress, fiber.StatusBadRequest, "invalid_email_address"},
+	{services.ErrInvalidExperiment, fiber.StatusBadRequest, "invalid_experiment"},
+	{services.ErrInvalidExportFormat, fiber.StatusBadRequest, "invalid_export_format"},
+	{services.ErrInvalidFriendInvite, fiber.StatusBadRequest, "invalid_friend_invite"},
+	{services.ErrInvalidGrant, fiber.StatusBadRequest, "invalid_grant"},
+	{services.ErrInvalidHandle, fiber.StatusBadRequest, "invalid_handle"},
+	{services.ErrInvalidListFilter, fiber.StatusBadRequest, "invalid_list_filter"},
+	{services.ErrInvalidMatchFriend, fiber.StatusBadRequest, "invalid_match_friend"},
+	{services.ErrInvalidMemory, fiber.StatusBadRequest, "invalid_memory"},
+	{services.ErrInvalidMigrationPhase, fiber.StatusBadRequest, "invalid_migration_phase"},
+	{services.ErrInvalidNote, fiber.StatusBadRequest, "invalid_note"},
+	{services.ErrInvalidNotificationPreference, fiber.StatusBadRequest, "invalid_notification_preference"},
+	{services.ErrInvalidPhone, fiber.StatusBadRequest, "invalid_phone"},
+	{services.ErrInvalidPromptTemplate, fiber.StatusBadRequest, "invalid_prompt_template"},
+	{services.ErrInvalidRating, fiber.StatusBadRequest, "invalid_rating"},
+	{services.ErrInvalidReportStatus, fiber.StatusBadRequest, "invalid_report_status"},
+	{services.ErrInvalidReportType, fiber.StatusBadRequest, "invalid_report_type"},
+	{services.ErrInvalidSNSMessage, fiber.StatusBadRequest, "invalid_sns_message"},
+	{services.ErrInvalidSignUp, fiber.StatusBadRequest, "invalid_sign_up"},
+	{services.ErrInvalidStripeWebhook, fiber.StatusBadRequest, "invalid_stripe_webhook"},
+	{services.ErrInvalidTimezone, fiber.StatusBadRequest, "invalid_timezone"},
+	{services.ErrInvalidWebhookPayload, fiber.StatusBadRequest, "invalid_webhook_payload"},
+	{services.ErrInviteBlocked, fiber.StatusBadRequest, "invite_blocked"},
+	{services.ErrInviteOwnCode, fiber.StatusBadRequest, "invite_own_code"},
+	{services.ErrInviteSelf, fiber.StatusBadRequest, "invite_self"},
+	{services.ErrPasswordTooShort, fiber.StatusBadRequest, "password_too_short"},
+	{services.ErrReportReasonMissing, fiber.StatusBadRequest, "report_reason_missing"},
+	{services.ErrSelfMatch, fiber.StatusBadRequest, "self_match"},
+	{services.ErrStepNotSkippable, fiber.StatusBadRequest, "step_not_skippable"},
+	{services.ErrTooManyContacts, fiber.StatusBadRequest, "too_many_contacts"},
+	{services.ErrUnknownPrice, fiber.StatusBadRequest, "unknown_price"},
+	{services.ErrUntrustedSubscribeURL, fiber.StatusBadRequest, "untrusted_subscribe_u_r_l"},
+
+	{services.ErrInvalidCredentials, fiber.StatusUnauthorized, "invalid_credentials"},
+	{services.ErrInvalidToken, fiber.StatusUnauthorized, "invalid_token"},
+	{services.ErrTokenReuse, fiber.StatusUnauthorized, "token_reuse"},
+
+	{services.ErrPremiumRequired, fiber.StatusPaymentRequired, "premium_required"},
+
+	{services.ErrDiscoveryAgeRestricted, fiber.StatusForbidden, "discovery_age_restricted"},
+	{services.ErrDiscoveryOptInRequired, fiber.StatusForbidden, "discovery_opt_in_required"},
+	{services.ErrDisplayNameLocked, fiber.StatusForbidden, "display_name_locked"},
+	{services.ErrInvalidClearHistory, fiber.StatusForbidden, "invalid_clear_history"},
+	{services.ErrSurveyNotEligible, fiber.StatusForbidden, "survey_not_eligible"},
+	{services.ErrUpgradeRequired, fiber.StatusForbidden, "upgrade_required"},
+
+	{services.ErrActionNotFound, fiber.StatusNotFound, "action_not_found"},
+	{services.ErrAppealNotFound, fiber.StatusNotFound, "appeal_not_found"},
+	{services.ErrCaseNotFound, fiber.StatusNotFound, "case_not_found"},
+	{services.ErrExperimentNotFound, fiber.StatusNotFound, "experiment_not_found"},
+	{services.ErrExportNotFound, fiber.StatusNotFound, "export_not_found"},
+	{services.ErrFriendNotFound, fiber.StatusNotFound, "friend_not_found"},
+	{services.ErrFriendRequestMissing, fiber.StatusNotFound, "friend_request_missing"},
+	{services.ErrGroupReadingNotFound, fiber.StatusNotFound, "group_reading_not_found"},
+	{services.ErrInviteCodeNotFound, fiber.StatusNotFound, "invite_code_not_found"},
+	{services.ErrMemoryNotFound, fiber.StatusNotFound, "memory_not_found"},
+	{services.ErrMigrationNotFound, fiber.StatusNotFound, "migration_not_found"},
+	{services.ErrNoAuraReading, fiber.StatusNotFound, "no_aura_reading"},
+	{services.ErrNoStripeCustomer, fiber.StatusNotFound, "no_stripe_customer"},
+	{services.ErrNothingToRestore, fiber.StatusNotFound, "nothing_to_restore"},
+	{services.ErrNotificationNotFound, fiber.StatusNotFound, "notification_not_found"},
+	{services.ErrPhotoNotFound, fiber.StatusNotFound, "photo_not_found"},
+	{services.ErrProcessedEventNotFound, fiber.StatusNotFound, "processed_event_not_found"},
+	{services.ErrPromptTemplateNotFound, fiber.StatusNotFound, "prompt_template_not_found"},
+	{services.ErrReadingNotFound, fiber.StatusNotFound, "reading_not_found"},
+	{services.ErrReportNotFound, fiber.StatusNotFound, "report_not_found"},
+	{services.ErrRequestNotFound, fiber.StatusNotFound, "request_not_found"},
+	{services.ErrScanBatchNotFound, fiber.StatusNotFound, "scan_batch_not_found"},
+	{services.ErrScanJobNotFound, fiber.StatusNotFound, "scan_job_not_found"},
+	{services.ErrSessionNotFound, fiber.StatusNotFound, "session_not_found"},
+	{services.ErrShareTokenInvalid, fiber.StatusNotFound, "share_token_invalid"},
+	{services.ErrSuppressionNotFound, fiber.StatusNotFound, "suppression_not_found"},
+	{services.ErrSurveyNotFound, fiber.StatusNotFound, "survey_not_found"},
+	{services.ErrTicketNotFound, fiber.StatusNotFound, "ticket_not_found"},
+	{services.ErrUnknownOnboardingStep, fiber.StatusNotFound, "unknown_onboarding_step"},
+	{services.ErrUserNotFound, fiber.StatusNotFound, "user_not_found"},
+	{services.ErrWebhookEventNotFound, fiber.StatusNotFound, "webhook_event_not_found"},
+
+	{services.ErrAccountDeactivated, fiber.StatusConflict, "account_deactivated"},
+	{services.ErrActionRevoked, fiber.StatusConflict, "action_revoked"},
+	{services.ErrAlreadyBlocked, fiber.StatusConflict, "already_blocked"},
+	{services.ErrAlreadySubscribed, fiber.StatusConflict, "already_subscribed"},
+	{services.ErrAppealExists, fiber.StatusConflict, "appeal_exists"},
+	{services.ErrAppealReviewed, fiber.StatusConflict, "appeal_reviewed"},
+	{services.ErrDiscoveryRegionUnknown, fiber.StatusConflict, "discovery_region_unknown"},
+	{services.ErrDisplayNameTaken, fiber.StatusConflict, "display_name_taken"},
+	{services.ErrEmailAlreadyVerified, fiber.StatusConflict, "email_already_verified"},
+	{services.ErrEmailTaken, fiber.StatusConflict, "email_taken"},
+	{services.ErrEventNotQuarantined, fiber.StatusConflict, "event_not_quarantined"},
+	{services.ErrExperimentAlreadyRunning, fiber.StatusConflict, "experiment_already_running"},
+	{services.ErrExperimentKeyTaken, fiber.StatusConflict, "experiment_key_taken"},
+	{services.ErrExperimentNotDraft, fiber.StatusConflict, "experiment_not_draft"},
+	{services.ErrExperimentNotRunning, fiber.StatusConflict, "experiment_not_running"},
+	{services.ErrExportNotReady, fiber.StatusConflict, "export_not_ready"},
+	{services.ErrGroupReadingRegenerate, fiber.StatusConflict, "group_reading_regenerate"},
+	{services.ErrHandleTaken, fiber.StatusConflict, "handle_taken"},
+	{services.ErrMemoryLimitReached, fiber.StatusConflict, "memory_limit_reached"},
+	{services.ErrMigrationPhaseBlocked, fiber.StatusConflict, "migration_phase_blocked"},
+	{services.ErrPhotoArchived, fiber.StatusConflict, "photo_archived"},
+	{services.ErrPromptTemplateInUse, fiber.StatusConflict, "prompt_template_in_use"},
+	{services.ErrReadingNotIndexed, fiber.StatusConflict, "reading_not_indexed"},
+	{services.ErrReadingPending, fiber.StatusConflict, "reading_pending"},
+	{services.ErrSelfBlock, fiber.StatusConflict, "self_block"},
+	{services.ErrSurveyAlreadyAnswered, fiber.StatusConflict, "survey_already_answered"},
+	{services.ErrTooManyPins, fiber.StatusConflict, "too_many_pins"},
+	{services.ErrWebhookNotDead, fiber.StatusConflict, "webhook_not_dead"},
+
+	{services.ErrInviteCodeInvalid, fiber.StatusGone, "invite_code_invalid"},
+	{services.ErrPhotoEvicted, fiber.StatusGone, "photo_evicted"},
+	{services.ErrShareTokenExpired, fiber.StatusGone, "share_token_expired"},
+
+	{services.ErrImageRejected, fiber.StatusUnprocessableEntity, "image_rejected"},
+	{services.ErrNoFaceDetected, fiber.StatusUnprocessableEntity, "no_face_detected"},
+	{services.ErrNotAGroupPhoto, fiber.StatusUnprocessableEntity, "not_a_group_photo"},
+
+	{services.ErrHandleCooldown, fiber.StatusTooManyRequests, "handle_cooldown"},
+	{services.ErrMatchLimitReached, fiber.StatusTooManyRequests, "match_limit_reached"},
+	{services.ErrTimezoneCooldown, fiber.StatusTooManyRequests, "timezone_cooldown"},
+	{services.ErrTooManyInviteCodes, fiber.StatusTooManyRequests, "too_many_invite_codes"},
+	{services.ErrTooManyInvites, fiber.StatusTooManyRequests, "too_many_invites"},
+
+	{services.ErrClearHistoryDisabled, fiber.StatusServiceUnavailable, "clear_history_disabled"},
+	{services.ErrGroupScanUnavailable, fiber.StatusServiceUnavailable, "group_scan_unavailable"},
+	{services.ErrImageModerationUnavailable, fiber.StatusServiceUnavailable, "image_moderation_unavailable"},
+	{services.ErrObjectStoreDisabled, fiber.StatusServiceUnavailable, "object_store_disabled"},
+	{services.ErrRegenerateUnavailable, fiber.StatusServiceUnavailable, "regenerate_unavailable"},
+	{services.ErrShareNotConfigured, fiber.StatusServiceUnavailable, "share_not_configured"},
+	{services.ErrStripeDisabled, fiber.StatusServiceUnavailable, "stripe_disabled"},
+	{services.ErrTraitIndexNotReady, fiber.StatusServiceUnavailable, "trait_index_not_ready"},
+	{services.ErrVectorSearchUnavailable, fiber.StatusServiceUnavailable, "vector_search_unavailable"},
+}
+
+func init() {
+	for _, e := range serviceErrors {
+		apperr.Register(e.err, e.status, e.code)
+	}
+}
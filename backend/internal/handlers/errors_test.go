@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+var errorCodePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+func TestServiceErrorCodes(t *testing.T) {
+	seen := map[string]bool{}
+	for _, e := range serviceErrors {
+		if !errorCodePattern.MatchString(e.code) || seen[e.code] {
+			t.Errorf("%v: code %q is malformed or taken", e.err, e.code)
+		}
+		seen[e.code] = true
+		if e.status < fiber.StatusBadRequest || e.status >= fiber.StatusInternalServerError && e.status != fiber.StatusServiceUnavailable {
+			t.Errorf("%v: status %d", e.err, e.status)
+		}
+	}
+
+	got, public := apperr.From(fmt.Errorf("%w: nothing to change", services.ErrInvalidCorrection))
+	if !public || got.Status != fiber.StatusBadRequest || got.Code != "invalid_correction" || got.Message != "invalid correction: nothing to change" {
+		t.Fatalf("wrapped sentinel: %+v", got)
+	}
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ExperimentHandler serves the admin tools for A/B experiments on readings
+type ExperimentHandler struct {
+	experimentService *services.ExperimentService
+}
+
+func NewExperimentHandler(experimentService *services.ExperimentService) *ExperimentHandler {
+	return &ExperimentHandler{experimentService: experimentService}
+}
+
+// List returns every experiment (admin)
+func (h *ExperimentHandler) List(c *fiber.Ctx) error {
+	experiments, err := h.experimentService.List()
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"experiments": experiments})
+}
+
+// Get returns one experiment (admin)
+func (h *ExperimentHandler) Get(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid experiment ID")
+	}
+
+	experiment, err := h.experimentService.Get(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(experiment)
+}
+
+// Create defines a draft experiment (admin)
+func (h *ExperimentHandler) Create(c *fiber.Ctx) error {
+	adminID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.ExperimentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	experiment, err := h.experimentService.Create(adminID, &req)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(experiment)
+}
+
+// Update replaces a draft experiment's definition (admin)
+func (h *ExperimentHandler) Update(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid experiment ID")
+	}
+
+	var req dto.ExperimentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	experiment, err := h.experimentService.Update(id, &req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(experiment)
+}
+
+// Start begins assigning scanning users to the variants (admin)
+func (h *ExperimentHandler) Start(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid experiment ID")
+	}
+
+	experiment, err := h.experimentService.Start(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(experiment)
+}
+
+// Stop ends a running experiment (admin)
+func (h *ExperimentHandler) Stop(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid experiment ID")
+	}
+
+	experiment, err := h.experimentService.Stop(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(experiment)
+}
+
+// Delete removes a draft experiment (admin)
+func (h *ExperimentHandler) Delete(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid experiment ID")
+	}
+
+	if err := h.experimentService.Delete(id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Results breaks engagement and conversion down by variant (admin)
+func (h *ExperimentHandler) Results(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid experiment ID")
+	}
+
+	results, err := h.experimentService.Results(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(results)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ExportHandler handles personal data export requests and downloads
+type ExportHandler struct {
+	exportService *services.DataExportService
+}
+
+// NewExportHandler creates a new ExportHandler instance
+func NewExportHandler(exportService *services.DataExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// RequestExport returns the user's current export, queueing a new one when
+// none is pending or ready. ?format=json|zip, ?refresh=true forces a rebuild.
+func (h *ExportHandler) RequestExport(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	export, created, err := h.exportService.RequestExport(userID, c.Query("format"), c.QueryBool("refresh"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidExportFormat) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to request export").WithCause(err)
+	}
+
+	resp := services.ExportResponse(export, c.BaseURL())
+	if created || resp.DownloadURL == "" {
+		return c.Status(fiber.StatusAccepted).JSON(resp)
+	}
+	return c.JSON(resp)
+}
+
+// Download streams a ready export file
+func (h *ExportHandler) Download(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	exportID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid export ID")
+	}
+
+	path, name, err := h.exportService.OpenDownload(userID, exportID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrExportNotFound):
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		case errors.Is(err, services.ErrExportNotReady):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch export").WithCause(err)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.Download(path, name)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ForecastHandler serves the personalized daily aura forecast
+type ForecastHandler struct {
+	forecastService *services.ForecastService
+}
+
+// NewForecastHandler creates a new ForecastHandler instance
+func NewForecastHandler(forecastService *services.ForecastService) *ForecastHandler {
+	return &ForecastHandler{forecastService: forecastService}
+}
+
+// GetToday returns the forecast for the user's current local day
+func (h *ForecastHandler) GetToday(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	forecast, err := h.forecastService.Today(c.UserContext(), userID, c.Get(timezoneHeader))
+	if err != nil {
+		if errors.Is(err, services.ErrUpgradeRequired) {
+			return apperr.New(fiber.StatusForbidden, "Daily forecasts are included in Plus and Pro")
+		}
+		if errors.Is(err, services.ErrNoAuraReading) {
+			return apperr.New(fiber.StatusNotFound, "Scan your aura first to unlock daily forecasts")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch forecast").WithCause(err)
+	}
+
+	return c.JSON(forecast)
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// FriendHandler handles friends, friend requests and QR/deep-link invite codes
+type FriendHandler struct {
+	friendService *services.FriendService
+}
+
+// NewFriendHandler creates a new FriendHandler instance
+func NewFriendHandler(friendService *services.FriendService) *FriendHandler {
+	return &FriendHandler{friendService: friendService}
+}
+
+// ListFriends returns the user's accepted friends
+func (h *FriendHandler) ListFriends(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	friends, err := h.friendService.ListFriends(userID)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch friends").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"friends": friends})
+}
+
+// CreateInviteCode issues a short-lived invite code with deep link and QR URL
+func (h *FriendHandler) CreateInviteCode(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.CreateInviteCodeRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+		}
+		if fields := validateRequest(&req); fields != nil {
+			return validationFailed(fields)
+		}
+	}
+
+	invite, err := h.friendService.CreateInviteCode(userID, &req, c.BaseURL())
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyInviteCodes) {
+			return apperr.Wrap(fiber.StatusTooManyRequests, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to create invite code").WithCause(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(invite)
+}
+
+// ListInviteCodes returns the user's redeemable invite codes
+func (h *FriendHandler) ListInviteCodes(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	invites, err := h.friendService.ListInviteCodes(userID, c.BaseURL())
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch invite codes").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"invite_codes": invites})
+}
+
+// InviteQRCode renders the invite deep link as a PNG QR code
+func (h *FriendHandler) InviteQRCode(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	png, err := h.friendService.InviteQRCode(userID, c.Params("code"))
+	if err != nil {
+		if errors.Is(err, services.ErrInviteCodeNotFound) {
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to render QR code").WithCause(err)
+	}
+
+	c.Set(fiber.HeaderContentType, "image/png")
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Send(png)
+}
+
+// RevokeInviteCode disables one of the user's invite codes
+func (h *FriendHandler) RevokeInviteCode(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	if err := h.friendService.RevokeInviteCode(userID, c.Params("code")); err != nil {
+		if errors.Is(err, services.ErrInviteCodeNotFound) {
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to revoke invite code").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Invite code revoked"})
+}
+
+// RedeemInviteCode makes the caller friends with the code's owner
+func (h *FriendHandler) RedeemInviteCode(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	friend, err := h.friendService.RedeemInviteCode(userID, c.Params("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInviteCodeNotFound):
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		case errors.Is(err, services.ErrInviteCodeInvalid):
+			return apperr.Wrap(fiber.StatusGone, err)
+		case errors.Is(err, services.ErrInviteOwnCode), errors.Is(err, services.ErrInviteBlocked):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to redeem invite code").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"friend": friend})
+}
+
+// Invite sends a friend invitation by invite code, email or user ID
+func (h *FriendHandler) Invite(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.FriendInviteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	// Emailed invites go out in the inviter's language.
+	resp, err := h.friendService.Invite(userID, &req, requestLocale(c, ""))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInviteCodeNotFound), errors.Is(err, services.ErrFriendNotFound):
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		case errors.Is(err, services.ErrInviteCodeInvalid):
+			return apperr.Wrap(fiber.StatusGone, err)
+		case errors.Is(err, services.ErrTooManyInvites):
+			return apperr.Wrap(fiber.StatusTooManyRequests, err)
+		case errors.Is(err, services.ErrInvalidFriendInvite), errors.Is(err, services.ErrInvalidEmailAddress),
+			errors.Is(err, services.ErrInviteSelf), errors.Is(err, services.ErrInviteOwnCode), errors.Is(err, services.ErrInviteBlocked):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to send invite").WithCause(err)
+	}
+
+	return c.JSON(resp)
+}
+
+// ListRequests returns pending incoming and outgoing friend requests
+func (h *FriendHandler) ListRequests(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	requests, err := h.friendService.ListRequests(userID)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch friend requests").WithCause(err)
+	}
+
+	return c.JSON(requests)
+}
+
+// AcceptRequest accepts an incoming friend request
+func (h *FriendHandler) AcceptRequest(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	requestID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request ID")
+	}
+
+	friend, err := h.friendService.AcceptRequest(userID, requestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrFriendRequestMissing):
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		case errors.Is(err, services.ErrInviteBlocked):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to accept friend request").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"friend": friend})
+}
+
+// DeclineRequest declines an incoming friend request
+func (h *FriendHandler) DeclineRequest(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	requestID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request ID")
+	}
+
+	if err := h.friendService.DeclineRequest(userID, requestID); err != nil {
+		if errors.Is(err, services.ErrFriendRequestMissing) {
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to decline friend request").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Friend request declined"})
+}
+
+// Unfriend removes a friend or withdraws an outgoing request
+func (h *FriendHandler) Unfriend(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	friendID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid friend ID")
+	}
+
+	if err := h.friendService.Unfriend(userID, friendID); err != nil {
+		if errors.Is(err, services.ErrFriendNotFound) {
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to remove friend").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Friend removed"})
+}
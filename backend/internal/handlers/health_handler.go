@@ -1,23 +1,30 @@
 package handlers
 
 import (
+	"log"
 	"time"
 
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/buildinfo"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
 )
 
-type HealthHandler struct{}
+type HealthHandler struct {
+	healthService *services.HealthService
+}
 
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(healthService *services.HealthService) *HealthHandler {
+	return &HealthHandler{healthService: healthService}
 }
 
 func (h *HealthHandler) Check(c *fiber.Ctx) error {
 	dbStatus := "ok"
 	if err := database.Ping(); err != nil {
-		dbStatus = "unhealthy: " + err.Error()
+		// The endpoint is public; the cause goes to the logs only.
+		log.Printf("health: database ping failed: %v", err)
+		dbStatus = "unhealthy"
 	}
 
 	return c.JSON(dto.HealthResponse{
@@ -26,3 +33,45 @@ func (h *HealthHandler) Check(c *fiber.Ctx) error {
 		DB:        dbStatus,
 	})
 }
+
+// Live reports that the process is serving requests, without checking any
+// dependency, so a slow database never gets the instance restarted.
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return c.JSON(dto.LivenessResponse{
+		Status:    services.HealthOK,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// Ready probes the dependencies and answers 503 when a critical one is
+// down or the instance is draining, so load balancers stop routing to it.
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	result := h.healthService.Ready(c.UserContext())
+	if result.Status == services.HealthUnavailable || result.Status == services.HealthDraining {
+		c.Status(fiber.StatusServiceUnavailable)
+	}
+	return c.JSON(result)
+}
+
+// Drain is the pre-stop deploy hook: it marks the instance draining, so
+// readiness fails and the load balancer moves traffic away before the
+// process gets SIGTERM.
+func (h *HealthHandler) Drain(c *fiber.Ctx) error {
+	h.healthService.Drain()
+	return c.Status(fiber.StatusAccepted).JSON(dto.LivenessResponse{
+		Status:    services.HealthDraining,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// Version reports the build serving the request.
+func (h *HealthHandler) Version(c *fiber.Ctx) error {
+	info := buildinfo.Get()
+	return c.JSON(dto.VersionResponse{
+		Version:    info.Version,
+		Commit:     info.Commit,
+		BuiltAt:    info.BuiltAt,
+		GoVersion:  info.GoVersion,
+		APIVersion: info.APIVersion,
+	})
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// InsightHandler serves the insights drawn from a user's reading history
+type InsightHandler struct {
+	insightService *services.InsightService
+}
+
+// NewInsightHandler creates a new InsightHandler instance
+func NewInsightHandler(insightService *services.InsightService) *InsightHandler {
+	return &InsightHandler{insightService: insightService}
+}
+
+// GetThemes returns the themes that recur across the user's readings
+func (h *InsightHandler) GetThemes(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	themes, err := h.insightService.Themes(c.UserContext(), userID)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch themes").WithCause(err)
+	}
+	return c.JSON(themes)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// MemoryHandler manages the user's opt-in AI personalization memory
+type MemoryHandler struct {
+	memoryService *services.MemoryService
+}
+
+// NewMemoryHandler creates a new MemoryHandler instance
+func NewMemoryHandler(memoryService *services.MemoryService) *MemoryHandler {
+	return &MemoryHandler{memoryService: memoryService}
+}
+
+// List returns the user's memories and whether personalization is enabled
+func (h *MemoryHandler) List(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	list, err := h.memoryService.List(userID)
+	if err != nil {
+		return h.memoryError(c, err, "Failed to fetch memories")
+	}
+
+	return c.JSON(list)
+}
+
+// UpdateSettings opts the user in or out of memory-based personalization
+func (h *MemoryHandler) UpdateSettings(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.MemorySettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	if err := h.memoryService.SetEnabled(userID, req.Enabled); err != nil {
+		return h.memoryError(c, err, "Failed to update memory settings")
+	}
+
+	return c.JSON(fiber.Map{"enabled": req.Enabled})
+}
+
+// Create stores a new memory
+func (h *MemoryHandler) Create(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.MemoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	memory, err := h.memoryService.Create(userID, &req)
+	if err != nil {
+		return h.memoryError(c, err, "Failed to create memory")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(memory)
+}
+
+// Update edits an existing memory
+func (h *MemoryHandler) Update(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	memoryID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid memory ID")
+	}
+
+	var req dto.MemoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	memory, err := h.memoryService.Update(userID, memoryID, &req)
+	if err != nil {
+		return h.memoryError(c, err, "Failed to update memory")
+	}
+
+	return c.JSON(memory)
+}
+
+// Delete removes a single memory
+func (h *MemoryHandler) Delete(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	memoryID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid memory ID")
+	}
+
+	if err := h.memoryService.Delete(userID, memoryID); err != nil {
+		return h.memoryError(c, err, "Failed to delete memory")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DeleteAll erases every memory and disables personalization
+func (h *MemoryHandler) DeleteAll(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	if err := h.memoryService.DeleteAll(userID); err != nil {
+		return h.memoryError(c, err, "Failed to delete memories")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Export returns all stored memories as a downloadable JSON file
+func (h *MemoryHandler) Export(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	export, err := h.memoryService.Export(userID)
+	if err != nil {
+		return h.memoryError(c, err, "Failed to export memories")
+	}
+
+	c.Attachment("aurasnap-memories.json")
+	return c.JSON(export)
+}
+
+func (h *MemoryHandler) memoryError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidMemory):
+		return apperr.Wrap(fiber.StatusBadRequest, err)
+	case errors.Is(err, services.ErrMemoryLimitReached):
+		return apperr.New(fiber.StatusConflict, "You can store up to 20 memories")
+	case errors.Is(err, services.ErrMemoryNotFound):
+		return apperr.New(fiber.StatusNotFound, "Memory not found")
+	case errors.Is(err, services.ErrUserNotFound):
+		return apperr.New(fiber.StatusNotFound, "User not found")
+	}
+	return apperr.New(fiber.StatusInternalServerError, fallback).WithCause(err)
+}
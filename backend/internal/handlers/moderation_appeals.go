@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Banned reports whether the user is banned (for middleware.RejectBanned).
+func (h *ModerationHandler) Banned(userID uuid.UUID) bool {
+	return h.moderationService.IsBanned(userID)
+}
+
+// ListMyActions returns the moderation actions taken against the caller.
+func (h *ModerationHandler) ListMyActions(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	actions, err := h.moderationService.ListUserActions(userID)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch moderation actions").WithCause(err)
+	}
+	return c.JSON(fiber.Map{"actions": actions})
+}
+
+// CreateAppeal files an appeal against one of the caller's moderation actions.
+func (h *ModerationHandler) CreateAppeal(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.CreateAppealRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	appeal, err := h.moderationService.CreateAppeal(userID, &req)
+	if err != nil {
+		return appealError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(appeal)
+}
+
+// ListMyAppeals returns the caller's appeals and their outcomes.
+func (h *ModerationHandler) ListMyAppeals(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	appeals, err := h.moderationService.ListUserAppeals(userID)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch appeals").WithCause(err)
+	}
+	return c.JSON(fiber.Map{"appeals": appeals})
+}
+
+// TakeAction bans or shadow-bans a user (admin).
+func (h *ModerationHandler) TakeAction(c *fiber.Ctx) error {
+	adminID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.ModerationActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	action, err := h.moderationService.TakeAction(adminID, userID, &req)
+	if err != nil {
+		return appealError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(action)
+}
+
+// RevokeAction lifts a moderation action (admin).
+func (h *ModerationHandler) RevokeAction(c *fiber.Ctx) error {
+	adminID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	actionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid action ID")
+	}
+
+	var req dto.RevokeActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	action, err := h.moderationService.RevokeAction(adminID, actionID, req.Reason)
+	if err != nil {
+		return appealError(c, err)
+	}
+	return c.JSON(action)
+}
+
+// ListAppeals returns appeals for review with SLA stats (admin). Use
+// ?overdue=true for pending appeals past their deadline.
+func (h *ModerationHandler) ListAppeals(c *fiber.Ctx) error {
+	status := c.Query("status", "")
+	overdue := c.QueryBool("overdue", false)
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	if limit > 100 {
+		limit = 100
+	}
+
+	appeals, total, err := h.moderationService.ListAppeals(status, overdue, limit, offset)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch appeals").WithCause(err)
+	}
+	sla, err := h.moderationService.AppealSLA()
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch appeals").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"appeals": appeals,
+		"sla":     sla,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// ReviewAppeal approves (reinstating the user) or rejects an appeal (admin).
+func (h *ModerationHandler) ReviewAppeal(c *fiber.Ctx) error {
+	adminID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	appealID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid appeal ID")
+	}
+
+	var req dto.ReviewAppealRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	appeal, err := h.moderationService.ReviewAppeal(adminID, appealID, &req)
+	if err != nil {
+		return appealError(c, err)
+	}
+	return c.JSON(appeal)
+}
+
+// ListAuditLog returns moderation audit entries, optionally filtered by
+// ?target_type= and ?target_id= (admin).
+func (h *ModerationHandler) ListAuditLog(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	if limit > 200 {
+		limit = 200
+	}
+
+	entries, total, err := h.moderationService.ListAuditLog(c.Query("target_type"), c.Query("target_id"), limit, offset)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch audit log").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"entries": entries,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+func appealError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidAction), errors.Is(err, services.ErrInvalidDuration), errors.Is(err, services.ErrInvalidAppeal),
+		errors.Is(err, services.ErrInvalidAppealReply):
+		return apperr.Wrap(fiber.StatusBadRequest, err)
+	case errors.Is(err, services.ErrActionNotFound), errors.Is(err, services.ErrAppealNotFound),
+		errors.Is(err, services.ErrUserNotFound):
+		return apperr.Wrap(fiber.StatusNotFound, err)
+	case errors.Is(err, services.ErrAppealExists), errors.Is(err, services.ErrAppealReviewed),
+		errors.Is(err, services.ErrActionRevoked):
+		return apperr.Wrap(fiber.StatusConflict, err)
+	}
+	return apperr.New(fiber.StatusInternalServerError, "Failed to update moderation records").WithCause(err)
+}
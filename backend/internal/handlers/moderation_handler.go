@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strconv"
 
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
@@ -25,23 +26,20 @@ func NewModerationHandler(moderationService *services.ModerationService) *Modera
 func (h *ModerationHandler) CreateReport(c *fiber.Ctx) error {
 	userID, err := extractUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-			Error: true, Message: "Unauthorized",
-		})
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	var req dto.CreateReportRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error: true, Message: "Invalid request body",
-		})
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
 	}
 
 	report, err := h.moderationService.CreateReport(userID, &req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error: true, Message: err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(report)
@@ -51,27 +49,22 @@ func (h *ModerationHandler) CreateReport(c *fiber.Ctx) error {
 func (h *ModerationHandler) BlockUser(c *fiber.Ctx) error {
 	blockerID, err := extractUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-			Error: true, Message: "Unauthorized",
-		})
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	var req dto.BlockUserRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error: true, Message: "Invalid request body",
-		})
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
 	}
 
 	if err := h.moderationService.BlockUser(blockerID, req.BlockedID); err != nil {
 		if errors.Is(err, services.ErrSelfBlock) || errors.Is(err, services.ErrAlreadyBlocked) {
-			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
-				Error: true, Message: err.Error(),
-			})
+			return apperr.Wrap(fiber.StatusConflict, err)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error: true, Message: "Failed to block user",
-		})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to block user").WithCause(err)
 	}
 
 	return c.JSON(fiber.Map{"message": "User blocked successfully"})
@@ -81,23 +74,17 @@ func (h *ModerationHandler) BlockUser(c *fiber.Ctx) error {
 func (h *ModerationHandler) UnblockUser(c *fiber.Ctx) error {
 	blockerID, err := extractUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-			Error: true, Message: "Unauthorized",
-		})
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	blockedIDStr := c.Params("id")
 	blockedID, err := uuid.Parse(blockedIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error: true, Message: "Invalid user ID",
-		})
+		return apperr.New(fiber.StatusBadRequest, "Invalid user ID")
 	}
 
 	if err := h.moderationService.UnblockUser(blockerID, blockedID); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error: true, Message: "Failed to unblock user",
-		})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to unblock user").WithCause(err)
 	}
 
 	return c.JSON(fiber.Map{"message": "User unblocked successfully"})
@@ -117,9 +104,7 @@ func (h *ModerationHandler) ListReports(c *fiber.Ctx) error {
 
 	reports, total, err := h.moderationService.ListReports(status, limit, offset)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error: true, Message: "Failed to fetch reports",
-		})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch reports").WithCause(err)
 	}
 
 	return c.JSON(fiber.Map{
@@ -135,33 +120,104 @@ func (h *ModerationHandler) ActionReport(c *fiber.Ctx) error {
 	reportIDStr := c.Params("id")
 	reportID, err := uuid.Parse(reportIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error: true, Message: "Invalid report ID",
-		})
+		return apperr.New(fiber.StatusBadRequest, "Invalid report ID")
 	}
 
 	var req dto.ActionReportRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error: true, Message: "Invalid request body",
-		})
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
 	}
 
 	if err := h.moderationService.ActionReport(reportID, &req); err != nil {
 		if errors.Is(err, services.ErrReportNotFound) {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error: true, Message: err.Error(),
-			})
+			return apperr.Wrap(fiber.StatusNotFound, err)
 		}
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error: true, Message: err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{"message": "Report updated successfully"})
 }
 
+// ListCases returns moderation cases, escalated ones first.
+func (h *ModerationHandler) ListCases(c *fiber.Ctx) error {
+	status := c.Query("status", "")
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	if limit > 100 {
+		limit = 100
+	}
+
+	cases, total, err := h.moderationService.ListCases(status, limit, offset)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch cases").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"cases":  cases,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetCase returns a moderation case with its reports.
+func (h *ModerationHandler) GetCase(c *fiber.Ctx) error {
+	caseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid case ID")
+	}
+
+	mc, err := h.moderationService.GetCase(caseID)
+	if err != nil {
+		if errors.Is(err, services.ErrCaseNotFound) {
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch case").WithCause(err)
+	}
+
+	return c.JSON(mc)
+}
+
+// ResolveCases actions or dismisses several cases and their reports at once.
+func (h *ModerationHandler) ResolveCases(c *fiber.Ctx) error {
+	return h.bulkCases(c, h.moderationService.ResolveCases)
+}
+
+// EscalateCases escalates several open cases at once.
+func (h *ModerationHandler) EscalateCases(c *fiber.Ctx) error {
+	return h.bulkCases(c, h.moderationService.EscalateCases)
+}
+
+func (h *ModerationHandler) bulkCases(c *fiber.Ctx, apply func(*dto.BulkCaseRequest) (*dto.BulkCaseResponse, error)) error {
+	var req dto.BulkCaseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	resp, err := apply(&req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBulkCases) || errors.Is(err, services.ErrInvalidCaseState) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to update cases").WithCause(err)
+	}
+
+	return c.JSON(resp)
+}
+
 // extractUserID gets the user UUID from the JWT claims in context.
+// timezoneHeader lets clients send their current IANA timezone per request;
+// it overrides the profile timezone for which day's content is shown, but
+// never for the scan quota or streaks.
+const timezoneHeader = "X-Timezone"
+
 func extractUserID(c *fiber.Ctx) (uuid.UUID, error) {
 	token, ok := c.Locals("user").(*jwt.Token)
 	if !ok {
@@ -180,3 +236,22 @@ func extractUserID(c *fiber.Ctx) (uuid.UUID, error) {
 
 	return uuid.Parse(sub)
 }
+
+// extractSessionID returns the "sid" (refresh token family) claim, or
+// uuid.Nil for tokens issued before sessions were tracked.
+func extractSessionID(c *fiber.Ctx) uuid.UUID {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return uuid.Nil
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return uuid.Nil
+	}
+	sid, _ := claims["sid"].(string)
+	id, err := uuid.Parse(sid)
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
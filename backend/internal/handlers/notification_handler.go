@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// NotificationHandler handles notification settings and the in-app inbox
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler instance
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// GetPreferences returns the user's effective channel × category matrix
+func (h *NotificationHandler) GetPreferences(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	prefs, err := h.notificationService.GetPreferences(userID)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch notification settings").WithCause(err)
+	}
+
+	return c.JSON(prefs)
+}
+
+// UpdatePreferences changes any subset of the matrix and, optionally, quiet hours and social delivery mode
+func (h *NotificationHandler) UpdatePreferences(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.NotificationPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	prefs, err := h.notificationService.UpdatePreferences(userID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidNotificationPreference) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to update notification settings").WithCause(err)
+	}
+
+	return c.JSON(prefs)
+}
+
+// RegisterDevice stores the caller's APNs or FCM push token. The platform
+// falls back to the X-Platform header when the body omits it.
+func (h *NotificationHandler) RegisterDevice(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.RegisterDeviceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+	if req.Platform == "" {
+		req.Platform = c.Get("X-Platform")
+	}
+	if req.AppVersion == "" {
+		req.AppVersion = c.Get("X-App-Version")
+	}
+
+	device, err := h.notificationService.RegisterDevice(userID, req.Token, req.Platform, req.AppVersion)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDevice) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to register device").WithCause(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(device)
+}
+
+// UnregisterDevice removes a push token, typically on sign-out
+func (h *NotificationHandler) UnregisterDevice(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	if err := h.notificationService.UnregisterDevice(userID, c.Params("token")); err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to unregister device").WithCause(err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListInbox returns the user's in-app notifications
+func (h *NotificationHandler) ListInbox(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	items, unread, err := h.notificationService.ListInbox(userID, c.QueryInt("limit", 50))
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch notifications").WithCause(err)
+	}
+
+	return c.JSON(dto.NotificationListResponse{Data: items, UnreadCount: unread})
+}
+
+// MarkRead marks one inbox notification as read
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	notificationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid notification ID")
+	}
+
+	if err := h.notificationService.MarkRead(userID, notificationID); err != nil {
+		if errors.Is(err, services.ErrNotificationNotFound) {
+			return apperr.New(fiber.StatusNotFound, "Notification not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to update notification").WithCause(err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// OnboardingHandler serves the server-driven onboarding flow
+type OnboardingHandler struct {
+	onboardingService *services.OnboardingService
+}
+
+// NewOnboardingHandler creates a new OnboardingHandler instance
+func NewOnboardingHandler(onboardingService *services.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{onboardingService: onboardingService}
+}
+
+// GetFlow returns the ordered onboarding steps with the user's progress
+func (h *OnboardingHandler) GetFlow(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	flow, err := h.onboardingService.Flow(userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch onboarding").WithCause(err)
+	}
+
+	return c.JSON(flow)
+}
+
+// CompleteStep marks an onboarding step as completed
+func (h *OnboardingHandler) CompleteStep(c *fiber.Ctx) error {
+	return h.record(c, h.onboardingService.CompleteStep)
+}
+
+// SkipStep marks an optional onboarding step as skipped
+func (h *OnboardingHandler) SkipStep(c *fiber.Ctx) error {
+	return h.record(c, h.onboardingService.SkipStep)
+}
+
+func (h *OnboardingHandler) record(c *fiber.Ctx, fn func(userID uuid.UUID, stepID string) (*dto.OnboardingResponse, error)) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	flow, err := fn(userID, c.Params("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownOnboardingStep):
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		case errors.Is(err, services.ErrStepNotSkippable):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to update onboarding").WithCause(err)
+	}
+
+	return c.JSON(flow)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// PromptTemplateHandler serves the admin tools for the versioned AI prompt
+// templates
+type PromptTemplateHandler struct {
+	promptTemplateService *services.PromptTemplateService
+}
+
+func NewPromptTemplateHandler(promptTemplateService *services.PromptTemplateService) *PromptTemplateHandler {
+	return &PromptTemplateHandler{promptTemplateService: promptTemplateService}
+}
+
+// List returns every stored template version, optionally for one prompt
+// name (admin)
+func (h *PromptTemplateHandler) List(c *fiber.Ctx) error {
+	templates, err := h.promptTemplateService.List(c.Query("name"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"templates": templates})
+}
+
+// Get returns one template version (admin)
+func (h *PromptTemplateHandler) Get(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid prompt template ID")
+	}
+
+	template, err := h.promptTemplateService.Get(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(template)
+}
+
+// Create stores the next version of a prompt, activating it if asked (admin)
+func (h *PromptTemplateHandler) Create(c *fiber.Ctx) error {
+	adminID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.PromptTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	template, err := h.promptTemplateService.Create(adminID, &req)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(template)
+}
+
+// Activate makes a version the one used for its prompt and provider (admin)
+func (h *PromptTemplateHandler) Activate(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid prompt template ID")
+	}
+
+	template, err := h.promptTemplateService.Activate(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(template)
+}
+
+// Deactivate stops using a version, falling back to the next broader
+// template or the built-in prompt (admin)
+func (h *PromptTemplateHandler) Deactivate(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid prompt template ID")
+	}
+
+	template, err := h.promptTemplateService.Deactivate(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(template)
+}
+
+// Delete removes a version that is inactive and produced no readings (admin)
+func (h *PromptTemplateHandler) Delete(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid prompt template ID")
+	}
+
+	if err := h.promptTemplateService.Delete(id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Stats compares the readings each template version produced, for A/B
+// analysis (admin)
+func (h *PromptTemplateHandler) Stats(c *fiber.Ctx) error {
+	stats, err := h.promptTemplateService.Stats()
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"templates": stats})
+}
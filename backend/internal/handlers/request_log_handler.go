@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestLogHandler lets support look up a failed request by the request
+// ID a user reports
+type RequestLogHandler struct {
+	requestLogService *services.RequestLogService
+}
+
+func NewRequestLogHandler(requestLogService *services.RequestLogService) *RequestLogHandler {
+	return &RequestLogHandler{requestLogService: requestLogService}
+}
+
+// Lookup returns what was recorded for a request ID and a link to its
+// trace (admin)
+func (h *RequestLogHandler) Lookup(c *fiber.Ctx) error {
+	resp, err := h.requestLogService.Lookup(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(resp)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ResearchExportHandler lets admins inspect and re-run research dataset exports
+type ResearchExportHandler struct {
+	researchExportService *services.ResearchExportService
+}
+
+// NewResearchExportHandler creates a new ResearchExportHandler instance
+func NewResearchExportHandler(researchExportService *services.ResearchExportService) *ResearchExportHandler {
+	return &ResearchExportHandler{researchExportService: researchExportService}
+}
+
+// List returns recent export runs
+func (h *ResearchExportHandler) List(c *fiber.Ctx) error {
+	exports, err := h.researchExportService.ListExports(c.QueryInt("limit", 30))
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch research exports").WithCause(err)
+	}
+	return c.JSON(fiber.Map{"exports": exports})
+}
+
+// Run exports one UTC day now (?day=YYYY-MM-DD, default yesterday)
+func (h *ResearchExportHandler) Run(c *fiber.Ctx) error {
+	day := time.Now().UTC().AddDate(0, 0, -1)
+	if raw := c.Query("day"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return apperr.New(fiber.StatusBadRequest, "day must be YYYY-MM-DD")
+		}
+		day = parsed
+	}
+	if !day.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+		return apperr.New(fiber.StatusBadRequest, "Only finished days can be exported")
+	}
+
+	export, err := h.researchExportService.ExportDay(c.UserContext(), day)
+	if err != nil {
+		if errors.Is(err, services.ErrObjectStoreDisabled) {
+			return apperr.Wrap(fiber.StatusServiceUnavailable, err)
+		}
+		// The envelope plus the export record, which says what failed.
+		log.Printf("research export %s: %v", day.Format("2006-01-02"), err)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error":   true,
+			"message": "Export failed",
+			"code":    apperr.CodeBadGateway,
+			"export":  export,
+		})
+	}
+	return c.JSON(export)
+}
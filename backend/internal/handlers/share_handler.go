@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ShareHandler handles public share links for aura readings
+type ShareHandler struct {
+	shareService *services.ShareService
+}
+
+// NewShareHandler creates a new ShareHandler instance
+func NewShareHandler(shareService *services.ShareService) *ShareHandler {
+	return &ShareHandler{shareService: shareService}
+}
+
+var sharePageTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html><html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width,initial-scale=1">
+<title>{{index .OpenGraph "og:title"}}</title>
+<meta name="description" content="{{index .OpenGraph "og:description"}}">
+{{range $key, $value := .OpenGraph}}{{if eq $key "theme-color"}}<meta name="theme-color" content="{{$value}}">
+{{else}}<meta property="{{$key}}" content="{{$value}}">
+{{end}}{{end}}<style>body{font-family:-apple-system,system-ui,sans-serif;max-width:640px;margin:0 auto;padding:24px;color:#333;line-height:1.6}.swatch{width:96px;height:96px;border-radius:50%;margin:24px auto;background:{{index .OpenGraph "theme-color"}}}h1{text-align:center}</style></head>
+<body><div class="swatch"></div><h1>{{index .OpenGraph "og:title"}}</h1><p>{{.Personality}}</p>
+<p><strong>Energy:</strong> {{.EnergyLevel}}/100 · <strong>Mood:</strong> {{.MoodScore}}/10</p>
+{{if .Strengths}}<h2>Strengths</h2><ul>{{range .Strengths}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .DailyAdvice}}<h2>Daily Advice</h2><p>{{.DailyAdvice}}</p>{{end}}
+<p><a href="https://apps.apple.com/app/aurasnap">Discover your aura with AuraSnap</a></p></body></html>`))
+
+// CreateShare creates a signed, expiring public link for one of the user's readings
+func (h *ShareHandler) CreateShare(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	readingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid reading ID")
+	}
+
+	link, err := h.shareService.CreateLink(userID, readingID, c.BaseURL())
+	if err != nil {
+		if errors.Is(err, services.ErrReadingNotFound) {
+			return apperr.New(fiber.StatusNotFound, "Reading not found")
+		}
+		if errors.Is(err, services.ErrShareNotConfigured) {
+			return apperr.Wrap(fiber.StatusServiceUnavailable, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to create share link").WithCause(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(link)
+}
+
+// GetShared serves the public view of a shared reading. Browsers and link-preview
+// crawlers get an HTML page with Open Graph tags; API clients asking for JSON get JSON.
+func (h *ShareHandler) GetShared(c *fiber.Ctx) error {
+	shareURL := c.BaseURL() + c.OriginalURL()
+
+	reading, err := h.shareService.Resolve(c.Params("token"), shareURL)
+	if err != nil {
+		if errors.Is(err, services.ErrShareTokenExpired) {
+			return apperr.Wrap(fiber.StatusGone, err)
+		}
+		if errors.Is(err, services.ErrShareTokenInvalid) || errors.Is(err, services.ErrReadingNotFound) {
+			return apperr.New(fiber.StatusNotFound, "Shared reading not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to load shared reading").WithCause(err)
+	}
+
+	c.Vary(fiber.HeaderAccept)
+	// Crawlers usually send "*/*", so HTML wins unless JSON is explicitly requested.
+	if c.Accepts(fiber.MIMETextHTML, fiber.MIMEApplicationJSON) == fiber.MIMETextHTML {
+		var buf bytes.Buffer
+		if err := sharePageTemplate.Execute(&buf, reading); err != nil {
+			return apperr.New(fiber.StatusInternalServerError, "Failed to render shared reading").WithCause(err)
+		}
+		c.Set("Content-Type", "text/html; charset=utf-8")
+		return c.Send(buf.Bytes())
+	}
+
+	return c.JSON(reading)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SimilarDiscoveryHandler serves opt-in discovery of users with similar
+// aura trajectories
+type SimilarDiscoveryHandler struct {
+	discoveryService *services.SimilarDiscoveryService
+}
+
+// NewSimilarDiscoveryHandler creates a new SimilarDiscoveryHandler instance
+func NewSimilarDiscoveryHandler(discoveryService *services.SimilarDiscoveryService) *SimilarDiscoveryHandler {
+	return &SimilarDiscoveryHandler{discoveryService: discoveryService}
+}
+
+// UpdateSettings opts in or out of similar-aura discovery
+func (h *SimilarDiscoveryHandler) UpdateSettings(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.SimilarDiscoverySettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	resp, err := h.discoveryService.UpdateSettings(c.UserContext(), userID, &req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(resp)
+}
+
+// Similar returns anonymized profiles of users whose aura trajectories
+// resemble the caller's
+func (h *SimilarDiscoveryHandler) Similar(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	profiles, err := h.discoveryService.Similar(c.UserContext(), userID, c.QueryInt("limit", 10))
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"data": profiles})
+}
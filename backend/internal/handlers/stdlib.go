@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// Standalone serves one route as a net/http handler, so a single endpoint
+// can be deployed on its own (a Lambda function URL, a Cloud Run function)
+// without the router and the services the rest of the API needs. handlers
+// run in order as in a Fiber route, so auth middleware can go first; path
+// uses Fiber's pattern syntax and its parameters reach c.Params as usual.
+// Errors are written as the API writes them, in the shared envelope.
+func Standalone(cfg *config.Config, method, path string, handlers ...fiber.Handler) http.Handler {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true, ErrorHandler: middleware.ErrorHandler(cfg)})
+	app.Add(method, path, handlers...)
+	return adaptor.FiberApp(app)
+}
+
+// HTTPHandler serves GET /api/share/:token, the public share card, as a
+// net/http handler. It only needs the share service.
+func (h *ShareHandler) HTTPHandler(cfg *config.Config) http.Handler {
+	return Standalone(cfg, fiber.MethodGet, "/api/share/:token", h.GetShared)
+}
+
+// ThemeHTTPHandler serves GET /api/aura/:id/theme, the colors share cards
+// and widgets are drawn with, as a net/http handler. auth must authenticate the request the
+// way the API does (middleware.JWTProtected) so the caller's ID is known.
+func (h *AuraHandler) ThemeHTTPHandler(cfg *config.Config, auth fiber.Handler) http.Handler {
+	return Standalone(cfg, fiber.MethodGet, "/api/aura/:id/theme", auth, h.Theme)
+}
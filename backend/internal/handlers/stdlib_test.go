@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestStandalone(t *testing.T) {
+	cfg := &config.Config{Environment: "production"}
+	h := Standalone(cfg, fiber.MethodGet, "/api/things/:id", func(c *fiber.Ctx) error {
+		return c.SendString("thing " + c.Params("id"))
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/things/42", nil))
+	body, _ := io.ReadAll(rec.Body)
+	if rec.Code != http.StatusOK || string(body) != "thing 42" {
+		t.Fatalf("GET = %d %q", rec.Code, body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/things/42", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %d, want 405", rec.Code)
+	}
+}
+
+func TestStandaloneWritesErrorEnvelope(t *testing.T) {
+	// The auth step fails the way JWTProtected does, before the handler runs.
+	deny := func(c *fiber.Ctx) error { return apperr.New(fiber.StatusUnauthorized, "Unauthorized") }
+	h := (&AuraHandler{}).ThemeHTTPHandler(&config.Config{Environment: "production"}, deny)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/aura/not-a-reading/theme", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", rec.Code)
+	}
+	var resp dto.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("body is not the error envelope: %v", err)
+	}
+	if !resp.Error || resp.Code != apperr.CodeUnauthorized || resp.Message != "Unauthorized" {
+		t.Errorf("envelope = %+v", resp)
+	}
+}
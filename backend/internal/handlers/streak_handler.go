@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -18,12 +19,12 @@ func (h *StreakHandler) GetStreak(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": true, "message": "Invalid user ID"})
+		return apperr.New(fiber.StatusUnauthorized, "Invalid user ID")
 	}
 
 	streak, err := h.streakService.Get(parsedUserID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": true, "message": "Failed to fetch streak"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch streak").WithCause(err)
 	}
 
 	return c.JSON(streak)
@@ -33,12 +34,12 @@ func (h *StreakHandler) UpdateStreak(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": true, "message": "Invalid user ID"})
+		return apperr.New(fiber.StatusUnauthorized, "Invalid user ID")
 	}
 
 	result, err := h.streakService.Update(parsedUserID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": true, "message": "Failed to update streak"})
+		return apperr.New(fiber.StatusInternalServerError, "Failed to update streak").WithCause(err)
 	}
 
 	return c.JSON(result)
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SurveyHandler serves in-app surveys and the admin survey tools
+type SurveyHandler struct {
+	surveyService *services.SurveyService
+}
+
+// NewSurveyHandler creates a new SurveyHandler instance
+func NewSurveyHandler(surveyService *services.SurveyService) *SurveyHandler {
+	return &SurveyHandler{surveyService: surveyService}
+}
+
+// Pending returns surveys the user should be shown now
+func (h *SurveyHandler) Pending(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	surveys, err := h.surveyService.Pending(userID)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch surveys").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"surveys": surveys})
+}
+
+// SubmitResponse validates and stores the user's answers to a survey
+func (h *SurveyHandler) SubmitResponse(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	surveyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid survey ID")
+	}
+
+	var req dto.SubmitSurveyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	response, err := h.surveyService.Submit(userID, surveyID, &req)
+	if err != nil {
+		return surveyError(c, err, "Failed to save survey response")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// ListSurveys returns all survey definitions (admin only)
+func (h *SurveyHandler) ListSurveys(c *fiber.Ctx) error {
+	surveys, err := h.surveyService.ListSurveys()
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch surveys").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"surveys": surveys})
+}
+
+// CreateSurvey defines a new survey (admin only)
+func (h *SurveyHandler) CreateSurvey(c *fiber.Ctx) error {
+	var req dto.SurveyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	survey, err := h.surveyService.CreateSurvey(&req)
+	if err != nil {
+		return surveyError(c, err, "Failed to create survey")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(survey)
+}
+
+// UpdateSurvey replaces a survey definition (admin only)
+func (h *SurveyHandler) UpdateSurvey(c *fiber.Ctx) error {
+	surveyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid survey ID")
+	}
+
+	var req dto.SurveyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	survey, err := h.surveyService.UpdateSurvey(surveyID, &req)
+	if err != nil {
+		return surveyError(c, err, "Failed to update survey")
+	}
+
+	return c.JSON(survey)
+}
+
+// Report returns aggregate results for a survey (admin only)
+func (h *SurveyHandler) Report(c *fiber.Ctx) error {
+	surveyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid survey ID")
+	}
+
+	report, err := h.surveyService.Report(surveyID)
+	if err != nil {
+		return surveyError(c, err, "Failed to build survey report")
+	}
+
+	return c.JSON(report)
+}
+
+func surveyError(c *fiber.Ctx, err error, fallback string) error {
+	var invalid *services.SurveyValidationError
+	switch {
+	case errors.As(err, &invalid):
+		return apperr.Wrap(fiber.StatusUnprocessableEntity, err)
+	case errors.Is(err, services.ErrSurveyNotFound), errors.Is(err, services.ErrReadingNotFound):
+		return apperr.Wrap(fiber.StatusNotFound, err)
+	case errors.Is(err, services.ErrSurveyNotEligible):
+		return apperr.Wrap(fiber.StatusForbidden, err)
+	case errors.Is(err, services.ErrSurveyAlreadyAnswered):
+		return apperr.Wrap(fiber.StatusConflict, err)
+	}
+	return apperr.New(fiber.StatusInternalServerError, fallback).WithCause(err)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// UsageHandler reports plan limits and what the user is using of them
+type UsageHandler struct {
+	photoStorageService *services.PhotoStorageService
+}
+
+// NewUsageHandler creates a new UsageHandler instance
+func NewUsageHandler(photoStorageService *services.PhotoStorageService) *UsageHandler {
+	return &UsageHandler{photoStorageService: photoStorageService}
+}
+
+// Get returns the user's plan and stored-photo usage
+func (h *UsageHandler) Get(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	usage, err := h.photoStorageService.Usage(userID)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch usage").WithCause(err)
+	}
+
+	return c.JSON(usage)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserHandler handles handles, discovery settings and user search
+type UserHandler struct {
+	userService *services.UserService
+}
+
+// NewUserHandler creates a new UserHandler instance
+func NewUserHandler(userService *services.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// UpdateHandle claims or changes the user's @handle; an empty handle clears it
+func (h *UserHandler) UpdateHandle(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.UpdateHandleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	resp, err := h.userService.SetHandle(userID, req.Handle)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidHandle), errors.Is(err, services.ErrHandleReserved),
+			errors.Is(err, services.ErrHandleProfanity):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		case errors.Is(err, services.ErrHandleTaken):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		case errors.Is(err, services.ErrHandleCooldown):
+			return apperr.Wrap(fiber.StatusTooManyRequests, err)
+		case errors.Is(err, services.ErrUserNotFound):
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to update handle").WithCause(err)
+	}
+
+	return c.JSON(resp)
+}
+
+// UpdateDiscovery sets whether others can find the user by handle or contacts
+func (h *UserHandler) UpdateDiscovery(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.DiscoverySettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	resp, err := h.userService.UpdateDiscovery(userID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPhone) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		if errors.Is(err, services.ErrUserNotFound) {
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to update discovery settings").WithCause(err)
+	}
+
+	return c.JSON(resp)
+}
+
+// UpdateLeaderboard opts in or out of public leaderboards and sets the
+// display name shown there; an empty display name goes anonymous
+func (h *UserHandler) UpdateLeaderboard(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.LeaderboardSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	resp, err := h.userService.UpdateLeaderboard(userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidDisplayName), errors.Is(err, services.ErrDisplayNameProfanity):
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		case errors.Is(err, services.ErrDisplayNameTaken):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		case errors.Is(err, services.ErrDisplayNameLocked):
+			return apperr.Wrap(fiber.StatusForbidden, err)
+		case errors.Is(err, services.ErrUserNotFound):
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to update leaderboard settings").WithCause(err)
+	}
+
+	return c.JSON(resp)
+}
+
+// UpdateResearchConsent opts in or out of anonymized research datasets
+func (h *UserHandler) UpdateResearchConsent(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req dto.ResearchConsentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if fields := validateRequest(&req); fields != nil {
+		return validationFailed(fields)
+	}
+
+	if err := h.userService.SetResearchConsent(userID, req.Enabled); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			return apperr.New(fiber.StatusNotFound, "User not found")
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to update research consent").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"research_consent": req.Enabled})
+}
+
+// Search finds discoverable users by handle prefix (?handle=ada or ?handle=@ada)
+func (h *UserHandler) Search(c *fiber.Ctx) error {
+	userID, err := extractUserID(c)
+	if err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	users, err := h.userService.SearchByHandle(userID, c.Query("handle"))
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to search users").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{"users": users})
+}
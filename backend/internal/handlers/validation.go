@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// validate checks the validate tags of request DTOs. Field errors are
+// reported under their JSON names, which is what the apps know.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+	return v
+}
+
+// validateRequest checks req, a pointer to a parsed DTO, against its
+// validate tags and returns the failed fields, or nil when it is valid.
+// Tags cover the shape of a request; rules that need the database or the
+// caller stay in the services.
+func validateRequest(req any) []dto.FieldError {
+	err := validate.Struct(req)
+	if err == nil {
+		return nil
+	}
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return []dto.FieldError{{Field: "", Code: "invalid", Message: "request is invalid"}}
+	}
+	if len(verrs) == 0 {
+		return nil
+	}
+	fields := make([]dto.FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		fields = append(fields, fieldError(fe))
+	}
+	return fields
+}
+
+// validationFailed is the 422 error for failed fields.
+func validationFailed(fields []dto.FieldError) error {
+	return &apperr.Error{
+		Status:  fiber.StatusUnprocessableEntity,
+		Code:    apperr.CodeValidationFailed,
+		Message: "Validation failed",
+		Fields:  fields,
+	}
+}
+
+func fieldError(fe validator.FieldError) dto.FieldError {
+	// Namespace is "Struct.field[0].sub"; the struct name means nothing to
+	// the client.
+	field := fe.Namespace()
+	if _, rest, ok := strings.Cut(field, "."); ok {
+		field = rest
+	}
+	code, message := fieldErrorCode(fe)
+	param := fe.Param()
+	if code == "required" {
+		// required_without's param is a Go field name.
+		param = ""
+	}
+	return dto.FieldError{Field: field, Code: code, Param: param, Message: field + " " + message}
+}
+
+// fieldErrorCode maps a failed tag to the stable code and English message
+// the apps see. Codes never name a validator tag, so tags can change
+// without breaking the apps' message tables.
+func fieldErrorCode(fe validator.FieldError) (string, string) {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required", "required_without", "required_with":
+		return "required", "is required"
+	case "email":
+		return "invalid_email", "must be a valid email address"
+	case "uuid", "uuid4":
+		return "invalid_uuid", "must be a valid ID"
+	case "url", "http_url":
+		return "invalid_url", "must be a valid http(s) URL"
+	case "oneof":
+		return "invalid_choice", "must be one of: " + strings.ReplaceAll(param, " ", ", ")
+	case "min", "gte":
+		switch fe.Kind() {
+		case reflect.String:
+			return "too_short", "must be at least " + param + " characters"
+		case reflect.Slice, reflect.Map:
+			return "too_few", "must have at least " + param + " items"
+		}
+		return "too_small", "must be at least " + param
+	case "max", "lte":
+		switch fe.Kind() {
+		case reflect.String:
+			return "too_long", "must be at most " + param + " characters"
+		case reflect.Slice, reflect.Map:
+			return "too_many", "must have at most " + param + " items"
+		}
+		return "too_large", "must be at most " + param
+	}
+	return "invalid", "is invalid"
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+)
+
+func TestValidateRequest(t *testing.T) {
+	if fields := validateRequest(&dto.LoginRequest{Email: "a@example.com", Password: "secret"}); fields != nil {
+		t.Fatalf("valid request: %+v", fields)
+	}
+
+	fields := validateRequest(&dto.LoginRequest{Email: "not-an-email"})
+	want := map[string]string{"email": "invalid_email", "password": "required"}
+	if len(fields) != len(want) {
+		t.Fatalf("fields = %+v", fields)
+	}
+	for _, f := range fields {
+		if want[f.Field] != f.Code || f.Message == "" {
+			t.Errorf("%s: code %q, message %q", f.Field, f.Code, f.Message)
+		}
+	}
+
+	fields = validateRequest(&dto.DeleteReadingsRequest{IDs: []string{"not-a-uuid"}})
+	if len(fields) != 1 || fields[0].Field != "ids[0]" || fields[0].Code != "invalid_uuid" {
+		t.Fatalf("nested field: %+v", fields)
+	}
+
+	fields = validateRequest(&dto.RateReadingRequest{Stars: 9})
+	if len(fields) != 1 || fields[0].Code != "too_large" || fields[0].Param != "5" {
+		t.Fatalf("range: %+v", fields)
+	}
+
+	batch := dto.CreateScanBatchRequest{Images: make([]dto.ScanBatchImage, 11)}
+	for i := range batch.Images {
+		batch.Images[i].ImageURL = "https://cdn.example.com/photo.jpg"
+	}
+	if fields := validateRequest(&batch); len(fields) != 1 || fields[0].Field != "images" {
+		t.Fatalf("oversized batch: %+v", fields)
+	}
+	batch.Images = batch.Images[:10]
+	batch.Images[3].ImageURL = ""
+	if fields := validateRequest(&batch); len(fields) != 1 || fields[0].Field != "images[3].image_url" {
+		t.Fatalf("batch photo without an image: %+v", fields)
+	}
+
+	// Either credential set is enough; the param never names a Go field.
+	if fields := validateRequest(&dto.RestoreAccountRequest{IdentityToken: "token"}); fields != nil {
+		t.Fatalf("identity token alone: %+v", fields)
+	}
+	for _, f := range validateRequest(&dto.RestoreAccountRequest{}) {
+		if f.Code != "required" || f.Param != "" {
+			t.Errorf("restore %s: code %q, param %q", f.Field, f.Code, f.Param)
+		}
+	}
+}
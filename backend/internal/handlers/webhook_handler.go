@@ -2,58 +2,243 @@ package handlers
 
 import (
 	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type WebhookHandler struct {
-	subscriptionService *services.SubscriptionService
-	cfg                 *config.Config
+	subscriptionService   *services.SubscriptionService
+	emailService          *services.EmailService
+	processedEventService *services.ProcessedEventService
+	cfg                   *config.Config
 }
 
-func NewWebhookHandler(subscriptionService *services.SubscriptionService, cfg *config.Config) *WebhookHandler {
+func NewWebhookHandler(subscriptionService *services.SubscriptionService, emailService *services.EmailService, processedEventService *services.ProcessedEventService, cfg *config.Config) *WebhookHandler {
 	return &WebhookHandler{
-		subscriptionService: subscriptionService,
-		cfg:                 cfg,
+		subscriptionService:   subscriptionService,
+		emailService:          emailService,
+		processedEventService: processedEventService,
+		cfg:                   cfg,
 	}
 }
 
+// HandleRevenueCat stores the event before applying it, so a failure while
+// processing is retried by the webhook worker rather than lost.
 func (h *WebhookHandler) HandleRevenueCat(c *fiber.Ctx) error {
 	// Verify authorization header using constant-time comparison
 	expected := strings.TrimSpace(h.cfg.RevenueCatWebhookAuth)
 	if expected == "" {
-		return c.Status(fiber.StatusServiceUnavailable).JSON(dto.ErrorResponse{
-			Error:   true,
-			Message: "Webhook auth not configured",
-		})
+		return apperr.New(fiber.StatusServiceUnavailable, "Webhook auth not configured")
 	}
 
 	authHeader := c.Get("Authorization")
 	if subtle.ConstantTimeCompare([]byte(authHeader), []byte(expected)) != 1 {
-		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-			Error:   true,
-			Message: "Unauthorized",
-		})
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	var webhook dto.RevenueCatWebhook
-	if err := c.BodyParser(&webhook); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   true,
-			Message: "Invalid webhook payload",
-		})
+	event, duplicate, err := h.subscriptionService.ReceiveRevenueCatWebhook(c.Body())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhookPayload) {
+			return apperr.New(fiber.StatusBadRequest, "Invalid webhook payload")
+		}
+		// Not stored: a 5xx makes RevenueCat deliver it again.
+		log.Printf("revenuecat webhook: %v", err)
+		return apperr.New(fiber.StatusInternalServerError, "Failed to store webhook event")
+	}
+	if duplicate {
+		return c.JSON(fiber.Map{"received": true, "duplicate": true})
+	}
+
+	if err := h.subscriptionService.ProcessWebhookEvent(event.ID); err != nil {
+		log.Printf("revenuecat webhook: %v (will retry)", err)
+	}
+
+	return c.JSON(fiber.Map{"received": true})
+}
+
+// HandleStripe verifies the Stripe-Signature header and queues the event
+// like HandleRevenueCat; subscription events update the same table.
+func (h *WebhookHandler) HandleStripe(c *fiber.Ctx) error {
+	if strings.TrimSpace(h.cfg.StripeWebhookSecret) == "" {
+		return apperr.New(fiber.StatusServiceUnavailable, "Webhook auth not configured")
+	}
+	if err := services.VerifyStripeSignature(c.Body(), c.Get("Stripe-Signature"), h.cfg.StripeWebhookSecret, time.Now()); err != nil {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
-	if err := h.subscriptionService.HandleWebhookEvent(&webhook.Event); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   true,
-			Message: "Failed to process webhook event",
-		})
+	event, duplicate, err := h.subscriptionService.ReceiveStripeWebhook(c.Body())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhookPayload) {
+			return apperr.New(fiber.StatusBadRequest, "Invalid webhook payload")
+		}
+		log.Printf("stripe webhook: %v", err)
+		return apperr.New(fiber.StatusInternalServerError, "Failed to store webhook event")
+	}
+	if duplicate {
+		return c.JSON(fiber.Map{"received": true, "duplicate": true})
+	}
+
+	if err := h.subscriptionService.ProcessWebhookEvent(event.ID); err != nil {
+		log.Printf("stripe webhook: %v (will retry)", err)
 	}
 
 	return c.JSON(fiber.Map{"received": true})
 }
+
+// ListEvents returns stored webhook events, e.g. ?status=dead for the
+// dead-letter queue (admin).
+func (h *WebhookHandler) ListEvents(c *fiber.Ctx) error {
+	status := c.Query("status", "")
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	if limit > 100 {
+		limit = 100
+	}
+
+	events, total, err := h.subscriptionService.ListWebhookEvents(status, limit, offset)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch webhook events").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"events": events,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ReplayEvent requeues a dead-lettered webhook event (admin).
+func (h *WebhookHandler) ReplayEvent(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid event ID")
+	}
+
+	event, err := h.subscriptionService.ReplayWebhookEvent(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWebhookEventNotFound):
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		case errors.Is(err, services.ErrWebhookNotDead):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to replay webhook event").WithCause(err)
+	}
+
+	return c.JSON(event)
+}
+
+// ListProcessedEvents returns consumed events, e.g. ?status=quarantined for
+// poison events awaiting review (admin).
+func (h *WebhookHandler) ListProcessedEvents(c *fiber.Ctx) error {
+	source := c.Query("source", "")
+	status := c.Query("status", "")
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+
+	if limit > 100 {
+		limit = 100
+	}
+
+	events, total, err := h.processedEventService.List(source, status, limit, offset)
+	if err != nil {
+		return apperr.New(fiber.StatusInternalServerError, "Failed to fetch events").WithCause(err)
+	}
+
+	return c.JSON(fiber.Map{
+		"events": events,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ReleaseProcessedEvent takes a quarantined event out of quarantine (admin).
+func (h *WebhookHandler) ReleaseProcessedEvent(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid event ID")
+	}
+
+	event, err := h.processedEventService.Release(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProcessedEventNotFound):
+			return apperr.Wrap(fiber.StatusNotFound, err)
+		case errors.Is(err, services.ErrEventNotQuarantined):
+			return apperr.Wrap(fiber.StatusConflict, err)
+		}
+		return apperr.New(fiber.StatusInternalServerError, "Failed to release event").WithCause(err)
+	}
+
+	return c.JSON(event)
+}
+
+// HandleSESFeedback receives SES bounce/complaint notifications via SNS.
+// SNS cannot send custom headers, so the secret is accepted as the HTTP
+// basic auth password (https://sns:<secret>@host/...) or a raw Authorization header.
+func (h *WebhookHandler) HandleSESFeedback(c *fiber.Ctx) error {
+	expected := strings.TrimSpace(h.cfg.EmailWebhookSecret)
+	if expected == "" {
+		return apperr.New(fiber.StatusServiceUnavailable, "Webhook auth not configured")
+	}
+
+	provided := c.Get("Authorization")
+	if password, ok := basicAuthPassword(c); ok {
+		provided = password
+	}
+	if subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) != 1 {
+		return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	// SNS posts JSON with a text/plain content type, so decode the raw body.
+	var msg dto.SNSMessage
+	if err := json.Unmarshal(c.Body(), &msg); err != nil {
+		return apperr.New(fiber.StatusBadRequest, "Invalid webhook payload")
+	}
+
+	suppressed, err := h.emailService.HandleSNSMessage(c.UserContext(), &msg)
+	if errors.Is(err, services.ErrEventQuarantined) {
+		// Acknowledge so SNS stops redelivering; an admin can inspect and
+		// release it from the quarantine.
+		log.Printf("ses webhook: %v", err)
+		return c.JSON(fiber.Map{"received": true, "quarantined": true})
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSNSMessage) || errors.Is(err, services.ErrUntrustedSubscribeURL) {
+			return apperr.Wrap(fiber.StatusBadRequest, err)
+		}
+		log.Printf("ses webhook: %v", err)
+		return apperr.New(fiber.StatusInternalServerError, "Failed to process webhook event")
+	}
+
+	return c.JSON(fiber.Map{"received": true, "suppressed": suppressed})
+}
+
+// basicAuthPassword extracts the password from a Basic Authorization header.
+func basicAuthPassword(c *fiber.Ctx) (string, bool) {
+	header := c.Get(fiber.HeaderAuthorization)
+	if !strings.HasPrefix(header, "Basic ") {
+		return "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "Basic "))
+	if err != nil {
+		return "", false
+	}
+	_, password, ok := strings.Cut(string(decoded), ":")
+	return password, ok
+}
@@ -0,0 +1,181 @@
+// Package i18n holds the localized text the server writes itself: the color
+// trait table used when the AI is unavailable, the language names the AI
+// prompt asks for, and the templates of messages admins send to users.
+//
+// Layout: messages/<locale>.json, one file per supported locale. Locales
+// missing a color or template fall back to English.
+package i18n
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed messages
+var messageFS embed.FS
+
+const DefaultLocale = "en"
+
+var ErrUnknownTemplate = errors.New("unknown message template")
+
+// ColorTraits is the fallback reading text for one aura color.
+type ColorTraits struct {
+	Personality string   `json:"personality"`
+	Strengths   []string `json:"strengths"`
+	Challenges  []string `json:"challenges"`
+	DailyAdvice string   `json:"daily_advice"`
+}
+
+// MessageTemplate is a text/template title and body for a user message.
+type MessageTemplate struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type catalog struct {
+	// Language is the English name of the locale, as the AI prompt uses it.
+	Language  string                     `json:"language"`
+	Colors    map[string]ColorTraits     `json:"colors"`
+	Templates map[string]MessageTemplate `json:"templates"`
+
+	parsed map[string]*template.Template
+}
+
+var catalogs = mustLoad()
+
+// mustLoad parses every embedded message file. A broken file panics at
+// startup rather than serving half-translated readings.
+func mustLoad() map[string]catalog {
+	files, err := fs.Glob(messageFS, "messages/*.json")
+	if err != nil {
+		panic(err)
+	}
+	out := make(map[string]catalog, len(files))
+	for _, file := range files {
+		raw, err := messageFS.ReadFile(file)
+		if err != nil {
+			panic(err)
+		}
+		var c catalog
+		if err := json.Unmarshal(raw, &c); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", file, err))
+		}
+		c.parsed = make(map[string]*template.Template, len(c.Templates))
+		for name, t := range c.Templates {
+			tmpl := template.New(name).Option("missingkey=error")
+			template.Must(tmpl.New("title").Parse(t.Title))
+			template.Must(tmpl.New("body").Parse(t.Body))
+			c.parsed[name] = tmpl
+		}
+		out[strings.TrimSuffix(path.Base(file), ".json")] = c
+	}
+	if _, ok := out[DefaultLocale]; !ok {
+		panic("i18n: missing messages/" + DefaultLocale + ".json")
+	}
+	return out
+}
+
+// Locales returns the supported locales, sorted.
+func Locales() []string {
+	out := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		out = append(out, locale)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Resolve picks the supported locale for a locale code or an Accept-Language
+// header such as "tr-TR,tr;q=0.9,en;q=0.8". Region suffixes are ignored, the
+// highest q-value wins, and anything unsupported resolves to English.
+func Resolve(acceptLanguage string) string {
+	best, bestQ := DefaultLocale, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale := strings.ToLower(strings.TrimSpace(tag))
+		if i := strings.IndexAny(locale, "-_"); i > 0 {
+			locale = locale[:i]
+		}
+		if _, ok := catalogs[locale]; !ok {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = locale, q
+		}
+	}
+	return best
+}
+
+// LanguageName returns the English name of a locale, e.g. "Turkish".
+func LanguageName(locale string) string {
+	if c, ok := catalogs[locale]; ok && c.Language != "" {
+		return c.Language
+	}
+	return catalogs[DefaultLocale].Language
+}
+
+// Traits returns the fallback text for a color in locale, falling back to
+// English. ok is false for a color the table does not know.
+func Traits(locale, color string) (ColorTraits, bool) {
+	if t, ok := catalogs[locale].Colors[color]; ok {
+		return t, true
+	}
+	t, ok := catalogs[DefaultLocale].Colors[color]
+	return t, ok
+}
+
+// Colors returns the colors of the English table, sorted.
+func Colors() []string {
+	out := make([]string, 0, len(catalogs[DefaultLocale].Colors))
+	for color := range catalogs[DefaultLocale].Colors {
+		out = append(out, color)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// TemplateNames returns the message templates of the English catalog, sorted.
+func TemplateNames() []string {
+	out := make([]string, 0, len(catalogs[DefaultLocale].Templates))
+	for name := range catalogs[DefaultLocale].Templates {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Render fills the title and body of a message template in locale, falling
+// back to English.
+func Render(locale, name string, data any) (string, string, error) {
+	tmpl, ok := catalogs[locale].parsed[name]
+	if !ok {
+		if tmpl, ok = catalogs[DefaultLocale].parsed[name]; !ok {
+			return "", "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+		}
+	}
+	var title, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&title, "title", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+	return title.String(), body.String(), nil
+}
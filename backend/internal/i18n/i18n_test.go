@@ -0,0 +1,102 @@
+package i18n
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	cases := map[string]string{
+		"":                        "en",
+		"tr":                      "tr",
+		"tr-TR,tr;q=0.9,en;q=0.8": "tr",
+		"de-DE,es;q=0.7,en;q=0.5": "es",
+		"en;q=0.4,es-MX;q=0.9":    "es",
+		"fr-FR,de":                "en",
+		"es_ES":                   "es",
+		"tr;q=bogus,es;q=0.2":     "es",
+	}
+	for header, want := range cases {
+		if got := Resolve(header); got != want {
+			t.Errorf("Resolve(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestEveryLocaleCoversEveryColor(t *testing.T) {
+	for _, locale := range Locales() {
+		c := catalogs[locale]
+		if c.Language == "" {
+			t.Errorf("%s: missing language name", locale)
+		}
+		for _, color := range Colors() {
+			traits, ok := c.Colors[color]
+			if !ok {
+				t.Errorf("%s: missing color %s", locale, color)
+				continue
+			}
+			if traits.Personality == "" || traits.DailyAdvice == "" || len(traits.Strengths) != 3 || len(traits.Challenges) != 3 {
+				t.Errorf("%s/%s: incomplete traits %+v", locale, color, traits)
+			}
+		}
+	}
+}
+
+func TestTraitsFallback(t *testing.T) {
+	tr, _ := Traits("tr", "blue")
+	en, _ := Traits("en", "blue")
+	if tr.Personality == en.Personality {
+		t.Error("tr traits are not translated")
+	}
+	if got, _ := Traits("fr", "blue"); got.Personality != en.Personality {
+		t.Errorf("unknown locale = %q, want English", got.Personality)
+	}
+	if _, ok := Traits("tr", "plaid"); ok {
+		t.Error("unknown color reported ok")
+	}
+	if LanguageName("es") != "Spanish" || LanguageName("xx") != "English" {
+		t.Errorf("language names = %q, %q", LanguageName("es"), LanguageName("xx"))
+	}
+}
+
+func TestRender(t *testing.T) {
+	data := struct{ Message string }{"Your appeal was accepted."}
+	title, body, err := Render("es", "support_reply", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "Respuesta del soporte de AuraSnap" || !strings.HasPrefix(body, "Your appeal was accepted.") {
+		t.Errorf("es support_reply = %q / %q", title, body)
+	}
+	if _, _, err := Render("tr", "no_such_template", data); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("unknown template: err = %v", err)
+	}
+	for _, locale := range Locales() {
+		for _, name := range TemplateNames() {
+			if _, ok := catalogs[locale].Templates[name]; !ok {
+				t.Errorf("%s: missing template %s", locale, name)
+			}
+		}
+	}
+}
+
+func TestTraitNames(t *testing.T) {
+	tr, _ := Traits("tr", "yellow")
+	en, _ := Traits("en", "yellow")
+	if got, ok := CanonicalTrait(KindChallenge, strings.ToUpper(tr.Challenges[1])); !ok || got != en.Challenges[1] {
+		t.Errorf("CanonicalTrait(%q) = %q, %v; want %q", tr.Challenges[1], got, ok, en.Challenges[1])
+	}
+	if got, _ := CanonicalTrait(KindStrength, en.Strengths[0]); got != en.Strengths[0] {
+		t.Errorf("English name canonicalized to %q", got)
+	}
+	if _, ok := CanonicalTrait(KindStrength, "Juggling"); ok {
+		t.Error("free-form name reported ok")
+	}
+	if got := TraitName("tr", KindChallenge, en.Challenges[1]); got != tr.Challenges[1] {
+		t.Errorf("TraitName(tr) = %q, want %q", got, tr.Challenges[1])
+	}
+	if got := TraitName("fr", KindChallenge, en.Challenges[1]); got != en.Challenges[1] {
+		t.Errorf("TraitName(fr) = %q, want English", got)
+	}
+}
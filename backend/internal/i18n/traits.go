@@ -0,0 +1,78 @@
+package i18n
+
+import "strings"
+
+// Trait kinds, as CanonicalTrait and TraitName take them.
+const (
+	KindStrength  = "strength"
+	KindChallenge = "challenge"
+)
+
+// The color tables list strengths and challenges in the same order in every
+// locale, so position maps a translated name to its English one. Names are
+// indexed by traitKey.
+var canonicalTraits, translatedTraits = indexTraitNames()
+
+func indexTraitNames() (map[string]map[string]string, map[string]map[string]map[string]string) {
+	canonical := map[string]map[string]string{KindStrength: {}, KindChallenge: {}}
+	translated := make(map[string]map[string]map[string]string, len(catalogs))
+	english := catalogs[DefaultLocale].Colors
+	// English goes first and colors in order, so a name shared by two
+	// entries always resolves the same way.
+	locales := append([]string{DefaultLocale}, Locales()...)
+	for _, locale := range locales {
+		if translated[locale] != nil {
+			continue
+		}
+		translated[locale] = map[string]map[string]string{KindStrength: {}, KindChallenge: {}}
+		for _, color := range Colors() {
+			t, ok := catalogs[locale].Colors[color]
+			if !ok {
+				continue
+			}
+			en := english[color]
+			for kind, pair := range map[string][2][]string{
+				KindStrength:  {t.Strengths, en.Strengths},
+				KindChallenge: {t.Challenges, en.Challenges},
+			} {
+				names, enNames := pair[0], pair[1]
+				for i := 0; i < len(names) && i < len(enNames); i++ {
+					key := traitKey(names[i])
+					if _, seen := canonical[kind][key]; !seen {
+						canonical[kind][key] = enNames[i]
+					}
+					enKey := traitKey(enNames[i])
+					if _, seen := translated[locale][kind][enKey]; !seen {
+						translated[locale][kind][enKey] = names[i]
+					}
+				}
+			}
+		}
+	}
+	return canonical, translated
+}
+
+// CanonicalTrait returns the English name of a strength or challenge the
+// color tables know in any locale, e.g. "Aşırı Düşünme" -> "Overthinking".
+// ok is false for free-form text the tables do not have.
+func CanonicalTrait(kind, name string) (string, bool) {
+	en, ok := canonicalTraits[kind][traitKey(name)]
+	return en, ok
+}
+
+// TraitName translates the English name of a strength or challenge to
+// locale. Names the tables do not have, and locales without a translation,
+// come back as given.
+func TraitName(locale, kind, english string) string {
+	if name, ok := translatedTraits[locale][kind][traitKey(english)]; ok {
+		return name
+	}
+	return english
+}
+
+// traitKey folds case, including Turkish dotted and dotless i, which do not
+// survive a round trip through strings.ToUpper and ToLower.
+func traitKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("ı", "i", "i\u0307", "i").Replace(key)
+}
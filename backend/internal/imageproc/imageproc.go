@@ -0,0 +1,83 @@
+// Package imageproc holds cheap, pure-Go image analysis that runs inline
+// with a request, before (or instead of) the AI provider.
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/jpeg" // register decoders for image.Decode
+	_ "image/png"
+)
+
+var ErrUnsupportedImage = errors.New("image must be a JPEG or PNG")
+
+// maxSamples bounds the pixels visited per image so analysis cost does not
+// grow with resolution.
+const maxSamples = 96 * 96
+
+// Decode decodes a JPEG or PNG.
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	return img, nil
+}
+
+// samplePixels calls fn for an evenly spaced grid of at most maxSamples
+// pixels.
+func samplePixels(img image.Image, fn func(r, g, b float64)) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return
+	}
+	step := 1
+	for (w/step)*(h/step) > maxSamples {
+		step++
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				continue
+			}
+			fn(float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+		}
+	}
+}
+
+// toHSV converts RGB in [0,1] to hue in degrees [0,360) and saturation and
+// value in [0,1].
+func toHSV(r, g, b float64) (h, s, v float64) {
+	max, min := r, r
+	for _, c := range []float64{g, b} {
+		if c > max {
+			max = c
+		}
+		if c < min {
+			min = c
+		}
+	}
+	v = max
+	delta := max - min
+	if max == 0 || delta == 0 {
+		return 0, 0, v
+	}
+	s = delta / max
+
+	switch max {
+	case r:
+		h = 60 * ((g - b) / delta)
+	case g:
+		h = 60 * ((b-r)/delta + 2)
+	default:
+		h = 60 * ((r-g)/delta + 4)
+	}
+	if h < 0 {
+		h += 360
+	}
+	return h, s, v
+}
@@ -0,0 +1,63 @@
+package imageproc
+
+import "image"
+
+// Below this saturation a pixel counts as neutral rather than as its hue.
+const neutralSaturation = 0.18
+
+// hueBands maps hue ranges (degrees, upper bound exclusive) to aura colors.
+var hueBands = []struct {
+	upTo  float64
+	color string
+}{
+	{15, "red"},
+	{40, "orange"},
+	{52, "gold"},
+	{70, "yellow"},
+	{165, "green"},
+	{230, "blue"},
+	{260, "indigo"},
+	{300, "violet"},
+	{345, "pink"},
+	{360, "red"},
+}
+
+// AuraColorForHSV names the aura color closest to one pixel.
+func AuraColorForHSV(h, s, v float64) string {
+	if s < neutralSaturation {
+		if v > 0.6 {
+			return "white"
+		}
+		return "indigo"
+	}
+	for _, band := range hueBands {
+		if h < band.upTo {
+			return band.color
+		}
+	}
+	return "red"
+}
+
+// InstantAuraColor picks a provisional aura color from the photo's dominant
+// hues. Pixels vote for their color weighted by saturation and brightness,
+// so vivid tones beat large dull backgrounds. It returns "" for an empty
+// image.
+func InstantAuraColor(img image.Image) string {
+	votes := map[string]float64{}
+	samplePixels(img, func(r, g, b float64) {
+		h, s, v := toHSV(r, g, b)
+		weight := 0.2 + s*v // neutrals still count, just less
+		votes[AuraColorForHSV(h, s, v)] += weight
+	})
+
+	best, bestVotes := "", 0.0
+	for _, c := range auraColorOrder {
+		if votes[c] > bestVotes {
+			best, bestVotes = c, votes[c]
+		}
+	}
+	return best
+}
+
+// auraColorOrder fixes tie-breaking so results are deterministic.
+var auraColorOrder = []string{"red", "orange", "yellow", "green", "blue", "indigo", "violet", "white", "gold", "pink"}
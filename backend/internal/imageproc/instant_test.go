@@ -0,0 +1,58 @@
+package imageproc
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestInstantAuraColor(t *testing.T) {
+	cases := map[string]color.Color{
+		"red":    color.NRGBA{220, 30, 30, 255},
+		"green":  color.NRGBA{40, 180, 60, 255},
+		"blue":   color.NRGBA{30, 90, 220, 255},
+		"violet": color.NRGBA{150, 40, 200, 255},
+		"white":  color.NRGBA{240, 240, 240, 255},
+	}
+	for want, c := range cases {
+		if got := InstantAuraColor(solid(40, 40, c)); got != want {
+			t.Errorf("%v: got %q, want %q", c, got, want)
+		}
+	}
+}
+
+func TestInstantAuraColorPrefersVividTones(t *testing.T) {
+	// A dull grey background covering 75% of the frame with a vivid blue patch.
+	img := solid(100, 100, color.NRGBA{110, 110, 110, 255})
+	for y := 0; y < 50; y++ {
+		for x := 0; x < 50; x++ {
+			img.Set(x, y, color.NRGBA{20, 80, 230, 255})
+		}
+	}
+	if got := InstantAuraColor(img); got != "blue" {
+		t.Fatalf("got %q, want blue", got)
+	}
+}
+
+func TestDecodeRejectsNonImages(t *testing.T) {
+	if _, err := Decode([]byte("not an image")); err != ErrUnsupportedImage {
+		t.Fatalf("got %v", err)
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, solid(4, 4, color.White))
+	if _, err := Decode(buf.Bytes()); err != nil {
+		t.Fatalf("png decode: %v", err)
+	}
+}
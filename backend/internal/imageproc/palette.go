@@ -0,0 +1,145 @@
+package imageproc
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PaletteSize is the number of swatches stored per reading.
+const PaletteSize = 5
+
+// Colors closer than this (Euclidean RGB, 0-255 scale) count as one swatch.
+const minSwatchDistance = 48
+
+// Swatch is one dominant photo color and its share of the sampled pixels.
+type Swatch struct {
+	Hex    string
+	Weight float64
+}
+
+type bucket struct {
+	r, g, b float64
+	count   float64
+}
+
+func (b bucket) mean() (float64, float64, float64) {
+	return b.r / b.count, b.g / b.count, b.b / b.count
+}
+
+// Palette extracts up to n dominant colors, most common first. Pixels are
+// quantized to 4 bits per channel, the busiest buckets are averaged, and
+// near-duplicates are dropped so the palette stays varied.
+func Palette(img image.Image, n int) []Swatch {
+	buckets := map[int]*bucket{}
+	total := 0.0
+	samplePixels(img, func(r, g, b float64) {
+		R, G, B := r*255, g*255, b*255
+		key := int(R)>>4<<8 | int(G)>>4<<4 | int(B)>>4
+		bk, ok := buckets[key]
+		if !ok {
+			bk = &bucket{}
+			buckets[key] = bk
+		}
+		bk.r += R
+		bk.g += G
+		bk.b += B
+		bk.count++
+		total++
+	})
+	if total == 0 {
+		return nil
+	}
+
+	keys := make([]int, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ci, cj := buckets[keys[i]].count, buckets[keys[j]].count
+		if ci != cj {
+			return ci > cj
+		}
+		return keys[i] < keys[j]
+	})
+
+	var picked []bucket
+	for _, k := range keys {
+		candidate := *buckets[k]
+		merged := false
+		for i := range picked {
+			if rgbDistance(picked[i], candidate) < minSwatchDistance {
+				picked[i].r += candidate.r
+				picked[i].g += candidate.g
+				picked[i].b += candidate.b
+				picked[i].count += candidate.count
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			picked = append(picked, candidate)
+		}
+	}
+	sort.SliceStable(picked, func(i, j int) bool { return picked[i].count > picked[j].count })
+
+	if len(picked) > n {
+		picked = picked[:n]
+	}
+	swatches := make([]Swatch, len(picked))
+	for i, p := range picked {
+		r, g, b := p.mean()
+		swatches[i] = Swatch{Hex: toHex(r, g, b), Weight: p.count / total}
+	}
+	return swatches
+}
+
+func rgbDistance(a, b bucket) float64 {
+	ar, ag, ab := a.mean()
+	br, bg, bb := b.mean()
+	return math.Sqrt((ar-br)*(ar-br) + (ag-bg)*(ag-bg) + (ab-bb)*(ab-bb))
+}
+
+func toHex(r, g, b float64) string {
+	return fmt.Sprintf("#%02x%02x%02x", uint8(math.Round(r)), uint8(math.Round(g)), uint8(math.Round(b)))
+}
+
+// parseHex parses "#rrggbb".
+func parseHex(hex string) (r, g, b float64, ok bool) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return float64(v >> 16 & 0xff), float64(v >> 8 & 0xff), float64(v & 0xff), true
+}
+
+// Blend mixes two "#rrggbb" colors; t=0 returns a, t=1 returns b. An
+// unparsable input returns the other color unchanged.
+func Blend(a, b string, t float64) string {
+	ar, ag, ab, okA := parseHex(a)
+	br, bg, bb, okB := parseHex(b)
+	switch {
+	case !okA:
+		return b
+	case !okB:
+		return a
+	}
+	return toHex(ar+(br-ar)*t, ag+(bg-ag)*t, ab+(bb-ab)*t)
+}
+
+// IsLight reports whether text on this color should be dark, using
+// perceived luminance.
+func IsLight(hex string) bool {
+	r, g, b, ok := parseHex(hex)
+	if !ok {
+		return false
+	}
+	return 0.299*r+0.587*g+0.114*b > 150
+}
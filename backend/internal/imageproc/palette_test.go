@@ -0,0 +1,43 @@
+package imageproc
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestPaletteOrdersByCoverage(t *testing.T) {
+	img := solid(100, 100, color.NRGBA{20, 40, 200, 255})
+	// The top fifth of the frame is orange; a slightly different blue row
+	// must merge into the main blue swatch rather than become its own.
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 100; x++ {
+			img.Set(x, y, color.NRGBA{240, 140, 20, 255})
+		}
+	}
+	for x := 0; x < 100; x++ {
+		img.Set(x, 50, color.NRGBA{24, 44, 206, 255})
+	}
+
+	got := Palette(img, PaletteSize)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 swatches, got %+v", got)
+	}
+	if got[0].Hex[:3] != "#14" || got[0].Weight < 0.75 {
+		t.Fatalf("blue should dominate: %+v", got)
+	}
+	if got[1].Hex != "#f08c14" {
+		t.Fatalf("second swatch = %s, want #f08c14", got[1].Hex)
+	}
+}
+
+func TestBlendAndIsLight(t *testing.T) {
+	if got := Blend("#000000", "#ffffff", 0.5); got != "#808080" {
+		t.Errorf("Blend = %s", got)
+	}
+	if got := Blend("bogus", "#123456", 0.5); got != "#123456" {
+		t.Errorf("Blend with bad input = %s", got)
+	}
+	if !IsLight("#f5f5f5") || IsLight("#202040") {
+		t.Error("IsLight misclassified")
+	}
+}
@@ -0,0 +1,57 @@
+package imageproc
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+)
+
+// thumbnailQuality is the JPEG quality thumbnails are encoded at.
+const thumbnailQuality = 80
+
+// Thumbnail scales img so its longer side is at most maxSide and encodes it
+// as JPEG. Each output pixel averages a grid of at most 4x4 source pixels,
+// which is smooth enough for a preview and cheap on large photos.
+func Thumbnail(img image.Image, maxSide int) ([]byte, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return nil, ErrUnsupportedImage
+	}
+
+	tw, th := w, h
+	if w >= h && w > maxSide {
+		tw, th = maxSide, max(1, h*maxSide/w)
+	} else if h > w && h > maxSide {
+		tw, th = max(1, w*maxSide/h), maxSide
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+(x+1)*w/tw
+			dst.Set(x, y, boxAverage(img, x0, y0, max(x1, x0+1), max(y1, y0+1)))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// boxAverage averages up to 4x4 evenly spaced pixels of [x0,x1)x[y0,y1).
+func boxAverage(img image.Image, x0, y0, x1, y1 int) color.RGBA {
+	stepX, stepY := max(1, (x1-x0)/4), max(1, (y1-y0)/4)
+	var r, g, bl, n uint32
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			cr, cg, cb, _ := img.At(x, y).RGBA()
+			r, g, bl, n = r+cr>>8, g+cg>>8, bl+cb>>8, n+1
+		}
+	}
+	return color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: 255}
+}
@@ -0,0 +1,36 @@
+package imageproc
+
+import (
+	"bytes"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestThumbnailFitsLongerSide(t *testing.T) {
+	data, err := Thumbnail(solid(1200, 600, color.NRGBA{R: 200, G: 40, B: 40, A: 255}), 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("thumbnail is not a JPEG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 256 || b.Dy() != 128 {
+		t.Errorf("thumbnail is %dx%d, want 256x128", b.Dx(), b.Dy())
+	}
+	if r, _, _, _ := img.At(100, 60).RGBA(); r>>8 < 180 {
+		t.Errorf("thumbnail lost the source color: red=%d", r>>8)
+	}
+}
+
+func TestThumbnailKeepsSmallImages(t *testing.T) {
+	data, err := Thumbnail(solid(40, 90, color.White), 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, _ := jpeg.Decode(bytes.NewReader(data))
+	if b := img.Bounds(); b.Dx() != 40 || b.Dy() != 90 {
+		t.Errorf("thumbnail is %dx%d, want 40x90", b.Dx(), b.Dy())
+	}
+}
@@ -0,0 +1,57 @@
+package jwtkeys
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func parse(t *testing.T, k *Keyring, token string) (*jwt.Token, error) {
+	t.Helper()
+	return jwt.Parse(token, k.Keyfunc)
+}
+
+func TestRotation(t *testing.T) {
+	old := New("old-secret", "")
+	token, err := old.Sign(jwt.MapClaims{"sub": "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := New("new-secret", "old-secret")
+	parsed, err := parse(t, rotated, token)
+	if err != nil {
+		t.Fatalf("token from previous secret rejected: %v", err)
+	}
+	if rotated.IsCurrent(parsed) {
+		t.Error("token from previous secret reported as current")
+	}
+
+	if _, err := parse(t, New("new-secret", ""), token); err == nil {
+		t.Error("token accepted after its secret was dropped")
+	}
+}
+
+func TestLegacyTokenWithoutKid(t *testing.T) {
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "u1"}).SignedString([]byte("old-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parse(t, New("new-secret", "old-secret"), legacy); err != nil {
+		t.Errorf("legacy token rejected: %v", err)
+	}
+	if _, err := parse(t, New("new-secret", ""), legacy); err == nil {
+		t.Error("legacy token accepted with unknown secret")
+	}
+}
+
+func TestRejectsOtherAlgorithms(t *testing.T) {
+	k := New("secret", "")
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": "u1"}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parse(t, k, none); err == nil {
+		t.Error("unsigned token accepted")
+	}
+}
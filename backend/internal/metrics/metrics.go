@@ -0,0 +1,210 @@
+// Package metrics holds the Prometheus collectors exposed on /metrics.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "aurasnap"
+
+// Registry is private to the app so tests and tooling get a clean set of collectors.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// ScansTotal counts scan pipeline outcomes: attempted, succeeded, failed,
+	// and regenerated or regenerate_failed for regenerations.
+	ScansTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scans_total",
+		Help:      "Aura scans by outcome (attempted, succeeded, failed, regenerated, regenerate_failed).",
+	}, []string{"outcome"})
+
+	// ScanFallbackTotal counts readings produced without AI output, by reason.
+	ScanFallbackTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scan_fallback_total",
+		Help:      "Aura readings that fell back to the deterministic engine, by reason (ai_error, ai_disabled, ai_budget, mock_mode).",
+	}, []string{"reason"})
+
+	// ImageModerationTotal counts scan photo screenings by outcome.
+	ImageModerationTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "image_moderation_total",
+		Help:      "Scan photos screened before analysis, by outcome (passed, rejected, error).",
+	}, []string{"outcome"})
+
+	// FaceDetectionTotal counts scan photo face checks by outcome.
+	FaceDetectionTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "face_detection_total",
+		Help:      "Scan photos checked for a face before analysis, by outcome (face, no_face, error).",
+	}, []string{"outcome"})
+
+	// AIProviderDuration measures each AI provider call.
+	AIProviderDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ai_provider_request_duration_seconds",
+		Help:      "AI provider request latency by provider and outcome.",
+		Buckets:   []float64{0.25, 0.5, 1, 2, 4, 8, 15, 30},
+	}, []string{"provider", "outcome"})
+
+	// AITokensTotal counts provider tokens by provider and kind (prompt or
+	// completion).
+	AITokensTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_tokens_total",
+		Help:      "AI provider tokens by provider and kind (prompt, completion).",
+	}, []string{"provider", "kind"})
+
+	// AISpendUSDTotal accumulates the priced cost of provider calls.
+	AISpendUSDTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ai_spend_usd_total",
+		Help:      "Priced AI provider spend in USD, by provider.",
+	}, []string{"provider"})
+
+	// HTTPRequestDuration measures requests by route template, not raw path.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request duration by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// DBQueryDuration measures GORM operations by type and table.
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query duration by operation and table.",
+		Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	}, []string{"operation", "table"})
+
+	// QuotaRejectionsTotal counts requests refused by usage limits.
+	QuotaRejectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "quota_rejections_total",
+		Help:      "Requests rejected by usage quotas, by quota.",
+	}, []string{"quota"})
+
+	// ReplayRejectionsTotal counts requests refused by replay protection.
+	ReplayRejectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "replay_rejections_total",
+		Help:      "Requests rejected by replay protection, by endpoint group and reason (invalid, expired, replayed).",
+	}, []string{"group", "reason"})
+
+	// ReplayNonceMissingTotal counts requests let through without a nonce
+	// while nonces are not required.
+	ReplayNonceMissingTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "replay_nonce_missing_total",
+		Help:      "Requests accepted without X-Request-Nonce because nonces are not required, by endpoint group.",
+	}, []string{"group"})
+
+	// AnalyticsEventsTotal counts product analytics deliveries.
+	AnalyticsEventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "analytics_events_total",
+		Help:      "Product analytics events by event and outcome (sent, failed, dropped).",
+	}, []string{"event", "outcome"})
+
+	// RequestLogsDroppedTotal counts failed requests not kept for support
+	// lookups because the write queue was full.
+	RequestLogsDroppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "request_logs_dropped_total",
+		Help:      "Failed requests dropped instead of recorded for lookup, because the queue was full.",
+	})
+
+	// QueueTasksTotal counts background tasks by kind and outcome: enqueued,
+	// succeeded, retried or dead (out of attempts).
+	QueueTasksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "queue_tasks_total",
+		Help:      "Background tasks by kind and outcome (enqueued, succeeded, retried, dead).",
+	}, []string{"kind", "outcome"})
+
+	// SchedulerJobRunsTotal counts scheduled job runs by outcome: succeeded,
+	// failed, locked (another instance held the job) or overlapping (the
+	// previous run had not finished).
+	SchedulerJobRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scheduler_job_runs_total",
+		Help:      "Scheduled job runs by job and outcome (succeeded, failed, locked, overlapping).",
+	}, []string{"job", "outcome"})
+
+	// SchedulerJobDuration measures scheduled jobs that ran on this instance.
+	SchedulerJobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scheduler_job_duration_seconds",
+		Help:      "Scheduled job duration by job.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900},
+	}, []string{"job"})
+
+	// SyntheticProbeTotal counts synthetic probe runs by outcome: success or
+	// the stage that failed (user, scan, status, result, ai_fallback).
+	SyntheticProbeTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "synthetic_probe_total",
+		Help:      "Synthetic scan probe runs by outcome (success or failed stage).",
+	}, []string{"outcome"})
+
+	// SyntheticProbeUp is 1 when the last probe passed and 0 when it failed.
+	SyntheticProbeUp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "synthetic_probe_up",
+		Help:      "Whether the last synthetic scan probe passed.",
+	})
+
+	// SyntheticProbeLastSuccess lets alerts catch a probe that stopped running.
+	SyntheticProbeLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "synthetic_probe_last_success_timestamp_seconds",
+		Help:      "Unix time of the last passing synthetic scan probe.",
+	})
+
+	// SyntheticProbeDuration measures end-to-end probe scans.
+	SyntheticProbeDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "synthetic_probe_duration_seconds",
+		Help:      "Synthetic scan probe duration.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120},
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		ScansTotal,
+		ScanFallbackTotal,
+		ImageModerationTotal,
+		FaceDetectionTotal,
+		AIProviderDuration,
+		AITokensTotal,
+		AISpendUSDTotal,
+		HTTPRequestDuration,
+		DBQueryDuration,
+		QuotaRejectionsTotal,
+		ReplayRejectionsTotal,
+		ReplayNonceMissingTotal,
+		AnalyticsEventsTotal,
+		RequestLogsDroppedTotal,
+		QueueTasksTotal,
+		SchedulerJobRunsTotal,
+		SchedulerJobDuration,
+		SyntheticProbeTotal,
+		SyntheticProbeUp,
+		SyntheticProbeLastSuccess,
+		SyntheticProbeDuration,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
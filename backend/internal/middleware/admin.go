@@ -4,18 +4,20 @@ import (
 	"crypto/subtle"
 	"strings"
 
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
-	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // AdminOnly restricts access to explicitly configured admin identities.
 //
 // Supported mechanisms:
-// - JWT claim match: `email` in ADMIN_EMAILS (comma-separated), or `sub` in ADMIN_USER_IDS (comma-separated UUIDs).
+// - JWT subject match: `sub` in ADMIN_USER_IDS (comma-separated UUIDs), or the account's email in ADMIN_EMAILS.
+// - The email is looked up server-side by `sub`; tokens carry no email or admin flag.
 // - Optional header token: `X-Admin-Token` equals ADMIN_TOKEN (constant-time compare).
-func AdminOnly(cfg *config.Config) fiber.Handler {
+func AdminOnly(cfg *config.Config, accountEmail func(userID uuid.UUID) string) fiber.Handler {
 	emailAllow := make(map[string]struct{})
 	for _, e := range strings.Split(cfg.AdminEmails, ",") {
 		e = strings.ToLower(strings.TrimSpace(e))
@@ -43,27 +45,26 @@ func AdminOnly(cfg *config.Config) fiber.Handler {
 
 		token, ok := c.Locals("user").(*jwt.Token)
 		if !ok {
-			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{Error: true, Message: "Forbidden"})
+			return apperr.New(fiber.StatusForbidden, "Forbidden")
 		}
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{Error: true, Message: "Forbidden"})
+			return apperr.New(fiber.StatusForbidden, "Forbidden")
 		}
 
-		email, _ := claims["email"].(string)
-		if email != "" {
-			if _, ok := emailAllow[strings.ToLower(email)]; ok {
+		sub, _ := claims["sub"].(string)
+		if sub != "" {
+			if _, ok := idAllow[sub]; ok {
 				return c.Next()
 			}
 		}
 
-		sub, _ := claims["sub"].(string)
-		if sub != "" {
-			if _, ok := idAllow[sub]; ok {
+		if userID, err := uuid.Parse(sub); err == nil && len(emailAllow) > 0 {
+			if _, ok := emailAllow[strings.ToLower(accountEmail(userID))]; ok {
 				return c.Next()
 			}
 		}
 
-		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{Error: true, Message: "Forbidden"})
+		return apperr.New(fiber.StatusForbidden, "Forbidden")
 	}
 }
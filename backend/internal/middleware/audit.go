@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AuditAdminRequests records every state-changing admin request (anything
+// but GET, HEAD and OPTIONS) in the audit log once it has been handled: who
+// made it, the route as action ("admin POST /users/:id/logout"), the :id
+// parameter as target, the client IP and the response status. Failed
+// requests are recorded too. Must run after JWTProtected.
+func AuditAdminRequests(record func(entry dto.AuditEntry)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+
+		err := c.Next()
+
+		actorID, parseErr := uuid.Parse(jwtSubject(c))
+		if parseErr != nil {
+			return err
+		}
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = errorStatus(err)
+		}
+
+		// Fiber leaves the matched handler's route on the context.
+		route := strings.TrimPrefix(c.Route().Path, "/api/admin")
+		record(dto.AuditEntry{
+			ActorID:    actorID,
+			Action:     "admin " + c.Method() + " " + route,
+			TargetType: "request",
+			TargetID:   c.Params("id"),
+			IP:         c.IP(),
+			Details: map[string]any{
+				"path":   c.Path(),
+				"status": status,
+			},
+		})
+		return err
+	}
+}
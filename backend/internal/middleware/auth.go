@@ -1,37 +1,69 @@
 package middleware
 
 import (
+	"log"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
-	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/jwtkeys"
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-func JWTProtected(cfg *config.Config) fiber.Handler {
+// AccessTokenHeader carries a re-issued access token when the request's
+// token was signed with a previous secret. Clients should replace their
+// stored token with it.
+const AccessTokenHeader = "X-Access-Token"
+
+// JWTProtected verifies the bearer token against the current and previous
+// JWT secrets, then asks tokenValid whether the account still accepts a
+// token of that session issued at that time (it rejects tokens from before
+// a force-logout and of signed-out sessions). sessionID is uuid.Nil for
+// tokens without a "sid" claim.
+func JWTProtected(cfg *config.Config, tokenValid func(userID, sessionID uuid.UUID, issuedAt time.Time) bool) fiber.Handler {
+	keys := jwtkeys.New(cfg.JWTSecret, cfg.JWTPreviousSecrets)
 	return jwtware.New(jwtware.Config{
-		SigningKey: jwtware.SigningKey{Key: []byte(cfg.JWTSecret)},
+		KeyFunc: keys.Keyfunc,
 		SuccessHandler: func(c *fiber.Ctx) error {
 			token, ok := c.Locals("user").(*jwt.Token)
 			if !ok {
-				return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-					Error:   true,
-					Message: "Unauthorized: invalid or expired token",
-				})
+				return apperr.New(fiber.StatusUnauthorized, "Unauthorized: invalid or expired token")
 			}
 			claims, ok := token.Claims.(jwt.MapClaims)
 			if !ok {
-				return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-					Error:   true,
-					Message: "Unauthorized: invalid or expired token",
-				})
+				return apperr.New(fiber.StatusUnauthorized, "Unauthorized: invalid or expired token")
 			}
 			sub, _ := claims["sub"].(string)
 			if sub == "" {
-				return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-					Error:   true,
-					Message: "Unauthorized: invalid or expired token",
-				})
+				return apperr.New(fiber.StatusUnauthorized, "Unauthorized: invalid or expired token")
+			}
+			userID, err := uuid.Parse(sub)
+			if err != nil {
+				return apperr.New(fiber.StatusUnauthorized, "Unauthorized: invalid or expired token")
+			}
+			var issuedAt time.Time
+			if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+				issuedAt = iat.Time
+			}
+			sessionID := uuid.Nil
+			if sid, _ := claims["sid"].(string); sid != "" {
+				sessionID, _ = uuid.Parse(sid)
+			}
+			if !tokenValid(userID, sessionID, issuedAt) {
+				return apperr.New(fiber.StatusUnauthorized, "Unauthorized: session has been signed out")
+			}
+
+			// Re-sign tokens from a rotated-out secret with the same claims and
+			// expiry so clients move to the current key without logging in.
+			if !keys.IsCurrent(token) {
+				if reissued, err := keys.Sign(claims); err == nil {
+					c.Set(AccessTokenHeader, reissued)
+				} else {
+					log.Printf("jwt: re-issue failed: %v", err)
+				}
 			}
 
 			// Keep compatibility with handlers that read userID from Fiber locals.
@@ -39,10 +71,7 @@ func JWTProtected(cfg *config.Config) fiber.Handler {
 			return c.Next()
 		},
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   true,
-				Message: "Unauthorized: invalid or expired token",
-			})
+			return apperr.New(fiber.StatusUnauthorized, "Unauthorized: invalid or expired token")
 		},
 	})
 }
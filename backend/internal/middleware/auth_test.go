@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/jwtkeys"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestJWTProtectedChecksSession(t *testing.T) {
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	userID, live, revoked := uuid.New(), uuid.New(), uuid.New()
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(cfg)})
+	app.Get("/me", JWTProtected(cfg, func(u, session uuid.UUID, _ time.Time) bool {
+		return u == userID && session != revoked
+	}), func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	keys := jwtkeys.New(cfg.JWTSecret, "")
+	status := func(claims jwt.MapClaims) int {
+		token, err := keys.Sign(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(fiber.MethodGet, "/me", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+	claims := func(sid string) jwt.MapClaims {
+		c := jwt.MapClaims{"sub": userID.String(), "iat": time.Now().Unix(), "exp": time.Now().Add(time.Minute).Unix()}
+		if sid != "" {
+			c["sid"] = sid
+		}
+		return c
+	}
+
+	if got := status(claims(live.String())); got != fiber.StatusOK {
+		t.Errorf("live session: status %d", got)
+	}
+	if got := status(claims(revoked.String())); got != fiber.StatusUnauthorized {
+		t.Errorf("signed-out session: status %d, want 401", got)
+	}
+	if got := status(claims("")); got != fiber.StatusOK {
+		t.Errorf("token without a session: status %d", got)
+	}
+}
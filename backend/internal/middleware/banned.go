@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RejectBanned blocks banned users from every route except those under the
+// allowed path prefixes (account management and appeals). Must run after
+// JWTProtected.
+func RejectBanned(isBanned func(userID uuid.UUID) bool, allowedPrefixes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		for _, prefix := range allowedPrefixes {
+			if strings.HasPrefix(c.Path(), prefix) {
+				return c.Next()
+			}
+		}
+
+		userID, err := uuid.Parse(jwtSubject(c))
+		if err != nil {
+			return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+		}
+		if isBanned(userID) {
+			return apperr.New(fiber.StatusForbidden, "Your account has been suspended. You can appeal from the app.")
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/cache"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// revalidateHeader marks the internal request that refreshes a stale entry;
+// its value is a per-process secret so clients can't use it to skip the cache.
+const revalidateHeader = "X-Cache-Revalidate"
+
+var revalidateToken = func() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}()
+
+// CacheConfig configures caching for one route.
+type CacheConfig struct {
+	// TTL is how long a response is served without touching the handler.
+	TTL time.Duration
+	// StaleWhileRevalidate is how long after TTL the old response is still
+	// served while a background request refreshes it.
+	StaleWhileRevalidate time.Duration
+	// Tags name the content the response is built from, for cache.Store.Purge.
+	Tags []string
+}
+
+// Cache serves successful GET responses from the store. Only use it on
+// routes whose response is the same for every caller.
+func Cache(store *cache.Store, cfg CacheConfig) fiber.Handler {
+	cacheControl := fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d",
+		int(cfg.TTL.Seconds()), int(cfg.StaleWhileRevalidate.Seconds()))
+
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet {
+			return c.Next()
+		}
+		key := string(c.Request().URI().RequestURI())
+
+		if c.Get(revalidateHeader) != revalidateToken {
+			entry, freshness := store.Get(key)
+			switch freshness {
+			case cache.Fresh:
+				return serveCached(c, entry, "HIT", cacheControl)
+			case cache.Stale:
+				if store.StartRevalidate(key) {
+					revalidate(c.App(), c.Context())
+				}
+				return serveCached(c, entry, "STALE", cacheControl)
+			}
+		}
+
+		if err := c.Next(); err != nil {
+			store.EndRevalidate(key)
+			return err
+		}
+		if c.Response().StatusCode() != fiber.StatusOK {
+			store.EndRevalidate(key)
+			return nil
+		}
+		store.Set(key, cache.Entry{
+			Status:      fiber.StatusOK,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}, cfg.TTL, cfg.StaleWhileRevalidate, cfg.Tags...)
+		c.Set("X-Cache", "MISS")
+		c.Set(fiber.HeaderCacheControl, cacheControl)
+		return nil
+	}
+}
+
+func serveCached(c *fiber.Ctx, entry *cache.Entry, state, cacheControl string) error {
+	c.Set("X-Cache", state)
+	c.Set(fiber.HeaderCacheControl, cacheControl)
+	c.Set(fiber.HeaderAge, fmt.Sprint(int(time.Since(entry.StoredAt).Seconds())))
+	c.Set(fiber.HeaderContentType, entry.ContentType)
+	return c.Status(entry.Status).Send(entry.Body)
+}
+
+// revalidate replays a copy of the request through the app in the
+// background; the Cache middleware stores the fresh response. The copy is
+// taken before returning because fasthttp reuses orig after the handler.
+func revalidate(app *fiber.App, orig *fasthttp.RequestCtx) {
+	rc := new(fasthttp.RequestCtx)
+	rc.Init(&orig.Request, orig.RemoteAddr(), nil)
+	rc.Request.Header.Set(revalidateHeader, revalidateToken)
+	go app.Handler()(rc)
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"log"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+)
+
+var compressionLevels = map[string]compress.Level{
+	"speed":   compress.LevelBestSpeed,
+	"default": compress.LevelDefault,
+	"best":    compress.LevelBestCompression,
+}
+
+// Compress encodes responses with brotli, gzip or deflate, whichever the
+// client prefers, at the level set by COMPRESSION ("speed", "default",
+// "best", or "off"). Photos are already compressed and WebSocket upgrades
+// must not be touched, so both are skipped.
+func Compress(cfg *config.Config) fiber.Handler {
+	setting := strings.ToLower(strings.TrimSpace(cfg.Compression))
+	level, ok := compressionLevels[setting]
+	if !ok {
+		if setting != "off" {
+			log.Printf("compress: unknown COMPRESSION %q, responses are sent uncompressed", cfg.Compression)
+		}
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	return compress.New(compress.Config{Level: level, Next: skipCompression})
+}
+
+func skipCompression(c *fiber.Ctx) bool {
+	path := c.Path()
+	return strings.HasSuffix(path, "/photo") ||
+		strings.HasSuffix(path, "/thumbnail") ||
+		strings.HasSuffix(path, "/ws") ||
+		strings.EqualFold(c.Get(fiber.HeaderUpgrade), "websocket")
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCompressSkipsPhotos(t *testing.T) {
+	app := fiber.New()
+	app.Use(Compress(&config.Config{Compression: "default"}))
+	body := strings.Repeat("a calm violet aura ", 200)
+	app.Get("/api/aura", func(c *fiber.Ctx) error { return c.SendString(body) })
+	app.Get("/api/aura/:id/photo", func(c *fiber.Ctx) error { return c.SendString(body) })
+
+	for path, want := range map[string]string{"/api/aura": "gzip", "/api/aura/1/photo": ""} {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		req.Header.Set(fiber.HeaderAcceptEncoding, "gzip")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.Header.Get(fiber.HeaderContentEncoding); got != want {
+			t.Errorf("%s: Content-Encoding = %q, want %q", path, got, want)
+		}
+	}
+}
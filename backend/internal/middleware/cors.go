@@ -9,8 +9,9 @@ import (
 func CORS(cfg *config.Config) fiber.Handler {
 	return cors.New(cors.Config{
 		AllowOrigins:     cfg.CORSOrigins,
-		AllowHeaders:     "Origin, Content-Type, Authorization, Accept",
+		AllowHeaders:     "Origin, Content-Type, Authorization, Accept, X-Device-Name, X-Platform, X-App-Version, " + fiber.HeaderIfNoneMatch + ", " + nonceHeader + ", " + timestampHeader,
 		AllowMethods:     "GET, POST, PUT, DELETE, PATCH, OPTIONS",
+		ExposeHeaders:    AccessTokenHeader + ", " + fiber.HeaderETag + ", " + RequestIDHeader,
 		AllowCredentials: false,
 	})
 }
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/buildinfo"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+// VersionSkew tags responses with the server's build and API version and
+// refuses clients more than one API version behind with 426, so they are
+// told to update instead of failing on a shape they cannot read. Newer
+// clients, and clients that send no X-API-Version, are served: during a
+// rolling deploy either side may be one version ahead.
+func VersionSkew() fiber.Handler {
+	version := buildinfo.Get().Version
+	apiVersion := strconv.Itoa(buildinfo.APIVersion)
+
+	return func(c *fiber.Ctx) error {
+		c.Set("X-Server-Version", version)
+		c.Set("X-API-Version", apiVersion)
+
+		if v, err := strconv.Atoi(strings.TrimSpace(c.Get("X-API-Version"))); err == nil && v < buildinfo.APIVersion-1 {
+			return apperr.New(fiber.StatusUpgradeRequired, "This app version is no longer supported; please update").WithCode("client_outdated")
+		}
+		return c.Next()
+	}
+}
+
+// Draining asks clients to close keep-alive connections once the instance
+// is draining, so they reconnect through the load balancer to another one
+// rather than reuse a socket that is about to close.
+func Draining(draining func() bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if draining() {
+			c.Response().SetConnectionClose()
+		}
+		return c.Next()
+	}
+}
+
+// DeployHookAuth protects the deploy hooks with DEPLOY_HOOK_TOKEN. The hooks
+// are only registered when it is set.
+func DeployHookAuth(cfg *config.Config) fiber.Handler {
+	token := strings.TrimSpace(cfg.DeployHookToken)
+
+	return func(c *fiber.Ctx) error {
+		got := strings.TrimSpace(strings.TrimPrefix(c.Get("Authorization"), "Bearer "))
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+		}
+		return c.Next()
+	}
+}
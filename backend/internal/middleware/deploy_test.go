@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/buildinfo"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestVersionSkewToleratesOneVersion(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(&config.Config{Environment: "production"})})
+	app.Use(VersionSkew())
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	tests := map[string]int{
+		"":                                     fiber.StatusOK,
+		strconv.Itoa(buildinfo.APIVersion):     fiber.StatusOK,
+		strconv.Itoa(buildinfo.APIVersion + 1): fiber.StatusOK,
+		strconv.Itoa(buildinfo.APIVersion - 1): fiber.StatusOK,
+		strconv.Itoa(buildinfo.APIVersion - 2): fiber.StatusUpgradeRequired,
+		"not-a-number":                         fiber.StatusOK,
+	}
+	for header, want := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set("X-API-Version", header)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != want {
+			t.Errorf("X-API-Version %q: status %d, want %d", header, resp.StatusCode, want)
+		}
+		if resp.Header.Get("X-API-Version") != strconv.Itoa(buildinfo.APIVersion) {
+			t.Errorf("X-API-Version %q: response header %q", header, resp.Header.Get("X-API-Version"))
+		}
+	}
+}
+
+func TestDrainingClosesConnections(t *testing.T) {
+	draining := false
+	app := fiber.New()
+	app.Use(Draining(func() bool { return draining }))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	resp, _ := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if resp.Close {
+		t.Error("Connection: close before draining")
+	}
+	draining = true
+	resp, _ = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if !resp.Close {
+		t.Error("no Connection: close while draining")
+	}
+}
+
+func TestDecodeJSONIgnoresUnknownFieldsAndAppliesDefaults(t *testing.T) {
+	app := fiber.New(fiber.Config{JSONDecoder: dto.DecodeJSON})
+	var got dto.NotificationPreferencesRequest
+	app.Post("/", func(c *fiber.Ctx) error { return c.BodyParser(&got) })
+
+	req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(`{"social_delivery":" Digest ","added_next_version":true}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status %d, want an unknown field to be ignored", resp.StatusCode)
+	}
+	if got.SocialDelivery != "digest" {
+		t.Errorf("social_delivery = %q, want the default normalization to digest", got.SocialDelivery)
+	}
+}
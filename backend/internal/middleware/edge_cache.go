@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EdgePolicy is how long a public response may be cached by clients and by
+// the CDN, and the surrogate keys it is purged by.
+type EdgePolicy struct {
+	// MaxAge applies to browsers and apps.
+	MaxAge time.Duration
+	// SharedMaxAge applies to the CDN, which is purged on change and so can
+	// keep responses far longer than clients.
+	SharedMaxAge time.Duration
+	// StaleWhileRevalidate is how long after expiry a stale copy may still
+	// be served while a fresh one is fetched.
+	StaleWhileRevalidate time.Duration
+	// Keys name the content the response is built from. Use the tags the
+	// in-process Cache uses, so one admin purge clears both.
+	Keys []string
+}
+
+// EdgeCache applies the policy to successful responses: Cache-Control plus
+// the keys as Surrogate-Key (Fastly) and Cache-Tag (Cloudflare). Other
+// responses are left uncacheable. Only use it on public routes; a response
+// for one user must never reach a shared cache.
+func EdgeCache(p EdgePolicy) fiber.Handler {
+	cacheControl := fmt.Sprintf("public, max-age=%d, s-maxage=%d",
+		int(p.MaxAge.Seconds()), int(p.SharedMaxAge.Seconds()))
+	if p.StaleWhileRevalidate > 0 {
+		cacheControl += fmt.Sprintf(", stale-while-revalidate=%d", int(p.StaleWhileRevalidate.Seconds()))
+	}
+	surrogateKeys := strings.Join(p.Keys, " ")
+	cacheTags := strings.Join(p.Keys, ",")
+
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if c.Response().StatusCode() != fiber.StatusOK {
+			c.Set(fiber.HeaderCacheControl, "no-store")
+			return nil
+		}
+		c.Set(fiber.HeaderCacheControl, cacheControl)
+		if surrogateKeys != "" {
+			c.Set("Surrogate-Key", surrogateKeys)
+			c.Set("Cache-Tag", cacheTags)
+		}
+		return nil
+	}
+}
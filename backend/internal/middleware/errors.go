@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/gofiber/fiber/v2"
+)
+
+// internalErrorMessage replaces the message of internal errors in
+// production.
+const internalErrorMessage = "Something went wrong. Please try again later."
+
+// ErrorHandler is the app's fiber.Config.ErrorHandler. It writes every
+// error a handler returns as the shared envelope, with the status and code
+// apperr resolves for it, and logs server errors with their cause. In
+// production, internal errors get a generic message and causes are never
+// sent; elsewhere the cause is appended to help debugging. Every error
+// carries the request ID, which finds the request in the logs.
+func ErrorHandler(cfg *config.Config) fiber.ErrorHandler {
+	production := cfg.Production()
+
+	return func(c *fiber.Ctx, err error) error {
+		e, public := apperr.From(err)
+		requestID := RequestIDFrom(c)
+		if e.Status >= fiber.StatusInternalServerError {
+			log.Printf("error: %s %s: %d %s [%s]: %v", c.Method(), c.Path(), e.Status, e.Code, requestID, err)
+		}
+
+		message := e.Message
+		switch {
+		case !public && production:
+			message = internalErrorMessage
+		case !production && e.Err != nil && public && e.Err.Error() != message:
+			message += ": " + e.Err.Error()
+		}
+
+		c.Status(e.Status)
+		if e.Fields != nil {
+			return c.JSON(dto.ValidationErrorResponse{Error: true, Message: message, Code: e.Code, Fields: e.Fields, RequestID: requestID})
+		}
+		return c.JSON(dto.ErrorResponse{Error: true, Message: message, Code: e.Code, RequestID: requestID})
+	}
+}
+
+// errorStatus is the status ErrorHandler will write for err, for
+// middleware that record a request before the error is handled.
+func errorStatus(err error) int {
+	e, _ := apperr.From(err)
+	return e.Status
+}
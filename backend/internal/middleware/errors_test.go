@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/gofiber/fiber/v2"
+)
+
+var errTestGone = errors.New("thing was removed")
+
+func init() {
+	apperr.Register(errTestGone, fiber.StatusGone, "thing_removed")
+}
+
+func TestErrorHandlerEnvelope(t *testing.T) {
+	internal := errors.New("pq: relation \"users\" does not exist")
+	routes := map[string]error{
+		"/app":       apperr.New(fiber.StatusNotFound, "Reading not found"),
+		"/coded":     apperr.New(fiber.StatusForbidden, "Nope").WithCode("plan_required"),
+		"/wrapped":   apperr.Wrap(fiber.StatusConflict, fmt.Errorf("saving: %w", errTestGone)),
+		"/sentinel":  fmt.Errorf("loading: %w", errTestGone),
+		"/fiber":     fiber.ErrMethodNotAllowed,
+		"/internal":  internal,
+		"/caused":    apperr.New(fiber.StatusInternalServerError, "Failed to save").WithCause(internal),
+		"/validated": &apperr.Error{Status: fiber.StatusUnprocessableEntity, Code: apperr.CodeValidationFailed, Message: "Validation failed", Fields: []dto.FieldError{{Field: "email", Code: "required"}}},
+	}
+
+	tests := []struct {
+		env, path string
+		status    int
+		code      string
+		message   string
+	}{
+		{"production", "/app", fiber.StatusNotFound, "not_found", "Reading not found"},
+		{"production", "/coded", fiber.StatusForbidden, "plan_required", "Nope"},
+		{"production", "/wrapped", fiber.StatusConflict, "thing_removed", "saving: thing was removed"},
+		{"production", "/sentinel", fiber.StatusGone, "thing_removed", "loading: thing was removed"},
+		{"production", "/fiber", fiber.StatusMethodNotAllowed, "method_not_allowed", "Method Not Allowed"},
+		{"production", "/internal", fiber.StatusInternalServerError, "internal_error", internalErrorMessage},
+		{"production", "/caused", fiber.StatusInternalServerError, "internal_error", "Failed to save"},
+		{"development", "/internal", fiber.StatusInternalServerError, "internal_error", internal.Error()},
+		{"development", "/caused", fiber.StatusInternalServerError, "internal_error", "Failed to save: " + internal.Error()},
+	}
+	for _, tt := range tests {
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(&config.Config{Environment: tt.env})})
+		for path, err := range routes {
+			app.Get(path, func(c *fiber.Ctx) error { return err })
+		}
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body dto.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s %s: %v", tt.env, tt.path, err)
+		}
+		if resp.StatusCode != tt.status || !body.Error || body.Code != tt.code || body.Message != tt.message {
+			t.Errorf("%s %s: %d %+v, want %d %s %q", tt.env, tt.path, resp.StatusCode, body, tt.status, tt.code, tt.message)
+		}
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(&config.Config{})})
+	app.Get("/validated", func(c *fiber.Ctx) error { return routes["/validated"] })
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/validated", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body dto.ValidationErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusUnprocessableEntity || body.Code != "validation_failed" || len(body.Fields) != 1 {
+		t.Fatalf("validation: %d %+v", resp.StatusCode, body)
+	}
+}
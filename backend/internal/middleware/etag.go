@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// conditionalCacheControl lets clients and CDNs keep a copy of per-user
+// responses but makes them revalidate it with If-None-Match on every use.
+const conditionalCacheControl = "private, no-cache"
+
+// ETag tags successful GET responses with a hash of the body and answers
+// 304 Not Modified when the client's If-None-Match already has it. The
+// handler still runs; only the transfer of an unchanged payload is saved,
+// which is what the app's constant polling of these endpoints costs.
+func ETag() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		tag := string(c.Response().Header.Peek(fiber.HeaderETag))
+		if tag == "" {
+			tag = bodyETag(c.Response().Body())
+			c.Set(fiber.HeaderETag, tag)
+		}
+		if len(c.Response().Header.Peek(fiber.HeaderCacheControl)) == 0 {
+			c.Set(fiber.HeaderCacheControl, conditionalCacheControl)
+		}
+		c.Vary(fiber.HeaderAuthorization)
+
+		if etagMatches(c.Get(fiber.HeaderIfNoneMatch), tag) {
+			c.Context().ResetBody()
+			c.Response().Header.Del(fiber.HeaderContentLength)
+			c.Status(fiber.StatusNotModified)
+		}
+		return nil
+	}
+}
+
+// bodyETag tags the uncompressed body. The tag is weak because Compress
+// may send the same content with different encodings.
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches applies the weak comparison If-None-Match calls for: W/
+// prefixes are ignored and "*" matches any current representation.
+func etagMatches(header, tag string) bool {
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
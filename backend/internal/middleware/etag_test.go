@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestETagNotModified(t *testing.T) {
+	app := fiber.New()
+	app.Get("/profile", ETag(), func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"name": "aura"})
+	})
+	app.Get("/missing", ETag(), func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": true})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/profile", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := resp.Header.Get(fiber.HeaderETag)
+	if resp.StatusCode != fiber.StatusOK || tag == "" {
+		t.Fatalf("first request: status %d, etag %q", resp.StatusCode, tag)
+	}
+	if got := resp.Header.Get(fiber.HeaderCacheControl); got != conditionalCacheControl {
+		t.Fatalf("Cache-Control = %q", got)
+	}
+
+	strong := strings.TrimPrefix(tag, "W/")
+	for _, header := range []string{tag, strong, `"other", ` + tag} {
+		req := httptest.NewRequest(fiber.MethodGet, "/profile", nil)
+		req.Header.Set(fiber.HeaderIfNoneMatch, header)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != fiber.StatusNotModified || len(body) != 0 {
+			t.Fatalf("If-None-Match %s: status %d, %d body bytes", header, resp.StatusCode, len(body))
+		}
+	}
+
+	req := httptest.NewRequest(fiber.MethodGet, "/profile", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, `"stale"`)
+	if resp, _ := app.Test(req); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("stale tag: status %d", resp.StatusCode)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest(fiber.MethodGet, "/missing", nil))
+	if resp.Header.Get(fiber.HeaderETag) != "" {
+		t.Fatal("error responses must not be tagged")
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics records request duration per route template. Unmatched paths are
+// grouped as "unmatched" to keep label cardinality bounded.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = errorStatus(err)
+		}
+
+		route := "unmatched"
+		if r := c.Route(); r != nil && r.Path != "/" && r.Path != "" {
+			route = r.Path
+		}
+
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Method(), route, strconv.Itoa(status)).
+			Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// MetricsAuth protects /metrics with a bearer token when METRICS_TOKEN is set.
+func MetricsAuth(cfg *config.Config) fiber.Handler {
+	token := strings.TrimSpace(cfg.MetricsToken)
+
+	return func(c *fiber.Ctx) error {
+		if token == "" {
+			return c.Next()
+		}
+		got := strings.TrimSpace(strings.TrimPrefix(c.Get("Authorization"), "Bearer "))
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/ratelimit"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// RateLimitConfig configures one rate-limited endpoint group. A zero Rate
+// disables that dimension; PerUser only applies behind JWTProtected.
+type RateLimitConfig struct {
+	Name    string
+	PerIP   ratelimit.Rate
+	PerUser ratelimit.Rate
+}
+
+// RateLimit enforces per-IP and per-user token buckets and sets
+// X-RateLimit-Limit/Remaining/Reset from the most constrained bucket, plus
+// Retry-After on 429. If the limiter store fails the request is let through.
+func RateLimit(limiter ratelimit.Limiter, cfg RateLimitConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		type check struct {
+			key  string
+			rate ratelimit.Rate
+		}
+		var checks []check
+		if cfg.PerIP.Limit > 0 {
+			checks = append(checks, check{cfg.Name + ":ip:" + c.IP(), cfg.PerIP})
+		}
+		if cfg.PerUser.Limit > 0 {
+			if sub := jwtSubject(c); sub != "" {
+				checks = append(checks, check{cfg.Name + ":user:" + sub, cfg.PerUser})
+			}
+		}
+
+		var tightest *ratelimit.Result
+		for _, ch := range checks {
+			res, err := limiter.Allow(c.UserContext(), ch.key, ch.rate)
+			if err != nil {
+				log.Printf("ratelimit: %s check failed, allowing request: %v", cfg.Name, err)
+				continue
+			}
+			if tightest == nil || !res.Allowed || (tightest.Allowed && res.Remaining < tightest.Remaining) {
+				r := res
+				tightest = &r
+			}
+			if !res.Allowed {
+				break
+			}
+		}
+
+		if tightest == nil {
+			return c.Next()
+		}
+
+		c.Set("X-RateLimit-Limit", strconv.Itoa(tightest.Limit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+		c.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(tightest.ResetAfter)))
+
+		if !tightest.Allowed {
+			metrics.QuotaRejectionsTotal.WithLabelValues("rate_limit_" + cfg.Name).Inc()
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(tightest.RetryAfter)))
+			return apperr.New(fiber.StatusTooManyRequests, "Too many requests. Please try again later.")
+		}
+		return c.Next()
+	}
+}
+
+func jwtSubject(c *fiber.Ctx) string {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return ""
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	sub, _ := claims["sub"].(string)
+	return sub
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
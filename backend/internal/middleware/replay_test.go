@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/replay"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestReplayGuard(t *testing.T) {
+	guarded := func(required bool) *fiber.App {
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(&config.Config{Environment: "production"})})
+		app.Post("/scan", ReplayGuard(replay.NewMemoryStore(), ReplayConfig{Name: "scan", Window: time.Minute, Required: required}),
+			func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+		return app
+	}
+	send := func(app *fiber.App, nonce string) int {
+		req := httptest.NewRequest(fiber.MethodPost, "/scan", nil)
+		if nonce != "" {
+			req.Header.Set(nonceHeader, nonce)
+			req.Header.Set(timestampHeader, strconv.FormatInt(time.Now().Unix(), 10))
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	app := guarded(true)
+	for _, step := range []struct {
+		name  string
+		nonce string
+		want  int
+	}{
+		{"first submission", "nonce-0123456789abcdef", fiber.StatusCreated},
+		{"replay", "nonce-0123456789abcdef", fiber.StatusConflict},
+		{"replay without the nonce", "", fiber.StatusBadRequest},
+	} {
+		if got := send(app, step.nonce); got != step.want {
+			t.Errorf("%s: status %d, want %d", step.name, got, step.want)
+		}
+	}
+
+	if got := send(guarded(false), ""); got != fiber.StatusCreated {
+		t.Errorf("nonce not required: status %d without a nonce, want 201", got)
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID on every response. Error bodies
+// repeat it as request_id, so a screenshot of an error is enough to find
+// the request.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is where the ID is kept in Locals, and what the access log
+// format reads it from.
+const requestIDKey = "requestid"
+
+// RequestID gives every request an ID, returns it in the X-Request-ID
+// header and keeps it for the access log and ErrorHandler. The ID is the
+// request's trace ID (the client's, when it sent a traceparent), so it
+// also finds the request's spans; outside a trace it is a random ID of the
+// same form. Must run after the otelfiber middleware.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := requestTraceID(c)
+		if id == "" {
+			var b [16]byte
+			rand.Read(b[:])
+			id = hex.EncodeToString(b[:])
+		}
+		c.Locals(requestIDKey, id)
+		c.Set(RequestIDHeader, id)
+		return c.Next()
+	}
+}
+
+// RequestIDFrom returns the ID RequestID gave the request, or "".
+func RequestIDFrom(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestIDKey).(string)
+	return id
+}
+
+func requestTraceID(c *fiber.Ctx) string {
+	if id := trace.SpanContextFromContext(c.UserContext()).TraceID(); id.IsValid() {
+		return id.String()
+	}
+	return ""
+}
+
+// RecordFailedRequests hands every request that failed to record once it
+// has been handled, so support can look it up by request ID. Routine
+// failures are left out: 401s (expired tokens are refreshed all the time)
+// and requests for routes that don't exist. Must run after RequestID.
+func RecordFailedRequests(record func(entry dto.RequestLogEntry)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		var code, message string
+		if err != nil {
+			e, _ := apperr.From(err)
+			status, code, message = e.Status, e.Code, err.Error()
+		}
+		var unrouted *fiber.Error
+		if status < fiber.StatusBadRequest || status == fiber.StatusUnauthorized ||
+			(errors.As(err, &unrouted) && unrouted.Code == fiber.StatusNotFound) {
+			return err
+		}
+
+		entry := dto.RequestLogEntry{
+			RequestID:  RequestIDFrom(c),
+			Traced:     trace.SpanContextFromContext(c.UserContext()).IsSampled(),
+			Method:     c.Method(),
+			Route:      c.Route().Path,
+			TargetID:   c.Params("id"),
+			Status:     status,
+			Code:       code,
+			Error:      message,
+			LatencyMs:  time.Since(start).Milliseconds(),
+			Platform:   c.Get("X-Platform"),
+			AppVersion: c.Get("X-App-Version"),
+		}
+		if userID, parseErr := uuid.Parse(jwtSubject(c)); parseErr == nil {
+			entry.UserID = &userID
+		}
+		record(entry)
+		return err
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var requestIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+func TestRequestIDInHeaderAndErrors(t *testing.T) {
+	var recorded []dto.RequestLogEntry
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(&config.Config{Environment: "production"})})
+	app.Use(RequestID(), RecordFailedRequests(func(e dto.RequestLogEntry) { recorded = append(recorded, e) }))
+	app.Get("/ok", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	app.Get("/readings/:id", func(c *fiber.Ctx) error { return apperr.New(fiber.StatusNotFound, "Reading not found") })
+	app.Get("/private", func(c *fiber.Ctx) error { return apperr.New(fiber.StatusUnauthorized, "Unauthorized") })
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/readings/abc", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := resp.Header.Get(RequestIDHeader)
+	if !requestIDPattern.MatchString(id) {
+		t.Fatalf("%s = %q, want 32 hex characters", RequestIDHeader, id)
+	}
+	var body dto.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.RequestID != id {
+		t.Errorf("request_id = %q, want the header's %q", body.RequestID, id)
+	}
+	if len(recorded) != 1 || recorded[0].RequestID != id || recorded[0].Route != "/readings/:id" ||
+		recorded[0].TargetID != "abc" || recorded[0].Status != fiber.StatusNotFound || recorded[0].Code != "not_found" {
+		t.Errorf("recorded = %+v, want the failed reading request", recorded)
+	}
+
+	// Successes, 401s and unknown routes are not recorded.
+	for _, path := range []string{"/ok", "/private", "/nowhere"} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if other := resp.Header.Get(RequestIDHeader); !requestIDPattern.MatchString(other) || other == id {
+			t.Errorf("%s: %s = %q, want a new ID", path, RequestIDHeader, other)
+		}
+	}
+	if len(recorded) != 1 {
+		t.Errorf("recorded %d requests, want only the failed reading request", len(recorded))
+	}
+}
+
+func TestRequestIDIsTraceID(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(trace.ContextWithSpanContext(c.UserContext(), sc))
+		return c.Next()
+	}, RequestID())
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString(RequestIDFrom(c)) })
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Header.Get(RequestIDHeader); got != traceID.String() {
+		t.Errorf("%s = %q, want the trace ID %q", RequestIDHeader, got, traceID)
+	}
+}
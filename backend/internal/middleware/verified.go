@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/apperr"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RequireVerifiedEmail rejects users who haven't verified their email when
+// REQUIRE_VERIFIED_EMAIL is on; otherwise it lets every request through.
+// Must run after JWTProtected.
+func RequireVerifiedEmail(cfg *config.Config, isVerified func(userID uuid.UUID) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !cfg.RequireVerifiedEmail {
+			return c.Next()
+		}
+
+		userID, err := uuid.Parse(jwtSubject(c))
+		if err != nil {
+			return apperr.New(fiber.StatusUnauthorized, "Unauthorized")
+		}
+		if !isVerified(userID) {
+			return apperr.New(fiber.StatusForbidden, "Please verify your email address to use this feature")
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tickets an admin message can answer. Support tickets live in the support
+// desk, so their IDs are kept as given.
+const (
+	TicketSupport        = "support"
+	TicketReport         = "report"
+	TicketModerationCase = "moderation_case"
+	TicketAppeal         = "appeal"
+)
+
+// AdminMessage is a templated message an admin sent to a user. Messages
+// answering the same ticket share TicketType and TicketID, which form the
+// thread. Channels lists where it was delivered.
+type AdminMessage struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	AdminID    uuid.UUID `gorm:"type:uuid;not null" json:"admin_id"`
+	Template   string    `gorm:"not null;size:50" json:"template"`
+	Locale     string    `gorm:"not null;size:10" json:"locale"`
+	Title      string    `gorm:"type:text;not null" json:"title"`
+	Body       string    `gorm:"type:text;not null" json:"body"`
+	TicketType string    `gorm:"size:20;index:idx_admin_message_ticket" json:"ticket_type,omitempty"`
+	TicketID   string    `gorm:"size:100;index:idx_admin_message_ticket" json:"ticket_id,omitempty"`
+	Channels   []string  `gorm:"type:jsonb;serializer:json" json:"channels"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (AdminMessage) TableName() string {
+	return "admin_messages"
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AI interaction purposes. Match and forecast calls are only tracked in
+// AIUsage.
+const (
+	AIPurposeAuraScan        = "aura_scan"
+	AIPurposeGroupScan       = "group_scan"
+	AIPurposeImageModeration = "image_moderation"
+	AIPurposeFaceDetection   = "face_detection"
+	AIPurposeMatch           = "match"
+	AIPurposeMatchNarrative  = "match_narrative"
+	AIPurposeForecast        = "forecast"
+)
+
+// AIInteraction records one AI provider call made on a user's behalf, for
+// the user's AI history. Only metadata is kept: never the photo, the prompt
+// or the reply.
+type AIInteraction struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index:idx_ai_interaction_user_time" json:"user_id"`
+	Purpose    string    `gorm:"not null;size:30" json:"purpose"`
+	Provider   string    `gorm:"not null;size:20" json:"provider"`
+	Model      string    `gorm:"size:100" json:"model"`
+	Outcome    string    `gorm:"not null;size:10" json:"outcome"` // success or error
+	DurationMs int       `gorm:"not null;default:0" json:"duration_ms"`
+	CreatedAt  time.Time `gorm:"index:idx_ai_interaction_user_time" json:"created_at"`
+}
+
+func (AIInteraction) TableName() string {
+	return "ai_interactions"
+}
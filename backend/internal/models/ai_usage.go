@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AIUsage records the tokens and cost of one AI provider call, for spend
+// tracking and the daily AI budget. Unlike AIInteraction it is kept for
+// every call, including those not made on a user's behalf, and outlives
+// the user's account.
+type AIUsage struct {
+	ID               uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID           *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"`
+	Purpose          string     `gorm:"not null;size:30" json:"purpose"`
+	Provider         string     `gorm:"not null;size:20" json:"provider"`
+	Model            string     `gorm:"size:100" json:"model"`
+	PromptTokens     int        `gorm:"not null;default:0" json:"prompt_tokens"`
+	CompletionTokens int        `gorm:"not null;default:0" json:"completion_tokens"`
+	CostUSD          float64    `gorm:"not null;default:0" json:"cost_usd"`
+	LatencyMs        int        `gorm:"not null;default:0" json:"latency_ms"`
+	Outcome          string     `gorm:"not null;size:10" json:"outcome"` // success or error
+	CreatedAt        time.Time  `gorm:"index" json:"created_at"`
+}
+
+func (AIUsage) TableName() string {
+	return "ai_usage"
+}
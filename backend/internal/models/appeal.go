@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AppealPending  = "pending"
+	AppealApproved = "approved"
+	AppealRejected = "rejected"
+)
+
+// Appeal is a user's one request to have a moderation action reviewed.
+// DueAt is the review deadline (APPEAL_SLA after filing).
+type Appeal struct {
+	ID         uuid.UUID         `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ActionID   uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex" json:"action_id"`
+	UserID     uuid.UUID         `gorm:"type:uuid;not null;index" json:"user_id"`
+	Message    string            `gorm:"type:text;not null" json:"message"`
+	Status     string            `gorm:"not null;size:20;default:'pending';index" json:"status"`
+	AdminNote  string            `gorm:"size:1000" json:"admin_note,omitempty"`
+	ReviewedBy *uuid.UUID        `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time        `json:"reviewed_at,omitempty"`
+	DueAt      time.Time         `gorm:"index" json:"due_at"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	Action     *ModerationAction `gorm:"foreignKey:ActionID" json:"action,omitempty"`
+}
+
+func (Appeal) TableName() string {
+	return "appeals"
+}
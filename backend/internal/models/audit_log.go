@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog is an append-only record of a sensitive operation: admin
+// requests, moderation decisions, account deletion and subscription
+// changes. ActorID is nil for actions taken by the system itself. The
+// database rejects updates and deletes (see database.InitDB).
+type AuditLog struct {
+	ID         uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ActorID    *uuid.UUID     `gorm:"type:uuid;index" json:"actor_id,omitempty"`
+	Action     string         `gorm:"not null;size:100;index" json:"action"` // e.g. appeal.approved
+	TargetType string         `gorm:"not null;size:30;index:idx_audit_target" json:"target_type"`
+	TargetID   string         `gorm:"not null;size:64;index:idx_audit_target" json:"target_id"`
+	Details    map[string]any `gorm:"type:jsonb;serializer:json" json:"details,omitempty"`
+	IPAddress  string         `gorm:"size:45" json:"ip_address,omitempty"`
+	CreatedAt  time.Time      `gorm:"index" json:"created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
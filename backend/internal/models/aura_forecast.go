@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ForecastSourceAI       = "ai"
+	ForecastSourceTemplate = "template"
+)
+
+// AuraForecast is a user's personalized forecast for one local calendar day.
+type AuraForecast struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_forecast_user_day" json:"user_id"`
+	Day           string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_forecast_user_day" json:"day"`
+	DominantColor string    `gorm:"type:varchar(50);not null" json:"dominant_color"`
+	EnergyOutlook string    `gorm:"type:varchar(10);not null" json:"energy_outlook"`
+	Headline      string    `gorm:"type:varchar(200);not null" json:"headline"`
+	Forecast      string    `gorm:"type:text;not null" json:"forecast"`
+	Focus         string    `gorm:"type:varchar(100)" json:"focus"`
+	LuckyColor    string    `gorm:"type:varchar(50)" json:"lucky_color"`
+	Source        string    `gorm:"type:varchar(10);not null" json:"source"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (AuraForecast) TableName() string {
+	return "aura_forecasts"
+}
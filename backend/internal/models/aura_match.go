@@ -16,8 +16,19 @@ type AuraMatch struct {
 	Synergy            string    `gorm:"type:text" json:"synergy"`
 	Tension            string    `gorm:"type:text" json:"tension"`
 	Advice             string    `gorm:"type:text" json:"advice"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	// Component scores from the compatibility engine (0-100 each) and the
+	// one-line relationship blurb.
+	ColorAffinity   int    `gorm:"not null;default:0" json:"color_affinity"`
+	EnergyAlignment int    `gorm:"not null;default:0" json:"energy_alignment"`
+	MoodCorrelation int    `gorm:"not null;default:0" json:"mood_correlation"`
+	Blurb           string `gorm:"type:text" json:"blurb,omitempty"`
+	// Narrative is the premium LLM explanation, cached with the readings it was written from.
+	Narrative             string     `gorm:"type:text" json:"narrative,omitempty"`
+	NarrativeUserAuraID   *uuid.UUID `gorm:"type:uuid" json:"-"`
+	NarrativeFriendAuraID *uuid.UUID `gorm:"type:uuid" json:"-"`
+	NarrativeGeneratedAt  *time.Time `json:"narrative_generated_at,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
 }
 
 func (AuraMatch) TableName() string {
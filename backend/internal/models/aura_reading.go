@@ -7,22 +7,75 @@ import (
 	"gorm.io/gorm"
 )
 
+// Reading statuses. Async scans are stored as pending with a provisional
+// result and become ready when the AI analysis finishes (or gives up).
+const (
+	ReadingStatusPending = "pending"
+	ReadingStatusReady   = "ready"
+)
+
+// Reading sources other than the AI provider names (glm, deepseek, openai).
+// Both mean the reading was generated offline by the deterministic engine:
+// by design when no AI is configured, or as a stand-in when the AI call
+// failed or the daily AI budget was spent.
+const (
+	ReadingSourceDeterministic = "deterministic"
+	ReadingSourceMock          = "mock"
+)
+
+// AuraReading is one aura scan result. Readings with a GroupReadingID belong
+// to a person in a group photo rather than the user, and stay out of the
+// user's history, stats and matches.
 type AuraReading struct {
-	ID             uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
-	UserID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
-	ImageURL       string         `gorm:"type:text;not null" json:"image_url"`
-	AuraColor      string         `gorm:"type:varchar(50);not null" json:"aura_color"`
-	SecondaryColor *string        `gorm:"type:varchar(50);default:NULL" json:"secondary_color,omitempty"`
-	EnergyLevel    int            `gorm:"type:integer;check:energy_level >= 1 AND energy_level <= 100" json:"energy_level"`
-	MoodScore      int            `gorm:"type:integer;check:mood_score >= 1 AND mood_score <= 10" json:"mood_score"`
-	Personality    string         `gorm:"type:text" json:"personality"`
-	Strengths      []string       `gorm:"type:jsonb;serializer:json" json:"strengths"`
-	Challenges     []string       `gorm:"type:jsonb;serializer:json" json:"challenges"`
-	DailyAdvice    string         `gorm:"type:text" json:"daily_advice"`
-	AnalyzedAt     time.Time      `gorm:"not null" json:"analyzed_at"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID       uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primary_key;index:idx_aura_user_created,priority:3" json:"id"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index;index:idx_aura_user_created,priority:1;index:idx_aura_user_color,priority:1;index:idx_aura_user_energy,priority:1;index:idx_aura_user_mood,priority:1" json:"user_id"`
+	ImageURL string    `gorm:"type:text;not null" json:"image_url"`
+	// ImageHash is the SHA-256 of an inline upload, empty for image URLs. It
+	// keys the deterministic engine so a photo always gets the same fallback.
+	ImageHash      string            `gorm:"type:varchar(64)" json:"-"`
+	AuraColor      string            `gorm:"type:varchar(50);not null;index:idx_aura_user_color,priority:2" json:"aura_color"`
+	SecondaryColor *string           `gorm:"type:varchar(50);default:NULL" json:"secondary_color,omitempty"`
+	EnergyLevel    int               `gorm:"type:integer;check:energy_level >= 1 AND energy_level <= 100;index:idx_aura_user_energy,priority:2" json:"energy_level"`
+	MoodScore      int               `gorm:"type:integer;check:mood_score >= 1 AND mood_score <= 10;index:idx_aura_user_mood,priority:2" json:"mood_score"`
+	Personality    string            `gorm:"type:text" json:"personality"`
+	Strengths      []string          `gorm:"type:jsonb;serializer:json" json:"strengths"`
+	Challenges     []string          `gorm:"type:jsonb;serializer:json" json:"challenges"`
+	DailyAdvice    string            `gorm:"type:text" json:"daily_advice"`
+	Provenance     map[string]string `gorm:"type:jsonb;serializer:json" json:"provenance,omitempty"`
+	Palette        []string          `gorm:"type:jsonb;serializer:json" json:"palette,omitempty"`
+	// Source is who wrote the reading; empty for readings stored before it
+	// was recorded. ProviderLatencyMs is the time spent waiting on AI
+	// providers, failed attempts included, when any were called.
+	Source            string `gorm:"type:varchar(20);not null;default:''" json:"source,omitempty"`
+	ProviderLatencyMs *int   `json:"provider_latency_ms,omitempty"`
+	PromptVariant     string `gorm:"type:varchar(20);index" json:"-"`
+	// PromptTemplateID is the prompt template version that produced the
+	// reading; nil for the built-in prompt and readings without AI output.
+	PromptTemplateID *uuid.UUID `gorm:"type:uuid;index" json:"-"`
+	// ExperimentID and ExperimentVariant tag readings made while the user
+	// was in a running experiment.
+	ExperimentID      *uuid.UUID       `gorm:"type:uuid;index" json:"-"`
+	ExperimentVariant string           `gorm:"type:varchar(50)" json:"-"`
+	Language          string           `gorm:"type:varchar(10);not null;default:'en'" json:"language"` // locale the text was written in
+	Status            string           `gorm:"type:varchar(10);not null;default:'ready'" json:"status"`
+	GroupReadingID    *uuid.UUID       `gorm:"type:uuid;index" json:"group_reading_id,omitempty"`
+	GroupPosition     *int             `gorm:"type:smallint" json:"group_position,omitempty"`  // 0-based, left to right
+	ScanBatchID       *uuid.UUID       `gorm:"type:uuid;index" json:"scan_batch_id,omitempty"` // set for photos imported in a batch
+	ShareCount        int              `gorm:"not null;default:0" json:"-"`
+	Rating            *int             `gorm:"type:smallint;check:rating IS NULL OR (rating >= 1 AND rating <= 5)" json:"rating,omitempty"`
+	RatingTags        []string         `gorm:"type:jsonb;serializer:json" json:"rating_tags,omitempty"`
+	RatedAt           *time.Time       `json:"rated_at,omitempty"`
+	Note              string           `gorm:"type:text;serializer:encrypted" json:"note,omitempty"` // private journal note
+	MoodTags          []string         `gorm:"type:jsonb;serializer:json" json:"mood_tags,omitempty"`
+	NotedAt           *time.Time       `json:"noted_at,omitempty"`
+	Pinned            bool             `gorm:"not null;default:false" json:"pinned"`
+	PinnedAt          *time.Time       `json:"pinned_at,omitempty"`
+	Synthetic         bool             `gorm:"not null;default:false" json:"-"`                // monitoring probe scan, kept out of stats
+	Versions          []ReadingVersion `gorm:"foreignKey:ReadingID" json:"versions,omitempty"` // prior results, when loaded
+	AnalyzedAt        time.Time        `gorm:"not null" json:"analyzed_at"`
+	CreatedAt         time.Time        `gorm:"index:idx_aura_user_created,priority:2;index:idx_aura_user_color,priority:3" json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt   `gorm:"index" json:"deleted_at,omitempty"`
 }
 
 func (AuraReading) TableName() string {
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthToken is a single-use, expiring token mailed to a user (password
+// reset, email confirmation). Only the SHA-256 hash of the token is stored.
+type AuthToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Purpose   string     `gorm:"not null;size:30;index" json:"purpose"`
+	TokenHash string     `gorm:"uniqueIndex;not null;size:64" json:"-"`
+	Payload   string     `gorm:"size:255" json:"-"` // purpose-specific, e.g. the new email address
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (AuthToken) TableName() string {
+	return "auth_tokens"
+}
+
+const (
+	TokenPurposePasswordReset = "password_reset"
+	TokenPurposeEmailChange   = "email_change"
+	TokenPurposeEmailVerify   = "email_verify"
+)
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContactHash is the salted hash of one of a user's own identifiers,
+// stored only while they opt into contact discovery. Raw phone numbers are
+// never persisted.
+type ContactHash struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_contact_hash_user_kind" json:"user_id"`
+	Kind      string    `gorm:"not null;size:10;uniqueIndex:idx_contact_hash_user_kind" json:"kind"` // email, phone
+	Hash      string    `gorm:"not null;size:64;index" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ContactHash) TableName() string {
+	return "contact_hashes"
+}
+
+const (
+	ContactKindEmail = "email"
+	ContactKindPhone = "phone"
+)
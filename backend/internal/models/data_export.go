@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataExport is an asynchronous GDPR/CCPA export of a user's data. The
+// generated file lives under EXPORT_DIR until ExpiresAt.
+type DataExport struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Status      string     `gorm:"not null;size:20;default:'pending';index" json:"status"` // pending, processing, ready, failed
+	Format      string     `gorm:"not null;size:10" json:"format"`                         // json, zip
+	FilePath    string     `gorm:"size:500" json:"-"`
+	SizeBytes   int64      `json:"size_bytes"`
+	Error       string     `gorm:"size:500" json:"error,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+const (
+	ExportPending    = "pending"
+	ExportProcessing = "processing"
+	ExportReady      = "ready"
+	ExportFailed     = "failed"
+)
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Data migration phases, in order. A migration double-writes new rows in
+// the new shape, backfills old rows, verifies both shapes agree, then cuts
+// reads over; complete means the old shape is no longer read.
+const (
+	MigrationPending     = "pending"
+	MigrationDoubleWrite = "double_write"
+	MigrationBackfill    = "backfill"
+	MigrationVerify      = "verify"
+	MigrationCutover     = "cutover"
+	MigrationComplete    = "complete"
+)
+
+// MigrationPhases lists the phases in the order a migration moves through.
+var MigrationPhases = []string{
+	MigrationPending, MigrationDoubleWrite, MigrationBackfill,
+	MigrationVerify, MigrationCutover, MigrationComplete,
+}
+
+// DataMigration tracks one online data reshaping (see services.dataMigration).
+// Cursors are opaque to the tracker; each migration defines its own.
+type DataMigration struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name           string     `gorm:"size:100;not null;uniqueIndex" json:"name"`
+	Phase          string     `gorm:"size:20;not null;default:'pending'" json:"phase"`
+	Total          int64      `gorm:"not null;default:0" json:"total"`
+	Backfilled     int64      `gorm:"not null;default:0" json:"backfilled"`
+	BackfillCursor string     `gorm:"type:text" json:"-"`
+	BackfilledAt   *time.Time `json:"backfilled_at,omitempty"`
+	Verified       int64      `gorm:"not null;default:0" json:"verified"`
+	Mismatches     int64      `gorm:"not null;default:0" json:"mismatches"`
+	VerifyCursor   string     `gorm:"type:text" json:"-"`
+	VerifiedAt     *time.Time `json:"verified_at,omitempty"`
+	LastError      string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (DataMigration) TableName() string {
+	return "data_migrations"
+}
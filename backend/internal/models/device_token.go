@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PushProviderAPNs = "apns"
+	PushProviderFCM  = "fcm"
+)
+
+// DeviceToken is a push token registered by one app install. A token moves
+// to whichever user registered it last.
+type DeviceToken struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Token      string    `gorm:"type:varchar(512);not null;uniqueIndex" json:"-"`
+	Platform   string    `gorm:"type:varchar(10);not null" json:"platform"`
+	Provider   string    `gorm:"type:varchar(10);not null" json:"provider"`
+	AppVersion string    `gorm:"type:varchar(32)" json:"app_version,omitempty"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (DeviceToken) TableName() string {
+	return "device_tokens"
+}
+
+// ReminderLog makes scheduled reminders idempotent: one row per user, kind
+// and local day.
+type ReminderLog struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Kind      string    `gorm:"type:varchar(30);primaryKey" json:"kind"`
+	Day       string    `gorm:"type:varchar(10);primaryKey" json:"day"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ReminderLog) TableName() string {
+	return "reminder_logs"
+}
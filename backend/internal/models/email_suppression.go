@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailSuppression blocks delivery to an address after a hard bounce or a
+// spam complaint reported by the email provider. Keyed by lowercased address
+// rather than user so it survives account deletion and email changes.
+type EmailSuppression struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Email     string    `gorm:"uniqueIndex;not null;size:255" json:"email"`
+	Reason    string    `gorm:"not null;size:20" json:"reason"` // hard_bounce, complaint
+	Source    string    `gorm:"size:20" json:"source"`          // ses
+	Detail    string    `gorm:"size:500" json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (EmailSuppression) TableName() string {
+	return "email_suppressions"
+}
+
+const (
+	SuppressionHardBounce = "hard_bounce"
+	SuppressionComplaint  = "complaint"
+)
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Experiment statuses. An experiment is edited as a draft, runs once, and
+// is stopped for good; at most one runs at a time so readings are never
+// split by two experiments at once.
+const (
+	ExperimentDraft   = "draft"
+	ExperimentRunning = "running"
+	ExperimentStopped = "stopped"
+)
+
+// ExperimentVariant is one arm of an experiment. Zero fields keep what the
+// scan would have used outside the experiment.
+type ExperimentVariant struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+	// PromptVariant is "baseline" or "history" (previous readings in the
+	// prompt); PromptTemplateID pins an aura_scan prompt template version.
+	PromptVariant    string     `json:"prompt_variant,omitempty"`
+	PromptTemplateID *uuid.UUID `json:"prompt_template_id,omitempty"`
+	Temperature      *float64   `json:"temperature,omitempty"`
+	// Provider is tried first; Model replaces its configured model.
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+}
+
+// Experiment splits scanning users between variants by weight. Assignment
+// is a hash of the key and user ID, so a user stays in one variant for the
+// whole experiment; readings record the variant that produced them.
+type Experiment struct {
+	ID          uuid.UUID           `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Key         string              `gorm:"not null;size:50;uniqueIndex" json:"key"`
+	Description string              `gorm:"type:text" json:"description,omitempty"`
+	Status      string              `gorm:"not null;size:20;default:'draft';index" json:"status"`
+	Variants    []ExperimentVariant `gorm:"type:jsonb;serializer:json;not null" json:"variants"`
+	CreatedBy   *uuid.UUID          `gorm:"type:uuid" json:"created_by,omitempty"`
+	StartedAt   *time.Time          `json:"started_at,omitempty"`
+	EndedAt     *time.Time          `json:"ended_at,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+func (Experiment) TableName() string {
+	return "experiments"
+}
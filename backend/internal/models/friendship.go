@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Friendship links two users. One row per pair; RequesterID is whoever
+// initiated it (e.g. the invite code owner).
+type Friendship struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	RequesterID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_friendship_pair" json:"requester_id"`
+	AddresseeID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_friendship_pair;index" json:"addressee_id"`
+	Status      string     `gorm:"not null;size:20;default:'accepted'" json:"status"` // pending, accepted, declined
+	Source      string     `gorm:"size:20" json:"source,omitempty"`                   // invite_code, request, email
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (Friendship) TableName() string {
+	return "friendships"
+}
+
+const (
+	FriendshipPending  = "pending"
+	FriendshipAccepted = "accepted"
+	FriendshipDeclined = "declined"
+)
+
+// FriendInviteCode is a short-lived code (shown as a deep link or QR) that
+// makes whoever redeems it a friend of the owner.
+type FriendInviteCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Code      string     `gorm:"uniqueIndex;not null;size:16" json:"code"`
+	MaxUses   int        `gorm:"not null;default:1" json:"max_uses"`
+	UseCount  int        `gorm:"not null;default:0" json:"use_count"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	// InvitedEmailHash is set for codes emailed to someone without an
+	// account (SHA-256 of the normalized address), for dedupe and rate limits.
+	InvitedEmailHash string    `gorm:"size:64;index" json:"-"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func (FriendInviteCode) TableName() string {
+	return "friend_invite_codes"
+}
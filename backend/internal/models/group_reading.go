@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GroupReading is one scan of a multi-person photo. Each detected person gets
+// their own AuraReading (owned by the scanning user, linked back through
+// GroupReadingID) and the group gets a shared energy summary.
+type GroupReading struct {
+	ID            uuid.UUID         `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID        uuid.UUID         `gorm:"type:uuid;not null;index" json:"user_id"`
+	ImageURL      string            `gorm:"type:text;not null" json:"image_url"`
+	PeopleCount   int               `gorm:"not null" json:"people_count"`
+	DominantColor string            `gorm:"type:varchar(50);not null" json:"dominant_color"`
+	Harmony       int               `gorm:"not null" json:"harmony"` // 0-100, mean pairwise compatibility
+	GroupEnergy   string            `gorm:"type:text" json:"group_energy"`
+	Provenance    map[string]string `gorm:"type:jsonb;serializer:json" json:"provenance,omitempty"`
+	People        []AuraReading     `gorm:"foreignKey:GroupReadingID" json:"people"`
+	CreatedAt     time.Time         `json:"created_at"`
+	DeletedAt     gorm.DeletedAt    `gorm:"index" json:"-"`
+}
+
+func (GroupReading) TableName() string {
+	return "group_readings"
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImageViolation records a scan photo rejected by image moderation. The
+// photo itself is not kept; ImageRef is the URL it was sent as, or
+// "base64_upload" for inline uploads. CaseID is set once the user's
+// violations put them in the moderation queue.
+type ImageViolation struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_image_violation_user_time" json:"user_id"`
+	Provider   string     `gorm:"not null;size:20" json:"provider"`
+	Categories []string   `gorm:"type:jsonb;serializer:json" json:"categories"`
+	ImageRef   string     `gorm:"type:text" json:"image_ref"`
+	CaseID     *uuid.UUID `gorm:"type:uuid" json:"case_id,omitempty"`
+	CreatedAt  time.Time  `gorm:"index:idx_image_violation_user_time" json:"created_at"`
+}
+
+func (ImageViolation) TableName() string {
+	return "image_violations"
+}
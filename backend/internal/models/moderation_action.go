@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Moderation action types. A ban locks the user out of everything but their
+// account and appeals; a suspension is a ban that ends at ExpiresAt; a
+// shadow ban hides them from other users.
+const (
+	ActionBan       = "ban"
+	ActionSuspend   = "suspend"
+	ActionShadowBan = "shadow_ban"
+)
+
+// ModerationAction is a sanction against a user. It is in force until
+// RevokedAt is set, by an admin or by an approved appeal, or until
+// ExpiresAt passes.
+type ModerationAction struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Type         string     `gorm:"not null;size:20" json:"type"`
+	Reason       string     `gorm:"size:1000;not null" json:"reason"`
+	CaseID       *uuid.UUID `gorm:"type:uuid" json:"case_id,omitempty"`
+	CreatedBy    uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy    *uuid.UUID `gorm:"type:uuid" json:"revoked_by,omitempty"`
+	RevokeReason string     `gorm:"size:1000" json:"revoke_reason,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (ModerationAction) TableName() string {
+	return "moderation_actions"
+}
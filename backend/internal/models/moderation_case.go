@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Moderation case statuses. Open and escalated cases still collect reports.
+const (
+	CaseOpen      = "open"
+	CaseEscalated = "escalated"
+	CaseActioned  = "actioned"
+	CaseDismissed = "dismissed"
+)
+
+// ModerationCase groups every report about one piece of content, so many
+// users reporting the same thing produce one item in the review queue.
+// At most one case per content is open (or escalated) at a time.
+type ModerationCase struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ContentType    string     `gorm:"not null;size:50;uniqueIndex:idx_case_open_content,where:status IN ('open','escalated')" json:"content_type"`
+	ContentID      string     `gorm:"not null;size:255;uniqueIndex:idx_case_open_content,where:status IN ('open','escalated')" json:"content_id"`
+	Status         string     `gorm:"not null;size:20;default:'open';index" json:"status"`
+	ReportCount    int        `gorm:"not null;default:0" json:"report_count"`
+	AdminNote      string     `gorm:"size:1000" json:"admin_note,omitempty"`
+	LastReportedAt time.Time  `json:"last_reported_at"`
+	EscalatedAt    *time.Time `json:"escalated_at,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Reports        []Report   `gorm:"foreignKey:CaseID" json:"reports,omitempty"`
+}
+
+func (ModerationCase) TableName() string {
+	return "moderation_cases"
+}
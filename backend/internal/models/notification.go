@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreference stores the user's channel × category opt-ins.
+// Missing entries fall back to the defaults in the notification service.
+type NotificationPreference struct {
+	UserID            uuid.UUID                  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Matrix            map[string]map[string]bool `gorm:"type:jsonb;serializer:json" json:"matrix"`
+	QuietHoursEnabled bool                       `gorm:"not null;default:true" json:"quiet_hours_enabled"`
+	QuietHoursStart   string                     `gorm:"type:varchar(5);not null;default:'22:00'" json:"quiet_hours_start"`
+	QuietHoursEnd     string                     `gorm:"type:varchar(5);not null;default:'08:00'" json:"quiet_hours_end"`
+	SocialDelivery    string                     `gorm:"type:varchar(10);not null;default:'digest'" json:"social_delivery"`
+	CreatedAt         time.Time                  `json:"created_at"`
+	UpdatedAt         time.Time                  `json:"updated_at"`
+}
+
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// NotificationDispatch records each notification that reached an interrupting
+// channel (push or email); used for the daily cap.
+type NotificationDispatch struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index:idx_notification_dispatch_user_time" json:"user_id"`
+	Category  string    `gorm:"type:varchar(30);not null" json:"category"`
+	Priority  string    `gorm:"type:varchar(10);not null" json:"priority"`
+	Channels  []string  `gorm:"type:jsonb;serializer:json" json:"channels"`
+	CreatedAt time.Time `gorm:"index:idx_notification_dispatch_user_time" json:"created_at"`
+}
+
+func (NotificationDispatch) TableName() string {
+	return "notification_dispatches"
+}
+
+// PendingNotification is a social event waiting to be batched into a digest.
+// All pending rows for a user share the DueAt of the first one in the window.
+type PendingNotification struct {
+	ID        uuid.UUID         `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID         `gorm:"type:uuid;not null;index" json:"user_id"`
+	Category  string            `gorm:"type:varchar(30);not null" json:"category"`
+	Title     string            `gorm:"type:varchar(200);not null" json:"title"`
+	Body      string            `gorm:"type:text" json:"body"`
+	Data      map[string]string `gorm:"type:jsonb;serializer:json" json:"data,omitempty"`
+	DueAt     time.Time         `gorm:"not null;index" json:"due_at"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+func (PendingNotification) TableName() string {
+	return "pending_notifications"
+}
+
+// Notification is an in-app inbox entry.
+type Notification struct {
+	ID        uuid.UUID         `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID         `gorm:"type:uuid;not null;index" json:"user_id"`
+	Category  string            `gorm:"type:varchar(30);not null" json:"category"`
+	Title     string            `gorm:"type:varchar(200);not null" json:"title"`
+	Body      string            `gorm:"type:text" json:"body"`
+	Data      map[string]string `gorm:"type:jsonb;serializer:json" json:"data,omitempty"`
+	ReadAt    *time.Time        `json:"read_at,omitempty"`
+	CreatedAt time.Time         `gorm:"index" json:"created_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
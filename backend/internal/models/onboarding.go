@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OnboardingCompleted = "completed"
+	OnboardingSkipped   = "skipped"
+)
+
+// OnboardingProgress records a user finishing or skipping one onboarding step.
+// StepVersion lets a reworked step be shown again to users who saw an older one.
+type OnboardingProgress struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_onboarding_user_step" json:"user_id"`
+	StepID      string    `gorm:"type:varchar(40);not null;uniqueIndex:idx_onboarding_user_step" json:"step_id"`
+	StepVersion int       `gorm:"not null;default:1" json:"step_version"`
+	Status      string    `gorm:"type:varchar(10);not null" json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (OnboardingProgress) TableName() string {
+	return "onboarding_progress"
+}
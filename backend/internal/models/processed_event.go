@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProcessedEvent records that an event from a webhook provider or internal
+// consumer has been applied, keyed by source and the event's own ID, so a
+// redelivery is skipped instead of applied twice. An event that keeps failing
+// is quarantined until an admin releases it; the payload is kept for them.
+type ProcessedEvent struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Source        string     `gorm:"not null;size:30;uniqueIndex:idx_processed_event_source_id" json:"source"`
+	EventID       string     `gorm:"not null;size:255;uniqueIndex:idx_processed_event_source_id" json:"event_id"`
+	Status        string     `gorm:"not null;size:20;default:'pending';index" json:"status"` // pending, processed, quarantined
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"size:1000" json:"last_error,omitempty"`
+	Payload       string     `gorm:"type:text" json:"payload,omitempty"`
+	ProcessedAt   *time.Time `json:"processed_at,omitempty"`
+	QuarantinedAt *time.Time `json:"quarantined_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
+
+const (
+	EventPending     = "pending"
+	EventProcessed   = "processed"
+	EventQuarantined = "quarantined"
+)
+
+// EventSourceSES is the source of SES feedback delivered through SNS;
+// webhook providers use their WebhookProvider names.
+const EventSourceSES = "ses"
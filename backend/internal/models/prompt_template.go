@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Prompt template names.
+const (
+	PromptAuraScan = "aura_scan"
+)
+
+// PromptTemplate is one version of an AI prompt. Versions are never edited:
+// changing a prompt saves the next version, and activating a version makes
+// it live for its name and provider. Provider "" applies to every provider
+// without an active override of its own; with no active version at all the
+// built-in prompt is used.
+type PromptTemplate struct {
+	ID       uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name     string    `gorm:"not null;size:50;uniqueIndex:idx_prompt_template_version,priority:1" json:"name"`
+	Provider string    `gorm:"not null;size:20;default:'';uniqueIndex:idx_prompt_template_version,priority:2" json:"provider,omitempty"`
+	Version  int       `gorm:"not null;uniqueIndex:idx_prompt_template_version,priority:3" json:"version"`
+	// System and User are text/template sources; see the built-in prompt
+	// for the fields available.
+	System      string     `gorm:"type:text;not null" json:"system"`
+	User        string     `gorm:"type:text;not null" json:"user"`
+	Notes       string     `gorm:"type:text" json:"notes,omitempty"`
+	Active      bool       `gorm:"not null;default:false;index" json:"active"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+	CreatedBy   *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (PromptTemplate) TableName() string {
+	return "prompt_templates"
+}
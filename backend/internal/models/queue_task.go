@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueueTask is a unit of background work on the Postgres task queue. A
+// worker claims a task by setting LockedUntil; a task whose worker died is
+// claimed again once that passes. Failed tasks are retried at RunAt until
+// they run out of attempts, and are then kept with FailedAt set.
+type QueueTask struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Kind        string     `gorm:"size:64;not null" json:"kind"`
+	Payload     []byte     `gorm:"not null" json:"payload"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	RunAt       time.Time  `gorm:"not null;index" json:"run_at"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	LastError   string     `gorm:"type:text" json:"last_error,omitempty"`
+	FailedAt    *time.Time `gorm:"index" json:"failed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (QueueTask) TableName() string {
+	return "queue_tasks"
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadingCorrection records one admin edit of a reading. Original holds the
+// values the edited fields had before, Corrected the values written, both
+// keyed by reading field name (aura_color, energy_level, ...).
+type ReadingCorrection struct {
+	ID        uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ReadingID uuid.UUID      `gorm:"type:uuid;not null;index" json:"reading_id"`
+	UserID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"` // the reading's owner
+	AdminID   uuid.UUID      `gorm:"type:uuid;not null" json:"admin_id"`
+	Reason    string         `gorm:"type:text;not null" json:"reason"`
+	Original  map[string]any `gorm:"type:jsonb;serializer:json" json:"original"`
+	Corrected map[string]any `gorm:"type:jsonb;serializer:json" json:"corrected"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+func (ReadingCorrection) TableName() string {
+	return "reading_corrections"
+}
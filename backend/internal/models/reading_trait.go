@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reading trait kinds.
+const (
+	TraitStrength  = "strength"
+	TraitChallenge = "challenge"
+)
+
+// Trait is one entry of the strength and challenge vocabulary. Readings in
+// any language that name the same trait share a row: Slug is derived from
+// the English name, which the i18n tables translate for display. Free-form
+// AI traits the tables do not know get a row of their own.
+type Trait struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Kind      string    `gorm:"size:20;not null;uniqueIndex:idx_trait_kind_slug,priority:1" json:"kind"`
+	Slug      string    `gorm:"size:120;not null;uniqueIndex:idx_trait_kind_slug,priority:2" json:"slug"`
+	Name      string    `gorm:"size:200;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Trait) TableName() string {
+	return "traits"
+}
+
+// ReadingTrait is one strength or challenge of a reading, the normalized
+// form of AuraReading.Strengths and Challenges (migration "reading_traits").
+// Text is the reading's own wording; TraitID links it to the vocabulary.
+type ReadingTrait struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ReadingID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_reading_trait_position,priority:1" json:"reading_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"-"`
+	Kind      string     `gorm:"size:20;not null;uniqueIndex:idx_reading_trait_position,priority:2" json:"kind"`
+	Position  int        `gorm:"not null;uniqueIndex:idx_reading_trait_position,priority:3" json:"position"`
+	Text      string     `gorm:"type:text;not null" json:"text"`
+	TraitID   *uuid.UUID `gorm:"type:uuid;index" json:"trait_id"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (ReadingTrait) TableName() string {
+	return "reading_traits"
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReadingVersion keeps a result a reading had before it was regenerated.
+// Version counts from 1 for the original result.
+type ReadingVersion struct {
+	ID             uuid.UUID         `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ReadingID      uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex:idx_reading_version" json:"reading_id"`
+	UserID         uuid.UUID         `gorm:"type:uuid;not null;index:idx_reading_version_user_time" json:"-"`
+	Version        int               `gorm:"not null;uniqueIndex:idx_reading_version" json:"version"`
+	AuraColor      string            `gorm:"type:varchar(50);not null" json:"aura_color"`
+	SecondaryColor *string           `gorm:"type:varchar(50)" json:"secondary_color,omitempty"`
+	EnergyLevel    int               `json:"energy_level"`
+	MoodScore      int               `json:"mood_score"`
+	Personality    string            `gorm:"type:text" json:"personality"`
+	Strengths      []string          `gorm:"type:jsonb;serializer:json" json:"strengths"`
+	Challenges     []string          `gorm:"type:jsonb;serializer:json" json:"challenges"`
+	DailyAdvice    string            `gorm:"type:text" json:"daily_advice"`
+	Provenance     map[string]string `gorm:"type:jsonb;serializer:json" json:"provenance,omitempty"`
+	Language       string            `gorm:"type:varchar(10)" json:"language"`
+	AnalyzedAt     time.Time         `json:"analyzed_at"`
+	CreatedAt      time.Time         `gorm:"index:idx_reading_version_user_time" json:"created_at"` // when it was replaced
+}
+
+func (ReadingVersion) TableName() string {
+	return "reading_versions"
+}
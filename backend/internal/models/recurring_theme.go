@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ThemeMethodEmbeddings = "embeddings"
+	ThemeMethodKeywords   = "keywords"
+)
+
+// RecurringTheme is a topic that keeps coming back in the personality and
+// advice texts of a user's readings. The set is rebuilt whenever the user's
+// readings change.
+type RecurringTheme struct {
+	ID     uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	// Label names the theme from its most frequent keywords.
+	Label    string   `gorm:"type:varchar(100);not null" json:"label"`
+	Keywords []string `gorm:"type:jsonb;serializer:json" json:"keywords"`
+	// Readings is how many analyzed readings mention the theme; Share is
+	// that count over all analyzed readings.
+	Readings  int       `gorm:"not null" json:"readings"`
+	Share     float64   `gorm:"not null" json:"share"`
+	FirstSeen time.Time `gorm:"not null" json:"first_seen"`
+	LastSeen  time.Time `gorm:"not null" json:"last_seen"`
+	Rank      int       `gorm:"not null" json:"-"`
+	CreatedAt time.Time `json:"-"`
+}
+
+func (RecurringTheme) TableName() string {
+	return "recurring_themes"
+}
+
+// ThemeAnalysis records what a user's recurring themes were last computed
+// from, so the worker only reanalyzes users with new or deleted readings.
+type ThemeAnalysis struct {
+	UserID uuid.UUID `gorm:"type:uuid;primary_key" json:"user_id"`
+	// Readings and LatestReadingAt fingerprint the analyzed readings.
+	Readings        int64      `gorm:"not null" json:"readings"`
+	LatestReadingAt *time.Time `json:"latest_reading_at,omitempty"`
+	Method          string     `gorm:"type:varchar(20);not null" json:"method"`
+	AnalyzedAt      time.Time  `gorm:"not null" json:"analyzed_at"`
+}
+
+func (ThemeAnalysis) TableName() string {
+	return "theme_analyses"
+}
@@ -6,12 +6,38 @@ import (
 	"github.com/google/uuid"
 )
 
+// RefreshToken is one link in a rotation chain. Every refresh revokes the
+// presented token and issues a successor in the same family; a family is
+// one signed-in device session.
 type RefreshToken struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	FamilyID  uuid.UUID `gorm:"type:uuid;index" json:"family_id"`
 	TokenHash string    `gorm:"uniqueIndex;not null;size:64" json:"-"`
 	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
 	Revoked   bool      `gorm:"default:false" json:"revoked"`
+	// RevokedReason: rotated, logout, reuse_detected, password_reset, revoked, admin
+	RevokedReason string     `gorm:"size:30" json:"revoked_reason,omitempty"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+
+	// Device metadata, refreshed on every rotation.
+	DeviceName string     `gorm:"size:100" json:"device_name,omitempty"`
+	Platform   string     `gorm:"size:20" json:"platform,omitempty"`
+	AppVersion string     `gorm:"size:30" json:"app_version,omitempty"`
+	UserAgent  string     `gorm:"size:255" json:"user_agent,omitempty"`
+	IPAddress  string     `gorm:"size:45" json:"ip_address,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	User      User      `gorm:"foreignKey:UserID" json:"-"`
 }
+
+const (
+	RevokeReasonRotated       = "rotated"
+	RevokeReasonLogout        = "logout"
+	RevokeReasonReuseDetected = "reuse_detected"
+	RevokeReasonPasswordReset = "password_reset"
+	RevokeReasonRevoked       = "revoked"
+	RevokeReasonAdmin         = "admin"
+	RevokeReasonAccountDelete = "account_deleted"
+)
@@ -15,7 +15,9 @@ type Report struct {
 	Reason      string    `gorm:"not null;size:500" json:"reason"`
 	Status      string    `gorm:"not null;default:'pending';size:50" json:"status"` // pending, reviewed, actioned, dismissed
 	AdminNote   string    `gorm:"size:1000" json:"admin_note,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Reporter    User      `gorm:"foreignKey:ReporterID" json:"-"`
+	// CaseID groups the report with others about the same content.
+	CaseID    *uuid.UUID `gorm:"type:uuid;index" json:"case_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Reporter  User       `gorm:"foreignKey:ReporterID" json:"-"`
 }
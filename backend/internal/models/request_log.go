@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestLog is a failed API request kept for support: the request ID a
+// client shows next to an error finds it. RequestID is the request's trace
+// ID, so Traced requests can also be followed to their spans. Rows are
+// purged after REQUEST_LOG_RETENTION.
+type RequestLog struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	RequestID string     `gorm:"size:32;not null;index" json:"request_id"`
+	Traced    bool       `gorm:"not null;default:false" json:"traced"`
+	UserID    *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"`
+	Method    string     `gorm:"size:10;not null" json:"method"`
+	// Route is the matched route pattern ("/api/aura/:id"); TargetID its
+	// :id parameter. Raw paths are not kept, since some carry tokens.
+	Route    string `gorm:"size:255;not null" json:"route"`
+	TargetID string `gorm:"size:255" json:"target_id,omitempty"`
+	Status   int    `gorm:"not null" json:"status"`
+	Code     string `gorm:"size:64" json:"code,omitempty"`
+	// Error is the full error, internal cause included; it is never sent to
+	// the client that made the request.
+	Error      string    `gorm:"type:text" json:"error,omitempty"`
+	LatencyMs  int64     `gorm:"not null;default:0" json:"latency_ms"`
+	Platform   string    `gorm:"size:20" json:"platform,omitempty"`
+	AppVersion string    `gorm:"size:32" json:"app_version,omitempty"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+func (RequestLog) TableName() string {
+	return "request_logs"
+}
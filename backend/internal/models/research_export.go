@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Research export statuses.
+const (
+	ResearchExportCompleted = "completed"
+	ResearchExportFailed    = "failed"
+)
+
+// ResearchExport records one run of the anonymized research dataset export:
+// one object per UTC day and schema version.
+type ResearchExport struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	SchemaVersion int        `gorm:"not null;uniqueIndex:idx_research_export_day" json:"schema_version"`
+	Day           string     `gorm:"size:10;not null;uniqueIndex:idx_research_export_day" json:"day"` // YYYY-MM-DD, UTC
+	Status        string     `gorm:"size:20;not null" json:"status"`
+	ObjectKey     string     `gorm:"type:text" json:"object_key,omitempty"`
+	Rows          int        `gorm:"not null;default:0" json:"rows"`
+	Participants  int        `gorm:"not null;default:0" json:"participants"`
+	Error         string     `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (ResearchExport) TableName() string {
+	return "research_exports"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ScanBatch is one import of several photos, e.g. from the camera roll. Each
+// accepted photo becomes an ordinary async AuraReading linked back through
+// ScanBatchID; photos refused before analysis are kept in Rejected.
+type ScanBatch struct {
+	ID         uuid.UUID            `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID     uuid.UUID            `gorm:"type:uuid;not null;index" json:"user_id"`
+	ImageCount int                  `gorm:"not null" json:"image_count"`
+	Rejected   []ScanBatchRejection `gorm:"type:jsonb;serializer:json" json:"rejected"`
+	Readings   []AuraReading        `gorm:"foreignKey:ScanBatchID" json:"readings"`
+	// Status, Pending and Ready summarize the readings when the batch is
+	// loaded; they are not stored.
+	Status    string         `gorm:"-" json:"status"`
+	Pending   int            `gorm:"-" json:"pending"`
+	Ready     int            `gorm:"-" json:"ready"`
+	CreatedAt time.Time      `json:"created_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ScanBatchRejection is a photo of a batch that was not scanned. Index is
+// its 0-based position in the request and Code the error code a single scan
+// of it would have failed with.
+type ScanBatchRejection struct {
+	Index   int    `json:"index"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (ScanBatch) TableName() string {
+	return "scan_batches"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StoredPhoto is the uploaded photo kept for a reading, plus its thumbnail.
+// When a free account goes over its photo quota the oldest originals are
+// evicted (file removed, EvictedAt set); thumbnails and readings stay.
+//
+// Old originals move to cold storage: the file is uploaded under ColdKey and
+// removed locally (ArchivedAt set). A restore brings it back to disk for a
+// while; ColdKey stays, so going cold again needs no new upload.
+type StoredPhoto struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	ReadingID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"reading_id"`
+	ContentType    string     `gorm:"size:20;not null" json:"content_type"`
+	OriginalPath   string     `gorm:"type:text" json:"-"`
+	OriginalBytes  int64      `gorm:"not null;default:0" json:"original_bytes"`
+	ThumbnailPath  string     `gorm:"type:text" json:"-"`
+	ThumbnailBytes int64      `gorm:"not null;default:0" json:"thumbnail_bytes"`
+	EvictedAt      *time.Time `gorm:"index" json:"evicted_at,omitempty"`
+	ColdKey        string     `gorm:"type:text" json:"-"`
+	ArchivedAt     *time.Time `gorm:"index" json:"archived_at,omitempty"`
+	// RestoreRequestedAt is set while a restore from cold storage is pending.
+	RestoreRequestedAt *time.Time `gorm:"index" json:"restore_requested_at,omitempty"`
+	RestoredAt         *time.Time `json:"restored_at,omitempty"`
+	// PurgeRequestedAt marks the record of a deleted account whose cold
+	// copy still has to be removed from the object store.
+	PurgeRequestedAt *time.Time `gorm:"index" json:"-"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+func (StoredPhoto) TableName() string {
+	return "stored_photos"
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/google/uuid"
 )
 
+// Subscription is a paid plan from either billing source: RevenueCat (app
+// stores) or Stripe (web). Source says which one owns the row.
 type Subscription struct {
 	ID                    uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	UserID                *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"`
@@ -13,6 +15,8 @@ type Subscription struct {
 	OriginalAppUserID     *string    `gorm:"index;size:255" json:"original_app_user_id,omitempty"`
 	TransactionID         *string    `gorm:"index;size:255" json:"transaction_id,omitempty"`
 	OriginalTransactionID *string    `gorm:"uniqueIndex;size:255" json:"original_transaction_id,omitempty"`
+	StripeSubscriptionID  *string    `gorm:"uniqueIndex;size:255" json:"stripe_subscription_id,omitempty"`
+	Source                string     `gorm:"not null;size:20;default:'revenuecat'" json:"source"`
 	ProductID             string     `gorm:"size:255" json:"product_id"`
 	Status                string     `gorm:"not null;default:'inactive';size:50" json:"status"`
 	CurrentPeriodStart    time.Time  `json:"current_period_start"`
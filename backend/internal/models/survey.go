@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SurveyKindNPS      = "nps"
+	SurveyKindAccuracy = "accuracy"
+	SurveyKindCustom   = "custom"
+)
+
+// Question types accepted in a survey schema
+const (
+	QuestionNPS         = "nps"          // integer 0-10
+	QuestionRating      = "rating"       // integer Min..Max, 1-5 by default
+	QuestionChoice      = "choice"       // one of Options
+	QuestionMultiChoice = "multi_choice" // subset of Options
+	QuestionText        = "text"         // free text up to MaxLength
+)
+
+// SurveyQuestion is one entry of a survey's answer schema
+type SurveyQuestion struct {
+	ID        string   `json:"id"`
+	Type      string   `json:"type"`
+	Prompt    string   `json:"prompt"`
+	Required  bool     `json:"required"`
+	Options   []string `json:"options,omitempty"`
+	Min       int      `json:"min,omitempty"`
+	Max       int      `json:"max,omitempty"`
+	MaxLength int      `json:"max_length,omitempty"`
+}
+
+// SurveySegment limits which users see a survey; zero values match everyone.
+type SurveySegment struct {
+	Plan              string `json:"plan,omitempty"` // "free" or "premium"
+	MinReadings       int    `json:"min_readings,omitempty"`
+	MinAccountAgeDays int    `json:"min_account_age_days,omitempty"`
+	RegisteredOnly    bool   `json:"registered_only,omitempty"`
+}
+
+// Survey is an admin-defined questionnaire shown in-app to a user segment.
+// Accuracy surveys are answered once per reading; other kinds once per user.
+type Survey struct {
+	ID          uuid.UUID        `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Kind        string           `gorm:"type:varchar(20);not null;index" json:"kind"`
+	Title       string           `gorm:"type:varchar(120);not null" json:"title"`
+	Description string           `gorm:"type:text" json:"description,omitempty"`
+	Questions   []SurveyQuestion `gorm:"type:jsonb;serializer:json;not null" json:"questions"`
+	Segment     SurveySegment    `gorm:"type:jsonb;serializer:json" json:"segment"`
+	Active      bool             `gorm:"not null;default:false;index" json:"active"`
+	StartsAt    *time.Time       `json:"starts_at,omitempty"`
+	EndsAt      *time.Time       `json:"ends_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+func (Survey) TableName() string {
+	return "surveys"
+}
+
+// SurveyResponse stores one user's validated answers. Score is the first
+// NPS/rating answer, denormalized for reporting; PromptVariant is copied from
+// the rated reading so accuracy feeds prompt-quality metrics.
+type SurveyResponse struct {
+	ID            uuid.UUID              `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	SurveyID      uuid.UUID              `gorm:"type:uuid;not null;index:idx_survey_response_user" json:"survey_id"`
+	UserID        uuid.UUID              `gorm:"type:uuid;not null;index:idx_survey_response_user" json:"user_id"`
+	ReadingID     *uuid.UUID             `gorm:"type:uuid;index" json:"reading_id,omitempty"`
+	Answers       map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"answers"`
+	Score         *float64               `json:"score,omitempty"`
+	PromptVariant string                 `gorm:"type:varchar(20);index" json:"prompt_variant,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+func (SurveyResponse) TableName() string {
+	return "survey_responses"
+}
@@ -8,11 +8,47 @@ import (
 )
 
 type User struct {
-	ID        uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	Email     string         `gorm:"uniqueIndex;not null;size:255" json:"email"`
-	AppleSub  *string        `gorm:"uniqueIndex;size:255" json:"-"`
-	Password  string         `gorm:"not null" json:"-"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID       uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Email    string    `gorm:"uniqueIndex;not null;size:255" json:"email"`
+	AppleSub *string   `gorm:"uniqueIndex;size:255" json:"-"`
+	// Handle is the optional, lowercase @handle used for friend search.
+	Handle          *string    `gorm:"uniqueIndex;size:20" json:"handle,omitempty"`
+	HandleChangedAt *time.Time `json:"-"`
+	// DiscoverableByHandle lets other users find this account via handle search.
+	DiscoverableByHandle bool `gorm:"not null;default:true" json:"discoverable_by_handle"`
+	// LeaderboardOptIn shows the user on public leaderboards as LeaderboardName,
+	// which is always "<name> #NNNN": a screened display name, or an
+	// anonymous one such as "Blue Fox #2941".
+	LeaderboardOptIn bool    `gorm:"not null;default:false" json:"leaderboard_opt_in"`
+	LeaderboardName  *string `gorm:"uniqueIndex;size:40" json:"leaderboard_name,omitempty"`
+	// LeaderboardNameLocked keeps an admin-set name until an admin unlocks it.
+	LeaderboardNameLocked bool `gorm:"not null;default:false" json:"-"`
+	// DiscoverableByContacts opts into matching via friends' hashed address books.
+	DiscoverableByContacts bool `gorm:"not null;default:false" json:"discoverable_by_contacts"`
+	// SimilarDiscoveryOptIn lists the user, anonymized, to adults in their
+	// region whose aura trajectories resemble theirs.
+	SimilarDiscoveryOptIn bool `gorm:"not null;default:false" json:"similar_discovery_opt_in"`
+	// BirthYear is self-declared, and only asked for to age-gate discovery.
+	BirthYear *int   `json:"birth_year,omitempty"`
+	Password  string `gorm:"not null" json:"-"`
+	Timezone  string `gorm:"size:64;not null;default:'UTC'" json:"timezone"`
+	// TimezoneChangedAt rate-limits timezone changes, which move the day
+	// boundaries of the scan quota and streaks.
+	TimezoneChangedAt *time.Time `json:"-"`
+	// EmailVerifiedAt is set once the user follows the verification link.
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// AIMemoryEnabled is the opt-in for injecting UserMemory facts into prompts.
+	AIMemoryEnabled bool `gorm:"not null;default:false" json:"ai_memory_enabled"`
+	// ResearchConsent opts the user's anonymized readings into research datasets.
+	ResearchConsent bool `gorm:"not null;default:false" json:"research_consent"`
+	// SessionsRevokedAt rejects access tokens issued before it (admin force-logout).
+	SessionsRevokedAt *time.Time `json:"-"`
+	// QuotaResetAt restarts today's scan and match counts (admin quota reset).
+	QuotaResetAt *time.Time `json:"-"`
+	// StripeCustomerID links the user to their Stripe customer (web billing).
+	StripeCustomerID *string   `gorm:"uniqueIndex;size:255" json:"-"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	// DeletedAt marks a deactivated account, restorable until the purge job erases it.
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
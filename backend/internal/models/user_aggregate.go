@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserAggregate caches per-user reading totals so profile and home screens
+// read one row instead of aggregating aura_readings. It is rewritten in the
+// transaction of every reading write and repaired by a nightly job. Only
+// personal, non-synthetic readings count.
+type UserAggregate struct {
+	UserID        uuid.UUID      `gorm:"type:uuid;primary_key" json:"user_id"`
+	TotalReadings int64          `gorm:"not null;default:0" json:"total_readings"`
+	DominantColor string         `gorm:"type:varchar(50)" json:"dominant_color"`
+	ColorCounts   map[string]int `gorm:"type:jsonb;serializer:json" json:"color_counts"`
+	AverageEnergy float64        `gorm:"not null;default:0" json:"average_energy"`
+	AverageMood   float64        `gorm:"not null;default:0" json:"average_mood"`
+	LastScanAt    *time.Time     `json:"last_scan_at,omitempty"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+func (UserAggregate) TableName() string {
+	return "user_aggregates"
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserMemory is a stable fact the user chose to share for AI personalization
+// (e.g. job, goals). Only used in prompts while the user has memory enabled.
+// Content is encrypted at rest when FIELD_ENCRYPTION_KEY is set.
+type UserMemory struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Category  string    `gorm:"type:varchar(30);not null" json:"category"`
+	Content   string    `gorm:"type:text;not null;serializer:encrypted" json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (UserMemory) TableName() string {
+	return "user_memories"
+}
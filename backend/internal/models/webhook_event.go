@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvent is an incoming webhook delivery, stored before it is applied
+// so a failure while processing can be retried instead of losing the event.
+type WebhookEvent struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Provider      string     `gorm:"not null;size:30;uniqueIndex:idx_webhook_event_provider_id,where:event_id <> ''" json:"provider"`
+	EventID       string     `gorm:"not null;size:255;uniqueIndex:idx_webhook_event_provider_id,where:event_id <> ''" json:"event_id"`
+	EventType     string     `gorm:"size:100" json:"event_type"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"`
+	Status        string     `gorm:"not null;size:20;default:'pending';index" json:"status"` // pending, processed, dead
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastError     string     `gorm:"size:1000" json:"last_error,omitempty"`
+	ProcessedAt   *time.Time `json:"processed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (WebhookEvent) TableName() string {
+	return "webhook_events"
+}
+
+const (
+	WebhookPending   = "pending"
+	WebhookProcessed = "processed"
+	WebhookDead      = "dead"
+)
+
+const (
+	WebhookProviderRevenueCat = "revenuecat"
+	WebhookProviderStripe     = "stripe"
+)
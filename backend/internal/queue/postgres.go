@@ -0,0 +1,137 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// pollInterval is how long an idle consumer waits before looking again.
+	pollInterval = time.Second
+	// visibilityTimeout hides a claimed task from other consumers. If its
+	// worker dies mid-run, the task is claimed again once this passes.
+	visibilityTimeout = 15 * time.Minute
+)
+
+// PostgresQueue keeps tasks in the queue_tasks table. Consumers claim them
+// with SKIP LOCKED, so any number of workers can share the table.
+type PostgresQueue struct {
+	db          *gorm.DB
+	concurrency int
+}
+
+func NewPostgresQueue(db *gorm.DB, concurrency int) *PostgresQueue {
+	return &PostgresQueue{db: db, concurrency: concurrency}
+}
+
+func (q *PostgresQueue) Enqueue(ctx context.Context, kind string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode %s task: %w", kind, err)
+	}
+	task := models.QueueTask{Kind: kind, Payload: body, RunAt: time.Now()}
+	if err := q.db.WithContext(ctx).Create(&task).Error; err != nil {
+		return fmt.Errorf("enqueue %s task: %w", kind, err)
+	}
+	metrics.QueueTasksTotal.WithLabelValues(kind, "enqueued").Inc()
+	return nil
+}
+
+func (q *PostgresQueue) Consume(ctx context.Context, handlers map[string]Handler) error {
+	kinds := make([]string, 0, len(handlers))
+	for kind := range handlers {
+		kinds = append(kinds, kind)
+	}
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < q.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				ran, err := q.runNext(ctx, kinds, handlers)
+				if err != nil {
+					log.Printf("queue: %v", err)
+				}
+				if ran {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+				case <-time.After(pollInterval):
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// runNext claims the oldest due task and runs it. It reports whether there
+// was one.
+func (q *PostgresQueue) runNext(ctx context.Context, kinds []string, handlers map[string]Handler) (bool, error) {
+	var task models.QueueTask
+	now := time.Now()
+	err := q.db.WithContext(ctx).Raw(`UPDATE queue_tasks SET attempts = attempts + 1, locked_until = ?
+		WHERE id = (
+			SELECT id FROM queue_tasks
+			WHERE kind IN ? AND failed_at IS NULL AND run_at <= ? AND (locked_until IS NULL OR locked_until < ?)
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(visibilityTimeout), kinds, now, now).Scan(&task).Error
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, nil
+		}
+		return false, fmt.Errorf("claim task: %w", err)
+	}
+	if task.Kind == "" {
+		return false, nil
+	}
+
+	runErr := runHandler(ctx, handlers[task.Kind], task.Payload)
+	if runErr == nil {
+		metrics.QueueTasksTotal.WithLabelValues(task.Kind, "succeeded").Inc()
+		return true, q.db.Delete(&models.QueueTask{}, "id = ?", task.ID).Error
+	}
+
+	updates := map[string]any{"last_error": runErr.Error(), "locked_until": nil}
+	if task.Attempts >= maxAttempts {
+		updates["failed_at"] = time.Now()
+		metrics.QueueTasksTotal.WithLabelValues(task.Kind, "dead").Inc()
+	} else {
+		updates["run_at"] = time.Now().Add(backoff(task.Attempts))
+		metrics.QueueTasksTotal.WithLabelValues(task.Kind, "retried").Inc()
+	}
+	if err := q.db.Model(&models.QueueTask{}).Where("id = ?", task.ID).Updates(updates).Error; err != nil {
+		return true, fmt.Errorf("record failure of %s task %s: %w", task.Kind, task.ID, err)
+	}
+	return true, fmt.Errorf("%s task %s (attempt %d): %w", task.Kind, task.ID, task.Attempts, runErr)
+}
+
+// runHandler turns a panicking handler into a failed attempt.
+func runHandler(ctx context.Context, handler Handler, payload []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if handler == nil {
+		return errors.New("no handler")
+	}
+	return handler(ctx, payload)
+}
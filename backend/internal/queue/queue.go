@@ -0,0 +1,62 @@
+// Package queue carries background tasks from the API to worker processes.
+// Tasks are kept in Postgres by default, or in Redis through asynq; either
+// way they survive restarts and are retried with backoff when they fail.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"gorm.io/gorm"
+)
+
+// maxAttempts is how many times a task runs before it is given up on.
+const maxAttempts = 5
+
+// Handler runs one task of a kind. Returning an error retries the task
+// later; handlers must therefore be safe to run more than once.
+type Handler func(ctx context.Context, payload []byte) error
+
+// Queue enqueues tasks and runs them.
+type Queue interface {
+	// Enqueue stores a task whose payload is marshalled to JSON.
+	Enqueue(ctx context.Context, kind string, payload any) error
+	// Consume runs tasks of the handled kinds until ctx is cancelled.
+	Consume(ctx context.Context, handlers map[string]Handler) error
+}
+
+// New returns the queue QUEUE_DRIVER selects.
+func New(cfg *config.Config, db *gorm.DB) (Queue, error) {
+	concurrency := cfg.QueueConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	switch cfg.QueueDriver {
+	case "", "postgres":
+		return NewPostgresQueue(db, concurrency), nil
+	case "redis":
+		if cfg.RedisURL == "" {
+			return nil, fmt.Errorf("QUEUE_DRIVER=redis needs REDIS_URL")
+		}
+		return NewRedisQueue(cfg.RedisURL, concurrency)
+	default:
+		return nil, fmt.Errorf("unknown QUEUE_DRIVER %q", cfg.QueueDriver)
+	}
+}
+
+// Decode unmarshals a task payload, for handlers.
+func Decode(payload []byte, v any) error {
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("decode task payload: %w", err)
+	}
+	return nil
+}
+
+// backoff is the wait before retrying a task that failed attempts times:
+// 30s, 2m, 4.5m, 8m, ...
+func backoff(attempts int) time.Duration {
+	return time.Duration(attempts*attempts) * 30 * time.Second
+}
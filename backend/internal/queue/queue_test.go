@@ -0,0 +1,51 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+)
+
+func TestNewChecksTheDriver(t *testing.T) {
+	if _, err := New(&config.Config{QueueDriver: "kafka"}, nil); err == nil {
+		t.Error("an unknown driver was accepted")
+	}
+	if _, err := New(&config.Config{QueueDriver: "redis"}, nil); err == nil {
+		t.Error("the redis driver was accepted without REDIS_URL")
+	}
+	q, err := New(&config.Config{QueueDriver: "redis", RedisURL: "redis://localhost:6379/1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := q.(*RedisQueue); !ok {
+		t.Errorf("redis driver built %T", q)
+	}
+	if q, _ := New(&config.Config{}, nil); q == nil {
+		t.Error("the default driver was not built")
+	} else if _, ok := q.(*PostgresQueue); !ok {
+		t.Errorf("default driver built %T, want postgres", q)
+	}
+}
+
+func TestBackoffGrows(t *testing.T) {
+	prev := time.Duration(0)
+	for attempts := 1; attempts < maxAttempts; attempts++ {
+		d := backoff(attempts)
+		if d <= prev {
+			t.Errorf("backoff(%d) = %v, not longer than %v", attempts, d, prev)
+		}
+		prev = d
+	}
+}
+
+func TestRunHandlerTurnsPanicsIntoErrors(t *testing.T) {
+	err := runHandler(context.Background(), func(context.Context, []byte) error { panic("boom") }, nil)
+	if err == nil {
+		t.Error("a panicking handler succeeded")
+	}
+	if err := runHandler(context.Background(), nil, nil); err == nil {
+		t.Error("a task without a handler succeeded")
+	}
+}
@@ -0,0 +1,70 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/hibiken/asynq"
+)
+
+// RedisQueue keeps tasks in Redis through asynq, for deployments that
+// would rather keep queue traffic off the database.
+type RedisQueue struct {
+	redis       asynq.RedisConnOpt
+	client      *asynq.Client
+	concurrency int
+}
+
+func NewRedisQueue(url string, concurrency int) (*RedisQueue, error) {
+	opt, err := asynq.ParseRedisURI(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse REDIS_URL: %w", err)
+	}
+	return &RedisQueue{redis: opt, client: asynq.NewClient(opt), concurrency: concurrency}, nil
+}
+
+func (q *RedisQueue) Enqueue(ctx context.Context, kind string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode %s task: %w", kind, err)
+	}
+	if _, err := q.client.EnqueueContext(ctx, asynq.NewTask(kind, body), asynq.MaxRetry(maxAttempts-1)); err != nil {
+		return fmt.Errorf("enqueue %s task: %w", kind, err)
+	}
+	metrics.QueueTasksTotal.WithLabelValues(kind, "enqueued").Inc()
+	return nil
+}
+
+func (q *RedisQueue) Consume(ctx context.Context, handlers map[string]Handler) error {
+	if len(handlers) == 0 {
+		return nil
+	}
+	mux := asynq.NewServeMux()
+	for kind, handler := range handlers {
+		mux.HandleFunc(kind, func(ctx context.Context, t *asynq.Task) error {
+			if err := runHandler(ctx, handler, t.Payload()); err != nil {
+				outcome := "retried"
+				if retried, _ := asynq.GetRetryCount(ctx); retried >= maxAttempts-1 {
+					outcome = "dead"
+				}
+				metrics.QueueTasksTotal.WithLabelValues(kind, outcome).Inc()
+				return err
+			}
+			metrics.QueueTasksTotal.WithLabelValues(kind, "succeeded").Inc()
+			return nil
+		})
+	}
+	srv := asynq.NewServer(q.redis, asynq.Config{
+		Concurrency:    q.concurrency,
+		RetryDelayFunc: func(n int, _ error, _ *asynq.Task) time.Duration { return backoff(n + 1) },
+	})
+	if err := srv.Start(mux); err != nil {
+		return fmt.Errorf("start queue consumer: %w", err)
+	}
+	<-ctx.Done()
+	srv.Shutdown()
+	return nil
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryBucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // when the bucket will have refilled completely
+}
+
+// MemoryLimiter keeps buckets in process memory. Limits are per instance, so
+// it is meant for development and single-instance deployments.
+type MemoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	now     func() time.Time
+	sweeps  int
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{buckets: make(map[string]*memoryBucket), now: time.Now}
+}
+
+func (m *MemoryLimiter) Allow(_ context.Context, key string, rate Rate) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &memoryBucket{}
+		m.buckets[key] = b
+	}
+
+	tokens, res := bucketState(b.tokens, b.last, now, rate)
+	b.tokens, b.last, b.full = tokens, now, now.Add(res.ResetAfter)
+
+	m.sweeps++
+	if m.sweeps%1000 == 0 {
+		m.sweep(now)
+	}
+	return res, nil
+}
+
+// sweep drops buckets that have refilled completely, each at its own rate,
+// since a new bucket starts full.
+func (m *MemoryLimiter) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Package ratelimit implements token-bucket rate limiting backed by Redis, or
+// by process memory when Redis is not configured.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// Rate allows Limit requests per Period, refilled continuously. Bursts up to
+// Limit are allowed.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// ParseRate parses "20/1m" style values; an empty or invalid value yields fallback.
+func ParseRate(value string, fallback Rate) Rate {
+	count, period, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return fallback
+	}
+	limit, err := strconv.Atoi(count)
+	if err != nil || limit <= 0 {
+		return fallback
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return Rate{Limit: limit, Period: d}
+}
+
+func (r Rate) String() string {
+	return fmt.Sprintf("%d/%s", r.Limit, r.Period)
+}
+
+// Result describes the bucket after a request was counted.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until one token is available (zero when allowed).
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again.
+	ResetAfter time.Duration
+}
+
+// Limiter takes one token from the bucket identified by key.
+type Limiter interface {
+	Allow(ctx context.Context, key string, rate Rate) (Result, error)
+}
+
+// bucketState computes token-bucket arithmetic shared by both stores.
+// tokens and last describe the stored bucket; a zero last means a new bucket.
+func bucketState(tokens float64, last, now time.Time, rate Rate) (float64, Result) {
+	capacity := float64(rate.Limit)
+	perToken := rate.Period / time.Duration(rate.Limit)
+
+	if last.IsZero() {
+		tokens = capacity
+	} else if elapsed := now.Sub(last); elapsed > 0 {
+		tokens += float64(elapsed) / float64(perToken)
+		if tokens > capacity {
+			tokens = capacity
+		}
+	}
+
+	res := Result{Limit: rate.Limit}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration((1 - tokens) * float64(perToken))
+	}
+	res.Remaining = int(tokens)
+	res.ResetAfter = time.Duration((capacity - tokens) * float64(perToken))
+	return tokens, res
+}
+
+// New returns a Redis-backed limiter when REDIS_URL is configured and
+// reachable, and an in-memory limiter otherwise.
+func New(cfg *config.Config) Limiter {
+	if strings.TrimSpace(cfg.RedisURL) == "" {
+		return NewMemoryLimiter()
+	}
+
+	opts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		log.Printf("ratelimit: invalid REDIS_URL, using in-memory limiter: %v", err)
+		return NewMemoryLimiter()
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("ratelimit: redis unreachable, using in-memory limiter: %v", err)
+		client.Close()
+		return NewMemoryLimiter()
+	}
+	return NewRedisLimiter(client)
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMemoryLimiterTokenBucket(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := NewMemoryLimiter()
+	m.now = func() time.Time { return now }
+	rate := Rate{Limit: 3, Period: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		res, _ := m.Allow(context.Background(), "ip:1", rate)
+		if !res.Allowed || res.Remaining != 2-i {
+			t.Fatalf("request %d: unexpected %+v", i, res)
+		}
+	}
+
+	res, _ := m.Allow(context.Background(), "ip:1", rate)
+	if res.Allowed || res.RetryAfter != 20*time.Second {
+		t.Fatalf("expected rejection with 20s retry, got %+v", res)
+	}
+
+	if other, _ := m.Allow(context.Background(), "ip:2", rate); !other.Allowed {
+		t.Fatal("buckets must be independent per key")
+	}
+
+	now = now.Add(20 * time.Second)
+	if res, _ := m.Allow(context.Background(), "ip:1", rate); !res.Allowed {
+		t.Fatalf("expected one token after refill, got %+v", res)
+	}
+}
+
+func TestMemoryLimiterSweepKeepsLongPeriodBuckets(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := NewMemoryLimiter()
+	m.now = func() time.Time { return now }
+	hourly := Rate{Limit: 1, Period: time.Hour}
+	perMinute := Rate{Limit: 20, Period: time.Minute}
+
+	if res, _ := m.Allow(context.Background(), "discover:1", hourly); !res.Allowed {
+		t.Fatalf("first hourly request refused: %+v", res)
+	}
+	// Enough per-minute traffic, spread past a minute, to trigger sweeps.
+	for i := 0; i < 2000; i++ {
+		now = now.Add(time.Second)
+		m.Allow(context.Background(), "auth:"+strconv.Itoa(i), perMinute)
+	}
+
+	if res, _ := m.Allow(context.Background(), "discover:1", hourly); res.Allowed {
+		t.Fatal("the hourly bucket was swept and refilled early")
+	}
+	if _, ok := m.buckets["auth:0"]; ok {
+		t.Error("an idle, refilled per-minute bucket was not swept")
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	fallback := Rate{Limit: 1, Period: time.Second}
+	if got := ParseRate("20/1m", fallback); got != (Rate{Limit: 20, Period: time.Minute}) {
+		t.Fatalf("unexpected rate %v", got)
+	}
+	for _, bad := range []string{"", "20", "0/1m", "x/1m", "5/soon"} {
+		if got := ParseRate(bad, fallback); got != fallback {
+			t.Fatalf("expected fallback for %q, got %v", bad, got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript runs the refill-and-take step atomically in Redis.
+// KEYS[1] bucket; ARGV: capacity, ms per token, now (ms).
+// Returns {allowed, tokens*1000}.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local per_token = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+  tokens = capacity
+  ts = now
+end
+
+local elapsed = math.max(0, now - ts)
+tokens = math.min(capacity, tokens + elapsed / per_token)
+
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity * per_token) + 1000)
+return {allowed, math.floor(tokens * 1000)}
+`)
+
+// RedisLimiter shares buckets across all API instances.
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisLimiter(client *redis.Client) *RedisLimiter {
+	return &RedisLimiter{client: client, prefix: "ratelimit:"}
+}
+
+func (r *RedisLimiter) Allow(ctx context.Context, key string, rate Rate) (Result, error) {
+	perToken := rate.Period / time.Duration(rate.Limit)
+	perTokenMs := float64(perToken) / float64(time.Millisecond)
+
+	values, err := tokenBucketScript.Run(ctx, r.client, []string{r.prefix + key},
+		rate.Limit, perTokenMs, time.Now().UnixMilli()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	tokens := float64(values[1]) / 1000
+	res := Result{Allowed: values[0] == 1, Limit: rate.Limit, Remaining: int(tokens)}
+	if !res.Allowed {
+		res.RetryAfter = time.Duration((1 - tokens) * float64(perToken))
+	}
+	res.ResetAfter = time.Duration((float64(rate.Limit) - tokens) * float64(perToken))
+	return res, nil
+}
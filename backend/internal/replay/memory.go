@@ -0,0 +1,46 @@
+package replay
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps nonces in process memory. Nonces are per instance, so it
+// is meant for development and single-instance deployments.
+type MemoryStore struct {
+	mu     sync.Mutex
+	seen   map[string]time.Time // key -> expiry
+	now    func() time.Time
+	sweeps int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{seen: make(map[string]time.Time), now: time.Now}
+}
+
+func (m *MemoryStore) Claim(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if expiry, ok := m.seen[key]; ok && now.Before(expiry) {
+		return false, nil
+	}
+	m.seen[key] = now.Add(ttl)
+
+	m.sweeps++
+	if m.sweeps%1000 == 0 {
+		m.sweep(now)
+	}
+	return true, nil
+}
+
+// sweep drops expired nonces.
+func (m *MemoryStore) sweep(now time.Time) {
+	for key, expiry := range m.seen {
+		if !now.Before(expiry) {
+			delete(m.seen, key)
+		}
+	}
+}
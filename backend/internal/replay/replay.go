@@ -0,0 +1,44 @@
+// Package replay remembers request nonces for a short window so a captured
+// request cannot be submitted twice. Nonces are kept in Redis, or in process
+// memory when Redis is not configured.
+package replay
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// Store records nonces. Claim reports whether key was new; a claimed key is
+// refused until ttl has passed.
+type Store interface {
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// New returns a Redis-backed store when REDIS_URL is configured and
+// reachable, and an in-memory store otherwise.
+func New(cfg *config.Config) Store {
+	if strings.TrimSpace(cfg.RedisURL) == "" {
+		return NewMemoryStore()
+	}
+
+	opts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		log.Printf("replay: invalid REDIS_URL, using in-memory nonce store: %v", err)
+		return NewMemoryStore()
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("replay: redis unreachable, using in-memory nonce store: %v", err)
+		client.Close()
+		return NewMemoryStore()
+	}
+	return NewRedisStore(client)
+}
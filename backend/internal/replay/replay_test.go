@@ -0,0 +1,29 @@
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreClaim(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if ok, _ := s.Claim(ctx, "a", time.Minute); !ok {
+		t.Fatal("first claim refused")
+	}
+	if ok, _ := s.Claim(ctx, "a", time.Minute); ok {
+		t.Fatal("replayed nonce accepted")
+	}
+	if ok, _ := s.Claim(ctx, "b", time.Minute); !ok {
+		t.Fatal("other nonce refused")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := s.Claim(ctx, "a", time.Minute); !ok {
+		t.Fatal("nonce still refused after its ttl")
+	}
+}
@@ -1,69 +1,321 @@
 package routes
 
 import (
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/cache"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/handlers"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/middleware"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/ratelimit"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/replay"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 )
 
+// SetupOps configures the operational routes every run mode serves, the
+// API or not: the Prometheus scrape endpoint, the health checks, the build
+// version and the deploy hooks.
+func SetupOps(app *fiber.App, cfg *config.Config, healthHandler *handlers.HealthHandler) {
+	// Prometheus scrape endpoint (bearer token when METRICS_TOKEN is set)
+	app.Get("/metrics", middleware.MetricsAuth(cfg), adaptor.HTTPHandler(metrics.Handler()))
+
+	// Health checks: /health/live for liveness probes, /health/ready probes
+	// the dependencies
+	api := app.Group("/api")
+	api.Get("/health", healthHandler.Check)
+	api.Get("/health/live", healthHandler.Live)
+	api.Get("/health/ready", healthHandler.Ready)
+	api.Get("/version", healthHandler.Version)
+
+	// Deploy hooks (only when DEPLOY_HOOK_TOKEN is set): drain before stop
+	if cfg.DeployHookToken != "" {
+		api.Post("/health/drain", middleware.DeployHookAuth(cfg), healthHandler.Drain)
+	}
+}
+
 // Setup configures all API routes for the application
-func Setup(app *fiber.App, cfg *config.Config, authHandler *handlers.AuthHandler, healthHandler *handlers.HealthHandler, webhookHandler *handlers.WebhookHandler, moderationHandler *handlers.ModerationHandler, auraHandler *handlers.AuraHandler, auraMatchHandler *handlers.AuraMatchHandler, streakHandler *handlers.StreakHandler, legalHandler *handlers.LegalHandler) {
+func Setup(app *fiber.App, cfg *config.Config, limiter ratelimit.Limiter, nonces replay.Store, authHandler *handlers.AuthHandler, healthHandler *handlers.HealthHandler, webhookHandler *handlers.WebhookHandler, moderationHandler *handlers.ModerationHandler, auraHandler *handlers.AuraHandler, auraMatchHandler *handlers.AuraMatchHandler, streakHandler *handlers.StreakHandler, legalHandler *handlers.LegalHandler, shareHandler *handlers.ShareHandler, memoryHandler *handlers.MemoryHandler, notificationHandler *handlers.NotificationHandler, emailHandler *handlers.EmailHandler, adminUserHandler *handlers.AdminUserHandler, userHandler *handlers.UserHandler, contactDiscoveryHandler *handlers.ContactDiscoveryHandler, friendHandler *handlers.FriendHandler, exportHandler *handlers.ExportHandler, onboardingHandler *handlers.OnboardingHandler, surveyHandler *handlers.SurveyHandler, forecastHandler *handlers.ForecastHandler, usageHandler *handlers.UsageHandler, researchExportHandler *handlers.ResearchExportHandler, billingHandler *handlers.BillingHandler, responseCache *cache.Store, cacheHandler *handlers.CacheHandler, auditHandler *handlers.AuditHandler, aiHistoryHandler *handlers.AIHistoryHandler, docsHandler *handlers.DocsHandler, dataMigrationHandler *handlers.DataMigrationHandler, insightHandler *handlers.InsightHandler, similarDiscoveryHandler *handlers.SimilarDiscoveryHandler, requestLogHandler *handlers.RequestLogHandler, promptTemplateHandler *handlers.PromptTemplateHandler, experimentHandler *handlers.ExperimentHandler) {
+	SetupOps(app, cfg, healthHandler)
+
 	api := app.Group("/api")
 
-	// Health check
-	api.Get("/health", healthHandler.Check)
+	// API description and Swagger UI (development and staging only)
+	if cfg.APIDocs {
+		api.Get("/docs", docsHandler.UI)
+		api.Get("/docs/openapi.yaml", docsHandler.SpecYAML)
+		api.Get("/docs/openapi.json", docsHandler.SpecJSON)
+	}
+
+	// Legal pages (cached here and at the CDN; purge the "legal" tag after
+	// editing them)
+	legalCache := middleware.Cache(responseCache, middleware.CacheConfig{
+		TTL:                  10 * time.Minute,
+		StaleWhileRevalidate: time.Hour,
+		Tags:                 []string{"legal"},
+	})
+	legalEdge := middleware.EdgeCache(middleware.EdgePolicy{
+		MaxAge:               10 * time.Minute,
+		SharedMaxAge:         24 * time.Hour,
+		StaleWhileRevalidate: time.Hour,
+		Keys:                 []string{"legal"},
+	})
+	api.Get("/privacy-policy", legalEdge, legalCache, legalHandler.PrivacyPolicy)
+	api.Get("/terms", legalEdge, legalCache, legalHandler.TermsOfService)
 
-	// Legal pages
-	api.Get("/privacy-policy", legalHandler.PrivacyPolicy)
-	api.Get("/terms", legalHandler.TermsOfService)
+	// Public share links (signed token, no auth). The CDN copy is kept short
+	// because a deleted reading stays visible until it expires.
+	shareEdge := middleware.EdgeCache(middleware.EdgePolicy{
+		MaxAge:       5 * time.Minute,
+		SharedMaxAge: 15 * time.Minute,
+		Keys:         []string{"share"},
+	})
+	api.Get("/share/:token", shareEdge, shareHandler.GetShared)
+
+	authLimit := middleware.RateLimit(limiter, middleware.RateLimitConfig{
+		Name:  "auth",
+		PerIP: ratelimit.ParseRate(cfg.RateLimitAuth, ratelimit.Rate{Limit: 20, Period: time.Minute}),
+	})
+	scanLimit := middleware.RateLimit(limiter, middleware.RateLimitConfig{
+		Name:    "scan",
+		PerIP:   ratelimit.ParseRate(cfg.RateLimitScanIP, ratelimit.Rate{Limit: 30, Period: time.Minute}),
+		PerUser: ratelimit.ParseRate(cfg.RateLimitScanUser, ratelimit.Rate{Limit: 10, Period: time.Minute}),
+	})
+	scanReplay := middleware.ReplayGuard(nonces, middleware.ReplayConfig{
+		Name:     "scan",
+		Window:   cfg.ReplayWindow,
+		Required: cfg.ReplayNonceRequired,
+	})
 
 	// Public auth routes
-	auth := api.Group("/auth")
+	auth := api.Group("/auth", authLimit)
 	auth.Post("/register", authHandler.Register)
 	auth.Post("/login", authHandler.Login)
 	auth.Post("/refresh", authHandler.RefreshToken)
 	auth.Post("/apple", authHandler.AppleSignIn)
+	auth.Post("/restore", authHandler.RestoreAccount)
+	auth.Post("/forgot-password", authHandler.ForgotPassword)
+	auth.Post("/reset-password", authHandler.ResetPassword)
+	auth.Post("/email/confirm", authHandler.ConfirmEmailChange)
+	auth.Post("/verify-email", authHandler.VerifyEmail)
 
 	// Webhooks (public but auth-header verified)
 	api.Post("/webhooks/revenuecat", webhookHandler.HandleRevenueCat)
+	api.Post("/webhooks/stripe", webhookHandler.HandleStripe)
+	api.Post("/webhooks/email/ses", webhookHandler.HandleSESFeedback)
+
+	// Polled read endpoints answer If-None-Match with 304 Not Modified
+	etag := middleware.ETag()
 
-	// Protected routes (require JWT)
-	protected := api.Group("", middleware.JWTProtected(cfg))
+	// Protected routes (require JWT). Banned users keep access to their
+	// account (sign out, export, deletion) and to appeals.
+	protected := api.Group("", middleware.JWTProtected(cfg, authHandler.AccessTokenValid),
+		middleware.RejectBanned(moderationHandler.Banned, "/api/auth/", "/api/moderation/"))
 
 	// Auth (protected)
 	protected.Post("/auth/logout", authHandler.Logout)
 	protected.Post("/auth/claim", authHandler.ClaimGuest)
 	protected.Delete("/auth/account", authHandler.DeleteAccount)
-	protected.Get("/auth/profile", authHandler.GetProfile)
+	protected.Get("/auth/profile", etag, authHandler.GetProfile)
+	protected.Put("/auth/profile/timezone", authHandler.UpdateTimezone)
+	protected.Patch("/auth/email", authLimit, authHandler.ChangeEmail)
+	protected.Post("/auth/resend-verification", authLimit, authHandler.ResendVerification)
+	protected.Get("/auth/sessions", authHandler.ListSessions)
+	protected.Delete("/auth/sessions/:id", authHandler.RevokeSession)
+	protected.Get("/auth/export", authLimit, exportHandler.RequestExport)
+	protected.Get("/auth/export/:id/download", exportHandler.Download)
+
+	// Plan entitlements
+	protected.Get("/subscription/entitlements", billingHandler.GetEntitlements)
+
+	// Web billing (Stripe Checkout and customer portal)
+	protected.Post("/billing/stripe/checkout", billingHandler.CreateCheckout)
+	protected.Post("/billing/stripe/portal", billingHandler.CreatePortal)
+
+	// Plan usage (stored photos)
+	protected.Get("/usage", usageHandler.Get)
+
+	// AI calls made on the user's behalf
+	protected.Get("/ai/history", aiHistoryHandler.List)
+
+	// Server-driven onboarding
+	protected.Get("/onboarding", onboardingHandler.GetFlow)
+	protected.Post("/onboarding/steps/:id/complete", onboardingHandler.CompleteStep)
+	protected.Post("/onboarding/steps/:id/skip", onboardingHandler.SkipStep)
+
+	// In-app surveys
+	protected.Get("/surveys/pending", surveyHandler.Pending)
+	protected.Post("/surveys/:id/responses", surveyHandler.SubmitResponse)
+
+	// Social features can require a verified email (REQUIRE_VERIFIED_EMAIL)
+	requireVerified := middleware.RequireVerifiedEmail(cfg, authHandler.EmailVerified)
+
+	// Handles, discovery settings and friend search
+	protected.Put("/users/me/handle", userHandler.UpdateHandle)
+	protected.Put("/users/me/discovery", userHandler.UpdateDiscovery)
+	protected.Put("/users/me/leaderboard", userHandler.UpdateLeaderboard)
+	protected.Put("/users/me/research-consent", userHandler.UpdateResearchConsent)
+	protected.Get("/users/search", userHandler.Search)
+
+	// Contact discovery: clients upload salted hashes, never raw contacts
+	discoverLimit := middleware.RateLimit(limiter, middleware.RateLimitConfig{
+		Name:    "discover",
+		PerUser: ratelimit.ParseRate(cfg.RateLimitDiscover, ratelimit.Rate{Limit: 10, Period: time.Hour}),
+	})
+	protected.Get("/friends/discover/salt", contactDiscoveryHandler.Salt)
+	protected.Post("/friends/discover", discoverLimit, contactDiscoveryHandler.Discover)
+
+	// Opt-in, anonymized discovery of adults in the region with similar auras
+	protected.Put("/users/me/similar-discovery", similarDiscoveryHandler.UpdateSettings)
+	protected.Get("/discover/similar", discoverLimit, requireVerified, similarDiscoveryHandler.Similar)
+
+	// Friends, friend requests and QR/deep-link invite codes
+	protected.Get("/friends", friendHandler.ListFriends)
+	protected.Post("/friends/invite-code", friendHandler.CreateInviteCode)
+	protected.Get("/friends/invite-codes", friendHandler.ListInviteCodes)
+	protected.Get("/friends/invite-code/:code/qr.png", friendHandler.InviteQRCode)
+	protected.Delete("/friends/invite-code/:code", friendHandler.RevokeInviteCode)
+	protected.Post("/friends/invite-code/:code/redeem", authLimit, friendHandler.RedeemInviteCode)
+	protected.Post("/friends/invite", authLimit, requireVerified, friendHandler.Invite)
+	protected.Get("/friends/requests", friendHandler.ListRequests)
+	protected.Post("/friends/requests/:id/accept", friendHandler.AcceptRequest)
+	protected.Post("/friends/requests/:id/decline", friendHandler.DeclineRequest)
+	protected.Delete("/friends/:id", friendHandler.Unfriend)
 
 	// Aura routes
 	aura := protected.Group("/aura")
 	aura.Get("/scan/check", auraHandler.CheckScanEligibility)
-	aura.Post("/scan", auraHandler.Scan)
-	aura.Post("/scan/upload", auraHandler.ScanWithUpload)
-	aura.Get("/stats", auraHandler.Stats)
-	aura.Get("/:id", auraHandler.GetByID)
-	aura.Get("", auraHandler.List)
+	aura.Post("/scan", scanLimit, scanReplay, auraHandler.Scan)
+	aura.Post("/scan/upload", scanLimit, scanReplay, auraHandler.ScanWithUpload)
+	aura.Post("/scan/group", scanLimit, scanReplay, auraHandler.ScanGroup)
+	aura.Post("/scan/batch", scanLimit, scanReplay, auraHandler.ScanBatch)
+	aura.Get("/scan/batch/:id", auraHandler.GetScanBatch)
+	aura.Get("/scan/jobs/:id/position", auraHandler.ScanJobPosition)
+	aura.Get("/scan/jobs/:id/position/ws", auraHandler.WatchScanJob)
+	aura.Get("/group/:id", auraHandler.GetGroup)
+	aura.Get("/stats", etag, auraHandler.Stats)
+	aura.Get("/traits", etag, auraHandler.Traits)
+	aura.Get("/compatibility/today", etag, auraMatchHandler.GetCompatibilityToday)
+	aura.Get("/forecast/today", etag, forecastHandler.GetToday)
+	aura.Post("/:id/share", requireVerified, shareHandler.CreateShare)
+	aura.Post("/:id/rating", auraHandler.Rate)
+	aura.Put("/:id/note", auraHandler.SetNote)
+	aura.Post("/:id/pin", auraHandler.Pin)
+	aura.Delete("/:id/pin", auraHandler.Unpin)
+	aura.Get("/:id/theme", auraHandler.Theme)
+	aura.Get("/:id/similar", auraHandler.Similar)
+	aura.Get("/:id/photo", auraHandler.Photo)
+	aura.Post("/:id/photo/restore", auraHandler.RestorePhoto)
+	aura.Post("/:id/regenerate", scanLimit, scanReplay, auraHandler.Regenerate)
+	aura.Get("/:id/thumbnail", auraHandler.Thumbnail)
+	aura.Get("/:id", etag, auraHandler.GetByID)
+	aura.Get("", etag, auraHandler.List)
+	aura.Delete("/all", auraHandler.DeleteAll)
+	aura.Delete("", auraHandler.DeleteMany)
 
 	// Aura Match routes
-	match := protected.Group("/match")
+	match := protected.Group("/match", requireVerified)
 	match.Post("", auraMatchHandler.CreateMatch)
 	match.Get("", auraMatchHandler.GetMatches)
 	match.Get("/:friend_id", auraMatchHandler.GetMatchByFriend)
+	match.Get("/:friend_id/narrative", scanLimit, auraMatchHandler.GetMatchNarrative)
 
 	// Streak routes
 	streak := protected.Group("/streak")
-	streak.Get("", streakHandler.GetStreak)
+	streak.Get("", etag, streakHandler.GetStreak)
 	streak.Post("/update", streakHandler.UpdateStreak)
 
+	// Insights drawn from the reading history
+	protected.Get("/insights/themes", etag, insightHandler.GetThemes)
+
+	// AI personalization memory (opt-in)
+	memory := protected.Group("/memory")
+	memory.Get("", memoryHandler.List)
+	memory.Get("/export", memoryHandler.Export)
+	memory.Put("/settings", memoryHandler.UpdateSettings)
+	memory.Post("", memoryHandler.Create)
+	memory.Put("/:id", memoryHandler.Update)
+	memory.Delete("/:id", memoryHandler.Delete)
+	memory.Delete("", memoryHandler.DeleteAll)
+
+	// Notification settings and inbox
+	protected.Get("/settings/notifications", notificationHandler.GetPreferences)
+	protected.Put("/settings/notifications", notificationHandler.UpdatePreferences)
+	protected.Get("/notifications", notificationHandler.ListInbox)
+	protected.Put("/notifications/:id/read", notificationHandler.MarkRead)
+	protected.Post("/notifications/devices", notificationHandler.RegisterDevice)
+	protected.Delete("/notifications/devices/:token", notificationHandler.UnregisterDevice)
+
 	// Moderation routes
 	protected.Post("/reports", moderationHandler.CreateReport)
 	protected.Post("/blocks", moderationHandler.BlockUser)
 	protected.Delete("/blocks/:id", moderationHandler.UnblockUser)
+	protected.Get("/moderation/actions", moderationHandler.ListMyActions)
+	protected.Get("/moderation/appeals", moderationHandler.ListMyAppeals)
+	protected.Post("/moderation/appeals", moderationHandler.CreateAppeal)
 
 	// Admin routes
-	admin := protected.Group("/admin", middleware.AdminOnly(cfg))
+	admin := protected.Group("/admin", middleware.AdminOnly(cfg, authHandler.AccountEmail),
+		middleware.AuditAdminRequests(auditHandler.Record))
 	admin.Get("/moderation/reports", moderationHandler.ListReports)
 	admin.Put("/moderation/reports/:id", moderationHandler.ActionReport)
+	admin.Get("/moderation/cases", moderationHandler.ListCases)
+	admin.Post("/moderation/cases/resolve", moderationHandler.ResolveCases)
+	admin.Post("/moderation/cases/escalate", moderationHandler.EscalateCases)
+	admin.Get("/moderation/cases/:id", moderationHandler.GetCase)
+	admin.Post("/moderation/users/:id/actions", moderationHandler.TakeAction)
+	admin.Post("/moderation/actions/:id/revoke", moderationHandler.RevokeAction)
+	admin.Get("/moderation/appeals", moderationHandler.ListAppeals)
+	admin.Post("/moderation/appeals/:id/review", moderationHandler.ReviewAppeal)
+	admin.Get("/moderation/audit-log", moderationHandler.ListAuditLog)
+	admin.Get("/audit-logs", auditHandler.List)
+	admin.Get("/requests/:id", requestLogHandler.Lookup)
+	admin.Post("/aura/analyze", auraHandler.AdminAnalyze)
+	admin.Get("/aura/prompt-variants", auraHandler.PromptVariantStats)
+	admin.Get("/aura/ratings", auraHandler.RatingStats)
+	admin.Patch("/aura/readings/:id", auraHandler.AdminCorrectReading)
+	admin.Get("/aura/readings/:id/corrections", auraHandler.AdminListCorrections)
+	admin.Get("/prompt-templates", promptTemplateHandler.List)
+	admin.Post("/prompt-templates", promptTemplateHandler.Create)
+	admin.Get("/prompt-templates/stats", promptTemplateHandler.Stats)
+	admin.Get("/prompt-templates/:id", promptTemplateHandler.Get)
+	admin.Post("/prompt-templates/:id/activate", promptTemplateHandler.Activate)
+	admin.Post("/prompt-templates/:id/deactivate", promptTemplateHandler.Deactivate)
+	admin.Delete("/prompt-templates/:id", promptTemplateHandler.Delete)
+	admin.Get("/experiments", experimentHandler.List)
+	admin.Post("/experiments", experimentHandler.Create)
+	admin.Get("/experiments/:id", experimentHandler.Get)
+	admin.Put("/experiments/:id", experimentHandler.Update)
+	admin.Delete("/experiments/:id", experimentHandler.Delete)
+	admin.Post("/experiments/:id/start", experimentHandler.Start)
+	admin.Post("/experiments/:id/stop", experimentHandler.Stop)
+	admin.Get("/experiments/:id/results", experimentHandler.Results)
+	admin.Get("/emails/templates", emailHandler.ListTemplates)
+	admin.Get("/emails/preview/:name", emailHandler.Preview)
+	admin.Post("/emails/preview/:name", emailHandler.Preview)
+	admin.Post("/emails/send-test", emailHandler.SendTest)
+	admin.Get("/emails/suppressions", emailHandler.ListSuppressions)
+	admin.Delete("/emails/suppressions/:email", emailHandler.DeleteSuppression)
+	admin.Get("/metrics/overview", adminUserHandler.MetricsOverview)
+	admin.Get("/users", adminUserHandler.ListUsers)
+	admin.Get("/users/:id", adminUserHandler.GetUser)
+	admin.Post("/users/:id/logout", adminUserHandler.ForceLogout)
+	admin.Post("/users/:id/premium", adminUserHandler.GrantPremium)
+	admin.Post("/users/:id/reset-quota", adminUserHandler.ResetQuota)
+	admin.Post("/users/:id/message", adminUserHandler.SendMessage)
+	admin.Put("/users/:id/leaderboard-name", adminUserHandler.OverrideLeaderboardName)
+	admin.Delete("/cache", cacheHandler.Purge)
+	admin.Get("/webhooks/events", webhookHandler.ListEvents)
+	admin.Post("/webhooks/events/:id/replay", webhookHandler.ReplayEvent)
+	admin.Get("/events", webhookHandler.ListProcessedEvents)
+	admin.Post("/events/:id/release", webhookHandler.ReleaseProcessedEvent)
+	admin.Get("/research-exports", researchExportHandler.List)
+	admin.Post("/research-exports", researchExportHandler.Run)
+	admin.Get("/migrations", dataMigrationHandler.List)
+	admin.Put("/migrations/:name/phase", dataMigrationHandler.SetPhase)
+	admin.Get("/surveys", surveyHandler.ListSurveys)
+	admin.Post("/surveys", surveyHandler.CreateSurvey)
+	admin.Put("/surveys/:id", surveyHandler.UpdateSurvey)
+	admin.Get("/surveys/:id/report", surveyHandler.Report)
 }
@@ -0,0 +1,93 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/api"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/handlers"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+	"gopkg.in/yaml.v3"
+)
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// TestOpenAPIPathsAreRouted keeps the SDK spec from describing endpoints
+// that no longer exist: every operation in api/openapi.yaml must be served.
+func TestOpenAPIPathsAreRouted(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]yaml.Node `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(api.OpenAPI, &spec); err != nil {
+		t.Fatalf("parsing openapi.yaml: %v", err)
+	}
+	if len(spec.Paths) == 0 {
+		t.Fatal("openapi.yaml has no paths")
+	}
+
+	// Handlers are only referenced, never called, so nil ones are fine.
+	app := fiber.New()
+	Setup(app, &config.Config{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	routed := make(map[string]bool)
+	for _, r := range app.GetRoutes(true) {
+		routed[r.Method+" "+r.Path] = true
+	}
+
+	for path, ops := range spec.Paths {
+		route := "/api" + pathParam.ReplaceAllString(path, ":$1")
+		for method := range ops {
+			method = strings.ToUpper(method)
+			if !isHTTPMethod(method) {
+				continue
+			}
+			if !routed[method+" "+route] {
+				t.Errorf("openapi.yaml documents %s %s but no such route is registered", method, path)
+			}
+		}
+	}
+}
+
+// TestDocsServeSpec checks the spec converts to JSON for Swagger UI and
+// that the docs stay off unless enabled.
+func TestDocsServeSpec(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		cfg := &config.Config{APIDocs: enabled}
+		app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(cfg)})
+		Setup(app, cfg, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, handlers.NewDocsHandler(), nil, nil, nil, nil, nil, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/docs/openapi.json", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !enabled {
+			if resp.StatusCode == http.StatusOK {
+				t.Fatalf("docs disabled: status %d", resp.StatusCode)
+			}
+			continue
+		}
+		var spec struct {
+			OpenAPI string         `json:"openapi"`
+			Paths   map[string]any `json:"paths"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("status %d: %v", resp.StatusCode, err)
+		}
+		if spec.OpenAPI == "" || len(spec.Paths) == 0 {
+			t.Fatalf("converted spec is missing openapi or paths")
+		}
+	}
+}
+
+func isHTTPMethod(m string) bool {
+	switch m {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
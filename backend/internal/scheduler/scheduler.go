@@ -0,0 +1,162 @@
+// Package scheduler runs jobs on cron schedules. Every instance that runs
+// the scheduler fires each job on schedule, and a Locker makes sure only
+// one of them runs it at a time; the others skip that run. Jobs should be
+// idempotent, working through whatever is due, since a run that finishes
+// quickly can be followed by another instance's run for the same tick.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/robfig/cron/v3"
+)
+
+// Locker takes a lock shared by every instance. TryLock does not wait: ok
+// is false when another holder has the lock.
+type Locker interface {
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+// Job is the work a scheduled job does on each run.
+type Job func(ctx context.Context) error
+
+type entry struct {
+	name     string
+	spec     string
+	schedule cron.Schedule
+	run      Job
+	next     time.Time
+	running  atomic.Bool
+}
+
+// Scheduler holds the registered jobs until Run starts them.
+type Scheduler struct {
+	locker  Locker
+	entries []*entry
+	now     func() time.Time
+}
+
+func New(locker Locker) *Scheduler {
+	return &Scheduler{locker: locker, now: time.Now}
+}
+
+// Register adds a job. spec is a standard five-field cron expression in
+// UTC ("*/15 * * * *"), a descriptor such as "@hourly", or "@every 5m".
+func (s *Scheduler) Register(name, spec string, run Job) error {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+	for _, e := range s.entries {
+		if e.name == name {
+			return fmt.Errorf("job %s is already registered", name)
+		}
+	}
+	s.entries = append(s.entries, &entry{name: name, spec: spec, schedule: schedule, run: run})
+	return nil
+}
+
+// Jobs lists the registered jobs as name: spec, for startup logs.
+func (s *Scheduler) Jobs() []string {
+	jobs := make([]string, len(s.entries))
+	for i, e := range s.entries {
+		jobs[i] = e.name + ": " + e.spec
+	}
+	return jobs
+}
+
+// Run fires each job on its schedule until ctx is cancelled, then waits for
+// the runs in progress to return.
+func (s *Scheduler) Run(ctx context.Context) {
+	if len(s.entries) == 0 {
+		return
+	}
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	now := s.now().UTC()
+	for _, e := range s.entries {
+		e.next = e.schedule.Next(now)
+	}
+	for {
+		timer := time.NewTimer(s.nextRun().Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := s.now().UTC()
+		for _, e := range s.entries {
+			if e.next.After(now) {
+				continue
+			}
+			e.next = e.schedule.Next(now)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.fire(ctx, e)
+			}()
+		}
+	}
+}
+
+func (s *Scheduler) nextRun() time.Time {
+	next := s.entries[0].next
+	for _, e := range s.entries[1:] {
+		if e.next.Before(next) {
+			next = e.next
+		}
+	}
+	return next
+}
+
+// fire runs a job unless its previous run is still going here or another
+// instance holds its lock.
+func (s *Scheduler) fire(ctx context.Context, e *entry) {
+	if !e.running.CompareAndSwap(false, true) {
+		metrics.SchedulerJobRunsTotal.WithLabelValues(e.name, "overlapping").Inc()
+		return
+	}
+	defer e.running.Store(false)
+
+	unlock, ok, err := s.locker.TryLock(ctx, e.name)
+	if err != nil {
+		log.Printf("scheduler: %s: lock: %v", e.name, err)
+		metrics.SchedulerJobRunsTotal.WithLabelValues(e.name, "failed").Inc()
+		return
+	}
+	if !ok {
+		metrics.SchedulerJobRunsTotal.WithLabelValues(e.name, "locked").Inc()
+		return
+	}
+	defer unlock()
+
+	start := time.Now()
+	err = s.runJob(ctx, e)
+	metrics.SchedulerJobDuration.WithLabelValues(e.name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		log.Printf("scheduler: %s: %v", e.name, err)
+		metrics.SchedulerJobRunsTotal.WithLabelValues(e.name, "failed").Inc()
+		return
+	}
+	metrics.SchedulerJobRunsTotal.WithLabelValues(e.name, "succeeded").Inc()
+}
+
+// runJob turns a panicking job into a failed run, so one bad job does not
+// take the process down with it.
+func (s *Scheduler) runJob(ctx context.Context, e *entry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return e.run(ctx)
+}
@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRegisterRejectsBadSpecsAndDuplicates(t *testing.T) {
+	s := New(NewLocalLocker())
+	noop := func(context.Context) error { return nil }
+	if err := s.Register("bad", "every minute", noop); err == nil {
+		t.Error("a malformed spec was accepted")
+	}
+	if err := s.Register("purge", "@hourly", noop); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Register("purge", "*/5 * * * *", noop); err == nil {
+		t.Error("a second job named purge was accepted")
+	}
+	if got := s.Jobs(); len(got) != 1 || got[0] != "purge: @hourly" {
+		t.Errorf("Jobs() = %v", got)
+	}
+}
+
+func TestFireSkipsLockedJobs(t *testing.T) {
+	locker := NewLocalLocker()
+	s := New(locker)
+	var runs atomic.Int32
+	if err := s.Register("purge", "@hourly", func(context.Context) error { runs.Add(1); return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, ok, _ := locker.TryLock(context.Background(), "purge")
+	if !ok {
+		t.Fatal("could not take the lock")
+	}
+	s.fire(context.Background(), s.entries[0])
+	if runs.Load() != 0 {
+		t.Error("the job ran while another holder had its lock")
+	}
+
+	unlock()
+	s.fire(context.Background(), s.entries[0])
+	if runs.Load() != 1 {
+		t.Errorf("runs = %d after the lock was released, want 1", runs.Load())
+	}
+	if _, ok, _ := locker.TryLock(context.Background(), "purge"); !ok {
+		t.Error("the lock was not released after the run")
+	}
+}
+
+func TestFireRecoversPanickingJobs(t *testing.T) {
+	s := New(NewLocalLocker())
+	if err := s.Register("broken", "@hourly", func(context.Context) error { panic("boom") }); err != nil {
+		t.Fatal(err)
+	}
+	s.fire(context.Background(), s.entries[0])
+	if s.entries[0].running.Load() {
+		t.Error("a panicked job is still marked running")
+	}
+}
+
+func TestRunFiresDueJobsUntilCancelled(t *testing.T) {
+	s := New(NewLocalLocker())
+	ran := make(chan struct{}, 10)
+	if err := s.Register("tick", "@every 1s", func(context.Context) error {
+		ran <- struct{}{}
+		return errors.New("failures do not stop the schedule")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ran:
+		case <-time.After(3 * time.Second):
+			t.Fatalf("run %d did not happen", i+1)
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestLockKeyIsStablePerJob(t *testing.T) {
+	if lockKey("purge") != lockKey("purge") {
+		t.Error("lock key is not deterministic")
+	}
+	if lockKey("purge") == lockKey("reminders") {
+		t.Error("different jobs share a lock key")
+	}
+}
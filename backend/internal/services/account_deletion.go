@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAccountDeactivated = errors.New("this account is scheduled for deletion; restore it to sign in")
+	ErrNothingToRestore   = errors.New("no account pending deletion matches these credentials")
+)
+
+// activeUsersOnly hides rows owned by deactivated (soft-deleted) users. Each
+// column must hold a user ID.
+func activeUsersOnly(columns ...string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, col := range columns {
+			db = db.Where(col + " IN (SELECT id FROM users WHERE deleted_at IS NULL)")
+		}
+		return db
+	}
+}
+
+// isActiveUser reports whether the account exists and is not deactivated.
+func isActiveUser(db *gorm.DB, userID uuid.UUID) bool {
+	var count int64
+	db.Model(&models.User{}).Where("id = ?", userID).Count(&count)
+	return count > 0
+}
+
+// DeleteAccount implements Apple Guideline 5.1.1(v) - account deletion.
+// The account is deactivated (soft-deleted) and signed out everywhere; it can
+// be restored until the grace period ends, after which the purge job
+// erases it. Returns when the purge becomes due.
+func (s *AuthService) DeleteAccount(userID uuid.UUID, password string, device dto.DeviceInfo) (time.Time, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return time.Time{}, ErrUserNotFound
+	}
+
+	// Verify password (skip for Apple Sign-In users who have no password)
+	if user.Password != "" {
+		if strings.TrimSpace(password) == "" {
+			return time.Time{}, ErrInvalidCredentials
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+			return time.Time{}, ErrInvalidCredentials
+		}
+	}
+
+	now := time.Now()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Sign out everywhere, current access tokens included, and void
+		// pending reset/confirmation links
+		if err := signOutEverywhere(tx, userID, models.RevokeReasonAccountDelete); err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.AuthToken{}).Error; err != nil {
+			return err
+		}
+
+		// Drop queued digest notifications and push tokens
+		if err := tx.Where("user_id = ?", userID).Delete(&models.PendingNotification{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.DeviceToken{}).Error; err != nil {
+			return err
+		}
+
+		// Soft-delete the user; GORM's DeletedAt scope hides it from lookups
+		if err := tx.Model(&user).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, models.AuditLog{
+			ActorID: &userID, Action: "account.deleted", TargetType: auditTargetUser, TargetID: userID.String(), IPAddress: device.IP,
+		})
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(s.cfg.AccountDeletionGrace), nil
+}
+
+// RestoreAccount reactivates an account deleted within the grace period and
+// starts a new session. Password accounts restore with email and password,
+// Apple accounts with a fresh identity token.
+func (s *AuthService) RestoreAccount(req *dto.RestoreAccountRequest, device dto.DeviceInfo) (*dto.AuthResponse, error) {
+	cutoff := time.Now().Add(-s.cfg.AccountDeletionGrace)
+	query := s.db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at > ?", cutoff)
+
+	var user models.User
+	if req.IdentityToken != "" {
+		claims, err := verifyAppleIdentityToken(context.Background(), req.IdentityToken, splitCSV(s.cfg.AppleClientIDs))
+		if err != nil {
+			return nil, ErrInvalidCredentials
+		}
+		sub, _ := claims["sub"].(string)
+		if sub == "" || query.Where("apple_sub = ?", sub).First(&user).Error != nil {
+			return nil, ErrNothingToRestore
+		}
+	} else {
+		if err := query.Where("email = ?", strings.TrimSpace(req.Email)).First(&user).Error; err != nil {
+			return nil, ErrNothingToRestore
+		}
+		if user.Password == "" || bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+			return nil, ErrInvalidCredentials
+		}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&user).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, models.AuditLog{
+			ActorID: &user.ID, Action: "account.restored", TargetType: auditTargetUser, TargetID: user.ID.String(), IPAddress: device.IP,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	user.DeletedAt = gorm.DeletedAt{}
+	return s.startSession(&user, device)
+}
+
+// deactivatedUser finds an account in its deletion grace period by column.
+func (s *AuthService) deactivatedUser(column, value string) (*models.User, bool) {
+	var user models.User
+	err := s.db.Unscoped().
+		Where(column+" = ? AND deleted_at IS NOT NULL AND deleted_at > ?", value, time.Now().Add(-s.cfg.AccountDeletionGrace)).
+		First(&user).Error
+	return &user, err == nil
+}
+
+// AccountPurgeJob is the scheduled job that erases accounts whose grace
+// period has ended.
+func (s *AuthService) AccountPurgeJob(ctx context.Context) error {
+	n, err := s.PurgeDeactivatedAccounts(time.Now())
+	if n > 0 {
+		log.Printf("account purge: erased %d accounts", n)
+	}
+	return err
+}
+
+// PurgeDeactivatedAccounts permanently erases accounts deactivated longer
+// than the grace period ago, including their readings and image references.
+// An account that fails to purge is logged and left for the next run so it
+// does not hold up the others.
+func (s *AuthService) PurgeDeactivatedAccounts(now time.Time) (int, error) {
+	var ids []uuid.UUID
+	if err := s.db.Unscoped().Model(&models.User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at <= ?", now.Add(-s.cfg.AccountDeletionGrace)).
+		Limit(100).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		if err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := purgeAccount(tx, id); err != nil {
+				return err
+			}
+			return writeAudit(tx, nil, "account.purged", auditTargetUser, id, nil)
+		}); err != nil {
+			log.Printf("account purge: user %s: %v", id, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// purgeAccount hard-deletes the user and everything that references them.
+// It stops at the first failed statement so the caller's transaction rolls
+// back rather than leaving a half-erased account.
+func purgeAccount(tx *gorm.DB, userID uuid.UUID) error {
+	tx = tx.Unscoped()
+	user := map[string]interface{}{"user": userID}
+
+	for _, d := range []struct {
+		model any
+		where string
+	}{
+		// Tokens and sessions
+		{&models.RefreshToken{}, "user_id = @user"},
+		{&models.AuthToken{}, "user_id = @user"},
+		// Subscriptions
+		{&models.Subscription{}, "user_id = @user"},
+		// Reports filed by the user
+		{&models.Report{}, "reporter_id = @user"},
+		// Moderation actions against the user and their appeals
+		{&models.Appeal{}, "user_id = @user"},
+		{&models.ModerationAction{}, "user_id = @user"},
+		// Blocks
+		{&models.Block{}, "blocker_id = @user OR blocked_id = @user"},
+		// AI personalization memories and AI history
+		{&models.UserMemory{}, "user_id = @user"},
+		{&models.AIInteraction{}, "user_id = @user"},
+		// Failed requests kept for support
+		{&models.RequestLog{}, "user_id = @user"},
+		// Queued tasks about the user, which name them by user_id
+		{&models.QueueTask{}, "convert_from(payload, 'UTF8')::jsonb ->> 'user_id' = @user"},
+		// Notification settings and inbox
+		{&models.NotificationPreference{}, "user_id = @user"},
+		{&models.Notification{}, "user_id = @user"},
+		{&models.NotificationDispatch{}, "user_id = @user"},
+		{&models.AdminMessage{}, "user_id = @user"},
+		{&models.PendingNotification{}, "user_id = @user"},
+		{&models.DeviceToken{}, "user_id = @user"},
+		{&models.ReminderLog{}, "user_id = @user"},
+		// Friendships and invite codes
+		{&models.Friendship{}, "requester_id = @user OR addressee_id = @user"},
+		{&models.FriendInviteCode{}, "user_id = @user"},
+		// Contact discovery hashes
+		{&models.ContactHash{}, "user_id = @user"},
+		// Onboarding progress and survey answers
+		{&models.OnboardingProgress{}, "user_id = @user"},
+		{&models.SurveyResponse{}, "user_id = @user"},
+	} {
+		if err := tx.Where(d.where, user).Delete(d.model).Error; err != nil {
+			return err
+		}
+	}
+
+	// AI spend records are kept for accounting, detached from the user
+	if err := tx.Model(&models.AIUsage{}).Where("user_id = ?", userID).Update("user_id", nil).Error; err != nil {
+		return err
+	}
+
+	// Data exports, stored photos and their files
+	if err := DeleteUserExports(tx, userID); err != nil {
+		return err
+	}
+	if err := DeleteUserPhotos(tx, userID); err != nil {
+		return err
+	}
+
+	// Readings (and with them the image references), matches and streaks.
+	// Rows that point at a reading go before the readings, and readings
+	// before the groups and batches they belong to.
+	if tx.Migrator().HasTable("reading_embeddings") {
+		if err := tx.Exec("DELETE FROM reading_embeddings WHERE user_id = ?", userID).Error; err != nil {
+			return err
+		}
+	}
+	for _, d := range []struct {
+		model any
+		where string
+	}{
+		{&models.AuraMatch{}, "user_id = @user OR friend_id = @user"},
+		{&models.ReadingVersion{}, "user_id = @user"},
+		{&models.ReadingCorrection{}, "user_id = @user"},
+		{&models.ReadingTrait{}, "user_id = @user"},
+		{&models.AuraReading{}, "user_id = @user"},
+		{&models.GroupReading{}, "user_id = @user"},
+		{&models.ScanBatch{}, "user_id = @user"},
+		{&models.AuraStreak{}, "user_id = @user"},
+		{&models.AuraForecast{}, "user_id = @user"},
+		{&models.UserAggregate{}, "user_id = @user"},
+		{&models.RecurringTheme{}, "user_id = @user"},
+		{&models.ThemeAnalysis{}, "user_id = @user"},
+	} {
+		if err := tx.Where(d.where, user).Delete(d.model).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Where("id = ?", userID).Delete(&models.User{}).Error
+}
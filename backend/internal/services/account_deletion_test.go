@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testPostgres connects to the database in TEST_DATABASE_URL and applies the
+// migrations, so foreign keys match production. Tests using it are skipped
+// when the variable is unset.
+func testPostgres(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Migrate(context.Background(), db, "up"); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+var errRollback = errors.New("rollback")
+
+// seedRegeneratedReading stores a user, one reading of theirs made now and
+// the version its regeneration left behind, as Regenerate writes them.
+func seedRegeneratedReading(t *testing.T, tx *gorm.DB, userID, readingID uuid.UUID, deletedAt *time.Time) {
+	t.Helper()
+	now := time.Now()
+	for _, seed := range []struct {
+		sql  string
+		args []any
+	}{
+		{`INSERT INTO users (id, email, password, deleted_at, created_at, updated_at) VALUES (?, ?, '', ?, ?, ?)`,
+			[]any{userID, userID.String() + "@test.invalid", deletedAt, now, now}},
+		{`INSERT INTO aura_readings (id, user_id, image_url, aura_color, energy_level, mood_score, analyzed_at, created_at, updated_at) VALUES (?, ?, 'https://example.com/a.jpg', 'blue', 50, 5, ?, ?, ?)`,
+			[]any{readingID, userID, now, now, now}},
+		{`INSERT INTO reading_versions (reading_id, user_id, version, aura_color, analyzed_at, created_at) VALUES (?, ?, 1, 'red', ?, ?)`,
+			[]any{readingID, userID, now, now}},
+	} {
+		if err := tx.Exec(seed.sql, seed.args...).Error; err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+}
+
+func TestPurgeAccountWithRegeneratedReading(t *testing.T) {
+	db := testPostgres(t)
+	userID := uuid.New()
+	deactivated := time.Now()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		seedRegeneratedReading(t, tx, userID, uuid.New(), &deactivated)
+
+		if err := purgeAccount(tx, userID); err != nil {
+			t.Fatalf("purgeAccount: %v", err)
+		}
+		for _, model := range []any{&models.User{}, &models.AuraReading{}, &models.ReadingVersion{}} {
+			var n int64
+			column := "user_id"
+			if _, ok := model.(*models.User); ok {
+				column = "id"
+			}
+			tx.Unscoped().Model(model).Where(column+" = ?", userID).Count(&n)
+			if n != 0 {
+				t.Errorf("%T: %d rows left after the purge", model, n)
+			}
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatal(err)
+	}
+}
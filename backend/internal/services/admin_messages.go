@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/i18n"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidAdminMessage = errors.New("invalid message")
+	ErrTicketNotFound      = errors.New("ticket not found for this user")
+)
+
+const (
+	maxAdminMessageRunes = 4000
+	maxSupportTicketID   = 100
+)
+
+// SendMessage renders a message template for the user and delivers it to
+// their inbox, email and push, regardless of notification preferences. The
+// message is kept, threaded by its ticket, and the send is audited.
+func (s *AdminUserService) SendMessage(adminID, userID uuid.UUID, req *dto.AdminMessageRequest) (*models.AdminMessage, error) {
+	text := strings.TrimSpace(req.Message)
+	if text == "" || utf8.RuneCountInString(text) > maxAdminMessageRunes {
+		return nil, fmt.Errorf("%w: message must be 1-%d characters", ErrInvalidAdminMessage, maxAdminMessageRunes)
+	}
+	if !isActiveUser(s.db, userID) {
+		return nil, ErrUserNotFound
+	}
+	ticketType, ticketID, err := s.messageTicket(userID, req.TicketType, req.TicketID)
+	if err != nil {
+		return nil, err
+	}
+
+	locale := i18n.Resolve(req.Locale)
+	title, body, err := i18n.Render(locale, req.Template, struct{ Message string }{text})
+	if errors.Is(err, i18n.ErrUnknownTemplate) {
+		return nil, fmt.Errorf("%w: template must be one of %s", ErrInvalidAdminMessage, strings.Join(i18n.TemplateNames(), ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	msg := models.AdminMessage{
+		UserID:     userID,
+		AdminID:    adminID,
+		Template:   req.Template,
+		Locale:     locale,
+		Title:      title,
+		Body:       body,
+		TicketType: ticketType,
+		TicketID:   ticketID,
+		Channels:   []string{},
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&msg).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, &adminID, "user.messaged", auditTargetUser, userID, map[string]any{
+			"message_id": msg.ID, "template": msg.Template, "ticket_type": ticketType, "ticket_id": ticketID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.notifications == nil {
+		return &msg, nil
+	}
+	data := map[string]string{"type": "admin_message", "message_id": msg.ID.String(), "locale": locale}
+	if ticketType != "" {
+		data["ticket_type"], data["ticket_id"] = ticketType, ticketID
+	}
+	channels, err := s.notifications.Notify(userID, NotificationMessage{
+		Category: CategoryAccount,
+		Title:    title,
+		Body:     body,
+		Data:     data,
+	})
+	if err != nil {
+		// The message is kept and audited; the admin sees it went nowhere.
+		log.Printf("admin message %s to %s: %v", msg.ID, userID, err)
+	}
+	if len(channels) > 0 {
+		msg.Channels = channels
+		s.db.Model(&msg).Update("channels", msg.Channels)
+	}
+	return &msg, nil
+}
+
+// messageTicket validates the ticket a message answers. Reports, cases and
+// appeals must exist; reports and appeals must also be the user's own.
+func (s *AdminUserService) messageTicket(userID uuid.UUID, ticketType, ticketID string) (string, string, error) {
+	ticketType = strings.TrimSpace(ticketType)
+	ticketID = strings.TrimSpace(ticketID)
+	if ticketType == "" && ticketID == "" {
+		return "", "", nil
+	}
+	if ticketType == models.TicketSupport {
+		if ticketID == "" || len(ticketID) > maxSupportTicketID {
+			return "", "", fmt.Errorf("%w: support ticket_id must be 1-%d characters", ErrInvalidAdminMessage, maxSupportTicketID)
+		}
+		return ticketType, ticketID, nil
+	}
+
+	id, err := uuid.Parse(ticketID)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: ticket_id must be a UUID for %s tickets", ErrInvalidAdminMessage, ticketType)
+	}
+	var q *gorm.DB
+	switch ticketType {
+	case models.TicketReport:
+		q = s.db.Model(&models.Report{}).Where("id = ? AND reporter_id = ?", id, userID)
+	case models.TicketAppeal:
+		q = s.db.Model(&models.Appeal{}).Where("id = ? AND user_id = ?", id, userID)
+	case models.TicketModerationCase:
+		q = s.db.Model(&models.ModerationCase{}).Where("id = ?", id)
+	default:
+		return "", "", fmt.Errorf("%w: ticket_type must be support, report, moderation_case or appeal", ErrInvalidAdminMessage)
+	}
+	var n int64
+	if err := q.Count(&n).Error; err != nil {
+		return "", "", err
+	}
+	if n == 0 {
+		return "", "", ErrTicketNotFound
+	}
+	return ticketType, id.String(), nil
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMessageTicketValidation(t *testing.T) {
+	s := &AdminUserService{}
+	userID := uuid.New()
+
+	if typ, id, err := s.messageTicket(userID, "", ""); err != nil || typ != "" || id != "" {
+		t.Errorf("no ticket = %q %q %v", typ, id, err)
+	}
+	if typ, id, err := s.messageTicket(userID, "support", " ZD-4821 "); err != nil || typ != "support" || id != "ZD-4821" {
+		t.Errorf("support ticket = %q %q %v", typ, id, err)
+	}
+
+	bad := []struct{ typ, id string }{
+		{"support", ""},
+		{"support", strings.Repeat("x", maxSupportTicketID+1)},
+		{"report", "not-a-uuid"},
+		{"invoice", uuid.NewString()},
+		{"", uuid.NewString()},
+	}
+	for _, tc := range bad {
+		if _, _, err := s.messageTicket(userID, tc.typ, tc.id); !errors.Is(err, ErrInvalidAdminMessage) {
+			t.Errorf("messageTicket(%q, %q) err = %v, want ErrInvalidAdminMessage", tc.typ, tc.id, err)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// adminMetricsMaxDays bounds the overview window.
+const adminMetricsMaxDays = 90
+
+// AdminMetricsService computes the admin dashboard overview.
+type AdminMetricsService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewAdminMetricsService(db *gorm.DB, cfg *config.Config) *AdminMetricsService {
+	return &AdminMetricsService{db: db, cfg: cfg}
+}
+
+// parseScanCosts parses AI_SCAN_COSTS ("glm=0.002,deepseek=0.001"),
+// skipping malformed pairs.
+func parseScanCosts(s string) map[string]float64 {
+	costs := make(map[string]float64)
+	for _, pair := range splitList(s) {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		cost, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || cost < 0 {
+			continue
+		}
+		costs[strings.ToLower(strings.TrimSpace(name))] = cost
+	}
+	return costs
+}
+
+// Overview reports daily active users, scans, the AI fallback rate, an
+// estimate of provider spend and the tracked AI usage for the last days UTC
+// days (today included), plus subscription counts. Active users are those who scanned or refreshed
+// a session that day; every reading, deleted or not, counts as a scan.
+// The dashboard reads from a replica when one is configured.
+func (s *AdminMetricsService) Overview(days int) (*dto.AdminMetricsOverview, error) {
+	db := s.db.Scopes(database.Replica).Session(&gorm.Session{})
+	if days <= 0 {
+		days = 14
+	}
+	if days > adminMetricsMaxDays {
+		days = adminMetricsMaxDays
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(days - 1))
+
+	overview := &dto.AdminMetricsOverview{
+		Days:                days,
+		Daily:               make([]dto.AdminDailyMetrics, days),
+		ScansByProvider:     map[string]int64{},
+		ActiveSubscriptions: map[string]int64{},
+		AIUsage:             dto.AdminAIUsage{ByProvider: map[string]dto.AdminAIProviderUsage{}, DailyBudgetUSD: s.cfg.AIDailyBudgetUSD},
+	}
+	index := make(map[string]int, days)
+	for i := range overview.Daily {
+		day := since.AddDate(0, 0, i).Format("2006-01-02")
+		overview.Daily[i].Day = day
+		index[day] = i
+	}
+
+	var scanRows []struct {
+		Day      string
+		Provider string
+		Scans    int64
+	}
+	err := db.Table("aura_readings").
+		Select("to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, "+readingProviderExpr+" AS provider, COUNT(*) AS scans").
+		Where("created_at >= ? AND NOT synthetic", since).
+		Group("day, provider").
+		Scan(&scanRows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	costs := parseScanCosts(s.cfg.AIScanCosts)
+	var fallbacks int64
+	for _, r := range scanRows {
+		i, ok := index[r.Day]
+		if !ok {
+			continue
+		}
+		day := &overview.Daily[i]
+		day.Scans += r.Scans
+		if r.Provider == models.ReadingSourceDeterministic || r.Provider == models.ReadingSourceMock {
+			day.FallbackScans += r.Scans
+			fallbacks += r.Scans
+		}
+		spend := float64(r.Scans) * costs[r.Provider]
+		day.EstimatedSpendUSD += spend
+		overview.EstimatedSpendUSD += spend
+		overview.Scans += r.Scans
+		overview.ScansByProvider[r.Provider] += r.Scans
+	}
+
+	var dauRows []struct {
+		Day   string
+		Users int64
+	}
+	err = db.Raw(`SELECT to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, COUNT(DISTINCT user_id) AS users
+		FROM (
+			SELECT user_id, created_at FROM aura_readings WHERE created_at >= ? AND NOT synthetic
+			UNION ALL
+			SELECT user_id, created_at FROM refresh_tokens WHERE created_at >= ?
+		) activity
+		GROUP BY day`, since, since).
+		Scan(&dauRows).Error
+	if err != nil {
+		return nil, err
+	}
+	var activeDays int64
+	for _, r := range dauRows {
+		if i, ok := index[r.Day]; ok {
+			overview.Daily[i].ActiveUsers = r.Users
+			activeDays += r.Users
+		}
+	}
+	overview.AverageDAU = roundTo(float64(activeDays)/float64(days), 2)
+
+	for i := range overview.Daily {
+		day := &overview.Daily[i]
+		day.FallbackRate = ratio(day.FallbackScans, day.Scans)
+		day.EstimatedSpendUSD = roundTo(day.EstimatedSpendUSD, 4)
+	}
+	overview.FallbackRate = ratio(fallbacks, overview.Scans)
+	overview.EstimatedSpendUSD = roundTo(overview.EstimatedSpendUSD, 4)
+
+	if err := s.aiUsage(db, since, index, overview); err != nil {
+		return nil, err
+	}
+
+	var subRows []struct {
+		Source string
+		Count  int64
+	}
+	if err := db.Table("subscriptions").
+		Select("source, COUNT(*) AS count").
+		Where("status = ? AND current_period_end > ?", "active", time.Now()).
+		Group("source").
+		Scan(&subRows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range subRows {
+		overview.ActiveSubscriptions[r.Source] = r.Count
+	}
+	db.Table("subscriptions").Where("created_at >= ?", since).Count(&overview.NewSubscriptions)
+
+	return overview, nil
+}
+
+// aiUsage adds the tracked provider calls since since to the overview: per
+// day, per provider, and today's spend against the daily budget.
+func (s *AdminMetricsService) aiUsage(db *gorm.DB, since time.Time, index map[string]int, overview *dto.AdminMetricsOverview) error {
+	var rows []struct {
+		Day              string
+		Provider         string
+		Calls            int64
+		FailedCalls      int64
+		PromptTokens     int64
+		CompletionTokens int64
+		SpendUSD         float64
+		LatencyMs        int64
+	}
+	err := db.Table("ai_usage").
+		Select("to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, provider, COUNT(*) AS calls, "+
+			"COUNT(*) FILTER (WHERE outcome = 'error') AS failed_calls, SUM(prompt_tokens) AS prompt_tokens, "+
+			"SUM(completion_tokens) AS completion_tokens, SUM(cost_usd) AS spend_usd, SUM(latency_ms) AS latency_ms").
+		Where("created_at >= ?", since).
+		Group("day, provider").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	usage := &overview.AIUsage
+	latency := make(map[string]int64)
+	todayKey := overview.Daily[len(overview.Daily)-1].Day
+	for _, r := range rows {
+		i, ok := index[r.Day]
+		if !ok {
+			continue
+		}
+		overview.Daily[i].AISpendUSD += r.SpendUSD
+		if r.Day == todayKey {
+			usage.TodaySpendUSD += r.SpendUSD
+		}
+		usage.Calls += r.Calls
+		usage.FailedCalls += r.FailedCalls
+		usage.PromptTokens += r.PromptTokens
+		usage.CompletionTokens += r.CompletionTokens
+		usage.SpendUSD += r.SpendUSD
+
+		p := usage.ByProvider[r.Provider]
+		p.Calls += r.Calls
+		p.FailedCalls += r.FailedCalls
+		p.PromptTokens += r.PromptTokens
+		p.CompletionTokens += r.CompletionTokens
+		p.SpendUSD += r.SpendUSD
+		usage.ByProvider[r.Provider] = p
+		latency[r.Provider] += r.LatencyMs
+	}
+
+	for name, p := range usage.ByProvider {
+		p.SpendUSD = roundTo(p.SpendUSD, 4)
+		if p.Calls > 0 {
+			p.AvgLatencyMs = roundTo(float64(latency[name])/float64(p.Calls), 1)
+		}
+		usage.ByProvider[name] = p
+	}
+	for i := range overview.Daily {
+		overview.Daily[i].AISpendUSD = roundTo(overview.Daily[i].AISpendUSD, 4)
+	}
+	usage.BudgetExceeded = usage.DailyBudgetUSD > 0 && usage.TodaySpendUSD >= usage.DailyBudgetUSD
+	usage.SpendUSD = roundTo(usage.SpendUSD, 4)
+	usage.TodaySpendUSD = roundTo(usage.TodaySpendUSD, 4)
+	return nil
+}
+
+func ratio(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return roundTo(float64(part)/float64(total), 4)
+}
+
+func roundTo(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(v*scale) / scale
+}
@@ -0,0 +1,25 @@
+package services
+
+import "testing"
+
+func TestParseScanCosts(t *testing.T) {
+	costs := parseScanCosts(" GLM=0.002, deepseek = 0.001 ,openai,bad=x,neg=-1")
+	if len(costs) != 2 {
+		t.Fatalf("costs = %v, want glm and deepseek only", costs)
+	}
+	if costs["glm"] != 0.002 || costs["deepseek"] != 0.001 {
+		t.Errorf("costs = %v", costs)
+	}
+	if costs[provenanceDeterministic] != 0 {
+		t.Error("fallback readings should cost nothing")
+	}
+}
+
+func TestRatio(t *testing.T) {
+	if got := ratio(0, 0); got != 0 {
+		t.Errorf("ratio(0, 0) = %v", got)
+	}
+	if got := ratio(1, 3); got != 0.3333 {
+		t.Errorf("ratio(1, 3) = %v, want 0.3333", got)
+	}
+}
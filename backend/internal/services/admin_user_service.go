@@ -0,0 +1,245 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidGrant = errors.New("days must be between 1 and 365")
+
+// complimentarySource marks subscriptions granted by an admin rather than
+// bought through a billing provider.
+const complimentarySource = "complimentary"
+
+// AdminUserService backs the admin user view and account actions.
+type AdminUserService struct {
+	db            *gorm.DB
+	cfg           *config.Config
+	notifications *NotificationService
+}
+
+func NewAdminUserService(db *gorm.DB, cfg *config.Config, notifications *NotificationService) *AdminUserService {
+	return &AdminUserService{db: db, cfg: cfg, notifications: notifications}
+}
+
+type adminUserRow struct {
+	ID                string
+	Email             string
+	Handle            *string
+	Timezone          string
+	CreatedAt         time.Time
+	Subscribed        bool
+	SuppressionReason *string
+	SuppressedAt      *time.Time
+}
+
+// activeSubscriptionExpr is true when the user has a paid period running.
+const activeSubscriptionExpr = "EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = users.id AND s.status = 'active' AND s.current_period_end > NOW())"
+
+// guestEmailPattern matches guest accounts in SQL, as isGuestEmail does.
+const guestEmailPattern = `guest\_%@guest.local`
+
+// ListUsers returns users matching the filter, flagging addresses on the
+// suppression list.
+func (s *AdminUserService) ListUsers(filter dto.AdminUserFilter, limit, offset int) ([]dto.AdminUserResponse, int64, error) {
+	query := s.db.Table("users").
+		Joins("LEFT JOIN email_suppressions es ON es.email = LOWER(users.email)").
+		Where("users.deleted_at IS NULL")
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		query = query.Where("users.email ILIKE ? OR users.handle ILIKE ?", pattern, pattern)
+	}
+	if filter.InvalidOnly {
+		query = query.Where("es.id IS NOT NULL")
+	}
+	switch filter.Type {
+	case "guest":
+		query = query.Where("LOWER(users.email) LIKE ?", guestEmailPattern)
+	case "registered":
+		query = query.Where("LOWER(users.email) NOT LIKE ?", guestEmailPattern)
+	}
+	if filter.Subscribed != nil {
+		if *filter.Subscribed {
+			query = query.Where(activeSubscriptionExpr)
+		} else {
+			query = query.Where("NOT " + activeSubscriptionExpr)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []adminUserRow
+	err := query.
+		Select("users.id, users.email, users.handle, users.timezone, users.created_at, " +
+			activeSubscriptionExpr + " AS subscribed, es.reason AS suppression_reason, es.updated_at AS suppressed_at").
+		Order("users.created_at DESC").
+		Limit(limit).Offset(offset).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users := make([]dto.AdminUserResponse, 0, len(rows))
+	for _, r := range rows {
+		users = append(users, r.response())
+	}
+	return users, total, nil
+}
+
+func (r adminUserRow) response() dto.AdminUserResponse {
+	u := dto.AdminUserResponse{
+		ID:             r.ID,
+		Email:          r.Email,
+		IsGuest:        isGuestEmail(r.Email),
+		Subscribed:     r.Subscribed,
+		Timezone:       r.Timezone,
+		CreatedAt:      r.CreatedAt,
+		EmailInvalid:   r.SuppressionReason != nil,
+		EmailFlaggedAt: r.SuppressedAt,
+	}
+	if r.Handle != nil {
+		u.Handle = *r.Handle
+	}
+	if r.SuppressionReason != nil {
+		u.EmailStatusReason = *r.SuppressionReason
+	}
+	return u
+}
+
+// GetUser returns the admin detail of one user, including deactivated
+// accounts awaiting purge.
+func (s *AdminUserService) GetUser(userID uuid.UUID) (*dto.AdminUserDetail, error) {
+	var user models.User
+	if err := s.db.Unscoped().First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	row := adminUserRow{
+		ID:        user.ID.String(),
+		Email:     user.Email,
+		Handle:    user.Handle,
+		Timezone:  user.Timezone,
+		CreatedAt: user.CreatedAt,
+	}
+	var suppression models.EmailSuppression
+	if err := s.db.Where("email = ?", normalizeEmail(user.Email)).First(&suppression).Error; err == nil {
+		row.SuppressionReason = &suppression.Reason
+		row.SuppressedAt = &suppression.UpdatedAt
+	}
+
+	ent := entitlementsFor(s.db, s.cfg, userID)
+	row.Subscribed = ent.Tier != TierFree
+	detail := &dto.AdminUserDetail{
+		AdminUserResponse: row.response(),
+		EmailVerified:     user.EmailVerifiedAt != nil,
+		Deactivated:       user.DeletedAt.Valid,
+		Tier:              ent.Tier,
+		Subscriptions:     []dto.AdminSubscriptionRow{},
+	}
+
+	s.db.Model(&models.AuraReading{}).Scopes(personalReadings).Where("user_id = ?", userID).Count(&detail.ReadingsCount)
+	var last models.AuraReading
+	if err := s.db.Scopes(personalReadings).Where("user_id = ?", userID).Order("created_at DESC").First(&last).Error; err == nil {
+		detail.LastReadingAt = &last.CreatedAt
+	}
+
+	s.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked = ? AND expires_at > ?", userID, false, time.Now()).
+		Count(&detail.ActiveSessions)
+	var bans int64
+	s.db.Model(&models.ModerationAction{}).Scopes(activeActions).
+		Where("user_id = ? AND type = ?", userID, models.ActionBan).
+		Count(&bans)
+	detail.Banned = bans > 0
+
+	var subs []models.Subscription
+	s.db.Where("user_id = ?", userID).Order("current_period_end DESC").Find(&subs)
+	for _, sub := range subs {
+		detail.Subscriptions = append(detail.Subscriptions, dto.AdminSubscriptionRow{
+			ID:               sub.ID.String(),
+			Source:           sub.Source,
+			ProductID:        sub.ProductID,
+			Status:           sub.Status,
+			CurrentPeriodEnd: sub.CurrentPeriodEnd,
+		})
+	}
+	return detail, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// ForceLogout signs the user out of every session, including access tokens
+// that have not expired yet.
+func (s *AdminUserService) ForceLogout(adminID, userID uuid.UUID) error {
+	if !isActiveUser(s.db, userID) {
+		return ErrUserNotFound
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := signOutEverywhere(tx, userID, models.RevokeReasonAdmin); err != nil {
+			return err
+		}
+		return writeAudit(tx, &adminID, "user.logged_out", auditTargetUser, userID, nil)
+	})
+}
+
+// GrantPremium gives the user complimentary premium for req.Days days,
+// recorded as a subscription so entitlements pick it up like a paid one.
+func (s *AdminUserService) GrantPremium(adminID, userID uuid.UUID, req *dto.GrantPremiumRequest) (*models.Subscription, error) {
+	if req.Days < 1 || req.Days > 365 {
+		return nil, ErrInvalidGrant
+	}
+	if !isActiveUser(s.db, userID) {
+		return nil, ErrUserNotFound
+	}
+
+	now := time.Now()
+	sub := models.Subscription{
+		UserID:             &userID,
+		Source:             complimentarySource,
+		ProductID:          complimentarySource,
+		Status:             "active",
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   now.AddDate(0, 0, req.Days),
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&sub).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, &adminID, "user.premium_granted", auditTargetUser, userID, map[string]any{
+			"subscription_id": sub.ID, "days": req.Days, "reason": truncateRunes(strings.TrimSpace(req.Reason), 500),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// ResetQuota gives the user a fresh daily scan and match allowance from now
+// until the end of their day.
+func (s *AdminUserService) ResetQuota(adminID, userID uuid.UUID) error {
+	if !isActiveUser(s.db, userID) {
+		return ErrUserNotFound
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("quota_reset_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, &adminID, "user.quota_reset", auditTargetUser, userID, nil)
+	})
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	aiHistoryDefaultPageSize = 50
+	aiHistoryMaxPageSize     = 200
+)
+
+type aiUserKey struct{}
+
+type aiUser struct {
+	db     *gorm.DB
+	userID uuid.UUID
+}
+
+// withAIUser attributes the AI calls made under ctx to userID, so they show
+// up in the user's AI history. Calls made without it (admin previews) are
+// not recorded.
+func withAIUser(ctx context.Context, db *gorm.DB, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, aiUserKey{}, aiUser{db: db, userID: userID})
+}
+
+// recordAIInteraction logs one provider call for the user attributed to ctx.
+// A failed write is logged rather than failing the call it describes.
+func recordAIInteraction(ctx context.Context, purpose, provider, model string, callErr error, started time.Time) {
+	u, ok := ctx.Value(aiUserKey{}).(aiUser)
+	if !ok || u.db == nil {
+		return
+	}
+	outcome := "success"
+	if callErr != nil {
+		outcome = "error"
+	}
+	interaction := models.AIInteraction{
+		UserID:     u.userID,
+		Purpose:    purpose,
+		Provider:   provider,
+		Model:      model,
+		Outcome:    outcome,
+		DurationMs: int(time.Since(started).Milliseconds()),
+	}
+	// The record outlives a request cancelled mid-call.
+	if err := u.db.WithContext(context.WithoutCancel(ctx)).Create(&interaction).Error; err != nil {
+		log.Printf("record ai interaction for user %s: %v", u.userID, err)
+	}
+}
+
+// AIHistoryService lists the AI calls made on a user's behalf.
+type AIHistoryService struct {
+	db *gorm.DB
+}
+
+func NewAIHistoryService(db *gorm.DB) *AIHistoryService {
+	return &AIHistoryService{db: db}
+}
+
+// List returns the user's AI interactions, newest first.
+func (s *AIHistoryService) List(userID uuid.UUID, page, pageSize int) (*dto.AIHistoryResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = aiHistoryDefaultPageSize
+	}
+	if pageSize > aiHistoryMaxPageSize {
+		pageSize = aiHistoryMaxPageSize
+	}
+
+	resp := &dto.AIHistoryResponse{Data: []models.AIInteraction{}, Page: page, PageSize: pageSize}
+	q := s.db.Scopes(database.Replica).Model(&models.AIInteraction{}).Where("user_id = ?", userID)
+	if err := q.Session(&gorm.Session{}).Count(&resp.TotalCount).Error; err != nil {
+		return nil, err
+	}
+	if err := q.Session(&gorm.Session{}).Order("created_at DESC").Limit(pageSize).Offset((page - 1) * pageSize).Find(&resp.Data).Error; err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
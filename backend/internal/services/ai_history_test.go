@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestWithAIUserAttributesCalls(t *testing.T) {
+	userID := uuid.New()
+	ctx := withAIUser(context.Background(), nil, userID)
+	u, ok := ctx.Value(aiUserKey{}).(aiUser)
+	if !ok || u.userID != userID {
+		t.Fatalf("ai user = %+v, %v", u, ok)
+	}
+
+	// Without a database, and without an attributed user, nothing is written.
+	recordAIInteraction(ctx, models.AIPurposeAuraScan, "openai", "gpt-4o-mini", errors.New("timeout"), time.Now())
+	recordAIInteraction(context.Background(), models.AIPurposeAuraScan, "openai", "gpt-4o-mini", nil, time.Now())
+}
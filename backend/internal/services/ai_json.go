@@ -0,0 +1,109 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var errNoJSONObject = errors.New("no json object found in ai response")
+
+// extractJSONObject pulls the first complete JSON object out of an AI completion.
+// Models often ignore "return JSON only" and wrap the payload in ```json fences,
+// prepend prose ("Here is the analysis:") or append commentary after the object.
+// Fenced blocks are preferred when present; otherwise the raw text is scanned.
+func extractJSONObject(content string) (string, error) {
+	content = strings.TrimSpace(strings.TrimPrefix(content, "\ufeff"))
+	if content == "" {
+		return "", errNoJSONObject
+	}
+
+	for _, block := range fencedBlocks(content) {
+		if obj, ok := scanJSONObject(block); ok {
+			return obj, nil
+		}
+	}
+
+	if obj, ok := scanJSONObject(content); ok {
+		return obj, nil
+	}
+	return "", errNoJSONObject
+}
+
+// fencedBlocks returns the bodies of all ``` fenced blocks, with any language
+// tag (```json, ```JSON, ```javascript) removed from the opening line.
+func fencedBlocks(content string) []string {
+	var blocks []string
+	rest := content
+	for {
+		start := strings.Index(rest, "```")
+		if start < 0 {
+			return blocks
+		}
+		rest = rest[start+3:]
+
+		// Drop the language tag on the opening fence line.
+		if nl := strings.IndexByte(rest, '\n'); nl >= 0 && !strings.Contains(rest[:nl], "{") {
+			rest = rest[nl+1:]
+		}
+
+		end := strings.Index(rest, "```")
+		if end < 0 {
+			// Unterminated fence (truncated output): keep what we have.
+			blocks = append(blocks, rest)
+			return blocks
+		}
+		blocks = append(blocks, rest[:end])
+		rest = rest[end+3:]
+	}
+}
+
+// scanJSONObject finds the first '{' that opens a valid JSON object and walks
+// forward to its matching '}', ignoring braces inside string literals. Stray
+// braces in leading prose ("use {curly} quotes") are skipped.
+func scanJSONObject(text string) (string, bool) {
+	for offset := 0; offset < len(text); {
+		start := strings.IndexByte(text[offset:], '{')
+		if start < 0 {
+			return "", false
+		}
+		start += offset
+
+		depth := 0
+		inString := false
+		escaped := false
+		for i := start; i < len(text); i++ {
+			ch := text[i]
+			if inString {
+				switch {
+				case escaped:
+					escaped = false
+				case ch == '\\':
+					escaped = true
+				case ch == '"':
+					inString = false
+				}
+				continue
+			}
+
+			switch ch {
+			case '"':
+				inString = true
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					candidate := text[start : i+1]
+					if json.Valid([]byte(candidate)) {
+						return candidate, true
+					}
+					i = len(text)
+				}
+			}
+		}
+
+		offset = start + 1
+	}
+	return "", false
+}
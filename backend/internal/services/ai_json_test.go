@@ -0,0 +1,65 @@
+package services
+
+import "testing"
+
+func TestExtractJSONObjectVariants(t *testing.T) {
+	want := `{"aura_color":"blue","energy_level":80}`
+
+	cases := map[string]string{
+		"plain":           want,
+		"json fence":      "```json\n" + want + "\n```",
+		"bare fence":      "```\n" + want + "\n```",
+		"upper tag fence": "```JSON\n" + want + "\n```",
+		"leading prose":   "Here is the aura analysis you asked for:\n" + want,
+		"trailing text":   want + "\n\nLet me know if you need anything else!",
+		"prose and fence": "Sure! Here you go:\n```json\n" + want + "\n```\nThe blue aura suggests calm.",
+		"stray braces":    "Note: use {curly} keys.\n" + want,
+		"bom":             "\ufeff" + want,
+	}
+
+	for name, content := range cases {
+		got, err := extractJSONObject(content)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if got != want {
+			t.Fatalf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestExtractJSONObjectBracesInStrings(t *testing.T) {
+	want := `{"advice":"Keep {balance} and \"calm}\"","aura_color":"green"}`
+	got, err := extractJSONObject("Result: " + want + " done")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestExtractJSONObjectNoObject(t *testing.T) {
+	for _, content := range []string{"", "   ", "I cannot analyze this image.", "```json\n```", `{"aura_color": "blue"`} {
+		if _, err := extractJSONObject(content); err == nil {
+			t.Fatalf("expected error for %q", content)
+		}
+	}
+}
+
+func TestParseAuraAIContentFenced(t *testing.T) {
+	content := "Here is the analysis:\n```json\n{\"aura_color\":\"Indigo\",\"secondary_color\":null,\"energy_level\":64,\"mood_score\":6}\n```\nHope this helps."
+	parsed, err := parseAuraAIContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.AuraColor != "indigo" {
+		t.Fatalf("expected indigo, got %s", parsed.AuraColor)
+	}
+	if parsed.SecondaryColor != nil {
+		t.Fatalf("expected nil secondary color, got %v", *parsed.SecondaryColor)
+	}
+	if parsed.EnergyLevel != 64 || parsed.MoodScore != 6 {
+		t.Fatalf("unexpected scores: %d/%d", parsed.EnergyLevel, parsed.MoodScore)
+	}
+}
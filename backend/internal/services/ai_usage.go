@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// errAIBudgetExceeded skips a provider call once the day's AI spend has
+// reached AI_DAILY_BUDGET_USD; callers fall back as if the AI failed.
+var errAIBudgetExceeded = errors.New("daily ai budget exceeded")
+
+// aiSpendRefresh is how long the day's spend is trusted before it is summed
+// again, so other instances' calls count toward the budget too.
+const aiSpendRefresh = 30 * time.Second
+
+// aiTokenUsage is the usage block of an OpenAI-compatible completion.
+type aiTokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// aiTokenPrice is USD per million tokens.
+type aiTokenPrice struct {
+	input  float64
+	output float64
+}
+
+type aiTokensKey struct{}
+
+// withAITokenCounter returns a context whose provider calls add the tokens
+// they report to the returned counter.
+func withAITokenCounter(ctx context.Context) (context.Context, *aiTokenUsage) {
+	counter := &aiTokenUsage{}
+	return context.WithValue(ctx, aiTokensKey{}, counter), counter
+}
+
+// countAITokens adds a completion's usage to the counter in ctx, if any.
+func countAITokens(ctx context.Context, usage aiTokenUsage) {
+	if counter, ok := ctx.Value(aiTokensKey{}).(*aiTokenUsage); ok {
+		counter.PromptTokens += usage.PromptTokens
+		counter.CompletionTokens += usage.CompletionTokens
+	}
+}
+
+// parseTokenPrices parses AI_TOKEN_PRICES ("gpt-4o-mini=0.15/0.60"),
+// skipping malformed pairs.
+func parseTokenPrices(s string) map[string]aiTokenPrice {
+	prices := make(map[string]aiTokenPrice)
+	for _, pair := range splitList(s) {
+		model, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		in, out, ok := strings.Cut(value, "/")
+		if !ok {
+			continue
+		}
+		input, err1 := strconv.ParseFloat(strings.TrimSpace(in), 64)
+		output, err2 := strconv.ParseFloat(strings.TrimSpace(out), 64)
+		if err1 != nil || err2 != nil || input < 0 || output < 0 {
+			continue
+		}
+		prices[strings.ToLower(strings.TrimSpace(model))] = aiTokenPrice{input: input, output: output}
+	}
+	return prices
+}
+
+// AIUsageService records the tokens and cost of AI provider calls and
+// enforces the daily AI budget. A nil *AIUsageService records nothing and
+// never runs out of budget.
+type AIUsageService struct {
+	db     *gorm.DB
+	prices map[string]aiTokenPrice
+	budget float64
+
+	mu        sync.Mutex
+	day       time.Time
+	spent     float64
+	checkedAt time.Time
+	// refreshing is set while a caller sums today's spend.
+	refreshing bool
+	exceeded   bool
+}
+
+func NewAIUsageService(db *gorm.DB, cfg *config.Config) *AIUsageService {
+	return &AIUsageService{db: db, prices: parseTokenPrices(cfg.AITokenPrices), budget: cfg.AIDailyBudgetUSD}
+}
+
+// cost prices a call's tokens. Unpriced models cost nothing.
+func (s *AIUsageService) cost(model string, usage aiTokenUsage) float64 {
+	price := s.prices[strings.ToLower(model)]
+	return (float64(usage.PromptTokens)*price.input + float64(usage.CompletionTokens)*price.output) / 1e6
+}
+
+// Record stores one provider call, attributed to the user in ctx when there
+// is one (see withAIUser). A failed write is logged rather than failing the
+// call it describes.
+func (s *AIUsageService) Record(ctx context.Context, purpose, provider, model string, usage aiTokenUsage, callErr error, started time.Time) {
+	if s == nil {
+		return
+	}
+	outcome := "success"
+	if callErr != nil {
+		outcome = "error"
+	}
+	record := models.AIUsage{
+		Purpose:          purpose,
+		Provider:         provider,
+		Model:            model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		CostUSD:          s.cost(model, usage),
+		LatencyMs:        int(time.Since(started).Milliseconds()),
+		Outcome:          outcome,
+	}
+	if u, ok := ctx.Value(aiUserKey{}).(aiUser); ok {
+		record.UserID = &u.userID
+	}
+
+	metrics.AITokensTotal.WithLabelValues(provider, "prompt").Add(float64(usage.PromptTokens))
+	metrics.AITokensTotal.WithLabelValues(provider, "completion").Add(float64(usage.CompletionTokens))
+	metrics.AISpendUSDTotal.WithLabelValues(provider).Add(record.CostUSD)
+
+	s.mu.Lock()
+	if s.day.Equal(aiBudgetDay()) {
+		s.spent += record.CostUSD
+	}
+	s.mu.Unlock()
+
+	// The record outlives a request cancelled mid-call.
+	if err := s.db.WithContext(context.WithoutCancel(ctx)).Create(&record).Error; err != nil {
+		log.Printf("record ai usage (%s %s): %v", provider, purpose, err)
+	}
+}
+
+// OverBudget reports whether today's AI spend has reached the daily budget.
+// When the spend cannot be read the AI stays on.
+func (s *AIUsageService) OverBudget(ctx context.Context) bool {
+	if s == nil || s.budget <= 0 {
+		return false
+	}
+	if err := s.refreshSpend(ctx); err != nil {
+		log.Printf("ai budget: read today's spend: %v", err)
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	over := s.spent >= s.budget
+	if over && !s.exceeded {
+		log.Printf("ai budget: spent $%.2f of $%.2f today, using the fallback until midnight UTC", s.spent, s.budget)
+	}
+	s.exceeded = over
+	return over
+}
+
+// SpentToday is today's AI spend, for the admin overview.
+func (s *AIUsageService) SpentToday(ctx context.Context) (float64, error) {
+	if s == nil {
+		return 0, nil
+	}
+	if err := s.refreshSpend(ctx); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.spent, nil
+}
+
+// refreshSpend sums today's spend when the last sum is from another day or
+// older than aiSpendRefresh. The sum runs without s.mu held, so scans are
+// not held up behind it; while one caller refreshes, the others use the
+// cached sum if it is from today.
+func (s *AIUsageService) refreshSpend(ctx context.Context) error {
+	day := aiBudgetDay()
+	s.mu.Lock()
+	fresh := s.day.Equal(day) && (s.refreshing || time.Since(s.checkedAt) < aiSpendRefresh)
+	if !fresh {
+		s.refreshing = true
+	}
+	s.mu.Unlock()
+	if fresh {
+		return nil
+	}
+
+	var spent float64
+	err := s.db.WithContext(ctx).Model(&models.AIUsage{}).
+		Select("COALESCE(SUM(cost_usd), 0)").
+		Where("created_at >= ?", day).
+		Scan(&spent).Error
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshing = false
+	if err != nil {
+		return err
+	}
+	if !s.day.Equal(day) {
+		s.exceeded = false
+	}
+	s.day, s.spent, s.checkedAt = day, spent, time.Now()
+	return nil
+}
+
+// scanFallbackReason labels why a scan fell back to the deterministic
+// engine, for metrics.
+func scanFallbackReason(err error) string {
+	switch {
+	case errors.Is(err, errAuraAIDisabled):
+		return "ai_disabled"
+	case errors.Is(err, errAIBudgetExceeded):
+		return "ai_budget"
+	case errors.Is(err, errAuraMockMode):
+		return "mock_mode"
+	default:
+		return "ai_error"
+	}
+}
+
+// scanSource labels a stored reading with who wrote it, given the source
+// of the AI result and the error analysis returned. With no AI configured
+// the deterministic engine wrote it by design; any other fallback, mock
+// mode included, is a mock standing in for the AI. latencyMs is the time spent on provider calls
+// since started, failed attempts included, when any were made.
+func scanSource(aiSource string, err error, started time.Time) (source string, latencyMs *int) {
+	switch {
+	case errors.Is(err, errAuraAIDisabled):
+		return models.ReadingSourceDeterministic, nil
+	case errors.Is(err, errAIBudgetExceeded), errors.Is(err, errAuraMockMode):
+		return models.ReadingSourceMock, nil
+	case err != nil:
+		source = models.ReadingSourceMock
+	default:
+		source = aiSource
+	}
+	ms := int(time.Since(started).Milliseconds())
+	return source, &ms
+}
+
+// aiBudgetDay is the start of the UTC day the budget applies to.
+func aiBudgetDay() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
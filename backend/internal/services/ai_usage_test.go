@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestParseTokenPrices(t *testing.T) {
+	prices := parseTokenPrices(" GPT-4o-mini = 0.15/0.60, glm-4-flash=0/0,deepseek-chat=0.27,bad=x/1,neg=-1/2")
+	if len(prices) != 2 {
+		t.Fatalf("prices = %v, want gpt-4o-mini and glm-4-flash only", prices)
+	}
+	if p := prices["gpt-4o-mini"]; p.input != 0.15 || p.output != 0.60 {
+		t.Errorf("gpt-4o-mini = %+v", p)
+	}
+}
+
+func TestAIUsageCost(t *testing.T) {
+	s := NewAIUsageService(nil, &config.Config{AITokenPrices: "gpt-4o-mini=0.15/0.60"})
+	got := s.cost("gpt-4o-mini", aiTokenUsage{PromptTokens: 1000, CompletionTokens: 500})
+	if want := (1000*0.15 + 500*0.60) / 1e6; math.Abs(got-want) > 1e-12 {
+		t.Errorf("cost = %v, want %v", got, want)
+	}
+	if got := s.cost("unpriced-model", aiTokenUsage{PromptTokens: 1000}); got != 0 {
+		t.Errorf("unpriced model cost %v", got)
+	}
+}
+
+func TestAITokenCounterSumsCalls(t *testing.T) {
+	ctx, counter := withAITokenCounter(context.Background())
+	countAITokens(ctx, aiTokenUsage{PromptTokens: 100, CompletionTokens: 20})
+	// A retry in the same provider call adds to the same counter.
+	countAITokens(ctx, aiTokenUsage{PromptTokens: 90, CompletionTokens: 10})
+	if *counter != (aiTokenUsage{PromptTokens: 190, CompletionTokens: 30}) {
+		t.Errorf("counter = %+v", *counter)
+	}
+	countAITokens(context.Background(), aiTokenUsage{PromptTokens: 1}) // no counter: ignored
+}
+
+func TestAIBudget(t *testing.T) {
+	var unset *AIUsageService
+	if unset.OverBudget(context.Background()) {
+		t.Error("a nil usage service ran out of budget")
+	}
+	unset.Record(context.Background(), "aura_scan", "openai", "gpt-4o-mini", aiTokenUsage{}, nil, time.Now())
+
+	if NewAIUsageService(nil, &config.Config{}).OverBudget(context.Background()) {
+		t.Error("no budget configured, yet over budget")
+	}
+
+	s := NewAIUsageService(nil, &config.Config{AIDailyBudgetUSD: 5})
+	s.day, s.checkedAt = aiBudgetDay(), time.Now()
+	s.spent = 4.99
+	if s.OverBudget(context.Background()) {
+		t.Error("over budget at $4.99 of $5")
+	}
+	s.spent = 5
+	if !s.OverBudget(context.Background()) {
+		t.Error("not over budget at $5 of $5")
+	}
+}
+
+func TestScanFallbackReason(t *testing.T) {
+	cases := map[error]string{
+		errAuraAIDisabled: "ai_disabled",
+		fmt.Errorf("analyze: %w", errAIBudgetExceeded):   "ai_budget",
+		errors.New("openai provider failed: status=500"): "ai_error",
+	}
+	for err, want := range cases {
+		if got := scanFallbackReason(err); got != want {
+			t.Errorf("scanFallbackReason(%v) = %s, want %s", err, got, want)
+		}
+	}
+}
+
+func TestScanSource(t *testing.T) {
+	started := time.Now().Add(-1500 * time.Millisecond)
+	cases := []struct {
+		name        string
+		err         error
+		want        string
+		wantLatency bool
+	}{
+		{"answered", nil, "openai", true},
+		{"ai disabled", errAuraAIDisabled, models.ReadingSourceDeterministic, false},
+		{"over budget", errAIBudgetExceeded, models.ReadingSourceMock, false},
+		{"provider failed", errors.New("glm provider failed: timeout"), models.ReadingSourceMock, true},
+	}
+	for _, tc := range cases {
+		source, latency := scanSource("openai", tc.err, started)
+		if source != tc.want {
+			t.Errorf("%s: source = %q, want %q", tc.name, source, tc.want)
+		}
+		if (latency != nil) != tc.wantLatency {
+			t.Errorf("%s: latency = %v, want set: %v", tc.name, latency, tc.wantLatency)
+		} else if latency != nil && *latency < 1500 {
+			t.Errorf("%s: latency = %dms, want at least 1500", tc.name, *latency)
+		}
+	}
+}
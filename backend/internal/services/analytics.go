@@ -0,0 +1,152 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/google/uuid"
+)
+
+// Product events. Names and properties are the same for every sink, so
+// dashboards survive a change of vendor.
+const (
+	// EventScanCompleted: a personal reading finished (reading_id, aura_color,
+	// ai, async).
+	EventScanCompleted = "scan_completed"
+	// EventPaywallHit: the user ran into a plan limit (feature).
+	EventPaywallHit = "paywall_hit"
+	// EventStreakBroken: a scan restarted a lapsed streak (previous_streak).
+	EventStreakBroken = "streak_broken"
+)
+
+const (
+	analyticsQueueSize   = 1024
+	analyticsSendTimeout = 10 * time.Second
+)
+
+// AnalyticsEvent is one product event about one user.
+type AnalyticsEvent struct {
+	Name       string
+	UserID     uuid.UUID
+	Properties map[string]any
+	Time       time.Time
+}
+
+// AnalyticsSink forwards events to a product analytics vendor.
+type AnalyticsSink interface {
+	Name() string
+	Track(ctx context.Context, event AnalyticsEvent) error
+}
+
+// NewAnalyticsSink picks the sink from ANALYTICS_DRIVER ("posthog" or
+// "amplitude"). Without a configured driver events are dropped.
+func NewAnalyticsSink(cfg *config.Config) (AnalyticsSink, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.AnalyticsDriver)) {
+	case "posthog":
+		return newPostHogSink(cfg)
+	case "amplitude":
+		return newAmplitudeSink(cfg)
+	case "", "none":
+		return noopAnalyticsSink{}, nil
+	default:
+		return nil, fmt.Errorf("unknown ANALYTICS_DRIVER %q", cfg.AnalyticsDriver)
+	}
+}
+
+type noopAnalyticsSink struct{}
+
+func (noopAnalyticsSink) Name() string { return "none" }
+
+func (noopAnalyticsSink) Track(context.Context, AnalyticsEvent) error { return nil }
+
+// Analytics queues events from request paths and delivers them to the sink
+// from RunAnalyticsWorker, so a slow vendor never delays a scan. A nil
+// *Analytics drops events.
+type Analytics struct {
+	sink  AnalyticsSink
+	queue chan AnalyticsEvent
+}
+
+func NewAnalytics(sink AnalyticsSink) *Analytics {
+	return &Analytics{sink: sink, queue: make(chan AnalyticsEvent, analyticsQueueSize)}
+}
+
+// Emit queues an event. When the queue is full the event is dropped rather
+// than blocking the caller.
+func (a *Analytics) Emit(userID uuid.UUID, name string, properties map[string]any) {
+	if a == nil {
+		return
+	}
+	if _, ok := a.sink.(noopAnalyticsSink); ok {
+		return
+	}
+	select {
+	case a.queue <- AnalyticsEvent{Name: name, UserID: userID, Properties: properties, Time: time.Now().UTC()}:
+	default:
+		metrics.AnalyticsEventsTotal.WithLabelValues(name, "dropped").Inc()
+	}
+}
+
+// RunAnalyticsWorker delivers queued events until ctx is cancelled.
+func (a *Analytics) RunAnalyticsWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-a.queue:
+			a.deliver(ctx, event)
+		}
+	}
+}
+
+func (a *Analytics) deliver(ctx context.Context, event AnalyticsEvent) {
+	ctx, cancel := context.WithTimeout(ctx, analyticsSendTimeout)
+	defer cancel()
+	if err := a.sink.Track(ctx, event); err != nil {
+		log.Printf("analytics(%s): %s: %v", a.sink.Name(), event.Name, err)
+		metrics.AnalyticsEventsTotal.WithLabelValues(event.Name, "failed").Inc()
+		return
+	}
+	metrics.AnalyticsEventsTotal.WithLabelValues(event.Name, "sent").Inc()
+}
+
+// scanCompletedProperties describes a finished reading; ai reports whether
+// any field came from an AI provider rather than the deterministic engine.
+func scanCompletedProperties(readingID uuid.UUID, color string, provenance map[string]string, async bool) map[string]any {
+	ai := false
+	for _, source := range provenance {
+		if source != provenanceDeterministic {
+			ai = true
+			break
+		}
+	}
+	return map[string]any{"reading_id": readingID.String(), "aura_color": color, "ai": ai, "async": async}
+}
+
+// postAnalytics sends a JSON event payload to a vendor endpoint.
+func postAnalytics(ctx context.Context, client *http.Client, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/google/uuid"
+)
+
+const amplitudeDefaultEndpoint = "https://api2.amplitude.com/2/httpapi"
+
+// amplitudeSink sends events through the Amplitude HTTP V2 API.
+type amplitudeSink struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func newAmplitudeSink(cfg *config.Config) (*amplitudeSink, error) {
+	if cfg.AmplitudeAPIKey == "" {
+		return nil, errors.New("AMPLITUDE_API_KEY is required")
+	}
+	endpoint := cfg.AmplitudeEndpoint
+	if endpoint == "" {
+		endpoint = amplitudeDefaultEndpoint
+	}
+	return &amplitudeSink{
+		url:    endpoint,
+		apiKey: cfg.AmplitudeAPIKey,
+		client: &http.Client{Timeout: analyticsSendTimeout},
+	}, nil
+}
+
+func (s *amplitudeSink) Name() string { return "amplitude" }
+
+func (s *amplitudeSink) Track(ctx context.Context, event AnalyticsEvent) error {
+	body, err := amplitudePayload(s.apiKey, event)
+	if err != nil {
+		return err
+	}
+	return postAnalytics(ctx, s.client, s.url, body)
+}
+
+// amplitudePayload sets insert_id so Amplitude drops a retried duplicate.
+func amplitudePayload(apiKey string, event AnalyticsEvent) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"api_key": apiKey,
+		"events": []map[string]any{{
+			"user_id":          event.UserID.String(),
+			"event_type":       event.Name,
+			"time":             event.Time.UnixMilli(),
+			"event_properties": event.Properties,
+			"insert_id":        uuid.NewString(),
+		}},
+	})
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+)
+
+const postHogDefaultHost = "https://us.i.posthog.com"
+
+// postHogSink sends events through the PostHog capture API.
+type postHogSink struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func newPostHogSink(cfg *config.Config) (*postHogSink, error) {
+	if cfg.PostHogAPIKey == "" {
+		return nil, errors.New("POSTHOG_API_KEY is required")
+	}
+	host := strings.TrimRight(cfg.PostHogHost, "/")
+	if host == "" {
+		host = postHogDefaultHost
+	}
+	return &postHogSink{
+		url:    host + "/capture/",
+		apiKey: cfg.PostHogAPIKey,
+		client: &http.Client{Timeout: analyticsSendTimeout},
+	}, nil
+}
+
+func (s *postHogSink) Name() string { return "posthog" }
+
+func (s *postHogSink) Track(ctx context.Context, event AnalyticsEvent) error {
+	body, err := postHogPayload(s.apiKey, event)
+	if err != nil {
+		return err
+	}
+	return postAnalytics(ctx, s.client, s.url, body)
+}
+
+func postHogPayload(apiKey string, event AnalyticsEvent) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"api_key":     apiKey,
+		"event":       event.Name,
+		"distinct_id": event.UserID.String(),
+		"properties":  event.Properties,
+		"timestamp":   event.Time,
+	})
+}
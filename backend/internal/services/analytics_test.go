@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/google/uuid"
+)
+
+func TestNewAnalyticsSink(t *testing.T) {
+	cases := []struct {
+		cfg  config.Config
+		name string
+	}{
+		{config.Config{}, "none"},
+		{config.Config{AnalyticsDriver: "PostHog", PostHogAPIKey: "phc_x"}, "posthog"},
+		{config.Config{AnalyticsDriver: "amplitude", AmplitudeAPIKey: "amp"}, "amplitude"},
+	}
+	for _, tc := range cases {
+		sink, err := NewAnalyticsSink(&tc.cfg)
+		if err != nil || sink.Name() != tc.name {
+			t.Errorf("NewAnalyticsSink(%q) = %v, %v, want %s", tc.cfg.AnalyticsDriver, sink, err, tc.name)
+		}
+	}
+	for _, driver := range []string{"posthog", "amplitude", "mixpanel"} {
+		if _, err := NewAnalyticsSink(&config.Config{AnalyticsDriver: driver}); err == nil {
+			t.Errorf("NewAnalyticsSink(%q) without credentials succeeded", driver)
+		}
+	}
+}
+
+func TestAnalyticsPayloads(t *testing.T) {
+	event := AnalyticsEvent{
+		Name:       EventPaywallHit,
+		UserID:     uuid.MustParse("5f0c5a9e-8d6b-4a5e-9f43-2b1d7c3e9a10"),
+		Properties: map[string]any{"feature": "forecast"},
+		Time:       time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	var posthog struct {
+		Event      string         `json:"event"`
+		DistinctID string         `json:"distinct_id"`
+		Properties map[string]any `json:"properties"`
+	}
+	raw, _ := postHogPayload("key", event)
+	if err := json.Unmarshal(raw, &posthog); err != nil {
+		t.Fatal(err)
+	}
+	if posthog.Event != EventPaywallHit || posthog.DistinctID != event.UserID.String() || posthog.Properties["feature"] != "forecast" {
+		t.Errorf("postHogPayload = %s", raw)
+	}
+
+	var amplitude struct {
+		Events []struct {
+			UserID    string `json:"user_id"`
+			EventType string `json:"event_type"`
+			Time      int64  `json:"time"`
+			InsertID  string `json:"insert_id"`
+		} `json:"events"`
+	}
+	raw, _ = amplitudePayload("key", event)
+	if err := json.Unmarshal(raw, &amplitude); err != nil {
+		t.Fatal(err)
+	}
+	if len(amplitude.Events) != 1 || amplitude.Events[0].EventType != EventPaywallHit ||
+		amplitude.Events[0].Time != event.Time.UnixMilli() || amplitude.Events[0].InsertID == "" {
+		t.Errorf("amplitudePayload = %s", raw)
+	}
+}
+
+type recordingSink struct{ events chan AnalyticsEvent }
+
+func (s recordingSink) Name() string { return "recording" }
+
+func (s recordingSink) Track(_ context.Context, event AnalyticsEvent) error {
+	s.events <- event
+	return nil
+}
+
+func TestAnalyticsDeliversQueuedEvents(t *testing.T) {
+	var disabled *Analytics
+	disabled.Emit(uuid.New(), EventScanCompleted, nil)
+
+	sink := recordingSink{events: make(chan AnalyticsEvent, 1)}
+	analytics := NewAnalytics(sink)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go analytics.RunAnalyticsWorker(ctx)
+
+	userID := uuid.New()
+	analytics.Emit(userID, EventStreakBroken, map[string]any{"previous_streak": 4})
+	select {
+	case got := <-sink.events:
+		if got.Name != EventStreakBroken || got.UserID != userID || got.Time.IsZero() {
+			t.Errorf("delivered %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("event was not delivered")
+	}
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidAction      = errors.New("invalid action: type must be ban, suspend or shadow_ban and reason is required")
+	ErrInvalidDuration    = errors.New("invalid duration: suspend needs a positive duration of at most 365 days, such as 7d, 2w or 72h")
+	ErrActionNotFound     = errors.New("moderation action not found")
+	ErrActionRevoked      = errors.New("moderation action is no longer in force")
+	ErrAppealExists       = errors.New("this action has already been appealed")
+	ErrAppealNotFound     = errors.New("appeal not found")
+	ErrAppealReviewed     = errors.New("appeal has already been reviewed")
+	ErrInvalidAppeal      = errors.New("message is required")
+	ErrInvalidAppealReply = errors.New("invalid decision: must be approve or reject")
+)
+
+// activeActions are moderation actions still in force.
+func activeActions(db *gorm.DB) *gorm.DB {
+	return db.Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())")
+}
+
+// notShadowBanned excludes users under an active shadow ban from results
+// other users see.
+func notShadowBanned(column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(column+" NOT IN (SELECT user_id FROM moderation_actions WHERE type = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW()))", models.ActionShadowBan)
+	}
+}
+
+// lockoutActions are the action types that lock a user out.
+var lockoutActions = []string{models.ActionBan, models.ActionSuspend}
+
+// IsBanned reports whether the user is banned or suspended.
+func (s *ModerationService) IsBanned(userID uuid.UUID) bool {
+	var count int64
+	s.db.Model(&models.ModerationAction{}).Scopes(activeActions).
+		Where("user_id = ? AND type IN ?", userID, lockoutActions).
+		Count(&count)
+	return count > 0
+}
+
+// TakeAction bans, suspends or shadow-bans a user. Bans and suspensions
+// also sign the user out everywhere.
+func (s *ModerationService) TakeAction(adminID, userID uuid.UUID, req *dto.ModerationActionRequest) (*models.ModerationAction, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if (req.Type != models.ActionBan && req.Type != models.ActionSuspend && req.Type != models.ActionShadowBan) || reason == "" {
+		return nil, ErrInvalidAction
+	}
+	var expiresAt *time.Time
+	if req.Type == models.ActionSuspend || req.Duration != "" {
+		d, err := parseActionDuration(req.Duration)
+		if err != nil {
+			return nil, err
+		}
+		t := time.Now().Add(d)
+		expiresAt = &t
+	}
+	if !isActiveUser(s.db, userID) {
+		return nil, ErrUserNotFound
+	}
+
+	action := models.ModerationAction{
+		UserID:    userID,
+		Type:      req.Type,
+		Reason:    truncateRunes(reason, 1000),
+		CaseID:    req.CaseID,
+		CreatedBy: adminID,
+		ExpiresAt: expiresAt,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&action).Error; err != nil {
+			return err
+		}
+		if containsString(lockoutActions, action.Type) {
+			if err := signOutEverywhere(tx, userID, models.RevokeReasonAdmin); err != nil {
+				return err
+			}
+		}
+		return writeAudit(tx, &adminID, "action.created", auditTargetAction, action.ID, map[string]any{
+			"user_id": userID, "type": action.Type, "reason": action.Reason, "expires_at": action.ExpiresAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+// maxActionDuration bounds a temporary action; longer ones should be bans.
+const maxActionDuration = 365 * 24 * time.Hour
+
+// actionDurationUnits are the units parseActionDuration adds to Go's.
+var actionDurationUnits = map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+
+// parseActionDuration reads a moderation action's duration: whole days or
+// weeks ("7d", "2w") or a Go duration ("72h").
+func parseActionDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidDuration
+	}
+	var d time.Duration
+	if unit, ok := actionDurationUnits[s[len(s)-1]]; ok {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n <= 0 || n > int(maxActionDuration/unit) {
+			return 0, ErrInvalidDuration
+		}
+		d = time.Duration(n) * unit
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, ErrInvalidDuration
+		}
+	}
+	if d <= 0 || d > maxActionDuration {
+		return 0, ErrInvalidDuration
+	}
+	return d, nil
+}
+
+// RevokeAction lifts a moderation action.
+func (s *ModerationService) RevokeAction(adminID, actionID uuid.UUID, reason string) (*models.ModerationAction, error) {
+	var action models.ModerationAction
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return revokeAction(tx, &action, actionID, adminID, strings.TrimSpace(reason), nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+// revokeAction locks and revokes the action, recording who lifted it and,
+// for reinstatements, the approved appeal.
+func revokeAction(tx *gorm.DB, action *models.ModerationAction, actionID, adminID uuid.UUID, reason string, appealID *uuid.UUID) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(action, "id = ?", actionID).Error; err != nil {
+		return ErrActionNotFound
+	}
+	if action.RevokedAt != nil {
+		return ErrActionRevoked
+	}
+
+	now := time.Now()
+	action.RevokedAt = &now
+	action.RevokedBy = &adminID
+	action.RevokeReason = truncateRunes(reason, 1000)
+	if err := tx.Model(action).Select("revoked_at", "revoked_by", "revoke_reason").Updates(action).Error; err != nil {
+		return err
+	}
+
+	details := map[string]any{"user_id": action.UserID, "type": action.Type, "reason": action.RevokeReason}
+	if appealID != nil {
+		details["appeal_id"] = *appealID
+	}
+	return writeAudit(tx, &adminID, "action.revoked", auditTargetAction, action.ID, details)
+}
+
+// ListUserActions returns the moderation actions taken against the user,
+// newest first, so they can see what to appeal.
+func (s *ModerationService) ListUserActions(userID uuid.UUID) ([]models.ModerationAction, error) {
+	var actions []models.ModerationAction
+	err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&actions).Error
+	return actions, err
+}
+
+// CreateAppeal files the user's appeal against one of their active
+// moderation actions. Each action can be appealed once.
+func (s *ModerationService) CreateAppeal(userID uuid.UUID, req *dto.CreateAppealRequest) (*models.Appeal, error) {
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		return nil, ErrInvalidAppeal
+	}
+
+	var action models.ModerationAction
+	if err := s.db.Where("id = ? AND user_id = ?", req.ActionID, userID).First(&action).Error; err != nil {
+		return nil, ErrActionNotFound
+	}
+	now := time.Now()
+	if action.RevokedAt != nil || (action.ExpiresAt != nil && !action.ExpiresAt.After(now)) {
+		return nil, ErrActionRevoked
+	}
+
+	appeal := models.Appeal{
+		ActionID: action.ID,
+		UserID:   userID,
+		Message:  truncateRunes(message, 2000),
+		Status:   models.AppealPending,
+		DueAt:    now.Add(s.appealSLA()),
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&appeal)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAppealExists
+		}
+		return writeAudit(tx, &userID, "appeal.filed", auditTargetAppeal, appeal.ID, map[string]any{
+			"action_id": action.ID, "due_at": appeal.DueAt,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &appeal, nil
+}
+
+// ListUserAppeals returns the user's appeals, newest first.
+func (s *ModerationService) ListUserAppeals(userID uuid.UUID) ([]models.Appeal, error) {
+	var appeals []models.Appeal
+	err := s.db.Preload("Action").Where("user_id = ?", userID).Order("created_at DESC").Find(&appeals).Error
+	return appeals, err
+}
+
+// ListAppeals returns appeals for review, oldest deadline first. overdue
+// narrows to pending appeals past their SLA.
+func (s *ModerationService) ListAppeals(status string, overdue bool, limit, offset int) ([]models.Appeal, int64, error) {
+	var appeals []models.Appeal
+	var total int64
+
+	query := s.db.Model(&models.Appeal{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if overdue {
+		query = query.Where("status = ? AND due_at < ?", models.AppealPending, time.Now())
+	}
+
+	query.Count(&total)
+
+	if err := query.Preload("Action").Order("due_at ASC").Limit(limit).Offset(offset).Find(&appeals).Error; err != nil {
+		return nil, 0, err
+	}
+	return appeals, total, nil
+}
+
+// AppealSLA reports the pending backlog and how reviews over the last 30
+// days compared with the SLA.
+func (s *ModerationService) AppealSLA() (*dto.AppealSLAStats, error) {
+	now := time.Now()
+	stats := &dto.AppealSLAStats{}
+	s.db.Model(&models.Appeal{}).Where("status = ?", models.AppealPending).Count(&stats.Pending)
+	s.db.Model(&models.Appeal{}).Where("status = ? AND due_at < ?", models.AppealPending, now).Count(&stats.Overdue)
+
+	var row struct {
+		Reviewed   int64
+		InSLA      int64
+		AvgSeconds *float64
+	}
+	err := s.db.Model(&models.Appeal{}).
+		Select("COUNT(*) AS reviewed, "+
+			"COUNT(*) FILTER (WHERE reviewed_at <= due_at) AS in_sla, "+
+			"AVG(EXTRACT(EPOCH FROM reviewed_at - created_at)) AS avg_seconds").
+		Where("reviewed_at >= ?", now.Add(-30*24*time.Hour)).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.Reviewed = row.Reviewed
+	stats.ReviewedInSLA = row.InSLA
+	if row.AvgSeconds != nil {
+		stats.AverageReviewHours = *row.AvgSeconds / 3600
+	}
+	return stats, nil
+}
+
+// ReviewAppeal approves or rejects a pending appeal. Approving revokes the
+// appealed action, reinstating the user.
+func (s *ModerationService) ReviewAppeal(adminID, appealID uuid.UUID, req *dto.ReviewAppealRequest) (*models.Appeal, error) {
+	status, ok := appealDecisions[req.Decision]
+	if !ok {
+		return nil, ErrInvalidAppealReply
+	}
+	note := truncateRunes(strings.TrimSpace(req.AdminNote), 1000)
+
+	var appeal models.Appeal
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&appeal, "id = ?", appealID).Error; err != nil {
+			return ErrAppealNotFound
+		}
+		if appeal.Status != models.AppealPending {
+			return ErrAppealReviewed
+		}
+
+		now := time.Now()
+		appeal.Status = status
+		appeal.AdminNote = note
+		appeal.ReviewedBy = &adminID
+		appeal.ReviewedAt = &now
+		if err := tx.Model(&appeal).Select("status", "admin_note", "reviewed_by", "reviewed_at").Updates(&appeal).Error; err != nil {
+			return err
+		}
+		if err := writeAudit(tx, &adminID, "appeal."+status, auditTargetAppeal, appeal.ID, map[string]any{
+			"action_id": appeal.ActionID, "within_sla": !now.After(appeal.DueAt), "note": note,
+		}); err != nil {
+			return err
+		}
+
+		if status != models.AppealApproved {
+			return nil
+		}
+		var action models.ModerationAction
+		err := revokeAction(tx, &action, appeal.ActionID, adminID, "appeal approved", &appeal.ID)
+		// An admin may have lifted the action while the appeal was pending.
+		if errors.Is(err, ErrActionRevoked) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &appeal, nil
+}
+
+var appealDecisions = map[string]string{
+	"approve": models.AppealApproved,
+	"reject":  models.AppealRejected,
+}
+
+// ListAuditLog returns audit entries, newest first, optionally for one target.
+func (s *ModerationService) ListAuditLog(targetType, targetID string, limit, offset int) ([]models.AuditLog, int64, error) {
+	var entries []models.AuditLog
+	var total int64
+
+	query := s.db.Model(&models.AuditLog{})
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if targetID != "" {
+		query = query.Where("target_id = ?", targetID)
+	}
+
+	query.Count(&total)
+
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (s *ModerationService) appealSLA() time.Duration {
+	if s.cfg == nil || s.cfg.AppealSLA <= 0 {
+		return 72 * time.Hour
+	}
+	return s.cfg.AppealSLA
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/google/uuid"
+)
+
+func TestParseActionDuration(t *testing.T) {
+	day := 24 * time.Hour
+	for in, want := range map[string]time.Duration{"7d": 7 * day, " 2w ": 14 * day, "72h": 72 * time.Hour, "365d": 365 * day} {
+		if got, err := parseActionDuration(in); err != nil || got != want {
+			t.Errorf("parseActionDuration(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "d", "0d", "-1d", "1.5d", "7x", "53w", "9000h", "-1h"} {
+		if _, err := parseActionDuration(in); !errors.Is(err, ErrInvalidDuration) {
+			t.Errorf("parseActionDuration(%q) error = %v, want ErrInvalidDuration", in, err)
+		}
+	}
+}
+
+func TestAppealValidation(t *testing.T) {
+	s := &ModerationService{}
+
+	if _, err := s.CreateAppeal(uuid.New(), &dto.CreateAppealRequest{ActionID: uuid.New(), Message: "   "}); !errors.Is(err, ErrInvalidAppeal) {
+		t.Errorf("blank message: error = %v, want ErrInvalidAppeal", err)
+	}
+	if _, err := s.ReviewAppeal(uuid.New(), uuid.New(), &dto.ReviewAppealRequest{Decision: "maybe"}); !errors.Is(err, ErrInvalidAppealReply) {
+		t.Errorf("unknown decision: error = %v, want ErrInvalidAppealReply", err)
+	}
+	for _, req := range []dto.ModerationActionRequest{
+		{Type: "mute", Reason: "spam"},
+		{Type: "ban", Reason: " "},
+	} {
+		if _, err := s.TakeAction(uuid.New(), uuid.New(), &req); !errors.Is(err, ErrInvalidAction) {
+			t.Errorf("TakeAction(%+v) error = %v, want ErrInvalidAction", req, err)
+		}
+	}
+	for _, req := range []dto.ModerationActionRequest{
+		{Type: "suspend", Reason: "spam"},
+		{Type: "suspend", Reason: "spam", Duration: "-1h"},
+		{Type: "ban", Reason: "spam", Duration: "a week"},
+		{Type: "suspend", Reason: "spam", Duration: "7x"},
+		{Type: "suspend", Reason: "spam", Duration: "366d"},
+	} {
+		if _, err := s.TakeAction(uuid.New(), uuid.New(), &req); !errors.Is(err, ErrInvalidDuration) {
+			t.Errorf("TakeAction(%+v) error = %v, want ErrInvalidDuration", req, err)
+		}
+	}
+
+	admin := &AdminUserService{}
+	for _, days := range []int{0, -3, 366} {
+		if _, err := admin.GrantPremium(uuid.New(), uuid.New(), &dto.GrantPremiumRequest{Days: days}); !errors.Is(err, ErrInvalidGrant) {
+			t.Errorf("GrantPremium(days=%d) error = %v, want ErrInvalidGrant", days, err)
+		}
+	}
+}
@@ -41,6 +41,10 @@ type appleKeyCache struct {
 
 var appleKeys appleKeyCache
 
+// errAppleKeysUnavailable marks failures to fetch Apple's keys, which say
+// nothing about the token.
+var errAppleKeysUnavailable = errors.New("apple jwks unavailable")
+
 func verifyAppleIdentityToken(ctx context.Context, tokenStr string, allowedAudiences []string) (jwt.MapClaims, error) {
 	parsed, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
 		if token.Method.Alg() != jwt.SigningMethodRS256.Alg() {
@@ -157,7 +161,7 @@ func getApplePublicKey(ctx context.Context, kid string) (*rsa.PublicKey, error)
 	if needFetch {
 		keys, err := fetchAppleKeys(ctx)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w: %w", errAppleKeysUnavailable, err)
 		}
 		appleKeys.keysByKID = keys
 		appleKeys.fetchedAt = time.Now()
@@ -234,3 +238,15 @@ func jwkToRSAPublicKey(k appleJWK) (*rsa.PublicKey, error) {
 
 	return &rsa.PublicKey{N: n, E: e}, nil
 }
+
+// appleEmailVerified reads the email_verified claim, which Apple has sent
+// both as a boolean and as the string "true".
+func appleEmailVerified(claims jwt.MapClaims) bool {
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
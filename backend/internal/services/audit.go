@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidAuditFilter = errors.New("invalid filter: actor_id must be a UUID and since/until RFC 3339 timestamps")
+
+// Audit log target types.
+const (
+	auditTargetAction       = "moderation_action"
+	auditTargetAppeal       = "appeal"
+	auditTargetUser         = "user"
+	auditTargetSubscription = "subscription"
+)
+
+// writeAudit appends an entry to the audit log inside the caller's
+// transaction, so the record and the change it describes commit together.
+func writeAudit(tx *gorm.DB, actorID *uuid.UUID, action, targetType string, targetID uuid.UUID, details map[string]any) error {
+	return recordAudit(tx, models.AuditLog{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID.String(),
+		Details:    details,
+	})
+}
+
+// recordAudit is writeAudit for entries that carry more than an actor, such
+// as the client IP.
+func recordAudit(tx *gorm.DB, entry models.AuditLog) error {
+	entry.Action = truncateRunes(entry.Action, 100)
+	entry.TargetID = truncateRunes(entry.TargetID, 64)
+	return tx.Create(&entry).Error
+}
+
+// AuditService exposes the audit log to admins.
+type AuditService struct {
+	db *gorm.DB
+}
+
+func NewAuditService(db *gorm.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// Record writes an entry built outside the services.
+func (s *AuditService) Record(entry dto.AuditEntry) error {
+	return recordAudit(s.db, models.AuditLog{
+		ActorID:    &entry.ActorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Details:    entry.Details,
+		IPAddress:  entry.IP,
+	})
+}
+
+// List returns audit entries matching the filter, newest first.
+func (s *AuditService) List(filter dto.AuditLogFilter, limit, offset int) ([]models.AuditLog, int64, error) {
+	scope, err := auditFilterScope(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.AuditLog
+	var total int64
+
+	query := s.db.Model(&models.AuditLog{}).Scopes(scope)
+	query.Count(&total)
+
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// auditFilterScope validates the filter and turns it into query conditions.
+func auditFilterScope(filter dto.AuditLogFilter) (func(*gorm.DB) *gorm.DB, error) {
+	var conds []func(*gorm.DB) *gorm.DB
+	where := func(query string, arg any) {
+		conds = append(conds, func(db *gorm.DB) *gorm.DB { return db.Where(query, arg) })
+	}
+
+	if v := strings.TrimSpace(filter.ActorID); v != "" {
+		actorID, err := uuid.Parse(v)
+		if err != nil {
+			return nil, ErrInvalidAuditFilter
+		}
+		where("actor_id = ?", actorID)
+	}
+	if v := strings.TrimSpace(filter.Action); v != "" {
+		where("action LIKE ?", escapeLike(v)+"%")
+	}
+	if v := strings.TrimSpace(filter.TargetType); v != "" {
+		where("target_type = ?", v)
+	}
+	if v := strings.TrimSpace(filter.TargetID); v != "" {
+		where("target_id = ?", v)
+	}
+	if v := strings.TrimSpace(filter.IP); v != "" {
+		where("ip_address = ?", v)
+	}
+	if v := strings.TrimSpace(filter.Since); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, ErrInvalidAuditFilter
+		}
+		where("created_at >= ?", since)
+	}
+	if v := strings.TrimSpace(filter.Until); v != "" {
+		until, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, ErrInvalidAuditFilter
+		}
+		where("created_at < ?", until)
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		for _, cond := range conds {
+			db = cond(db)
+		}
+		return db
+	}, nil
+}
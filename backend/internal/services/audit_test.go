@@ -0,0 +1,30 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+)
+
+func TestAuditFilterValidation(t *testing.T) {
+	for _, filter := range []dto.AuditLogFilter{
+		{ActorID: "not-a-uuid"},
+		{Since: "yesterday"},
+		{Until: "2026-13-01T00:00:00Z"},
+	} {
+		if _, err := auditFilterScope(filter); !errors.Is(err, ErrInvalidAuditFilter) {
+			t.Errorf("auditFilterScope(%+v) error = %v, want ErrInvalidAuditFilter", filter, err)
+		}
+	}
+
+	valid := dto.AuditLogFilter{
+		ActorID: "5f0c5a9e-8d6b-4a5e-9f43-2b1d7c3e9a10",
+		Action:  "appeal.",
+		Since:   "2026-01-01T00:00:00Z",
+		Until:   "2026-02-01T00:00:00+03:00",
+	}
+	if _, err := auditFilterScope(valid); err != nil {
+		t.Errorf("auditFilterScope(%+v) error = %v", valid, err)
+	}
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// provenanceAdmin marks reading fields an admin has corrected.
+const provenanceAdmin = "admin_corrected"
+
+var (
+	ErrInvalidCorrection = errors.New("invalid correction")
+	ErrReadingPending    = errors.New("reading is still being analyzed")
+)
+
+// CorrectReading overwrites fields of any user's reading, keeping the
+// previous values in the reading's correction history.
+func (s *AuraService) CorrectReading(ctx context.Context, adminID, readingID uuid.UUID, req *dto.ReadingCorrectionRequest) (*models.AuraReading, error) {
+	ctx, span := tracer.Start(ctx, "AuraService.CorrectReading")
+	defer span.End()
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidCorrection)
+	}
+
+	var reading models.AuraReading
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reading, "id = ?", readingID).Error; err != nil {
+			return ErrReadingNotFound
+		}
+		// The background analysis would overwrite the correction.
+		if reading.Status == models.ReadingStatusPending {
+			return ErrReadingPending
+		}
+
+		original, corrected, err := applyCorrection(&reading, req)
+		if err != nil {
+			return err
+		}
+
+		columns := make([]string, 0, len(corrected)+1)
+		for field := range corrected {
+			columns = append(columns, field)
+		}
+		slices.Sort(columns)
+		if err := tx.Model(&reading).Select(append(columns, "provenance")).Updates(&reading).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ReadingCorrection{
+			ReadingID: reading.ID,
+			UserID:    reading.UserID,
+			AdminID:   adminID,
+			Reason:    truncateRunes(reason, 1000),
+			Original:  original,
+			Corrected: corrected,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reading, nil
+}
+
+// ListCorrections returns a reading's correction history, newest first.
+func (s *AuraService) ListCorrections(readingID uuid.UUID) ([]models.ReadingCorrection, error) {
+	var corrections []models.ReadingCorrection
+	err := s.db.Where("reading_id = ?", readingID).Order("created_at DESC").Find(&corrections).Error
+	return corrections, err
+}
+
+// applyCorrection validates req, writes the changed fields onto reading and
+// flags them in its provenance. Fields set to their current value are not
+// counted as changes.
+func applyCorrection(reading *models.AuraReading, req *dto.ReadingCorrectionRequest) (map[string]any, map[string]any, error) {
+	original, corrected := map[string]any{}, map[string]any{}
+	change := func(field string, before, after any) {
+		original[field], corrected[field] = before, after
+	}
+
+	if req.AuraColor != nil {
+		color := normalizeAuraColor(*req.AuraColor)
+		if color == "" {
+			return nil, nil, fmt.Errorf("%w: aura_color must be one of %s", ErrInvalidCorrection, strings.Join(auraColors, ", "))
+		}
+		if color != reading.AuraColor {
+			change("aura_color", reading.AuraColor, color)
+			reading.AuraColor = color
+		}
+	}
+	if req.SecondaryColor != nil {
+		var secondary *string
+		if v := strings.ToLower(strings.TrimSpace(*req.SecondaryColor)); v != "" {
+			if normalizeAuraColor(v) == "" && !slices.Contains(secondaryColors, v) {
+				return nil, nil, fmt.Errorf("%w: unknown secondary_color %q", ErrInvalidCorrection, v)
+			}
+			secondary = &v
+		}
+		if derefString(secondary) != derefString(reading.SecondaryColor) {
+			change("secondary_color", reading.SecondaryColor, secondary)
+			reading.SecondaryColor = secondary
+		}
+	}
+	if req.EnergyLevel != nil {
+		if *req.EnergyLevel < 1 || *req.EnergyLevel > 100 {
+			return nil, nil, fmt.Errorf("%w: energy_level must be 1-100", ErrInvalidCorrection)
+		}
+		if *req.EnergyLevel != reading.EnergyLevel {
+			change("energy_level", reading.EnergyLevel, *req.EnergyLevel)
+			reading.EnergyLevel = *req.EnergyLevel
+		}
+	}
+	if req.MoodScore != nil {
+		if *req.MoodScore < 1 || *req.MoodScore > 10 {
+			return nil, nil, fmt.Errorf("%w: mood_score must be 1-10", ErrInvalidCorrection)
+		}
+		if *req.MoodScore != reading.MoodScore {
+			change("mood_score", reading.MoodScore, *req.MoodScore)
+			reading.MoodScore = *req.MoodScore
+		}
+	}
+	for _, text := range []struct {
+		field string
+		value *string
+		dst   *string
+	}{
+		{"personality", req.Personality, &reading.Personality},
+		{"daily_advice", req.DailyAdvice, &reading.DailyAdvice},
+	} {
+		if text.value == nil {
+			continue
+		}
+		v := strings.TrimSpace(*text.value)
+		if v == "" {
+			return nil, nil, fmt.Errorf("%w: %s can't be empty", ErrInvalidCorrection, text.field)
+		}
+		if v != *text.dst {
+			change(text.field, *text.dst, v)
+			*text.dst = v
+		}
+	}
+	for _, list := range []struct {
+		field string
+		value []string
+		dst   *[]string
+	}{
+		{"strengths", req.Strengths, &reading.Strengths},
+		{"challenges", req.Challenges, &reading.Challenges},
+	} {
+		if list.value == nil {
+			continue
+		}
+		if len(list.value) == 0 {
+			return nil, nil, fmt.Errorf("%w: %s can't be empty", ErrInvalidCorrection, list.field)
+		}
+		if !slices.Equal(list.value, *list.dst) {
+			change(list.field, *list.dst, list.value)
+			*list.dst = list.value
+		}
+	}
+
+	if len(corrected) == 0 {
+		return nil, nil, fmt.Errorf("%w: nothing to change", ErrInvalidCorrection)
+	}
+	if reading.Provenance == nil {
+		reading.Provenance = make(map[string]string, len(corrected))
+	}
+	for field := range corrected {
+		reading.Provenance[field] = provenanceAdmin
+	}
+	return original, corrected, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestApplyCorrection(t *testing.T) {
+	gold := "gold"
+	reading := &models.AuraReading{
+		AuraColor:      "red",
+		SecondaryColor: &gold,
+		EnergyLevel:    40,
+		MoodScore:      5,
+		Strengths:      []string{"Courage"},
+		Provenance:     map[string]string{"aura_color": "openai", "energy_level": "openai"},
+	}
+	blue, clear, energy := " Blue ", "", 40
+	original, corrected, err := applyCorrection(reading, &dto.ReadingCorrectionRequest{
+		AuraColor:      &blue,
+		SecondaryColor: &clear,
+		EnergyLevel:    &energy, // unchanged, so not recorded
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reading.AuraColor != "blue" || reading.SecondaryColor != nil {
+		t.Errorf("reading = %s / %v", reading.AuraColor, reading.SecondaryColor)
+	}
+	if original["aura_color"] != "red" || corrected["aura_color"] != "blue" {
+		t.Errorf("aura_color history = %v -> %v", original["aura_color"], corrected["aura_color"])
+	}
+	if _, ok := corrected["energy_level"]; ok {
+		t.Error("unchanged energy_level recorded as a correction")
+	}
+	if reading.Provenance["aura_color"] != provenanceAdmin || reading.Provenance["secondary_color"] != provenanceAdmin {
+		t.Errorf("provenance = %v", reading.Provenance)
+	}
+	if reading.Provenance["energy_level"] != "openai" {
+		t.Errorf("untouched field provenance changed: %v", reading.Provenance)
+	}
+}
+
+func TestApplyCorrectionRejectsInvalid(t *testing.T) {
+	magenta, mood, empty := "magenta", 11, ""
+	for name, req := range map[string]dto.ReadingCorrectionRequest{
+		"color":     {AuraColor: &magenta},
+		"mood":      {MoodScore: &mood},
+		"empty":     {Personality: &empty},
+		"no change": {},
+	} {
+		reading := &models.AuraReading{AuraColor: "red", MoodScore: 5, Personality: "Bold."}
+		if _, _, err := applyCorrection(reading, &req); !errors.Is(err, ErrInvalidCorrection) {
+			t.Errorf("%s: err = %v, want ErrInvalidCorrection", name, err)
+		}
+	}
+}
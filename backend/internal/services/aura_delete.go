@@ -0,0 +1,187 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxBulkDelete bounds one DELETE /aura request; clearing everything goes
+// through DeleteAll instead.
+const maxBulkDelete = 100
+
+// clearHistoryTokenTTL is how long a clear-history confirmation stays valid.
+const clearHistoryTokenTTL = 5 * time.Minute
+
+const (
+	clearTokenPayloadLen = 16 + 8 // user ID + expiry
+	clearTokenSigLen     = 16
+)
+
+var (
+	ErrInvalidBulkDelete    = errors.New("ids must list 1-100 reading IDs")
+	ErrInvalidClearHistory  = errors.New("confirmation token is invalid or expired; request a new one")
+	ErrClearHistoryDisabled = errors.New("clearing history is not configured")
+)
+
+// DeleteMany deletes the listed readings of the user with their stored
+// photos. IDs that are not the user's readings are skipped; the number
+// actually deleted is returned. Earlier versions stay with the soft-deleted
+// readings until the account is purged: CanScan counts them as
+// regenerations, which deleting must not give back.
+func (s *AuraService) DeleteMany(userID uuid.UUID, rawIDs []string) (int64, error) {
+	if len(rawIDs) == 0 || len(rawIDs) > maxBulkDelete {
+		return 0, ErrInvalidBulkDelete
+	}
+	ids := make([]uuid.UUID, 0, len(rawIDs))
+	for _, raw := range rawIDs {
+		id, err := uuid.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return 0, ErrInvalidBulkDelete
+		}
+		ids = append(ids, id)
+	}
+
+	var deleted []uuid.UUID
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.AuraReading{}).
+			Where("user_id = ? AND id IN ?", userID, ids).
+			Pluck("id", &deleted).Error; err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+		if err := tx.Where("user_id = ? AND id IN ?", userID, deleted).Delete(&models.AuraReading{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND reading_id IN ?", userID, deleted).Delete(&models.ReadingTrait{}).Error; err != nil {
+			return err
+		}
+		return refreshUserAggregate(tx, userID)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.removePhotos(userID, deleted)
+	return int64(len(deleted)), nil
+}
+
+// removePhotos deletes the stored photos of deleted readings. Failures are
+// logged; the readings are already gone.
+func (s *AuraService) removePhotos(userID uuid.UUID, readingIDs []uuid.UUID) {
+	if s.photos == nil {
+		return
+	}
+	for _, id := range readingIDs {
+		if err := s.photos.Remove(userID, id); err != nil {
+			log.Printf("photos: remove photo of deleted reading %s: %v", id, err)
+		}
+	}
+}
+
+// ClearHistoryConfirmation issues the short-lived token DeleteAll requires,
+// with the number of readings that would be deleted for the app to show.
+func (s *AuraService) ClearHistoryConfirmation(userID uuid.UUID) (*dto.ClearHistoryConfirmation, error) {
+	if strings.TrimSpace(s.cfg.JWTSecret) == "" {
+		return nil, ErrClearHistoryDisabled
+	}
+	var count int64
+	if err := s.db.Model(&models.AuraReading{}).Scopes(personalReadings).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(clearHistoryTokenTTL).Truncate(time.Second)
+	return &dto.ClearHistoryConfirmation{
+		ConfirmationToken: s.clearHistoryToken(userID, expiresAt),
+		ExpiresAt:         expiresAt,
+		Readings:          count,
+	}, nil
+}
+
+// DeleteAll clears the user's whole reading history: personal and group
+// readings, corrections and stored photos. Earlier versions are kept for
+// the scan quota, as in DeleteMany. The token must come from
+// ClearHistoryConfirmation for the same user.
+func (s *AuraService) DeleteAll(userID uuid.UUID, token string) (int64, error) {
+	if err := s.verifyClearHistoryToken(userID, token); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Report personal readings, as the confirmation did; the people of
+		// group photos go with their group readings.
+		if err := tx.Model(&models.AuraReading{}).Scopes(personalReadings).Where("user_id = ?", userID).Count(&deleted).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.AuraReading{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.GroupReading{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.ScanBatch{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.ReadingCorrection{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.ReadingTrait{}).Error; err != nil {
+			return err
+		}
+		if err := refreshUserAggregate(tx, userID); err != nil {
+			return err
+		}
+		return DeleteUserPhotos(tx, userID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func (s *AuraService) clearHistoryToken(userID uuid.UUID, expiresAt time.Time) string {
+	buf := make([]byte, clearTokenPayloadLen, clearTokenPayloadLen+clearTokenSigLen)
+	copy(buf, userID[:])
+	binary.BigEndian.PutUint64(buf[16:], uint64(expiresAt.Unix()))
+	buf = append(buf, s.clearHistorySignature(buf)...)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func (s *AuraService) verifyClearHistoryToken(userID uuid.UUID, token string) error {
+	if strings.TrimSpace(s.cfg.JWTSecret) == "" {
+		return ErrClearHistoryDisabled
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil || len(raw) != clearTokenPayloadLen+clearTokenSigLen {
+		return ErrInvalidClearHistory
+	}
+	payload, sig := raw[:clearTokenPayloadLen], raw[clearTokenPayloadLen:]
+	if !hmac.Equal(sig, s.clearHistorySignature(payload)) {
+		return ErrInvalidClearHistory
+	}
+	if !hmac.Equal(payload[:16], userID[:]) {
+		return ErrInvalidClearHistory
+	}
+	if time.Now().After(time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0)) {
+		return ErrInvalidClearHistory
+	}
+	return nil
+}
+
+func (s *AuraService) clearHistorySignature(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte("clear-history:v1:"+s.cfg.JWTSecret))
+	mac.Write(payload)
+	return mac.Sum(nil)[:clearTokenSigLen]
+}
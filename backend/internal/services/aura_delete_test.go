@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestClearHistoryToken(t *testing.T) {
+	s := &AuraService{cfg: &config.Config{JWTSecret: "secret"}}
+	userID := uuid.New()
+
+	token := s.clearHistoryToken(userID, time.Now().Add(time.Minute))
+	if err := s.verifyClearHistoryToken(userID, token); err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+
+	expired := s.clearHistoryToken(userID, time.Now().Add(-time.Second))
+	other := &AuraService{cfg: &config.Config{JWTSecret: "other"}}
+	for name, check := range map[string]error{
+		"other user": s.verifyClearHistoryToken(uuid.New(), token),
+		"expired":    s.verifyClearHistoryToken(userID, expired),
+		"tampered":   s.verifyClearHistoryToken(userID, strings.ToUpper(token)),
+		"other key":  other.verifyClearHistoryToken(userID, token),
+		"garbage":    s.verifyClearHistoryToken(userID, "not-a-token"),
+	} {
+		if !errors.Is(check, ErrInvalidClearHistory) {
+			t.Errorf("%s: error = %v, want ErrInvalidClearHistory", name, check)
+		}
+	}
+}
+
+func TestDeleteManyValidatesIDs(t *testing.T) {
+	s := &AuraService{}
+	tooMany := make([]string, maxBulkDelete+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()
+	}
+	for _, ids := range [][]string{nil, {"not-a-uuid"}, tooMany} {
+		if _, err := s.DeleteMany(uuid.New(), ids); !errors.Is(err, ErrInvalidBulkDelete) {
+			t.Errorf("DeleteMany(%d ids) error = %v, want ErrInvalidBulkDelete", len(ids), err)
+		}
+	}
+}
+
+func TestDeletingRegeneratedReadingKeepsQuotaUsed(t *testing.T) {
+	db := testPostgres(t)
+	userID, readingID := uuid.New(), uuid.New()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		seedRegeneratedReading(t, tx, userID, readingID, nil)
+		s := &AuraService{db: tx}
+
+		_, before, err := s.CanScan(context.Background(), userID, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n, err := s.DeleteMany(userID, []string{readingID.String()}); err != nil || n != 1 {
+			t.Fatalf("DeleteMany = %d, %v", n, err)
+		}
+		_, after, err := s.CanScan(context.Background(), userID, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if before != 8 || after != before {
+			t.Errorf("scans left: %d before the delete, %d after; want 8 both times", before, after)
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Prompt variants recorded on each reading so share rates can be compared.
+const (
+	promptVariantBaseline = "baseline"
+	promptVariantHistory  = "history"
+
+	auraHistoryReadings = 3
+)
+
+// promptVariant assigns the user to the history experiment. Buckets are stable
+// per user so the same person always sees the same variant.
+func (s *AuraService) promptVariant(userID uuid.UUID) string {
+	if s.cfg == nil || !s.cfg.AuraPromptHistory {
+		return promptVariantBaseline
+	}
+
+	rollout := s.cfg.AuraPromptHistoryRollout
+	if rollout >= 100 {
+		return promptVariantHistory
+	}
+	sum := sha256.Sum256([]byte("prompt-history:" + userID.String()))
+	if int(binary.BigEndian.Uint32(sum[:4])%100) < rollout {
+		return promptVariantHistory
+	}
+	return promptVariantBaseline
+}
+
+// readingHistory summarizes the user's most recent readings for the prompt.
+func (s *AuraService) readingHistory(db *gorm.DB, userID uuid.UUID) string {
+	var recent []models.AuraReading
+	if err := db.Select("aura_color", "energy_level", "mood_score").Scopes(personalReadings).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(auraHistoryReadings).
+		Find(&recent).Error; err != nil {
+		return ""
+	}
+	return summarizeReadingHistory(recent)
+}
+
+// summarizeReadingHistory renders readings (newest first) as a compact
+// oldest-to-newest line such as "blue (energy 55, mood 6) -> green (energy 70,
+// mood 7); energy trending up".
+func summarizeReadingHistory(recent []models.AuraReading) string {
+	if len(recent) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(recent))
+	for i := len(recent) - 1; i >= 0; i-- {
+		r := recent[i]
+		parts = append(parts, fmt.Sprintf("%s (energy %d, mood %d)", r.AuraColor, r.EnergyLevel, r.MoodScore))
+	}
+	summary := strings.Join(parts, " -> ")
+
+	if len(recent) > 1 {
+		oldest, newest := recent[len(recent)-1], recent[0]
+		summary += "; energy " + trend(newest.EnergyLevel-oldest.EnergyLevel, 10)
+		summary += ", mood " + trend(newest.MoodScore-oldest.MoodScore, 1)
+	}
+	return summary
+}
+
+func trend(delta, threshold int) string {
+	switch {
+	case delta >= threshold:
+		return "trending up"
+	case delta <= -threshold:
+		return "trending down"
+	default:
+		return "steady"
+	}
+}
+
+// PromptVariantStats compares readings, self-ratings and share rates per
+// prompt variant. Average self-rating is the primary quality signal.
+func (s *AuraService) PromptVariantStats() ([]dto.PromptVariantStats, error) {
+	var rows []struct {
+		PromptVariant string
+		Readings      int64
+		Shared        int64
+		Rated         int64
+		AverageRating *float64
+	}
+	if err := s.db.Model(&models.AuraReading{}).
+		Select("COALESCE(NULLIF(prompt_variant, ''), ?) AS prompt_variant, COUNT(*) AS readings, COUNT(*) FILTER (WHERE share_count > 0) AS shared, COUNT(rating) AS rated, AVG(rating) AS average_rating", promptVariantBaseline).
+		Where("NOT synthetic").
+		Group("1").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var accuracy []struct {
+		PromptVariant string
+		Responses     int64
+		Average       float64
+	}
+	if err := s.db.Model(&models.SurveyResponse{}).
+		Joins("JOIN surveys ON surveys.id = survey_responses.survey_id AND surveys.kind = ?", models.SurveyKindAccuracy).
+		Select("survey_responses.prompt_variant, COUNT(*) AS responses, AVG(survey_responses.score) AS average").
+		Where("survey_responses.score IS NOT NULL").
+		Group("survey_responses.prompt_variant").
+		Scan(&accuracy).Error; err != nil {
+		return nil, err
+	}
+	byVariant := make(map[string]int, len(accuracy))
+	for i, a := range accuracy {
+		byVariant[a.PromptVariant] = i
+	}
+
+	stats := make([]dto.PromptVariantStats, 0, len(rows))
+	for _, row := range rows {
+		shareRate := 0.0
+		if row.Readings > 0 {
+			shareRate = float64(row.Shared) / float64(row.Readings)
+		}
+		stats = append(stats, dto.PromptVariantStats{
+			Variant:        row.PromptVariant,
+			Readings:       row.Readings,
+			SharedReadings: row.Shared,
+			ShareRate:      shareRate,
+			Ratings:        row.Rated,
+			AverageRating:  row.AverageRating,
+		})
+		if i, ok := byVariant[row.PromptVariant]; ok {
+			avg := accuracy[i].Average
+			stats[len(stats)-1].AccuracyResponses = accuracy[i].Responses
+			stats[len(stats)-1].AverageAccuracy = &avg
+		}
+	}
+	markLeadingVariant(stats)
+	return stats, nil
+}
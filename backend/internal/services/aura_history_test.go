@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestSummarizeReadingHistory(t *testing.T) {
+	recent := []models.AuraReading{
+		{AuraColor: "green", EnergyLevel: 75, MoodScore: 7},
+		{AuraColor: "blue", EnergyLevel: 60, MoodScore: 7},
+		{AuraColor: "blue", EnergyLevel: 50, MoodScore: 6},
+	}
+
+	got := summarizeReadingHistory(recent)
+	want := "blue (energy 50, mood 6) -> blue (energy 60, mood 7) -> green (energy 75, mood 7); energy trending up, mood trending up"
+	if got != want {
+		t.Fatalf("unexpected summary:\n got %q\nwant %q", got, want)
+	}
+
+	if summarizeReadingHistory(nil) != "" {
+		t.Fatal("expected empty summary without readings")
+	}
+}
+
+func TestPromptVariantRollout(t *testing.T) {
+	userID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+
+	off := &AuraService{cfg: &config.Config{AuraPromptHistory: false, AuraPromptHistoryRollout: 100}}
+	if got := off.promptVariant(userID); got != promptVariantBaseline {
+		t.Fatalf("expected baseline when disabled, got %s", got)
+	}
+
+	full := &AuraService{cfg: &config.Config{AuraPromptHistory: true, AuraPromptHistoryRollout: 100}}
+	if got := full.promptVariant(userID); got != promptVariantHistory {
+		t.Fatalf("expected history at full rollout, got %s", got)
+	}
+
+	none := &AuraService{cfg: &config.Config{AuraPromptHistory: true, AuraPromptHistoryRollout: 0}}
+	if got := none.promptVariant(userID); got != promptVariantBaseline {
+		t.Fatalf("expected baseline at zero rollout, got %s", got)
+	}
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"image"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/imageproc"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/queue"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// provenanceInstant marks a field taken from the local photo histogram.
+const provenanceInstant = "instant"
+
+const (
+	// instantAnalysisTimeout bounds the background AI call of an async scan.
+	instantAnalysisTimeout = 60 * time.Second
+	// pendingReadingMaxAge is how long a reading may stay pending before it
+	// is released with its provisional result (e.g. after a restart).
+	pendingReadingMaxAge = 5 * time.Minute
+)
+
+// CreateInstant stores a reading with a provisional result right away and
+// finishes the AI analysis in the background. The provisional color comes
+// from the photo's dominant hues when image bytes are available, so the app
+// can start its reveal while the full result is on its way; clients poll
+// the reading until its status is ready. The analysis waits its turn in the
+// scan queue, whose position clients can poll or watch, or with
+// AURA_SCAN_DISPATCH=queue goes to the task queue's workers.
+func (s *AuraService) CreateInstant(ctx context.Context, userID uuid.UUID, req dto.CreateAuraRequest) (*models.AuraReading, error) {
+	ctx, span := tracer.Start(ctx, "AuraService.CreateInstant")
+	defer span.End()
+	return s.createInstant(ctx, userID, req, nil)
+}
+
+// createInstant is CreateInstant for a photo of the given batch, if any.
+func (s *AuraService) createInstant(ctx context.Context, userID uuid.UUID, req dto.CreateAuraRequest, batchID *uuid.UUID) (*models.AuraReading, error) {
+	ctx = withAIUser(ctx, s.db, userID)
+	db := s.db.WithContext(ctx)
+
+	imageURL, err := scanImageURL(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.screenImage(ctx, db, userID, imageURL, req.ImageData); err != nil {
+		return nil, err
+	}
+	if err := s.requireFace(ctx, userID, imageURL, req.ImageData); err != nil {
+		return nil, err
+	}
+	metrics.ScansTotal.WithLabelValues("attempted").Inc()
+
+	imageHash := inlineImageHash(req.ImageData)
+	base := deterministicAuraResult(userID, photoKey(imageURL, imageHash))
+	img := decodeInlineImage(req.ImageData)
+	instant := ""
+	if img != nil {
+		instant = imageproc.InstantAuraColor(img)
+	}
+	if instant != "" {
+		// The photo-derived color also becomes the AI's fallback, so the
+		// provisional and final results agree unless the AI knows better.
+		base.AuraColor = instant
+		if base.SecondaryColor != nil && *base.SecondaryColor == instant {
+			base.SecondaryColor = nil
+		}
+	}
+
+	variant, opts := s.analysisOptions(ctx, db, userID, req.Locale)
+	draft := deterministicDraft(base)
+	if instant != "" {
+		draft.Provenance["aura_color"] = provenanceInstant
+	}
+	localizeDraft(&draft, opts.Language)
+
+	reading := &models.AuraReading{
+		UserID:         userID,
+		ImageURL:       imageURL,
+		ImageHash:      imageHash,
+		AuraColor:      draft.Scores.AuraColor,
+		SecondaryColor: draft.Scores.SecondaryColor,
+		EnergyLevel:    clamp(draft.Scores.EnergyLevel, 1, 100),
+		MoodScore:      clamp(draft.Scores.MoodScore, 1, 10),
+		Personality:    draft.Personality,
+		Strengths:      draft.Strengths,
+		Challenges:     draft.Challenges,
+		DailyAdvice:    draft.DailyAdvice,
+		Provenance:     draft.Provenance,
+		Palette:        photoPalette(img),
+		Source:         draft.Source,
+		ScanBatchID:    batchID,
+		Language:       opts.Language,
+		Status:         models.ReadingStatusPending,
+		AnalyzedAt:     time.Now(),
+	}
+	variant.tag(reading)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(reading).Error; err != nil {
+			return err
+		}
+		if err := dualWriteReadingTraits(tx, reading.ID); err != nil {
+			return err
+		}
+		return refreshUserAggregate(tx, userID)
+	})
+	if err != nil {
+		metrics.ScansTotal.WithLabelValues("failed").Inc()
+		return nil, err
+	}
+
+	s.storePhoto(ctx, reading, req.ImageData)
+	task := scanTask{UserID: userID, ReadingID: reading.ID, ImageURL: imageURL, Base: base, Options: opts}
+	if s.tasks == nil || s.cfg.AuraScanDispatch != "queue" {
+		s.scanQueue.enqueue(reading.ID, func() { s.completeInstant(task) })
+	} else if err := s.tasks.Enqueue(ctx, TaskScanAnalyze, task); err != nil {
+		log.Printf("instant scan %s: %v; analyzing here", reading.ID, err)
+		s.scanQueue.enqueue(reading.ID, func() { s.completeInstant(task) })
+	}
+	return reading, nil
+}
+
+// UseQueue lets CreateInstant send analyses to the workers when
+// AURA_SCAN_DISPATCH is "queue".
+func (s *AuraService) UseQueue(tasks queue.Queue) {
+	s.tasks = tasks
+}
+
+// TaskScanAnalyze runs the AI analysis of a pending async scan.
+const TaskScanAnalyze = "scan.analyze"
+
+// scanTask is everything completeInstant needs, so a worker other than the
+// instance that took the scan can finish it.
+type scanTask struct {
+	UserID    uuid.UUID           `json:"user_id"`
+	ReadingID uuid.UUID           `json:"reading_id"`
+	ImageURL  string              `json:"image_url"`
+	Base      auraAnalysisResult  `json:"base"`
+	Options   auraAnalysisOptions `json:"options"`
+}
+
+// HandleScanTask is the worker side of a queued async scan.
+func (s *AuraService) HandleScanTask(ctx context.Context, payload []byte) error {
+	var task scanTask
+	if err := queue.Decode(payload, &task); err != nil {
+		return err
+	}
+	s.completeInstant(task)
+	return nil
+}
+
+// decodeInlineBytes decodes base64 (optionally data-URI) image data, or
+// returns nil when there is nothing usable.
+func decodeInlineBytes(imageData string) []byte {
+	data := strings.TrimSpace(imageData)
+	if data == "" {
+		return nil
+	}
+	if i := strings.Index(data, ","); strings.HasPrefix(data, "data:") && i >= 0 {
+		data = data[i+1:]
+	}
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+// decodeInlineImage decodes inline image data into an image, or returns nil
+// when there is nothing usable.
+func decodeInlineImage(imageData string) image.Image {
+	raw := decodeInlineBytes(imageData)
+	if raw == nil {
+		return nil
+	}
+	img, err := imageproc.Decode(raw)
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// completeInstant runs the AI analysis for a pending reading and replaces
+// the provisional result. When the AI is unavailable the provisional result
+// stands and the reading is simply marked ready.
+func (s *AuraService) completeInstant(task scanTask) {
+	userID, readingID, base := task.UserID, task.ReadingID, task.Base
+	ctx, cancel := context.WithTimeout(withAIUser(context.Background(), s.db, userID), instantAnalysisTimeout)
+	defer cancel()
+
+	final := models.AuraReading{Status: models.ReadingStatusReady}
+	columns := []string{"status", "source", "provider_latency_ms"}
+	started := time.Now()
+	draft, err := s.analyzer.analyze(ctx, task.ImageURL, base, task.Options)
+	final.Source, final.ProviderLatencyMs = scanSource(draft.Source, err, started)
+	if err != nil {
+		metrics.ScanFallbackTotal.WithLabelValues(scanFallbackReason(err)).Inc()
+	} else {
+		final.AuraColor = draft.Scores.AuraColor
+		final.SecondaryColor = draft.Scores.SecondaryColor
+		final.EnergyLevel = clamp(draft.Scores.EnergyLevel, 1, 100)
+		final.MoodScore = clamp(draft.Scores.MoodScore, 1, 10)
+		final.Personality = draft.Personality
+		final.Strengths = draft.Strengths
+		final.Challenges = draft.Challenges
+		final.DailyAdvice = draft.DailyAdvice
+		final.Provenance = draft.Provenance
+		final.PromptTemplateID = draft.PromptTemplateID
+		final.AnalyzedAt = time.Now()
+		columns = append(columns, "aura_color", "secondary_color", "energy_level", "mood_score",
+			"personality", "strengths", "challenges", "daily_advice", "provenance", "prompt_template_id", "analyzed_at")
+	}
+
+	// A reading deleted or released meanwhile is left alone.
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.AuraReading{}).
+			Where("id = ? AND status = ?", readingID, models.ReadingStatusPending).
+			Select(columns).Updates(&final).Error; err != nil {
+			return err
+		}
+		if err := dualWriteReadingTraits(tx, readingID); err != nil {
+			return err
+		}
+		return refreshUserAggregate(tx, userID)
+	})
+	if err != nil {
+		log.Printf("instant scan %s: failed to store final result: %v", readingID, err)
+		metrics.ScansTotal.WithLabelValues("failed").Inc()
+		return
+	}
+	metrics.ScansTotal.WithLabelValues("succeeded").Inc()
+	color := final.AuraColor
+	if color == "" {
+		color = base.AuraColor
+	}
+	s.analytics.Emit(userID, EventScanCompleted, scanCompletedProperties(readingID, color, final.Provenance, true))
+}
+
+// ReleaseStalePending marks readings left pending (e.g. by a restart during
+// analysis) as ready with their provisional result.
+func (s *AuraService) ReleaseStalePending() (int64, error) {
+	result := s.db.Model(&models.AuraReading{}).
+		Where("status = ? AND created_at < ?", models.ReadingStatusPending, time.Now().Add(-pendingReadingMaxAge)).
+		Update("status", models.ReadingStatusReady)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidListFilter     = errors.New("invalid filter: color must be an aura color, from/to dates (YYYY-MM-DD) or RFC 3339 timestamps, min_energy 1-100, sort date, energy or mood and order asc or desc")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrCursorSortUnsupported = errors.New("cursor pagination only supports sorting by date")
+)
+
+const defaultListOrder = "pinned DESC, pinned_at DESC, created_at DESC"
+
+// listSortColumns maps the sort parameter to an indexed column.
+var listSortColumns = map[string]string{
+	"date":   "created_at",
+	"energy": "energy_level",
+	"mood":   "mood_score",
+}
+
+// auraListScope validates the filter and turns it into query conditions
+// and an ORDER BY clause.
+func auraListScope(filter dto.AuraListFilter) (func(*gorm.DB) *gorm.DB, string, error) {
+	var conds []func(*gorm.DB) *gorm.DB
+	where := func(query string, arg any) {
+		conds = append(conds, func(db *gorm.DB) *gorm.DB { return db.Where(query, arg) })
+	}
+
+	if v := strings.TrimSpace(filter.Color); v != "" {
+		color := normalizeAuraColor(v)
+		if color == "" {
+			return nil, "", ErrInvalidListFilter
+		}
+		where("aura_color = ?", color)
+	}
+	if v := strings.TrimSpace(filter.From); v != "" {
+		from, _, err := parseListBound(v)
+		if err != nil {
+			return nil, "", ErrInvalidListFilter
+		}
+		where("created_at >= ?", from)
+	}
+	if v := strings.TrimSpace(filter.To); v != "" {
+		to, dateOnly, err := parseListBound(v)
+		if err != nil {
+			return nil, "", ErrInvalidListFilter
+		}
+		if dateOnly {
+			where("created_at < ?", to.AddDate(0, 0, 1))
+		} else {
+			where("created_at <= ?", to)
+		}
+	}
+	if v := strings.TrimSpace(filter.MinEnergy); v != "" {
+		minEnergy, err := strconv.Atoi(v)
+		if err != nil || minEnergy < 1 || minEnergy > 100 {
+			return nil, "", ErrInvalidListFilter
+		}
+		where("energy_level >= ?", minEnergy)
+	}
+	if filter.PinnedOnly {
+		conds = append(conds, func(db *gorm.DB) *gorm.DB { return db.Where("pinned") })
+	}
+	if v := strings.TrimSpace(filter.Trait); v != "" {
+		slug := filterTraitSlug(v)
+		if slug == "" {
+			return nil, "", ErrInvalidListFilter
+		}
+		where("id IN (SELECT rt.reading_id FROM reading_traits AS rt JOIN traits AS t ON t.id = rt.trait_id WHERE t.slug = ?)", slug)
+	}
+
+	order := defaultListOrder
+	direction := strings.ToUpper(strings.TrimSpace(filter.Order))
+	if direction != "" && direction != "ASC" && direction != "DESC" {
+		return nil, "", ErrInvalidListFilter
+	}
+	if v := strings.ToLower(strings.TrimSpace(filter.Sort)); v != "" {
+		column, ok := listSortColumns[v]
+		if !ok {
+			return nil, "", ErrInvalidListFilter
+		}
+		if direction == "" {
+			direction = "DESC"
+		}
+		order = column + " " + direction
+		if column != "created_at" {
+			order += ", created_at DESC"
+		}
+	} else if direction == "ASC" {
+		order = "created_at ASC"
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		for _, cond := range conds {
+			db = cond(db)
+		}
+		return db
+	}, order, nil
+}
+
+// parseListBound accepts a date (UTC midnight) or an RFC 3339 timestamp.
+func parseListBound(v string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	return t, false, err
+}
+
+// ListByCursor returns up to limit readings after cursor, ordered by
+// creation time (newest first unless the filter orders asc) with the ID as
+// tie-breaker, and the cursor for the next page. Unlike page offsets, a
+// cursor neither skips nor repeats readings when new scans arrive while the
+// client scrolls. An empty cursor starts from the beginning.
+func (s *AuraService) ListByCursor(userID uuid.UUID, cursor string, limit int, filter dto.AuraListFilter) ([]models.AuraReading, string, error) {
+	if v := strings.ToLower(strings.TrimSpace(filter.Sort)); v != "" && v != "date" {
+		return nil, "", ErrCursorSortUnsupported
+	}
+	if strings.TrimSpace(filter.Trait) != "" && !traitIndexReady(s.db) {
+		return nil, "", ErrTraitIndexNotReady
+	}
+	filterScope, _, err := auraListScope(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	direction, op := "DESC", "<"
+	if strings.EqualFold(strings.TrimSpace(filter.Order), "asc") {
+		direction, op = "ASC", ">"
+	}
+
+	query := s.db.Scopes(database.Replica, personalReadings, filterScope).Where("user_id = ?", userID)
+	if cursor != "" {
+		createdAt, id, err := decodeReadingCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Where("(created_at, id) "+op+" (?, ?)", createdAt, id)
+	}
+
+	var readings []models.AuraReading
+	err = query.Order("created_at " + direction + ", id " + direction).
+		Limit(limit + 1).
+		Find(&readings).Error
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(readings) > limit {
+		readings = readings[:limit]
+		last := readings[len(readings)-1]
+		next = encodeReadingCursor(last.CreatedAt, last.ID)
+	}
+	if err := readTraitsFromTable(s.db.Scopes(database.Replica).Session(&gorm.Session{}), readings); err != nil {
+		return nil, "", err
+	}
+	return readings, next, nil
+}
+
+// encodeReadingCursor packs a reading's position into an opaque token.
+func encodeReadingCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeReadingCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	ts, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	return createdAt, id, nil
+}
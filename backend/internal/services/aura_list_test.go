@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/google/uuid"
+)
+
+func TestAuraListScope(t *testing.T) {
+	for _, filter := range []dto.AuraListFilter{
+		{Color: "plaid"},
+		{From: "last week"},
+		{To: "2026-02-30"},
+		{MinEnergy: "0"},
+		{MinEnergy: "high"},
+		{Sort: "name"},
+		{Sort: "energy", Order: "sideways"},
+	} {
+		if _, _, err := auraListScope(filter); !errors.Is(err, ErrInvalidListFilter) {
+			t.Errorf("auraListScope(%+v) error = %v, want ErrInvalidListFilter", filter, err)
+		}
+	}
+
+	cases := []struct {
+		filter dto.AuraListFilter
+		order  string
+	}{
+		{dto.AuraListFilter{}, defaultListOrder},
+		{dto.AuraListFilter{Order: "asc"}, "created_at ASC"},
+		{dto.AuraListFilter{Sort: "date", Order: "asc"}, "created_at ASC"},
+		{dto.AuraListFilter{Sort: "Energy"}, "energy_level DESC, created_at DESC"},
+		{dto.AuraListFilter{Sort: "mood", Order: "asc", Color: "Blue", From: "2026-01-01", To: "2026-01-31T23:00:00Z", MinEnergy: "40"}, "mood_score ASC, created_at DESC"},
+	}
+	for _, tc := range cases {
+		_, order, err := auraListScope(tc.filter)
+		if err != nil {
+			t.Errorf("auraListScope(%+v) error = %v", tc.filter, err)
+			continue
+		}
+		if order != tc.order {
+			t.Errorf("auraListScope(%+v) order = %q, want %q", tc.filter, order, tc.order)
+		}
+	}
+}
+
+func TestReadingCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 3, 14, 9, 26, 53, 589793000, time.UTC)
+	id := uuid.New()
+	gotAt, gotID, err := decodeReadingCursor(encodeReadingCursor(createdAt, id))
+	if err != nil {
+		t.Fatalf("decodeReadingCursor error = %v", err)
+	}
+	if !gotAt.Equal(createdAt) || gotID != id {
+		t.Errorf("round trip = (%v, %v), want (%v, %v)", gotAt, gotID, createdAt, id)
+	}
+
+	for _, cursor := range []string{"not base64!", "bm8tc2VwYXJhdG9y", encodeReadingCursor(createdAt, id)[:10]} {
+		if _, _, err := decodeReadingCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("decodeReadingCursor(%q) error = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
@@ -2,30 +2,47 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
-	"math/rand"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrInvalidMatchFriend  = errors.New("invalid friend ID")
+	ErrSelfMatch           = errors.New("cannot match with yourself")
+	ErrFriendNoAuraReading = errors.New("friend doesn't have an aura reading yet")
+)
+
 type AuraMatchService struct {
-	db  *gorm.DB
-	cfg *config.Config
+	db            *gorm.DB
+	cfg           *config.Config
+	notifications *NotificationService
+	usage         *AIUsageService
+}
+
+func NewAuraMatchService(db *gorm.DB, cfg *config.Config, notifications *NotificationService) *AuraMatchService {
+	return &AuraMatchService{db: db, cfg: cfg, notifications: notifications}
 }
 
-func NewAuraMatchService(db *gorm.DB, cfg *config.Config) *AuraMatchService {
-	return &AuraMatchService{db: db, cfg: cfg}
+// UseAIUsage records the tokens and cost of match AI calls and holds them
+// to the daily AI budget.
+func (s *AuraMatchService) UseAIUsage(usage *AIUsageService) {
+	s.usage = usage
 }
 
 // Complementary color pairs for high compatibility
@@ -42,6 +59,38 @@ var complementaryColors = map[string]string{
 	"white":  "pink",
 }
 
+// Pairs whose energies clash; checked in both directions
+var challengingColors = map[string][]string{
+	"red":    {"orange", "gold"},
+	"blue":   {"indigo", "violet"},
+	"green":  {"pink", "white"},
+	"yellow": {"orange", "gold"},
+}
+
+// colorRelation classifies two aura colors as "same", "complementary",
+// "challenging" or "neutral" using the combination matrix above.
+func colorRelation(a, b string) string {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	switch {
+	case a == b:
+		return "same"
+	case complementaryColors[a] == b:
+		return "complementary"
+	case containsString(challengingColors[a], b) || containsString(challengingColors[b], a):
+		return "challenging"
+	}
+	return "neutral"
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
 // Synergy messages based on color combinations
 var synergyMessages = map[string]string{
 	"same":          "You share a deep soul connection! Your energies resonate on the same frequency.",
@@ -66,10 +115,10 @@ var adviceMessages = map[string]string{
 
 // compatibilityAIResult represents the JSON structure returned by OpenAI for match analysis
 type compatibilityAIResult struct {
-	CompatibilityScore int    `json:"compatibility_score"`
-	Synergy            string `json:"synergy"`
-	Tension            string `json:"tension"`
-	Advice             string `json:"advice"`
+	Synergy string `json:"synergy"`
+	Tension string `json:"tension"`
+	Advice  string `json:"advice"`
+	Blurb   string `json:"blurb"`
 }
 
 const matchSystemPrompt = `You are an aura compatibility analyst. You understand color theory, energy dynamics, and personality psychology as they relate to aura colors.
@@ -86,22 +135,25 @@ The AuraSnap aura color system includes these colors and their meanings:
 - Gold: Confident, abundant, empowered. Strengths: confidence, generosity. Challenges: ego.
 - Pink: Loving, gentle, compassionate. Strengths: empathy, nurturing. Challenges: lack of boundaries.
 
-Analyze the compatibility between two people based on their aura data. Consider:
+Explain the compatibility between two people based on their aura data. The compatibility score and its components (color affinity, energy alignment, mood correlation, each 0-100) have already been computed; explain them, never contradict or re-score them. Consider:
 1. Color theory: complementary colors (red-green, blue-orange, yellow-violet, indigo-gold, pink-white) have natural harmony.
 2. Energy levels: similar energy levels indicate natural rhythm compatibility; large gaps may cause friction.
-3. Mood alignment: similar mood scores suggest emotional resonance.
+3. Mood alignment: moods that move together suggest emotional resonance.
 4. Personality traits: look for complementary strengths and overlapping challenges.
 
 Return ONLY valid JSON with these exact fields:
-- compatibility_score: integer 0-100 (0=incompatible, 50=neutral, 80+=highly compatible, 95+=soulmate level)
+- blurb: one catchy sentence (max 25 words) summing up this relationship
 - synergy: 2-3 sentences describing the positive dynamics and strengths of this pairing
 - tension: 1-2 sentences about potential friction points or growth areas
 - advice: 1-2 sentences of practical relationship guidance for this specific pairing
 
 Be specific and personal — reference the actual colors, traits, and energy levels provided. Do not give generic responses.`
 
-func (s *AuraMatchService) calculateCompatibilityAI(userAura, friendAura models.AuraReading) (*compatibilityAIResult, error) {
-	userPrompt := fmt.Sprintf(`Analyze compatibility between these two auras:
+func (s *AuraMatchService) calculateCompatibilityAI(ctx context.Context, br compatibilityBreakdown, userAura, friendAura models.AuraReading) (*compatibilityAIResult, error) {
+	// Reading text can be model output shaped by user input, so it goes in as untrusted data.
+	userPrompt := fmt.Sprintf(`Compatibility score: %d/100 (color affinity %d, energy alignment %d, mood correlation %d; color relation: %s)
+
+Explain compatibility between these two auras:
 
 Person A:
 - Aura Color: %s
@@ -118,17 +170,22 @@ Person B:
 - Personality: %s
 - Strengths: %s
 - Challenges: %s`,
+		br.Score, br.ColorAffinity, br.EnergyAlignment, br.MoodCorrelation, br.Relation,
 		userAura.AuraColor, userAura.EnergyLevel, userAura.MoodScore,
-		userAura.Personality, strings.Join(userAura.Strengths, ", "), strings.Join(userAura.Challenges, ", "),
+		wrapUntrusted("personality", userAura.Personality, 500),
+		wrapUntrusted("strengths", strings.Join(userAura.Strengths, ", "), 300),
+		wrapUntrusted("challenges", strings.Join(userAura.Challenges, ", "), 300),
 		friendAura.AuraColor, friendAura.EnergyLevel, friendAura.MoodScore,
-		friendAura.Personality, strings.Join(friendAura.Strengths, ", "), strings.Join(friendAura.Challenges, ", "),
+		wrapUntrusted("personality", friendAura.Personality, 500),
+		wrapUntrusted("strengths", strings.Join(friendAura.Strengths, ", "), 300),
+		wrapUntrusted("challenges", strings.Join(friendAura.Challenges, ", "), 300),
 	)
 
 	// Reuse the OpenAI request/response types defined in aura_service.go (same package)
 	reqBody := openAIRequest{
 		Model: "gpt-4o-mini",
 		Messages: []openAIMessage{
-			{Role: "system", Content: matchSystemPrompt},
+			{Role: "system", Content: matchSystemPrompt + "\n\n" + untrustedInputInstruction},
 			{Role: "user", Content: userPrompt},
 		},
 		MaxTokens:   300,
@@ -138,169 +195,176 @@ Person B:
 		},
 	}
 
+	raw, err := s.chatCompletion(ctx, models.AIPurposeMatch, "openai_match", reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := extractJSONObject(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse compatibility JSON: %w", err)
+	}
+
+	var result compatibilityAIResult
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse compatibility JSON: %w", err)
+	}
+
+	// Validate non-empty text fields
+	if strings.TrimSpace(result.Synergy) == "" {
+		return nil, fmt.Errorf("AI returned empty synergy")
+	}
+	if strings.TrimSpace(result.Tension) == "" {
+		return nil, fmt.Errorf("AI returned empty tension")
+	}
+	if strings.TrimSpace(result.Advice) == "" {
+		return nil, fmt.Errorf("AI returned empty advice")
+	}
+	if strings.TrimSpace(result.Blurb) == "" {
+		return nil, fmt.Errorf("AI returned empty blurb")
+	}
+	result.Blurb = truncateRunes(strings.TrimSpace(result.Blurb), 300)
+
+	return &result, nil
+}
+
+func (s *AuraMatchService) chatCompletion(ctx context.Context, purpose, op string, reqBody openAIRequest) (string, error) {
+	return openAIChatCompletion(ctx, s.cfg.OpenAIAPIKey, s.usage, purpose, op, reqBody)
+}
+
+// openAIChatCompletion sends reqBody to the OpenAI chat API and returns the
+// first choice's content. op labels the span and provider latency metric;
+// the call is recorded in usage under purpose, and skipped with
+// errAIBudgetExceeded once the daily AI budget is spent.
+func openAIChatCompletion(ctx context.Context, apiKey string, usage *AIUsageService, purpose, op string, reqBody openAIRequest) (content string, err error) {
+	if usage.OverBudget(ctx) {
+		return "", errAIBudgetExceeded
+	}
+	var tokens aiTokenUsage
+	defer func(start time.Time) {
+		usage.Record(ctx, purpose, "openai", reqBody.Model, tokens, err, start)
+	}(time.Now())
+
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	client := &http.Client{Timeout: 30 * time.Second}
 
-	httpReq, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(bodyBytes))
+	ctx, span := tracer.Start(ctx, "aura.ai."+op, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("ai.provider", "openai"), attribute.String("ai.model", reqBody.Model)))
+	defer span.End()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(bodyBytes))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+s.cfg.OpenAIAPIKey)
+	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
 
+	start := time.Now()
 	resp, err := client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		metrics.AIProviderDuration.WithLabelValues(op, "error").Observe(time.Since(start).Seconds())
+		span.RecordError(err)
+		return "", fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	outcome := "success"
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		outcome = "error"
+	}
+	metrics.AIProviderDuration.WithLabelValues(op, outcome).Observe(time.Since(start).Seconds())
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI API returned status %d: %s", resp.StatusCode, string(respBody))
+		return "", fmt.Errorf("OpenAI API returned status %d: %s", resp.StatusCode, string(respBody))
 	}
 
 	var openAIResp openAIResponse
 	if err := json.Unmarshal(respBody, &openAIResp); err != nil {
-		return nil, fmt.Errorf("failed to parse OpenAI response: %w", err)
+		return "", fmt.Errorf("failed to parse OpenAI response: %w", err)
 	}
 
+	tokens = openAIResp.Usage
 	if openAIResp.Error != nil {
-		return nil, fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
+		return "", fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
 	}
 
 	if len(openAIResp.Choices) == 0 {
-		return nil, fmt.Errorf("OpenAI returned no choices")
+		return "", fmt.Errorf("OpenAI returned no choices")
 	}
 
-	content := openAIResp.Choices[0].Message.Content
-
-	var result compatibilityAIResult
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse compatibility JSON: %w", err)
-	}
-
-	// Validate compatibility_score is 0-100
-	if result.CompatibilityScore < 0 {
-		result.CompatibilityScore = 0
-	}
-	if result.CompatibilityScore > 100 {
-		result.CompatibilityScore = 100
-	}
-
-	// Validate non-empty text fields
-	if strings.TrimSpace(result.Synergy) == "" {
-		return nil, fmt.Errorf("AI returned empty synergy")
-	}
-	if strings.TrimSpace(result.Tension) == "" {
-		return nil, fmt.Errorf("AI returned empty tension")
-	}
-	if strings.TrimSpace(result.Advice) == "" {
-		return nil, fmt.Errorf("AI returned empty advice")
-	}
-
-	return &result, nil
+	return openAIResp.Choices[0].Message.Content, nil
 }
 
-func (s *AuraMatchService) calculateCompatibilityFallback(userColor, friendColor string) (int, string, string, string) {
-	var score int
-	var matchType string
-
-	// Same color = 85-100%
-	if userColor == friendColor {
-		score = 85 + rand.Intn(16)
-		matchType = "same"
-	} else if complementaryColors[userColor] == friendColor {
-		// Complementary colors = 70-90%
-		score = 70 + rand.Intn(21)
-		matchType = "complementary"
-	} else {
-		// Check if colors are "challenging"
-		challengingPairs := map[string][]string{
-			"red":    {"orange", "gold"},
-			"blue":   {"indigo", "violet"},
-			"green":  {"pink", "white"},
-			"yellow": {"orange", "gold"},
-		}
-
-		found := false
-		for color, challengers := range challengingPairs {
-			if userColor == color {
-				for _, c := range challengers {
-					if friendColor == c {
-						score = 30 + rand.Intn(31)
-						matchType = "challenging"
-						found = true
-						break
-					}
-				}
-			}
-			if found {
-				break
-			}
-		}
-
-		if !found {
-			// Neutral = 50-75%
-			score = 50 + rand.Intn(26)
-			matchType = "neutral"
-		}
-	}
-
+// calculateCompatibilityFallback writes the match text from templates when
+// the AI is unavailable.
+func (s *AuraMatchService) calculateCompatibilityFallback(br compatibilityBreakdown, userColor, friendColor string) *compatibilityAIResult {
+	matchType := br.Relation
 	synergy := fmt.Sprintf("%s Your %s aura meets their %s energy. %s", synergyMessages[matchType], userColor, friendColor, getSynergyDetail(userColor, friendColor))
 	tension := tensionMessages[matchType]
 	advice := adviceMessages[matchType]
 
-	return score, synergy, tension, advice
+	return &compatibilityAIResult{
+		Synergy: synergy,
+		Tension: tension,
+		Advice:  advice,
+		Blurb:   compatibilityBlurb(br, userColor, friendColor),
+	}
 }
 
-func (s *AuraMatchService) Create(userID uuid.UUID, req dto.CreateMatchRequest) (*dto.AuraMatchResponse, error) {
+func (s *AuraMatchService) Create(ctx context.Context, userID uuid.UUID, req dto.CreateMatchRequest) (*dto.AuraMatchResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuraMatchService.Create")
+	defer span.End()
+	db := s.db.WithContext(ctx)
+
 	friendID, err := uuid.Parse(req.FriendID)
 	if err != nil {
-		return nil, errors.New("invalid friend ID")
+		return nil, ErrInvalidMatchFriend
 	}
 
 	if userID == friendID {
-		return nil, errors.New("cannot match with yourself")
+		return nil, ErrSelfMatch
+	}
+
+	if err := checkMatchQuota(db, s.cfg, userID); err != nil {
+		return nil, err
 	}
 
 	// Get user's latest aura
 	var userAura models.AuraReading
-	if err := s.db.Where("user_id = ?", userID).Order("created_at DESC").First(&userAura).Error; err != nil {
-		return nil, errors.New("you need an aura reading first")
+	if err := db.Scopes(personalReadings).Where("user_id = ?", userID).Order("created_at DESC").First(&userAura).Error; err != nil {
+		return nil, ErrNoAuraReading
 	}
 
 	// Get friend's latest aura
 	var friendAura models.AuraReading
-	if err := s.db.Where("user_id = ?", friendID).Order("created_at DESC").First(&friendAura).Error; err != nil {
-		return nil, errors.New("friend doesn't have an aura reading yet")
+	if err := db.Scopes(activeUsersOnly("user_id"), personalReadings).Where("user_id = ?", friendID).Order("created_at DESC").First(&friendAura).Error; err != nil {
+		return nil, ErrFriendNoAuraReading
 	}
 
-	var score int
-	var synergy, tension, advice string
+	// The engine owns the score; the AI only writes the words around it.
+	br := computeCompatibility(db, userAura, friendAura)
 
-	// Try AI-powered analysis if API key is configured
+	var text *compatibilityAIResult
 	if s.cfg.OpenAIAPIKey != "" {
-		aiResult, err := s.calculateCompatibilityAI(userAura, friendAura)
+		aiResult, err := s.calculateCompatibilityAI(ctx, br, userAura, friendAura)
 		if err != nil {
-			log.Printf("OpenAI match API error, falling back to mock: %v", err)
-			score, synergy, tension, advice = s.calculateCompatibilityFallback(userAura.AuraColor, friendAura.AuraColor)
+			log.Printf("OpenAI match API error, falling back to templates: %v", err)
 		} else {
-			score = aiResult.CompatibilityScore
-			synergy = aiResult.Synergy
-			tension = aiResult.Tension
-			advice = aiResult.Advice
+			text = aiResult
 		}
-	} else {
-		score, synergy, tension, advice = s.calculateCompatibilityFallback(userAura.AuraColor, friendAura.AuraColor)
+	}
+	if text == nil {
+		text = s.calculateCompatibilityFallback(br, userAura.AuraColor, friendAura.AuraColor)
 	}
 
 	match := &models.AuraMatch{
@@ -308,30 +372,48 @@ func (s *AuraMatchService) Create(userID uuid.UUID, req dto.CreateMatchRequest)
 		FriendID:           friendID,
 		UserAuraID:         userAura.ID,
 		FriendAuraID:       friendAura.ID,
-		CompatibilityScore: score,
-		Synergy:            synergy,
-		Tension:            tension,
-		Advice:             advice,
+		CompatibilityScore: br.Score,
+		ColorAffinity:      br.ColorAffinity,
+		EnergyAlignment:    br.EnergyAlignment,
+		MoodCorrelation:    br.MoodCorrelation,
+		Blurb:              text.Blurb,
+		Synergy:            text.Synergy,
+		Tension:            text.Tension,
+		Advice:             text.Advice,
 	}
 
-	if err := s.db.Create(match).Error; err != nil {
+	if err := db.Create(match).Error; err != nil {
 		return nil, err
 	}
+	s.notifyMatchResult(match)
+
+	resp := matchResponse(match, userAura.AuraColor, friendAura.AuraColor, false)
+	return &resp, nil
+}
 
-	return &dto.AuraMatchResponse{
-		ID:                 match.ID,
-		UserID:             match.UserID,
-		FriendID:           match.FriendID,
-		UserAuraID:         match.UserAuraID,
-		FriendAuraID:       match.FriendAuraID,
-		CompatibilityScore: match.CompatibilityScore,
-		Synergy:            match.Synergy,
-		Tension:            match.Tension,
-		Advice:             match.Advice,
-		UserAuraColor:      userAura.AuraColor,
-		FriendAuraColor:    friendAura.AuraColor,
-		CreatedAt:          match.CreatedAt,
-	}, nil
+// notifyMatchResult tells the friend someone checked compatibility with them.
+func (s *AuraMatchService) notifyMatchResult(match *models.AuraMatch) {
+	if s.notifications == nil {
+		return
+	}
+	name := "A friend"
+	var user models.User
+	if err := s.db.Select("handle").First(&user, "id = ?", match.UserID).Error; err == nil && user.Handle != nil {
+		name = "@" + *user.Handle
+	}
+	err := s.notifications.NotifyLater(context.Background(), match.FriendID, NotificationMessage{
+		Category: CategorySocial,
+		Title:    "New aura match",
+		Body:     fmt.Sprintf("%s matched auras with you: %d%% compatible.", name, match.CompatibilityScore),
+		Data: map[string]string{
+			"event":     "match_result",
+			"match_id":  match.ID.String(),
+			"friend_id": match.UserID.String(),
+		},
+	})
+	if err != nil {
+		log.Printf("match notification for %s: %v", match.FriendID, err)
+	}
 }
 
 func getSynergyDetail(color1, color2 string) string {
@@ -352,10 +434,11 @@ func getSynergyDetail(color1, color2 string) string {
 
 func (s *AuraMatchService) List(userID uuid.UUID) ([]dto.AuraMatchResponse, error) {
 	var matches []models.AuraMatch
-	if err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&matches).Error; err != nil {
+	if err := s.db.Scopes(activeUsersOnly("friend_id")).Where("user_id = ?", userID).Order("created_at DESC").Find(&matches).Error; err != nil {
 		return nil, err
 	}
 
+	premium := entitlementsFor(s.db, s.cfg, userID).MatchNarrative
 	responses := make([]dto.AuraMatchResponse, len(matches))
 	for i, m := range matches {
 		// Get aura colors
@@ -363,20 +446,7 @@ func (s *AuraMatchService) List(userID uuid.UUID) ([]dto.AuraMatchResponse, erro
 		s.db.First(&userAura, "id = ?", m.UserAuraID)
 		s.db.First(&friendAura, "id = ?", m.FriendAuraID)
 
-		responses[i] = dto.AuraMatchResponse{
-			ID:                 m.ID,
-			UserID:             m.UserID,
-			FriendID:           m.FriendID,
-			UserAuraID:         m.UserAuraID,
-			FriendAuraID:       m.FriendAuraID,
-			CompatibilityScore: m.CompatibilityScore,
-			Synergy:            m.Synergy,
-			Tension:            m.Tension,
-			Advice:             m.Advice,
-			UserAuraColor:      userAura.AuraColor,
-			FriendAuraColor:    friendAura.AuraColor,
-			CreatedAt:          m.CreatedAt,
-		}
+		responses[i] = matchResponse(&matches[i], userAura.AuraColor, friendAura.AuraColor, premium)
 	}
 
 	return responses, nil
@@ -384,7 +454,7 @@ func (s *AuraMatchService) List(userID uuid.UUID) ([]dto.AuraMatchResponse, erro
 
 func (s *AuraMatchService) GetByFriend(userID, friendID uuid.UUID) (*dto.AuraMatchResponse, error) {
 	var match models.AuraMatch
-	if err := s.db.Where("user_id = ? AND friend_id = ?", userID, friendID).
+	if err := s.db.Scopes(activeUsersOnly("friend_id")).Where("user_id = ? AND friend_id = ?", userID, friendID).
 		Order("created_at DESC").First(&match).Error; err != nil {
 		return nil, err
 	}
@@ -393,18 +463,39 @@ func (s *AuraMatchService) GetByFriend(userID, friendID uuid.UUID) (*dto.AuraMat
 	s.db.First(&userAura, "id = ?", match.UserAuraID)
 	s.db.First(&friendAura, "id = ?", match.FriendAuraID)
 
-	return &dto.AuraMatchResponse{
-		ID:                 match.ID,
-		UserID:             match.UserID,
-		FriendID:           match.FriendID,
-		UserAuraID:         match.UserAuraID,
-		FriendAuraID:       match.FriendAuraID,
-		CompatibilityScore: match.CompatibilityScore,
-		Synergy:            match.Synergy,
-		Tension:            match.Tension,
-		Advice:             match.Advice,
-		UserAuraColor:      userAura.AuraColor,
-		FriendAuraColor:    friendAura.AuraColor,
-		CreatedAt:          match.CreatedAt,
-	}, nil
+	resp := matchResponse(&match, userAura.AuraColor, friendAura.AuraColor, entitlementsFor(s.db, s.cfg, userID).MatchNarrative)
+	return &resp, nil
+}
+
+// matchResponse converts a match to its API form. The cached narrative is
+// only included for premium users.
+func matchResponse(m *models.AuraMatch, userColor, friendColor string, premium bool) dto.AuraMatchResponse {
+	resp := dto.AuraMatchResponse{
+		ID:                 m.ID,
+		UserID:             m.UserID,
+		FriendID:           m.FriendID,
+		UserAuraID:         m.UserAuraID,
+		FriendAuraID:       m.FriendAuraID,
+		CompatibilityScore: m.CompatibilityScore,
+		Synergy:            m.Synergy,
+		Tension:            m.Tension,
+		Advice:             m.Advice,
+		Blurb:              m.Blurb,
+		UserAuraColor:      userColor,
+		FriendAuraColor:    friendColor,
+		CreatedAt:          m.CreatedAt,
+	}
+	// Matches created before the engine have no component scores.
+	if m.ColorAffinity > 0 {
+		resp.Breakdown = &dto.CompatibilityBreakdown{
+			ColorAffinity:   m.ColorAffinity,
+			EnergyAlignment: m.EnergyAlignment,
+			MoodCorrelation: m.MoodCorrelation,
+		}
+	}
+	if premium {
+		resp.Narrative = m.Narrative
+		resp.NarrativeGeneratedAt = m.NarrativeGeneratedAt
+	}
+	return resp
 }
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidNote = errors.New("notes are limited to 2000 characters and up to 5 known mood tags")
+
+// moodTags are the mood chips the app offers in the journal.
+var moodTags = []string{
+	"calm", "happy", "grateful", "energized", "focused", "hopeful",
+	"tired", "anxious", "stressed", "sad", "irritable", "overwhelmed",
+}
+
+const (
+	maxNoteRunes = 2000
+	maxMoodTags  = 5
+)
+
+// SetNote stores the owner's private journal note and mood tags on a
+// reading, replacing any earlier note. An empty note with no tags clears it.
+func (s *AuraService) SetNote(userID, readingID uuid.UUID, req dto.ReadingNoteRequest) (*models.AuraReading, error) {
+	note := strings.TrimSpace(req.Note)
+	tags, ok := normalizeMoodTags(req.MoodTags)
+	if !ok || utf8.RuneCountInString(note) > maxNoteRunes {
+		return nil, ErrInvalidNote
+	}
+
+	var reading models.AuraReading
+	if err := s.db.Where("user_id = ? AND id = ?", userID, readingID).First(&reading).Error; err != nil {
+		return nil, ErrReadingNotFound
+	}
+
+	reading.Note = note
+	reading.MoodTags = tags
+	reading.NotedAt = nil
+	if note != "" || len(tags) > 0 {
+		now := time.Now()
+		reading.NotedAt = &now
+	}
+	if err := s.db.Model(&reading).Select("note", "mood_tags", "noted_at").Updates(&reading).Error; err != nil {
+		return nil, err
+	}
+	return &reading, nil
+}
+
+// normalizeMoodTags lower-cases tags, drops duplicates and rejects unknown
+// tags. No tags is nil, so a cleared note stores no tag list.
+func normalizeMoodTags(raw []string) ([]string, bool) {
+	var tags []string
+	for _, tag := range raw {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !containsString(moodTags, tag) {
+			return nil, false
+		}
+		if !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > maxMoodTags {
+		return nil, false
+	}
+	return tags, true
+}
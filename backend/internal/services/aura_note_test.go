@@ -0,0 +1,22 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeMoodTags(t *testing.T) {
+	tags, ok := normalizeMoodTags([]string{" Calm", "grateful", "calm"})
+	if !ok || strings.Join(tags, ",") != "calm,grateful" {
+		t.Errorf("tags = %v, %v", tags, ok)
+	}
+	if tags, ok := normalizeMoodTags(nil); !ok || tags != nil {
+		t.Errorf("no tags = %v, %v", tags, ok)
+	}
+	if _, ok := normalizeMoodTags([]string{"ecstatic"}); ok {
+		t.Error("unknown tag accepted")
+	}
+	if _, ok := normalizeMoodTags([]string{"calm", "happy", "grateful", "energized", "focused", "hopeful"}); ok {
+		t.Error("more than maxMoodTags accepted")
+	}
+}
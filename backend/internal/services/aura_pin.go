@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxPinnedReadings keeps pinned readings a short favorites list rather than
+// a second history.
+const maxPinnedReadings = 50
+
+var ErrTooManyPins = errors.New("you can pin up to 50 readings; unpin one first")
+
+// SetPinned pins or unpins one of the user's readings. Pinned readings list
+// first, most recently pinned first. Pinning a pinned reading is a no-op.
+func (s *AuraService) SetPinned(userID, readingID uuid.UUID, pinned bool) (*models.AuraReading, error) {
+	var reading models.AuraReading
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the user row so concurrent pins can't pass the cap together.
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.User{}, "id = ?", userID).Error; err != nil {
+			return ErrReadingNotFound
+		}
+		if err := tx.Scopes(personalReadings).Where("user_id = ? AND id = ?", userID, readingID).First(&reading).Error; err != nil {
+			return ErrReadingNotFound
+		}
+		if reading.Pinned == pinned {
+			return nil
+		}
+
+		if pinned {
+			var count int64
+			if err := tx.Model(&models.AuraReading{}).Where("user_id = ? AND pinned", userID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count >= maxPinnedReadings {
+				return ErrTooManyPins
+			}
+			now := time.Now()
+			reading.PinnedAt = &now
+		} else {
+			reading.PinnedAt = nil
+		}
+		reading.Pinned = pinned
+		return tx.Model(&reading).Select("pinned", "pinned_at").Updates(&reading).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reading, nil
+}
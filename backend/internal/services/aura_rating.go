@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidRating = errors.New("stars must be between 1 and 5 and tags must be known")
+
+// ratingTags are the feedback chips the app offers after a scan.
+var ratingTags = []string{
+	"spot_on", "too_generic", "wrong_color", "too_negative",
+	"too_positive", "energy_off", "mood_off", "too_long",
+}
+
+const maxRatingTags = 4
+
+// minRatingsToLead is the sample size every variant needs before one is
+// marked as leading the experiment.
+const minRatingsToLead = 30
+
+// readingProviderExpr labels a reading by its source: the AI provider name,
+// "mock" when the deterministic engine stood in for a failed AI call, or
+// "deterministic". Readings from before sources were recorded are labelled
+// by the provenance of their text.
+const readingProviderExpr = "COALESCE(NULLIF(source, ''), NULLIF(provenance->>'personality', ''), 'deterministic')"
+
+// RateReading stores the owner's 1-5 star accuracy rating and tags on the
+// reading. Rating again replaces the earlier rating.
+func (s *AuraService) RateReading(userID, readingID uuid.UUID, req dto.RateReadingRequest) (*models.AuraReading, error) {
+	tags, ok := normalizeRatingTags(req.Tags)
+	if req.Stars < 1 || req.Stars > 5 || !ok {
+		return nil, ErrInvalidRating
+	}
+
+	var reading models.AuraReading
+	if err := s.db.Where("user_id = ? AND id = ?", userID, readingID).First(&reading).Error; err != nil {
+		return nil, ErrReadingNotFound
+	}
+
+	now := time.Now()
+	reading.Rating = &req.Stars
+	reading.RatingTags = tags
+	reading.RatedAt = &now
+	if err := s.db.Model(&reading).Select("rating", "rating_tags", "rated_at").Updates(&reading).Error; err != nil {
+		return nil, err
+	}
+	return &reading, nil
+}
+
+// normalizeRatingTags lower-cases tags, accepts "too generic" for
+// "too_generic", drops duplicates and rejects unknown tags.
+func normalizeRatingTags(raw []string) ([]string, bool) {
+	tags := []string{}
+	for _, tag := range raw {
+		tag = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), " ", "_")
+		tag = strings.ReplaceAll(tag, "-", "_")
+		if !containsString(ratingTags, tag) {
+			return nil, false
+		}
+		if !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > maxRatingTags {
+		return nil, false
+	}
+	return tags, true
+}
+
+// RatingStats aggregates self-ratings per prompt variant and provider.
+func (s *AuraService) RatingStats() ([]dto.ReadingRatingStats, error) {
+	var rows []struct {
+		PromptVariant string
+		Provider      string
+		Rating        int
+		RatingTags    []string `gorm:"serializer:json"`
+	}
+	if err := s.db.Model(&models.AuraReading{}).
+		Select("COALESCE(NULLIF(prompt_variant, ''), ?) AS prompt_variant, "+readingProviderExpr+" AS provider, rating, rating_tags", promptVariantBaseline).
+		Where("rating IS NOT NULL AND NOT synthetic").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	type key struct{ variant, provider string }
+	byKey := map[key]*dto.ReadingRatingStats{}
+	sums := map[key]int{}
+	for _, row := range rows {
+		k := key{row.PromptVariant, row.Provider}
+		st, ok := byKey[k]
+		if !ok {
+			st = &dto.ReadingRatingStats{
+				Variant:      row.PromptVariant,
+				Provider:     row.Provider,
+				Distribution: map[int]int64{1: 0, 2: 0, 3: 0, 4: 0, 5: 0},
+				Tags:         map[string]int64{},
+			}
+			byKey[k] = st
+		}
+		st.Ratings++
+		st.Distribution[row.Rating]++
+		sums[k] += row.Rating
+		for _, tag := range row.RatingTags {
+			st.Tags[tag]++
+		}
+	}
+
+	stats := make([]dto.ReadingRatingStats, 0, len(byKey))
+	for k, st := range byKey {
+		st.AverageRating = float64(sums[k]) / float64(st.Ratings)
+		stats = append(stats, *st)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Variant != stats[j].Variant {
+			return stats[i].Variant < stats[j].Variant
+		}
+		return stats[i].Provider < stats[j].Provider
+	})
+	return stats, nil
+}
+
+// markLeadingVariant flags the variant with the best average rating, but
+// only once every variant has at least minRatingsToLead ratings.
+func markLeadingVariant(stats []dto.PromptVariantStats) {
+	if len(stats) < 2 {
+		return
+	}
+	best := -1
+	for i, st := range stats {
+		if st.Ratings < minRatingsToLead || st.AverageRating == nil {
+			return
+		}
+		if best < 0 || *st.AverageRating > *stats[best].AverageRating {
+			best = i
+		}
+	}
+	stats[best].Leading = true
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+)
+
+func TestNormalizeRatingTags(t *testing.T) {
+	tags, ok := normalizeRatingTags([]string{"Too generic", "too_generic", "energy-off"})
+	if !ok || !reflect.DeepEqual(tags, []string{"too_generic", "energy_off"}) {
+		t.Fatalf("got %v, %v", tags, ok)
+	}
+	if _, ok := normalizeRatingTags([]string{"boring"}); ok {
+		t.Fatal("unknown tag should be rejected")
+	}
+	if _, ok := normalizeRatingTags([]string{"spot_on", "too_generic", "wrong_color", "too_negative", "too_long"}); ok {
+		t.Fatal("too many tags should be rejected")
+	}
+}
+
+func TestMarkLeadingVariant(t *testing.T) {
+	avg := func(v float64) *float64 { return &v }
+
+	stats := []dto.PromptVariantStats{
+		{Variant: "baseline", Ratings: 40, AverageRating: avg(3.6)},
+		{Variant: "history", Ratings: 35, AverageRating: avg(4.1)},
+	}
+	markLeadingVariant(stats)
+	if stats[0].Leading || !stats[1].Leading {
+		t.Fatalf("expected history to lead: %+v", stats)
+	}
+
+	stats = []dto.PromptVariantStats{
+		{Variant: "baseline", Ratings: 40, AverageRating: avg(3.6)},
+		{Variant: "history", Ratings: 5, AverageRating: avg(4.9)},
+	}
+	markLeadingVariant(stats)
+	if stats[0].Leading || stats[1].Leading {
+		t.Fatalf("no variant should lead with too few ratings: %+v", stats)
+	}
+}
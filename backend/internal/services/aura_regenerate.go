@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrRegenerateUnavailable  = errors.New("readings can't be regenerated right now; try again later")
+	ErrGroupReadingRegenerate = errors.New("readings from group scans can't be regenerated")
+)
+
+// Regenerate re-runs the AI analysis on a reading's stored image and
+// replaces its result, keeping the previous result as a version. It is a
+// paid feature; the handler counts it against the daily scan quota, which
+// includes regenerations. A failed analysis leaves the reading unchanged and
+// uses no scan.
+func (s *AuraService) Regenerate(ctx context.Context, userID, readingID uuid.UUID, locale string) (*models.AuraReading, error) {
+	ctx, span := tracer.Start(ctx, "AuraService.Regenerate")
+	defer span.End()
+	ctx = withAIUser(ctx, s.db, userID)
+	db := s.db.WithContext(ctx)
+
+	if entitlementsFor(db, s.cfg, userID).Tier == TierFree {
+		s.analytics.Emit(userID, EventPaywallHit, map[string]any{"feature": "regenerate"})
+		return nil, ErrUpgradeRequired
+	}
+
+	var current models.AuraReading
+	if err := db.Where("user_id = ? AND id = ?", userID, readingID).First(&current).Error; err != nil {
+		return nil, ErrReadingNotFound
+	}
+	if err := regenerable(&current); err != nil {
+		return nil, err
+	}
+	if locale == "" {
+		locale = current.Language
+	}
+
+	_, opts := s.analysisOptions(ctx, db, userID, locale)
+	// A fresh seed, so the provider does not hand back the same reading.
+	seed := rand.Int64N(1<<53) + 1
+	opts.Seed = &seed
+	base := deterministicAuraResult(userID, photoKey(current.ImageURL, current.ImageHash))
+	started := time.Now()
+	draft, err := s.analyzer.analyze(ctx, current.ImageURL, base, opts)
+	source, latency := scanSource(draft.Source, err, started)
+	if err != nil {
+		span.RecordError(err)
+		metrics.ScansTotal.WithLabelValues("regenerate_failed").Inc()
+		return nil, ErrRegenerateUnavailable
+	}
+
+	var reading models.AuraReading
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND id = ?", userID, readingID).First(&reading).Error; err != nil {
+			return ErrReadingNotFound
+		}
+		if err := regenerable(&reading); err != nil {
+			return err
+		}
+
+		var versions int64
+		if err := tx.Model(&models.ReadingVersion{}).Where("reading_id = ?", reading.ID).Count(&versions).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.ReadingVersion{
+			ReadingID:      reading.ID,
+			UserID:         reading.UserID,
+			Version:        int(versions) + 1,
+			AuraColor:      reading.AuraColor,
+			SecondaryColor: reading.SecondaryColor,
+			EnergyLevel:    reading.EnergyLevel,
+			MoodScore:      reading.MoodScore,
+			Personality:    reading.Personality,
+			Strengths:      reading.Strengths,
+			Challenges:     reading.Challenges,
+			DailyAdvice:    reading.DailyAdvice,
+			Provenance:     reading.Provenance,
+			Language:       reading.Language,
+			AnalyzedAt:     reading.AnalyzedAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		reading.AuraColor = draft.Scores.AuraColor
+		reading.SecondaryColor = draft.Scores.SecondaryColor
+		reading.EnergyLevel = clamp(draft.Scores.EnergyLevel, 1, 100)
+		reading.MoodScore = clamp(draft.Scores.MoodScore, 1, 10)
+		reading.Personality = draft.Personality
+		reading.Strengths = draft.Strengths
+		reading.Challenges = draft.Challenges
+		reading.DailyAdvice = draft.DailyAdvice
+		reading.Provenance = draft.Provenance
+		reading.PromptTemplateID = draft.PromptTemplateID
+		reading.Source, reading.ProviderLatencyMs = source, latency
+		reading.Language = opts.Language
+		reading.AnalyzedAt = time.Now()
+		if err := tx.Model(&reading).Select("aura_color", "secondary_color", "energy_level", "mood_score",
+			"personality", "strengths", "challenges", "daily_advice", "provenance", "source", "provider_latency_ms", "prompt_template_id", "language", "analyzed_at").
+			Updates(&reading).Error; err != nil {
+			return err
+		}
+		if err := dualWriteReadingTraits(tx, reading.ID); err != nil {
+			return err
+		}
+		return refreshUserAggregate(tx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Where("reading_id = ?", reading.ID).Order("version DESC").Find(&reading.Versions).Error; err != nil {
+		return nil, err
+	}
+	metrics.ScansTotal.WithLabelValues("regenerated").Inc()
+	return &reading, nil
+}
+
+// regenerable rejects readings whose result can't be replaced.
+func regenerable(reading *models.AuraReading) error {
+	if reading.GroupReadingID != nil {
+		return ErrGroupReadingRegenerate
+	}
+	// The background analysis would overwrite the regenerated result.
+	if reading.Status == models.ReadingStatusPending {
+		return ErrReadingPending
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestRegenerable(t *testing.T) {
+	groupID := uuid.New()
+	cases := []struct {
+		name    string
+		reading models.AuraReading
+		want    error
+	}{
+		{"ready", models.AuraReading{Status: models.ReadingStatusReady}, nil},
+		{"pending", models.AuraReading{Status: models.ReadingStatusPending}, ErrReadingPending},
+		{"group member", models.AuraReading{Status: models.ReadingStatusReady, GroupReadingID: &groupID}, ErrGroupReadingRegenerate},
+	}
+	for _, tc := range cases {
+		if err := regenerable(&tc.reading); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
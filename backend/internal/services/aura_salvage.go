@@ -0,0 +1,277 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/i18n"
+	"github.com/google/uuid"
+)
+
+// Provenance values recorded per reading field.
+const (
+	provenanceDeterministic = "deterministic"
+)
+
+// auraReadingFields lists every reading field tracked in provenance.
+var auraReadingFields = []string{
+	"aura_color", "secondary_color", "energy_level", "mood_score",
+	"personality", "strengths", "challenges", "daily_advice",
+}
+
+// auraReadingDraft is a reading assembled from AI output and the deterministic engine,
+// with a record of which source produced each field.
+type auraReadingDraft struct {
+	Scores      auraAnalysisResult
+	Personality string
+	Strengths   []string
+	Challenges  []string
+	DailyAdvice string
+	Provenance  map[string]string
+	// Source is the provider that answered, or deterministic.
+	Source string
+	// PromptTemplateID is the prompt template version the AI was given;
+	// nil for the built-in prompt.
+	PromptTemplateID *uuid.UUID
+}
+
+// auraAIPartial holds the fields that individually validated in an AI response.
+// A nil pointer or slice means the field was missing or malformed.
+type auraAIPartial struct {
+	AuraColor      *string
+	SecondaryColor **string
+	EnergyLevel    *int
+	MoodScore      *int
+	Personality    *string
+	Strengths      []string
+	Challenges     []string
+	DailyAdvice    *string
+}
+
+func (p auraAIPartial) empty() bool {
+	return p.AuraColor == nil && p.SecondaryColor == nil && p.EnergyLevel == nil && p.MoodScore == nil &&
+		p.Personality == nil && p.Strengths == nil && p.Challenges == nil && p.DailyAdvice == nil
+}
+
+// deterministicDraft builds a complete reading from the deterministic engine alone.
+func deterministicDraft(base auraAnalysisResult) auraReadingDraft {
+	draft := auraReadingDraft{Scores: base, Provenance: make(map[string]string, len(auraReadingFields)), Source: provenanceDeterministic}
+	for _, field := range auraReadingFields {
+		draft.Provenance[field] = provenanceDeterministic
+	}
+	fillDraftTraits(&draft, nil)
+	return draft
+}
+
+// parseAuraAIPartial validates each field of an AI response independently so a
+// single malformed value (e.g. strengths returned as a string) does not discard
+// the rest of the response.
+func parseAuraAIPartial(content string) (auraAIPartial, error) {
+	raw, err := extractJSONObject(content)
+	if err != nil {
+		return auraAIPartial{}, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return auraAIPartial{}, err
+	}
+
+	var partial auraAIPartial
+
+	if v, ok := rawString(fields["aura_color"]); ok {
+		if color := normalizeAuraColor(v); color != "" {
+			partial.AuraColor = &color
+		}
+	}
+	if msg, ok := fields["secondary_color"]; ok {
+		if string(msg) == "null" {
+			var none *string
+			partial.SecondaryColor = &none
+		} else if v, ok := rawString(msg); ok {
+			t := strings.ToLower(strings.TrimSpace(v))
+			var secondary *string
+			if t != "" && t != "null" && t != "none" {
+				secondary = &t
+			}
+			partial.SecondaryColor = &secondary
+		}
+	}
+	if v, ok := rawInt(fields["energy_level"]); ok && v > 0 {
+		v = clamp(v, 1, 100)
+		partial.EnergyLevel = &v
+	}
+	if v, ok := rawInt(fields["mood_score"]); ok && v > 0 {
+		v = clamp(v, 1, 10)
+		partial.MoodScore = &v
+	}
+	if v, ok := rawString(fields["personality"]); ok && strings.TrimSpace(v) != "" {
+		v = strings.TrimSpace(v)
+		partial.Personality = &v
+	}
+	partial.Strengths = rawStringList(fields["strengths"])
+	partial.Challenges = rawStringList(fields["challenges"])
+	if v, ok := rawString(fields["daily_advice"]); ok && strings.TrimSpace(v) != "" {
+		v = strings.TrimSpace(v)
+		partial.DailyAdvice = &v
+	}
+
+	if partial.empty() {
+		return partial, errors.New("no usable fields in aura ai response")
+	}
+	return partial, nil
+}
+
+// salvageAuraDraft overlays the valid AI fields onto the deterministic result.
+// Descriptive text is only kept when the AI color itself was valid; otherwise
+// the text would describe a color the reading does not have.
+func salvageAuraDraft(base auraAnalysisResult, partial auraAIPartial, source string) auraReadingDraft {
+	draft := deterministicDraft(base)
+	ai := make(map[string]bool, len(auraReadingFields))
+
+	if partial.AuraColor != nil {
+		draft.Scores.AuraColor = *partial.AuraColor
+		ai["aura_color"] = true
+	}
+	if partial.SecondaryColor != nil {
+		draft.Scores.SecondaryColor = *partial.SecondaryColor
+		ai["secondary_color"] = true
+	}
+	if partial.EnergyLevel != nil {
+		draft.Scores.EnergyLevel = *partial.EnergyLevel
+		ai["energy_level"] = true
+	}
+	if partial.MoodScore != nil {
+		draft.Scores.MoodScore = *partial.MoodScore
+		ai["mood_score"] = true
+	}
+
+	if partial.AuraColor != nil {
+		if partial.Personality != nil {
+			draft.Personality = *partial.Personality
+			ai["personality"] = true
+		}
+		if partial.Strengths != nil {
+			draft.Strengths = partial.Strengths
+			ai["strengths"] = true
+		}
+		if partial.Challenges != nil {
+			draft.Challenges = partial.Challenges
+			ai["challenges"] = true
+		}
+		if partial.DailyAdvice != nil {
+			draft.DailyAdvice = *partial.DailyAdvice
+			ai["daily_advice"] = true
+		}
+	}
+
+	fillDraftTraits(&draft, ai)
+	for field := range ai {
+		draft.Provenance[field] = source
+	}
+	draft.Source = source
+	return draft
+}
+
+// fillDraftTraits fills every descriptive field not produced by the AI from the
+// color trait table, keyed on the final aura color.
+func fillDraftTraits(draft *auraReadingDraft, fromAI map[string]bool) {
+	traits, ok := colorTraits[draft.Scores.AuraColor]
+	if !ok {
+		draft.Scores.AuraColor = "violet"
+		traits = colorTraits["violet"]
+	}
+
+	if !fromAI["personality"] {
+		draft.Personality = traits.personality
+	}
+	if !fromAI["strengths"] {
+		draft.Strengths = traits.strengths
+	}
+	if !fromAI["challenges"] {
+		draft.Challenges = traits.challenges
+	}
+	if !fromAI["daily_advice"] {
+		draft.DailyAdvice = traits.dailyAdvice
+	}
+}
+
+// localizeDraft rewrites the descriptive fields that came from the trait
+// table in locale. Fields the AI wrote are already in the prompt's language.
+func localizeDraft(draft *auraReadingDraft, locale string) {
+	if locale == "" || locale == i18n.DefaultLocale {
+		return
+	}
+	traits, ok := i18n.Traits(locale, draft.Scores.AuraColor)
+	if !ok {
+		return
+	}
+	if draft.Provenance["personality"] == provenanceDeterministic {
+		draft.Personality = traits.Personality
+	}
+	if draft.Provenance["strengths"] == provenanceDeterministic {
+		draft.Strengths = traits.Strengths
+	}
+	if draft.Provenance["challenges"] == provenanceDeterministic {
+		draft.Challenges = traits.Challenges
+	}
+	if draft.Provenance["daily_advice"] == provenanceDeterministic {
+		draft.DailyAdvice = traits.DailyAdvice
+	}
+}
+
+func rawString(msg json.RawMessage) (string, bool) {
+	if len(msg) == 0 {
+		return "", false
+	}
+	var v string
+	if err := json.Unmarshal(msg, &v); err != nil {
+		return "", false
+	}
+	return v, true
+}
+
+// rawInt accepts integers, floats ("72.5") and numeric strings ("72").
+func rawInt(msg json.RawMessage) (int, bool) {
+	if len(msg) == 0 {
+		return 0, false
+	}
+	var f float64
+	if err := json.Unmarshal(msg, &f); err == nil {
+		return int(math.Round(f)), true
+	}
+	if s, ok := rawString(msg); ok {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return int(math.Round(f)), true
+		}
+	}
+	return 0, false
+}
+
+// rawStringList accepts a JSON array of 1-6 non-empty strings. Anything else
+// (a comma-joined string, objects, empty items) is treated as malformed.
+func rawStringList(msg json.RawMessage) []string {
+	if len(msg) == 0 {
+		return nil
+	}
+	var items []string
+	if err := json.Unmarshal(msg, &items); err != nil {
+		return nil
+	}
+
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			return nil
+		}
+		out = append(out, item)
+	}
+	if len(out) == 0 || len(out) > 6 {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/i18n"
+)
+
+func TestSalvageKeepsValidFieldsWhenStrengthsMalformed(t *testing.T) {
+	content := `{"aura_color":"blue","energy_level":"72","mood_score":8.4,"personality":"Calm and grounded.","strengths":"Loyalty, Intuition","challenges":["Overthinking","Shyness","Doubt"]}`
+
+	partial, err := parseAuraAIPartial(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	base := auraAnalysisResult{AuraColor: "red", EnergyLevel: 50, MoodScore: 5}
+	draft := salvageAuraDraft(base, partial, "glm")
+
+	if draft.Scores.AuraColor != "blue" || draft.Scores.EnergyLevel != 72 || draft.Scores.MoodScore != 8 {
+		t.Fatalf("unexpected salvaged scores: %+v", draft.Scores)
+	}
+	if draft.Personality != "Calm and grounded." {
+		t.Fatalf("expected AI personality, got %q", draft.Personality)
+	}
+	if len(draft.Strengths) != len(colorTraits["blue"].strengths) || draft.Strengths[0] != colorTraits["blue"].strengths[0] {
+		t.Fatalf("expected blue trait strengths, got %v", draft.Strengths)
+	}
+	if draft.Challenges[0] != "Overthinking" {
+		t.Fatalf("expected AI challenges, got %v", draft.Challenges)
+	}
+
+	expected := map[string]string{
+		"aura_color":      "glm",
+		"secondary_color": provenanceDeterministic,
+		"energy_level":    "glm",
+		"mood_score":      "glm",
+		"personality":     "glm",
+		"strengths":       provenanceDeterministic,
+		"challenges":      "glm",
+		"daily_advice":    provenanceDeterministic,
+	}
+	for field, source := range expected {
+		if draft.Provenance[field] != source {
+			t.Fatalf("provenance[%s]: expected %s, got %s", field, source, draft.Provenance[field])
+		}
+	}
+}
+
+func TestSalvageDropsTextWhenColorInvalid(t *testing.T) {
+	partial, err := parseAuraAIPartial(`{"aura_color":"turquoise","energy_level":90,"personality":"Electric."}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	draft := salvageAuraDraft(auraAnalysisResult{AuraColor: "green", EnergyLevel: 40, MoodScore: 6}, partial, "deepseek")
+
+	if draft.Scores.AuraColor != "green" || draft.Provenance["aura_color"] != provenanceDeterministic {
+		t.Fatalf("expected deterministic color, got %s (%s)", draft.Scores.AuraColor, draft.Provenance["aura_color"])
+	}
+	if draft.Scores.EnergyLevel != 90 || draft.Provenance["energy_level"] != "deepseek" {
+		t.Fatalf("expected salvaged energy, got %d", draft.Scores.EnergyLevel)
+	}
+	if draft.Personality != colorTraits["green"].personality {
+		t.Fatalf("expected green personality, got %q", draft.Personality)
+	}
+}
+
+func TestParseAuraAIPartialNothingUsable(t *testing.T) {
+	if _, err := parseAuraAIPartial(`{"aura_color":42,"strengths":"none"}`); err == nil {
+		t.Fatal("expected error when no field validates")
+	}
+}
+
+func TestAuraResultSchemaCoversReadingFields(t *testing.T) {
+	schema := auraResultSchema()
+	properties := schema["properties"].(map[string]any)
+
+	if len(properties) != len(auraReadingFields) {
+		t.Fatalf("expected %d schema properties, got %d", len(auraReadingFields), len(properties))
+	}
+	for _, field := range auraReadingFields {
+		if _, ok := properties[field]; !ok {
+			t.Fatalf("schema missing property %s", field)
+		}
+	}
+}
+
+func TestLocalizeDraftKeepsAIFields(t *testing.T) {
+	base := auraAnalysisResult{AuraColor: "blue", EnergyLevel: 60, MoodScore: 7}
+	color, personality := "blue", "Written by the model in Turkish."
+	draft := salvageAuraDraft(base, auraAIPartial{AuraColor: &color, Personality: &personality}, "glm")
+	localizeDraft(&draft, "tr")
+
+	want, _ := i18n.Traits("tr", "blue")
+	if draft.Personality != personality {
+		t.Errorf("AI personality overwritten: %q", draft.Personality)
+	}
+	if draft.DailyAdvice != want.DailyAdvice || draft.Strengths[0] != want.Strengths[0] {
+		t.Errorf("fallback fields not localized: %q %v", draft.DailyAdvice, draft.Strengths)
+	}
+
+	english := deterministicDraft(base)
+	localizeDraft(&english, "en")
+	if english.Personality != colorTraits["blue"].personality {
+		t.Errorf("en personality = %q", english.Personality)
+	}
+}
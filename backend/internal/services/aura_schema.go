@@ -0,0 +1,39 @@
+package services
+
+// jsonObjectResponseFormat asks for any JSON object (GLM, DeepSeek).
+var jsonObjectResponseFormat = map[string]any{"type": "json_object"}
+
+// auraResultResponseFormat constrains structured-output providers to the exact
+// reading shape parsed by parseAuraAIPartial, so wrong field types cannot occur.
+// Strict mode requires every property to be listed as required and no extras.
+var auraResultResponseFormat = map[string]any{
+	"type": "json_schema",
+	"json_schema": map[string]any{
+		"name":   "aura_reading",
+		"strict": true,
+		"schema": auraResultSchema(),
+	},
+}
+
+func auraResultSchema() map[string]any {
+	stringList := map[string]any{
+		"type":  "array",
+		"items": map[string]any{"type": "string"},
+	}
+
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"aura_color":      map[string]any{"type": "string", "enum": auraColors},
+			"secondary_color": map[string]any{"type": []string{"string", "null"}},
+			"energy_level":    map[string]any{"type": "integer", "description": "1-100"},
+			"mood_score":      map[string]any{"type": "integer", "description": "1-10"},
+			"personality":     map[string]any{"type": "string"},
+			"strengths":       stringList,
+			"challenges":      stringList,
+			"daily_advice":    map[string]any{"type": "string"},
+		},
+		"required":             auraReadingFields,
+		"additionalProperties": false,
+	}
+}
@@ -2,37 +2,93 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/i18n"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/queue"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 )
 
+var tracer = otel.Tracer("github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/services")
+
+var errAuraAIDisabled = errors.New("aura ai analyzer disabled")
+
+// errAuraMockMode skips the AI when FORCE_MOCK_MODE is set.
+var errAuraMockMode = errors.New("aura ai skipped in mock mode")
+
 type AuraService struct {
-	db       *gorm.DB
-	analyzer *auraAIAnalyzer
+	db          *gorm.DB
+	cfg         *config.Config
+	analyzer    *auraAIAnalyzer
+	moderator   imageModerator
+	faces       faceDetector
+	photos      *PhotoStorageService
+	scanQueue   *scanQueue
+	tasks       queue.Queue
+	analytics   *Analytics
+	usage       *AIUsageService
+	experiments *ExperimentService
 }
 
 type auraAIProvider struct {
-	name   string
-	apiURL string
-	apiKey string
-	model  string
+	name         string
+	apiURL       string
+	apiKey       string
+	model        string
+	supportsSeed bool
+	// supportsJSONSchema marks providers that accept response_format json_schema
+	// (OpenAI structured outputs); others get json_object and the lenient parser.
+	supportsJSONSchema bool
 }
 
 type auraAIAnalyzer struct {
-	providers []auraAIProvider
-	client    *http.Client
+	providers   []auraAIProvider
+	client      *http.Client
+	temperature float64
+	seed        int64
+	usage       *AIUsageService
+	prompts     *PromptTemplateService
+	mockMode    bool
+}
+
+// auraAnalysisOptions controls sampling for a single analysis. Nil fields fall
+// back to the analyzer's configured defaults.
+type auraAnalysisOptions struct {
+	Temperature   *float64
+	Seed          *int64
+	Deterministic bool
+	// History is a compact summary of the user's prior readings; empty omits it.
+	History string
+	// Memory holds the user's opted-in personalization facts, already delimited.
+	Memory string
+	// Language is the resolved locale the reading text is written in.
+	Language string
+	// PromptTemplateID pins a prompt template version instead of the active
+	// one. Provider is tried first and, when Model is set, asked for Model.
+	PromptTemplateID *uuid.UUID
+	Provider         string
+	Model            string
 }
 
 type auraAnalysisResult struct {
@@ -45,8 +101,9 @@ type auraAnalysisResult struct {
 type auraChatCompletionRequest struct {
 	Model          string            `json:"model"`
 	Messages       []auraChatMessage `json:"messages"`
-	Temperature    float64           `json:"temperature,omitempty"`
-	ResponseFormat map[string]string `json:"response_format,omitempty"`
+	Temperature    *float64          `json:"temperature,omitempty"`
+	Seed           *int64            `json:"seed,omitempty"`
+	ResponseFormat map[string]any    `json:"response_format,omitempty"`
 }
 
 type auraChatMessage struct {
@@ -60,15 +117,40 @@ type auraChatCompletionResponse struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage aiTokenUsage `json:"usage"`
 }
 
-func NewAuraService(db *gorm.DB, cfg *config.Config) *AuraService {
+func NewAuraService(db *gorm.DB, cfg *config.Config, photos *PhotoStorageService, analytics *Analytics) *AuraService {
 	return &AuraService{
-		db:       db,
-		analyzer: newAuraAIAnalyzer(cfg),
+		db:        db,
+		cfg:       cfg,
+		analytics: analytics,
+		analyzer:  newAuraAIAnalyzer(cfg),
+		moderator: newImageModerator(cfg),
+		faces:     newFaceDetector(cfg),
+		photos:    photos,
+		scanQueue: newScanQueue(cfg.AuraScanWorkers),
 	}
 }
 
+// UseAIUsage records the tokens and cost of the service's AI calls and
+// holds them to the daily AI budget.
+func (s *AuraService) UseAIUsage(usage *AIUsageService) {
+	s.usage = usage
+	s.analyzer.usage = usage
+}
+
+// UsePromptTemplates takes scan prompts from the active prompt templates
+// instead of the built-in ones.
+func (s *AuraService) UsePromptTemplates(prompts *PromptTemplateService) {
+	s.analyzer.prompts = prompts
+}
+
+// UseExperiments puts scans into the running experiment's variants.
+func (s *AuraService) UseExperiments(experiments *ExperimentService) {
+	s.experiments = experiments
+}
+
 func newAuraAIAnalyzer(cfg *config.Config) *auraAIAnalyzer {
 	timeout := cfg.AuraAITimeout
 	if timeout <= 0 {
@@ -93,163 +175,266 @@ func newAuraAIAnalyzer(cfg *config.Config) *auraAIAnalyzer {
 			model:  strings.TrimSpace(cfg.DeepSeekModel),
 		})
 	}
+	if strings.TrimSpace(cfg.OpenAIAPIKey) != "" {
+		providers = append(providers, auraAIProvider{
+			name:               "openai",
+			apiURL:             "https://api.openai.com/v1/chat/completions",
+			apiKey:             strings.TrimSpace(cfg.OpenAIAPIKey),
+			model:              strings.TrimSpace(cfg.OpenAIModel),
+			supportsSeed:       true,
+			supportsJSONSchema: true,
+		})
+	}
 
 	return &auraAIAnalyzer{
-		providers: providers,
-		client:    &http.Client{Timeout: timeout},
+		providers:   providers,
+		client:      &http.Client{Timeout: timeout},
+		temperature: cfg.AuraAITemperature,
+		seed:        cfg.AuraAISeed,
+		mockMode:    cfg.ForceMockMode,
 	}
 }
 
-var colorTraits = map[string]struct {
+// colorTrait is the fallback reading text for one aura color.
+type colorTrait struct {
 	personality string
 	strengths   []string
 	challenges  []string
 	dailyAdvice string
-}{
-	"red": {
-		personality: "Passionate, energetic, and action-oriented.",
-		strengths:   []string{"Courage", "Leadership", "Determination"},
-		challenges:  []string{"Impulsiveness", "Patience", "Anger Management"},
-		dailyAdvice: "Channel your energy into a physical activity today. Avoid hasty decisions.",
-	},
-	"orange": {
-		personality: "Creative, social, and adventurous.",
-		strengths:   []string{"Creativity", "Optimism", "Social Skills"},
-		challenges:  []string{"Scattered Focus", "Restlessness", "Overcommitment"},
-		dailyAdvice: "Start a new creative project. Connect with an old friend.",
-	},
-	"yellow": {
-		personality: "Optimistic, intellectual, and cheerful.",
-		strengths:   []string{"Analytical Thinking", "Positivity", "Communication"},
-		challenges:  []string{"Critical Nature", "Overthinking", "Perfectionism"},
-		dailyAdvice: "Share your ideas with others. Take time to relax your mind.",
-	},
-	"green": {
-		personality: "Balanced, growth-oriented, and nurturing.",
-		strengths:   []string{"Compassion", "Reliability", "Growth Mindset"},
-		challenges:  []string{"Jealousy", "Possessiveness", "Insecurity"},
-		dailyAdvice: "Spend time in nature. Nurture a relationship or a plant.",
-	},
-	"blue": {
-		personality: "Calm, intuitive, and trustworthy.",
-		strengths:   []string{"Communication", "Intuition", "Loyalty"},
-		challenges:  []string{"Fear of Expression", "Melancholy", "Stubbornness"},
-		dailyAdvice: "Speak your truth today. Trust your gut feelings.",
-	},
-	"indigo": {
-		personality: "Intuitive, wise, and deeply spiritual.",
-		strengths:   []string{"Vision", "Wisdom", "Integrity"},
-		challenges:  []string{"Isolation", "Judgment", "Rigidity"},
-		dailyAdvice: "Meditate or reflect on your long-term goals. Practice forgiveness.",
-	},
-	"violet": {
-		personality: "Visionary, artistic, and magical.",
-		strengths:   []string{"Imagination", "Humanitarianism", "Leadership"},
-		challenges:  []string{"Unrealistic Expectations", "Arrogance", "Detachment"},
-		dailyAdvice: "Engage in art or music. Visualize your ideal future.",
-	},
-	"white": {
-		personality: "Pure, balanced, and spiritually connected.",
-		strengths:   []string{"Purity", "Healing", "High Vibration"},
-		challenges:  []string{"Vulnerability", "Naivety", "Disconnection from Reality"},
-		dailyAdvice: "Focus on cleansing your space, physical or mental. Protect your energy.",
-	},
-	"gold": {
-		personality: "Confident, abundant, and empowered.",
-		strengths:   []string{"Confidence", "Generosity", "Willpower"},
-		challenges:  []string{"Ego", "Greed", "Overbearing nature"},
-		dailyAdvice: "Share your abundance with others. Practice humility.",
-	},
-	"pink": {
-		personality: "Loving, gentle, and compassionate.",
-		strengths:   []string{"Love", "Empathy", "Nurturing"},
-		challenges:  []string{"Neediness", "Martyrdom", "Lack of Boundaries"},
-		dailyAdvice: "Practice self-love. Set healthy boundaries with kindness.",
-	},
+}
+
+// colorTraits is the English trait table, loaded from the i18n messages.
+var colorTraits = localizedTraits(i18n.DefaultLocale)
+
+// localizedTraits returns the trait table for locale, with English for any
+// color the locale file leaves out.
+func localizedTraits(locale string) map[string]colorTrait {
+	out := make(map[string]colorTrait)
+	for _, color := range i18n.Colors() {
+		t, _ := i18n.Traits(locale, color)
+		out[color] = colorTrait{personality: t.Personality, strengths: t.Strengths, challenges: t.Challenges, dailyAdvice: t.DailyAdvice}
+	}
+	return out
 }
 
 var auraColors = []string{"red", "orange", "yellow", "green", "blue", "indigo", "violet", "white", "gold", "pink"}
 var secondaryColors = []string{"silver", "gold", "white", "black", "grey"}
 
-func (s *AuraService) Create(userID uuid.UUID, req dto.CreateAuraRequest) (*models.AuraReading, error) {
-	imageURL := strings.TrimSpace(req.ImageURL)
-	if imageURL == "" && strings.TrimSpace(req.ImageData) != "" {
-		// Keep a deterministic marker when image data is sent inline.
-		imageURL = "base64_upload"
-	}
-	if imageURL == "" {
-		return nil, errors.New("image_url or image_data is required")
+// Create analyzes an image and stores the reading. ctx carries the request
+// span so AI and database calls show up as children in traces.
+func (s *AuraService) Create(ctx context.Context, userID uuid.UUID, req dto.CreateAuraRequest) (*models.AuraReading, error) {
+	ctx, span := tracer.Start(ctx, "AuraService.Create")
+	defer span.End()
+	synthetic := isSyntheticScan(ctx)
+	if !synthetic {
+		ctx = withAIUser(ctx, s.db, userID)
 	}
+	db := s.db.WithContext(ctx)
 
-	analysis := deterministicAuraResult(userID, imageURL)
-	if aiAnalysis, err := s.analyzer.analyze(imageURL, analysis); err == nil {
-		analysis = aiAnalysis
+	imageURL, err := scanImageURL(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.screenImage(ctx, db, userID, imageURL, req.ImageData); err != nil {
+		return nil, err
+	}
+	// The probe image is a drawing, not a photo of a person.
+	if !synthetic {
+		if err := s.requireFace(ctx, userID, imageURL, req.ImageData); err != nil {
+			return nil, err
+		}
+		metrics.ScansTotal.WithLabelValues("attempted").Inc()
 	}
 
-	traits, ok := colorTraits[analysis.AuraColor]
-	if !ok {
-		traits = colorTraits["violet"]
-		analysis.AuraColor = "violet"
+	variant, opts := s.analysisOptions(ctx, db, userID, req.Locale)
+	imageHash := inlineImageHash(req.ImageData)
+	base := deterministicAuraResult(userID, photoKey(imageURL, imageHash))
+	started := time.Now()
+	draft, err := s.analyzer.analyze(ctx, imageURL, base, opts)
+	source, latency := scanSource(draft.Source, err, started)
+	if err != nil {
+		span.RecordError(err)
+		if !synthetic {
+			metrics.ScanFallbackTotal.WithLabelValues(scanFallbackReason(err)).Inc()
+		}
+		draft = deterministicDraft(base)
+		localizeDraft(&draft, opts.Language)
 	}
 
 	reading := &models.AuraReading{
-		UserID:         userID,
-		ImageURL:       imageURL,
-		AuraColor:      analysis.AuraColor,
-		SecondaryColor: analysis.SecondaryColor,
-		EnergyLevel:    clamp(analysis.EnergyLevel, 1, 100),
-		MoodScore:      clamp(analysis.MoodScore, 1, 10),
-		Personality:    traits.personality,
-		Strengths:      traits.strengths,
-		Challenges:     traits.challenges,
-		DailyAdvice:    traits.dailyAdvice,
-		AnalyzedAt:     time.Now(),
-	}
-
-	if err := s.db.Create(reading).Error; err != nil {
+		UserID:            userID,
+		ImageURL:          imageURL,
+		ImageHash:         imageHash,
+		AuraColor:         draft.Scores.AuraColor,
+		SecondaryColor:    draft.Scores.SecondaryColor,
+		EnergyLevel:       clamp(draft.Scores.EnergyLevel, 1, 100),
+		MoodScore:         clamp(draft.Scores.MoodScore, 1, 10),
+		Personality:       draft.Personality,
+		Strengths:         draft.Strengths,
+		Challenges:        draft.Challenges,
+		DailyAdvice:       draft.DailyAdvice,
+		Provenance:        draft.Provenance,
+		Palette:           photoPalette(decodeInlineImage(req.ImageData)),
+		Source:            source,
+		ProviderLatencyMs: latency,
+		PromptTemplateID:  draft.PromptTemplateID,
+		Language:          opts.Language,
+		Synthetic:         synthetic,
+		AnalyzedAt:        time.Now(),
+	}
+	variant.tag(reading)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(reading).Error; err != nil {
+			return err
+		}
+		if synthetic {
+			return nil
+		}
+		if err := dualWriteReadingTraits(tx, reading.ID); err != nil {
+			return err
+		}
+		return refreshUserAggregate(tx, userID)
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to save reading")
+		if !synthetic {
+			metrics.ScansTotal.WithLabelValues("failed").Inc()
+		}
 		return nil, err
 	}
+	if synthetic {
+		return reading, nil
+	}
 
+	s.storePhoto(ctx, reading, req.ImageData)
+	metrics.ScansTotal.WithLabelValues("succeeded").Inc()
+	s.analytics.Emit(userID, EventScanCompleted, scanCompletedProperties(reading.ID, reading.AuraColor, reading.Provenance, false))
 	return reading, nil
 }
 
-const auraDailyFreeLimit = 2
+// storePhoto keeps an inline upload with its reading. Storage problems are
+// logged rather than failing a scan that already succeeded.
+func (s *AuraService) storePhoto(ctx context.Context, reading *models.AuraReading, imageData string) {
+	raw := decodeInlineBytes(imageData)
+	if s.photos == nil || raw == nil {
+		return
+	}
+	if err := s.photos.Store(ctx, reading.UserID, reading.ID, raw); err != nil {
+		log.Printf("store photo for reading %s: %v", reading.ID, err)
+	}
+}
+
+// scanImageURL returns the image reference stored on the reading.
+func scanImageURL(req dto.CreateAuraRequest) (string, error) {
+	imageURL := strings.TrimSpace(req.ImageURL)
+	if imageURL == "" && strings.TrimSpace(req.ImageData) != "" {
+		// Keep a deterministic marker when image data is sent inline.
+		imageURL = "base64_upload"
+	}
+	if imageURL == "" {
+		return "", errors.New("image_url or image_data is required")
+	}
+	return imageURL, nil
+}
 
-func (s *AuraService) IsSubscribed(userID uuid.UUID) bool {
-	var sub models.Subscription
-	err := s.db.
-		Where("user_id = ? AND status = ? AND current_period_end > ?", userID, "active", time.Now()).
-		Order("current_period_end DESC").
-		First(&sub).Error
-	return err == nil
+// analysisOptions assigns the prompt variant and experiment arm and gathers
+// the prompt context. locale is a locale code or Accept-Language header.
+func (s *AuraService) analysisOptions(ctx context.Context, db *gorm.DB, userID uuid.UUID, locale string) (readingVariant, auraAnalysisOptions) {
+	opts := auraAnalysisOptions{Language: i18n.Resolve(locale)}
+	variant := readingVariant{prompt: s.promptVariant(userID)}
+	if a := s.experiments.assign(ctx, userID); a != nil {
+		variant.experimentID, variant.experiment = &a.experimentID, a.variant.Name
+		if a.variant.PromptVariant != "" {
+			variant.prompt = a.variant.PromptVariant
+		}
+		opts.Temperature = a.variant.Temperature
+		opts.PromptTemplateID = a.variant.PromptTemplateID
+		opts.Provider, opts.Model = a.variant.Provider, a.variant.Model
+	}
+	if variant.prompt == promptVariantHistory {
+		opts.History = s.readingHistory(db, userID)
+	}
+	opts.Memory = promptMemories(db, userID)
+	return variant, opts
 }
 
-func (s *AuraService) CanScan(userID uuid.UUID, isSubscribed bool) (bool, int, error) {
-	if isSubscribed {
+const auraDailyFreeLimit = 2
+
+// CanScan reports whether the user has scans left today under their plan's
+// daily limit (-1 for unlimited). The day is counted in the user's profile
+// timezone, never a per-request one, so the quota cannot be reset by
+// switching zones.
+func (s *AuraService) CanScan(ctx context.Context, userID uuid.UUID, dailyLimit int) (bool, int, error) {
+	if dailyLimit < 0 {
 		return true, -1, nil
 	}
 
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
+	db := s.db.WithContext(ctx)
+	startOfDay, endOfDay := localDayBounds(time.Now(), profileLocation(db, userID))
+	startOfDay = quotaStart(db, userID, startOfDay)
 
-	var scansToday int64
-	if err := s.db.Model(&models.AuraReading{}).
+	// Deleted readings still count: deleting a scan does not give it back.
+	var scansToday, groupScansToday int64
+	if err := db.Unscoped().Model(&models.AuraReading{}).Scopes(personalReadings).
 		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, startOfDay, endOfDay).
 		Count(&scansToday).Error; err != nil {
 		return false, 0, err
 	}
+	// A group scan counts once however many people were in the photo.
+	if err := db.Unscoped().Model(&models.GroupReading{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, startOfDay, endOfDay).
+		Count(&groupScansToday).Error; err != nil {
+		return false, 0, err
+	}
+	// So does each regeneration of an earlier reading.
+	var regenerationsToday int64
+	if err := db.Model(&models.ReadingVersion{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, startOfDay, endOfDay).
+		Count(&regenerationsToday).Error; err != nil {
+		return false, 0, err
+	}
+	scansToday += groupScansToday + regenerationsToday
 
-	remaining := auraDailyFreeLimit - int(scansToday)
+	remaining := dailyLimit - int(scansToday)
 	if remaining < 0 {
 		remaining = 0
 	}
 
-	return scansToday < auraDailyFreeLimit, remaining, nil
+	allowed := int(scansToday) < dailyLimit
+	if !allowed {
+		s.analytics.Emit(userID, EventPaywallHit, map[string]any{"feature": "daily_scans", "daily_limit": dailyLimit})
+	}
+	return allowed, remaining, nil
 }
 
-func deterministicAuraResult(userID uuid.UUID, imageURL string) auraAnalysisResult {
-	seedInput := strings.ToLower(strings.TrimSpace(imageURL)) + ":" + userID.String()
+// inlineImageHash is the hex SHA-256 of inline image data, or "" when there
+// is none.
+func inlineImageHash(imageData string) string {
+	raw := decodeInlineBytes(imageData)
+	if raw == nil {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// photoKey identifies a photo for the deterministic engine: its content
+// hash when it was uploaded inline, otherwise its URL. Inline uploads all
+// share the "base64_upload" URL, so keying on it would give every photo a
+// user uploads the same fallback.
+func photoKey(imageURL, imageHash string) string {
+	if imageHash != "" {
+		return "sha256:" + imageHash
+	}
+	return imageURL
+}
+
+// deterministicAuraResult is the reading the deterministic engine gives the
+// user for a photo (see photoKey); the same inputs always give the same one.
+func deterministicAuraResult(userID uuid.UUID, photo string) auraAnalysisResult {
+	seedInput := strings.ToLower(strings.TrimSpace(photo)) + ":" + userID.String()
 	hash := sha256.Sum256([]byte(seedInput))
 
 	color := auraColors[int(hash[0])%len(auraColors)]
@@ -272,14 +457,34 @@ func deterministicAuraResult(userID uuid.UUID, imageURL string) auraAnalysisResu
 	}
 }
 
-func (a *auraAIAnalyzer) analyze(imageURL string, base auraAnalysisResult) (auraAnalysisResult, error) {
+func (a *auraAIAnalyzer) analyze(ctx context.Context, imageURL string, base auraAnalysisResult, opts auraAnalysisOptions) (auraReadingDraft, error) {
+	if a != nil && a.mockMode {
+		return auraReadingDraft{}, errAuraMockMode
+	}
 	if a == nil || len(a.providers) == 0 {
-		return base, errors.New("aura ai analyzer disabled")
+		return auraReadingDraft{}, errAuraAIDisabled
+	}
+	if a.usage.OverBudget(ctx) {
+		return auraReadingDraft{}, errAIBudgetExceeded
 	}
 
 	var lastErr error
-	for _, provider := range a.providers {
-		result, err := a.analyzeWithProvider(provider, imageURL, base)
+	for _, provider := range a.providersFor(opts) {
+		start := time.Now()
+		providerCtx, span := tracer.Start(ctx, "aura.ai."+provider.name, trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("ai.provider", provider.name), attribute.String("ai.model", provider.model)))
+		providerCtx, tokens := withAITokenCounter(providerCtx)
+		result, err := a.analyzeWithProvider(providerCtx, provider, imageURL, base, opts)
+		recordAIInteraction(ctx, models.AIPurposeAuraScan, provider.name, provider.model, err, start)
+		a.usage.Record(ctx, models.AIPurposeAuraScan, provider.name, provider.model, *tokens, err, start)
+		outcome := "success"
+		if err != nil {
+			outcome = "error"
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		metrics.AIProviderDuration.WithLabelValues(provider.name, outcome).Observe(time.Since(start).Seconds())
 		if err == nil {
 			return result, nil
 		}
@@ -287,71 +492,227 @@ func (a *auraAIAnalyzer) analyze(imageURL string, base auraAnalysisResult) (aura
 	}
 
 	if lastErr != nil {
-		return base, lastErr
+		return auraReadingDraft{}, lastErr
 	}
-	return base, errors.New("no aura ai provider available")
+	return auraReadingDraft{}, errors.New("no aura ai provider available")
 }
 
-func (a *auraAIAnalyzer) analyzeWithProvider(provider auraAIProvider, imageURL string, base auraAnalysisResult) (auraAnalysisResult, error) {
-	prompt := fmt.Sprintf(
-		"Analyze this aura image URL and return only JSON. image_url=%q allowed_colors=%v fallback=%+v. Output keys: aura_color (string), secondary_color (string or null), energy_level (1-100), mood_score (1-10). Keep results realistic.",
-		imageURL,
-		auraColors,
-		base,
-	)
+// providersFor orders the providers for a scan: opts.Provider first, with
+// opts.Model in place of its configured model, then the rest as configured.
+func (a *auraAIAnalyzer) providersFor(opts auraAnalysisOptions) []auraAIProvider {
+	if opts.Provider == "" {
+		return a.providers
+	}
+	ordered := make([]auraAIProvider, 0, len(a.providers))
+	for _, p := range a.providers {
+		if p.name == opts.Provider {
+			if opts.Model != "" {
+				p.model = opts.Model
+			}
+			ordered = append(ordered, p)
+		}
+	}
+	for _, p := range a.providers {
+		if p.name != opts.Provider {
+			ordered = append(ordered, p)
+		}
+	}
+	return ordered
+}
+
+func (a *auraAIAnalyzer) analyzeWithProvider(ctx context.Context, provider auraAIProvider, imageURL string, base auraAnalysisResult, opts auraAnalysisOptions) (auraReadingDraft, error) {
+	data := auraPromptData{
+		ImageURL:      imageURL,
+		AllowedColors: fmt.Sprint(auraColors),
+		Fallback:      fmt.Sprintf("%+v", base),
+		History:       opts.History,
+		Memory:        opts.Memory,
+	}
+	if opts.Language != "" && opts.Language != i18n.DefaultLocale {
+		data.Language = i18n.LanguageName(opts.Language)
+	}
+	// The prompt comes from the active template for the provider; one that
+	// fails to render falls back to the built-in prompt.
+	var tmpl *compiledPrompt
+	if opts.PromptTemplateID != nil {
+		tmpl = a.prompts.pinned(ctx, *opts.PromptTemplateID)
+	}
+	if tmpl == nil {
+		tmpl = a.prompts.resolve(ctx, models.PromptAuraScan, provider.name)
+	}
+	system, prompt, err := tmpl.render(data)
+	if err != nil {
+		log.Printf("aura prompt template %v failed to render, using the built-in: %v", tmpl.id, err)
+		tmpl = builtinCompiled[models.PromptAuraScan]
+		if system, prompt, err = tmpl.render(data); err != nil {
+			return auraReadingDraft{}, err
+		}
+	}
 
 	reqBody := auraChatCompletionRequest{
 		Model: provider.model,
 		Messages: []auraChatMessage{
-			{Role: "system", Content: "You are an aura analysis engine. Return valid JSON only."},
+			// The untrusted input instruction is always appended, whatever
+			// the template says.
+			{Role: "system", Content: system + " " + untrustedInputInstruction},
 			{Role: "user", Content: prompt},
 		},
-		Temperature:    0.2,
-		ResponseFormat: map[string]string{"type": "json_object"},
+		ResponseFormat: jsonObjectResponseFormat,
+	}
+	if provider.supportsJSONSchema {
+		reqBody.ResponseFormat = auraResultResponseFormat
+	}
+	reqBody.Temperature, reqBody.Seed = a.sampling(provider, base, opts)
+
+	content, status, err := a.complete(ctx, provider, reqBody)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("http.response.status_code", status))
+	if err != nil && status == http.StatusBadRequest && provider.supportsJSONSchema {
+		// Model snapshot without structured output support: retry in plain JSON mode.
+		reqBody.ResponseFormat = jsonObjectResponseFormat
+		content, _, err = a.complete(ctx, provider, reqBody)
+	}
+	if err != nil {
+		return auraReadingDraft{}, err
 	}
 
+	partial, err := parseAuraAIPartial(content)
+	if err != nil {
+		return auraReadingDraft{}, err
+	}
+
+	draft := salvageAuraDraft(base, partial, provider.name)
+	draft.PromptTemplateID = tmpl.id
+	localizeDraft(&draft, opts.Language)
+	return draft, nil
+}
+
+// complete sends one chat completion request and returns the message content
+// along with the HTTP status code (0 when the request never got a response).
+func (a *auraAIAnalyzer) complete(ctx context.Context, provider auraAIProvider, reqBody auraChatCompletionRequest) (string, int, error) {
 	payload, err := json.Marshal(reqBody)
 	if err != nil {
-		return base, err
+		return "", 0, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, provider.apiURL, bytes.NewReader(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.apiURL, bytes.NewReader(payload))
 	if err != nil {
-		return base, err
+		return "", 0, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+provider.apiKey)
 
 	resp, err := a.client.Do(req)
 	if err != nil {
-		return base, err
+		return "", 0, err
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return base, err
+		return "", resp.StatusCode, err
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return base, fmt.Errorf("aura ai request failed: status=%d", resp.StatusCode)
+		return "", resp.StatusCode, fmt.Errorf("aura ai request failed: status=%d", resp.StatusCode)
 	}
 
 	var completion auraChatCompletionResponse
 	if err := json.Unmarshal(respBody, &completion); err != nil {
-		return base, err
+		return "", resp.StatusCode, err
 	}
+	countAITokens(ctx, completion.Usage)
 	if len(completion.Choices) == 0 {
-		return base, errors.New("aura ai returned no choices")
+		return "", resp.StatusCode, errors.New("aura ai returned no choices")
 	}
 
-	content := strings.TrimSpace(completion.Choices[0].Message.Content)
-	parsed, err := parseAuraAIContent(content)
-	if err != nil {
-		return base, err
+	return strings.TrimSpace(completion.Choices[0].Message.Content), resp.StatusCode, nil
+}
+
+// sampling resolves temperature and seed for one provider call. Deterministic
+// mode forces temperature 0 and, where the provider supports it, a seed derived
+// from the deterministic base result so replays of the same input match.
+func (a *auraAIAnalyzer) sampling(provider auraAIProvider, base auraAnalysisResult, opts auraAnalysisOptions) (*float64, *int64) {
+	temperature := a.temperature
+	if opts.Temperature != nil {
+		temperature = *opts.Temperature
+	}
+	seed := a.seed
+	if opts.Seed != nil {
+		seed = *opts.Seed
 	}
 
-	return mergeAuraAnalysis(base, parsed), nil
+	if opts.Deterministic {
+		temperature = 0
+		if seed == 0 {
+			seed = deterministicSeed(base)
+		}
+	}
+
+	var seedPtr *int64
+	if provider.supportsSeed && seed != 0 {
+		seedPtr = &seed
+	}
+	return &temperature, seedPtr
+}
+
+func deterministicSeed(base auraAnalysisResult) int64 {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%d", base.AuraColor, base.EnergyLevel, base.MoodScore)))
+	seed := int64(binary.BigEndian.Uint64(h[:8]) & 0x7fffffffffffffff)
+	if seed == 0 {
+		seed = 1
+	}
+	return seed
+}
+
+// Analyze runs the AI pipeline for an image without persisting a reading. It is
+// used by admin tooling (deterministic replays, provider comparisons).
+func (s *AuraService) Analyze(ctx context.Context, req dto.AdminAnalyzeRequest) (*dto.AuraAnalysisPreview, error) {
+	imageURL := strings.TrimSpace(req.ImageURL)
+	if imageURL == "" {
+		return nil, errors.New("image_url is required")
+	}
+
+	// The deterministic base is keyed on the user; replays pass the original owner.
+	userID := uuid.Nil
+	if strings.TrimSpace(req.UserID) != "" {
+		parsed, err := uuid.Parse(req.UserID)
+		if err != nil {
+			return nil, errors.New("invalid user_id")
+		}
+		userID = parsed
+	}
+
+	opts := auraAnalysisOptions{
+		Temperature:   req.Temperature,
+		Seed:          req.Seed,
+		Deterministic: req.Deterministic,
+	}
+
+	base := deterministicAuraResult(userID, imageURL)
+	started := time.Now()
+	draft, err := s.analyzer.analyze(ctx, imageURL, base, opts)
+	source, latency := scanSource(draft.Source, err, started)
+	aiError := ""
+	if err != nil {
+		draft = deterministicDraft(base)
+		aiError = err.Error()
+	}
+
+	return &dto.AuraAnalysisPreview{
+		AuraColor:         draft.Scores.AuraColor,
+		SecondaryColor:    draft.Scores.SecondaryColor,
+		EnergyLevel:       clamp(draft.Scores.EnergyLevel, 1, 100),
+		MoodScore:         clamp(draft.Scores.MoodScore, 1, 10),
+		Personality:       draft.Personality,
+		Strengths:         draft.Strengths,
+		Challenges:        draft.Challenges,
+		DailyAdvice:       draft.DailyAdvice,
+		Provenance:        draft.Provenance,
+		Deterministic:     opts.Deterministic,
+		Source:            source,
+		ProviderLatencyMs: latency,
+		AIError:           aiError,
+	}, nil
 }
 
 func parseAuraAIContent(content string) (auraAnalysisResult, error) {
@@ -364,10 +725,9 @@ func parseAuraAIContent(content string) (auraAnalysisResult, error) {
 		return parsed, nil
 	}
 
-	start := strings.Index(content, "{")
-	end := strings.LastIndex(content, "}")
-	if start >= 0 && end > start {
-		parsed, ok = parseAuraJSON(content[start : end+1])
+	// Fenced, prefixed or suffixed responses: isolate the object first.
+	if raw, err := extractJSONObject(content); err == nil {
+		parsed, ok = parseAuraJSON(raw)
 		if ok {
 			return parsed, nil
 		}
@@ -446,25 +806,43 @@ func clamp(v, minV, maxV int) int {
 
 func (s *AuraService) GetByID(userID, id uuid.UUID) (*models.AuraReading, error) {
 	var reading models.AuraReading
-	err := s.db.Where("user_id = ? AND id = ?", userID, id).First(&reading).Error
+	err := s.db.Preload("Versions", func(db *gorm.DB) *gorm.DB { return db.Order("version DESC") }).
+		Where("user_id = ? AND id = ?", userID, id).First(&reading).Error
 	if err != nil {
 		return nil, err
 	}
-	return &reading, nil
+	readings := []models.AuraReading{reading}
+	if err := readTraitsFromTable(s.db, readings); err != nil {
+		return nil, err
+	}
+	return &readings[0], nil
 }
 
-func (s *AuraService) List(userID uuid.UUID, page, pageSize int) ([]models.AuraReading, int64, error) {
+// List returns a page of the user's readings matching the filter. Without a
+// sort, pinned readings come first, then the newest.
+func (s *AuraService) List(userID uuid.UUID, page, pageSize int, filter dto.AuraListFilter) ([]models.AuraReading, int64, error) {
+	filterScope, order, err := auraListScope(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if strings.TrimSpace(filter.Trait) != "" && !traitIndexReady(s.db) {
+		return nil, 0, ErrTraitIndexNotReady
+	}
+
 	var readings []models.AuraReading
 	var total int64
 
 	offset := (page - 1) * pageSize
+	scope := func(db *gorm.DB) *gorm.DB {
+		return personalReadings(db).Where("user_id = ?", userID).Scopes(filterScope)
+	}
 
-	if err := s.db.Model(&models.AuraReading{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+	if err := s.db.Model(&models.AuraReading{}).Scopes(database.Replica, scope).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := s.db.Where("user_id = ?", userID).
-		Order("created_at DESC").
+	err = s.db.Scopes(database.Replica, scope).
+		Order(order).
 		Limit(pageSize).
 		Offset(offset).
 		Find(&readings).Error
@@ -472,26 +850,27 @@ func (s *AuraService) List(userID uuid.UUID, page, pageSize int) ([]models.AuraR
 	if err != nil {
 		return nil, 0, err
 	}
+	if err := readTraitsFromTable(s.db.Scopes(database.Replica).Session(&gorm.Session{}), readings); err != nil {
+		return nil, 0, err
+	}
 
 	return readings, total, nil
 }
 
 func (s *AuraService) GetLatest(userID uuid.UUID) (*models.AuraReading, error) {
 	var reading models.AuraReading
-	err := s.db.Where("user_id = ?", userID).Order("created_at DESC").First(&reading).Error
+	err := s.db.Scopes(personalReadings).Where("user_id = ?", userID).Order("created_at DESC").First(&reading).Error
 	if err != nil {
 		return nil, err
 	}
 	return &reading, nil
 }
 
-func (s *AuraService) GetToday(userID uuid.UUID) (*models.AuraReading, error) {
+func (s *AuraService) GetToday(userID uuid.UUID, tz string) (*models.AuraReading, error) {
 	var reading models.AuraReading
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
+	startOfDay, endOfDay := localDayBounds(time.Now(), userLocation(s.db, userID, tz))
 
-	err := s.db.Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, startOfDay, endOfDay).
+	err := s.db.Scopes(personalReadings).Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, startOfDay, endOfDay).
 		Order("created_at DESC").
 		First(&reading).Error
 
@@ -502,45 +881,48 @@ func (s *AuraService) GetToday(userID uuid.UUID) (*models.AuraReading, error) {
 }
 
 func (s *AuraService) Delete(userID, id uuid.UUID) error {
-	result := s.db.Where("user_id = ? AND id = ?", userID, id).Delete(&models.AuraReading{})
-	if result.Error != nil {
-		return result.Error
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND id = ?", userID, id).Delete(&models.AuraReading{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("record not found")
+		}
+		if err := tx.Where("reading_id = ?", id).Delete(&models.ReadingTrait{}).Error; err != nil {
+			return err
+		}
+		return refreshUserAggregate(tx, userID)
+	})
+	if err != nil {
+		return err
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("record not found")
+	if s.photos != nil {
+		return s.photos.Remove(userID, id)
 	}
 	return nil
 }
 
+// GetStats reads the user's aggregate row rather than their readings, from
+// a replica when one has it.
 func (s *AuraService) GetStats(userID uuid.UUID) (*dto.AuraStatsResponse, error) {
-	var readings []models.AuraReading
-	if err := s.db.Where("user_id = ?", userID).Find(&readings).Error; err != nil {
-		return nil, err
-	}
-
-	if len(readings) == 0 {
-		return &dto.AuraStatsResponse{
-			ColorDistribution: make(map[string]int),
-			TotalReadings:     0,
-			AverageEnergy:     0,
-			AverageMood:       0,
-		}, nil
+	agg := &models.UserAggregate{}
+	if err := s.db.Scopes(database.Replica).First(agg, "user_id = ?", userID).Error; err != nil {
+		if agg, err = userAggregate(s.db, userID); err != nil {
+			return nil, err
+		}
 	}
 
-	colorDist := make(map[string]int)
-	totalEnergy := 0
-	totalMood := 0
-
-	for _, r := range readings {
-		colorDist[r.AuraColor]++
-		totalEnergy += r.EnergyLevel
-		totalMood += r.MoodScore
+	colorDist := agg.ColorCounts
+	if colorDist == nil {
+		colorDist = make(map[string]int)
 	}
-
 	return &dto.AuraStatsResponse{
 		ColorDistribution: colorDist,
-		TotalReadings:     int64(len(readings)),
-		AverageEnergy:     float64(totalEnergy) / float64(len(readings)),
-		AverageMood:       float64(totalMood) / float64(len(readings)),
+		TotalReadings:     agg.TotalReadings,
+		AverageEnergy:     agg.AverageEnergy,
+		AverageMood:       agg.AverageMood,
+		DominantColor:     agg.DominantColor,
+		LastScanAt:        agg.LastScanAt,
 	}, nil
 }
@@ -1,9 +1,14 @@
 package services
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
 	"github.com/google/uuid"
 )
 
@@ -28,6 +33,57 @@ func TestDeterministicAuraResultStable(t *testing.T) {
 	}
 }
 
+func TestDeterministicAuraResultKeyedOnPhoto(t *testing.T) {
+	userID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	photoA := base64.StdEncoding.EncodeToString([]byte("photo a bytes"))
+	photoB := base64.StdEncoding.EncodeToString([]byte("photo b bytes"))
+
+	hashA := inlineImageHash(photoA)
+	if hashA == "" || inlineImageHash("data:image/jpeg;base64,"+photoA) != hashA {
+		t.Fatalf("the same bytes should hash the same with or without a data URI, got %q", hashA)
+	}
+	if inlineImageHash(photoB) == hashA {
+		t.Fatal("different photos share a hash")
+	}
+	if inlineImageHash("") != "" {
+		t.Error("no inline data should have no hash")
+	}
+
+	// Re-sending the same photo gives the same fallback; another photo uploaded
+	// inline is keyed on its own bytes, not the shared base64_upload marker.
+	first := deterministicAuraResult(userID, photoKey("base64_upload", hashA))
+	if again := deterministicAuraResult(userID, photoKey("base64_upload", inlineImageHash(photoA))); again != first {
+		t.Fatalf("same photo gave %#v then %#v", first, again)
+	}
+	if photoKey("base64_upload", hashA) == photoKey("base64_upload", inlineImageHash(photoB)) {
+		t.Error("two inline photos share a key")
+	}
+	// Readings from URLs keep the fallback they had before photos were hashed.
+	if photoKey("https://cdn.example.com/a.jpg", "") != "https://cdn.example.com/a.jpg" {
+		t.Error("URL photos should be keyed on their URL")
+	}
+}
+
+func TestForceMockModeSkipsAI(t *testing.T) {
+	cfg := &config.Config{
+		ForceMockMode: true,
+		OpenAIAPIKey:  "sk-test",
+		OpenAIModel:   "gpt-4o-mini",
+		FaceDetection: "openai",
+	}
+	analyzer := newAuraAIAnalyzer(cfg)
+	_, err := analyzer.analyze(context.Background(), "https://cdn.example.com/a.jpg", auraAnalysisResult{}, auraAnalysisOptions{})
+	if !errors.Is(err, errAuraMockMode) {
+		t.Fatalf("err = %v, want errAuraMockMode", err)
+	}
+	if source, latency := scanSource("", err, time.Now()); source != models.ReadingSourceMock || latency != nil {
+		t.Errorf("source = %q, latency = %v; want mock with no latency", source, latency)
+	}
+	if newFaceDetector(cfg) != nil {
+		t.Error("mock mode should skip face detection")
+	}
+}
+
 func TestParseAuraAIContentJSON(t *testing.T) {
 	content := `{"aura_color":"Blue","secondary_color":"gold","energy_level":88,"mood_score":9}`
 	parsed, err := parseAuraAIContent(content)
@@ -87,3 +143,28 @@ func TestAuraProviderPriorityGLMFirst(t *testing.T) {
 		t.Fatalf("expected deepseek second, got %s", analyzer.providers[1].name)
 	}
 }
+
+func TestAuraSamplingDeterministicMode(t *testing.T) {
+	analyzer := &auraAIAnalyzer{temperature: 0.7}
+	base := auraAnalysisResult{AuraColor: "blue", EnergyLevel: 70, MoodScore: 8}
+	seeded := auraAIProvider{name: "openai", supportsSeed: true}
+	unseeded := auraAIProvider{name: "glm"}
+
+	temp, seed := analyzer.sampling(seeded, base, auraAnalysisOptions{})
+	if *temp != 0.7 || seed != nil {
+		t.Fatalf("expected configured temperature and no seed, got %v/%v", *temp, seed)
+	}
+
+	temp, seed = analyzer.sampling(seeded, base, auraAnalysisOptions{Deterministic: true})
+	if *temp != 0 || seed == nil {
+		t.Fatalf("expected temperature 0 with seed in deterministic mode, got %v/%v", *temp, seed)
+	}
+	_, again := analyzer.sampling(seeded, base, auraAnalysisOptions{Deterministic: true})
+	if *again != *seed {
+		t.Fatalf("deterministic seed changed: %d vs %d", *seed, *again)
+	}
+
+	if _, seed = analyzer.sampling(unseeded, base, auraAnalysisOptions{Deterministic: true}); seed != nil {
+		t.Fatalf("expected no seed for provider without seed support")
+	}
+}
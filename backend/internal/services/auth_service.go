@@ -2,16 +2,16 @@ package services
 
 import (
 	"context"
-	"crypto/rand"
 	"crypto/sha256"
-	"encoding/base64"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/jwtkeys"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -25,26 +25,33 @@ var (
 	ErrInvalidToken       = errors.New("invalid or expired refresh token")
 	ErrUserNotFound       = errors.New("user not found")
 	ErrGuestOnlyAction    = errors.New("guest account required")
+	ErrInvalidSignUp      = errors.New("email required and password must be at least 8 characters")
+	ErrInvalidAppleToken  = errors.New("invalid Apple identity token")
 )
 
 type AuthService struct {
-	db  *gorm.DB
-	cfg *config.Config
+	db     *gorm.DB
+	cfg    *config.Config
+	emails *EmailService
+	keys   *jwtkeys.Keyring
 }
 
-func NewAuthService(db *gorm.DB, cfg *config.Config) *AuthService {
-	return &AuthService{db: db, cfg: cfg}
+func NewAuthService(db *gorm.DB, cfg *config.Config, emails *EmailService) *AuthService {
+	return &AuthService{db: db, cfg: cfg, emails: emails, keys: jwtkeys.New(cfg.JWTSecret, cfg.JWTPreviousSecrets)}
 }
 
-func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) Register(req *dto.RegisterRequest, device dto.DeviceInfo) (*dto.AuthResponse, error) {
 	if len(req.Email) == 0 || len(req.Password) < 8 {
-		return nil, errors.New("email required and password must be at least 8 characters")
+		return nil, ErrInvalidSignUp
 	}
 
 	var existing models.User
 	if err := s.db.Where("email = ?", req.Email).First(&existing).Error; err == nil {
 		return nil, ErrEmailTaken
 	}
+	if _, ok := s.deactivatedUser("email", req.Email); ok {
+		return nil, ErrAccountDeactivated
+	}
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -61,12 +68,21 @@ func (s *AuthService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, err
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	return s.generateTokenPair(&user)
+	if err := s.sendVerificationEmail(&user, req.Locale); err != nil {
+		log.Printf("register: %v", err)
+	}
+
+	return s.startSession(&user, device)
 }
 
-func (s *AuthService) Login(req *dto.LoginRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) Login(req *dto.LoginRequest, device dto.DeviceInfo) (*dto.AuthResponse, error) {
 	var user models.User
 	if err := s.db.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		// Point the owner of a deactivated account at POST /auth/restore.
+		if deactivated, ok := s.deactivatedUser("email", req.Email); ok && deactivated.Password != "" &&
+			bcrypt.CompareHashAndPassword([]byte(deactivated.Password), []byte(req.Password)) == nil {
+			return nil, ErrAccountDeactivated
+		}
 		return nil, ErrInvalidCredentials
 	}
 
@@ -74,13 +90,13 @@ func (s *AuthService) Login(req *dto.LoginRequest) (*dto.AuthResponse, error) {
 		return nil, ErrInvalidCredentials
 	}
 
-	return s.generateTokenPair(&user)
+	return s.startSession(&user, device)
 }
 
-func (s *AuthService) ClaimGuest(userID uuid.UUID, req *dto.ClaimGuestRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) ClaimGuest(userID uuid.UUID, req *dto.ClaimGuestRequest, device dto.DeviceInfo) (*dto.AuthResponse, error) {
 	email := strings.TrimSpace(strings.ToLower(req.Email))
 	if email == "" || len(req.Password) < 8 {
-		return nil, errors.New("email required and password must be at least 8 characters")
+		return nil, ErrInvalidSignUp
 	}
 
 	var existing models.User
@@ -105,8 +121,9 @@ func (s *AuthService) ClaimGuest(userID uuid.UUID, req *dto.ClaimGuestRequest) (
 		if err := tx.Model(&models.User{}).
 			Where("id = ?", userID).
 			Updates(map[string]interface{}{
-				"email":    email,
-				"password": string(hash),
+				"email":             email,
+				"password":          string(hash),
+				"email_verified_at": nil,
 			}).Error; err != nil {
 			return err
 		}
@@ -119,89 +136,28 @@ func (s *AuthService) ClaimGuest(userID uuid.UUID, req *dto.ClaimGuestRequest) (
 
 	user.Email = email
 	user.Password = string(hash)
-	return s.generateTokenPair(&user)
-}
-
-func (s *AuthService) Refresh(req *dto.RefreshRequest) (*dto.AuthResponse, error) {
-	tokenHash := hashToken(req.RefreshToken)
-
-	var stored models.RefreshToken
-	if err := s.db.Where("token_hash = ? AND revoked = false", tokenHash).First(&stored).Error; err != nil {
-		return nil, ErrInvalidToken
-	}
-
-	if time.Now().After(stored.ExpiresAt) {
-		s.db.Model(&stored).Update("revoked", true)
-		return nil, ErrInvalidToken
-	}
-
-	// Revoke old token (rotation)
-	s.db.Model(&stored).Update("revoked", true)
-
-	var user models.User
-	if err := s.db.First(&user, "id = ?", stored.UserID).Error; err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
-	}
-
-	return s.generateTokenPair(&user)
-}
-
-func (s *AuthService) Logout(req *dto.LogoutRequest) error {
-	tokenHash := hashToken(req.RefreshToken)
-	return s.db.Model(&models.RefreshToken{}).
-		Where("token_hash = ?", tokenHash).
-		Update("revoked", true).Error
-}
-
-// DeleteAccount implements Apple Guideline 5.1.1(v) - account deletion.
-// Scrubs all user data: tokens, subscriptions, reports, blocks, then soft-deletes user.
-func (s *AuthService) DeleteAccount(userID uuid.UUID, password string) error {
-	var user models.User
-	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
-		return ErrUserNotFound
+	user.EmailVerifiedAt = nil
+	if err := s.sendVerificationEmail(&user, req.Locale); err != nil {
+		log.Printf("claim guest: %v", err)
 	}
-
-	// Verify password (skip for Apple Sign-In users who have no password)
-	if user.Password != "" {
-		if strings.TrimSpace(password) == "" {
-			return ErrInvalidCredentials
-		}
-		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-			return ErrInvalidCredentials
-		}
-	}
-
-	// Scrub all associated data in a transaction
-	return s.db.Transaction(func(tx *gorm.DB) error {
-		// Revoke all refresh tokens
-		tx.Where("user_id = ?", userID).Delete(&models.RefreshToken{})
-
-		// Remove subscriptions
-		tx.Where("user_id = ?", userID).Delete(&models.Subscription{})
-
-		// Remove reports filed by user
-		tx.Where("reporter_id = ?", userID).Delete(&models.Report{})
-
-		// Remove blocks
-		tx.Where("blocker_id = ? OR blocked_id = ?", userID, userID).Delete(&models.Block{})
-
-		// Soft-delete the user (GORM DeletedAt)
-		return tx.Delete(&user).Error
-	})
+	return s.startSession(&user, device)
 }
 
 // AppleSignIn handles Sign in with Apple (Guideline 4.8).
 // Verifies Apple identity token and creates/finds a user.
-func (s *AuthService) AppleSignIn(req *dto.AppleSignInRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) AppleSignIn(req *dto.AppleSignInRequest, device dto.DeviceInfo) (*dto.AuthResponse, error) {
 	allowedAudiences := splitCSV(s.cfg.AppleClientIDs)
 	claims, err := verifyAppleIdentityToken(context.Background(), req.IdentityToken, allowedAudiences)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, errAppleKeysUnavailable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAppleToken, err)
 	}
 
 	sub, _ := claims["sub"].(string)
 	if sub == "" {
-		return nil, errors.New("Apple token missing subject")
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidAppleToken)
 	}
 
 	// Use email from token, or from the request (first sign-in only)
@@ -213,6 +169,10 @@ func (s *AuthService) AppleSignIn(req *dto.AppleSignInRequest) (*dto.AuthRespons
 		email = sub + "@privaterelay.appleid.com"
 	}
 
+	if _, ok := s.deactivatedUser("apple_sub", sub); ok {
+		return nil, ErrAccountDeactivated
+	}
+
 	var user models.User
 	err = s.db.Where("apple_sub = ?", sub).First(&user).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -233,6 +193,11 @@ func (s *AuthService) AppleSignIn(req *dto.AppleSignInRequest) (*dto.AuthRespons
 				AppleSub: &subCopy,
 				Password: "", // Apple users have no password
 			}
+			// Apple only issues verified addresses (including private relays).
+			if appleEmailVerified(claims) {
+				now := time.Now()
+				user.EmailVerifiedAt = &now
+			}
 			if err := s.db.Create(&user).Error; err != nil {
 				return nil, fmt.Errorf("failed to create Apple user: %w", err)
 			}
@@ -243,7 +208,7 @@ func (s *AuthService) AppleSignIn(req *dto.AppleSignInRequest) (*dto.AuthRespons
 		return nil, fmt.Errorf("failed to lookup user by apple_sub: %w", err)
 	}
 
-	return s.generateTokenPair(&user)
+	return s.startSession(&user, device)
 }
 
 func splitCSV(csv string) []string {
@@ -258,64 +223,62 @@ func splitCSV(csv string) []string {
 	return out
 }
 
-func (s *AuthService) generateTokenPair(user *models.User) (*dto.AuthResponse, error) {
-	accessToken, err := s.generateAccessToken(user)
-	if err != nil {
-		return nil, err
-	}
-
-	refreshToken, err := s.generateRefreshToken(user)
-	if err != nil {
-		return nil, err
+// generateAccessToken signs a short-lived JWT; "sid" is the session
+// (refresh token family) it belongs to. Tokens carry only identifiers:
+// email, admin, guest and plan status are looked up server-side so they
+// cannot be read from or go stale in a token.
+func (s *AuthService) generateAccessToken(user *models.User, familyID uuid.UUID) (string, error) {
+	claims := jwt.MapClaims{
+		"sub": user.ID.String(),
+		"sid": familyID.String(),
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(s.cfg.JWTAccessExpiry).Unix(),
 	}
 
-	return &dto.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User: dto.UserResponse{
-			ID:    user.ID,
-			Email: user.Email,
-		},
-	}, nil
+	return s.keys.Sign(claims)
 }
 
-func (s *AuthService) generateAccessToken(user *models.User) (string, error) {
-	claims := jwt.MapClaims{
-		"sub":   user.ID.String(),
-		"email": user.Email,
-		"iat":   time.Now().Unix(),
-		"exp":   time.Now().Add(s.cfg.JWTAccessExpiry).Unix(),
+// AccountEmail returns the user's current email, or "" for unknown or
+// deactivated accounts.
+func (s *AuthService) AccountEmail(userID uuid.UUID) string {
+	var user models.User
+	if err := s.db.Select("email").First(&user, "id = ?", userID).Error; err != nil {
+		return ""
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.cfg.JWTSecret))
+	return user.Email
 }
 
-func (s *AuthService) generateRefreshToken(user *models.User) (string, error) {
-	rawBytes := make([]byte, 32)
-	if _, err := rand.Read(rawBytes); err != nil {
-		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+// UpdateTimezone stores the user's IANA timezone used for daily limits and
+// streaks. Changes are limited to one per TimezoneChangeCooldown, so moving
+// the day boundary cannot be used to reset the scan quota.
+func (s *AuthService) UpdateTimezone(userID uuid.UUID, timezone string) (string, error) {
+	loc, err := loadTimezone(timezone)
+	if err != nil {
+		return "", err
 	}
 
-	rawToken := base64.URLEncoding.EncodeToString(rawBytes)
-	tokenHash := hashToken(rawToken)
-
-	record := models.RefreshToken{
-		ID:        uuid.New(),
-		UserID:    user.ID,
-		TokenHash: tokenHash,
-		ExpiresAt: time.Now().Add(s.cfg.JWTRefreshExpiry),
+	var user models.User
+	if err := s.db.Select("id", "timezone", "timezone_changed_at").First(&user, "id = ?", userID).Error; err != nil {
+		return "", ErrUserNotFound
 	}
-
-	if err := s.db.Create(&record).Error; err != nil {
-		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	if user.Timezone == loc.String() {
+		return loc.String(), nil
+	}
+	if user.TimezoneChangedAt != nil && time.Since(*user.TimezoneChangedAt) < s.cfg.TimezoneChangeCooldown {
+		return "", ErrTimezoneCooldown
 	}
 
-	return rawToken, nil
+	if err := s.db.Model(&user).Updates(map[string]interface{}{
+		"timezone":            loc.String(),
+		"timezone_changed_at": time.Now(),
+	}).Error; err != nil {
+		return "", err
+	}
+	return loc.String(), nil
 }
 
 // GetProfile retrieves the user's profile including subscription and streak info
-func (s *AuthService) GetProfile(userID uuid.UUID) (map[string]interface{}, error) {
+func (s *AuthService) GetProfile(userID uuid.UUID) (*dto.ProfileResponse, error) {
 	var user models.User
 	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
 		return nil, ErrUserNotFound
@@ -333,11 +296,25 @@ func (s *AuthService) GetProfile(userID uuid.UUID) (map[string]interface{}, erro
 		currentStreak = streak.CurrentStreak
 	}
 
-	return map[string]interface{}{
-		"id":                 userID.String(),
-		"email":              user.Email,
-		"subscriptionStatus": subStatus,
-		"currentStreak":      currentStreak,
+	agg, err := userAggregate(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ProfileResponse{
+		ID:                  userID.String(),
+		Email:               user.Email,
+		Handle:              user.Handle,
+		Discoverable:        user.DiscoverableByHandle,
+		ContactDiscoverable: user.DiscoverableByContacts,
+		PendingEmail:        s.pendingEmailChange(userID),
+		EmailVerified:       user.EmailVerifiedAt != nil,
+		Timezone:            user.Timezone,
+		SubscriptionStatus:  subStatus,
+		CurrentStreak:       currentStreak,
+		TotalReadings:       agg.TotalReadings,
+		DominantColor:       agg.DominantColor,
+		LastScanAt:          agg.LastScanAt,
 	}, nil
 }
 
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidAuthToken = errors.New("invalid, expired or already used link")
+	ErrPasswordTooShort = errors.New("password must be at least 8 characters")
+)
+
+// ForgotPassword emails a single-use reset link. It succeeds for unknown
+// addresses too so the endpoint cannot be used to discover accounts.
+func (s *AuthService) ForgotPassword(req *dto.ForgotPasswordRequest) error {
+	email := strings.TrimSpace(strings.ToLower(req.Email))
+	if email == "" {
+		return ErrInvalidEmailAddress
+	}
+
+	var user models.User
+	if err := s.db.Where("LOWER(email) = ?", email).First(&user).Error; err != nil {
+		return nil
+	}
+	if isGuestEmail(user.Email) {
+		return nil
+	}
+
+	ttl := s.cfg.PasswordResetTTL
+	token, err := issueAuthToken(s.db, user.ID, models.TokenPurposePasswordReset, "", ttl)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]any{
+		"ResetURL":       authTokenLink(s.cfg.PasswordResetURL, token),
+		"ExpiresMinutes": int(ttl.Minutes()),
+	}
+	// Deliver in the background so response time doesn't reveal whether
+	// the account exists.
+	go func() {
+		if err := s.emails.Send(user.Email, "password_reset", req.Locale, data); err != nil {
+			log.Printf("password reset email for user %s: %v", user.ID, err)
+		}
+	}()
+	return nil
+}
+
+// ResetPassword consumes a reset token, sets the new password and signs the
+// user out everywhere: refresh tokens are revoked and access tokens issued
+// before the reset stop working.
+func (s *AuthService) ResetPassword(req *dto.ResetPasswordRequest) error {
+	if len(req.Password) < 8 {
+		return ErrPasswordTooShort
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeAuthToken(tx, req.Token, models.TokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", token.UserID).Update("password", string(hash)).Error; err != nil {
+			return err
+		}
+		return signOutEverywhere(tx, token.UserID, models.RevokeReasonPasswordReset)
+	})
+}
+
+// issueAuthToken creates a token for purpose, replacing any unused one so
+// only the most recently mailed link works.
+func issueAuthToken(db *gorm.DB, userID uuid.UUID, purpose, payload string, ttl time.Duration) (string, error) {
+	rawBytes := make([]byte, 32)
+	if _, err := rand.Read(rawBytes); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	rawToken := base64.RawURLEncoding.EncodeToString(rawBytes)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+			Delete(&models.AuthToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.AuthToken{
+			UserID:    userID,
+			Purpose:   purpose,
+			TokenHash: hashToken(rawToken),
+			Payload:   payload,
+			ExpiresAt: time.Now().Add(ttl),
+		}).Error
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store %s token: %w", purpose, err)
+	}
+	return rawToken, nil
+}
+
+// consumeAuthToken marks a valid token used. The conditional update makes
+// concurrent redemptions of the same token race-safe: only one succeeds.
+func consumeAuthToken(tx *gorm.DB, rawToken, purpose string) (*models.AuthToken, error) {
+	rawToken = strings.TrimSpace(rawToken)
+	if rawToken == "" {
+		return nil, ErrInvalidAuthToken
+	}
+
+	var token models.AuthToken
+	if err := tx.Where("token_hash = ?", hashToken(rawToken)).First(&token).Error; err != nil {
+		return nil, ErrInvalidAuthToken
+	}
+
+	now := time.Now()
+	if err := checkAuthToken(&token, purpose, now); err != nil {
+		return nil, err
+	}
+
+	result := tx.Model(&models.AuthToken{}).
+		Where("id = ? AND used_at IS NULL", token.ID).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidAuthToken
+	}
+	token.UsedAt = &now
+	return &token, nil
+}
+
+// checkAuthToken accepts a stored token only for the purpose it was issued
+// for, once, and before it expires.
+func checkAuthToken(token *models.AuthToken, purpose string, now time.Time) error {
+	if token.Purpose != purpose || token.UsedAt != nil || now.After(token.ExpiresAt) {
+		return ErrInvalidAuthToken
+	}
+	return nil
+}
+
+// authTokenLink appends the token to a configured landing URL.
+func authTokenLink(base, token string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
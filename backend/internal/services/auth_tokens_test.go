@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestAuthTokenLink(t *testing.T) {
+	cases := map[string]string{
+		"https://aurasnap.app/reset-password": "https://aurasnap.app/reset-password?token=abc-_1",
+		"https://aurasnap.app/reset?lang=tr":  "https://aurasnap.app/reset?lang=tr&token=abc-_1",
+		"aurasnap://reset-password":           "aurasnap://reset-password?token=abc-_1",
+	}
+	for base, want := range cases {
+		if got := authTokenLink(base, "abc-_1"); got != want {
+			t.Errorf("authTokenLink(%q) = %q, want %q", base, got, want)
+		}
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	cases := map[string]string{
+		"ada@example.com": "a***@example.com",
+		"şule@örnek.com":  "ş***@örnek.com",
+		"invalid":         "***",
+	}
+	for in, want := range cases {
+		if got := maskEmail(in); got != want {
+			t.Errorf("maskEmail(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCheckAuthToken(t *testing.T) {
+	now := time.Now()
+	used := now.Add(-time.Minute)
+	valid := models.AuthToken{Purpose: models.TokenPurposePasswordReset, ExpiresAt: now.Add(time.Hour)}
+	if err := checkAuthToken(&valid, models.TokenPurposePasswordReset, now); err != nil {
+		t.Fatalf("valid token refused: %v", err)
+	}
+
+	cases := map[string]struct {
+		token   models.AuthToken
+		purpose string
+	}{
+		"expired":       {models.AuthToken{Purpose: models.TokenPurposePasswordReset, ExpiresAt: now.Add(-time.Second)}, models.TokenPurposePasswordReset},
+		"consumed":      {models.AuthToken{Purpose: models.TokenPurposePasswordReset, ExpiresAt: now.Add(time.Hour), UsedAt: &used}, models.TokenPurposePasswordReset},
+		"wrong purpose": {models.AuthToken{Purpose: models.TokenPurposeEmailVerify, ExpiresAt: now.Add(time.Hour)}, models.TokenPurposePasswordReset},
+	}
+	for name, c := range cases {
+		if err := checkAuthToken(&c.token, c.purpose, now); !errors.Is(err, ErrInvalidAuthToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidAuthToken", name, err)
+		}
+	}
+}
+
+func TestPasswordResetRejectsBadInput(t *testing.T) {
+	// Both are refused before the database is touched.
+	s := &AuthService{}
+	if err := s.ForgotPassword(&dto.ForgotPasswordRequest{Email: "  "}); !errors.Is(err, ErrInvalidEmailAddress) {
+		t.Errorf("ForgotPassword without an email: %v", err)
+	}
+	if err := s.ResetPassword(&dto.ResetPasswordRequest{Token: "t", Password: "short"}); !errors.Is(err, ErrPasswordTooShort) {
+		t.Errorf("ResetPassword with a short password: %v", err)
+	}
+	if _, err := consumeAuthToken(nil, "  ", models.TokenPurposePasswordReset); !errors.Is(err, ErrInvalidAuthToken) {
+		t.Errorf("consumeAuthToken with a blank token: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+)
+
+const cdnRequestTimeout = 15 * time.Second
+
+// CDN invalidates edge-cached responses. Tags are the surrogate keys the
+// edge cache policy attaches to public responses (see middleware.EdgeCache),
+// the same names the in-process response cache is purged by.
+type CDN interface {
+	Name() string
+	PurgeTags(ctx context.Context, tags []string) error
+	PurgeAll(ctx context.Context) error
+}
+
+// NewCDN picks the driver from CDN_DRIVER ("cloudflare" or "fastly").
+// Without a configured driver nothing sits in front of the API and purges
+// only clear the in-process cache.
+func NewCDN(cfg *config.Config) (CDN, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.CDNDriver)) {
+	case "cloudflare":
+		return newCloudflareCDN(cfg)
+	case "fastly":
+		return newFastlyCDN(cfg)
+	case "", "none":
+		return noopCDN{}, nil
+	default:
+		return nil, fmt.Errorf("unknown CDN_DRIVER %q", cfg.CDNDriver)
+	}
+}
+
+type noopCDN struct{}
+
+func (noopCDN) Name() string { return "none" }
+
+func (noopCDN) PurgeTags(context.Context, []string) error { return nil }
+
+func (noopCDN) PurgeAll(context.Context) error { return nil }
+
+// cdnRequest sends one purge call and turns a non-2xx answer into an error.
+func cdnRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+)
+
+const cloudflareAPI = "https://api.cloudflare.com/client/v4"
+
+// cloudflareMaxTags is how many cache tags one purge request may carry.
+const cloudflareMaxTags = 30
+
+// cloudflareCDN purges by Cache-Tag through the zone purge API.
+type cloudflareCDN struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newCloudflareCDN(cfg *config.Config) (*cloudflareCDN, error) {
+	if cfg.CloudflareZoneID == "" || cfg.CloudflareAPIToken == "" {
+		return nil, errors.New("CLOUDFLARE_ZONE_ID and CLOUDFLARE_API_TOKEN are required")
+	}
+	return &cloudflareCDN{
+		url:    cloudflareAPI + "/zones/" + cfg.CloudflareZoneID + "/purge_cache",
+		token:  cfg.CloudflareAPIToken,
+		client: &http.Client{Timeout: cdnRequestTimeout},
+	}, nil
+}
+
+func (c *cloudflareCDN) Name() string { return "cloudflare" }
+
+func (c *cloudflareCDN) PurgeTags(ctx context.Context, tags []string) error {
+	for start := 0; start < len(tags); start += cloudflareMaxTags {
+		end := min(start+cloudflareMaxTags, len(tags))
+		if err := c.purge(ctx, map[string]any{"tags": tags[start:end]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *cloudflareCDN) PurgeAll(ctx context.Context) error {
+	return c.purge(ctx, map[string]any{"purge_everything": true})
+}
+
+func (c *cloudflareCDN) purge(ctx context.Context, payload map[string]any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	return cdnRequest(c.client, req)
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+)
+
+const fastlyAPI = "https://api.fastly.com"
+
+// fastlyMaxKeys is how many surrogate keys one batch purge may carry.
+const fastlyMaxKeys = 256
+
+// fastlyCDN purges by Surrogate-Key through the service purge API.
+type fastlyCDN struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newFastlyCDN(cfg *config.Config) (*fastlyCDN, error) {
+	if cfg.FastlyServiceID == "" || cfg.FastlyAPIToken == "" {
+		return nil, errors.New("FASTLY_SERVICE_ID and FASTLY_API_TOKEN are required")
+	}
+	return &fastlyCDN{
+		url:    fastlyAPI + "/service/" + cfg.FastlyServiceID,
+		token:  cfg.FastlyAPIToken,
+		client: &http.Client{Timeout: cdnRequestTimeout},
+	}, nil
+}
+
+func (f *fastlyCDN) Name() string { return "fastly" }
+
+func (f *fastlyCDN) PurgeTags(ctx context.Context, tags []string) error {
+	for start := 0; start < len(tags); start += fastlyMaxKeys {
+		end := min(start+fastlyMaxKeys, len(tags))
+		req, err := f.request(ctx, "/purge")
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Surrogate-Key", strings.Join(tags[start:end], " "))
+		if err := cdnRequest(f.client, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fastlyCDN) PurgeAll(ctx context.Context) error {
+	req, err := f.request(ctx, "/purge_all")
+	if err != nil {
+		return err
+	}
+	return cdnRequest(f.client, req)
+}
+
+func (f *fastlyCDN) request(ctx context.Context, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Fastly-Key", f.token)
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
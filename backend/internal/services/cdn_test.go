@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCloudflarePurgeBatchesTags(t *testing.T) {
+	var batches [][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		var body struct {
+			Tags []string `json:"tags"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		batches = append(batches, body.Tags)
+	}))
+	defer srv.Close()
+
+	tags := make([]string, cloudflareMaxTags+5)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("tag-%d", i)
+	}
+	cdn := &cloudflareCDN{url: srv.URL, token: "token", client: srv.Client()}
+	if err := cdn.PurgeTags(context.Background(), tags); err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 || len(batches[0]) != cloudflareMaxTags || len(batches[1]) != 5 {
+		t.Fatalf("batches = %d", len(batches))
+	}
+}
+
+func TestFastlyPurgeSendsSurrogateKeys(t *testing.T) {
+	var path, keys string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, keys = r.URL.Path, r.Header.Get("Surrogate-Key")
+		if r.Header.Get("Fastly-Key") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	cdn := &fastlyCDN{url: srv.URL + "/service/svc", token: "token", client: srv.Client()}
+	if err := cdn.PurgeTags(context.Background(), []string{"legal", "share"}); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/service/svc/purge" || keys != "legal share" {
+		t.Fatalf("purge %s with keys %q", path, keys)
+	}
+
+	cdn.token = "wrong"
+	if err := cdn.PurgeAll(context.Background()); err == nil {
+		t.Fatal("expected an error for a rejected purge")
+	}
+}
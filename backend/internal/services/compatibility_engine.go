@@ -0,0 +1,170 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Component weights of the compatibility score; they sum to 1.
+const (
+	colorAffinityWeight   = 0.5
+	energyAlignmentWeight = 0.25
+	moodCorrelationWeight = 0.25
+)
+
+const (
+	// moodHistoryWindow is how far back shared scan days feed mood correlation.
+	moodHistoryWindow = 30 * 24 * time.Hour
+	// minSharedMoodDays is how many days both users must have scanned on
+	// before correlation replaces the latest-mood comparison.
+	minSharedMoodDays = 3
+)
+
+// relationAffinity is the color affinity matrix collapsed by relation; the
+// pairings themselves live in complementaryColors and challengingColors.
+var relationAffinity = map[string]int{
+	"complementary": 95,
+	"same":          88,
+	"neutral":       65,
+	"challenging":   35,
+}
+
+// compatibilityBreakdown is the deterministic engine's result for one pair.
+type compatibilityBreakdown struct {
+	Score           int
+	Relation        string
+	ColorAffinity   int
+	EnergyAlignment int
+	MoodCorrelation int
+}
+
+// computeCompatibility scores two readings, using both users' recent mood
+// history when they have scanned on enough of the same days.
+func computeCompatibility(db *gorm.DB, userAura, friendAura models.AuraReading) compatibilityBreakdown {
+	since := time.Now().Add(-moodHistoryWindow)
+	return scoreCompatibility(userAura, friendAura,
+		dailyMoods(db, userAura.UserID, since), dailyMoods(db, friendAura.UserID, since))
+}
+
+func scoreCompatibility(a, b models.AuraReading, moodsA, moodsB map[string]float64) compatibilityBreakdown {
+	relation := colorRelation(a.AuraColor, b.AuraColor)
+	br := compatibilityBreakdown{
+		Relation:        relation,
+		ColorAffinity:   colorAffinity(a, b),
+		EnergyAlignment: clamp(100-absInt(a.EnergyLevel-b.EnergyLevel), 0, 100),
+		MoodCorrelation: moodCorrelation(a.MoodScore, b.MoodScore, moodsA, moodsB),
+	}
+	br.Score = clamp(int(math.Round(
+		colorAffinityWeight*float64(br.ColorAffinity)+
+			energyAlignmentWeight*float64(br.EnergyAlignment)+
+			moodCorrelationWeight*float64(br.MoodCorrelation))), 0, 100)
+	return br
+}
+
+// colorAffinity starts from the primary colors' relation and nudges it by
+// the secondary colors: a shared secondary or a secondary that complements
+// the other's primary adds, one that clashes subtracts.
+func colorAffinity(a, b models.AuraReading) int {
+	score := relationAffinity[colorRelation(a.AuraColor, b.AuraColor)]
+
+	secA := strings.ToLower(derefString(a.SecondaryColor))
+	secB := strings.ToLower(derefString(b.SecondaryColor))
+	if secA != "" && secA == secB {
+		score += 5
+	}
+	for _, pair := range [][2]string{{secA, b.AuraColor}, {secB, a.AuraColor}} {
+		if pair[0] == "" {
+			continue
+		}
+		switch colorRelation(pair[0], pair[1]) {
+		case "complementary":
+			score += 4
+		case "challenging":
+			score -= 4
+		}
+	}
+	return clamp(score, 0, 100)
+}
+
+// moodCorrelation maps the Pearson correlation of the two users' daily mood
+// over shared scan days to 0-100. Without enough shared days, or when either
+// mood never moved, it falls back to how close the latest moods are.
+func moodCorrelation(latestA, latestB int, moodsA, moodsB map[string]float64) int {
+	var xs, ys []float64
+	for day, x := range moodsA {
+		if y, ok := moodsB[day]; ok {
+			xs = append(xs, x)
+			ys = append(ys, y)
+		}
+	}
+	if len(xs) >= minSharedMoodDays {
+		if r, ok := pearson(xs, ys); ok {
+			return clamp(int(math.Round((r+1)*50)), 0, 100)
+		}
+	}
+	// Mood is 1-10, so the largest gap is 9.
+	return clamp(100-absInt(latestA-latestB)*100/9, 0, 100)
+}
+
+func pearson(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
+
+// dailyMoods returns the user's average mood per UTC day since the cutoff.
+func dailyMoods(db *gorm.DB, userID uuid.UUID, since time.Time) map[string]float64 {
+	var rows []struct {
+		Day  string
+		Mood float64
+	}
+	db.Model(&models.AuraReading{}).
+		Select("to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, AVG(mood_score) AS mood").
+		Scopes(personalReadings).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Group("1").
+		Scan(&rows)
+
+	moods := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		moods[row.Day] = row.Mood
+	}
+	return moods
+}
+
+// compatibilityBlurb is the template relationship blurb used when the AI is
+// unavailable.
+func compatibilityBlurb(br compatibilityBreakdown, userColor, friendColor string) string {
+	energy := "your energy levels run at a similar pace"
+	if br.EnergyAlignment < 60 {
+		energy = "one of you tends to run hotter than the other"
+	}
+	mood := "your moods tend to move together"
+	if br.MoodCorrelation < 50 {
+		mood = "your moods often pull in different directions"
+	}
+	return fmt.Sprintf("A %s pairing of %s and %s: %s, and %s.",
+		br.Relation, userColor, friendColor, energy, mood)
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestScoreCompatibilityWeighsComponents(t *testing.T) {
+	a := models.AuraReading{AuraColor: "red", EnergyLevel: 70, MoodScore: 7}
+	b := models.AuraReading{AuraColor: "green", EnergyLevel: 60, MoodScore: 7}
+
+	br := scoreCompatibility(a, b, nil, nil)
+	if br.Relation != "complementary" || br.ColorAffinity != 95 {
+		t.Fatalf("color: got %s/%d", br.Relation, br.ColorAffinity)
+	}
+	if br.EnergyAlignment != 90 || br.MoodCorrelation != 100 {
+		t.Fatalf("energy/mood: got %d/%d", br.EnergyAlignment, br.MoodCorrelation)
+	}
+	// 0.5*95 + 0.25*90 + 0.25*100 = 95
+	if br.Score != 95 {
+		t.Fatalf("score = %d, want 95", br.Score)
+	}
+
+	clash := scoreCompatibility(a, models.AuraReading{AuraColor: "gold", EnergyLevel: 10, MoodScore: 1}, nil, nil)
+	if clash.Score >= br.Score || clash.Relation != "challenging" {
+		t.Fatalf("challenging pair should score lower: %+v", clash)
+	}
+}
+
+func TestMoodCorrelationUsesSharedDays(t *testing.T) {
+	moodsA := map[string]float64{"2026-03-01": 3, "2026-03-02": 5, "2026-03-03": 8}
+	together := map[string]float64{"2026-03-01": 2, "2026-03-02": 4, "2026-03-03": 9}
+	opposite := map[string]float64{"2026-03-01": 9, "2026-03-02": 6, "2026-03-03": 2}
+
+	if got := moodCorrelation(8, 2, moodsA, together); got < 90 {
+		t.Errorf("moods moving together should correlate highly, got %d", got)
+	}
+	if got := moodCorrelation(8, 9, moodsA, opposite); got > 10 {
+		t.Errorf("opposite moods should correlate poorly, got %d", got)
+	}
+	// Too few shared days: fall back to latest-mood proximity.
+	if got := moodCorrelation(5, 5, map[string]float64{"2026-03-01": 5}, together); got != 100 {
+		t.Errorf("fallback proximity = %d, want 100", got)
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+var ErrNoAuraReading = errors.New("you need an aura reading first")
+
+// Friends whose latest reading is older than this aren't suggested.
+const friendReadingMaxAge = 7 * 24 * time.Hour
+
+// Lower ranks harmonize better.
+var relationRank = map[string]int{"complementary": 0, "same": 1, "neutral": 2, "challenging": 3}
+
+// friendCandidate is a friend's latest reading considered for today's suggestion.
+type friendCandidate struct {
+	UserID       uuid.UUID
+	Handle       string
+	Color        string
+	EnergyLevel  int
+	ScannedToday bool
+}
+
+// CompatibilityToday lists which colors harmonize with the user's current
+// aura and suggests the friend whose latest reading pairs best with it.
+func (s *AuraMatchService) CompatibilityToday(ctx context.Context, userID uuid.UUID, tz string) (*dto.CompatibilityTodayResponse, error) {
+	db := s.db.WithContext(ctx)
+
+	var current models.AuraReading
+	if err := db.Scopes(personalReadings).Where("user_id = ?", userID).Order("created_at DESC").First(&current).Error; err != nil {
+		return nil, ErrNoAuraReading
+	}
+
+	now := time.Now()
+	loc := userLocation(db, userID, tz)
+	dayStart, _ := localDayBounds(now, loc)
+
+	resp := &dto.CompatibilityTodayResponse{
+		Date:         now.In(loc).Format("2006-01-02"),
+		CurrentColor: strings.ToLower(current.AuraColor),
+		Harmonious:   harmoniousColors(current.AuraColor),
+		Challenging:  []string{},
+	}
+	for _, color := range auraColors {
+		if colorRelation(current.AuraColor, color) == "challenging" {
+			resp.Challenging = append(resp.Challenging, color)
+		}
+	}
+
+	var friendships []models.Friendship
+	if err := db.Scopes(activeUsersOnly("requester_id", "addressee_id")).
+		Where("(requester_id = ? OR addressee_id = ?) AND status = ?", userID, userID, models.FriendshipAccepted).
+		Find(&friendships).Error; err != nil {
+		return nil, err
+	}
+	friendIDs := make([]uuid.UUID, 0, len(friendships))
+	for _, f := range friendships {
+		other := f.RequesterID
+		if other == userID {
+			other = f.AddresseeID
+		}
+		if !isBlockedPair(db, userID, other) {
+			friendIDs = append(friendIDs, other)
+		}
+	}
+	if len(friendIDs) == 0 {
+		return resp, nil
+	}
+
+	var readings []models.AuraReading
+	if err := db.Select("user_id", "aura_color", "energy_level", "created_at").Scopes(personalReadings).
+		Where("user_id IN ? AND created_at > ?", friendIDs, now.Add(-friendReadingMaxAge)).
+		Order("created_at DESC").
+		Find(&readings).Error; err != nil {
+		return nil, err
+	}
+	var users []models.User
+	db.Select("id", "handle").Where("id IN ?", friendIDs).Find(&users)
+	handles := make(map[uuid.UUID]string, len(users))
+	for _, u := range users {
+		if u.Handle != nil {
+			handles[u.ID] = *u.Handle
+		}
+	}
+
+	seen := make(map[uuid.UUID]bool, len(friendIDs))
+	candidates := make([]friendCandidate, 0, len(friendIDs))
+	for _, r := range readings {
+		if seen[r.UserID] {
+			continue
+		}
+		seen[r.UserID] = true
+		candidates = append(candidates, friendCandidate{
+			UserID:       r.UserID,
+			Handle:       handles[r.UserID],
+			Color:        strings.ToLower(r.AuraColor),
+			EnergyLevel:  r.EnergyLevel,
+			ScannedToday: !r.CreatedAt.Before(dayStart),
+		})
+	}
+
+	if best := bestFriendMatch(current, candidates); best != nil {
+		resp.BestFriend = &dto.FriendColorMatch{
+			ID:           best.UserID.String(),
+			Handle:       best.Handle,
+			AuraColor:    best.Color,
+			Relation:     colorRelation(current.AuraColor, best.Color),
+			ScannedToday: best.ScannedToday,
+		}
+	}
+	return resp, nil
+}
+
+// harmoniousColors returns every non-challenging color, best pairing first.
+func harmoniousColors(color string) []dto.ColorHarmony {
+	out := []dto.ColorHarmony{}
+	for _, c := range auraColors {
+		if rel := colorRelation(color, c); rel != "challenging" {
+			out = append(out, dto.ColorHarmony{Color: c, Relation: rel})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return relationRank[out[i].Relation] < relationRank[out[j].Relation]
+	})
+	return out
+}
+
+// bestFriendMatch ranks candidates by color relation, then prefers friends
+// who scanned today, then the closest energy level. Challenging pairings
+// are never suggested.
+func bestFriendMatch(current models.AuraReading, candidates []friendCandidate) *friendCandidate {
+	var best *friendCandidate
+	better := func(a, b *friendCandidate) bool {
+		ra, rb := relationRank[colorRelation(current.AuraColor, a.Color)], relationRank[colorRelation(current.AuraColor, b.Color)]
+		if ra != rb {
+			return ra < rb
+		}
+		if a.ScannedToday != b.ScannedToday {
+			return a.ScannedToday
+		}
+		ea, eb := absInt(a.EnergyLevel-current.EnergyLevel), absInt(b.EnergyLevel-current.EnergyLevel)
+		if ea != eb {
+			return ea < eb
+		}
+		return a.UserID.String() < b.UserID.String()
+	}
+	for i := range candidates {
+		c := &candidates[i]
+		if colorRelation(current.AuraColor, c.Color) == "challenging" {
+			continue
+		}
+		if best == nil || better(c, best) {
+			best = c
+		}
+	}
+	return best
+}
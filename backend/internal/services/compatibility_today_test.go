@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestColorRelation(t *testing.T) {
+	cases := []struct{ a, b, want string }{
+		{"blue", "Blue", "same"},
+		{"blue", "orange", "complementary"},
+		{"orange", "blue", "complementary"},
+		{"red", "gold", "challenging"},
+		{"gold", "red", "challenging"},
+		{"blue", "pink", "neutral"},
+	}
+	for _, tc := range cases {
+		if got := colorRelation(tc.a, tc.b); got != tc.want {
+			t.Errorf("colorRelation(%s, %s) = %s, want %s", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestHarmoniousColorsOrdersBestFirst(t *testing.T) {
+	got := harmoniousColors("blue")
+	if got[0].Color != "orange" || got[1].Color != "blue" {
+		t.Fatalf("expected orange then blue first, got %+v", got[:2])
+	}
+	for _, h := range got {
+		if h.Color == "indigo" || h.Color == "violet" {
+			t.Errorf("challenging color %s listed as harmonious", h.Color)
+		}
+	}
+}
+
+func TestBestFriendMatch(t *testing.T) {
+	current := models.AuraReading{AuraColor: "blue", EnergyLevel: 60}
+	neutral := friendCandidate{UserID: uuid.New(), Color: "pink", EnergyLevel: 60, ScannedToday: true}
+	staleComplement := friendCandidate{UserID: uuid.New(), Color: "orange", EnergyLevel: 20}
+	freshComplement := friendCandidate{UserID: uuid.New(), Color: "orange", EnergyLevel: 90, ScannedToday: true}
+	clash := friendCandidate{UserID: uuid.New(), Color: "indigo", EnergyLevel: 60, ScannedToday: true}
+
+	best := bestFriendMatch(current, []friendCandidate{neutral, staleComplement, freshComplement, clash})
+	if best == nil || best.UserID != freshComplement.UserID {
+		t.Fatalf("expected today's complementary friend, got %+v", best)
+	}
+
+	if best := bestFriendMatch(current, []friendCandidate{clash}); best != nil {
+		t.Fatalf("challenging friend should not be suggested, got %+v", best)
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxContactHashes = 500
+
+var (
+	ErrInvalidPhone       = errors.New("phone must be in international format, e.g. +905551234567")
+	ErrTooManyContacts    = errors.New("too many contact hashes in one request")
+	ErrInvalidContactHash = errors.New("contact hashes must be 64-character hex SHA-256 digests")
+)
+
+var (
+	e164Pattern        = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	contactHashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// ContactDiscoveryService matches hashed address books against users who
+// opted into contact discovery. Clients hash locally; raw contacts never
+// reach the server.
+type ContactDiscoveryService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewContactDiscoveryService(db *gorm.DB, cfg *config.Config) *ContactDiscoveryService {
+	return &ContactDiscoveryService{db: db, cfg: cfg}
+}
+
+// Salt describes how clients must hash identifiers before upload.
+func (s *ContactDiscoveryService) Salt() *dto.ContactDiscoverySaltResponse {
+	return &dto.ContactDiscoverySaltResponse{
+		Salt:      s.cfg.ContactDiscoverySalt,
+		Algorithm: "sha256",
+		Format:    "hex(sha256(salt + \":\" + kind + \":\" + value)); kind is email (trimmed, lowercased) or phone (E.164)",
+		MaxHashes: maxContactHashes,
+	}
+}
+
+// Discover returns opted-in users whose email or phone hash is in the list,
+// excluding the caller and anyone blocked in either direction.
+func (s *ContactDiscoveryService) Discover(userID uuid.UUID, req *dto.ContactDiscoverRequest) ([]dto.ContactMatch, error) {
+	if len(req.Hashes) > maxContactHashes {
+		return nil, ErrTooManyContacts
+	}
+	hashes := make([]string, 0, len(req.Hashes))
+	seen := make(map[string]struct{}, len(req.Hashes))
+	for _, h := range req.Hashes {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if !contactHashPattern.MatchString(h) {
+			return nil, ErrInvalidContactHash
+		}
+		if _, dup := seen[h]; !dup {
+			seen[h] = struct{}{}
+			hashes = append(hashes, h)
+		}
+	}
+	if len(hashes) == 0 {
+		return []dto.ContactMatch{}, nil
+	}
+
+	type row struct {
+		UserID uuid.UUID
+		Handle *string
+		Hash   string
+	}
+	var rows []row
+	err := s.db.Table("contact_hashes").
+		Select("contact_hashes.user_id, users.handle, contact_hashes.hash").
+		Joins("JOIN users ON users.id = contact_hashes.user_id AND users.deleted_at IS NULL").
+		Where("contact_hashes.hash IN ? AND users.discoverable_by_contacts = true AND users.id <> ?", hashes, userID).
+		Where("users.id NOT IN (?)", s.db.Model(&models.Block{}).Select("blocked_id").Where("blocker_id = ?", userID)).
+		Where("users.id NOT IN (?)", s.db.Model(&models.Block{}).Select("blocker_id").Where("blocked_id = ?", userID)).
+		Scopes(notShadowBanned("users.id")).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]dto.ContactMatch, 0, len(rows))
+	matched := make(map[uuid.UUID]struct{}, len(rows))
+	for _, r := range rows {
+		// A user matched by both email and phone is listed once.
+		if _, dup := matched[r.UserID]; dup {
+			continue
+		}
+		matched[r.UserID] = struct{}{}
+		m := dto.ContactMatch{ID: r.UserID.String(), MatchedHash: r.Hash}
+		if r.Handle != nil {
+			m.Handle = *r.Handle
+		}
+		matches = append(matches, m)
+	}
+	return matches, nil
+}
+
+// contactHash is the server-side counterpart of the client hashing scheme.
+func contactHash(salt, kind, value string) string {
+	sum := sha256.Sum256([]byte(salt + ":" + kind + ":" + value))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizePhone strips formatting and validates E.164.
+func normalizePhone(phone string) (string, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		if r == '+' || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, phone)
+	if strings.HasPrefix(cleaned, "00") {
+		cleaned = "+" + cleaned[2:]
+	}
+	if !e164Pattern.MatchString(cleaned) {
+		return "", ErrInvalidPhone
+	}
+	return cleaned, nil
+}
+
+// putContactHash stores (or replaces) one identifier hash for the user.
+func putContactHash(db *gorm.DB, userID uuid.UUID, kind, hash string) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "kind"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hash", "updated_at"}),
+	}).Create(&models.ContactHash{UserID: userID, Kind: kind, Hash: hash}).Error
+}
+
+// syncEmailContactHash keeps the stored email hash in line with the user's
+// current address and opt-in; guests are never discoverable by email.
+func syncEmailContactHash(db *gorm.DB, salt string, user *models.User) error {
+	if !user.DiscoverableByContacts || isGuestEmail(user.Email) {
+		return db.Where("user_id = ? AND kind = ?", user.ID, models.ContactKindEmail).Delete(&models.ContactHash{}).Error
+	}
+	hash := contactHash(salt, models.ContactKindEmail, strings.ToLower(strings.TrimSpace(user.Email)))
+	return putContactHash(db, user.ID, models.ContactKindEmail, hash)
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	cases := map[string]string{
+		"+90 (555) 123-45-67": "+905551234567",
+		"0044 20 7946 0958":   "+442079460958",
+		"+1 415 555 0100":     "+14155550100",
+	}
+	for in, want := range cases {
+		got, err := normalizePhone(in)
+		if err != nil || got != want {
+			t.Errorf("normalizePhone(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"5551234567", "+0123456789", "+12"} {
+		if _, err := normalizePhone(bad); !errors.Is(err, ErrInvalidPhone) {
+			t.Errorf("normalizePhone(%q) should fail, got %v", bad, err)
+		}
+	}
+}
+
+func TestContactHashIsKindScoped(t *testing.T) {
+	email := contactHash("salt", "email", "ada@example.com")
+	if len(email) != 64 || !contactHashPattern.MatchString(email) {
+		t.Fatalf("unexpected hash format %q", email)
+	}
+	if email == contactHash("salt", "phone", "ada@example.com") || email == contactHash("other", "email", "ada@example.com") {
+		t.Error("hash must depend on salt and kind")
+	}
+}
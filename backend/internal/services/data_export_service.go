@@ -0,0 +1,475 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/queue"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ExportFormatJSON = "json"
+	ExportFormatZIP  = "zip"
+
+	maxExportImageBytes = 10 << 20
+)
+
+var (
+	ErrInvalidExportFormat = errors.New("format must be json or zip")
+	ErrExportNotFound      = errors.New("export not found")
+	ErrExportNotReady      = errors.New("export is not ready yet")
+)
+
+// DataExportService builds GDPR/CCPA data exports in the background and
+// notifies the user when the download is ready.
+type DataExportService struct {
+	db            *gorm.DB
+	cfg           *config.Config
+	friends       *FriendService
+	notifications *NotificationService
+	httpClient    *http.Client
+	tasks         queue.Queue
+}
+
+func NewDataExportService(db *gorm.DB, cfg *config.Config, friends *FriendService, notifications *NotificationService) *DataExportService {
+	return &DataExportService{
+		db:            db,
+		cfg:           cfg,
+		friends:       friends,
+		notifications: notifications,
+		httpClient:    newPublicHTTPClient(30 * time.Second),
+	}
+}
+
+// UseQueue hands new exports to the workers as soon as they are requested,
+// instead of leaving them for RunExportWorker's next sweep.
+func (s *DataExportService) UseQueue(tasks queue.Queue) {
+	s.tasks = tasks
+}
+
+// TaskExportBuild builds an export queued by RequestExport.
+const TaskExportBuild = "export.build"
+
+type exportTask struct {
+	ExportID uuid.UUID `json:"export_id"`
+}
+
+// RequestExport returns the user's in-progress or unexpired export of the
+// requested format, queueing a new one if there is none (or fresh is set).
+// created reports whether a new job was queued.
+func (s *DataExportService) RequestExport(userID uuid.UUID, format string, fresh bool) (*models.DataExport, bool, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = ExportFormatJSON
+	}
+	if format != ExportFormatJSON && format != ExportFormatZIP {
+		return nil, false, ErrInvalidExportFormat
+	}
+
+	var existing models.DataExport
+	err := s.db.Where("user_id = ? AND format = ? AND (status IN ? OR (status = ? AND expires_at > ?))",
+		userID, format, []string{models.ExportPending, models.ExportProcessing}, models.ExportReady, time.Now()).
+		Order("created_at DESC").
+		First(&existing).Error
+	inProgress := err == nil && existing.Status != models.ExportReady
+	if err == nil && (!fresh || inProgress) {
+		return &existing, false, nil
+	}
+
+	export := models.DataExport{UserID: userID, Format: format, Status: models.ExportPending}
+	if err := s.db.Create(&export).Error; err != nil {
+		return nil, false, err
+	}
+	if s.tasks != nil {
+		// The export stays pending either way; the sweep builds it if the
+		// task could not be queued.
+		if err := s.tasks.Enqueue(context.Background(), TaskExportBuild, exportTask{ExportID: export.ID}); err != nil {
+			log.Printf("export %s: %v", export.ID, err)
+		}
+	}
+	return &export, true, nil
+}
+
+// Get returns one of the user's exports.
+func (s *DataExportService) Get(userID, exportID uuid.UUID) (*models.DataExport, error) {
+	var export models.DataExport
+	if err := s.db.Where("id = ? AND user_id = ?", exportID, userID).First(&export).Error; err != nil {
+		return nil, ErrExportNotFound
+	}
+	return &export, nil
+}
+
+// OpenDownload returns the path and download filename of a ready export.
+func (s *DataExportService) OpenDownload(userID, exportID uuid.UUID) (string, string, error) {
+	export, err := s.Get(userID, exportID)
+	if err != nil {
+		return "", "", err
+	}
+	if export.Status != models.ExportReady || export.ExpiresAt == nil || time.Now().After(*export.ExpiresAt) {
+		return "", "", ErrExportNotReady
+	}
+	name := fmt.Sprintf("aurasnap-export-%s.%s", export.CreatedAt.UTC().Format("2006-01-02"), export.Format)
+	return export.FilePath, name, nil
+}
+
+// ExportResponse converts an export to its API form. baseURL is the API
+// origin used to build the download link.
+func ExportResponse(export *models.DataExport, baseURL string) dto.DataExportResponse {
+	resp := dto.DataExportResponse{
+		ID:          export.ID.String(),
+		Status:      export.Status,
+		Format:      export.Format,
+		SizeBytes:   export.SizeBytes,
+		Error:       export.Error,
+		ExpiresAt:   export.ExpiresAt,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+	}
+	if export.Status == models.ExportReady {
+		resp.DownloadURL = strings.TrimRight(baseURL, "/") + "/api/auth/export/" + export.ID.String() + "/download"
+	}
+	return resp
+}
+
+// RunExportWorker builds pending exports and removes expired files every
+// interval until ctx is cancelled. With a task queue most exports are built
+// by HandleExportTask first, and this sweep only catches the rest.
+func (s *DataExportService) RunExportWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				processed, err := s.ProcessNext(ctx)
+				if err != nil {
+					log.Printf("export: %v", err)
+				}
+				if !processed {
+					break
+				}
+			}
+			if n, err := s.PurgeExpired(time.Now()); err != nil {
+				log.Printf("export: purge failed: %v", err)
+			} else if n > 0 {
+				log.Printf("export: purged %d expired exports", n)
+			}
+		}
+	}
+}
+
+// ProcessNext claims one pending export (SKIP LOCKED, so several instances
+// can run the worker) and builds it. It reports whether a job was found.
+func (s *DataExportService) ProcessNext(ctx context.Context) (bool, error) {
+	return s.process(ctx, func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") })
+}
+
+// HandleExportTask builds the export a TaskExportBuild names, unless the
+// sweep got to it first.
+func (s *DataExportService) HandleExportTask(ctx context.Context, payload []byte) error {
+	var task exportTask
+	if err := queue.Decode(payload, &task); err != nil {
+		return err
+	}
+	_, err := s.process(ctx, func(db *gorm.DB) *gorm.DB { return db.Where("id = ?", task.ExportID) })
+	return err
+}
+
+// process claims the first pending export scope finds and builds it.
+func (s *DataExportService) process(ctx context.Context, scope func(*gorm.DB) *gorm.DB) (bool, error) {
+	var export models.DataExport
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", models.ExportPending).
+			Scopes(scope).
+			First(&export).Error; err != nil {
+			return err
+		}
+		return tx.Model(&export).Update("status", models.ExportProcessing).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	path, size, buildErr := s.build(ctx, &export)
+	now := time.Now()
+	if buildErr != nil {
+		s.db.Model(&export).Updates(map[string]interface{}{
+			"status":       models.ExportFailed,
+			"error":        truncateRunes(buildErr.Error(), 500),
+			"completed_at": now,
+		})
+		return true, fmt.Errorf("export %s failed: %w", export.ID, buildErr)
+	}
+
+	expiresAt := now.Add(s.exportTTL())
+	if err := s.db.Model(&export).Updates(map[string]interface{}{
+		"status":       models.ExportReady,
+		"file_path":    path,
+		"size_bytes":   size,
+		"expires_at":   expiresAt,
+		"completed_at": now,
+	}).Error; err != nil {
+		return true, err
+	}
+
+	s.notifyReady(&export, expiresAt)
+	return true, nil
+}
+
+// PurgeExpired deletes files and rows of exports past their expiry.
+func (s *DataExportService) PurgeExpired(now time.Time) (int, error) {
+	var expired []models.DataExport
+	if err := s.db.Where("expires_at <= ?", now).Find(&expired).Error; err != nil {
+		return 0, err
+	}
+	for _, e := range expired {
+		removeExportFile(e.FilePath)
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	return len(expired), s.db.Delete(&expired).Error
+}
+
+// DeleteUserExports removes all export files and rows (account deletion).
+func DeleteUserExports(tx *gorm.DB, userID uuid.UUID) error {
+	var exports []models.DataExport
+	tx.Where("user_id = ?", userID).Find(&exports)
+	for _, e := range exports {
+		removeExportFile(e.FilePath)
+	}
+	return tx.Where("user_id = ?", userID).Delete(&models.DataExport{}).Error
+}
+
+func (s *DataExportService) build(ctx context.Context, export *models.DataExport) (string, int64, error) {
+	bundle, err := s.collect(export.UserID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	dir := filepath.Join(s.cfg.ExportDir, export.UserID.String())
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(dir, export.ID.String()+"."+export.Format)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", 0, err
+	}
+	if export.Format == ExportFormatZIP {
+		err = s.writeZIP(ctx, f, bundle)
+	} else {
+		err = writeExportJSON(f, bundle)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), nil
+}
+
+func (s *DataExportService) collect(userID uuid.UUID) (*dto.UserDataExport, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	bundle := &dto.UserDataExport{
+		ExportedAt: time.Now().UTC(),
+		Profile: dto.ExportProfile{
+			ID:                     user.ID.String(),
+			Email:                  user.Email,
+			Timezone:               user.Timezone,
+			EmailVerifiedAt:        user.EmailVerifiedAt,
+			AIMemoryEnabled:        user.AIMemoryEnabled,
+			ResearchConsent:        user.ResearchConsent,
+			DiscoverableByHandle:   user.DiscoverableByHandle,
+			DiscoverableByContacts: user.DiscoverableByContacts,
+			LeaderboardOptIn:       user.LeaderboardOptIn,
+			SimilarDiscoveryOptIn:  user.SimilarDiscoveryOptIn,
+			BirthYear:              user.BirthYear,
+			SignInWithApple:        user.AppleSub != nil,
+			CreatedAt:              user.CreatedAt,
+		},
+		AuraReadings: []models.AuraReading{},
+		AuraMatches:  []models.AuraMatch{},
+		ScanDays:     []string{},
+		Memories:     []models.UserMemory{},
+		AIHistory:    []models.AIInteraction{},
+		Friends:      []dto.FriendResponse{},
+	}
+	if user.Handle != nil {
+		bundle.Profile.Handle = *user.Handle
+	}
+	if user.LeaderboardName != nil {
+		bundle.Profile.LeaderboardName = *user.LeaderboardName
+	}
+
+	if err := s.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&bundle.AuraReadings).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Where("user_id = ? OR friend_id = ?", userID, userID).Order("created_at ASC").Find(&bundle.AuraMatches).Error; err != nil {
+		return nil, err
+	}
+	var streak models.AuraStreak
+	if err := s.db.Where("user_id = ?", userID).First(&streak).Error; err == nil {
+		bundle.Streak = &streak
+	}
+	// Streak history: every local day with at least one scan.
+	seen := map[string]bool{}
+	loc := userLocation(s.db, userID, "")
+	for _, r := range bundle.AuraReadings {
+		day := r.CreatedAt.In(loc).Format("2006-01-02")
+		if !seen[day] {
+			seen[day] = true
+			bundle.ScanDays = append(bundle.ScanDays, day)
+		}
+	}
+	if err := s.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&bundle.Memories).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&bundle.AIHistory).Error; err != nil {
+		return nil, err
+	}
+	if friends, err := s.friends.ListFriends(userID); err == nil {
+		bundle.Friends = friends
+	}
+	if prefs, err := s.notifications.GetPreferences(userID); err == nil {
+		bundle.Notification = prefs
+	}
+	return bundle, nil
+}
+
+func writeExportJSON(w io.Writer, bundle *dto.UserDataExport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bundle)
+}
+
+// writeZIP stores data.json plus the photos behind each reading's image URL.
+// Photos that can't be fetched are listed with the error instead.
+func (s *DataExportService) writeZIP(ctx context.Context, w io.Writer, bundle *dto.UserDataExport) error {
+	zw := zip.NewWriter(w)
+
+	for _, r := range bundle.AuraReadings {
+		img := dto.ExportImage{ReadingID: r.ID.String(), SourceURL: r.ImageURL}
+		u, err := url.Parse(r.ImageURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+			continue
+		}
+		name, err := s.addImage(ctx, zw, r.ID.String(), r.ImageURL)
+		if err != nil {
+			img.Error = err.Error()
+		} else {
+			img.File = name
+		}
+		bundle.Images = append(bundle.Images, img)
+	}
+
+	f, err := zw.Create("data.json")
+	if err != nil {
+		return err
+	}
+	if err := writeExportJSON(f, bundle); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func (s *DataExportService) addImage(ctx context.Context, zw *zip.Writer, readingID, imageURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch failed: status %d", resp.StatusCode)
+	}
+
+	ext := ".jpg"
+	if exts, _ := mime.ExtensionsByType(resp.Header.Get("Content-Type")); len(exts) > 0 {
+		ext = exts[0]
+	}
+	name := "images/" + readingID + ext
+	f, err := zw.Create(name)
+	if err != nil {
+		return "", err
+	}
+	n, err := io.Copy(f, io.LimitReader(resp.Body, maxExportImageBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if n > maxExportImageBytes {
+		return "", fmt.Errorf("image larger than %d MB, truncated", maxExportImageBytes>>20)
+	}
+	return name, nil
+}
+
+func (s *DataExportService) notifyReady(export *models.DataExport, expiresAt time.Time) {
+	if s.notifications == nil {
+		return
+	}
+	_, err := s.notifications.Notify(export.UserID, NotificationMessage{
+		Category: CategorySecurity,
+		Title:    "Your AuraSnap data export is ready",
+		Body:     fmt.Sprintf("Download it from Settings → Privacy before %s.", expiresAt.UTC().Format("Jan 2, 2006")),
+		Data:     map[string]string{"event": "data_export_ready", "export_id": export.ID.String()},
+	})
+	if err != nil {
+		log.Printf("export: notify %s: %v", export.UserID, err)
+	}
+}
+
+func (s *DataExportService) exportTTL() time.Duration {
+	if s.cfg.ExportTTL <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return s.cfg.ExportTTL
+}
+
+func removeExportFile(path string) {
+	if path == "" {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("export: remove %s: %v", path, err)
+	}
+}
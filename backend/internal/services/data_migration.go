@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	migrationBatchSize   = 500
+	migrationBatchesTick = 20
+	auditTargetMigration = "data_migration"
+)
+
+var (
+	ErrMigrationNotFound     = errors.New("data migration not found")
+	ErrInvalidMigrationPhase = errors.New("phase must be one of pending, double_write, backfill, verify, cutover, complete")
+	ErrMigrationPhaseBlocked = errors.New("phase change not allowed")
+)
+
+// dataMigration reshapes existing data online. The tracker moves it through
+// models.MigrationPhases; the migration supplies the per-phase work:
+//
+//   - double_write: write paths also write the new shape (the migration's
+//     own hook, called from those paths);
+//   - backfill: Backfill converts old rows in cursor order;
+//   - verify: Verify compares both shapes in cursor order;
+//   - cutover: reads switch to the new shape (the migration's own check).
+//
+// Backfill and Verify must be idempotent: a batch may run again after a
+// crash.
+type dataMigration interface {
+	Name() string
+	Description() string
+	// Count is how many source rows backfill and verify walk.
+	Count(db *gorm.DB) (int64, error)
+	// Backfill converts up to limit rows after cursor ("" = start) and
+	// returns the cursor of the last one and how many it converted.
+	Backfill(tx *gorm.DB, cursor string, limit int) (string, int, error)
+	// Verify checks up to limit rows after cursor and returns the cursor of
+	// the last one, how many it checked and how many disagreed.
+	Verify(db *gorm.DB, cursor string, limit int) (string, int, int, error)
+}
+
+// dataMigrations is every migration the tracker knows about.
+var dataMigrations = []dataMigration{readingTraitsMigration{}}
+
+func findDataMigration(name string) dataMigration {
+	for _, m := range dataMigrations {
+		if m.Name() == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// migrationPhaseAtLeast reports whether the named migration has reached
+// phase. Unknown or untracked migrations are pending.
+func migrationPhaseAtLeast(db *gorm.DB, name, phase string) bool {
+	var current string
+	if err := db.Model(&models.DataMigration{}).Where("name = ?", name).Pluck("phase", &current).Error; err != nil || current == "" {
+		return phase == models.MigrationPending
+	}
+	return slices.Index(models.MigrationPhases, current) >= slices.Index(models.MigrationPhases, phase)
+}
+
+// DataMigrationService tracks and runs data migrations for the admin
+// dashboard.
+type DataMigrationService struct {
+	db *gorm.DB
+}
+
+func NewDataMigrationService(db *gorm.DB) *DataMigrationService {
+	return &DataMigrationService{db: db}
+}
+
+// List returns every registered migration with its progress.
+func (s *DataMigrationService) List() ([]dto.DataMigrationStatus, error) {
+	statuses := make([]dto.DataMigrationStatus, 0, len(dataMigrations))
+	for _, m := range dataMigrations {
+		row, err := s.ensure(s.db, m)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, migrationStatus(m, row))
+	}
+	return statuses, nil
+}
+
+// SetPhase moves a migration to another phase. Moving forward goes one
+// phase at a time, and only once the current phase's work is done: verify
+// after a finished backfill, cutover after a clean verification. Moving
+// back is always allowed before complete, which is the point of the
+// double-write approach. Starting backfill or verify resets its progress.
+func (s *DataMigrationService) SetPhase(adminID uuid.UUID, name string, req *dto.DataMigrationPhaseRequest) (*dto.DataMigrationStatus, error) {
+	m := findDataMigration(name)
+	if m == nil {
+		return nil, ErrMigrationNotFound
+	}
+	target := slices.Index(models.MigrationPhases, req.Phase)
+	if target < 0 {
+		return nil, ErrInvalidMigrationPhase
+	}
+
+	var row *models.DataMigration
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if row, err = s.ensure(tx, m); err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(row, "id = ?", row.ID).Error; err != nil {
+			return err
+		}
+		current := slices.Index(models.MigrationPhases, row.Phase)
+		if err := phaseChangeAllowed(row, current, target); err != nil {
+			return err
+		}
+
+		from := row.Phase
+		row.Phase = req.Phase
+		row.LastError = ""
+		switch req.Phase {
+		case models.MigrationBackfill:
+			row.Backfilled, row.BackfillCursor, row.BackfilledAt = 0, "", nil
+			if row.Total, err = m.Count(tx); err != nil {
+				return err
+			}
+		case models.MigrationVerify:
+			row.Verified, row.Mismatches, row.VerifyCursor, row.VerifiedAt = 0, 0, "", nil
+			if row.Total, err = m.Count(tx); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(row).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, &adminID, "migration.phase_changed", auditTargetMigration, row.ID, map[string]any{
+			"migration": name,
+			"from":      from,
+			"to":        req.Phase,
+			"reason":    truncateRunes(req.Reason, 500),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	status := migrationStatus(m, row)
+	return &status, nil
+}
+
+func phaseChangeAllowed(row *models.DataMigration, current, target int) error {
+	switch {
+	case target == current:
+		return nil
+	case row.Phase == models.MigrationComplete:
+		return fmt.Errorf("%w: the migration is complete", ErrMigrationPhaseBlocked)
+	case target < current:
+		return nil
+	case target > current+1:
+		return fmt.Errorf("%w: phases advance one at a time", ErrMigrationPhaseBlocked)
+	case row.Phase == models.MigrationBackfill && row.BackfilledAt == nil:
+		return fmt.Errorf("%w: the backfill has not finished", ErrMigrationPhaseBlocked)
+	case row.Phase == models.MigrationVerify && (row.VerifiedAt == nil || row.Mismatches > 0):
+		return fmt.Errorf("%w: verification has not passed cleanly", ErrMigrationPhaseBlocked)
+	}
+	return nil
+}
+
+// ensure returns the tracking row of m, creating it as pending.
+func (s *DataMigrationService) ensure(db *gorm.DB, m dataMigration) (*models.DataMigration, error) {
+	row := models.DataMigration{Name: m.Name()}
+	if err := db.Where(models.DataMigration{Name: m.Name()}).FirstOrCreate(&row).Error; err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// RunMigrationWorker advances migrations in the backfill and verify phases
+// every interval until ctx is cancelled.
+func (s *DataMigrationService) RunMigrationWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, m := range dataMigrations {
+				if err := s.Step(ctx, m.Name()); err != nil {
+					log.Printf("migrations: %s: %v", m.Name(), err)
+				}
+			}
+		}
+	}
+}
+
+// Step runs a few batches of the migration's current phase. Each batch
+// commits with its progress, so a restart resumes where it stopped.
+func (s *DataMigrationService) Step(ctx context.Context, name string) error {
+	m := findDataMigration(name)
+	if m == nil {
+		return ErrMigrationNotFound
+	}
+	for range migrationBatchesTick {
+		if ctx.Err() != nil {
+			return nil
+		}
+		more, err := s.batch(ctx, m)
+		if err != nil {
+			s.db.Model(&models.DataMigration{}).Where("name = ?", name).Update("last_error", err.Error())
+			return err
+		}
+		if !more {
+			return nil
+		}
+	}
+	return nil
+}
+
+// batch runs one batch and reports whether the phase has work left.
+func (s *DataMigrationService) batch(ctx context.Context, m dataMigration) (bool, error) {
+	more := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var row models.DataMigration
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&row, "name = ?", m.Name()).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		now := time.Now()
+		switch {
+		case row.Phase == models.MigrationBackfill && row.BackfilledAt == nil:
+			cursor, n, err := m.Backfill(tx, row.BackfillCursor, migrationBatchSize)
+			if err != nil {
+				return err
+			}
+			row.Backfilled += int64(n)
+			if n > 0 {
+				row.BackfillCursor = cursor
+			}
+			if n < migrationBatchSize {
+				row.BackfilledAt = &now
+			}
+			more = row.BackfilledAt == nil
+		case row.Phase == models.MigrationVerify && row.VerifiedAt == nil:
+			cursor, n, mismatches, err := m.Verify(tx, row.VerifyCursor, migrationBatchSize)
+			if err != nil {
+				return err
+			}
+			row.Verified += int64(n)
+			row.Mismatches += int64(mismatches)
+			if n > 0 {
+				row.VerifyCursor = cursor
+			}
+			if n < migrationBatchSize {
+				row.VerifiedAt = &now
+			}
+			more = row.VerifiedAt == nil
+		default:
+			return nil
+		}
+		row.LastError = ""
+		return tx.Save(&row).Error
+	})
+	return more, err
+}
+
+func migrationStatus(m dataMigration, row *models.DataMigration) dto.DataMigrationStatus {
+	status := dto.DataMigrationStatus{
+		Name:         m.Name(),
+		Description:  m.Description(),
+		Phase:        row.Phase,
+		Total:        row.Total,
+		Backfilled:   row.Backfilled,
+		BackfilledAt: row.BackfilledAt,
+		Verified:     row.Verified,
+		Mismatches:   row.Mismatches,
+		VerifiedAt:   row.VerifiedAt,
+		LastError:    row.LastError,
+		UpdatedAt:    row.UpdatedAt,
+	}
+	if row.Total > 0 {
+		status.BackfillPercent = min(100, float64(row.Backfilled)*100/float64(row.Total))
+		status.VerifyPercent = min(100, float64(row.Verified)*100/float64(row.Total))
+	}
+	if i := slices.Index(models.MigrationPhases, row.Phase); i >= 0 && i+1 < len(models.MigrationPhases) {
+		status.NextPhase = models.MigrationPhases[i+1]
+	}
+	return status
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestPhaseChangeAllowed(t *testing.T) {
+	phase := func(p string) int { return slices.Index(models.MigrationPhases, p) }
+	done := time.Now()
+
+	cases := []struct {
+		name string
+		row  models.DataMigration
+		to   string
+		ok   bool
+	}{
+		{"start double write", models.DataMigration{Phase: models.MigrationPending}, models.MigrationDoubleWrite, true},
+		{"skip a phase", models.DataMigration{Phase: models.MigrationPending}, models.MigrationBackfill, false},
+		{"backfill running", models.DataMigration{Phase: models.MigrationBackfill}, models.MigrationVerify, false},
+		{"backfill done", models.DataMigration{Phase: models.MigrationBackfill, BackfilledAt: &done}, models.MigrationVerify, true},
+		{"verify mismatches", models.DataMigration{Phase: models.MigrationVerify, VerifiedAt: &done, Mismatches: 3}, models.MigrationCutover, false},
+		{"verify clean", models.DataMigration{Phase: models.MigrationVerify, VerifiedAt: &done}, models.MigrationCutover, true},
+		{"roll back cutover", models.DataMigration{Phase: models.MigrationCutover}, models.MigrationDoubleWrite, true},
+		{"roll back complete", models.DataMigration{Phase: models.MigrationComplete}, models.MigrationCutover, false},
+	}
+	for _, tc := range cases {
+		err := phaseChangeAllowed(&tc.row, phase(tc.row.Phase), phase(tc.to))
+		if tc.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		if !tc.ok && !errors.Is(err, ErrMigrationPhaseBlocked) {
+			t.Errorf("%s: err = %v, want ErrMigrationPhaseBlocked", tc.name, err)
+		}
+	}
+}
+
+func TestMigrationStatusProgress(t *testing.T) {
+	status := migrationStatus(readingTraitsMigration{}, &models.DataMigration{
+		Phase: models.MigrationVerify, Total: 200, Backfilled: 200, Verified: 50,
+	})
+	if status.BackfillPercent != 100 || status.VerifyPercent != 25 {
+		t.Errorf("progress = %v/%v, want 100/25", status.BackfillPercent, status.VerifyPercent)
+	}
+	if status.NextPhase != models.MigrationCutover {
+		t.Errorf("next phase = %q, want cutover", status.NextPhase)
+	}
+	if done := migrationStatus(readingTraitsMigration{}, &models.DataMigration{Phase: models.MigrationComplete}); done.NextPhase != "" {
+		t.Errorf("complete migration has next phase %q", done.NextPhase)
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var ErrEmailUnchanged = errors.New("new email matches the current email")
+
+// RequestEmailChange mails a confirmation link to the new address. The
+// account keeps its current email until ConfirmEmailChange succeeds.
+func (s *AuthService) RequestEmailChange(userID uuid.UUID, req *dto.ChangeEmailRequest) (string, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return "", ErrUserNotFound
+	}
+	// Guests have no real address yet; they must claim the account instead.
+	if isGuestEmail(user.Email) {
+		return "", ErrGuestOnlyAction
+	}
+
+	// Apple Sign-In users have no password to confirm.
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+			return "", ErrInvalidCredentials
+		}
+	}
+
+	addr, err := mail.ParseAddress(strings.TrimSpace(req.NewEmail))
+	if err != nil || isGuestEmail(addr.Address) {
+		return "", ErrInvalidEmailAddress
+	}
+	newEmail := strings.ToLower(addr.Address)
+	if newEmail == strings.ToLower(user.Email) {
+		return "", ErrEmailUnchanged
+	}
+	if s.emailTaken(s.db, newEmail, userID) {
+		return "", ErrEmailTaken
+	}
+	if s.emails.IsSuppressed(newEmail) {
+		return "", ErrEmailSuppressed
+	}
+
+	ttl := s.cfg.EmailChangeTTL
+	token, err := issueAuthToken(s.db, userID, models.TokenPurposeEmailChange, newEmail, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	data := map[string]any{
+		"ConfirmURL":   authTokenLink(s.cfg.EmailChangeURL, token),
+		"ExpiresHours": int(ttl.Hours()),
+	}
+	if err := s.emails.Send(newEmail, "email_change", req.Locale, data); err != nil {
+		return "", err
+	}
+	return newEmail, nil
+}
+
+// ConfirmEmailChange applies the pending address and tells the old address
+// about it, so a hijacked account is noticed by its owner.
+func (s *AuthService) ConfirmEmailChange(req *dto.ConfirmEmailChangeRequest, locale string) (*dto.UserResponse, error) {
+	var user models.User
+	var oldEmail string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeAuthToken(tx, req.Token, models.TokenPurposeEmailChange)
+		if err != nil {
+			return err
+		}
+		if err := tx.First(&user, "id = ?", token.UserID).Error; err != nil {
+			return ErrUserNotFound
+		}
+		// The address may have been registered since the link was sent.
+		if s.emailTaken(tx, token.Payload, user.ID) {
+			return ErrEmailTaken
+		}
+
+		// Following the link proves ownership of the new address.
+		oldEmail = user.Email
+		now := time.Now()
+		user.Email = token.Payload
+		user.EmailVerifiedAt = &now
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"email":             token.Payload,
+			"email_verified_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		return syncEmailContactHash(tx, s.cfg.ContactDiscoverySalt, &user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		data := map[string]any{"NewEmail": maskEmail(user.Email), "SupportEmail": s.cfg.SupportEmail}
+		if err := s.emails.Send(oldEmail, "email_changed", locale, data); err != nil {
+			log.Printf("email change notice for user %s: %v", user.ID, err)
+		}
+	}()
+	return userResponse(&user), nil
+}
+
+// pendingEmailChange returns the unconfirmed new address, if any.
+func (s *AuthService) pendingEmailChange(userID uuid.UUID) string {
+	var token models.AuthToken
+	err := s.db.Where("user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > NOW()", userID, models.TokenPurposeEmailChange).
+		Order("created_at DESC").
+		First(&token).Error
+	if err != nil {
+		return ""
+	}
+	return token.Payload
+}
+
+func (s *AuthService) emailTaken(db *gorm.DB, email string, exceptUserID uuid.UUID) bool {
+	var count int64
+	db.Model(&models.User{}).Where("LOWER(email) = ? AND id <> ?", strings.ToLower(email), exceptUserID).Count(&count)
+	return count > 0
+}
+
+// maskEmail keeps the first character of the local part: "ada@x.com" -> "a***@x.com".
+func maskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+	return string([]rune(local)[:1]) + "***@" + domain
+}
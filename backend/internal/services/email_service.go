@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/emails"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidEmailAddress = errors.New("invalid email address")
+
+// EmailService renders templated emails and hands them to the mailer.
+type EmailService struct {
+	db       *gorm.DB
+	renderer *emails.Renderer
+	mailer   Mailer
+}
+
+func NewEmailService(db *gorm.DB, renderer *emails.Renderer, mailer Mailer) *EmailService {
+	return &EmailService{db: db, renderer: renderer, mailer: mailer}
+}
+
+// Templates lists available templates and their locales.
+func (s *EmailService) Templates() map[string][]string {
+	return s.renderer.Templates()
+}
+
+// Preview renders a template; nil data uses the template's sample data.
+func (s *EmailService) Preview(name, locale string, data map[string]any) (*emails.Message, error) {
+	if data == nil {
+		data = emails.SampleData(name)
+	}
+	return s.renderer.Render(name, locale, data)
+}
+
+// Send renders and delivers a template to one address. Addresses that
+// hard-bounced or complained are refused with ErrEmailSuppressed.
+func (s *EmailService) Send(to, name, locale string, data map[string]any) error {
+	addr, err := mail.ParseAddress(strings.TrimSpace(to))
+	if err != nil {
+		return ErrInvalidEmailAddress
+	}
+	if s.IsSuppressed(addr.Address) {
+		return ErrEmailSuppressed
+	}
+	msg, err := s.renderer.Render(name, locale, data)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(addr.Address, msg)
+}
+
+// SendTest delivers a preview to a designer's inbox with a [TEST] subject.
+func (s *EmailService) SendTest(to, name, locale string, data map[string]any) error {
+	addr, err := mail.ParseAddress(strings.TrimSpace(to))
+	if err != nil {
+		return ErrInvalidEmailAddress
+	}
+	if s.IsSuppressed(addr.Address) {
+		return ErrEmailSuppressed
+	}
+	msg, err := s.Preview(name, locale, data)
+	if err != nil {
+		return err
+	}
+	msg.Subject = "[TEST] " + msg.Subject
+	return s.mailer.Send(addr.Address, msg)
+}
+
+// NotificationSender exposes email as a channel of the notification dispatcher.
+func (s *EmailService) NotificationSender(db *gorm.DB) NotificationSender {
+	return &emailNotificationSender{db: db, emails: s}
+}
+
+type emailNotificationSender struct {
+	db     *gorm.DB
+	emails *EmailService
+}
+
+func (e *emailNotificationSender) Channel() string { return ChannelEmail }
+
+func (e *emailNotificationSender) Send(userID uuid.UUID, msg NotificationMessage) error {
+	var user models.User
+	if err := e.db.Select("email").First(&user, "id = ?", userID).Error; err != nil {
+		return ErrUserNotFound
+	}
+	// Guest accounts have placeholder addresses that cannot receive mail.
+	if isGuestEmail(user.Email) {
+		return nil
+	}
+
+	data := map[string]any{"Title": msg.Title, "Body": msg.Body, "URL": msg.Data["url"]}
+	err := e.emails.Send(user.Email, "notification", msg.Data["locale"], data)
+	if errors.Is(err, ErrEmailSuppressed) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("email notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrEmailSuppressed       = errors.New("email address is suppressed after a bounce or complaint")
+	ErrSuppressionNotFound   = errors.New("suppression not found")
+	ErrInvalidSNSMessage     = errors.New("invalid SNS message")
+	ErrUntrustedSubscribeURL = errors.New("subscribe URL is not an Amazon SNS endpoint")
+)
+
+// IsSuppressed reports whether sends to the address are blocked.
+func (s *EmailService) IsSuppressed(email string) bool {
+	var count int64
+	s.db.Model(&models.EmailSuppression{}).Where("email = ?", normalizeEmail(email)).Count(&count)
+	return count > 0
+}
+
+// ListSuppressions returns suppressed addresses, newest first.
+func (s *EmailService) ListSuppressions(limit, offset int) ([]models.EmailSuppression, int64, error) {
+	var rows []models.EmailSuppression
+	var total int64
+	query := s.db.Model(&models.EmailSuppression{})
+	query.Count(&total)
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// Unsuppress lifts a suppression, e.g. after a user fixes their mailbox.
+func (s *EmailService) Unsuppress(email string) error {
+	result := s.db.Where("email = ?", normalizeEmail(email)).Delete(&models.EmailSuppression{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSuppressionNotFound
+	}
+	return nil
+}
+
+// HandleSNSMessage processes an SNS delivery carrying SES feedback, once per
+// SNS message ID. Subscription confirmations are accepted by visiting
+// SubscribeURL.
+func (s *EmailService) HandleSNSMessage(ctx context.Context, msg *dto.SNSMessage) (int, error) {
+	switch msg.Type {
+	case "SubscriptionConfirmation":
+		return 0, confirmSNSSubscription(ctx, msg.SubscribeURL)
+	case "Notification":
+		var n dto.SESNotification
+		if err := json.Unmarshal([]byte(msg.Message), &n); err != nil {
+			return 0, ErrInvalidSNSMessage
+		}
+		suppressed := 0
+		_, err := consumeOnce(s.db.WithContext(ctx), models.EventSourceSES, msg.MessageID, msg.Message, func(tx *gorm.DB) error {
+			var err error
+			suppressed, err = applySESNotification(tx, &n)
+			return err
+		})
+		return suppressed, err
+	case "UnsubscribeConfirmation":
+		return 0, nil
+	}
+	return 0, ErrInvalidSNSMessage
+}
+
+func applySESNotification(db *gorm.DB, n *dto.SESNotification) (int, error) {
+	rows := sesSuppressions(n)
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	// A later complaint or bounce for the same address refreshes the reason.
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "email"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "source", "detail", "updated_at"}),
+	}).Create(&rows).Error
+	if err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// sesSuppressions maps an SES notification to suppression rows. Only
+// permanent bounces and complaints suppress; transient bounces are retried.
+func sesSuppressions(n *dto.SESNotification) []models.EmailSuppression {
+	kind := n.NotificationType
+	if kind == "" {
+		kind = n.EventType
+	}
+
+	var rows []models.EmailSuppression
+	add := func(email, reason, detail string) {
+		email = normalizeEmail(email)
+		if email == "" {
+			return
+		}
+		rows = append(rows, models.EmailSuppression{
+			Email:  email,
+			Reason: reason,
+			Source: "ses",
+			Detail: truncateRunes(detail, 500),
+		})
+	}
+
+	switch kind {
+	case "Bounce":
+		if n.Bounce == nil || n.Bounce.BounceType != "Permanent" {
+			return nil
+		}
+		for _, r := range n.Bounce.BouncedRecipients {
+			detail := r.DiagnosticCode
+			if detail == "" {
+				detail = n.Bounce.BounceSubType
+			}
+			add(r.EmailAddress, models.SuppressionHardBounce, detail)
+		}
+	case "Complaint":
+		if n.Complaint == nil {
+			return nil
+		}
+		for _, r := range n.Complaint.ComplainedRecipients {
+			add(r.EmailAddress, models.SuppressionComplaint, n.Complaint.ComplaintFeedbackType)
+		}
+	}
+	return rows
+}
+
+// confirmSNSSubscription visits the confirmation link, restricted to SNS
+// hosts so the webhook cannot be used to make arbitrary outbound requests.
+func confirmSNSSubscription(ctx context.Context, subscribeURL string) error {
+	u, err := url.Parse(subscribeURL)
+	if err != nil || u.Scheme != "https" || !strings.HasPrefix(u.Hostname(), "sns.") || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+		return ErrUntrustedSubscribeURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("confirm SNS subscription: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("confirm SNS subscription: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func truncateRunes(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
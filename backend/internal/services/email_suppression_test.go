@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestSESSuppressions(t *testing.T) {
+	hard := &dto.SESNotification{
+		NotificationType: "Bounce",
+		Bounce: &dto.SESBounce{
+			BounceType:    "Permanent",
+			BounceSubType: "General",
+			BouncedRecipients: []dto.SESRecipient{
+				{EmailAddress: " Gone@Example.com ", DiagnosticCode: "smtp; 550 5.1.1 user unknown"},
+				{EmailAddress: ""},
+			},
+		},
+	}
+	rows := sesSuppressions(hard)
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 suppression, got %d", len(rows))
+	}
+	if rows[0].Email != "gone@example.com" || rows[0].Reason != models.SuppressionHardBounce || rows[0].Detail == "" {
+		t.Errorf("unexpected suppression: %+v", rows[0])
+	}
+
+	soft := &dto.SESNotification{
+		NotificationType: "Bounce",
+		Bounce: &dto.SESBounce{
+			BounceType:        "Transient",
+			BouncedRecipients: []dto.SESRecipient{{EmailAddress: "full@example.com"}},
+		},
+	}
+	if rows := sesSuppressions(soft); len(rows) != 0 {
+		t.Errorf("transient bounce should not suppress, got %+v", rows)
+	}
+
+	complaint := &dto.SESNotification{
+		EventType: "Complaint",
+		Complaint: &dto.SESComplaint{
+			ComplaintFeedbackType: "abuse",
+			ComplainedRecipients:  []dto.SESRecipient{{EmailAddress: "angry@example.com"}},
+		},
+	}
+	rows = sesSuppressions(complaint)
+	if len(rows) != 1 || rows[0].Reason != models.SuppressionComplaint {
+		t.Errorf("complaint event should suppress, got %+v", rows)
+	}
+}
+
+func TestConfirmSNSSubscriptionRejectsForeignHosts(t *testing.T) {
+	for _, u := range []string{
+		"http://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription",
+		"https://evil.example.com/?Action=ConfirmSubscription",
+		"https://sns.us-east-1.amazonaws.com.evil.example/",
+	} {
+		if err := confirmSNSSubscription(t.Context(), u); err != ErrUntrustedSubscribeURL {
+			t.Errorf("%s: expected ErrUntrustedSubscribeURL, got %v", u, err)
+		}
+	}
+}
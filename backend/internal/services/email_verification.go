@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrEmailAlreadyVerified = errors.New("email is already verified")
+
+// sendVerificationEmail issues a verification token for the user's current
+// address and mails it in the background.
+func (s *AuthService) sendVerificationEmail(user *models.User, locale string) error {
+	if isGuestEmail(user.Email) {
+		return nil
+	}
+
+	ttl := s.cfg.EmailVerifyTTL
+	token, err := issueAuthToken(s.db, user.ID, models.TokenPurposeEmailVerify, user.Email, ttl)
+	if err != nil {
+		return err
+	}
+
+	to, userID := user.Email, user.ID
+	data := map[string]any{
+		"VerifyURL":    authTokenLink(s.cfg.EmailVerifyURL, token),
+		"ExpiresHours": int(ttl.Hours()),
+	}
+	go func() {
+		if err := s.emails.Send(to, "verify_email", locale, data); err != nil {
+			log.Printf("verification email for user %s: %v", userID, err)
+		}
+	}()
+	return nil
+}
+
+// VerifyEmail marks the address the token was issued for as verified. A
+// token sent before an email change no longer applies.
+func (s *AuthService) VerifyEmail(req *dto.VerifyEmailRequest) (*dto.UserResponse, error) {
+	var user models.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeAuthToken(tx, req.Token, models.TokenPurposeEmailVerify)
+		if err != nil {
+			return err
+		}
+		if err := tx.First(&user, "id = ?", token.UserID).Error; err != nil {
+			return ErrUserNotFound
+		}
+		if user.Email != token.Payload {
+			return ErrInvalidAuthToken
+		}
+
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		return tx.Model(&user).Update("email_verified_at", now).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userResponse(&user), nil
+}
+
+// ResendVerification mails a fresh link, invalidating the previous one.
+func (s *AuthService) ResendVerification(userID uuid.UUID, locale string) error {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return ErrUserNotFound
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+	if isGuestEmail(user.Email) {
+		return ErrGuestOnlyAction
+	}
+	return s.sendVerificationEmail(&user, locale)
+}
+
+// IsEmailVerified backs the RequireVerifiedEmail middleware.
+func (s *AuthService) IsEmailVerified(userID uuid.UUID) bool {
+	var count int64
+	s.db.Model(&models.User{}).Where("id = ? AND email_verified_at IS NOT NULL", userID).Count(&count)
+	return count > 0
+}
+
+func userResponse(user *models.User) *dto.UserResponse {
+	return &dto.UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt != nil,
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const openAIEmbeddingModel = "text-embedding-3-small"
+
+// Embedder turns texts into vectors for theme clustering, one per text in
+// order.
+type Embedder interface {
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// NewEmbedder picks the embedder from EMBEDDINGS_DRIVER ("openai"). Without
+// a configured driver it returns nil and themes come from keyword
+// extraction instead.
+func NewEmbedder(cfg *config.Config) (Embedder, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.EmbeddingsDriver)) {
+	case "openai":
+		if cfg.OpenAIAPIKey == "" {
+			return nil, fmt.Errorf("EMBEDDINGS_DRIVER=openai requires OPENAI_API_KEY")
+		}
+		return &openAIEmbedder{apiKey: cfg.OpenAIAPIKey, client: &http.Client{Timeout: 30 * time.Second}}, nil
+	case "", "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown EMBEDDINGS_DRIVER %q", cfg.EmbeddingsDriver)
+	}
+}
+
+type openAIEmbedder struct {
+	apiKey string
+	client *http.Client
+}
+
+func (e *openAIEmbedder) Name() string { return "openai" }
+
+func (e *openAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	bodyBytes, err := json.Marshal(map[string]any{"model": openAIEmbeddingModel, "input": texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	ctx, span := tracer.Start(ctx, "aura.ai.openai_embeddings", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("ai.provider", "openai"), attribute.String("ai.model", openAIEmbeddingModel)))
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/embeddings", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+	start := time.Now()
+	resp, err := e.client.Do(req)
+	if err != nil {
+		metrics.AIProviderDuration.WithLabelValues("openai_embeddings", "error").Observe(time.Since(start).Seconds())
+		span.RecordError(err)
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	outcome := "success"
+	if resp.StatusCode != http.StatusOK {
+		outcome = "error"
+	}
+	metrics.AIProviderDuration.WithLabelValues("openai_embeddings", outcome).Observe(time.Since(start).Seconds())
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OpenAI embeddings returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse embeddings response: %w", err)
+	}
+	vectors := make([][]float64, len(texts))
+	for _, d := range parsed.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			vectors[d.Index] = d.Embedding
+		}
+	}
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("OpenAI embeddings missing vector %d", i)
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUpgradeRequired   = errors.New("this feature is not included in your plan")
+	ErrMatchLimitReached = errors.New("daily match limit reached")
+)
+
+const (
+	TierFree = "free"
+	TierPlus = "plus"
+	TierPro  = "pro"
+)
+
+// planTiers are the entitlements of each tier, lowest first.
+var planTiers = []dto.Entitlements{
+	{Tier: TierFree, DailyScans: auraDailyFreeLimit, GroupScans: false, BatchScans: false, Forecast: false, MatchesPerDay: 3, MatchNarrative: false},
+	{Tier: TierPlus, DailyScans: 10, GroupScans: true, BatchScans: true, Forecast: true, MatchesPerDay: 20, MatchNarrative: false},
+	{Tier: TierPro, DailyScans: -1, GroupScans: true, BatchScans: true, Forecast: true, MatchesPerDay: -1, MatchNarrative: true},
+}
+
+func tierRank(tier string) int {
+	for i, t := range planTiers {
+		if t.Tier == tier {
+			return i
+		}
+	}
+	return 0
+}
+
+// productTier maps a subscribed product to its tier; products not listed in
+// PLUS_PRODUCT_IDS are pro.
+func productTier(cfg *config.Config, productID string) string {
+	if cfg != nil && containsString(splitList(cfg.PlusProductIDs), productID) {
+		return TierPlus
+	}
+	return TierPro
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// entitlementsFor returns the entitlements of the highest tier among the
+// user's active subscriptions from any billing source.
+func entitlementsFor(db *gorm.DB, cfg *config.Config, userID uuid.UUID) dto.Entitlements {
+	var products []string
+	db.Model(&models.Subscription{}).
+		Where("user_id = ? AND status = ? AND current_period_end > ?", userID, "active", time.Now()).
+		Pluck("product_id", &products)
+
+	best := 0
+	for _, product := range products {
+		if rank := tierRank(productTier(cfg, product)); rank > best {
+			best = rank
+		}
+	}
+	return planTiers[best]
+}
+
+// EntitlementService answers what the user's plan includes.
+type EntitlementService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewEntitlementService(db *gorm.DB, cfg *config.Config) *EntitlementService {
+	return &EntitlementService{db: db, cfg: cfg}
+}
+
+// For returns the user's current entitlements.
+func (s *EntitlementService) For(ctx context.Context, userID uuid.UUID) dto.Entitlements {
+	return entitlementsFor(s.db.WithContext(ctx), s.cfg, userID)
+}
+
+// Response returns the user's entitlements alongside every tier.
+func (s *EntitlementService) Response(ctx context.Context, userID uuid.UUID) dto.EntitlementsResponse {
+	tiers := make([]dto.Entitlements, len(planTiers))
+	copy(tiers, planTiers)
+	return dto.EntitlementsResponse{Entitlements: s.For(ctx, userID), Tiers: tiers}
+}
+
+// checkMatchQuota returns ErrMatchLimitReached when the user has already
+// created their plan's number of matches today, in their own timezone.
+func checkMatchQuota(db *gorm.DB, cfg *config.Config, userID uuid.UUID) error {
+	ent := entitlementsFor(db, cfg, userID)
+	if ent.MatchesPerDay < 0 {
+		return nil
+	}
+	start, end := localDayBounds(time.Now(), profileLocation(db, userID))
+	start = quotaStart(db, userID, start)
+	var count int64
+	if err := db.Model(&models.AuraMatch{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, start, end).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count >= int64(ent.MatchesPerDay) {
+		return ErrMatchLimitReached
+	}
+	return nil
+}
+
+// quotaStart moves the start of the user's quota day forward to an admin
+// quota reset made during that day.
+func quotaStart(db *gorm.DB, userID uuid.UUID, dayStart time.Time) time.Time {
+	var user models.User
+	if err := db.Select("id", "quota_reset_at").First(&user, "id = ?", userID).Error; err != nil {
+		return dayStart
+	}
+	if user.QuotaResetAt != nil && user.QuotaResetAt.After(dayStart) {
+		return *user.QuotaResetAt
+	}
+	return dayStart
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+)
+
+func TestProductTier(t *testing.T) {
+	cfg := &config.Config{PlusProductIDs: "aurasnap_plus_monthly, price_plus"}
+
+	cases := map[string]string{
+		"aurasnap_plus_monthly": TierPlus,
+		"price_plus":            TierPlus,
+		"aurasnap_premium":      TierPro,
+		"":                      TierPro,
+	}
+	for product, want := range cases {
+		if got := productTier(cfg, product); got != want {
+			t.Errorf("productTier(%q) = %q, want %q", product, got, want)
+		}
+	}
+	if got := productTier(nil, "aurasnap_plus_monthly"); got != TierPro {
+		t.Errorf("productTier without config = %q, want pro", got)
+	}
+}
+
+func TestPlanTiersAscend(t *testing.T) {
+	if tierRank(TierFree) >= tierRank(TierPlus) || tierRank(TierPlus) >= tierRank(TierPro) {
+		t.Fatalf("tiers out of order: free=%d plus=%d pro=%d", tierRank(TierFree), tierRank(TierPlus), tierRank(TierPro))
+	}
+	free, plus := planTiers[tierRank(TierFree)], planTiers[tierRank(TierPlus)]
+	if free.DailyScans != auraDailyFreeLimit || free.GroupScans || free.BatchScans || free.Forecast {
+		t.Errorf("free tier = %+v", free)
+	}
+	if plus.DailyScans <= free.DailyScans || plus.MatchesPerDay <= free.MatchesPerDay || plus.MatchNarrative || !plus.BatchScans {
+		t.Errorf("plus tier = %+v", plus)
+	}
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidExperiment        = errors.New("invalid experiment")
+	ErrExperimentNotFound       = errors.New("experiment not found")
+	ErrExperimentKeyTaken       = errors.New("experiment key already used")
+	ErrExperimentNotDraft       = errors.New("experiment already started")
+	ErrExperimentNotRunning     = errors.New("experiment is not running")
+	ErrExperimentAlreadyRunning = errors.New("another experiment is running")
+)
+
+// experimentCacheTTL is how long the running experiment is reused before it
+// is read again, so starting or stopping one reaches every instance.
+const experimentCacheTTL = 30 * time.Second
+
+var experimentSlug = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// experimentAssignment is the variant a user is in.
+type experimentAssignment struct {
+	experimentID uuid.UUID
+	variant      models.ExperimentVariant
+}
+
+// readingVariant is what a reading is tagged with so variants can be
+// compared: the prompt variant, and the experiment arm if any.
+type readingVariant struct {
+	prompt       string
+	experimentID *uuid.UUID
+	experiment   string
+}
+
+func (v readingVariant) tag(r *models.AuraReading) {
+	r.PromptVariant = v.prompt
+	r.ExperimentID = v.experimentID
+	r.ExperimentVariant = v.experiment
+}
+
+// experimentBucket picks the user's variant by weight. The hash includes
+// the key so each experiment splits users independently.
+func experimentBucket(key string, userID uuid.UUID, variants []models.ExperimentVariant) models.ExperimentVariant {
+	total := 0
+	for _, v := range variants {
+		total += v.Weight
+	}
+	sum := sha256.Sum256([]byte("experiment:" + key + ":" + userID.String()))
+	n := int(binary.BigEndian.Uint64(sum[:8]) % uint64(total))
+	for _, v := range variants {
+		if n < v.Weight {
+			return v
+		}
+		n -= v.Weight
+	}
+	return variants[len(variants)-1]
+}
+
+// ExperimentService runs A/B experiments on aura scans. A nil
+// *ExperimentService runs none.
+type ExperimentService struct {
+	db *gorm.DB
+
+	mu       sync.Mutex
+	running  *models.Experiment
+	loadedAt time.Time
+}
+
+func NewExperimentService(db *gorm.DB) *ExperimentService {
+	return &ExperimentService{db: db}
+}
+
+// assign returns the user's variant in the running experiment, or nil when
+// none is running. When the experiment cannot be read the last one read is
+// kept.
+func (s *ExperimentService) assign(ctx context.Context, userID uuid.UUID) *experimentAssignment {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loadedAt.IsZero() || time.Since(s.loadedAt) >= experimentCacheTTL {
+		s.loadedAt = time.Now()
+		var running []models.Experiment
+		if err := s.db.WithContext(ctx).Where("status = ?", models.ExperimentRunning).Limit(1).Find(&running).Error; err != nil {
+			log.Printf("experiments: load running experiment: %v", err)
+		} else if len(running) == 0 {
+			s.running = nil
+		} else {
+			s.running = &running[0]
+		}
+	}
+	if s.running == nil || len(s.running.Variants) == 0 {
+		return nil
+	}
+	return &experimentAssignment{
+		experimentID: s.running.ID,
+		variant:      experimentBucket(s.running.Key, userID, s.running.Variants),
+	}
+}
+
+// invalidate makes the next assign read the running experiment again.
+func (s *ExperimentService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// validateExperiment checks a definition and normalizes its variants.
+func validateExperiment(e *models.Experiment) error {
+	if !experimentSlug.MatchString(e.Key) {
+		return fmt.Errorf("%w: key must be lowercase letters, digits, - and _", ErrInvalidExperiment)
+	}
+	if len(e.Variants) < 2 {
+		return fmt.Errorf("%w: an experiment needs at least two variants", ErrInvalidExperiment)
+	}
+	seen := make(map[string]bool, len(e.Variants))
+	for i := range e.Variants {
+		v := &e.Variants[i]
+		v.Name = strings.TrimSpace(v.Name)
+		v.Provider = strings.ToLower(strings.TrimSpace(v.Provider))
+		v.Model = strings.TrimSpace(v.Model)
+		switch {
+		case !experimentSlug.MatchString(v.Name) || len(v.Name) > 50:
+			return fmt.Errorf("%w: variant names must be lowercase letters, digits, - and _, up to 50", ErrInvalidExperiment)
+		case seen[v.Name]:
+			return fmt.Errorf("%w: variant %q appears twice", ErrInvalidExperiment, v.Name)
+		case v.Weight < 1 || v.Weight > 1000:
+			return fmt.Errorf("%w: variant %q weight must be 1-1000", ErrInvalidExperiment, v.Name)
+		case v.PromptVariant != "" && v.PromptVariant != promptVariantBaseline && v.PromptVariant != promptVariantHistory:
+			return fmt.Errorf("%w: variant %q prompt_variant must be %s or %s", ErrInvalidExperiment, v.Name, promptVariantBaseline, promptVariantHistory)
+		case v.Temperature != nil && (*v.Temperature < 0 || *v.Temperature > 2):
+			return fmt.Errorf("%w: variant %q temperature must be 0-2", ErrInvalidExperiment, v.Name)
+		case v.Provider != "" && !slices.Contains(promptProviders, v.Provider):
+			return fmt.Errorf("%w: variant %q provider must be one of %s", ErrInvalidExperiment, v.Name, strings.Join(promptProviders, ", "))
+		case v.Model != "" && v.Provider == "":
+			return fmt.Errorf("%w: variant %q needs a provider for its model", ErrInvalidExperiment, v.Name)
+		case len(v.Model) > 100:
+			return fmt.Errorf("%w: variant %q model is too long", ErrInvalidExperiment, v.Name)
+		}
+		seen[v.Name] = true
+	}
+	return nil
+}
+
+// checkExperiment validates e, then checks that its key is free and that
+// pinned prompt templates are aura_scan templates.
+func (s *ExperimentService) checkExperiment(e *models.Experiment) error {
+	if err := validateExperiment(e); err != nil {
+		return err
+	}
+	var taken int64
+	s.db.Model(&models.Experiment{}).Where("key = ? AND id <> ?", e.Key, e.ID).Count(&taken)
+	if taken > 0 {
+		return ErrExperimentKeyTaken
+	}
+	for _, v := range e.Variants {
+		if v.PromptTemplateID == nil {
+			continue
+		}
+		var count int64
+		if err := s.db.Model(&models.PromptTemplate{}).
+			Where("id = ? AND name = ?", *v.PromptTemplateID, models.PromptAuraScan).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("%w: variant %q prompt_template_id is not an aura_scan template", ErrInvalidExperiment, v.Name)
+		}
+	}
+	return nil
+}
+
+func applyExperimentRequest(e *models.Experiment, req *dto.ExperimentRequest) {
+	e.Key = strings.ToLower(strings.TrimSpace(req.Key))
+	e.Description = strings.TrimSpace(req.Description)
+	e.Variants = req.Variants
+}
+
+// List returns every experiment, newest first.
+func (s *ExperimentService) List() ([]models.Experiment, error) {
+	experiments := []models.Experiment{}
+	err := s.db.Order("created_at DESC").Find(&experiments).Error
+	return experiments, err
+}
+
+// Get returns one experiment.
+func (s *ExperimentService) Get(id uuid.UUID) (*models.Experiment, error) {
+	var e models.Experiment
+	if err := s.db.First(&e, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExperimentNotFound
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Create saves a draft experiment.
+func (s *ExperimentService) Create(adminID uuid.UUID, req *dto.ExperimentRequest) (*models.Experiment, error) {
+	e := &models.Experiment{Status: models.ExperimentDraft, CreatedBy: &adminID}
+	applyExperimentRequest(e, req)
+	if err := s.checkExperiment(e); err != nil {
+		return nil, err
+	}
+	if err := s.db.Create(e).Error; err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Update replaces a draft's definition. Once started an experiment is
+// fixed, since changing weights would move users between variants.
+func (s *ExperimentService) Update(id uuid.UUID, req *dto.ExperimentRequest) (*models.Experiment, error) {
+	e, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != models.ExperimentDraft {
+		return nil, ErrExperimentNotDraft
+	}
+	applyExperimentRequest(e, req)
+	if err := s.checkExperiment(e); err != nil {
+		return nil, err
+	}
+	if err := s.db.Save(e).Error; err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Start begins assigning users to a draft experiment's variants.
+func (s *ExperimentService) Start(id uuid.UUID) (*models.Experiment, error) {
+	var e models.Experiment
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&e, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrExperimentNotFound
+			}
+			return err
+		}
+		if e.Status != models.ExperimentDraft {
+			return ErrExperimentNotDraft
+		}
+		var running int64
+		if err := tx.Model(&models.Experiment{}).Where("status = ?", models.ExperimentRunning).Count(&running).Error; err != nil {
+			return err
+		}
+		if running > 0 {
+			return ErrExperimentAlreadyRunning
+		}
+		now := time.Now()
+		e.Status, e.StartedAt = models.ExperimentRunning, &now
+		return tx.Model(&e).Updates(map[string]any{"status": e.Status, "started_at": now}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return &e, nil
+}
+
+// Stop ends a running experiment. Its readings keep their tags, so results
+// stay available.
+func (s *ExperimentService) Stop(id uuid.UUID) (*models.Experiment, error) {
+	e, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != models.ExperimentRunning {
+		return nil, ErrExperimentNotRunning
+	}
+	now := time.Now()
+	e.Status, e.EndedAt = models.ExperimentStopped, &now
+	if err := s.db.Model(e).Updates(map[string]any{"status": e.Status, "ended_at": now}).Error; err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return e, nil
+}
+
+// Delete removes a draft; experiments that ran are kept with their results.
+func (s *ExperimentService) Delete(id uuid.UUID) error {
+	e, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if e.Status != models.ExperimentDraft {
+		return ErrExperimentNotDraft
+	}
+	return s.db.Delete(e).Error
+}
+
+// Results compares engagement and conversion between the variants of an
+// experiment: readings per user, users who came back on another day, share
+// rate, self-ratings, and subscriptions started after the first reading.
+func (s *ExperimentService) Results(id uuid.UUID) (*dto.ExperimentResults, error) {
+	e, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var readings []struct {
+		ExperimentVariant string
+		Readings          int64
+		Shared            int64
+		Rated             int64
+		AverageRating     *float64
+	}
+	if err := s.db.Model(&models.AuraReading{}).Scopes(personalReadings).
+		Select("experiment_variant, COUNT(*) AS readings, COUNT(*) FILTER (WHERE share_count > 0) AS shared, COUNT(rating) AS rated, AVG(rating) AS average_rating").
+		Where("experiment_id = ? AND NOT synthetic", id).
+		Group("experiment_variant").
+		Scan(&readings).Error; err != nil {
+		return nil, err
+	}
+
+	var users []struct {
+		ExperimentVariant string
+		Users             int64
+		Returning         int64
+		Converted         int64
+	}
+	if err := s.db.Raw(`SELECT u.experiment_variant, COUNT(*) AS users,
+			COUNT(*) FILTER (WHERE u.days > 1) AS returning,
+			COUNT(*) FILTER (WHERE EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = u.user_id AND s.created_at >= u.first_reading)) AS converted
+		FROM (SELECT experiment_variant, user_id, MIN(created_at) AS first_reading, COUNT(DISTINCT created_at::date) AS days
+			FROM aura_readings
+			WHERE experiment_id = ? AND NOT synthetic AND group_reading_id IS NULL AND deleted_at IS NULL
+			GROUP BY experiment_variant, user_id) u
+		GROUP BY u.experiment_variant`, id).
+		Scan(&users).Error; err != nil {
+		return nil, err
+	}
+
+	results := &dto.ExperimentResults{Experiment: *e, Variants: make([]dto.ExperimentVariantStats, 0, len(e.Variants))}
+	for _, v := range e.Variants {
+		st := dto.ExperimentVariantStats{Variant: v.Name, Weight: v.Weight}
+		for _, r := range readings {
+			if r.ExperimentVariant == v.Name {
+				st.Readings, st.SharedReadings, st.Ratings, st.AverageRating = r.Readings, r.Shared, r.Rated, r.AverageRating
+			}
+		}
+		for _, u := range users {
+			if u.ExperimentVariant == v.Name {
+				st.Users, st.ReturningUsers, st.Conversions = u.Users, u.Returning, u.Converted
+			}
+		}
+		if st.Readings > 0 {
+			st.ShareRate = float64(st.SharedReadings) / float64(st.Readings)
+		}
+		if st.Users > 0 {
+			st.ReadingsPerUser = float64(st.Readings) / float64(st.Users)
+			st.ReturnRate = float64(st.ReturningUsers) / float64(st.Users)
+			st.ConversionRate = float64(st.Conversions) / float64(st.Users)
+		}
+		results.Variants = append(results.Variants, st)
+	}
+	return results, nil
+}
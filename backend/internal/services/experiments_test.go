@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestExperimentBucketIsStableAndWeighted(t *testing.T) {
+	variants := []models.ExperimentVariant{{Name: "control", Weight: 3}, {Name: "warm", Weight: 1}}
+
+	counts := map[string]int{}
+	const users = 20000
+	for i := 0; i < users; i++ {
+		id := uuid.New()
+		v := experimentBucket("temp-test", id, variants)
+		if again := experimentBucket("temp-test", id, variants); again.Name != v.Name {
+			t.Fatalf("user %s moved from %s to %s", id, v.Name, again.Name)
+		}
+		counts[v.Name]++
+	}
+	if share := float64(counts["warm"]) / users; math.Abs(share-0.25) > 0.02 {
+		t.Errorf("warm got %.3f of users, want about 0.25", share)
+	}
+
+	// Each experiment splits users independently of the others.
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		id := uuid.New()
+		if experimentBucket("a", id, variants).Name != experimentBucket("b", id, variants).Name {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Error("two experiments put every user in the same variant")
+	}
+}
+
+func TestValidateExperiment(t *testing.T) {
+	temp := 0.9
+	valid := func() models.Experiment {
+		return models.Experiment{Key: "warmer-readings", Variants: []models.ExperimentVariant{
+			{Name: "control", Weight: 1},
+			{Name: "warm", Weight: 1, Temperature: &temp, PromptVariant: promptVariantHistory, Provider: " OpenAI ", Model: "gpt-4o"},
+		}}
+	}
+	e := valid()
+	if err := validateExperiment(&e); err != nil {
+		t.Fatalf("valid experiment refused: %v", err)
+	}
+	if e.Variants[1].Provider != "openai" {
+		t.Errorf("provider = %q, want it normalized", e.Variants[1].Provider)
+	}
+
+	hot := 2.5
+	cases := map[string]func(*models.Experiment){
+		"bad key":           func(e *models.Experiment) { e.Key = "Warmer Readings" },
+		"one variant":       func(e *models.Experiment) { e.Variants = e.Variants[:1] },
+		"duplicate name":    func(e *models.Experiment) { e.Variants[1].Name = "control" },
+		"zero weight":       func(e *models.Experiment) { e.Variants[0].Weight = 0 },
+		"unknown prompt":    func(e *models.Experiment) { e.Variants[0].PromptVariant = "verbose" },
+		"temperature":       func(e *models.Experiment) { e.Variants[0].Temperature = &hot },
+		"unknown provider":  func(e *models.Experiment) { e.Variants[0].Provider = "llama" },
+		"model no provider": func(e *models.Experiment) { e.Variants[0].Model = "gpt-4o" },
+	}
+	for name, mutate := range cases {
+		e := valid()
+		mutate(&e)
+		if err := validateExperiment(&e); !errors.Is(err, ErrInvalidExperiment) {
+			t.Errorf("%s: err = %v, want ErrInvalidExperiment", name, err)
+		}
+	}
+}
+
+func TestProvidersForExperimentVariant(t *testing.T) {
+	a := &auraAIAnalyzer{providers: []auraAIProvider{
+		{name: "glm", model: "glm-4"},
+		{name: "deepseek", model: "deepseek-chat"},
+		{name: "openai", model: "gpt-4o-mini"},
+	}}
+
+	got := a.providersFor(auraAnalysisOptions{Provider: "openai", Model: "gpt-4o"})
+	if len(got) != 3 || got[0].name != "openai" || got[0].model != "gpt-4o" || got[1].name != "glm" || got[2].name != "deepseek" {
+		t.Errorf("providers = %+v", got)
+	}
+	if a.providers[2].model != "gpt-4o-mini" {
+		t.Error("the configured model was changed")
+	}
+	if got := a.providersFor(auraAnalysisOptions{}); got[0].name != "glm" {
+		t.Errorf("without a variant provider the order should be unchanged, got %+v", got)
+	}
+}
+
+func TestNilExperimentServiceAssignsNothing(t *testing.T) {
+	var s *ExperimentService
+	if a := s.assign(context.Background(), uuid.New()); a != nil {
+		t.Errorf("assign = %+v, want nil", a)
+	}
+}
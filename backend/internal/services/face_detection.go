@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+var ErrNoFaceDetected = errors.New("we couldn't find a face in this photo; take a selfie with your face clearly visible")
+
+const openAIChatCompletionsURL = "https://api.openai.com/v1/chat/completions"
+
+const faceCountPrompt = `Count the human faces clearly visible in this photo. Animals, drawings, statues and faces on screens do not count. Return only JSON: {"faces": <integer>}`
+
+// faceDetector counts the human faces in a photo. image is a URL or a data:
+// URI.
+type faceDetector interface {
+	name() string
+	modelName() string
+	countFaces(ctx context.Context, image string) (int, error)
+}
+
+// newFaceDetector returns the configured detector, or nil when face
+// detection is off, its provider is not configured, or mock mode is on.
+func newFaceDetector(cfg *config.Config) faceDetector {
+	if cfg.ForceMockMode {
+		return nil
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.FaceDetection)) {
+	case "openai":
+		if strings.TrimSpace(cfg.OpenAIAPIKey) == "" {
+			return nil
+		}
+		return &openAIFaceDetector{
+			apiURL: openAIChatCompletionsURL,
+			apiKey: strings.TrimSpace(cfg.OpenAIAPIKey),
+			model:  strings.TrimSpace(cfg.OpenAIModel),
+			client: &http.Client{Timeout: 10 * time.Second},
+		}
+	}
+	return nil
+}
+
+// openAIFaceDetector asks a vision model for a face count, at low image
+// detail to keep the call cheap.
+type openAIFaceDetector struct {
+	apiURL string
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+func (d *openAIFaceDetector) name() string { return "openai" }
+
+func (d *openAIFaceDetector) modelName() string { return d.model }
+
+func (d *openAIFaceDetector) countFaces(ctx context.Context, image string) (int, error) {
+	payload, err := json.Marshal(map[string]any{
+		"model":       d.model,
+		"temperature": 0,
+		"messages": []map[string]any{{
+			"role": "user",
+			"content": []map[string]any{
+				{"type": "text", "text": faceCountPrompt},
+				{"type": "image_url", "image_url": map[string]string{"url": image, "detail": "low"}},
+			},
+		}},
+		"response_format": map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.apiURL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+d.apiKey)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("face detection request failed: status=%d", resp.StatusCode)
+	}
+
+	var completion auraChatCompletionResponse
+	if err := json.Unmarshal(body, &completion); err != nil {
+		return 0, err
+	}
+	countAITokens(ctx, completion.Usage)
+	if len(completion.Choices) == 0 {
+		return 0, errors.New("face detection returned no choices")
+	}
+	return parseFaceCount(completion.Choices[0].Message.Content)
+}
+
+// parseFaceCount reads {"faces": n} from a model reply.
+func parseFaceCount(content string) (int, error) {
+	raw, err := extractJSONObject(content)
+	if err != nil {
+		return 0, err
+	}
+	var out struct {
+		Faces *int `json:"faces"`
+	}
+	if err := json.Unmarshal([]byte(raw), &out); err != nil {
+		return 0, err
+	}
+	if out.Faces == nil || *out.Faces < 0 {
+		return 0, errors.New("face detection reply has no face count")
+	}
+	return *out.Faces, nil
+}
+
+// requireFace rejects a scan photo with no human face in it, before it
+// reaches the aura AI. When the detector fails, or the daily AI budget is
+// spent, the scan goes ahead.
+func (s *AuraService) requireFace(ctx context.Context, userID uuid.UUID, imageURL, imageData string) error {
+	image := screeningImage(imageURL, imageData)
+	if s.faces == nil || image == "" || s.usage.OverBudget(ctx) {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "aura.face_detection")
+	defer span.End()
+
+	start := time.Now()
+	callCtx, tokens := withAITokenCounter(ctx)
+	faces, err := s.faces.countFaces(callCtx, image)
+	recordAIInteraction(ctx, models.AIPurposeFaceDetection, s.faces.name(), s.faces.modelName(), err, start)
+	s.usage.Record(ctx, models.AIPurposeFaceDetection, s.faces.name(), s.faces.modelName(), *tokens, err, start)
+	if err != nil {
+		span.RecordError(err)
+		metrics.FaceDetectionTotal.WithLabelValues("error").Inc()
+		log.Printf("face detection (%s) for user %s: %v", s.faces.name(), userID, err)
+		return nil
+	}
+	if faces == 0 {
+		metrics.FaceDetectionTotal.WithLabelValues("no_face").Inc()
+		return ErrNoFaceDetected
+	}
+	metrics.FaceDetectionTotal.WithLabelValues("face").Inc()
+	return nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseFaceCount(t *testing.T) {
+	cases := []struct {
+		content string
+		want    int
+		wantErr bool
+	}{
+		{`{"faces": 1}`, 1, false},
+		{"```json\n{\"faces\": 0}\n```", 0, false},
+		{`{"count": 2}`, 0, true},
+		{`{"faces": -1}`, 0, true},
+		{`no json here`, 0, true},
+	}
+	for _, tc := range cases {
+		got, err := parseFaceCount(tc.content)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("parseFaceCount(%q) = %d, %v", tc.content, got, err)
+		}
+	}
+}
+
+func TestOpenAIFaceDetector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Content []map[string]any `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.Messages) != 1 || len(req.Messages[0].Content) != 2 || req.Messages[0].Content[1]["type"] != "image_url" {
+			t.Errorf("request messages = %+v", req.Messages)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"faces\": 0}"}}]}`))
+	}))
+	defer server.Close()
+
+	d := &openAIFaceDetector{apiURL: server.URL, apiKey: "k", model: "gpt-4o-mini", client: server.Client()}
+	faces, err := d.countFaces(context.Background(), "https://cdn.example.com/wall.jpg")
+	if err != nil || faces != 0 {
+		t.Fatalf("countFaces = %d, %v", faces, err)
+	}
+}
+
+type stubFaceDetector struct {
+	faces int
+	err   error
+}
+
+func (d stubFaceDetector) name() string { return "stub" }
+
+func (d stubFaceDetector) modelName() string { return "stub-model" }
+
+func (d stubFaceDetector) countFaces(context.Context, string) (int, error) {
+	return d.faces, d.err
+}
+
+func TestRequireFace(t *testing.T) {
+	url := "https://cdn.example.com/a.jpg"
+	cases := []struct {
+		detector faceDetector
+		want     error
+	}{
+		{nil, nil},
+		{stubFaceDetector{faces: 1}, nil},
+		{stubFaceDetector{faces: 0}, ErrNoFaceDetected},
+		{stubFaceDetector{err: errors.New("timeout")}, nil},
+	}
+	for i, tc := range cases {
+		s := &AuraService{faces: tc.detector}
+		if err := s.requireFace(context.Background(), uuid.New(), url, ""); !errors.Is(err, tc.want) {
+			t.Errorf("case %d: err = %v, want %v", i, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/fieldcrypt"
+	"gorm.io/gorm"
+)
+
+// reencryptBatch bounds the rows rewritten per column and pass.
+const reencryptBatch = 500
+
+// encryptedColumn is a text column stored with the encrypted serializer.
+type encryptedColumn struct {
+	table  string
+	column string
+}
+
+// encryptedColumns lists every column using serializer:encrypted, so key
+// rotation can find the rows still on a retired key.
+var encryptedColumns = []encryptedColumn{
+	{table: "user_memories", column: "content"},
+	{table: "aura_readings", column: "note"},
+}
+
+// FieldEncryptionService moves encrypted columns to the current key.
+type FieldEncryptionService struct {
+	db *gorm.DB
+}
+
+func NewFieldEncryptionService(db *gorm.DB) *FieldEncryptionService {
+	return &FieldEncryptionService{db: db}
+}
+
+// RunReencryptWorker re-encrypts rows written in plain text or with a
+// retired key, a batch per column on each tick.
+func (s *FieldEncryptionService) RunReencryptWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.Reencrypt(ctx)
+			if err != nil {
+				log.Printf("field re-encryption worker: %v", err)
+			}
+			if n > 0 {
+				log.Printf("field re-encryption worker: re-encrypted %d values", n)
+			}
+		}
+	}
+}
+
+// Reencrypt rewrites up to reencryptBatch values per encrypted column that
+// are not yet on the current key. It does nothing while encryption is off.
+func (s *FieldEncryptionService) Reencrypt(ctx context.Context) (int, error) {
+	keyring := fieldcrypt.Active()
+	if keyring == nil {
+		return 0, nil
+	}
+	db := s.db.WithContext(ctx)
+
+	done := 0
+	for _, col := range encryptedColumns {
+		var rows []struct {
+			ID    string
+			Value string
+		}
+		if err := db.Table(col.table).
+			Select("id, "+col.column+" AS value").
+			Where(col.column+" <> '' AND "+col.column+" NOT LIKE ?", keyring.CurrentPrefix()+"%").
+			Limit(reencryptBatch).
+			Scan(&rows).Error; err != nil {
+			return done, err
+		}
+
+		for _, row := range rows {
+			plain, err := keyring.Decrypt(row.Value)
+			if err != nil {
+				log.Printf("field re-encryption: %s.%s %s: %v", col.table, col.column, row.ID, err)
+				continue
+			}
+			sealed, err := keyring.Encrypt(plain)
+			if err != nil {
+				return done, err
+			}
+			// Only the value read above is replaced, so a concurrent write wins.
+			if err := db.Table(col.table).
+				Where("id = ? AND "+col.column+" = ?", row.ID, row.Value).
+				UpdateColumn(col.column, sealed).Error; err != nil {
+				return done, err
+			}
+			done++
+		}
+	}
+	return done, nil
+}
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// forecastLookback is how far back readings shape a forecast; users
+	// without a reading in it are not considered active.
+	forecastLookback = 14 * 24 * time.Hour
+	// forecastBatchSize caps generations per worker tick so a large backlog
+	// cannot hold the AI provider for long.
+	forecastBatchSize = 200
+)
+
+const (
+	outlookRising  = "rising"
+	outlookSteady  = "steady"
+	outlookDipping = "dipping"
+)
+
+const forecastSystemPrompt = `You are the voice of AuraSnap, a playful aura personality app. You write a short, horoscope-style daily forecast for one person from a summary of their recent aura readings.
+
+Respond with a JSON object only:
+{"headline": "max 8 words", "forecast": "2-3 sentences, 40-70 words, second person", "focus": "one or two words naming today's focus", "lucky_color": "one of: red, orange, yellow, green, blue, indigo, violet, white, gold, pink"}
+
+Keep it warm, specific to the colors and energy trend given, and playful. Never make medical, financial or relationship-ending claims.`
+
+// forecastThemes opens the template forecast for each dominant color.
+var forecastThemes = map[string]string{
+	"red":    "Bold momentum surrounds you today",
+	"orange": "A spark of creativity is looking for an outlet",
+	"yellow": "Your mind is bright and quick today",
+	"green":  "Steady growth is the theme of your day",
+	"blue":   "Calm clarity carries you through today",
+	"indigo": "Your intuition is especially sharp today",
+	"violet": "Imagination colors everything you touch today",
+	"white":  "A clean, balanced energy settles over your day",
+	"gold":   "Confidence and abundance are on your side",
+	"pink":   "Warmth and kindness flow easily today",
+}
+
+var outlookSentences = map[string]string{
+	outlookRising:  "Your energy has been climbing lately, so say yes to the thing you've been putting off.",
+	outlookSteady:  "Your energy has been consistent, which makes this a good day to build on routines.",
+	outlookDipping: "Your energy has dipped recently, so leave room to rest and recharge.",
+}
+
+// forecastSignals summarizes recent readings for the forecast writers.
+type forecastSignals struct {
+	DominantColor string
+	AvgEnergy     int
+	AvgMood       int
+	Outlook       string
+	Readings      int
+}
+
+type forecastAIResult struct {
+	Headline   string `json:"headline"`
+	Forecast   string `json:"forecast"`
+	Focus      string `json:"focus"`
+	LuckyColor string `json:"lucky_color"`
+}
+
+type ForecastService struct {
+	db        *gorm.DB
+	cfg       *config.Config
+	analytics *Analytics
+	usage     *AIUsageService
+}
+
+func NewForecastService(db *gorm.DB, cfg *config.Config, analytics *Analytics) *ForecastService {
+	return &ForecastService{db: db, cfg: cfg, analytics: analytics}
+}
+
+// UseAIUsage records the tokens and cost of forecast AI calls and holds
+// them to the daily AI budget.
+func (s *ForecastService) UseAIUsage(usage *AIUsageService) {
+	s.usage = usage
+}
+
+// Today returns the user's forecast for their current local day, generating
+// it on demand when the worker has not reached them yet.
+func (s *ForecastService) Today(ctx context.Context, userID uuid.UUID, tz string) (*models.AuraForecast, error) {
+	ctx, span := tracer.Start(ctx, "ForecastService.Today")
+	defer span.End()
+
+	if !entitlementsFor(s.db.WithContext(ctx), s.cfg, userID).Forecast {
+		s.analytics.Emit(userID, EventPaywallHit, map[string]any{"feature": "forecast"})
+		return nil, ErrUpgradeRequired
+	}
+
+	loc := userLocation(s.db, userID, tz)
+	now := time.Now()
+	day := now.In(loc).Format("2006-01-02")
+
+	var forecast models.AuraForecast
+	if err := s.db.WithContext(ctx).Where("user_id = ? AND day = ?", userID, day).First(&forecast).Error; err == nil {
+		return &forecast, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return s.generate(ctx, userID, day, now)
+}
+
+// ForecastJob is the scheduled job that generates missing forecasts for
+// active users. Users get theirs shortly after local midnight.
+func (s *ForecastService) ForecastJob(ctx context.Context) error {
+	n, err := s.GenerateDue(ctx, time.Now())
+	if n > 0 {
+		log.Printf("forecast job: generated %d forecasts", n)
+	}
+	return err
+}
+
+// GenerateDue creates today's forecast for every active user whose plan
+// includes forecasts and who does not have one yet, up to forecastBatchSize per call.
+func (s *ForecastService) GenerateDue(ctx context.Context, now time.Time) (int, error) {
+	var users []models.User
+	if err := s.db.WithContext(ctx).Select("id", "timezone").
+		Where("id IN (SELECT user_id FROM aura_readings WHERE deleted_at IS NULL AND NOT synthetic AND created_at >= ?)", now.Add(-forecastLookback)).
+		Find(&users).Error; err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	for _, user := range users {
+		if generated >= forecastBatchSize || ctx.Err() != nil {
+			break
+		}
+		loc, err := loadTimezone(user.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		day := now.In(loc).Format("2006-01-02")
+
+		var count int64
+		s.db.Model(&models.AuraForecast{}).Where("user_id = ? AND day = ?", user.ID, day).Count(&count)
+		if count > 0 || !entitlementsFor(s.db, s.cfg, user.ID).Forecast {
+			continue
+		}
+		if _, err := s.generate(ctx, user.ID, day, now); err != nil {
+			log.Printf("forecast for %s: %v", user.ID, err)
+			continue
+		}
+		generated++
+	}
+	return generated, nil
+}
+
+func (s *ForecastService) generate(ctx context.Context, userID uuid.UUID, day string, now time.Time) (*models.AuraForecast, error) {
+	var readings []models.AuraReading
+	if err := s.db.WithContext(ctx).Scopes(personalReadings).Where("user_id = ? AND created_at >= ?", userID, now.Add(-forecastLookback)).
+		Order("created_at DESC").Limit(30).Find(&readings).Error; err != nil {
+		return nil, err
+	}
+	if len(readings) == 0 {
+		// Fall back to the latest reading ever so lapsed users still get one.
+		var latest models.AuraReading
+		if err := s.db.WithContext(ctx).Scopes(personalReadings).Where("user_id = ?", userID).Order("created_at DESC").First(&latest).Error; err != nil {
+			return nil, ErrNoAuraReading
+		}
+		readings = []models.AuraReading{latest}
+	}
+
+	signals := computeForecastSignals(readings)
+	forecast := templateForecast(userID, day, signals)
+	if s.cfg.OpenAIAPIKey != "" {
+		if ai, err := s.aiForecast(ctx, signals, readings[0]); err != nil {
+			log.Printf("forecast AI failed, using template: %v", err)
+		} else {
+			forecast.Headline = ai.Headline
+			forecast.Forecast = ai.Forecast
+			if ai.Focus != "" {
+				forecast.Focus = ai.Focus
+			}
+			if containsString(auraColors, ai.LuckyColor) {
+				forecast.LuckyColor = ai.LuckyColor
+			}
+			forecast.Source = models.ForecastSourceAI
+		}
+	}
+
+	// The worker and an on-demand request can race; the first insert wins.
+	if err := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(forecast).Error; err != nil {
+		return nil, err
+	}
+	var stored models.AuraForecast
+	if err := s.db.WithContext(ctx).Where("user_id = ? AND day = ?", userID, day).First(&stored).Error; err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// computeForecastSignals expects readings newest first. The dominant color
+// is the most frequent one, ties going to the most recent.
+func computeForecastSignals(readings []models.AuraReading) forecastSignals {
+	counts := map[string]int{}
+	energy, mood := 0, 0
+	for _, r := range readings {
+		counts[strings.ToLower(r.AuraColor)]++
+		energy += r.EnergyLevel
+		mood += r.MoodScore
+	}
+
+	dominant, best := "", 0
+	for _, r := range readings {
+		color := strings.ToLower(r.AuraColor)
+		if counts[color] > best {
+			dominant, best = color, counts[color]
+		}
+	}
+
+	return forecastSignals{
+		DominantColor: dominant,
+		AvgEnergy:     energy / len(readings),
+		AvgMood:       mood / len(readings),
+		Outlook:       energyOutlook(readings),
+		Readings:      len(readings),
+	}
+}
+
+// energyOutlook compares the newer half of the readings with the older half.
+func energyOutlook(readings []models.AuraReading) string {
+	if len(readings) < 2 {
+		return outlookSteady
+	}
+	half := len(readings) / 2
+	avg := func(rs []models.AuraReading) int {
+		sum := 0
+		for _, r := range rs {
+			sum += r.EnergyLevel
+		}
+		return sum / len(rs)
+	}
+	switch delta := avg(readings[:half]) - avg(readings[half:]); {
+	case delta >= 10:
+		return outlookRising
+	case delta <= -10:
+		return outlookDipping
+	}
+	return outlookSteady
+}
+
+// templateForecast writes a deterministic forecast: the same user and day
+// always get the same focus and lucky color.
+func templateForecast(userID uuid.UUID, day string, signals forecastSignals) *models.AuraForecast {
+	h := fnv.New32a()
+	h.Write([]byte(userID.String() + day))
+	seed := int(h.Sum32() & 0x7fffffff)
+
+	color := signals.DominantColor
+	theme, ok := forecastThemes[color]
+	if !ok {
+		theme = "Your aura is shifting in interesting ways today"
+	}
+	traits := colorTraits[color]
+
+	focus := "Balance"
+	if len(traits.strengths) > 0 {
+		focus = traits.strengths[seed%len(traits.strengths)]
+	}
+
+	lucky := color
+	var pairings []string
+	for _, c := range harmoniousColors(color) {
+		if c.Color != color {
+			pairings = append(pairings, c.Color)
+		}
+	}
+	if len(pairings) > 0 {
+		lucky = pairings[seed%len(pairings)]
+	}
+
+	body := theme + ". " + outlookSentences[signals.Outlook]
+	if traits.dailyAdvice != "" {
+		body += " " + traits.dailyAdvice
+	}
+
+	return &models.AuraForecast{
+		UserID:        userID,
+		Day:           day,
+		DominantColor: color,
+		EnergyOutlook: signals.Outlook,
+		Headline:      fmt.Sprintf("Your %s day: lead with %s", color, strings.ToLower(focus)),
+		Forecast:      body,
+		Focus:         focus,
+		LuckyColor:    lucky,
+		Source:        models.ForecastSourceTemplate,
+	}
+}
+
+func (s *ForecastService) aiForecast(ctx context.Context, signals forecastSignals, latest models.AuraReading) (*forecastAIResult, error) {
+	userPrompt := fmt.Sprintf(`Dominant aura color (last two weeks): %s
+Latest reading: %s aura, energy %d/100, mood %d/10
+Average energy: %d/100, average mood: %d/10 across %d readings
+Energy trend: %s
+Latest personality summary: %s`,
+		signals.DominantColor,
+		latest.AuraColor, latest.EnergyLevel, latest.MoodScore,
+		signals.AvgEnergy, signals.AvgMood, signals.Readings,
+		signals.Outlook,
+		wrapUntrusted("personality", latest.Personality, 500),
+	)
+
+	raw, err := openAIChatCompletion(ctx, s.cfg.OpenAIAPIKey, s.usage, models.AIPurposeForecast, "openai_forecast", openAIRequest{
+		Model: "gpt-4o-mini",
+		Messages: []openAIMessage{
+			{Role: "system", Content: forecastSystemPrompt + "\n\n" + untrustedInputInstruction},
+			{Role: "user", Content: userPrompt},
+		},
+		MaxTokens:      250,
+		Temperature:    0.9,
+		ResponseFormat: &responseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := extractJSONObject(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse forecast JSON: %w", err)
+	}
+	var result forecastAIResult
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse forecast JSON: %w", err)
+	}
+
+	result.Headline = truncateRunes(strings.TrimSpace(result.Headline), 200)
+	result.Forecast = truncateRunes(strings.TrimSpace(result.Forecast), 1000)
+	result.Focus = truncateRunes(strings.TrimSpace(result.Focus), 100)
+	result.LuckyColor = strings.ToLower(strings.TrimSpace(result.LuckyColor))
+	if result.Headline == "" || result.Forecast == "" {
+		return nil, errors.New("AI returned an empty forecast")
+	}
+	return &result, nil
+}
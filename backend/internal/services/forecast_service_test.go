@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestComputeForecastSignals(t *testing.T) {
+	// Newest first: blue and red tie, blue is more recent.
+	readings := []models.AuraReading{
+		{AuraColor: "Blue", EnergyLevel: 80, MoodScore: 8},
+		{AuraColor: "red", EnergyLevel: 70, MoodScore: 6},
+		{AuraColor: "blue", EnergyLevel: 40, MoodScore: 4},
+		{AuraColor: "red", EnergyLevel: 50, MoodScore: 6},
+	}
+	got := computeForecastSignals(readings)
+	if got.DominantColor != "blue" {
+		t.Errorf("dominant = %q, want blue", got.DominantColor)
+	}
+	if got.AvgEnergy != 60 || got.AvgMood != 6 {
+		t.Errorf("averages = %d/%d, want 60/6", got.AvgEnergy, got.AvgMood)
+	}
+	if got.Outlook != outlookRising {
+		t.Errorf("outlook = %q, want rising", got.Outlook)
+	}
+}
+
+func TestTemplateForecastIsDeterministic(t *testing.T) {
+	userID := uuid.New()
+	signals := forecastSignals{DominantColor: "green", Outlook: outlookDipping}
+
+	a := templateForecast(userID, "2026-03-01", signals)
+	b := templateForecast(userID, "2026-03-01", signals)
+	if *a != *b {
+		t.Fatalf("same user and day produced different forecasts: %+v vs %+v", a, b)
+	}
+	if a.LuckyColor == "" || a.LuckyColor == "green" || colorRelation("green", a.LuckyColor) == "challenging" {
+		t.Errorf("lucky color %q should be a harmonious other color", a.LuckyColor)
+	}
+	if a.Source != models.ForecastSourceTemplate || a.Forecast == "" || a.Headline == "" {
+		t.Errorf("incomplete template forecast: %+v", a)
+	}
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxDailyInvites caps friend requests plus emailed invites per user.
+	maxDailyInvites = 20
+	// declinedRequestCooldown keeps a declined requester from asking again.
+	declinedRequestCooldown = 30 * 24 * time.Hour
+	// emailInviteTTL is how long an emailed invite code stays redeemable.
+	emailInviteTTL = 7 * 24 * time.Hour
+)
+
+// Invite outcomes reported in dto.FriendInviteResponse.Status.
+const (
+	InviteStatusFriends   = "friends"
+	InviteStatusRequested = "requested"
+	InviteStatusSent      = "sent"
+)
+
+var (
+	ErrInvalidFriendInvite  = errors.New("provide exactly one of code, email or user_id")
+	ErrInviteSelf           = errors.New("you can't invite yourself")
+	ErrTooManyInvites       = errors.New("too many invites today; try again tomorrow")
+	ErrFriendRequestMissing = errors.New("friend request not found")
+	ErrFriendNotFound       = errors.New("friend not found")
+)
+
+// Invite sends a friend invitation by invite code, email or user ID.
+// Redeeming a code makes friends immediately; a user ID creates a pending
+// request; an email becomes a request when the address has an account and
+// an emailed invite code otherwise, with the same response either way.
+func (s *FriendService) Invite(userID uuid.UUID, req *dto.FriendInviteRequest, locale string) (*dto.FriendInviteResponse, error) {
+	code, email, target := strings.TrimSpace(req.Code), strings.TrimSpace(req.Email), strings.TrimSpace(req.UserID)
+	given := 0
+	for _, v := range []string{code, email, target} {
+		if v != "" {
+			given++
+		}
+	}
+	if given != 1 {
+		return nil, ErrInvalidFriendInvite
+	}
+
+	switch {
+	case code != "":
+		friend, err := s.RedeemInviteCode(userID, code)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.FriendInviteResponse{Status: InviteStatusFriends, Friend: friend}, nil
+
+	case target != "":
+		targetID, err := uuid.Parse(target)
+		if err != nil || !isActiveUser(s.db, targetID) {
+			return nil, ErrFriendNotFound
+		}
+		if err := s.checkInviteAllowed(userID, targetID); err != nil {
+			return nil, err
+		}
+		return s.request(userID, targetID, "request")
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return nil, ErrInvalidEmailAddress
+	}
+	email = normalizeEmail(addr.Address)
+
+	var existing models.User
+	if err := s.db.Select("id").Where("LOWER(email) = ?", email).First(&existing).Error; err == nil {
+		if existing.ID == userID {
+			return nil, ErrInviteSelf
+		}
+		if err := s.checkInviteAllowed(userID, existing.ID); err != nil {
+			// Don't reveal blocks or that the address has an account.
+			if errors.Is(err, ErrInviteBlocked) {
+				return &dto.FriendInviteResponse{Status: InviteStatusSent}, nil
+			}
+			return nil, err
+		}
+		if _, err := s.request(userID, existing.ID, "email"); err != nil {
+			return nil, err
+		}
+		return &dto.FriendInviteResponse{Status: InviteStatusSent}, nil
+	}
+
+	if err := s.emailInvite(userID, email, locale); err != nil {
+		return nil, err
+	}
+	return &dto.FriendInviteResponse{Status: InviteStatusSent}, nil
+}
+
+func (s *FriendService) checkInviteAllowed(userID, targetID uuid.UUID) error {
+	if userID == targetID {
+		return ErrInviteSelf
+	}
+	if isBlockedPair(s.db, userID, targetID) {
+		return ErrInviteBlocked
+	}
+	return nil
+}
+
+// invitesSentSince counts requests and emailed invites the user started.
+func (s *FriendService) invitesSentSince(userID uuid.UUID, since time.Time) int64 {
+	var requests, emailed int64
+	s.db.Model(&models.Friendship{}).
+		Where("requester_id = ? AND source IN ? AND created_at >= ?", userID, []string{"request", "email"}, since).
+		Count(&requests)
+	s.db.Model(&models.FriendInviteCode{}).
+		Where("user_id = ? AND invited_email_hash <> '' AND created_at >= ?", userID, since).
+		Count(&emailed)
+	return requests + emailed
+}
+
+// request creates or advances the friendship row between the pair.
+func (s *FriendService) request(userID, targetID uuid.UUID, source string) (*dto.FriendInviteResponse, error) {
+	existing, err := s.findFriendship(s.db, userID, targetID)
+	if err == nil {
+		switch {
+		case existing.Status == models.FriendshipAccepted:
+			return &dto.FriendInviteResponse{Status: InviteStatusFriends, Friend: s.friendResponse(targetID, existing)}, nil
+		case existing.Status == models.FriendshipPending && existing.RequesterID == userID:
+			return &dto.FriendInviteResponse{Status: InviteStatusRequested}, nil
+		case existing.Status == models.FriendshipPending:
+			// They already asked us: inviting back accepts.
+			friend, err := s.accept(existing, userID)
+			if err != nil {
+				return nil, err
+			}
+			return &dto.FriendInviteResponse{Status: InviteStatusFriends, Friend: friend}, nil
+		case existing.RequesterID == userID && time.Since(existing.UpdatedAt) < declinedRequestCooldown:
+			// Declined recently: look pending to the requester, notify no one.
+			return &dto.FriendInviteResponse{Status: InviteStatusRequested}, nil
+		}
+	}
+
+	if s.invitesSentSince(userID, time.Now().Add(-24*time.Hour)) >= maxDailyInvites {
+		return nil, ErrTooManyInvites
+	}
+
+	friendship := models.Friendship{RequesterID: userID, AddresseeID: targetID, Status: models.FriendshipPending, Source: source}
+	if existing != nil {
+		// Reuse the declined row, now pointing from the new requester.
+		err = s.db.Model(existing).Updates(map[string]interface{}{
+			"requester_id": userID,
+			"addressee_id": targetID,
+			"status":       models.FriendshipPending,
+			"source":       source,
+			"accepted_at":  nil,
+			"created_at":   time.Now(),
+		}).Error
+		friendship.ID = existing.ID
+	} else {
+		err = s.db.Create(&friendship).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyFriend(targetID, userID, "Friend request", "%s wants to be friends on AuraSnap.", "friend_request")
+	return &dto.FriendInviteResponse{Status: InviteStatusRequested}, nil
+}
+
+// emailInvite mails a single-use invite code to an address without an
+// account. The same address is not emailed twice while a code is live.
+func (s *FriendService) emailInvite(userID uuid.UUID, email, locale string) error {
+	if s.emails == nil {
+		return errors.New("email invites are not configured")
+	}
+	sum := sha256.Sum256([]byte(email))
+	hash := hex.EncodeToString(sum[:])
+
+	var live int64
+	s.db.Model(&models.FriendInviteCode{}).
+		Where("user_id = ? AND invited_email_hash = ? AND expires_at > ? AND revoked_at IS NULL", userID, hash, time.Now()).
+		Count(&live)
+	if live > 0 {
+		return nil
+	}
+	if s.invitesSentSince(userID, time.Now().Add(-24*time.Hour)) >= maxDailyInvites {
+		return ErrTooManyInvites
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return err
+	}
+	invite := models.FriendInviteCode{
+		UserID:           userID,
+		Code:             code,
+		MaxUses:          1,
+		ExpiresAt:        time.Now().Add(emailInviteTTL),
+		InvitedEmailHash: hash,
+	}
+	if err := s.db.Create(&invite).Error; err != nil {
+		return err
+	}
+
+	err = s.emails.Send(email, "friend_invite", locale, map[string]any{
+		"InviterHandle": s.handleOf(userID),
+		"InviteURL":     s.inviteLink(code),
+		"Code":          code,
+		"ExpiresDays":   int(emailInviteTTL.Hours() / 24),
+	})
+	if errors.Is(err, ErrEmailSuppressed) {
+		// The recipient opted out of mail; the code just goes unused.
+		return nil
+	}
+	return err
+}
+
+// ListRequests returns pending requests to and from the user, newest first.
+func (s *FriendService) ListRequests(userID uuid.UUID) (*dto.FriendRequestsResponse, error) {
+	var pending []models.Friendship
+	if err := s.db.Scopes(activeUsersOnly("requester_id", "addressee_id")).
+		Where("(requester_id = ? OR addressee_id = ?) AND status = ?", userID, userID, models.FriendshipPending).
+		Order("created_at DESC").
+		Find(&pending).Error; err != nil {
+		return nil, err
+	}
+
+	out := &dto.FriendRequestsResponse{Incoming: []dto.FriendRequestResponse{}, Outgoing: []dto.FriendRequestResponse{}}
+	for _, f := range pending {
+		other, direction := f.RequesterID, "incoming"
+		if f.RequesterID == userID {
+			other, direction = f.AddresseeID, "outgoing"
+		}
+		req := dto.FriendRequestResponse{
+			ID:        f.ID.String(),
+			UserID:    other.String(),
+			Handle:    s.handleOf(other),
+			Direction: direction,
+			CreatedAt: f.CreatedAt,
+		}
+		if direction == "incoming" {
+			out.Incoming = append(out.Incoming, req)
+		} else {
+			out.Outgoing = append(out.Outgoing, req)
+		}
+	}
+	return out, nil
+}
+
+// AcceptRequest accepts an incoming pending request.
+func (s *FriendService) AcceptRequest(userID, requestID uuid.UUID) (*dto.FriendResponse, error) {
+	f, err := s.incomingRequest(userID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if isBlockedPair(s.db, userID, f.RequesterID) {
+		return nil, ErrInviteBlocked
+	}
+	return s.accept(f, userID)
+}
+
+// DeclineRequest declines an incoming pending request. The requester is not
+// told and can't ask again until the cooldown passes.
+func (s *FriendService) DeclineRequest(userID, requestID uuid.UUID) error {
+	f, err := s.incomingRequest(userID, requestID)
+	if err != nil {
+		return err
+	}
+	return s.db.Model(f).Update("status", models.FriendshipDeclined).Error
+}
+
+func (s *FriendService) incomingRequest(userID, requestID uuid.UUID) (*models.Friendship, error) {
+	var f models.Friendship
+	if err := s.db.Scopes(activeUsersOnly("requester_id")).
+		Where("id = ? AND addressee_id = ? AND status = ?", requestID, userID, models.FriendshipPending).
+		First(&f).Error; err != nil {
+		return nil, ErrFriendRequestMissing
+	}
+	return &f, nil
+}
+
+func (s *FriendService) accept(f *models.Friendship, accepterID uuid.UUID) (*dto.FriendResponse, error) {
+	now := time.Now()
+	if err := s.db.Model(f).Updates(map[string]interface{}{
+		"status":      models.FriendshipAccepted,
+		"accepted_at": now,
+	}).Error; err != nil {
+		return nil, err
+	}
+	f.Status, f.AcceptedAt = models.FriendshipAccepted, &now
+
+	other := f.RequesterID
+	if other == accepterID {
+		other = f.AddresseeID
+	}
+	s.notifyFriend(other, accepterID, "Friend request accepted", "%s accepted your friend request.", "friend_added")
+	return s.friendResponse(other, f), nil
+}
+
+// Unfriend removes a friendship, or withdraws the user's own pending request.
+func (s *FriendService) Unfriend(userID, friendID uuid.UUID) error {
+	result := s.db.Where(
+		"((requester_id = ? AND addressee_id = ?) OR (requester_id = ? AND addressee_id = ?)) AND (status = ? OR (status = ? AND requester_id = ?))",
+		userID, friendID, friendID, userID, models.FriendshipAccepted, models.FriendshipPending, userID,
+	).Delete(&models.Friendship{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFriendNotFound
+	}
+	return nil
+}
+
+// removeFriendships drops every friendship row between a blocked pair.
+func removeFriendships(db *gorm.DB, a, b uuid.UUID) error {
+	return db.Where("(requester_id = ? AND addressee_id = ?) OR (requester_id = ? AND addressee_id = ?)", a, b, b, a).
+		Delete(&models.Friendship{}).Error
+}
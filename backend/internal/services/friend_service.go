@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+const (
+	inviteCodeLength     = 8
+	defaultInviteTTL     = 24 * time.Hour
+	maxInviteTTL         = 7 * 24 * time.Hour
+	maxInviteUses        = 50
+	maxActiveInviteCodes = 10
+	inviteQRSize         = 512
+)
+
+// inviteCodeAlphabet omits look-alikes (0/O, 1/I) so codes can be typed.
+const inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+var (
+	ErrInviteCodeNotFound = errors.New("invite code not found")
+	ErrInviteCodeInvalid  = errors.New("invite code is expired, revoked or used up")
+	ErrInviteOwnCode      = errors.New("you can't redeem your own invite code")
+	ErrInviteBlocked      = errors.New("this invite can't be redeemed")
+	ErrTooManyInviteCodes = errors.New("too many active invite codes; revoke one first")
+)
+
+// FriendService manages friendships and the invite codes that create them.
+type FriendService struct {
+	db            *gorm.DB
+	cfg           *config.Config
+	notifications *NotificationService
+	emails        *EmailService
+}
+
+func NewFriendService(db *gorm.DB, cfg *config.Config, notifications *NotificationService, emails *EmailService) *FriendService {
+	return &FriendService{db: db, cfg: cfg, notifications: notifications, emails: emails}
+}
+
+// CreateInviteCode issues a code that up to MaxUses people can redeem
+// before it expires.
+func (s *FriendService) CreateInviteCode(userID uuid.UUID, req *dto.CreateInviteCodeRequest, baseURL string) (*dto.InviteCodeResponse, error) {
+	maxUses := req.MaxUses
+	if maxUses <= 0 {
+		maxUses = 1
+	}
+	if maxUses > maxInviteUses {
+		maxUses = maxInviteUses
+	}
+	ttl := time.Duration(req.TTLMinutes) * time.Minute
+	if ttl <= 0 {
+		ttl = defaultInviteTTL
+	}
+	if ttl > maxInviteTTL {
+		ttl = maxInviteTTL
+	}
+
+	var active int64
+	s.db.Model(&models.FriendInviteCode{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ? AND use_count < max_uses", userID, time.Now()).
+		Count(&active)
+	if active >= maxActiveInviteCodes {
+		return nil, ErrTooManyInviteCodes
+	}
+
+	invite := models.FriendInviteCode{
+		UserID:    userID,
+		MaxUses:   maxUses,
+		ExpiresAt: time.Now().Add(ttl).UTC().Truncate(time.Second),
+	}
+	// Retry on the rare collision with an existing code.
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		if invite.Code, err = generateInviteCode(); err != nil {
+			return nil, err
+		}
+		if err = s.db.Create(&invite).Error; err == nil {
+			return s.inviteResponse(&invite, baseURL), nil
+		}
+	}
+	return nil, fmt.Errorf("failed to create invite code: %w", err)
+}
+
+// ListInviteCodes returns the user's codes that can still be redeemed.
+func (s *FriendService) ListInviteCodes(userID uuid.UUID, baseURL string) ([]dto.InviteCodeResponse, error) {
+	var invites []models.FriendInviteCode
+	err := s.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ? AND use_count < max_uses", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&invites).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.InviteCodeResponse, 0, len(invites))
+	for i := range invites {
+		out = append(out, *s.inviteResponse(&invites[i], baseURL))
+	}
+	return out, nil
+}
+
+// RevokeInviteCode stops a code from being redeemed.
+func (s *FriendService) RevokeInviteCode(userID uuid.UUID, code string) error {
+	result := s.db.Model(&models.FriendInviteCode{}).
+		Where("user_id = ? AND code = ? AND revoked_at IS NULL", userID, normalizeInviteCode(code)).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInviteCodeNotFound
+	}
+	return nil
+}
+
+// InviteQRCode renders the invite deep link as a PNG for the code's owner.
+func (s *FriendService) InviteQRCode(userID uuid.UUID, code string) ([]byte, error) {
+	var invite models.FriendInviteCode
+	if err := s.db.Where("user_id = ? AND code = ?", userID, normalizeInviteCode(code)).First(&invite).Error; err != nil {
+		return nil, ErrInviteCodeNotFound
+	}
+	return qrcode.Encode(s.inviteLink(invite.Code), qrcode.Medium, inviteQRSize)
+}
+
+// RedeemInviteCode makes the redeemer and the code owner friends. Redeeming
+// when already friends succeeds without consuming a use; a pending or
+// declined request between the pair is upgraded in place.
+func (s *FriendService) RedeemInviteCode(userID uuid.UUID, code string) (*dto.FriendResponse, error) {
+	var invite models.FriendInviteCode
+	if err := s.db.Scopes(activeUsersOnly("user_id")).Where("code = ?", normalizeInviteCode(code)).First(&invite).Error; err != nil {
+		return nil, ErrInviteCodeNotFound
+	}
+	if invite.UserID == userID {
+		return nil, ErrInviteOwnCode
+	}
+	if isBlockedPair(s.db, userID, invite.UserID) {
+		return nil, ErrInviteBlocked
+	}
+
+	existing, err := s.findFriendship(s.db, userID, invite.UserID)
+	if err == nil && existing.Status == models.FriendshipAccepted {
+		return s.friendResponse(invite.UserID, existing), nil
+	}
+
+	var friendship models.Friendship
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Conditional increment keeps concurrent redemptions within MaxUses.
+		result := tx.Model(&models.FriendInviteCode{}).
+			Where("id = ? AND revoked_at IS NULL AND expires_at > ? AND use_count < max_uses", invite.ID, time.Now()).
+			UpdateColumn("use_count", gorm.Expr("use_count + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInviteCodeInvalid
+		}
+
+		now := time.Now()
+		friendship = models.Friendship{
+			RequesterID: invite.UserID,
+			AddresseeID: userID,
+			Status:      models.FriendshipAccepted,
+			Source:      "invite_code",
+			AcceptedAt:  &now,
+		}
+		if existing != nil {
+			friendship.ID = existing.ID
+			return tx.Model(existing).Updates(map[string]interface{}{
+				"requester_id": friendship.RequesterID,
+				"addressee_id": friendship.AddresseeID,
+				"status":       friendship.Status,
+				"source":       friendship.Source,
+				"accepted_at":  now,
+			}).Error
+		}
+		return tx.Create(&friendship).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyFriend(invite.UserID, userID, "New friend", "%s joined you as a friend with your invite.", "friend_added")
+	return s.friendResponse(invite.UserID, &friendship), nil
+}
+
+// ListFriends returns accepted friends, newest first.
+func (s *FriendService) ListFriends(userID uuid.UUID) ([]dto.FriendResponse, error) {
+	var friendships []models.Friendship
+	err := s.db.Scopes(activeUsersOnly("requester_id", "addressee_id")).
+		Where("(requester_id = ? OR addressee_id = ?) AND status = ?", userID, userID, models.FriendshipAccepted).
+		Order("created_at DESC").
+		Find(&friendships).Error
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]dto.FriendResponse, 0, len(friendships))
+	for i := range friendships {
+		f := &friendships[i]
+		other := f.RequesterID
+		if other == userID {
+			other = f.AddresseeID
+		}
+		out = append(out, *s.friendResponse(other, f))
+	}
+	return out, nil
+}
+
+func (s *FriendService) findFriendship(db *gorm.DB, a, b uuid.UUID) (*models.Friendship, error) {
+	var f models.Friendship
+	err := db.Where("(requester_id = ? AND addressee_id = ?) OR (requester_id = ? AND addressee_id = ?)", a, b, b, a).
+		First(&f).Error
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (s *FriendService) friendResponse(friendID uuid.UUID, f *models.Friendship) *dto.FriendResponse {
+	resp := &dto.FriendResponse{ID: friendID.String(), Handle: s.handleOf(friendID), FriendsSince: f.CreatedAt}
+	if f.AcceptedAt != nil {
+		resp.FriendsSince = *f.AcceptedAt
+	}
+	return resp
+}
+
+// handleOf returns the user's handle, or "" when they haven't set one.
+func (s *FriendService) handleOf(userID uuid.UUID) string {
+	var user models.User
+	if err := s.db.Select("handle").First(&user, "id = ?", userID).Error; err == nil && user.Handle != nil {
+		return *user.Handle
+	}
+	return ""
+}
+
+// notifyFriend tells recipientID about something actorID did; bodyFormat's
+// %s is replaced with the actor's @handle.
+func (s *FriendService) notifyFriend(recipientID, actorID uuid.UUID, title, bodyFormat, event string) {
+	if s.notifications == nil {
+		return
+	}
+	name := "Someone"
+	if handle := s.handleOf(actorID); handle != "" {
+		name = "@" + handle
+	}
+	err := s.notifications.NotifyLater(context.Background(), recipientID, NotificationMessage{
+		Category: CategorySocial,
+		Title:    title,
+		Body:     fmt.Sprintf(bodyFormat, name),
+		Data:     map[string]string{"event": event, "friend_id": actorID.String()},
+	})
+	if err != nil {
+		log.Printf("friend notification for %s: %v", recipientID, err)
+	}
+}
+
+func (s *FriendService) inviteResponse(invite *models.FriendInviteCode, baseURL string) *dto.InviteCodeResponse {
+	if configured := strings.TrimSpace(s.cfg.PublicBaseURL); configured != "" {
+		baseURL = configured
+	}
+	return &dto.InviteCodeResponse{
+		Code:      invite.Code,
+		DeepLink:  s.inviteLink(invite.Code),
+		QRURL:     strings.TrimRight(baseURL, "/") + "/api/friends/invite-code/" + invite.Code + "/qr.png",
+		MaxUses:   invite.MaxUses,
+		UseCount:  invite.UseCount,
+		ExpiresAt: invite.ExpiresAt,
+	}
+}
+
+func (s *FriendService) inviteLink(code string) string {
+	return strings.TrimRight(s.cfg.InviteLinkBaseURL, "/") + "/" + code
+}
+
+func generateInviteCode() (string, error) {
+	max := big.NewInt(int64(len(inviteCodeAlphabet)))
+	code := make([]byte, inviteCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = inviteCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// normalizeInviteCode accepts codes typed with spaces/dashes or in lowercase.
+func normalizeInviteCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// isBlockedPair reports whether either user has blocked the other.
+func isBlockedPair(db *gorm.DB, a, b uuid.UUID) bool {
+	var count int64
+	db.Model(&models.Block{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", a, b, b, a).
+		Count(&count)
+	return count > 0
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/google/uuid"
+)
+
+func TestGenerateInviteCode(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		code, err := generateInviteCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(code) != inviteCodeLength {
+			t.Fatalf("code %q has length %d", code, len(code))
+		}
+		for _, r := range code {
+			if !strings.ContainsRune(inviteCodeAlphabet, r) {
+				t.Fatalf("code %q contains %q outside the alphabet", code, r)
+			}
+		}
+		seen[code] = true
+	}
+	if len(seen) < 99 {
+		t.Errorf("expected unique codes, got %d distinct of 100", len(seen))
+	}
+}
+
+func TestNormalizeInviteCode(t *testing.T) {
+	if got := normalizeInviteCode(" abcd-ef 23 "); got != "ABCDEF23" {
+		t.Errorf("normalizeInviteCode = %q", got)
+	}
+}
+
+func TestInviteRequiresExactlyOneTarget(t *testing.T) {
+	s := &FriendService{}
+	for _, req := range []dto.FriendInviteRequest{
+		{},
+		{Code: "ABCD2345", Email: "a@example.com"},
+		{Email: "a@example.com", UserID: uuid.NewString()},
+		{Code: "  ", Email: " "},
+	} {
+		if _, err := s.Invite(uuid.New(), &req, "en"); !errors.Is(err, ErrInvalidFriendInvite) {
+			t.Errorf("Invite(%+v) error = %v, want ErrInvalidFriendInvite", req, err)
+		}
+	}
+}
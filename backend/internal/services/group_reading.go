@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	minGroupPeople = 2
+	maxGroupPeople = 8
+)
+
+var (
+	ErrNotAGroupPhoto       = errors.New("fewer than two people were found in this photo; use a single scan instead")
+	ErrGroupScanUnavailable = errors.New("group scans are unavailable right now; try again later or pass people_count")
+	ErrGroupReadingNotFound = errors.New("group reading not found")
+)
+
+// groupAnalysis is the analyzer's result for a multi-person photo.
+type groupAnalysis struct {
+	People      []auraReadingDraft
+	GroupEnergy string
+	Source      string
+}
+
+// personalReadings excludes the per-person readings of group scans, which
+// describe the people in a photo rather than the scanning user.
+func personalReadings(db *gorm.DB) *gorm.DB {
+	return db.Where("group_reading_id IS NULL")
+}
+
+// CreateGroup reads the aura of each person in a multi-person photo and
+// stores them as one GroupReading. A group scan uses one daily scan.
+func (s *AuraService) CreateGroup(ctx context.Context, userID uuid.UUID, req dto.CreateGroupAuraRequest) (*models.GroupReading, error) {
+	ctx, span := tracer.Start(ctx, "AuraService.CreateGroup")
+	defer span.End()
+	ctx = withAIUser(ctx, s.db, userID)
+	db := s.db.WithContext(ctx)
+
+	imageURL, err := scanImageURL(dto.CreateAuraRequest{ImageURL: req.ImageURL, ImageData: req.ImageData})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.screenImage(ctx, db, userID, imageURL, req.ImageData); err != nil {
+		return nil, err
+	}
+	metrics.ScansTotal.WithLabelValues("attempted").Inc()
+
+	imageHash := inlineImageHash(req.ImageData)
+	bases := func(n int) []auraAnalysisResult {
+		out := make([]auraAnalysisResult, n)
+		for i := range out {
+			out[i] = deterministicAuraResult(userID, fmt.Sprintf("%s#%d", photoKey(imageURL, imageHash), i))
+		}
+		return out
+	}
+
+	started := time.Now()
+	analysis, err := s.analyzer.analyzeGroup(ctx, imageURL, bases(maxGroupPeople))
+	source, latency := scanSource(analysis.Source, err, started)
+	if err != nil {
+		if errors.Is(err, ErrNotAGroupPhoto) {
+			return nil, err
+		}
+		span.RecordError(err)
+		// Without face detection the head count can only come from the client.
+		if req.PeopleCount < minGroupPeople {
+			return nil, ErrGroupScanUnavailable
+		}
+		metrics.ScanFallbackTotal.WithLabelValues(scanFallbackReason(err)).Inc()
+		analysis = deterministicGroup(bases(min(req.PeopleCount, maxGroupPeople)))
+	}
+
+	group := &models.GroupReading{
+		UserID:      userID,
+		ImageURL:    imageURL,
+		PeopleCount: len(analysis.People),
+		GroupEnergy: analysis.GroupEnergy,
+		Provenance:  map[string]string{"people": analysis.Source, "group_energy": analysis.Source},
+	}
+	now := time.Now()
+	for i, draft := range analysis.People {
+		position := i
+		group.People = append(group.People, models.AuraReading{
+			UserID:            userID,
+			ImageURL:          imageURL,
+			ImageHash:         imageHash,
+			AuraColor:         draft.Scores.AuraColor,
+			SecondaryColor:    draft.Scores.SecondaryColor,
+			EnergyLevel:       clamp(draft.Scores.EnergyLevel, 1, 100),
+			MoodScore:         clamp(draft.Scores.MoodScore, 1, 10),
+			Personality:       draft.Personality,
+			Strengths:         draft.Strengths,
+			Challenges:        draft.Challenges,
+			DailyAdvice:       draft.DailyAdvice,
+			Provenance:        draft.Provenance,
+			Source:            source,
+			ProviderLatencyMs: latency,
+			GroupPosition:     &position,
+			AnalyzedAt:        now,
+		})
+	}
+	group.DominantColor, group.Harmony = groupEnergyScores(group.People)
+	if group.GroupEnergy == "" {
+		group.GroupEnergy = templateGroupEnergy(group.DominantColor, group.Harmony, group.PeopleCount)
+		group.Provenance["group_energy"] = provenanceDeterministic
+	}
+
+	if err := db.Create(group).Error; err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to save group reading")
+		metrics.ScansTotal.WithLabelValues("failed").Inc()
+		return nil, err
+	}
+
+	metrics.ScansTotal.WithLabelValues("succeeded").Inc()
+	return group, nil
+}
+
+// GetGroup returns one of the user's group readings with its people.
+func (s *AuraService) GetGroup(userID, id uuid.UUID) (*models.GroupReading, error) {
+	var group models.GroupReading
+	err := s.db.Preload("People", func(db *gorm.DB) *gorm.DB {
+		return db.Order("group_position")
+	}).Where("user_id = ? AND id = ?", userID, id).First(&group).Error
+	if err != nil {
+		return nil, ErrGroupReadingNotFound
+	}
+	return &group, nil
+}
+
+// deterministicGroup builds a group reading from the deterministic engine alone.
+func deterministicGroup(bases []auraAnalysisResult) groupAnalysis {
+	analysis := groupAnalysis{People: make([]auraReadingDraft, len(bases)), Source: provenanceDeterministic}
+	for i, base := range bases {
+		analysis.People[i] = deterministicDraft(base)
+	}
+	return analysis
+}
+
+// groupEnergyScores returns the most common aura color (earliest position
+// wins ties) and the mean pairwise compatibility of everyone in the photo.
+func groupEnergyScores(people []models.AuraReading) (string, int) {
+	counts := make(map[string]int, len(people))
+	dominant := ""
+	for _, p := range people {
+		counts[p.AuraColor]++
+		if dominant == "" || counts[p.AuraColor] > counts[dominant] {
+			dominant = p.AuraColor
+		}
+	}
+
+	total, pairs := 0, 0
+	for i := range people {
+		for j := i + 1; j < len(people); j++ {
+			total += scoreCompatibility(people[i], people[j], nil, nil).Score
+			pairs++
+		}
+	}
+	if pairs == 0 {
+		return dominant, 0
+	}
+	return dominant, int(math.Round(float64(total) / float64(pairs)))
+}
+
+func templateGroupEnergy(dominant string, harmony, people int) string {
+	mood := "a lively mix of energies that spark off each other"
+	switch {
+	case harmony >= 80:
+		mood = "a strongly harmonious energy, easy to be around together"
+	case harmony >= 60:
+		mood = "a balanced energy where differences complement each other"
+	}
+	return fmt.Sprintf("These %d share %s, led by %s.", people, mood, dominant)
+}
+
+// analyzeGroup asks the providers to find every person in the photo and read
+// each aura. bases supplies the per-position deterministic fallback used to
+// salvage malformed fields.
+func (a *auraAIAnalyzer) analyzeGroup(ctx context.Context, imageURL string, bases []auraAnalysisResult) (groupAnalysis, error) {
+	if a != nil && a.mockMode {
+		return groupAnalysis{}, errAuraMockMode
+	}
+	if a == nil || len(a.providers) == 0 {
+		return groupAnalysis{}, errAuraAIDisabled
+	}
+	if a.usage.OverBudget(ctx) {
+		return groupAnalysis{}, errAIBudgetExceeded
+	}
+
+	var lastErr error
+	for _, provider := range a.providers {
+		start := time.Now()
+		providerCtx, span := tracer.Start(ctx, "aura.ai.group."+provider.name, trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("ai.provider", provider.name), attribute.String("ai.model", provider.model)))
+		providerCtx, tokens := withAITokenCounter(providerCtx)
+		result, err := a.analyzeGroupWithProvider(providerCtx, provider, imageURL, bases)
+		recordAIInteraction(ctx, models.AIPurposeGroupScan, provider.name, provider.model, err, start)
+		a.usage.Record(ctx, models.AIPurposeGroupScan, provider.name, provider.model, *tokens, err, start)
+		outcome := "success"
+		if err != nil {
+			outcome = "error"
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		metrics.AIProviderDuration.WithLabelValues(provider.name, outcome).Observe(time.Since(start).Seconds())
+		if err == nil || errors.Is(err, ErrNotAGroupPhoto) {
+			return result, err
+		}
+		lastErr = fmt.Errorf("%s provider failed: %w", provider.name, err)
+	}
+	return groupAnalysis{}, lastErr
+}
+
+func (a *auraAIAnalyzer) analyzeGroupWithProvider(ctx context.Context, provider auraAIProvider, imageURL string, bases []auraAnalysisResult) (groupAnalysis, error) {
+	prompt := fmt.Sprintf(
+		"Detect each distinct person's face in this photo and read each person's aura. Return only JSON. image_url=%q allowed_colors=%v. Output keys: people (array ordered left to right, at most %d entries, empty if fewer than two faces), each with aura_color (string), secondary_color (string or null), energy_level (1-100), mood_score (1-10), personality (1 sentence), strengths (array of 3 short strings), challenges (array of 3 short strings), daily_advice (1 sentence); and group_energy (2-3 sentences on how these auras combine as a group). Keep results realistic.",
+		imageURL,
+		auraColors,
+		len(bases),
+	)
+
+	reqBody := auraChatCompletionRequest{
+		Model: provider.model,
+		Messages: []auraChatMessage{
+			{Role: "system", Content: "You are an aura analysis engine for group photos. Return valid JSON only."},
+			{Role: "user", Content: prompt},
+		},
+		ResponseFormat: jsonObjectResponseFormat,
+	}
+	if provider.supportsJSONSchema {
+		reqBody.ResponseFormat = groupResultResponseFormat
+	}
+	reqBody.Temperature, reqBody.Seed = a.sampling(provider, bases[0], auraAnalysisOptions{})
+
+	content, status, err := a.complete(ctx, provider, reqBody)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("http.response.status_code", status))
+	if err != nil && status == http.StatusBadRequest && provider.supportsJSONSchema {
+		reqBody.ResponseFormat = jsonObjectResponseFormat
+		content, _, err = a.complete(ctx, provider, reqBody)
+	}
+	if err != nil {
+		return groupAnalysis{}, err
+	}
+	return parseGroupAnalysis(content, bases, provider.name)
+}
+
+// parseGroupAnalysis salvages each person independently, like single scans;
+// people beyond len(bases) are dropped.
+func parseGroupAnalysis(content string, bases []auraAnalysisResult, source string) (groupAnalysis, error) {
+	raw, err := extractJSONObject(content)
+	if err != nil {
+		return groupAnalysis{}, err
+	}
+	var fields struct {
+		People      []json.RawMessage `json:"people"`
+		GroupEnergy json.RawMessage   `json:"group_energy"`
+	}
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return groupAnalysis{}, err
+	}
+
+	analysis := groupAnalysis{Source: source}
+	for _, person := range fields.People {
+		if len(analysis.People) == len(bases) {
+			break
+		}
+		partial, err := parseAuraAIPartial(string(person))
+		if err != nil || partial.empty() {
+			continue
+		}
+		analysis.People = append(analysis.People, salvageAuraDraft(bases[len(analysis.People)], partial, source))
+	}
+	if len(analysis.People) < minGroupPeople {
+		return groupAnalysis{}, ErrNotAGroupPhoto
+	}
+	if summary, ok := rawString(fields.GroupEnergy); ok {
+		analysis.GroupEnergy = truncateRunes(strings.TrimSpace(summary), 600)
+	}
+	return analysis, nil
+}
+
+// groupResultResponseFormat is the structured-output schema for group scans;
+// each person uses the single-reading schema.
+var groupResultResponseFormat = map[string]any{
+	"type": "json_schema",
+	"json_schema": map[string]any{
+		"name":   "group_aura_reading",
+		"strict": true,
+		"schema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"people":       map[string]any{"type": "array", "items": auraResultSchema()},
+				"group_energy": map[string]any{"type": "string"},
+			},
+			"required":             []string{"people", "group_energy"},
+			"additionalProperties": false,
+		},
+	},
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func groupBases(n int) []auraAnalysisResult {
+	bases := make([]auraAnalysisResult, n)
+	for i := range bases {
+		bases[i] = deterministicAuraResult(uuid.Nil, "group.jpg#"+string(rune('0'+i)))
+	}
+	return bases
+}
+
+func TestParseGroupAnalysis(t *testing.T) {
+	content := "```json\n" + `{"people":[
+		{"aura_color":"blue","energy_level":70,"mood_score":8},
+		"not a person",
+		{"aura_color":"gold","energy_level":55,"mood_score":6},
+		{"aura_color":"pink","energy_level":60,"mood_score":7}
+	],"group_energy":"A calm, generous circle."}` + "\n```"
+
+	analysis, err := parseGroupAnalysis(content, groupBases(2), "openai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analysis.People) != 2 {
+		t.Fatalf("people = %d, want 2 (malformed entry skipped, extras beyond bases dropped)", len(analysis.People))
+	}
+	if analysis.People[0].Scores.AuraColor != "blue" || analysis.People[1].Scores.AuraColor != "gold" {
+		t.Errorf("colors = %s, %s", analysis.People[0].Scores.AuraColor, analysis.People[1].Scores.AuraColor)
+	}
+	if analysis.GroupEnergy != "A calm, generous circle." {
+		t.Errorf("group energy = %q", analysis.GroupEnergy)
+	}
+}
+
+func TestParseGroupAnalysisSinglePerson(t *testing.T) {
+	_, err := parseGroupAnalysis(`{"people":[{"aura_color":"red"}],"group_energy":""}`, groupBases(8), "glm")
+	if !errors.Is(err, ErrNotAGroupPhoto) {
+		t.Errorf("err = %v, want ErrNotAGroupPhoto", err)
+	}
+}
+
+func TestGroupEnergyScores(t *testing.T) {
+	people := []models.AuraReading{
+		{AuraColor: "green", EnergyLevel: 60, MoodScore: 7},
+		{AuraColor: "blue", EnergyLevel: 60, MoodScore: 7},
+		{AuraColor: "blue", EnergyLevel: 60, MoodScore: 7},
+	}
+	dominant, harmony := groupEnergyScores(people)
+	if dominant != "blue" {
+		t.Errorf("dominant = %q, want blue", dominant)
+	}
+	if harmony < 0 || harmony > 100 {
+		t.Errorf("harmony = %d, want 0-100", harmony)
+	}
+
+	_, same := groupEnergyScores(people[1:])
+	if same <= 0 {
+		t.Errorf("identical pair harmony = %d, want positive", same)
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidHandle   = errors.New("handle must be 3-20 characters: letters, numbers, underscores or dots, starting with a letter")
+	ErrHandleReserved  = errors.New("this handle is reserved")
+	ErrHandleProfanity = errors.New("this handle isn't allowed")
+	ErrHandleTaken     = errors.New("this handle is already taken")
+	ErrHandleCooldown  = errors.New("handle was changed recently; try again later")
+)
+
+var handlePattern = regexp.MustCompile(`^[a-z][a-z0-9_.]{2,19}$`)
+
+// reservedHandles can't be claimed because they could impersonate the app,
+// staff, or collide with routes and client keywords.
+var reservedHandles = map[string]struct{}{
+	"admin": {}, "administrator": {}, "aurasnap": {}, "aura": {}, "auras": {},
+	"support": {}, "help": {}, "helpdesk": {}, "staff": {}, "team": {},
+	"moderator": {}, "mod": {}, "official": {}, "security": {}, "system": {},
+	"root": {}, "api": {}, "www": {}, "app": {}, "about": {}, "settings": {},
+	"me": {}, "you": {}, "null": {}, "undefined": {}, "anonymous": {}, "guest": {},
+	"everyone": {}, "here": {}, "apple": {}, "privacy": {}, "terms": {},
+}
+
+// blockedHandleTerms are matched as substrings after folding look-alike
+// characters, so "sh1t" or "f.u.c.k" are caught. English and Turkish.
+var blockedHandleTerms = []string{
+	"fuck", "shit", "bitch", "cunt", "pussy", "whore", "slut",
+	"nigger", "nigga", "faggot", "retard", "nazi", "hitler", "porn",
+	"orospu", "amcik", "sikis", "yarrak", "pezevenk", "ibne", "gavat", "kahpe",
+}
+
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "8", "b", "9", "g",
+	"_", "", ".", "",
+)
+
+// normalizeHandle lowercases and strips a leading "@".
+func normalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// validateHandle checks format, reservations and profanity for a
+// normalized handle.
+func validateHandle(handle string) error {
+	if !handlePattern.MatchString(handle) || strings.Contains(handle, "..") || strings.HasSuffix(handle, ".") {
+		return ErrInvalidHandle
+	}
+
+	folded := leetReplacer.Replace(handle)
+	if _, ok := reservedHandles[handle]; ok {
+		return ErrHandleReserved
+	}
+	if _, ok := reservedHandles[folded]; ok {
+		return ErrHandleReserved
+	}
+	if strings.HasPrefix(folded, "aurasnap") || strings.HasPrefix(folded, "admin") {
+		return ErrHandleReserved
+	}
+
+	if containsBlockedTerm(folded) {
+		return ErrHandleProfanity
+	}
+	return nil
+}
+
+// containsBlockedTerm reports whether folded text contains a blocked term.
+func containsBlockedTerm(folded string) bool {
+	for _, term := range blockedHandleTerms {
+		if strings.Contains(folded, term) {
+			return true
+		}
+	}
+	return false
+}
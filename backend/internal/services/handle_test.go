@@ -0,0 +1,38 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateHandle(t *testing.T) {
+	cases := []struct {
+		handle string
+		want   error
+	}{
+		{"ada.lovelace", nil},
+		{"lin_22", nil},
+		{"ab", ErrInvalidHandle},
+		{"1ada", ErrInvalidHandle},
+		{"ada..x", ErrInvalidHandle},
+		{"ada.", ErrInvalidHandle},
+		{"ada-x", ErrInvalidHandle},
+		{"this_handle_is_way_too_long", ErrInvalidHandle},
+		{"support", ErrHandleReserved},
+		{"adm1n", ErrHandleReserved},
+		{"aurasnap_team", ErrHandleReserved},
+		{"sh1t_happens", ErrHandleProfanity},
+		{"f.u.c.k", ErrHandleProfanity},
+	}
+	for _, tc := range cases {
+		if err := validateHandle(tc.handle); !errors.Is(err, tc.want) {
+			t.Errorf("validateHandle(%q) = %v, want %v", tc.handle, err, tc.want)
+		}
+	}
+}
+
+func TestNormalizeHandle(t *testing.T) {
+	if got := normalizeHandle("  @Ada.Lovelace "); got != "ada.lovelace" {
+		t.Errorf("normalizeHandle = %q", got)
+	}
+}
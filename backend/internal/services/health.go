@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	HealthOK          = "ok"
+	HealthDegraded    = "degraded"
+	HealthUnavailable = "unavailable"
+	HealthDown        = "down"
+	HealthDraining    = "draining"
+)
+
+const (
+	// healthProbeTimeout bounds each dependency probe.
+	healthProbeTimeout = 3 * time.Second
+	// healthCacheTTL reuses a readiness result for this long, so frequent
+	// polling (several load balancers, every instance) does not turn into
+	// a stream of provider calls.
+	healthCacheTTL = 10 * time.Second
+)
+
+// healthProbe checks one dependency. A critical dependency being down
+// makes the instance unavailable; others only degrade it, since the app
+// has a fallback for each (in-memory rate limits, template readings).
+type healthProbe struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+// HealthService probes the API's dependencies for the readiness endpoint.
+// Only configured dependencies are probed.
+type HealthService struct {
+	probes []healthProbe
+
+	// draining is set before shutdown so load balancers take the instance
+	// out of rotation while it finishes in-flight requests.
+	draining atomic.Bool
+
+	mu       sync.Mutex
+	cached   *dto.ReadinessResponse
+	cachedAt time.Time
+}
+
+func NewHealthService(cfg *config.Config, objectStore ObjectStore) *HealthService {
+	probes := []healthProbe{{name: "database", critical: true, check: database.PingContext}}
+
+	if strings.TrimSpace(cfg.RedisURL) != "" {
+		if opts, err := redis.ParseURL(cfg.RedisURL); err != nil {
+			log.Printf("health: invalid REDIS_URL, not probing redis: %v", err)
+		} else {
+			client := redis.NewClient(opts)
+			probes = append(probes, healthProbe{name: "redis", check: func(ctx context.Context) error {
+				return client.Ping(ctx).Err()
+			}})
+		}
+	}
+	if cfg.OpenAIAPIKey != "" {
+		client := &http.Client{Timeout: healthProbeTimeout}
+		probes = append(probes, healthProbe{name: "ai", check: func(ctx context.Context) error {
+			return probeOpenAI(ctx, client, cfg.OpenAIAPIKey)
+		}})
+	}
+	if store, ok := objectStore.(interface{ Probe(context.Context) error }); ok {
+		probes = append(probes, healthProbe{name: "storage", check: store.Probe})
+	}
+	return &HealthService{probes: probes}
+}
+
+// probeOpenAI lists models, the cheapest authenticated call the provider
+// has.
+func probeOpenAI(ctx context.Context, client *http.Client, apiKey string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.openai.com/v1/models", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("OpenAI API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Drain marks the instance as shutting down: readiness reports "draining"
+// from now on. It cannot be undone; the process is expected to exit.
+func (s *HealthService) Drain() {
+	if s.draining.CompareAndSwap(false, true) {
+		log.Println("health: draining, reporting not ready")
+	}
+}
+
+// Draining reports whether Drain was called.
+func (s *HealthService) Draining() bool {
+	return s.draining.Load()
+}
+
+// Ready probes every dependency concurrently and reports each one's status
+// and latency. Failures are logged; the response never carries their
+// details. A draining instance answers at once, without probing.
+func (s *HealthService) Ready(ctx context.Context) dto.ReadinessResponse {
+	if s.Draining() {
+		return dto.ReadinessResponse{Status: HealthDraining, Timestamp: time.Now().UTC().Format(time.RFC3339), Checks: map[string]dto.DependencyCheck{}}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cached != nil && time.Since(s.cachedAt) < healthCacheTTL {
+		return *s.cached
+	}
+
+	checks := make(map[string]dto.DependencyCheck, len(s.probes))
+	var (
+		wg      sync.WaitGroup
+		checkMu sync.Mutex
+	)
+	for _, p := range s.probes {
+		wg.Add(1)
+		go func(p healthProbe) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := p.check(probeCtx)
+			check := dto.DependencyCheck{Status: HealthOK, LatencyMs: time.Since(start).Milliseconds(), Critical: p.critical}
+			if err != nil {
+				log.Printf("health: %s probe failed: %v", p.name, err)
+				check.Status = HealthDown
+			}
+			checkMu.Lock()
+			checks[p.name] = check
+			checkMu.Unlock()
+		}(p)
+	}
+	wg.Wait()
+
+	status := HealthOK
+	for _, check := range checks {
+		if check.Status == HealthOK {
+			continue
+		}
+		if check.Critical {
+			status = HealthUnavailable
+			break
+		}
+		status = HealthDegraded
+	}
+
+	result := dto.ReadinessResponse{Status: status, Timestamp: time.Now().UTC().Format(time.RFC3339), Checks: checks}
+	s.cached, s.cachedAt = &result, time.Now()
+	return result
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestHealthReady(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name   string
+		probes []healthProbe
+		want   string
+	}{
+		{"all up", []healthProbe{{name: "database", critical: true, check: up}, {name: "redis", check: up}}, HealthOK},
+		{"optional down", []healthProbe{{name: "database", critical: true, check: up}, {name: "ai", check: down}}, HealthDegraded},
+		{"critical down", []healthProbe{{name: "database", critical: true, check: down}, {name: "ai", check: down}}, HealthUnavailable},
+	}
+	for _, tt := range tests {
+		s := &HealthService{probes: tt.probes}
+		got := s.Ready(context.Background())
+		if got.Status != tt.want || len(got.Checks) != len(tt.probes) {
+			t.Errorf("%s: %+v, want %s", tt.name, got, tt.want)
+		}
+		for _, p := range tt.probes {
+			if check := got.Checks[p.name]; check.Critical != p.critical {
+				t.Errorf("%s: %s critical = %v", tt.name, p.name, check.Critical)
+			}
+		}
+	}
+}
+
+func TestHealthReadyCachesResults(t *testing.T) {
+	calls := 0
+	s := &HealthService{probes: []healthProbe{{name: "ai", check: func(context.Context) error {
+		calls++
+		return nil
+	}}}}
+	s.Ready(context.Background())
+	s.Ready(context.Background())
+	if calls != 1 {
+		t.Fatalf("probe ran %d times, want 1 within the cache TTL", calls)
+	}
+}
+
+func TestHealthReadyWhileDraining(t *testing.T) {
+	calls := 0
+	s := &HealthService{probes: []healthProbe{{name: "database", critical: true, check: func(context.Context) error {
+		calls++
+		return nil
+	}}}}
+	if got := s.Ready(context.Background()); got.Status != HealthOK {
+		t.Fatalf("status = %s before draining", got.Status)
+	}
+	s.Drain()
+	if got := s.Ready(context.Background()); got.Status != HealthDraining {
+		t.Errorf("status = %s, want %s even with a cached ok", got.Status, HealthDraining)
+	}
+	if calls != 1 {
+		t.Errorf("probe ran %d times, want no probing while draining", calls)
+	}
+}
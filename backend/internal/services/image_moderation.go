@@ -0,0 +1,248 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrImageRejected              = errors.New("this photo can't be analyzed because it appears to contain sensitive content")
+	ErrImageModerationUnavailable = errors.New("photo screening is unavailable, please try again shortly")
+)
+
+const (
+	openAIModerationURL   = "https://api.openai.com/v1/moderations"
+	openAIModerationModel = "omni-moderation-latest"
+)
+
+// imageModerator screens a photo and returns the categories it was flagged
+// in. image is a URL or a data: URI.
+type imageModerator interface {
+	name() string
+	modelName() string
+	flaggedCategories(ctx context.Context, image string) ([]string, error)
+}
+
+// newImageModerator returns the configured moderator, or nil when image
+// moderation is off or its provider is not configured.
+func newImageModerator(cfg *config.Config) imageModerator {
+	switch strings.ToLower(strings.TrimSpace(cfg.ImageModeration)) {
+	case "openai":
+		if strings.TrimSpace(cfg.OpenAIAPIKey) == "" {
+			return nil
+		}
+		return &openAIImageModerator{
+			apiURL: openAIModerationURL,
+			apiKey: strings.TrimSpace(cfg.OpenAIAPIKey),
+			client: &http.Client{Timeout: 10 * time.Second},
+		}
+	}
+	return nil
+}
+
+// openAIImageModerator uses the OpenAI moderation endpoint, whose omni
+// model accepts images.
+type openAIImageModerator struct {
+	apiURL string
+	apiKey string
+	client *http.Client
+}
+
+type openAIModerationResponse struct {
+	Results []struct {
+		Flagged    bool            `json:"flagged"`
+		Categories map[string]bool `json:"categories"`
+	} `json:"results"`
+}
+
+func (m *openAIImageModerator) name() string { return "openai" }
+
+func (m *openAIImageModerator) modelName() string { return openAIModerationModel }
+
+func (m *openAIImageModerator) flaggedCategories(ctx context.Context, image string) ([]string, error) {
+	payload, err := json.Marshal(map[string]any{
+		"model": openAIModerationModel,
+		"input": []map[string]any{
+			{"type": "image_url", "image_url": map[string]string{"url": image}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.apiURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("moderation request failed: status=%d", resp.StatusCode)
+	}
+
+	var result openAIModerationResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Results) == 0 {
+		return nil, errors.New("moderation returned no results")
+	}
+
+	var flagged []string
+	for category, hit := range result.Results[0].Categories {
+		if hit {
+			flagged = append(flagged, category)
+		}
+	}
+	return flagged, nil
+}
+
+// screeningImage is what photo checks are shown: inline uploads as a data:
+// URI, otherwise the image URL. Empty when there is nothing to screen.
+func screeningImage(imageURL, imageData string) string {
+	data := strings.TrimSpace(imageData)
+	if strings.HasPrefix(data, "data:") {
+		return data
+	}
+	if raw := decodeInlineBytes(data); raw != nil {
+		return "data:" + http.DetectContentType(raw) + ";base64," + base64.StdEncoding.EncodeToString(raw)
+	}
+	if strings.HasPrefix(imageURL, "http://") || strings.HasPrefix(imageURL, "https://") {
+		return imageURL
+	}
+	return ""
+}
+
+// rejectedCategories keeps the flagged categories the configuration rejects.
+func rejectedCategories(flagged []string, rejected string) []string {
+	var out []string
+	for _, category := range flagged {
+		if containsString(splitList(rejected), category) {
+			out = append(out, category)
+		}
+	}
+	return out
+}
+
+// screenImage runs image moderation on a scan photo before it reaches the
+// aura AI. A rejected photo is recorded as a violation and ErrImageRejected
+// returned. When the provider fails the scan goes ahead, unless
+// ImageModerationFailClosed is set.
+func (s *AuraService) screenImage(ctx context.Context, db *gorm.DB, userID uuid.UUID, imageURL, imageData string) error {
+	image := screeningImage(imageURL, imageData)
+	if s.moderator == nil || image == "" {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "aura.image_moderation")
+	defer span.End()
+
+	start := time.Now()
+	flagged, err := s.moderator.flaggedCategories(ctx, image)
+	recordAIInteraction(ctx, models.AIPurposeImageModeration, s.moderator.name(), s.moderator.modelName(), err, start)
+	s.usage.Record(ctx, models.AIPurposeImageModeration, s.moderator.name(), s.moderator.modelName(), aiTokenUsage{}, err, start)
+	if err != nil {
+		span.RecordError(err)
+		metrics.ImageModerationTotal.WithLabelValues("error").Inc()
+		log.Printf("image moderation (%s) for user %s: %v", s.moderator.name(), userID, err)
+		if s.cfg.ImageModerationFailClosed {
+			return ErrImageModerationUnavailable
+		}
+		return nil
+	}
+
+	categories := rejectedCategories(flagged, s.cfg.ImageModerationCategories)
+	if len(categories) == 0 {
+		metrics.ImageModerationTotal.WithLabelValues("passed").Inc()
+		return nil
+	}
+
+	metrics.ImageModerationTotal.WithLabelValues("rejected").Inc()
+	log.Printf("image moderation: rejected photo from user %s (%s)", userID, strings.Join(categories, ", "))
+	if err := recordImageViolation(db, s.cfg, userID, s.moderator.name(), categories, imageURL); err != nil {
+		log.Printf("image moderation: recording violation for user %s: %v", userID, err)
+	}
+	return ErrImageRejected
+}
+
+// recordImageViolation stores a rejected photo and, once the user has
+// ImageViolationEscalation violations within ImageViolationWindow, files
+// them in the moderation queue as an escalated case about the user.
+func recordImageViolation(db *gorm.DB, cfg *config.Config, userID uuid.UUID, provider string, categories []string, imageRef string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		violation := models.ImageViolation{
+			UserID:     userID,
+			Provider:   provider,
+			Categories: categories,
+			ImageRef:   imageRef,
+		}
+		if err := tx.Create(&violation).Error; err != nil {
+			return err
+		}
+
+		if cfg.ImageViolationEscalation <= 0 {
+			return nil
+		}
+		var recent int64
+		if err := tx.Model(&models.ImageViolation{}).
+			Where("user_id = ? AND created_at > ?", userID, time.Now().Add(-cfg.ImageViolationWindow)).
+			Count(&recent).Error; err != nil {
+			return err
+		}
+		if recent < int64(cfg.ImageViolationEscalation) {
+			return nil
+		}
+
+		mc, err := openCase(tx, "user", userID.String())
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		updates := map[string]any{
+			"last_reported_at": now,
+			"admin_note":       fmt.Sprintf("%d photos rejected by image moderation in the last %s", recent, cfg.ImageViolationWindow),
+		}
+		if mc.Status == models.CaseOpen {
+			updates["status"] = models.CaseEscalated
+			updates["escalated_at"] = now
+		}
+		if err := tx.Model(mc).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&violation).Update("case_id", mc.ID).Error; err != nil {
+			return err
+		}
+		if mc.Status != models.CaseOpen {
+			return nil
+		}
+		return writeAudit(tx, nil, "case.escalated", "moderation_case", mc.ID, map[string]any{
+			"user_id": userID, "reason": "image_violations", "violations": recent,
+		})
+	})
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/google/uuid"
+)
+
+func TestOpenAIImageModeratorFlaggedCategories(t *testing.T) {
+	var sent struct {
+		Model string `json:"model"`
+		Input []struct {
+			Type     string `json:"type"`
+			ImageURL struct {
+				URL string `json:"url"`
+			} `json:"image_url"`
+		} `json:"input"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("authorization = %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Write([]byte(`{"results":[{"flagged":true,"categories":{"sexual":false,"violence":true,"violence/graphic":true,"harassment":false}}]}`))
+	}))
+	defer server.Close()
+
+	m := &openAIImageModerator{apiURL: server.URL, apiKey: "test-key", client: server.Client()}
+	flagged, err := m.flaggedCategories(context.Background(), "https://cdn.example.com/a.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(flagged)
+	if strings.Join(flagged, ",") != "violence,violence/graphic" {
+		t.Errorf("flagged = %v", flagged)
+	}
+	if len(sent.Input) != 1 || sent.Input[0].Type != "image_url" || sent.Input[0].ImageURL.URL != "https://cdn.example.com/a.jpg" {
+		t.Errorf("request input = %+v", sent.Input)
+	}
+}
+
+func TestRejectedCategories(t *testing.T) {
+	got := rejectedCategories([]string{"violence", "harassment", "sexual/minors"}, "sexual, sexual/minors,violence")
+	if strings.Join(got, ",") != "violence,sexual/minors" {
+		t.Errorf("rejected = %v", got)
+	}
+	if got := rejectedCategories([]string{"harassment"}, "sexual,violence"); len(got) != 0 {
+		t.Errorf("rejected = %v, want none", got)
+	}
+}
+
+func TestScreeningImage(t *testing.T) {
+	if got := screeningImage("https://cdn.example.com/a.jpg", ""); got != "https://cdn.example.com/a.jpg" {
+		t.Errorf("url image = %q", got)
+	}
+	if got := screeningImage("base64_upload", "data:image/png;base64,AAAA"); got != "data:image/png;base64,AAAA" {
+		t.Errorf("data uri = %q", got)
+	}
+	if got := screeningImage("base64_upload", ""); got != "" {
+		t.Errorf("nothing to screen = %q", got)
+	}
+}
+
+type stubModerator struct {
+	flagged []string
+	err     error
+}
+
+func (m stubModerator) name() string { return "stub" }
+
+func (m stubModerator) modelName() string { return "stub-model" }
+
+func (m stubModerator) flaggedCategories(context.Context, string) ([]string, error) {
+	return m.flagged, m.err
+}
+
+func TestScreenImageProviderFailure(t *testing.T) {
+	cfg := &config.Config{ImageModerationCategories: "sexual,violence"}
+	s := &AuraService{cfg: cfg, moderator: stubModerator{err: errors.New("timeout")}}
+	url := "https://cdn.example.com/a.jpg"
+
+	if err := s.screenImage(context.Background(), nil, uuid.New(), url, ""); err != nil {
+		t.Errorf("fail open: err = %v", err)
+	}
+	cfg.ImageModerationFailClosed = true
+	if err := s.screenImage(context.Background(), nil, uuid.New(), url, ""); !errors.Is(err, ErrImageModerationUnavailable) {
+		t.Errorf("fail closed: err = %v, want ErrImageModerationUnavailable", err)
+	}
+
+	s.moderator = stubModerator{flagged: []string{"harassment"}}
+	if err := s.screenImage(context.Background(), nil, uuid.New(), url, ""); err != nil {
+		t.Errorf("unrejected category: err = %v", err)
+	}
+}
+
+func TestNewImageModeratorOff(t *testing.T) {
+	if m := newImageModerator(&config.Config{ImageModeration: "off", OpenAIAPIKey: "k"}); m != nil {
+		t.Error("moderator built with IMAGE_MODERATION=off")
+	}
+	if m := newImageModerator(&config.Config{ImageModeration: "openai"}); m != nil {
+		t.Error("moderator built without OPENAI_API_KEY")
+	}
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// themeWindow caps how many of the latest readings themes come from.
+	themeWindow = 100
+	// themeBatchSize caps analyses per worker tick.
+	themeBatchSize = 100
+)
+
+// InsightWeekly is the reminder kind of the weekly insight notification.
+const InsightWeekly = "weekly_insight"
+
+// InsightService finds the themes that recur across a user's readings and
+// sends the weekly insight built on them. Themes come from embedding
+// clusters when an Embedder is configured, otherwise from keyword
+// extraction. Embeddings already in the vector store are reused.
+type InsightService struct {
+	db            *gorm.DB
+	cfg           *config.Config
+	embedder      Embedder
+	vectors       *ReadingEmbeddingService
+	notifications *NotificationService
+}
+
+func NewInsightService(db *gorm.DB, cfg *config.Config, embedder Embedder, vectors *ReadingEmbeddingService, notifications *NotificationService) *InsightService {
+	return &InsightService{db: db, cfg: cfg, embedder: embedder, vectors: vectors, notifications: notifications}
+}
+
+// Themes returns the user's recurring themes, most frequent first,
+// reanalyzing their readings first if they changed since the last run.
+func (s *InsightService) Themes(ctx context.Context, userID uuid.UUID) (*dto.RecurringThemesResponse, error) {
+	ctx, span := tracer.Start(ctx, "InsightService.Themes")
+	defer span.End()
+
+	analysis, err := s.currentAnalysis(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	themes := []models.RecurringTheme{}
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("rank").Find(&themes).Error; err != nil {
+		return nil, err
+	}
+	return &dto.RecurringThemesResponse{
+		Themes:     themes,
+		Readings:   analysis.Readings,
+		Method:     analysis.Method,
+		AnalyzedAt: analysis.AnalyzedAt,
+	}, nil
+}
+
+// readingsFingerprint is what an analysis is current for: the number of
+// personal readings and the newest one's time.
+func (s *InsightService) readingsFingerprint(ctx context.Context, userID uuid.UUID) (int64, *time.Time, error) {
+	var row struct {
+		Count  int64
+		Latest *time.Time
+	}
+	err := s.db.WithContext(ctx).Model(&models.AuraReading{}).Scopes(personalReadings).
+		Select("COUNT(*) AS count, MAX(created_at) AS latest").
+		Where("user_id = ? AND NOT synthetic", userID).Scan(&row).Error
+	return row.Count, row.Latest, err
+}
+
+func (s *InsightService) currentAnalysis(ctx context.Context, userID uuid.UUID) (*models.ThemeAnalysis, error) {
+	count, latest, err := s.readingsFingerprint(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var analysis models.ThemeAnalysis
+	err = s.db.WithContext(ctx).Where("user_id = ?", userID).First(&analysis).Error
+	if err == nil && analysis.Readings == count && sameTime(analysis.LatestReadingAt, latest) {
+		return &analysis, nil
+	}
+	return s.Analyze(ctx, userID)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// Analyze recomputes the user's recurring themes from their latest
+// readings and replaces the stored ones.
+func (s *InsightService) Analyze(ctx context.Context, userID uuid.UUID) (*models.ThemeAnalysis, error) {
+	count, latest, err := s.readingsFingerprint(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var readings []models.AuraReading
+	if err := s.db.WithContext(ctx).Scopes(personalReadings).Select("id", "personality", "daily_advice", "created_at").
+		Where("user_id = ? AND NOT synthetic", userID).
+		Order("created_at DESC").Limit(themeWindow).Find(&readings).Error; err != nil {
+		return nil, err
+	}
+	// Oldest first, so embedding clusters grow in the order readings came.
+	docs := make([]themeDoc, len(readings))
+	for i, r := range readings {
+		docs[len(readings)-1-i] = themeDoc{ReadingID: r.ID, Text: r.Personality + "\n" + r.DailyAdvice, CreatedAt: r.CreatedAt}
+	}
+
+	detected, method := s.detect(ctx, docs)
+	now := time.Now()
+	analysis := &models.ThemeAnalysis{UserID: userID, Readings: count, LatestReadingAt: latest, Method: method, AnalyzedAt: now}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecurringTheme{}).Error; err != nil {
+			return err
+		}
+		for rank, d := range detected {
+			theme := models.RecurringTheme{
+				UserID:    userID,
+				Label:     d.Label,
+				Keywords:  d.Keywords,
+				Readings:  len(d.Docs),
+				Share:     float64(len(d.Docs)) / float64(len(docs)),
+				FirstSeen: docs[d.Docs[0]].CreatedAt,
+				LastSeen:  docs[d.Docs[len(d.Docs)-1]].CreatedAt,
+				Rank:      rank,
+			}
+			if err := tx.Create(&theme).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(analysis).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+// detect clusters by stored or fresh embeddings when possible and falls
+// back to keywords when no embedder is configured or it fails.
+func (s *InsightService) detect(ctx context.Context, docs []themeDoc) ([]detectedTheme, string) {
+	if vectors, ok := s.storedVectors(ctx, docs); ok {
+		return embeddingThemes(docs, vectors), models.ThemeMethodEmbeddings
+	}
+	if s.embedder != nil && len(docs) >= minThemeReadings {
+		texts := make([]string, len(docs))
+		for i, d := range docs {
+			texts[i] = truncateRunes(d.Text, 2000)
+		}
+		vectors, err := s.embedder.Embed(ctx, texts)
+		if err == nil {
+			return embeddingThemes(docs, vectors), models.ThemeMethodEmbeddings
+		}
+		log.Printf("insights: %s embeddings failed, using keywords: %v", s.embedder.Name(), err)
+	}
+	return keywordThemes(docs), models.ThemeMethodKeywords
+}
+
+// storedVectors returns the docs' embeddings from the vector store when it
+// has all of them.
+func (s *InsightService) storedVectors(ctx context.Context, docs []themeDoc) ([][]float64, bool) {
+	if !s.vectors.Enabled() || len(docs) < minThemeReadings {
+		return nil, false
+	}
+	ids := make([]uuid.UUID, len(docs))
+	for i, d := range docs {
+		ids[i] = d.ReadingID
+	}
+	stored, err := s.vectors.Vectors(ctx, ids)
+	if err != nil {
+		log.Printf("insights: loading stored embeddings: %v", err)
+		return nil, false
+	}
+	vectors := make([][]float64, len(docs))
+	for i, d := range docs {
+		if vectors[i] = stored[d.ReadingID]; vectors[i] == nil {
+			return nil, false
+		}
+	}
+	return vectors, true
+}
+
+// InsightJob is the scheduled job that reanalyzes users whose readings
+// changed and sends due weekly insights.
+func (s *InsightService) InsightJob(ctx context.Context) error {
+	n, analyzeErr := s.AnalyzeDue(ctx)
+	if n > 0 {
+		log.Printf("insight job: analyzed %d users", n)
+	}
+	sent, err := s.SendWeeklyDue(ctx, time.Now())
+	if sent > 0 {
+		log.Printf("insight job: sent %d weekly insights", sent)
+	}
+	return errors.Join(analyzeErr, err)
+}
+
+// AnalyzeDue reanalyzes up to themeBatchSize users whose readings changed
+// since their last analysis.
+func (s *InsightService) AnalyzeDue(ctx context.Context) (int, error) {
+	var userIDs []uuid.UUID
+	if err := s.db.WithContext(ctx).Raw(`SELECT r.user_id FROM (
+			SELECT user_id, COUNT(*) AS readings, MAX(created_at) AS latest FROM aura_readings
+			WHERE deleted_at IS NULL AND NOT synthetic AND group_reading_id IS NULL GROUP BY user_id
+		) r LEFT JOIN theme_analyses a ON a.user_id = r.user_id
+		WHERE a.user_id IS NULL OR a.readings <> r.readings OR a.latest_reading_at IS DISTINCT FROM r.latest
+		LIMIT ?`, themeBatchSize).Scan(&userIDs).Error; err != nil {
+		return 0, err
+	}
+
+	analyzed := 0
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := s.Analyze(ctx, userID); err != nil {
+			log.Printf("insights for %s: %v", userID, err)
+			continue
+		}
+		analyzed++
+	}
+	return analyzed, nil
+}
+
+// SendWeeklyDue sends the weekly insight on Sunday at the daily reminder
+// hour to every user with a recurring theme, once per week.
+func (s *InsightService) SendWeeklyDue(ctx context.Context, now time.Time) (int, error) {
+	var users []models.User
+	if err := s.db.WithContext(ctx).Select("id", "timezone").
+		Where("id IN (SELECT user_id FROM recurring_themes)").
+		Find(&users).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, user := range users {
+		loc, err := loadTimezone(user.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		local := now.In(loc)
+		if local.Weekday() != time.Sunday || !reminderDue(local, s.cfg.ReminderDailyHour) {
+			continue
+		}
+		msg, ok := s.weeklyInsight(ctx, user.ID, now)
+		if !ok || !claimReminder(s.db, user.ID, InsightWeekly, local.Format("2006-01-02")) {
+			continue
+		}
+		if _, err := s.notifications.Notify(user.ID, msg); err != nil {
+			log.Printf("insights: weekly insight for %s: %v", user.ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// weeklyInsight sums up the user's week around their top recurring theme.
+func (s *InsightService) weeklyInsight(ctx context.Context, userID uuid.UUID, now time.Time) (NotificationMessage, bool) {
+	var top models.RecurringTheme
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("rank").First(&top).Error; err != nil {
+		return NotificationMessage{}, false
+	}
+	var analysis models.ThemeAnalysis
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).First(&analysis).Error; err != nil {
+		return NotificationMessage{}, false
+	}
+	var thisWeek int64
+	s.db.WithContext(ctx).Model(&models.AuraReading{}).Scopes(personalReadings).
+		Where("user_id = ? AND NOT synthetic AND created_at >= ?", userID, now.AddDate(0, 0, -7)).
+		Count(&thisWeek)
+
+	return NotificationMessage{
+		Category: CategoryInsights,
+		Title:    "Your week in auras 🔮",
+		Body:     weeklyInsightBody(top, analysis.Readings, thisWeek, now),
+		Data:     map[string]string{"event": InsightWeekly, "theme": top.Label},
+	}, true
+}
+
+func weeklyInsightBody(top models.RecurringTheme, total, thisWeek int64, now time.Time) string {
+	theme := fmt.Sprintf("“%s” keeps coming up: it runs through %d of your %d readings.", top.Label, top.Readings, total)
+	switch {
+	case thisWeek == 0:
+		return theme + " Scan this week to see if it continues."
+	case top.LastSeen.After(now.AddDate(0, 0, -7)):
+		return fmt.Sprintf("You scanned %d times this week. %s It showed up again this week.", thisWeek, theme)
+	default:
+		return fmt.Sprintf("You scanned %d times this week. %s It stayed quiet this week.", thisWeek, theme)
+	}
+}
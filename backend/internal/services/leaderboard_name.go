@@ -0,0 +1,224 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidDisplayName   = errors.New("display name must be 3-20 characters: letters, numbers or single spaces")
+	ErrDisplayNameProfanity = errors.New("this display name isn't allowed")
+	ErrDisplayNameLocked    = errors.New("your leaderboard name was set by a moderator and can't be changed")
+	ErrDisplayNameTaken     = errors.New("no free leaderboard name is left for this display name; try another")
+)
+
+// leaderboardNameAttempts bounds the random discriminators tried before
+// giving up on a base name.
+const leaderboardNameAttempts = 20
+
+var displayNamePattern = regexp.MustCompile(`^[\p{L}\p{N}]+( [\p{L}\p{N}]+)*$`)
+
+// Anonymous names are "<color> <animal> #NNNN".
+var (
+	anonymousColors = []string{
+		"Blue", "Red", "Green", "Gold", "Silver", "Violet", "Amber", "Coral",
+		"Indigo", "Jade", "Ruby", "Teal", "Ivory", "Crimson", "Azure", "Copper",
+	}
+	anonymousAnimals = []string{
+		"Fox", "Owl", "Wolf", "Otter", "Lynx", "Hawk", "Panda", "Heron",
+		"Tiger", "Raven", "Koala", "Falcon", "Badger", "Dolphin", "Crane", "Deer",
+	}
+)
+
+// normalizeDisplayName trims and collapses runs of whitespace.
+func normalizeDisplayName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// validateDisplayName checks a normalized display name with the same
+// reserved-word and profanity rules as handles. Spaces are folded away so
+// "f u c k" is caught too.
+func validateDisplayName(name string) error {
+	if n := len([]rune(name)); n < 3 || n > 20 || !displayNamePattern.MatchString(name) {
+		return ErrInvalidDisplayName
+	}
+	lower := strings.ToLower(name)
+	folded := leetReplacer.Replace(strings.ReplaceAll(lower, " ", ""))
+	if containsBlockedTerm(folded) {
+		return ErrDisplayNameProfanity
+	}
+	if _, ok := reservedHandles[folded]; ok {
+		return ErrDisplayNameProfanity
+	}
+	if strings.HasPrefix(folded, "aurasnap") || strings.HasPrefix(folded, "admin") || strings.HasPrefix(folded, "moderator") {
+		return ErrDisplayNameProfanity
+	}
+	return nil
+}
+
+// anonymousBase derives a stable "<color> <animal>" from the user ID, so a
+// user keeps the same anonymous name across opt-outs.
+func anonymousBase(userID uuid.UUID) string {
+	sum := sha256.Sum256(userID[:])
+	n := binary.BigEndian.Uint32(sum[:4])
+	return anonymousColors[n%uint32(len(anonymousColors))] + " " +
+		anonymousAnimals[(n>>8)%uint32(len(anonymousAnimals))]
+}
+
+// leaderboardBase splits "<base> #NNNN" and returns the base.
+func leaderboardBase(name string) string {
+	if i := strings.LastIndex(name, " #"); i > 0 {
+		return name[:i]
+	}
+	return name
+}
+
+func formatLeaderboardName(base string, discriminator int) string {
+	return fmt.Sprintf("%s #%04d", base, discriminator)
+}
+
+// assignLeaderboardName stores base with a free random discriminator. The
+// user's current name is kept if its base is unchanged.
+func assignLeaderboardName(tx *gorm.DB, user *models.User, base string, locked bool) error {
+	if user.LeaderboardName != nil && leaderboardBase(*user.LeaderboardName) == base {
+		if user.LeaderboardNameLocked == locked {
+			return nil
+		}
+		user.LeaderboardNameLocked = locked
+		return tx.Model(user).Update("leaderboard_name_locked", locked).Error
+	}
+
+	for i := 0; i < leaderboardNameAttempts; i++ {
+		name := formatLeaderboardName(base, rand.IntN(10000))
+		var count int64
+		tx.Model(&models.User{}).Unscoped().Where("leaderboard_name = ?", name).Count(&count)
+		if count > 0 {
+			continue
+		}
+		err := tx.Model(user).Updates(map[string]interface{}{
+			"leaderboard_name":        name,
+			"leaderboard_name_locked": locked,
+		}).Error
+		if err != nil {
+			// Lost a race for the same name on the unique index.
+			if strings.Contains(err.Error(), "duplicate key") {
+				continue
+			}
+			return err
+		}
+		user.LeaderboardName = &name
+		user.LeaderboardNameLocked = locked
+		return nil
+	}
+	return ErrDisplayNameTaken
+}
+
+func leaderboardResponse(user *models.User) *dto.LeaderboardSettingsResponse {
+	resp := &dto.LeaderboardSettingsResponse{
+		OptIn:  user.LeaderboardOptIn,
+		Locked: user.LeaderboardNameLocked,
+	}
+	if user.LeaderboardName != nil {
+		resp.DisplayName = *user.LeaderboardName
+		resp.Anonymous = leaderboardBase(*user.LeaderboardName) == anonymousBase(user.ID)
+	}
+	return resp
+}
+
+// UpdateLeaderboard opts the user in or out of public leaderboards and
+// sets their display name. An empty name switches to the anonymous one.
+// Opting in without ever choosing a name also gets the anonymous one.
+func (s *UserService) UpdateLeaderboard(userID uuid.UUID, req *dto.LeaderboardSettingsRequest) (*dto.LeaderboardSettingsResponse, error) {
+	var base string
+	if req.DisplayName != nil {
+		base = normalizeDisplayName(*req.DisplayName)
+		if base != "" {
+			if err := validateDisplayName(base); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var user models.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+			return ErrUserNotFound
+		}
+		if req.DisplayName != nil {
+			if user.LeaderboardNameLocked {
+				return ErrDisplayNameLocked
+			}
+			if base == "" {
+				base = anonymousBase(user.ID)
+			}
+			if err := assignLeaderboardName(tx, &user, base, false); err != nil {
+				return err
+			}
+		}
+		if req.OptIn != nil {
+			if *req.OptIn && user.LeaderboardName == nil {
+				if err := assignLeaderboardName(tx, &user, anonymousBase(user.ID), false); err != nil {
+					return err
+				}
+			}
+			user.LeaderboardOptIn = *req.OptIn
+			return tx.Model(&user).Update("leaderboard_opt_in", user.LeaderboardOptIn).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leaderboardResponse(&user), nil
+}
+
+// OverrideLeaderboardName replaces a user's leaderboard name. The name is
+// screened like a user's own, and locked against changes by the user
+// unless req.Lock is false.
+func (s *AdminUserService) OverrideLeaderboardName(adminID, userID uuid.UUID, req *dto.LeaderboardNameOverride) (*dto.LeaderboardSettingsResponse, error) {
+	base := normalizeDisplayName(req.DisplayName)
+	if base != "" {
+		if err := validateDisplayName(base); err != nil {
+			return nil, err
+		}
+	}
+	lock := req.Lock == nil || *req.Lock
+
+	var user models.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", userID).Error; err != nil {
+			return ErrUserNotFound
+		}
+		var previous string
+		if user.LeaderboardName != nil {
+			previous = *user.LeaderboardName
+		}
+		if base == "" {
+			base = anonymousBase(user.ID)
+		}
+		if err := assignLeaderboardName(tx, &user, base, lock); err != nil {
+			return err
+		}
+		return writeAudit(tx, &adminID, "user.leaderboard_name_overridden", auditTargetUser, userID, map[string]any{
+			"previous": previous,
+			"name":     *user.LeaderboardName,
+			"locked":   lock,
+			"reason":   truncateRunes(strings.TrimSpace(req.Reason), 500),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leaderboardResponse(&user), nil
+}
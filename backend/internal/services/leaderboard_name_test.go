@@ -0,0 +1,47 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateDisplayName(t *testing.T) {
+	cases := []struct {
+		name string
+		want error
+	}{
+		{"Aura Queen", nil},
+		{"Zeynep 22", nil},
+		{"ab", ErrInvalidDisplayName},
+		{"ada_x", ErrInvalidDisplayName},
+		{"Ada #0001", ErrInvalidDisplayName},
+		{"a name far too long to show", ErrInvalidDisplayName},
+		{"Sh1t Happens", ErrDisplayNameProfanity},
+		{"F U C K", ErrDisplayNameProfanity},
+		{"Admin Team", ErrDisplayNameProfanity},
+		{"Support", ErrDisplayNameProfanity},
+	}
+	for _, tc := range cases {
+		if err := validateDisplayName(normalizeDisplayName(tc.name)); !errors.Is(err, tc.want) {
+			t.Errorf("validateDisplayName(%q) = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestAnonymousLeaderboardName(t *testing.T) {
+	id := uuid.New()
+	base := anonymousBase(id)
+	if base != anonymousBase(id) {
+		t.Fatalf("anonymousBase is not stable for %s", id)
+	}
+	name := formatLeaderboardName(base, 42)
+	if !regexp.MustCompile(`^[A-Z][a-z]+ [A-Z][a-z]+ #0042$`).MatchString(name) {
+		t.Errorf("formatLeaderboardName = %q", name)
+	}
+	if got := leaderboardBase(name); got != base {
+		t.Errorf("leaderboardBase(%q) = %q, want %q", name, got, base)
+	}
+}
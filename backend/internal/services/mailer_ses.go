@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/emails"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// sesMailer sends through the SES v2 API. Credentials come from the default
+// AWS chain (env vars, shared config, or the instance/task role).
+type sesMailer struct {
+	client           *sesv2.Client
+	from             string
+	configurationSet string
+}
+
+func newSESMailer(cfg *config.Config) (*sesMailer, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if cfg.SESRegion != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.SESRegion))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("load AWS config for SES: %w", err)
+	}
+	return &sesMailer{
+		client:           sesv2.NewFromConfig(awsCfg),
+		from:             cfg.MailFrom,
+		configurationSet: cfg.SESConfigurationSet,
+	}, nil
+}
+
+func (m *sesMailer) Send(to string, msg *emails.Message) error {
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("invalid MAIL_FROM: %w", err)
+	}
+	rcpt, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	// Send the same MIME body as SMTP so both drivers render identically.
+	body, err := buildMIMEMessage(from, rcpt, msg)
+	if err != nil {
+		return err
+	}
+
+	input := &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(from.Address),
+		Destination:      &types.Destination{ToAddresses: []string{rcpt.Address}},
+		Content:          &types.EmailContent{Raw: &types.RawMessage{Data: body}},
+	}
+	if m.configurationSet != "" {
+		input.ConfigurationSetName = aws.String(m.configurationSet)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if _, err := m.client.SendEmail(ctx, input); err != nil {
+		return fmt.Errorf("ses send: %w", err)
+	}
+	return nil
+}
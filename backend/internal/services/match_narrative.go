@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrPremiumRequired = errors.New("this feature requires AuraSnap Premium")
+
+// A new reading only invalidates a cached narrative when it moves this far
+// from the one the narrative was written from (or changes color).
+const (
+	narrativeEnergyDelta = 20
+	narrativeMoodDelta   = 3
+	maxNarrativeRunes    = 1200
+)
+
+const narrativeSystemPrompt = `You are the voice of AuraSnap, a playful aura personality app. You write a short, warm narrative that explains a compatibility result between two friends.
+
+Write ONE paragraph of 80-120 words in plain text (no markdown, no lists, no JSON). Address Person A as "you" and Person B as "your friend". Explain why the score is what it is by weaving together their aura colors, energy levels, mood scores and the synergy, tension and advice already computed. Keep it friendly, specific and encouraging; never make medical, financial or relationship-ending claims.`
+
+// Narrative returns the match with the premium LLM narrative, generating it
+// on first use and again whenever either user's latest reading has changed
+// significantly since the cached narrative was written.
+func (s *AuraMatchService) Narrative(ctx context.Context, userID, friendID uuid.UUID) (*dto.AuraMatchResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuraMatchService.Narrative")
+	defer span.End()
+	db := s.db.WithContext(ctx)
+
+	if !entitlementsFor(db, s.cfg, userID).MatchNarrative {
+		return nil, ErrPremiumRequired
+	}
+
+	var match models.AuraMatch
+	if err := db.Scopes(activeUsersOnly("friend_id")).Where("user_id = ? AND friend_id = ?", userID, friendID).
+		Order("created_at DESC").First(&match).Error; err != nil {
+		return nil, err
+	}
+
+	var matchUserAura, matchFriendAura models.AuraReading
+	db.Unscoped().First(&matchUserAura, "id = ?", match.UserAuraID)
+	db.Unscoped().First(&matchFriendAura, "id = ?", match.FriendAuraID)
+
+	userAura := latestReadingOr(db, userID, matchUserAura)
+	friendAura := latestReadingOr(db, friendID, matchFriendAura)
+
+	if narrativeStale(db, &match, userAura, friendAura) {
+		narrative, err := s.generateNarrative(ctx, &match, userAura, friendAura)
+		if err != nil {
+			// Keep serving a stale narrative over the template one.
+			log.Printf("match narrative generation failed: %v", err)
+			if match.Narrative == "" {
+				match.Narrative = fallbackNarrative(&match, userAura, friendAura)
+			}
+		} else {
+			now := time.Now()
+			match.Narrative = narrative
+			match.NarrativeGeneratedAt = &now
+			updates := map[string]interface{}{
+				"narrative":                narrative,
+				"narrative_user_aura_id":   userAura.ID,
+				"narrative_friend_aura_id": friendAura.ID,
+				"narrative_generated_at":   now,
+			}
+			if err := db.Model(&match).Updates(updates).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	resp := matchResponse(&match, matchUserAura.AuraColor, matchFriendAura.AuraColor, true)
+	return &resp, nil
+}
+
+// latestReadingOr returns the user's most recent reading, or fallback when
+// they have none left.
+func latestReadingOr(db *gorm.DB, userID uuid.UUID, fallback models.AuraReading) models.AuraReading {
+	var reading models.AuraReading
+	if err := db.Where("user_id = ?", userID).Order("created_at DESC").First(&reading).Error; err != nil {
+		return fallback
+	}
+	return reading
+}
+
+// narrativeStale reports whether the cached narrative must be (re)written.
+func narrativeStale(db *gorm.DB, match *models.AuraMatch, userAura, friendAura models.AuraReading) bool {
+	if match.Narrative == "" || match.NarrativeUserAuraID == nil || match.NarrativeFriendAuraID == nil {
+		return true
+	}
+	return readingMovedSince(db, *match.NarrativeUserAuraID, userAura) ||
+		readingMovedSince(db, *match.NarrativeFriendAuraID, friendAura)
+}
+
+func readingMovedSince(db *gorm.DB, baseID uuid.UUID, latest models.AuraReading) bool {
+	if baseID == latest.ID {
+		return false
+	}
+	var base models.AuraReading
+	if err := db.Unscoped().First(&base, "id = ?", baseID).Error; err != nil {
+		return true
+	}
+	return readingChangedSignificantly(base, latest)
+}
+
+// readingChangedSignificantly treats a new primary or secondary color, or a
+// large swing in energy or mood, as a change worth a new narrative.
+func readingChangedSignificantly(old, latest models.AuraReading) bool {
+	if !strings.EqualFold(old.AuraColor, latest.AuraColor) {
+		return true
+	}
+	if !strings.EqualFold(derefString(old.SecondaryColor), derefString(latest.SecondaryColor)) {
+		return true
+	}
+	return absInt(old.EnergyLevel-latest.EnergyLevel) >= narrativeEnergyDelta ||
+		absInt(old.MoodScore-latest.MoodScore) >= narrativeMoodDelta
+}
+
+func (s *AuraMatchService) generateNarrative(ctx context.Context, match *models.AuraMatch, userAura, friendAura models.AuraReading) (string, error) {
+	if s.cfg.OpenAIAPIKey == "" {
+		return "", errors.New("OpenAI API key not configured")
+	}
+
+	userPrompt := fmt.Sprintf(`Compatibility score: %d/100
+Synergy: %s
+Tension: %s
+Advice: %s
+
+Person A (you): %s aura, energy %d/100, mood %d/10
+Personality: %s
+
+Person B (your friend): %s aura, energy %d/100, mood %d/10
+Personality: %s`,
+		match.CompatibilityScore,
+		wrapUntrusted("synergy", match.Synergy, 500),
+		wrapUntrusted("tension", match.Tension, 300),
+		wrapUntrusted("advice", match.Advice, 300),
+		userAura.AuraColor, userAura.EnergyLevel, userAura.MoodScore,
+		wrapUntrusted("personality", userAura.Personality, 500),
+		friendAura.AuraColor, friendAura.EnergyLevel, friendAura.MoodScore,
+		wrapUntrusted("personality", friendAura.Personality, 500),
+	)
+
+	content, err := s.chatCompletion(ctx, models.AIPurposeMatchNarrative, "openai_match_narrative", openAIRequest{
+		Model: "gpt-4o-mini",
+		Messages: []openAIMessage{
+			{Role: "system", Content: narrativeSystemPrompt + "\n\n" + untrustedInputInstruction},
+			{Role: "user", Content: userPrompt},
+		},
+		MaxTokens:   250,
+		Temperature: 0.8,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	narrative := strings.TrimSpace(content)
+	if narrative == "" {
+		return "", errors.New("AI returned empty narrative")
+	}
+	return truncateRunes(narrative, maxNarrativeRunes), nil
+}
+
+// fallbackNarrative stitches the stored breakdown into a paragraph when the
+// LLM is unavailable. It is returned but not cached, so the next request
+// retries the LLM.
+func fallbackNarrative(match *models.AuraMatch, userAura, friendAura models.AuraReading) string {
+	return fmt.Sprintf("Your %s aura and your friend's %s aura land at %d%% compatibility. %s %s %s",
+		userAura.AuraColor, friendAura.AuraColor, match.CompatibilityScore,
+		match.Synergy, match.Tension, match.Advice)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
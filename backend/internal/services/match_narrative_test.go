@@ -0,0 +1,31 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestReadingChangedSignificantly(t *testing.T) {
+	violet := "violet"
+	base := models.AuraReading{AuraColor: "blue", EnergyLevel: 60, MoodScore: 6}
+
+	cases := []struct {
+		name   string
+		latest models.AuraReading
+		want   bool
+	}{
+		{"same values", base, false},
+		{"small energy drift", models.AuraReading{AuraColor: "blue", EnergyLevel: 75, MoodScore: 7}, false},
+		{"color case only", models.AuraReading{AuraColor: "Blue", EnergyLevel: 60, MoodScore: 6}, false},
+		{"new color", models.AuraReading{AuraColor: "green", EnergyLevel: 60, MoodScore: 6}, true},
+		{"new secondary color", models.AuraReading{AuraColor: "blue", SecondaryColor: &violet, EnergyLevel: 60, MoodScore: 6}, true},
+		{"energy swing", models.AuraReading{AuraColor: "blue", EnergyLevel: 35, MoodScore: 6}, true},
+		{"mood swing", models.AuraReading{AuraColor: "blue", EnergyLevel: 60, MoodScore: 9}, true},
+	}
+	for _, tc := range cases {
+		if got := readingChangedSignificantly(base, tc.latest); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMemoryNotFound     = errors.New("memory not found")
+	ErrMemoryLimitReached = errors.New("memory limit reached")
+	ErrInvalidMemory      = errors.New("invalid memory")
+)
+
+const (
+	maxMemoriesPerUser = 20
+	maxMemoryRunes     = 280
+)
+
+var memoryCategories = map[string]bool{
+	"job": true, "goals": true, "interests": true, "relationships": true, "wellbeing": true, "other": true,
+}
+
+type MemoryService struct {
+	db *gorm.DB
+}
+
+func NewMemoryService(db *gorm.DB) *MemoryService {
+	return &MemoryService{db: db}
+}
+
+func (s *MemoryService) List(userID uuid.UUID) (*dto.MemoryListResponse, error) {
+	enabled, err := s.enabled(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	memories, err := s.memories(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MemoryListResponse{Enabled: enabled, Data: memories}, nil
+}
+
+func (s *MemoryService) SetEnabled(userID uuid.UUID, enabled bool) error {
+	result := s.db.Model(&models.User{}).Where("id = ?", userID).Update("ai_memory_enabled", enabled)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (s *MemoryService) Create(userID uuid.UUID, req *dto.MemoryRequest) (*models.UserMemory, error) {
+	category, content, err := validateMemory(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := s.db.Model(&models.UserMemory{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count >= maxMemoriesPerUser {
+		return nil, ErrMemoryLimitReached
+	}
+
+	memory := models.UserMemory{UserID: userID, Category: category, Content: content}
+	if err := s.db.Create(&memory).Error; err != nil {
+		return nil, fmt.Errorf("failed to create memory: %w", err)
+	}
+	return &memory, nil
+}
+
+func (s *MemoryService) Update(userID, memoryID uuid.UUID, req *dto.MemoryRequest) (*models.UserMemory, error) {
+	category, content, err := validateMemory(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var memory models.UserMemory
+	if err := s.db.Where("id = ? AND user_id = ?", memoryID, userID).First(&memory).Error; err != nil {
+		return nil, ErrMemoryNotFound
+	}
+
+	memory.Category = category
+	memory.Content = content
+	if err := s.db.Save(&memory).Error; err != nil {
+		return nil, fmt.Errorf("failed to update memory: %w", err)
+	}
+	return &memory, nil
+}
+
+func (s *MemoryService) Delete(userID, memoryID uuid.UUID) error {
+	result := s.db.Where("id = ? AND user_id = ?", memoryID, userID).Delete(&models.UserMemory{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMemoryNotFound
+	}
+	return nil
+}
+
+// DeleteAll permanently erases every memory and turns personalization off.
+func (s *MemoryService) DeleteAll(userID uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserMemory{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).Update("ai_memory_enabled", false).Error
+	})
+}
+
+func (s *MemoryService) Export(userID uuid.UUID) (*dto.MemoryExport, error) {
+	list, err := s.List(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MemoryExport{
+		UserID:     userID.String(),
+		Enabled:    list.Enabled,
+		ExportedAt: time.Now().UTC(),
+		Memories:   list.Data,
+	}, nil
+}
+
+func (s *MemoryService) enabled(userID uuid.UUID) (bool, error) {
+	var user models.User
+	if err := s.db.Select("ai_memory_enabled").First(&user, "id = ?", userID).Error; err != nil {
+		return false, ErrUserNotFound
+	}
+	return user.AIMemoryEnabled, nil
+}
+
+func (s *MemoryService) memories(userID uuid.UUID) ([]models.UserMemory, error) {
+	var memories []models.UserMemory
+	err := s.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&memories).Error
+	return memories, err
+}
+
+func validateMemory(req *dto.MemoryRequest) (string, string, error) {
+	category := strings.ToLower(strings.TrimSpace(req.Category))
+	if category == "" {
+		category = "other"
+	}
+	if !memoryCategories[category] {
+		return "", "", fmt.Errorf("%w: category must be job, goals, interests, relationships, wellbeing, or other", ErrInvalidMemory)
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return "", "", fmt.Errorf("%w: content is required", ErrInvalidMemory)
+	}
+	if utf8.RuneCountInString(content) > maxMemoryRunes {
+		return "", "", fmt.Errorf("%w: content must be at most %d characters", ErrInvalidMemory, maxMemoryRunes)
+	}
+	return category, content, nil
+}
+
+// promptMemories renders an opted-in user's memories as delimited, sanitized
+// lines for prompts. Returns "" when memory is off or empty.
+func promptMemories(db *gorm.DB, userID uuid.UUID) string {
+	svc := MemoryService{db: db}
+	if enabled, err := svc.enabled(userID); err != nil || !enabled {
+		return ""
+	}
+	memories, err := svc.memories(userID)
+	if err != nil || len(memories) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(memories))
+	for _, m := range memories {
+		lines = append(lines, wrapUntrusted(m.Category, m.Content, maxMemoryRunes))
+	}
+	return strings.Join(lines, " ")
+}
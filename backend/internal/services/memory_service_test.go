@@ -0,0 +1,31 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+)
+
+func TestValidateMemory(t *testing.T) {
+	category, content, err := validateMemory(&dto.MemoryRequest{Category: " Job ", Content: "  Nurse on night shifts "})
+	if err != nil || category != "job" || content != "Nurse on night shifts" {
+		t.Fatalf("unexpected result: %q %q %v", category, content, err)
+	}
+
+	if category, _, _ := validateMemory(&dto.MemoryRequest{Content: "Learning piano"}); category != "other" {
+		t.Fatalf("expected default category other, got %q", category)
+	}
+
+	invalid := []dto.MemoryRequest{
+		{Category: "password", Content: "hunter2"},
+		{Category: "goals", Content: "   "},
+		{Category: "goals", Content: strings.Repeat("a", maxMemoryRunes+1)},
+	}
+	for _, req := range invalid {
+		if _, _, err := validateMemory(&req); !errors.Is(err, ErrInvalidMemory) {
+			t.Fatalf("expected ErrInvalidMemory for %+v, got %v", req, err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"slices"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const readingTraitsMigrationName = "reading_traits"
+
+// readingTraitsMigration moves strengths and challenges from the JSON
+// arrays on aura_readings to reading_traits rows. During double-write the
+// scan, async completion and regenerate paths call dualWriteReadingTraits;
+// from cutover on, reading reads take the traits from the table.
+type readingTraitsMigration struct{}
+
+func (readingTraitsMigration) Name() string { return readingTraitsMigrationName }
+
+func (readingTraitsMigration) Description() string {
+	return "Strengths and challenges from aura_readings JSON arrays to reading_traits rows"
+}
+
+func (readingTraitsMigration) Count(db *gorm.DB) (int64, error) {
+	var n int64
+	err := db.Model(&models.AuraReading{}).Count(&n).Error
+	return n, err
+}
+
+func (readingTraitsMigration) Backfill(tx *gorm.DB, cursor string, limit int) (string, int, error) {
+	readings, err := traitSourceBatch(tx, cursor, limit)
+	if err != nil || len(readings) == 0 {
+		return cursor, 0, err
+	}
+	for i := range readings {
+		if err := writeReadingTraits(tx, &readings[i]); err != nil {
+			return cursor, 0, err
+		}
+	}
+	return readings[len(readings)-1].ID.String(), len(readings), nil
+}
+
+func (readingTraitsMigration) Verify(db *gorm.DB, cursor string, limit int) (string, int, int, error) {
+	readings, err := traitSourceBatch(db, cursor, limit)
+	if err != nil || len(readings) == 0 {
+		return cursor, 0, 0, err
+	}
+	ids := readingIDs(readings)
+	traits, err := loadReadingTraits(db, ids)
+	if err != nil {
+		return cursor, 0, 0, err
+	}
+	// Rows written before traits were linked to the vocabulary need
+	// another backfill too.
+	var unlinked []uuid.UUID
+	if err := db.Model(&models.ReadingTrait{}).Where("reading_id IN ? AND trait_id IS NULL AND text ~ '[[:alnum:]]'", ids).
+		Distinct("reading_id").Pluck("reading_id", &unlinked).Error; err != nil {
+		return cursor, 0, 0, err
+	}
+	mismatches := 0
+	for _, r := range readings {
+		got := traits[r.ID]
+		if !slices.Equal(got[models.TraitStrength], r.Strengths) || !slices.Equal(got[models.TraitChallenge], r.Challenges) ||
+			slices.Contains(unlinked, r.ID) {
+			mismatches++
+		}
+	}
+	return readings[len(readings)-1].ID.String(), len(readings), mismatches, nil
+}
+
+// traitSourceBatch reads the next readings in ID order.
+func traitSourceBatch(db *gorm.DB, cursor string, limit int) ([]models.AuraReading, error) {
+	query := db.Select("id", "user_id", "strengths", "challenges").Order("id").Limit(limit)
+	if cursor != "" {
+		query = query.Where("id > ?", cursor)
+	}
+	var readings []models.AuraReading
+	err := query.Find(&readings).Error
+	return readings, err
+}
+
+// writeReadingTraits replaces the reading's trait rows with its arrays,
+// each linked to its vocabulary trait.
+func writeReadingTraits(tx *gorm.DB, reading *models.AuraReading) error {
+	if err := tx.Where("reading_id = ?", reading.ID).Delete(&models.ReadingTrait{}).Error; err != nil {
+		return err
+	}
+	var rows []models.ReadingTrait
+	for kind, texts := range map[string][]string{models.TraitStrength: reading.Strengths, models.TraitChallenge: reading.Challenges} {
+		traitIDs, err := resolveTraits(tx, kind, texts)
+		if err != nil {
+			return err
+		}
+		for i, text := range texts {
+			rows = append(rows, models.ReadingTrait{ReadingID: reading.ID, UserID: reading.UserID, Kind: kind, Position: i, Text: text, TraitID: traitIDs[i]})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.Create(&rows).Error
+}
+
+// dualWriteReadingTraits mirrors a reading's arrays into reading_traits once
+// the migration has started double-writing. Call it in the transaction that
+// wrote the reading.
+func dualWriteReadingTraits(tx *gorm.DB, readingID uuid.UUID) error {
+	if !migrationPhaseAtLeast(tx, readingTraitsMigrationName, models.MigrationDoubleWrite) {
+		return nil
+	}
+	var reading models.AuraReading
+	if err := tx.Select("id", "user_id", "strengths", "challenges").First(&reading, "id = ?", readingID).Error; err != nil {
+		return err
+	}
+	return writeReadingTraits(tx, &reading)
+}
+
+// readTraitsFromTable replaces the readings' arrays with their trait rows
+// once the migration has cut reads over.
+func readTraitsFromTable(db *gorm.DB, readings []models.AuraReading) error {
+	if len(readings) == 0 || !migrationPhaseAtLeast(db, readingTraitsMigrationName, models.MigrationCutover) {
+		return nil
+	}
+	traits, err := loadReadingTraits(db, readingIDs(readings))
+	if err != nil {
+		return err
+	}
+	for i := range readings {
+		t := traits[readings[i].ID]
+		readings[i].Strengths = nonNil(t[models.TraitStrength])
+		readings[i].Challenges = nonNil(t[models.TraitChallenge])
+	}
+	return nil
+}
+
+// loadReadingTraits returns the trait texts of each reading by kind, in
+// position order.
+func loadReadingTraits(db *gorm.DB, ids []uuid.UUID) (map[uuid.UUID]map[string][]string, error) {
+	var rows []models.ReadingTrait
+	if err := db.Where("reading_id IN ?", ids).Order("reading_id, kind, position").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	traits := make(map[uuid.UUID]map[string][]string, len(ids))
+	for _, row := range rows {
+		if traits[row.ReadingID] == nil {
+			traits[row.ReadingID] = map[string][]string{}
+		}
+		traits[row.ReadingID][row.Kind] = append(traits[row.ReadingID][row.Kind], row.Text)
+	}
+	return traits, nil
+}
+
+func readingIDs(readings []models.AuraReading) []uuid.UUID {
+	ids := make([]uuid.UUID, len(readings))
+	for i, r := range readings {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCaseNotFound     = errors.New("moderation case not found")
+	ErrInvalidBulkCases = errors.New("case_ids must list 1-100 cases")
+	ErrInvalidCaseState = errors.New("invalid status: must be actioned or dismissed")
+)
+
+const maxBulkCases = 100
+
+// openCaseStatuses are the statuses of cases that still collect reports.
+var openCaseStatuses = []string{models.CaseOpen, models.CaseEscalated}
+
+// openCase returns the content's open (or escalated) case locked for update,
+// creating it when there is none. The partial unique index keeps concurrent
+// reports from opening two cases for the same content.
+func openCase(tx *gorm.DB, contentType, contentID string) (*models.ModerationCase, error) {
+	mc := models.ModerationCase{
+		ContentType:    contentType,
+		ContentID:      contentID,
+		Status:         models.CaseOpen,
+		LastReportedAt: time.Now(),
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&mc).Error; err != nil {
+		return nil, err
+	}
+
+	var locked models.ModerationCase
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("content_type = ? AND content_id = ? AND status IN ?", contentType, contentID, openCaseStatuses).
+		First(&locked).Error; err != nil {
+		return nil, err
+	}
+	return &locked, nil
+}
+
+// countReport records one more report on the case and escalates it once the
+// report count reaches the configured threshold.
+func (s *ModerationService) countReport(tx *gorm.DB, mc *models.ModerationCase) error {
+	now := time.Now()
+	mc.ReportCount++
+	mc.LastReportedAt = now
+	if shouldEscalate(mc, s.cfg.ModerationEscalationThreshold) {
+		mc.Status = models.CaseEscalated
+		mc.EscalatedAt = &now
+	}
+	return tx.Model(mc).Select("report_count", "last_reported_at", "status", "escalated_at").Updates(mc).Error
+}
+
+// shouldEscalate reports whether an open case has crossed the threshold;
+// a threshold of 0 disables automatic escalation.
+func shouldEscalate(mc *models.ModerationCase, threshold int) bool {
+	return mc.Status == models.CaseOpen && threshold > 0 && mc.ReportCount >= threshold
+}
+
+// ListCases returns moderation cases, escalated first and then by most
+// recent report.
+func (s *ModerationService) ListCases(status string, limit, offset int) ([]models.ModerationCase, int64, error) {
+	var cases []models.ModerationCase
+	var total int64
+
+	query := s.db.Model(&models.ModerationCase{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&total)
+
+	if err := query.
+		Order(clause.Expr{SQL: "status = ? DESC", Vars: []interface{}{models.CaseEscalated}}).
+		Order("last_reported_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&cases).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return cases, total, nil
+}
+
+// GetCase returns a case with all of its reports.
+func (s *ModerationService) GetCase(caseID uuid.UUID) (*models.ModerationCase, error) {
+	var mc models.ModerationCase
+	err := s.db.Preload("Reports", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).First(&mc, "id = ?", caseID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCaseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &mc, nil
+}
+
+// ResolveCases closes open or escalated cases as actioned or dismissed and
+// gives their unresolved reports the same status. Cases that are unknown or
+// already closed are returned as skipped.
+func (s *ModerationService) ResolveCases(req *dto.BulkCaseRequest) (*dto.BulkCaseResponse, error) {
+	if req.Status != models.CaseActioned && req.Status != models.CaseDismissed {
+		return nil, ErrInvalidCaseState
+	}
+	if len(req.CaseIDs) == 0 || len(req.CaseIDs) > maxBulkCases {
+		return nil, ErrInvalidBulkCases
+	}
+
+	var resp *dto.BulkCaseResponse
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		ids, skipped, err := lockCases(tx, req.CaseIDs, openCaseStatuses)
+		if err != nil {
+			return err
+		}
+		resp = &dto.BulkCaseResponse{Skipped: skipped}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		result := tx.Model(&models.ModerationCase{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":      req.Status,
+			"admin_note":  req.AdminNote,
+			"resolved_at": time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		resp.Updated = result.RowsAffected
+
+		return tx.Model(&models.Report{}).
+			Where("case_id IN ? AND status IN ?", ids, []string{"pending", "reviewed"}).
+			Updates(map[string]interface{}{
+				"status":     req.Status,
+				"admin_note": req.AdminNote,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// EscalateCases escalates open cases for senior review. Cases that are
+// unknown, closed or already escalated are returned as skipped.
+func (s *ModerationService) EscalateCases(req *dto.BulkCaseRequest) (*dto.BulkCaseResponse, error) {
+	if len(req.CaseIDs) == 0 || len(req.CaseIDs) > maxBulkCases {
+		return nil, ErrInvalidBulkCases
+	}
+
+	var resp *dto.BulkCaseResponse
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		ids, skipped, err := lockCases(tx, req.CaseIDs, []string{models.CaseOpen})
+		if err != nil {
+			return err
+		}
+		resp = &dto.BulkCaseResponse{Skipped: skipped}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		updates := map[string]interface{}{
+			"status":       models.CaseEscalated,
+			"escalated_at": time.Now(),
+		}
+		if req.AdminNote != "" {
+			updates["admin_note"] = req.AdminNote
+		}
+		result := tx.Model(&models.ModerationCase{}).Where("id IN ?", ids).Updates(updates)
+		resp.Updated = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// lockCases locks the requested cases that are in one of the given statuses
+// and returns their IDs, plus the requested IDs that were not.
+func lockCases(tx *gorm.DB, requested []uuid.UUID, statuses []string) ([]uuid.UUID, []uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := tx.Model(&models.ModerationCase{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ? AND status IN ?", requested, statuses).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, nil, err
+	}
+
+	found := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		found[id] = true
+	}
+	skipped := []uuid.UUID{}
+	for _, id := range requested {
+		if !found[id] {
+			skipped = append(skipped, id)
+			found[id] = true
+		}
+	}
+	return ids, skipped, nil
+}
+
+// BackfillCases files pending reports from before cases existed under
+// their content's open case. Duplicate reports by the same user are counted
+// once.
+func (s *ModerationService) BackfillCases() (int, error) {
+	var reports []models.Report
+	if err := s.db.Where("case_id IS NULL AND status = ?", "pending").
+		Order("created_at ASC").
+		Find(&reports).Error; err != nil {
+		return 0, err
+	}
+
+	for _, report := range reports {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			mc, err := openCase(tx, report.ContentType, report.ContentID)
+			if err != nil {
+				return err
+			}
+			var dupes int64
+			tx.Model(&models.Report{}).Where("case_id = ? AND reporter_id = ?", mc.ID, report.ReporterID).Count(&dupes)
+			if err := tx.Model(&report).Update("case_id", mc.ID).Error; err != nil {
+				return err
+			}
+			if dupes > 0 {
+				return nil
+			}
+			return s.countReport(tx, mc)
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(reports), nil
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestShouldEscalate(t *testing.T) {
+	cases := []struct {
+		status    string
+		count     int
+		threshold int
+		want      bool
+	}{
+		{models.CaseOpen, 4, 5, false},
+		{models.CaseOpen, 5, 5, true},
+		{models.CaseOpen, 50, 0, false},
+		{models.CaseEscalated, 9, 5, false},
+		{models.CaseDismissed, 9, 5, false},
+	}
+	for _, tc := range cases {
+		mc := &models.ModerationCase{Status: tc.status, ReportCount: tc.count}
+		if got := shouldEscalate(mc, tc.threshold); got != tc.want {
+			t.Errorf("shouldEscalate(%s, %d, threshold %d) = %v, want %v", tc.status, tc.count, tc.threshold, got, tc.want)
+		}
+	}
+}
+
+func TestResolveCasesValidatesRequest(t *testing.T) {
+	s := &ModerationService{}
+	if _, err := s.ResolveCases(&dto.BulkCaseRequest{CaseIDs: []uuid.UUID{uuid.New()}, Status: "reviewed"}); !errors.Is(err, ErrInvalidCaseState) {
+		t.Errorf("status reviewed: error = %v, want ErrInvalidCaseState", err)
+	}
+	if _, err := s.ResolveCases(&dto.BulkCaseRequest{Status: models.CaseDismissed}); !errors.Is(err, ErrInvalidBulkCases) {
+		t.Errorf("no cases: error = %v, want ErrInvalidBulkCases", err)
+	}
+	if _, err := s.EscalateCases(&dto.BulkCaseRequest{CaseIDs: make([]uuid.UUID, maxBulkCases+1)}); !errors.Is(err, ErrInvalidBulkCases) {
+		t.Errorf("too many cases: error = %v, want ErrInvalidBulkCases", err)
+	}
+}
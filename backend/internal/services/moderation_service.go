@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
 	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
 	"github.com/google/uuid"
@@ -16,6 +17,10 @@ var (
 	ErrReportNotFound = errors.New("report not found")
 	ErrAlreadyBlocked = errors.New("user already blocked")
 	ErrSelfBlock      = errors.New("cannot block yourself")
+
+	ErrInvalidReportType   = errors.New("invalid content_type: must be user, post, or comment")
+	ErrReportReasonMissing = errors.New("reason is required")
+	ErrInvalidReportStatus = errors.New("invalid status: must be reviewed, actioned, or dismissed")
 )
 
 // ProfanityPatterns is a basic regex-based content filter (Apple Guideline 1.2).
@@ -26,11 +31,12 @@ var ProfanityPatterns = []*regexp.Regexp{
 }
 
 type ModerationService struct {
-	db *gorm.DB
+	db  *gorm.DB
+	cfg *config.Config
 }
 
-func NewModerationService(db *gorm.DB) *ModerationService {
-	return &ModerationService{db: db}
+func NewModerationService(db *gorm.DB, cfg *config.Config) *ModerationService {
+	return &ModerationService{db: db, cfg: cfg}
 }
 
 // --- Content Filtering ---
@@ -56,14 +62,17 @@ func (s *ModerationService) SanitizeContent(text string) string {
 
 // --- Reports ---
 
+// CreateReport files the report under the content's open moderation case,
+// opening one if needed. A user reporting the same content again while its
+// case is open gets their existing report back instead of a duplicate.
 func (s *ModerationService) CreateReport(reporterID uuid.UUID, req *dto.CreateReportRequest) (*models.Report, error) {
 	validTypes := map[string]bool{"user": true, "post": true, "comment": true}
 	if !validTypes[req.ContentType] {
-		return nil, errors.New("invalid content_type: must be user, post, or comment")
+		return nil, ErrInvalidReportType
 	}
 
 	if strings.TrimSpace(req.Reason) == "" {
-		return nil, errors.New("reason is required")
+		return nil, ErrReportReasonMissing
 	}
 
 	report := models.Report{
@@ -75,7 +84,25 @@ func (s *ModerationService) CreateReport(reporterID uuid.UUID, req *dto.CreateRe
 		Status:      "pending",
 	}
 
-	if err := s.db.Create(&report).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		mc, err := openCase(tx, req.ContentType, req.ContentID)
+		if err != nil {
+			return err
+		}
+
+		var existing models.Report
+		if err := tx.Where("case_id = ? AND reporter_id = ?", mc.ID, reporterID).First(&existing).Error; err == nil {
+			report = existing
+			return nil
+		}
+
+		report.CaseID = &mc.ID
+		if err := tx.Create(&report).Error; err != nil {
+			return err
+		}
+		return s.countReport(tx, mc)
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to create report: %w", err)
 	}
 
@@ -103,7 +130,7 @@ func (s *ModerationService) ListReports(status string, limit, offset int) ([]mod
 func (s *ModerationService) ActionReport(reportID uuid.UUID, req *dto.ActionReportRequest) error {
 	validStatuses := map[string]bool{"reviewed": true, "actioned": true, "dismissed": true}
 	if !validStatuses[req.Status] {
-		return errors.New("invalid status: must be reviewed, actioned, or dismissed")
+		return ErrInvalidReportStatus
 	}
 
 	result := s.db.Model(&models.Report{}).
@@ -138,7 +165,13 @@ func (s *ModerationService) BlockUser(blockerID, blockedID uuid.UUID) error {
 		BlockedID: blockedID,
 	}
 
-	return s.db.Create(&block).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&block).Error; err != nil {
+			return err
+		}
+		// Blocking ends any friendship or pending request between the pair.
+		return removeFriendships(tx, blockerID, blockedID)
+	})
 }
 
 func (s *ModerationService) UnblockUser(blockerID, blockedID uuid.UUID) error {
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Social delivery modes.
+const (
+	SocialDeliveryImmediate = "immediate"
+	SocialDeliveryDigest    = "digest"
+
+	// deliveryQueued is returned by Notify instead of channel names when the
+	// message was queued for a digest.
+	deliveryQueued = "digest"
+)
+
+// digestSummaries phrase a batch of same-kind events, keyed by Data["event"].
+var digestSummaries = map[string]string{
+	"friend_scan":    "%d friends scanned their aura today",
+	"friend_match":   "%d friends matched auras with you",
+	"friend_request": "You have %d new friend requests",
+	"friend_added":   "%d people joined you as friends",
+}
+
+func (s *NotificationService) digestWindow() time.Duration {
+	if s.cfg == nil || s.cfg.NotificationDigestWindow <= 0 {
+		return 3 * time.Hour
+	}
+	return s.cfg.NotificationDigestWindow
+}
+
+// enqueueDigest adds msg to the user's open digest, opening one that is due
+// after the digest window if none is pending.
+func (s *NotificationService) enqueueDigest(userID uuid.UUID, msg NotificationMessage) error {
+	dueAt := time.Now().Add(s.digestWindow())
+
+	var open models.PendingNotification
+	if err := s.db.Where("user_id = ?", userID).Order("due_at ASC").First(&open).Error; err == nil {
+		dueAt = open.DueAt
+	}
+
+	return s.db.Create(&models.PendingNotification{
+		UserID:   userID,
+		Category: msg.Category,
+		Title:    msg.Title,
+		Body:     msg.Body,
+		Data:     msg.Data,
+		DueAt:    dueAt,
+	}).Error
+}
+
+// RunDigestWorker flushes due digests every interval until ctx is cancelled.
+func (s *NotificationService) RunDigestWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.FlushDueDigests(time.Now()); err != nil {
+				log.Printf("notification: digest flush failed: %v", err)
+			} else if n > 0 {
+				log.Printf("notification: sent %d digests", n)
+			}
+		}
+	}
+}
+
+// FlushDueDigests sends one summary per user whose digest is due and removes
+// the batched events. Rows are claimed with SKIP LOCKED so several instances
+// can run the worker.
+func (s *NotificationService) FlushDueDigests(now time.Time) (int, error) {
+	var due []models.PendingNotification
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("due_at <= ?", now).
+			Order("created_at ASC").
+			Find(&due).Error; err != nil {
+			return err
+		}
+		if len(due) == 0 {
+			return nil
+		}
+		ids := make([]uuid.UUID, len(due))
+		for i, p := range due {
+			ids[i] = p.ID
+		}
+		return tx.Where("id IN ?", ids).Delete(&models.PendingNotification{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	byUser := make(map[uuid.UUID][]models.PendingNotification)
+	var order []uuid.UUID
+	for _, p := range due {
+		if _, ok := byUser[p.UserID]; !ok {
+			order = append(order, p.UserID)
+		}
+		byUser[p.UserID] = append(byUser[p.UserID], p)
+	}
+
+	for _, userID := range order {
+		msg := summarizeDigest(byUser[userID])
+		if _, err := s.dispatch(userID, msg); err != nil {
+			log.Printf("notification: digest for %s failed: %v", userID, err)
+		}
+	}
+	return len(order), nil
+}
+
+// summarizeDigest turns batched events into one message. A single event is
+// sent unchanged; several of one kind use digestSummaries.
+func summarizeDigest(items []models.PendingNotification) NotificationMessage {
+	first := items[0]
+	if len(items) == 1 {
+		return NotificationMessage{Category: first.Category, Title: first.Title, Body: first.Body, Data: first.Data}
+	}
+
+	event := first.Data["event"]
+	sameEvent := event != ""
+	for _, item := range items[1:] {
+		if item.Data["event"] != event {
+			sameEvent = false
+			break
+		}
+	}
+
+	title := fmt.Sprintf("%d new updates from friends", len(items))
+	if format, ok := digestSummaries[event]; ok && sameEvent {
+		title = fmt.Sprintf(format, len(items))
+	}
+
+	data := map[string]string{"digest_count": fmt.Sprint(len(items))}
+	if sameEvent {
+		data["event"] = event
+	}
+
+	return NotificationMessage{
+		Category: first.Category,
+		Title:    title,
+		Body:     fmt.Sprintf("%s and %d more", first.Title, len(items)-1),
+		Data:     data,
+	}
+}
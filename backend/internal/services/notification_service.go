@@ -0,0 +1,458 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/queue"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Notification channels.
+const (
+	ChannelPush  = "push"
+	ChannelEmail = "email"
+	ChannelInbox = "inbox"
+)
+
+// Notification categories.
+const (
+	CategoryReminders = "reminders"
+	CategorySocial    = "social"
+	CategoryMarketing = "marketing"
+	CategoryInsights  = "insights"
+)
+
+// Notification priorities. High priority (security alerts) bypasses quiet
+// hours and the daily cap.
+const (
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+// CategorySecurity is outside the preference matrix: security alerts are
+// always delivered and always high priority.
+const CategorySecurity = "security"
+
+// CategoryAccount is also outside the matrix: messages from the AuraSnap
+// team about the user's account or tickets are always delivered, but wait
+// for quiet hours like any normal message.
+const CategoryAccount = "account"
+
+const (
+	defaultQuietHoursStart = "22:00"
+	defaultQuietHoursEnd   = "08:00"
+)
+
+var (
+	notificationChannels   = []string{ChannelPush, ChannelEmail, ChannelInbox}
+	notificationCategories = []string{CategoryReminders, CategorySocial, CategoryMarketing, CategoryInsights}
+
+	ErrInvalidNotificationPreference = errors.New("invalid notification preference")
+	ErrNotificationNotFound          = errors.New("notification not found")
+)
+
+// defaultNotificationPreference applies until the user changes a cell.
+// Marketing is opt-in on every channel; email is opt-in for everything.
+func defaultNotificationPreference(channel, category string) bool {
+	if category == CategoryMarketing || channel == ChannelEmail {
+		return false
+	}
+	return true
+}
+
+// NotificationMessage is a channel-agnostic notification.
+type NotificationMessage struct {
+	Category string
+	Priority string
+	Title    string
+	Body     string
+	Data     map[string]string
+}
+
+// NotificationSender delivers a message on one channel. Senders are only
+// reached through NotificationService.Notify, which enforces preferences.
+type NotificationSender interface {
+	Channel() string
+	Send(userID uuid.UUID, msg NotificationMessage) error
+}
+
+type NotificationService struct {
+	db      *gorm.DB
+	cfg     *config.Config
+	senders map[string]NotificationSender
+	tasks   queue.Queue
+}
+
+// NewNotificationService creates the dispatcher with the in-app inbox channel.
+// Push and email senders are registered as their drivers are configured.
+func NewNotificationService(db *gorm.DB, cfg *config.Config) *NotificationService {
+	s := &NotificationService{db: db, cfg: cfg, senders: make(map[string]NotificationSender)}
+	s.RegisterSender(&inboxSender{db: db})
+	return s
+}
+
+func (s *NotificationService) RegisterSender(sender NotificationSender) {
+	s.senders[sender.Channel()] = sender
+}
+
+// UseQueue hands NotifyLater's deliveries to the workers.
+func (s *NotificationService) UseQueue(tasks queue.Queue) {
+	s.tasks = tasks
+}
+
+// TaskNotificationSend delivers a notification queued by NotifyLater.
+const TaskNotificationSend = "notification.send"
+
+type notificationTask struct {
+	UserID  uuid.UUID           `json:"user_id"`
+	Message NotificationMessage `json:"message"`
+}
+
+// NotifyLater queues msg for a worker to deliver through Notify, so a
+// request does not wait on push and email providers. Without a queue it
+// delivers right away.
+func (s *NotificationService) NotifyLater(ctx context.Context, userID uuid.UUID, msg NotificationMessage) error {
+	if s.tasks == nil {
+		_, err := s.Notify(userID, msg)
+		return err
+	}
+	return s.tasks.Enqueue(ctx, TaskNotificationSend, notificationTask{UserID: userID, Message: msg})
+}
+
+// HandleNotificationTask is the worker side of NotifyLater.
+func (s *NotificationService) HandleNotificationTask(ctx context.Context, payload []byte) error {
+	var task notificationTask
+	if err := queue.Decode(payload, &task); err != nil {
+		return err
+	}
+	_, err := s.Notify(task.UserID, task.Message)
+	return err
+}
+
+// Notify sends msg on every channel the user allows for its category and
+// returns the channels that accepted it. Failures on one channel do not stop
+// the others. Social messages for users in digest mode are queued instead and
+// the result is ["digest"].
+func (s *NotificationService) Notify(userID uuid.UUID, msg NotificationMessage) ([]string, error) {
+	// Deactivated accounts get nothing while they wait to be restored or purged.
+	if !isActiveUser(s.db, userID) {
+		return nil, nil
+	}
+	if msg.Category == CategorySocial && msg.Priority != PriorityHigh {
+		pref, err := s.loadPreference(userID)
+		if err != nil {
+			return nil, err
+		}
+		if pref.SocialDelivery == SocialDeliveryDigest {
+			if err := s.enqueueDigest(userID, msg); err != nil {
+				return nil, err
+			}
+			return []string{deliveryQueued}, nil
+		}
+	}
+	return s.dispatch(userID, msg)
+}
+
+// dispatch delivers msg immediately. Push and email interrupt the user, so
+// outside high priority they are held back during quiet hours and once the
+// daily cap is reached; the inbox entry is still written so nothing is lost.
+func (s *NotificationService) dispatch(userID uuid.UUID, msg NotificationMessage) ([]string, error) {
+	if msg.Category == CategorySecurity {
+		msg.Priority = PriorityHigh
+	} else if msg.Category != CategoryAccount && !isNotificationCategory(msg.Category) {
+		return nil, fmt.Errorf("%w: unknown category %q", ErrInvalidNotificationPreference, msg.Category)
+	}
+	if msg.Priority != PriorityHigh {
+		msg.Priority = PriorityNormal
+	}
+
+	pref, err := s.loadPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	interruptAllowed := true
+	if msg.Priority != PriorityHigh {
+		if reason := s.holdReason(userID, pref); reason != "" {
+			log.Printf("notification: holding %s push/email for %s: %s", msg.Category, userID, reason)
+			interruptAllowed = false
+		}
+	}
+
+	var delivered, interrupted []string
+	var lastErr error
+	for _, channel := range notificationChannels {
+		sender, ok := s.senders[channel]
+		if !ok {
+			continue
+		}
+		if msg.Category != CategorySecurity && msg.Category != CategoryAccount && !pref.Matrix[channel][msg.Category] {
+			continue
+		}
+		if channel != ChannelInbox && !interruptAllowed {
+			continue
+		}
+		if err := sender.Send(userID, msg); err != nil {
+			if errors.Is(err, ErrNoNotificationRoute) {
+				continue
+			}
+			log.Printf("notification: %s delivery to %s failed: %v", channel, userID, err)
+			lastErr = err
+			continue
+		}
+		delivered = append(delivered, channel)
+		if channel != ChannelInbox {
+			interrupted = append(interrupted, channel)
+		}
+	}
+
+	if len(interrupted) > 0 {
+		s.db.Create(&models.NotificationDispatch{UserID: userID, Category: msg.Category, Priority: msg.Priority, Channels: interrupted})
+	}
+
+	if len(delivered) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return delivered, nil
+}
+
+// holdReason returns why interrupting channels are held back, or "".
+func (s *NotificationService) holdReason(userID uuid.UUID, pref models.NotificationPreference) string {
+	loc := userLocation(s.db, userID, "")
+	now := time.Now().In(loc)
+
+	if pref.QuietHoursEnabled && inQuietHours(now, pref.QuietHoursStart, pref.QuietHoursEnd) {
+		return "quiet hours"
+	}
+
+	limit := s.dailyCap()
+	if limit <= 0 {
+		return ""
+	}
+	start, end := localDayBounds(now, loc)
+	var sent int64
+	if err := s.db.Model(&models.NotificationDispatch{}).
+		Where("user_id = ? AND priority <> ? AND created_at >= ? AND created_at < ?", userID, PriorityHigh, start, end).
+		Count(&sent).Error; err != nil {
+		return ""
+	}
+	if sent >= int64(limit) {
+		return "daily cap reached"
+	}
+	return ""
+}
+
+func (s *NotificationService) dailyCap() int {
+	if s.cfg == nil {
+		return 0
+	}
+	return s.cfg.NotificationDailyCap
+}
+
+// GetPreferences returns the effective matrix (stored values over defaults)
+// with quiet hours and the daily cap.
+func (s *NotificationService) GetPreferences(userID uuid.UUID) (*dto.NotificationPreferencesResponse, error) {
+	pref, err := s.loadPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.preferenceResponse(pref), nil
+}
+
+// UpdatePreferences applies a partial matrix and optional quiet hours.
+func (s *NotificationService) UpdatePreferences(userID uuid.UUID, req *dto.NotificationPreferencesRequest) (*dto.NotificationPreferencesResponse, error) {
+	for channel, categories := range req.Preferences {
+		if !isNotificationChannel(channel) {
+			return nil, fmt.Errorf("%w: unknown channel %q", ErrInvalidNotificationPreference, channel)
+		}
+		for category := range categories {
+			if !isNotificationCategory(category) {
+				return nil, fmt.Errorf("%w: unknown category %q", ErrInvalidNotificationPreference, category)
+			}
+		}
+	}
+	if mode := req.SocialDelivery; mode != "" && mode != SocialDeliveryImmediate && mode != SocialDeliveryDigest {
+		return nil, fmt.Errorf("%w: social_delivery must be immediate or digest", ErrInvalidNotificationPreference)
+	}
+	if q := req.QuietHours; q != nil {
+		if _, ok := parseClock(q.Start); !ok {
+			return nil, fmt.Errorf("%w: quiet_hours.start must be HH:MM", ErrInvalidNotificationPreference)
+		}
+		if _, ok := parseClock(q.End); !ok {
+			return nil, fmt.Errorf("%w: quiet_hours.end must be HH:MM", ErrInvalidNotificationPreference)
+		}
+	}
+
+	pref, err := s.loadPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+	pref.Matrix = mergeNotificationMatrix(pref.Matrix, req.Preferences)
+	if q := req.QuietHours; q != nil {
+		pref.QuietHoursEnabled = q.Enabled
+		pref.QuietHoursStart = q.Start
+		pref.QuietHoursEnd = q.End
+	}
+	if req.SocialDelivery != "" {
+		pref.SocialDelivery = req.SocialDelivery
+	}
+
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"matrix", "quiet_hours_enabled", "quiet_hours_start", "quiet_hours_end", "social_delivery", "updated_at"}),
+	}).Create(&pref).Error; err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return s.preferenceResponse(pref), nil
+}
+
+// loadPreference returns the stored preference row or the defaults.
+func (s *NotificationService) loadPreference(userID uuid.UUID) (models.NotificationPreference, error) {
+	pref := models.NotificationPreference{
+		UserID:            userID,
+		QuietHoursEnabled: true,
+		QuietHoursStart:   defaultQuietHoursStart,
+		QuietHoursEnd:     defaultQuietHoursEnd,
+		SocialDelivery:    SocialDeliveryDigest,
+	}
+	err := s.db.Where("user_id = ?", userID).First(&pref).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return pref, err
+	}
+	pref.Matrix = mergeNotificationMatrix(pref.Matrix, nil)
+	return pref, nil
+}
+
+func (s *NotificationService) preferenceResponse(pref models.NotificationPreference) *dto.NotificationPreferencesResponse {
+	return &dto.NotificationPreferencesResponse{
+		Preferences: pref.Matrix,
+		QuietHours: dto.QuietHoursSettings{
+			Enabled: pref.QuietHoursEnabled,
+			Start:   pref.QuietHoursStart,
+			End:     pref.QuietHoursEnd,
+		},
+		DailyCap:       s.dailyCap(),
+		SocialDelivery: pref.SocialDelivery,
+	}
+}
+
+// inQuietHours reports whether local time falls in [start, end). Windows that
+// wrap midnight (22:00-08:00) are supported; start == end means no window.
+func inQuietHours(local time.Time, start, end string) bool {
+	from, ok1 := parseClock(start)
+	to, ok2 := parseClock(end)
+	if !ok1 || !ok2 || from == to {
+		return false
+	}
+
+	minute := local.Hour()*60 + local.Minute()
+	if from < to {
+		return minute >= from && minute < to
+	}
+	return minute >= from || minute < to
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(value string) (int, bool) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// ListInbox returns the newest inbox notifications and the unread count.
+func (s *NotificationService) ListInbox(userID uuid.UUID, limit int) ([]models.Notification, int64, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	var items []models.Notification
+	if err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var unread int64
+	if err := s.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&unread).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, unread, nil
+}
+
+func (s *NotificationService) MarkRead(userID, notificationID uuid.UUID) error {
+	result := s.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", notificationID, userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// mergeNotificationMatrix builds a complete matrix from defaults, then base,
+// then changes. Unknown keys in base are dropped.
+func mergeNotificationMatrix(base, changes map[string]map[string]bool) map[string]map[string]bool {
+	out := make(map[string]map[string]bool, len(notificationChannels))
+	for _, channel := range notificationChannels {
+		out[channel] = make(map[string]bool, len(notificationCategories))
+		for _, category := range notificationCategories {
+			value := defaultNotificationPreference(channel, category)
+			if v, ok := base[channel][category]; ok {
+				value = v
+			}
+			if v, ok := changes[channel][category]; ok {
+				value = v
+			}
+			out[channel][category] = value
+		}
+	}
+	return out
+}
+
+func isNotificationChannel(channel string) bool {
+	for _, c := range notificationChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+func isNotificationCategory(category string) bool {
+	for _, c := range notificationCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// inboxSender stores notifications for the in-app inbox.
+type inboxSender struct {
+	db *gorm.DB
+}
+
+func (s *inboxSender) Channel() string { return ChannelInbox }
+
+func (s *inboxSender) Send(userID uuid.UUID, msg NotificationMessage) error {
+	return s.db.Create(&models.Notification{
+		UserID:   userID,
+		Category: msg.Category,
+		Title:    msg.Title,
+		Body:     msg.Body,
+		Data:     msg.Data,
+	}).Error
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestMergeNotificationMatrix(t *testing.T) {
+	merged := mergeNotificationMatrix(
+		map[string]map[string]bool{ChannelPush: {CategorySocial: false}, "sms": {CategorySocial: true}},
+		map[string]map[string]bool{ChannelEmail: {CategoryMarketing: true}},
+	)
+
+	if _, ok := merged["sms"]; ok {
+		t.Fatal("unknown channel should be dropped")
+	}
+	if len(merged) != len(notificationChannels) {
+		t.Fatalf("expected %d channels, got %d", len(notificationChannels), len(merged))
+	}
+	if merged[ChannelPush][CategorySocial] {
+		t.Fatal("stored opt-out should override default")
+	}
+	if !merged[ChannelEmail][CategoryMarketing] {
+		t.Fatal("change should override default")
+	}
+	if merged[ChannelPush][CategoryMarketing] || merged[ChannelInbox][CategoryMarketing] {
+		t.Fatal("marketing must default to opted out")
+	}
+	if !merged[ChannelPush][CategoryReminders] || !merged[ChannelInbox][CategoryInsights] {
+		t.Fatal("expected default opt-in for reminders and insights")
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2026, 5, 1, hour, minute, 0, 0, time.UTC) }
+
+	cases := []struct {
+		now        time.Time
+		start, end string
+		want       bool
+	}{
+		{at(23, 30), "22:00", "08:00", true},
+		{at(3, 0), "22:00", "08:00", true},
+		{at(8, 0), "22:00", "08:00", false},
+		{at(21, 59), "22:00", "08:00", false},
+		{at(13, 15), "13:00", "14:00", true},
+		{at(14, 0), "13:00", "14:00", false},
+		{at(12, 0), "09:00", "09:00", false},
+		{at(12, 0), "bad", "09:00", false},
+	}
+	for _, tc := range cases {
+		if got := inQuietHours(tc.now, tc.start, tc.end); got != tc.want {
+			t.Fatalf("inQuietHours(%s, %s-%s) = %v, want %v", tc.now.Format("15:04"), tc.start, tc.end, got, tc.want)
+		}
+	}
+}
+
+func TestSummarizeDigest(t *testing.T) {
+	scan := func(name string) models.PendingNotification {
+		return models.PendingNotification{Category: CategorySocial, Title: name + " scanned their aura", Data: map[string]string{"event": "friend_scan"}}
+	}
+
+	single := summarizeDigest([]models.PendingNotification{scan("Ada")})
+	if single.Title != "Ada scanned their aura" {
+		t.Fatalf("single event should pass through, got %q", single.Title)
+	}
+
+	batch := summarizeDigest([]models.PendingNotification{scan("Ada"), scan("Lin"), scan("Sam")})
+	if batch.Title != "3 friends scanned their aura today" || batch.Body != "Ada scanned their aura and 2 more" {
+		t.Fatalf("unexpected digest: %q / %q", batch.Title, batch.Body)
+	}
+
+	mixed := summarizeDigest([]models.PendingNotification{scan("Ada"), {Category: CategorySocial, Title: "Lin matched with you", Data: map[string]string{"event": "friend_match"}}})
+	if mixed.Title != "2 new updates from friends" {
+		t.Fatalf("unexpected mixed digest title: %q", mixed.Title)
+	}
+}
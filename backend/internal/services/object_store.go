@@ -0,0 +1,211 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// ErrObjectNotRestored is returned when reading a cold object whose restore
+// has not finished (or was never requested).
+var ErrObjectNotRestored = errors.New("object is in cold storage and not restored yet")
+
+// ObjectStore writes internal artifacts (e.g. research datasets) by key.
+type ObjectStore interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	// Name identifies the store in logs and admin responses.
+	Name() string
+}
+
+// ColdStore is an ObjectStore that can also keep objects in a cold storage
+// class. Cold objects cost less to keep but must be restored, which takes
+// hours, before they can be read again.
+type ColdStore interface {
+	ObjectStore
+	PutCold(ctx context.Context, key string, body []byte, contentType string) error
+	// Restore requests a readable copy of a cold object for days days.
+	// Requesting a restore that is already under way is not an error.
+	Restore(ctx context.Context, key string, days int) error
+	// Get reads an object, or returns ErrObjectNotRestored for a cold
+	// object that is not readable yet.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewObjectStore returns the S3 store when OBJECT_STORE_BUCKET is set, a
+// local directory store when OBJECT_STORE_DIR is set, and nil otherwise.
+func NewObjectStore(cfg *config.Config) (ObjectStore, error) {
+	switch {
+	case cfg.ObjectStoreBucket != "":
+		return newS3ObjectStore(cfg)
+	case cfg.ObjectStoreDir != "":
+		return &dirObjectStore{dir: cfg.ObjectStoreDir}, nil
+	}
+	return nil, nil
+}
+
+// s3ObjectStore puts objects in one bucket under an optional prefix.
+// Credentials come from the default AWS chain, as for SES.
+type s3ObjectStore struct {
+	client    *s3.Client
+	bucket    string
+	prefix    string
+	coldClass types.StorageClass
+}
+
+func newS3ObjectStore(cfg *config.Config) (*s3ObjectStore, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if cfg.SESRegion != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.SESRegion))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("load AWS config for S3: %w", err)
+	}
+	return &s3ObjectStore{
+		client:    s3.NewFromConfig(awsCfg),
+		bucket:    cfg.ObjectStoreBucket,
+		prefix:    strings.Trim(cfg.ObjectStorePrefix, "/"),
+		coldClass: types.StorageClass(cfg.PhotoColdStorageClass),
+	}, nil
+}
+
+func (o *s3ObjectStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	_, err := o.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(o.bucket),
+		Key:         aws.String(path.Join(o.prefix, key)),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+// PutCold writes the object straight into the configured cold storage
+// class (GLACIER by default).
+func (o *s3ObjectStore) PutCold(ctx context.Context, key string, body []byte, contentType string) error {
+	_, err := o.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:       aws.String(o.bucket),
+		Key:          aws.String(path.Join(o.prefix, key)),
+		Body:         bytes.NewReader(body),
+		ContentType:  aws.String(contentType),
+		StorageClass: o.coldClass,
+	})
+	return err
+}
+
+func (o *s3ObjectStore) Restore(ctx context.Context, key string, days int) error {
+	_, err := o.client.RestoreObject(ctx, &s3.RestoreObjectInput{
+		Bucket: aws.String(o.bucket),
+		Key:    aws.String(path.Join(o.prefix, key)),
+		RestoreRequest: &types.RestoreRequest{
+			Days:                 aws.Int32(int32(days)),
+			GlacierJobParameters: &types.GlacierJobParameters{Tier: types.TierStandard},
+		},
+	})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "RestoreAlreadyInProgress" {
+		return nil
+	}
+	var active *types.ObjectAlreadyInActiveTierError
+	if errors.As(err, &active) {
+		return nil
+	}
+	return err
+}
+
+func (o *s3ObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	out, err := o.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(o.bucket),
+		Key:    aws.String(path.Join(o.prefix, key)),
+	})
+	var cold *types.InvalidObjectState
+	if errors.As(err, &cold) {
+		return nil, ErrObjectNotRestored
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (o *s3ObjectStore) Delete(ctx context.Context, key string) error {
+	_, err := o.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(o.bucket),
+		Key:    aws.String(path.Join(o.prefix, key)),
+	})
+	return err
+}
+
+// Probe checks the bucket exists and the credentials can reach it.
+func (o *s3ObjectStore) Probe(ctx context.Context) error {
+	_, err := o.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(o.bucket)})
+	return err
+}
+
+func (o *s3ObjectStore) Name() string {
+	return "s3://" + path.Join(o.bucket, o.prefix)
+}
+
+// dirObjectStore writes objects as files, for local development. It has no
+// real cold tier: cold objects are plain files and restores are instant.
+type dirObjectStore struct {
+	dir string
+}
+
+func (o *dirObjectStore) path(key string) string {
+	return filepath.Join(o.dir, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+func (o *dirObjectStore) Put(_ context.Context, key string, body []byte, _ string) error {
+	target := o.path(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(target, body, 0o600)
+}
+
+func (o *dirObjectStore) PutCold(ctx context.Context, key string, body []byte, contentType string) error {
+	return o.Put(ctx, key, body, contentType)
+}
+
+func (o *dirObjectStore) Restore(context.Context, string, int) error {
+	return nil
+}
+
+func (o *dirObjectStore) Get(_ context.Context, key string) ([]byte, error) {
+	return os.ReadFile(o.path(key))
+}
+
+func (o *dirObjectStore) Delete(_ context.Context, key string) error {
+	if err := os.Remove(o.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Probe checks the directory exists.
+func (o *dirObjectStore) Probe(context.Context) error {
+	info, err := os.Stat(o.dir)
+	if err == nil && !info.IsDir() {
+		err = fmt.Errorf("%s is not a directory", o.dir)
+	}
+	return err
+}
+
+func (o *dirObjectStore) Name() string {
+	return "file://" + o.dir
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrUnknownOnboardingStep = errors.New("unknown onboarding step")
+	ErrStepNotSkippable      = errors.New("this onboarding step can't be skipped")
+)
+
+// onboardingFlowVersion changes whenever steps are added, removed or reordered.
+const onboardingFlowVersion = 1
+
+// onboardingStep is a step definition. Bump Version when a step changes
+// enough that users who already finished it should see it again.
+type onboardingStep struct {
+	dto.OnboardingStep
+	guestOnly bool
+}
+
+// onboardingFlow is served in order; clients render steps by Type.
+var onboardingFlow = []onboardingStep{
+	{OnboardingStep: dto.OnboardingStep{
+		ID: "camera_permission", Type: "permission", Version: 1, Required: true,
+		Title:  "Let AuraSnap see you",
+		Body:   "We need camera access to read your aura. Photos are analyzed and never sold.",
+		Params: map[string]string{"permission": "camera"},
+	}},
+	{OnboardingStep: dto.OnboardingStep{
+		ID: "first_scan", Type: "scan", Version: 1, Required: true,
+		Title: "Take your first aura scan",
+		Body:  "Snap a selfie in good light to reveal your aura color.",
+	}},
+	{OnboardingStep: dto.OnboardingStep{
+		ID: "claim_account", Type: "claim_account", Version: 1, Skippable: true,
+		Title: "Save your aura history",
+		Body:  "Add an email and password so your readings and streak follow you to any device.",
+	}, guestOnly: true},
+	{OnboardingStep: dto.OnboardingStep{
+		ID: "notifications", Type: "permission", Version: 1, Skippable: true,
+		Title:  "Never break your streak",
+		Body:   "Get a gentle reminder for your daily scan and when friends match with you.",
+		Params: map[string]string{"permission": "notifications"},
+	}},
+}
+
+// OnboardingService tracks each user's progress through the onboarding flow
+type OnboardingService struct {
+	db *gorm.DB
+}
+
+func NewOnboardingService(db *gorm.DB) *OnboardingService {
+	return &OnboardingService{db: db}
+}
+
+// Flow returns the onboarding steps that apply to the user with their status.
+func (s *OnboardingService) Flow(userID uuid.UUID) (*dto.OnboardingResponse, error) {
+	var user models.User
+	if err := s.db.Select("id", "email").First(&user, "id = ?", userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	var rows []models.OnboardingProgress
+	if err := s.db.Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	progress := make(map[string]models.OnboardingProgress, len(rows))
+	for _, r := range rows {
+		progress[r.StepID] = r
+	}
+
+	var readings int64
+	s.db.Model(&models.AuraReading{}).Where("user_id = ?", userID).Limit(1).Count(&readings)
+
+	return resolveOnboarding(onboardingFlow, progress, readings > 0, isGuestEmail(user.Email)), nil
+}
+
+// CompleteStep marks a step done for the user.
+func (s *OnboardingService) CompleteStep(userID uuid.UUID, stepID string) (*dto.OnboardingResponse, error) {
+	return s.record(userID, stepID, models.OnboardingCompleted)
+}
+
+// SkipStep marks an optional step as skipped.
+func (s *OnboardingService) SkipStep(userID uuid.UUID, stepID string) (*dto.OnboardingResponse, error) {
+	return s.record(userID, stepID, models.OnboardingSkipped)
+}
+
+func (s *OnboardingService) record(userID uuid.UUID, stepID, status string) (*dto.OnboardingResponse, error) {
+	step := findOnboardingStep(stepID)
+	if step == nil {
+		return nil, ErrUnknownOnboardingStep
+	}
+	if status == models.OnboardingSkipped && !step.Skippable {
+		return nil, ErrStepNotSkippable
+	}
+
+	row := models.OnboardingProgress{UserID: userID, StepID: step.ID, StepVersion: step.Version, Status: status}
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "step_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"step_version", "status", "updated_at"}),
+	}).Create(&row).Error; err != nil {
+		return nil, err
+	}
+	return s.Flow(userID)
+}
+
+func findOnboardingStep(id string) *onboardingStep {
+	for i := range onboardingFlow {
+		if onboardingFlow[i].ID == id {
+			return &onboardingFlow[i]
+		}
+	}
+	return nil
+}
+
+// resolveOnboarding filters the flow for the user and fills in each step's
+// status. Progress recorded against an older step version doesn't count, and
+// first_scan completes itself once the user has a reading.
+func resolveOnboarding(flow []onboardingStep, progress map[string]models.OnboardingProgress, hasReading, isGuest bool) *dto.OnboardingResponse {
+	resp := &dto.OnboardingResponse{Version: onboardingFlowVersion, Steps: []dto.OnboardingStep{}}
+	for _, def := range flow {
+		if def.guestOnly && !isGuest {
+			continue
+		}
+		step := def.OnboardingStep
+		step.Status = "pending"
+		if p, ok := progress[step.ID]; ok && p.StepVersion >= step.Version {
+			step.Status = p.Status
+		}
+		if step.ID == "first_scan" && hasReading {
+			step.Status = models.OnboardingCompleted
+		}
+		if step.Status == "pending" && resp.NextStep == "" {
+			resp.NextStep = step.ID
+		}
+		resp.Steps = append(resp.Steps, step)
+	}
+	resp.Completed = resp.NextStep == ""
+	return resp
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestResolveOnboardingGuest(t *testing.T) {
+	resp := resolveOnboarding(onboardingFlow, map[string]models.OnboardingProgress{
+		"camera_permission": {StepID: "camera_permission", StepVersion: 1, Status: models.OnboardingCompleted},
+	}, true, true)
+
+	if len(resp.Steps) != len(onboardingFlow) {
+		t.Fatalf("guest should see all %d steps, got %d", len(onboardingFlow), len(resp.Steps))
+	}
+	if resp.Steps[1].Status != models.OnboardingCompleted {
+		t.Errorf("first_scan should complete itself once a reading exists, got %s", resp.Steps[1].Status)
+	}
+	if resp.NextStep != "claim_account" || resp.Completed {
+		t.Errorf("expected next step claim_account, got %q (completed=%v)", resp.NextStep, resp.Completed)
+	}
+}
+
+func TestResolveOnboardingRegisteredUser(t *testing.T) {
+	progress := map[string]models.OnboardingProgress{
+		"camera_permission": {StepVersion: 1, Status: models.OnboardingCompleted},
+		"notifications":     {StepVersion: 1, Status: models.OnboardingSkipped},
+	}
+	resp := resolveOnboarding(onboardingFlow, progress, true, false)
+
+	for _, s := range resp.Steps {
+		if s.ID == "claim_account" {
+			t.Fatal("claim_account is only for guests")
+		}
+	}
+	if !resp.Completed || resp.NextStep != "" {
+		t.Errorf("expected flow completed, next step %q", resp.NextStep)
+	}
+}
+
+func TestResolveOnboardingStaleStepVersion(t *testing.T) {
+	flow := []onboardingStep{onboardingFlow[0]}
+	flow[0].Version = 2
+	resp := resolveOnboarding(flow, map[string]models.OnboardingProgress{
+		"camera_permission": {StepVersion: 1, Status: models.OnboardingCompleted},
+	}, false, false)
+
+	if resp.Steps[0].Status != "pending" {
+		t.Errorf("progress from an older step version should not count, got %s", resp.Steps[0].Status)
+	}
+}
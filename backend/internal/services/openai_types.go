@@ -26,4 +26,5 @@ type openAIResponse struct {
 	Error *struct {
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
+	Usage aiTokenUsage `json:"usage"`
 }
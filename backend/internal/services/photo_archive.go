@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// photoArchiveBatch bounds the photos handled per worker pass and step.
+const photoArchiveBatch = 100
+
+// Photo restore statuses.
+const (
+	PhotoAvailable = "available"
+	PhotoRestoring = "restoring"
+)
+
+// coldKey is where a photo's original is kept in cold storage.
+func coldKey(photo *models.StoredPhoto) string {
+	return "photos/" + photo.UserID.String() + "/" + filepath.Base(photo.OriginalPath)
+}
+
+// RunPhotoArchiveWorker moves old originals to cold storage, finishes
+// pending restores and removes the cold copies of deleted accounts.
+func (s *PhotoStorageService) RunPhotoArchiveWorker(ctx context.Context, interval time.Duration) {
+	if s.store == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			archived, err := s.ArchiveDue(ctx, time.Now())
+			if err != nil {
+				log.Printf("photo archive worker: archive: %v", err)
+			}
+			restored, err := s.CompleteRestores(ctx)
+			if err != nil {
+				log.Printf("photo archive worker: restore: %v", err)
+			}
+			if err := s.purgeDeleted(ctx); err != nil {
+				log.Printf("photo archive worker: purge: %v", err)
+			}
+			if archived > 0 || restored > 0 {
+				log.Printf("photo archive worker: archived %d, restored %d", archived, restored)
+			}
+		}
+	}
+}
+
+// ArchiveDue moves originals older than PhotoColdAfter to cold storage,
+// and restored originals back once their PhotoRestoreDays are over.
+func (s *PhotoStorageService) ArchiveDue(ctx context.Context, now time.Time) (int, error) {
+	if s.store == nil {
+		return 0, nil
+	}
+	db := s.db.WithContext(ctx)
+	restoredBefore := now.AddDate(0, 0, -s.cfg.PhotoRestoreDays)
+
+	var due []models.StoredPhoto
+	if err := db.Where("created_at < ? AND evicted_at IS NULL AND archived_at IS NULL AND restore_requested_at IS NULL AND purge_requested_at IS NULL AND original_path <> ''", now.Add(-s.cfg.PhotoColdAfter)).
+		Where("restored_at IS NULL OR restored_at < ?", restoredBefore).
+		Order("created_at").
+		Limit(photoArchiveBatch).
+		Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for i := range due {
+		photo := &due[i]
+		key := photo.ColdKey
+		if key == "" {
+			raw, err := os.ReadFile(photo.OriginalPath)
+			if err != nil {
+				log.Printf("photo archive: read %s: %v", photo.ID, err)
+				continue
+			}
+			key = coldKey(photo)
+			if err := s.store.PutCold(ctx, key, raw, photo.ContentType); err != nil {
+				return archived, err
+			}
+		}
+		if err := db.Model(photo).Updates(map[string]interface{}{
+			"cold_key":      key,
+			"archived_at":   now,
+			"original_path": "",
+			"restored_at":   nil,
+		}).Error; err != nil {
+			return archived, err
+		}
+		removePhotoFiles(photo.OriginalPath)
+		archived++
+	}
+	return archived, nil
+}
+
+// RequestRestore asks for a reading's archived original to be brought back.
+// Restores take hours on S3; the photo is served again once the archive
+// worker finds the restore finished.
+func (s *PhotoStorageService) RequestRestore(ctx context.Context, userID, readingID uuid.UUID) (*dto.PhotoRestoreResponse, error) {
+	db := s.db.WithContext(ctx)
+	var photo models.StoredPhoto
+	if err := db.Where("user_id = ? AND reading_id = ?", userID, readingID).First(&photo).Error; err != nil {
+		return nil, ErrPhotoNotFound
+	}
+	if photo.EvictedAt != nil {
+		return nil, ErrPhotoEvicted
+	}
+	if photo.ArchivedAt == nil {
+		return &dto.PhotoRestoreResponse{Status: PhotoAvailable}, nil
+	}
+	if photo.RestoreRequestedAt != nil {
+		return &dto.PhotoRestoreResponse{Status: PhotoRestoring, RequestedAt: photo.RestoreRequestedAt}, nil
+	}
+	if s.store == nil {
+		return nil, ErrPhotoNotFound
+	}
+
+	if err := s.store.Restore(ctx, photo.ColdKey, s.cfg.PhotoRestoreDays); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := db.Model(&photo).Update("restore_requested_at", now).Error; err != nil {
+		return nil, err
+	}
+	return &dto.PhotoRestoreResponse{Status: PhotoRestoring, RequestedAt: &now}, nil
+}
+
+// RequestPhotoRestore asks for a reading's archived original to be restored.
+func (s *AuraService) RequestPhotoRestore(ctx context.Context, userID, readingID uuid.UUID) (*dto.PhotoRestoreResponse, error) {
+	if s.photos == nil {
+		return nil, ErrPhotoNotFound
+	}
+	return s.photos.RequestRestore(ctx, userID, readingID)
+}
+
+// CompleteRestores copies the originals whose restore has finished back to
+// local disk, where they are served until they go cold again.
+func (s *PhotoStorageService) CompleteRestores(ctx context.Context) (int, error) {
+	if s.store == nil {
+		return 0, nil
+	}
+	db := s.db.WithContext(ctx)
+
+	var pending []models.StoredPhoto
+	if err := db.Where("restore_requested_at IS NOT NULL AND purge_requested_at IS NULL").
+		Order("restore_requested_at").
+		Limit(photoArchiveBatch).
+		Find(&pending).Error; err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for i := range pending {
+		photo := &pending[i]
+		raw, err := s.store.Get(ctx, photo.ColdKey)
+		if errors.Is(err, ErrObjectNotRestored) {
+			continue
+		}
+		if err != nil {
+			return restored, err
+		}
+		dir := filepath.Join(s.cfg.PhotoDir, photo.UserID.String())
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return restored, err
+		}
+		target := filepath.Join(dir, filepath.Base(photo.ColdKey))
+		if err := os.WriteFile(target, raw, 0o600); err != nil {
+			return restored, err
+		}
+		if err := db.Model(photo).Updates(map[string]interface{}{
+			"original_path":        target,
+			"archived_at":          nil,
+			"restore_requested_at": nil,
+			"restored_at":          time.Now(),
+		}).Error; err != nil {
+			removePhotoFiles(target)
+			return restored, err
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// purgeDeleted removes the cold copies left by deleted accounts, then their
+// records.
+func (s *PhotoStorageService) purgeDeleted(ctx context.Context) error {
+	db := s.db.WithContext(ctx)
+	var purge []models.StoredPhoto
+	if err := db.Where("purge_requested_at IS NOT NULL").Limit(photoArchiveBatch).Find(&purge).Error; err != nil {
+		return err
+	}
+	for i := range purge {
+		if err := s.store.Delete(ctx, purge[i].ColdKey); err != nil {
+			return err
+		}
+		if err := db.Delete(&purge[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteCold removes a photo's cold copy, if it has one.
+func (s *PhotoStorageService) deleteCold(key string) {
+	if key == "" || s.store == nil {
+		return
+	}
+	if err := s.store.Delete(context.Background(), key); err != nil {
+		log.Printf("photos: delete cold copy %s: %v", key, err)
+	}
+}
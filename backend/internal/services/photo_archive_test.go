@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestDirColdStoreRoundTrip(t *testing.T) {
+	store := &dirObjectStore{dir: t.TempDir()}
+	ctx := context.Background()
+	key := "photos/u/r.jpg"
+
+	if err := store.PutCold(ctx, key, []byte("jpeg"), "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Restore(ctx, key, 7); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := store.Get(ctx, key)
+	if err != nil || string(raw) != "jpeg" {
+		t.Fatalf("get = %q, %v", raw, err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("deleting a missing object: %v", err)
+	}
+}
+
+func TestPhotoStorageColdStore(t *testing.T) {
+	store := &dirObjectStore{dir: t.TempDir()}
+	if s := NewPhotoStorageService(nil, &config.Config{PhotoColdAfter: 0}, store); s.store != nil {
+		t.Error("cold storage enabled with PHOTO_COLD_AFTER=0")
+	}
+	if s := NewPhotoStorageService(nil, &config.Config{PhotoColdAfter: 1}, nil); s.store != nil {
+		t.Error("cold storage enabled without an object store")
+	}
+	if s := NewPhotoStorageService(nil, &config.Config{PhotoColdAfter: 1}, store); s.store == nil {
+		t.Error("cold storage not enabled")
+	}
+}
+
+func TestColdKey(t *testing.T) {
+	userID, readingID := uuid.New(), uuid.New()
+	photo := &models.StoredPhoto{UserID: userID, OriginalPath: "/data/photos/" + userID.String() + "/" + readingID.String() + ".png"}
+	if got, want := coldKey(photo), "photos/"+userID.String()+"/"+readingID.String()+".png"; got != want {
+		t.Errorf("coldKey = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/imageproc"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// thumbnailMaxSide is the longer side of stored thumbnails, in pixels.
+const thumbnailMaxSide = 320
+
+var (
+	ErrPhotoNotFound = errors.New("photo not found")
+	ErrPhotoEvicted  = errors.New("the original photo was removed to stay within your plan's storage; the thumbnail is still available")
+	ErrPhotoArchived = errors.New("the original photo is in cold storage; request a restore to view it again")
+)
+
+// PhotoStorageService keeps scan photos on local disk, moves old originals
+// to cold storage and enforces the per-plan photo quota.
+type PhotoStorageService struct {
+	db    *gorm.DB
+	cfg   *config.Config
+	store ColdStore
+}
+
+// NewPhotoStorageService builds the photo store. Cold storage is used when
+// store is a ColdStore and PHOTO_COLD_AFTER is set.
+func NewPhotoStorageService(db *gorm.DB, cfg *config.Config, store ObjectStore) *PhotoStorageService {
+	s := &PhotoStorageService{db: db, cfg: cfg}
+	if cold, ok := store.(ColdStore); ok && cfg.PhotoColdAfter > 0 {
+		s.store = cold
+	}
+	return s
+}
+
+// Store saves a reading's original photo and a thumbnail, then evicts the
+// user's oldest originals beyond their plan's quota.
+func (s *PhotoStorageService) Store(ctx context.Context, userID, readingID uuid.UUID, raw []byte) error {
+	ctx, span := tracer.Start(ctx, "PhotoStorageService.Store")
+	defer span.End()
+	db := s.db.WithContext(ctx)
+
+	contentType := http.DetectContentType(raw)
+	ext := map[string]string{"image/jpeg": ".jpg", "image/png": ".png"}[contentType]
+	if ext == "" {
+		return imageproc.ErrUnsupportedImage
+	}
+	img, err := imageproc.Decode(raw)
+	if err != nil {
+		return err
+	}
+	thumb, err := imageproc.Thumbnail(img, thumbnailMaxSide)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.cfg.PhotoDir, userID.String())
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	photo := models.StoredPhoto{
+		UserID:         userID,
+		ReadingID:      readingID,
+		ContentType:    contentType,
+		OriginalPath:   filepath.Join(dir, readingID.String()+ext),
+		OriginalBytes:  int64(len(raw)),
+		ThumbnailPath:  filepath.Join(dir, readingID.String()+"_thumb.jpg"),
+		ThumbnailBytes: int64(len(thumb)),
+	}
+	if err := os.WriteFile(photo.OriginalPath, raw, 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(photo.ThumbnailPath, thumb, 0o600); err != nil {
+		removePhotoFiles(photo.OriginalPath)
+		return err
+	}
+	if err := db.Create(&photo).Error; err != nil {
+		removePhotoFiles(photo.OriginalPath, photo.ThumbnailPath)
+		return err
+	}
+
+	if _, err := s.enforceQuota(db, userID); err != nil {
+		log.Printf("photo quota for %s: %v", userID, err)
+	}
+	return nil
+}
+
+// photoLimit is how many originals the user's plan keeps; 0 means unlimited.
+func (s *PhotoStorageService) photoLimit(db *gorm.DB, userID uuid.UUID) int {
+	if hasActiveSubscription(db, userID) || s.cfg.FreePhotoLimit <= 0 {
+		return 0
+	}
+	return s.cfg.FreePhotoLimit
+}
+
+// enforceQuota evicts the originals beyond the newest photoLimit, so a
+// lapsed subscription is brought back within quota on the next scan.
+func (s *PhotoStorageService) enforceQuota(db *gorm.DB, userID uuid.UUID) (int, error) {
+	limit := s.photoLimit(db, userID)
+	if limit == 0 {
+		return 0, nil
+	}
+
+	var over []models.StoredPhoto
+	if err := db.Where("user_id = ? AND evicted_at IS NULL", userID).
+		Order("created_at DESC").
+		Offset(limit).
+		Find(&over).Error; err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	for i := range over {
+		removePhotoFiles(over[i].OriginalPath)
+		s.deleteCold(over[i].ColdKey)
+		if err := db.Model(&over[i]).Updates(map[string]interface{}{
+			"original_path":        "",
+			"evicted_at":           now,
+			"cold_key":             "",
+			"archived_at":          nil,
+			"restore_requested_at": nil,
+		}).Error; err != nil {
+			return i, err
+		}
+	}
+	return len(over), nil
+}
+
+// Photo returns the path and content type of a reading's original photo, or
+// of its thumbnail.
+func (s *PhotoStorageService) Photo(userID, readingID uuid.UUID, thumbnail bool) (string, string, error) {
+	var photo models.StoredPhoto
+	if err := s.db.Where("user_id = ? AND reading_id = ?", userID, readingID).First(&photo).Error; err != nil {
+		return "", "", ErrPhotoNotFound
+	}
+	if thumbnail {
+		return photo.ThumbnailPath, "image/jpeg", nil
+	}
+	if photo.EvictedAt != nil {
+		return "", "", ErrPhotoEvicted
+	}
+	if photo.ArchivedAt != nil {
+		return "", "", ErrPhotoArchived
+	}
+	return photo.OriginalPath, photo.ContentType, nil
+}
+
+// Photo returns a reading's stored photo (or its thumbnail).
+func (s *AuraService) Photo(userID, readingID uuid.UUID, thumbnail bool) (string, string, error) {
+	if s.photos == nil {
+		return "", "", ErrPhotoNotFound
+	}
+	return s.photos.Photo(userID, readingID, thumbnail)
+}
+
+// Usage reports the user's plan and stored-photo bytes.
+func (s *PhotoStorageService) Usage(userID uuid.UUID) (*dto.UsageResponse, error) {
+	var totals struct {
+		OriginalBytes  int64
+		ThumbnailBytes int64
+		Photos         int64
+		Evicted        int64
+	}
+	if err := s.db.Model(&models.StoredPhoto{}).
+		Select("COALESCE(SUM(original_bytes) FILTER (WHERE evicted_at IS NULL), 0) AS original_bytes, "+
+			"COALESCE(SUM(thumbnail_bytes), 0) AS thumbnail_bytes, "+
+			"COUNT(*) FILTER (WHERE evicted_at IS NULL) AS photos, "+
+			"COUNT(*) FILTER (WHERE evicted_at IS NOT NULL) AS evicted").
+		Where("user_id = ?", userID).
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	resp := &dto.UsageResponse{
+		Plan: "free",
+		Storage: dto.StorageUsage{
+			Bytes:          totals.OriginalBytes + totals.ThumbnailBytes,
+			OriginalBytes:  totals.OriginalBytes,
+			ThumbnailBytes: totals.ThumbnailBytes,
+			Photos:         totals.Photos,
+			EvictedPhotos:  totals.Evicted,
+		},
+	}
+	if hasActiveSubscription(s.db, userID) {
+		resp.Plan = "premium"
+	}
+	if limit := s.photoLimit(s.db, userID); limit > 0 {
+		resp.Storage.PhotoLimit = &limit
+	}
+	return resp, nil
+}
+
+// Remove deletes a reading's photo files and record.
+func (s *PhotoStorageService) Remove(userID, readingID uuid.UUID) error {
+	var photo models.StoredPhoto
+	if err := s.db.Where("user_id = ? AND reading_id = ?", userID, readingID).First(&photo).Error; err != nil {
+		return nil
+	}
+	removePhotoFiles(photo.OriginalPath, photo.ThumbnailPath)
+	s.deleteCold(photo.ColdKey)
+	return s.db.Delete(&photo).Error
+}
+
+// DeleteUserPhotos removes every stored photo of a user, files included.
+// Records with a cold copy are kept, marked for the archive worker to
+// delete the copy, since the object store is not reachable from here.
+func DeleteUserPhotos(tx *gorm.DB, userID uuid.UUID) error {
+	var photos []models.StoredPhoto
+	tx.Where("user_id = ?", userID).Find(&photos)
+	for _, p := range photos {
+		removePhotoFiles(p.OriginalPath, p.ThumbnailPath)
+	}
+	if err := tx.Model(&models.StoredPhoto{}).
+		Where("user_id = ? AND cold_key <> ''", userID).
+		Updates(map[string]interface{}{
+			"original_path":      "",
+			"thumbnail_path":     "",
+			"purge_requested_at": time.Now(),
+		}).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ? AND (cold_key IS NULL OR cold_key = '')", userID).Delete(&models.StoredPhoto{}).Error
+}
+
+func removePhotoFiles(paths ...string) {
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("photos: remove %s: %v", path, err)
+		}
+	}
+}
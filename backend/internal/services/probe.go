@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	_ "embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// probeImage is the bundled scan the synthetic probe submits.
+//
+//go:embed assets/probe.png
+var probeImage []byte
+
+// probeEmail identifies the internal probe user. The .invalid domain never
+// receives mail and the password hash matches no password, so nobody can
+// sign in as it.
+const probeEmail = "probe@synthetic.aurasnap.invalid"
+
+const probeTimeout = 2 * time.Minute
+
+type syntheticScanKey struct{}
+
+// withSyntheticScan marks scans made under ctx as probe scans: they skip
+// face detection, photo storage, analytics and scan metrics, and the
+// reading is flagged Synthetic so it stays out of every stat.
+func withSyntheticScan(ctx context.Context) context.Context {
+	return context.WithValue(ctx, syntheticScanKey{}, true)
+}
+
+func isSyntheticScan(ctx context.Context) bool {
+	v, _ := ctx.Value(syntheticScanKey{}).(bool)
+	return v
+}
+
+// probeError is a failed probe run and the pipeline stage it failed at.
+type probeError struct {
+	stage string
+	err   error
+}
+
+func (e *probeError) Error() string { return e.stage + ": " + e.err.Error() }
+
+func (e *probeError) Unwrap() error { return e.err }
+
+// ProbeService runs the synthetic monitoring scan: a real scan of a bundled
+// image through the production pipeline, as the internal probe user.
+type ProbeService struct {
+	db   *gorm.DB
+	cfg  *config.Config
+	aura *AuraService
+}
+
+func NewProbeService(db *gorm.DB, cfg *config.Config, aura *AuraService) *ProbeService {
+	return &ProbeService{db: db, cfg: cfg, aura: aura}
+}
+
+// RunProbeWorker runs the probe every interval until ctx is cancelled.
+func (s *ProbeService) RunProbeWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Probe(ctx); err != nil {
+				log.Printf("probe: %v", err)
+			}
+		}
+	}
+}
+
+// Probe scans the bundled image, checks the reading and deletes it again.
+// The outcome is reported through the synthetic probe metrics, so alerts
+// fire on a failing or stale probe rather than on log lines.
+func (s *ProbeService) Probe(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := s.probe(ctx)
+	metrics.SyntheticProbeDuration.Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		stage := "error"
+		var pe *probeError
+		if errors.As(err, &pe) {
+			stage = pe.stage
+		}
+		metrics.SyntheticProbeTotal.WithLabelValues(stage).Inc()
+		metrics.SyntheticProbeUp.Set(0)
+		return err
+	}
+	metrics.SyntheticProbeTotal.WithLabelValues("success").Inc()
+	metrics.SyntheticProbeUp.Set(1)
+	metrics.SyntheticProbeLastSuccess.Set(float64(time.Now().Unix()))
+	return nil
+}
+
+func (s *ProbeService) probe(ctx context.Context) error {
+	user, err := s.probeUser()
+	if err != nil {
+		return &probeError{stage: "user", err: err}
+	}
+
+	reading, err := s.aura.Create(withSyntheticScan(ctx), user.ID, dto.CreateAuraRequest{
+		ImageData: base64.StdEncoding.EncodeToString(probeImage),
+	})
+	if err != nil {
+		return &probeError{stage: "scan", err: err}
+	}
+	// Probe readings are never kept, whatever the check says.
+	defer func() {
+		if err := s.db.Unscoped().Delete(reading).Error; err != nil {
+			log.Printf("probe: delete reading %s: %v", reading.ID, err)
+		}
+	}()
+
+	return checkProbeReading(reading, s.aura.analyzer != nil && len(s.aura.analyzer.providers) > 0)
+}
+
+// probeUser returns the probe user, creating it on first use.
+func (s *ProbeService) probeUser() (*models.User, error) {
+	user := models.User{Email: probeEmail, Password: "!"}
+	if err := s.db.Where(models.User{Email: probeEmail}).FirstOrCreate(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// checkProbeReading verifies a probe reading is complete. With AI providers
+// configured, a reading made entirely by the deterministic fallback counts
+// as a failure: every provider is down.
+func checkProbeReading(reading *models.AuraReading, wantAI bool) error {
+	switch {
+	case reading.Status != models.ReadingStatusReady:
+		return &probeError{stage: "status", err: fmt.Errorf("reading is %q", reading.Status)}
+	case normalizeAuraColor(reading.AuraColor) == "":
+		return &probeError{stage: "result", err: fmt.Errorf("unknown aura color %q", reading.AuraColor)}
+	case reading.EnergyLevel < 1 || reading.EnergyLevel > 100:
+		return &probeError{stage: "result", err: fmt.Errorf("energy level %d out of range", reading.EnergyLevel)}
+	case strings.TrimSpace(reading.Personality) == "":
+		return &probeError{stage: "result", err: errors.New("empty personality")}
+	}
+	if wantAI {
+		for _, source := range reading.Provenance {
+			if source != provenanceDeterministic {
+				return nil
+			}
+		}
+		return &probeError{stage: "ai_fallback", err: errors.New("every field fell back to the deterministic engine")}
+	}
+	return nil
+}
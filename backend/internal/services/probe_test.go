@@ -0,0 +1,64 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestProbeImageDecodes(t *testing.T) {
+	if _, err := png.Decode(bytes.NewReader(probeImage)); err != nil {
+		t.Fatalf("bundled probe image: %v", err)
+	}
+}
+
+func TestSyntheticScanContext(t *testing.T) {
+	if isSyntheticScan(context.Background()) {
+		t.Fatal("plain context reported as synthetic")
+	}
+	if !isSyntheticScan(withSyntheticScan(context.Background())) {
+		t.Fatal("synthetic context not detected")
+	}
+}
+
+func TestCheckProbeReading(t *testing.T) {
+	ok := func() *models.AuraReading {
+		return &models.AuraReading{
+			Status:      models.ReadingStatusReady,
+			AuraColor:   "violet",
+			EnergyLevel: 72,
+			Personality: "Calm and curious.",
+			Provenance:  map[string]string{"personality": "openai", "strengths": provenanceDeterministic},
+		}
+	}
+
+	if err := checkProbeReading(ok(), true); err != nil {
+		t.Fatalf("complete reading: %v", err)
+	}
+
+	cases := map[string]func(*models.AuraReading){
+		"status": func(r *models.AuraReading) { r.Status = models.ReadingStatusPending },
+		"result": func(r *models.AuraReading) { r.Personality = " " },
+		"ai_fallback": func(r *models.AuraReading) {
+			r.Provenance = map[string]string{"personality": provenanceDeterministic}
+		},
+	}
+	for stage, mutate := range cases {
+		r := ok()
+		mutate(r)
+		var pe *probeError
+		if err := checkProbeReading(r, true); !errors.As(err, &pe) || pe.stage != stage {
+			t.Errorf("%s: got %v", stage, err)
+		}
+	}
+
+	fallback := ok()
+	fallback.Provenance = map[string]string{"personality": provenanceDeterministic}
+	if err := checkProbeReading(fallback, false); err != nil {
+		t.Fatalf("fallback without providers should pass: %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrEventQuarantined       = errors.New("event is quarantined")
+	ErrProcessedEventNotFound = errors.New("processed event not found")
+	ErrEventNotQuarantined    = errors.New("only quarantined events can be released")
+)
+
+const (
+	// eventMaxFailures is how many times an event may fail before it is
+	// quarantined as poison and skipped until an admin releases it.
+	eventMaxFailures = 5
+	// eventRetention is how long processed markers are kept; providers stop
+	// redelivering long before this.
+	eventRetention = 90 * 24 * time.Hour
+)
+
+// consumeOnce applies an event exactly once per (source, eventID). fn runs
+// in a transaction that also marks the event processed, so the effect and
+// the marker commit together and a redelivery reports duplicate instead of
+// running fn again. Failures are counted outside the rolled-back
+// transaction; after eventMaxFailures the event is quarantined with its
+// payload and ErrEventQuarantined is returned until it is released. Events
+// without an ID cannot be deduplicated and are simply applied.
+func consumeOnce(db *gorm.DB, source, eventID, payload string, fn func(tx *gorm.DB) error) (bool, error) {
+	if eventID == "" {
+		return false, db.Transaction(fn)
+	}
+	eventID = truncateRunes(eventID, 255)
+
+	marker := models.ProcessedEvent{Source: source, EventID: eventID, Status: models.EventPending}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&marker).Error; err != nil {
+		return false, err
+	}
+
+	duplicate := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// The row lock serializes concurrent deliveries of the same event.
+		var event models.ProcessedEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("source = ? AND event_id = ?", source, eventID).
+			First(&event).Error; err != nil {
+			return err
+		}
+		switch event.Status {
+		case models.EventProcessed:
+			duplicate = true
+			return nil
+		case models.EventQuarantined:
+			return ErrEventQuarantined
+		}
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return tx.Model(&event).Updates(map[string]interface{}{
+			"status":       models.EventProcessed,
+			"attempts":     event.Attempts + 1,
+			"last_error":   "",
+			"payload":      "",
+			"processed_at": time.Now(),
+		}).Error
+	})
+	if err == nil || errors.Is(err, ErrEventQuarantined) {
+		return duplicate, err
+	}
+
+	quarantined, recordErr := recordEventFailure(db, source, eventID, payload, err)
+	if recordErr != nil {
+		log.Printf("events: recording failure of %s/%s: %v", source, eventID, recordErr)
+	}
+	if quarantined {
+		return false, fmt.Errorf("%w: %v", ErrEventQuarantined, err)
+	}
+	return false, err
+}
+
+// recordEventFailure counts a failed attempt and quarantines the event once
+// it has failed eventMaxFailures times. It reports whether it was
+// quarantined by this failure.
+func recordEventFailure(db *gorm.DB, source, eventID, payload string, cause error) (bool, error) {
+	var event models.ProcessedEvent
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("source = ? AND event_id = ? AND status = ?", source, eventID, models.EventPending).
+			First(&event).Error; err != nil {
+			return err
+		}
+		event.Attempts++
+		updates := map[string]interface{}{
+			"attempts":   event.Attempts,
+			"last_error": truncateRunes(cause.Error(), 1000),
+		}
+		if event.Attempts >= eventMaxFailures {
+			event.Status = models.EventQuarantined
+			updates["status"] = models.EventQuarantined
+			updates["payload"] = payload
+			updates["quarantined_at"] = time.Now()
+		}
+		return tx.Model(&event).Updates(updates).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return event.Status == models.EventQuarantined, err
+}
+
+// releaseEvent lets a quarantined event be consumed again with a fresh set
+// of attempts.
+func releaseEvent(db *gorm.DB, source, eventID string) error {
+	return db.Model(&models.ProcessedEvent{}).
+		Where("source = ? AND event_id = ? AND status = ?", source, truncateRunes(eventID, 255), models.EventQuarantined).
+		Updates(map[string]interface{}{
+			"status":         models.EventPending,
+			"attempts":       0,
+			"quarantined_at": nil,
+		}).Error
+}
+
+// ProcessedEventService gives admins visibility into consumed and
+// quarantined events.
+type ProcessedEventService struct {
+	db *gorm.DB
+}
+
+func NewProcessedEventService(db *gorm.DB) *ProcessedEventService {
+	return &ProcessedEventService{db: db}
+}
+
+// List returns events, most recently updated first, optionally filtered by
+// source and status (e.g. quarantined).
+func (s *ProcessedEventService) List(source, status string, limit, offset int) ([]models.ProcessedEvent, int64, error) {
+	var events []models.ProcessedEvent
+	var total int64
+
+	query := s.db.Model(&models.ProcessedEvent{})
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&total)
+
+	if err := query.Order("updated_at DESC").Limit(limit).Offset(offset).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// eventReplayers re-apply a released event from its stored payload, for
+// sources whose sender will not redeliver once the event was acknowledged.
+var eventReplayers = map[string]func(tx *gorm.DB, payload string) error{
+	models.EventSourceSES: func(tx *gorm.DB, payload string) error {
+		var n dto.SESNotification
+		if err := json.Unmarshal([]byte(payload), &n); err != nil {
+			return err
+		}
+		_, err := applySESNotification(tx, &n)
+		return err
+	},
+}
+
+// Release takes an event out of quarantine. A dead-lettered webhook for it
+// is requeued for the webhook worker; events of other sources are
+// re-applied from the stored payload where a replayer exists.
+func (s *ProcessedEventService) Release(id uuid.UUID) (*models.ProcessedEvent, error) {
+	var event models.ProcessedEvent
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, "id = ?", id).Error; err != nil {
+			return ErrProcessedEventNotFound
+		}
+		if event.Status != models.EventQuarantined {
+			return ErrEventNotQuarantined
+		}
+		if err := releaseEvent(tx, event.Source, event.EventID); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.WebhookEvent{}).
+			Where("provider = ? AND event_id = ? AND status = ?", event.Source, event.EventID, models.WebhookDead).
+			Updates(map[string]interface{}{
+				"status":          models.WebhookPending,
+				"attempts":        0,
+				"next_attempt_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if replay, ok := eventReplayers[event.Source]; ok && event.Payload != "" {
+		if _, err := consumeOnce(s.db, event.Source, event.EventID, event.Payload, func(tx *gorm.DB) error {
+			return replay(tx, event.Payload)
+		}); err != nil {
+			log.Printf("events: replaying %s/%s: %v", event.Source, event.EventID, err)
+		}
+	}
+
+	if err := s.db.First(&event, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// pruneProcessedEvents drops processed markers older than eventRetention.
+func pruneProcessedEvents(db *gorm.DB) error {
+	return db.Where("status = ? AND processed_at < ?", models.EventProcessed, time.Now().Add(-eventRetention)).
+		Delete(&models.ProcessedEvent{}).Error
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestSESReplayerRejectsInvalidPayload(t *testing.T) {
+	replay, ok := eventReplayers[models.EventSourceSES]
+	if !ok {
+		t.Fatal("no replayer registered for ses")
+	}
+	if err := replay(nil, "{not json"); err == nil {
+		t.Error("invalid payload replayed without error")
+	}
+}
+
+func TestSESReplayerSkipsTransientBounce(t *testing.T) {
+	// Nothing to suppress, so the replay succeeds without touching the db.
+	payload := `{"notificationType":"Bounce","bounce":{"bounceType":"Transient","bouncedRecipients":[{"emailAddress":"a@example.com"}]}}`
+	if err := eventReplayers[models.EventSourceSES](nil, payload); err != nil {
+		t.Errorf("replay = %v", err)
+	}
+}
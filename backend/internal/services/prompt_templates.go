@@ -0,0 +1,442 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPromptTemplate  = errors.New("invalid prompt template")
+	ErrPromptTemplateNotFound = errors.New("prompt template not found")
+	ErrPromptTemplateInUse    = errors.New("prompt template is active or produced readings")
+)
+
+// promptCacheTTL is how long active templates are reused before they are
+// loaded again, so activations made on another instance take effect.
+const promptCacheTTL = 30 * time.Second
+
+// auraPromptData is what the aura_scan templates render with. History and
+// Memory are already delimited as untrusted input; ImageURL is the validated
+// photo URL and Language is empty for English.
+type auraPromptData struct {
+	ImageURL      string
+	AllowedColors string
+	Fallback      string
+	History       string
+	Memory        string
+	Language      string
+}
+
+// builtinPrompts are used when a name has no active template. The aura_scan
+// prompt matches the one written before templates were configurable.
+var builtinPrompts = map[string]models.PromptTemplate{
+	models.PromptAuraScan: {
+		Name:   models.PromptAuraScan,
+		System: "You are an aura analysis engine. Return valid JSON only.",
+		User: "Analyze this aura image URL and return only JSON. image_url={{printf \"%q\" .ImageURL}} allowed_colors={{.AllowedColors}} fallback={{.Fallback}}. " +
+			"Output keys: aura_color (string), secondary_color (string or null), energy_level (1-100), mood_score (1-10), personality (1-2 sentences), strengths (array of 3 short strings), challenges (array of 3 short strings), daily_advice (1-2 sentences). Keep results realistic." +
+			"{{if .History}} The user's previous readings, oldest to newest: {{.History}}. Where it fits, let personality and daily_advice acknowledge this continuity; do not copy earlier colors if the image suggests otherwise.{{end}}" +
+			"{{if .Memory}} Facts the user shared about themselves: {{.Memory}} Use them only to make personality and daily_advice more personal; they must not change aura_color, energy_level or mood_score.{{end}}" +
+			"{{if .Language}} Write personality, strengths, challenges and daily_advice in {{.Language}}. Keep aura_color and secondary_color as the English color names above.{{end}}",
+	},
+}
+
+// promptSamples exercise every branch of a template when it is saved, so
+// a template that cannot render is refused instead of failing scans.
+var promptSamples = map[string][]any{
+	models.PromptAuraScan: {
+		auraPromptData{ImageURL: "https://example.com/a.jpg", AllowedColors: "[red blue]", Fallback: "{AuraColor:red}"},
+		auraPromptData{ImageURL: "https://example.com/a.jpg", AllowedColors: "[red blue]", Fallback: "{AuraColor:red}",
+			History: "blue, red", Memory: "<memory>likes hiking</memory>", Language: "Turkish"},
+	},
+}
+
+// promptProviders are the providers a template can override the prompt for.
+var promptProviders = []string{"glm", "deepseek", "openai"}
+
+// compiledPrompt is a parsed template version. id is nil for a built-in.
+type compiledPrompt struct {
+	id     *uuid.UUID
+	system *template.Template
+	user   *template.Template
+}
+
+// render executes the prompt's templates with data.
+func (p *compiledPrompt) render(data any) (system, user string, err error) {
+	var sys, usr strings.Builder
+	if err := p.system.Execute(&sys, data); err != nil {
+		return "", "", err
+	}
+	if err := p.user.Execute(&usr, data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(sys.String()), usr.String(), nil
+}
+
+func compilePrompt(t models.PromptTemplate) (*compiledPrompt, error) {
+	system, err := template.New("system").Option("missingkey=error").Parse(t.System)
+	if err != nil {
+		return nil, fmt.Errorf("system: %w", err)
+	}
+	user, err := template.New("user").Option("missingkey=error").Parse(t.User)
+	if err != nil {
+		return nil, fmt.Errorf("user: %w", err)
+	}
+	p := &compiledPrompt{system: system, user: user}
+	if t.ID != uuid.Nil {
+		id := t.ID
+		p.id = &id
+	}
+	return p, nil
+}
+
+type promptKey struct{ name, provider string }
+
+// PromptTemplateService keeps the AI prompts in the database, so they can
+// be changed and compared without a deploy. A nil *PromptTemplateService
+// serves the built-in prompts.
+type PromptTemplateService struct {
+	db *gorm.DB
+
+	mu       sync.Mutex
+	active   map[promptKey]*compiledPrompt
+	loadedAt time.Time
+	// byID caches pinned versions; versions never change once saved.
+	byID map[uuid.UUID]*compiledPrompt
+}
+
+func NewPromptTemplateService(db *gorm.DB) *PromptTemplateService {
+	return &PromptTemplateService{db: db}
+}
+
+// builtinCompiled holds the built-in prompts, parsed once.
+var builtinCompiled = func() map[string]*compiledPrompt {
+	out := make(map[string]*compiledPrompt, len(builtinPrompts))
+	for name, t := range builtinPrompts {
+		p, err := compilePrompt(t)
+		if err != nil {
+			panic(fmt.Sprintf("built-in %s prompt: %v", name, err))
+		}
+		out[name] = p
+	}
+	return out
+}()
+
+// resolve returns the prompt to use for name with provider: the provider's
+// active override, then the active all-providers version, then the
+// built-in. When templates cannot be loaded the last loaded set is kept.
+func (s *PromptTemplateService) resolve(ctx context.Context, name, provider string) *compiledPrompt {
+	if s == nil {
+		return builtinCompiled[name]
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active == nil || time.Since(s.loadedAt) >= promptCacheTTL {
+		if err := s.load(ctx); err != nil {
+			log.Printf("prompt templates: load active templates: %v", err)
+		}
+	}
+	if p, ok := s.active[promptKey{name, provider}]; ok {
+		return p
+	}
+	if p, ok := s.active[promptKey{name, ""}]; ok {
+		return p
+	}
+	return builtinCompiled[name]
+}
+
+// pinned returns a specific version, active or not, for experiments that
+// compare versions side by side. It returns nil when the version cannot be
+// loaded, so the caller can fall back to resolve.
+func (s *PromptTemplateService) pinned(ctx context.Context, id uuid.UUID) *compiledPrompt {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.byID[id]; ok {
+		return p
+	}
+	var t models.PromptTemplate
+	if err := s.db.WithContext(ctx).First(&t, "id = ?", id).Error; err != nil {
+		log.Printf("prompt templates: load pinned template %s: %v", id, err)
+		return nil
+	}
+	p, err := compilePrompt(t)
+	if err != nil {
+		log.Printf("prompt templates: pinned template %s does not parse: %v", id, err)
+		return nil
+	}
+	if s.byID == nil {
+		s.byID = make(map[uuid.UUID]*compiledPrompt)
+	}
+	s.byID[id] = p
+	return p
+}
+
+// load reads every active template. s.mu must be held.
+func (s *PromptTemplateService) load(ctx context.Context) error {
+	s.loadedAt = time.Now()
+	var rows []models.PromptTemplate
+	if err := s.db.WithContext(ctx).Where("active").Find(&rows).Error; err != nil {
+		if s.active == nil {
+			s.active = map[promptKey]*compiledPrompt{}
+		}
+		return err
+	}
+	active := make(map[promptKey]*compiledPrompt, len(rows))
+	for _, row := range rows {
+		p, err := compilePrompt(row)
+		if err != nil {
+			log.Printf("prompt templates: %s v%d does not parse, using the fallback: %v", row.Name, row.Version, err)
+			continue
+		}
+		active[promptKey{row.Name, row.Provider}] = p
+	}
+	s.active = active
+	return nil
+}
+
+// invalidate makes the next resolve load the templates again.
+func (s *PromptTemplateService) invalidate() {
+	s.mu.Lock()
+	s.active = nil
+	s.mu.Unlock()
+}
+
+// validatePromptTemplate refuses templates with an unknown name or
+// provider, and templates that fail to parse or render.
+func validatePromptTemplate(t models.PromptTemplate) error {
+	samples, ok := promptSamples[t.Name]
+	if !ok {
+		return fmt.Errorf("%w: name must be one of %s", ErrInvalidPromptTemplate, strings.Join(promptTemplateNames(), ", "))
+	}
+	if t.Provider != "" && !slices.Contains(promptProviders, t.Provider) {
+		return fmt.Errorf("%w: provider must be empty or one of %s", ErrInvalidPromptTemplate, strings.Join(promptProviders, ", "))
+	}
+	p, err := compilePrompt(t)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPromptTemplate, err)
+	}
+	for _, data := range samples {
+		system, user, err := p.render(data)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPromptTemplate, err)
+		}
+		if system == "" || strings.TrimSpace(user) == "" {
+			return fmt.Errorf("%w: system and user prompts must not render empty", ErrInvalidPromptTemplate)
+		}
+	}
+	return nil
+}
+
+func promptTemplateNames() []string {
+	names := make([]string, 0, len(builtinPrompts))
+	for name := range builtinPrompts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// List returns the saved versions, newest first, optionally of one name.
+func (s *PromptTemplateService) List(name string) ([]models.PromptTemplate, error) {
+	q := s.db.Order("name, provider, version DESC")
+	if name != "" {
+		q = q.Where("name = ?", name)
+	}
+	templates := []models.PromptTemplate{}
+	err := q.Find(&templates).Error
+	return templates, err
+}
+
+// Get returns one version.
+func (s *PromptTemplateService) Get(id uuid.UUID) (*models.PromptTemplate, error) {
+	var t models.PromptTemplate
+	if err := s.db.First(&t, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPromptTemplateNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Create saves the next version of a template, activating it when asked.
+func (s *PromptTemplateService) Create(adminID uuid.UUID, req *dto.PromptTemplateRequest) (*models.PromptTemplate, error) {
+	t := models.PromptTemplate{
+		Name:      strings.TrimSpace(req.Name),
+		Provider:  strings.ToLower(strings.TrimSpace(req.Provider)),
+		System:    req.System,
+		User:      req.User,
+		Notes:     strings.TrimSpace(req.Notes),
+		CreatedBy: &adminID,
+	}
+	if err := validatePromptTemplate(t); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Versions are numbered per name and provider; the unique index
+		// turns a concurrent save into an error rather than a duplicate.
+		if err := tx.Model(&models.PromptTemplate{}).
+			Select("COALESCE(MAX(version), 0) + 1").
+			Where("name = ? AND provider = ?", t.Name, t.Provider).
+			Scan(&t.Version).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&t).Error; err != nil {
+			return err
+		}
+		if req.Activate {
+			return activatePromptTemplate(tx, &t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if req.Activate {
+		s.invalidate()
+	}
+	return &t, nil
+}
+
+// Activate makes a version live for its name and provider, replacing the
+// version active before it.
+func (s *PromptTemplateService) Activate(id uuid.UUID) (*models.PromptTemplate, error) {
+	var t models.PromptTemplate
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&t, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPromptTemplateNotFound
+			}
+			return err
+		}
+		return activatePromptTemplate(tx, &t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return &t, nil
+}
+
+func activatePromptTemplate(tx *gorm.DB, t *models.PromptTemplate) error {
+	if err := tx.Model(&models.PromptTemplate{}).
+		Where("name = ? AND provider = ? AND active AND id <> ?", t.Name, t.Provider, t.ID).
+		Update("active", false).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	t.Active, t.ActivatedAt = true, &now
+	return tx.Model(t).Updates(map[string]any{"active": true, "activated_at": now}).Error
+}
+
+// Deactivate takes a version out of use. Its name and provider fall back to
+// the all-providers version, then the built-in prompt.
+func (s *PromptTemplateService) Deactivate(id uuid.UUID) (*models.PromptTemplate, error) {
+	t, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(t).Update("active", false).Error; err != nil {
+		return nil, err
+	}
+	t.Active = false
+	s.invalidate()
+	return t, nil
+}
+
+// Delete removes a version that is not active and has produced no readings;
+// versions with readings are kept for comparison.
+func (s *PromptTemplateService) Delete(id uuid.UUID) error {
+	t, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	var readings int64
+	if err := s.db.Model(&models.AuraReading{}).Unscoped().Where("prompt_template_id = ?", id).Count(&readings).Error; err != nil {
+		return err
+	}
+	if t.Active || readings > 0 {
+		return ErrPromptTemplateInUse
+	}
+	return s.db.Delete(t).Error
+}
+
+// Stats compares the readings each aura_scan prompt version produced:
+// volume, self-ratings and share rate. Readings from the built-in prompt,
+// including those made before templates existed, are version 0.
+func (s *PromptTemplateService) Stats() ([]dto.PromptTemplateStats, error) {
+	var rows []struct {
+		PromptTemplateID *uuid.UUID
+		Readings         int64
+		Shared           int64
+		Rated            int64
+		AverageRating    *float64
+	}
+	if err := s.db.Model(&models.AuraReading{}).
+		Select("prompt_template_id, COUNT(*) AS readings, COUNT(*) FILTER (WHERE share_count > 0) AS shared, COUNT(rating) AS rated, AVG(rating) AS average_rating").
+		Where("NOT synthetic AND group_reading_id IS NULL").
+		Group("prompt_template_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		if row.PromptTemplateID != nil {
+			ids = append(ids, *row.PromptTemplateID)
+		}
+	}
+	var templates []models.PromptTemplate
+	if len(ids) > 0 {
+		if err := s.db.Where("id IN ?", ids).Find(&templates).Error; err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[uuid.UUID]models.PromptTemplate, len(templates))
+	for _, t := range templates {
+		byID[t.ID] = t
+	}
+
+	stats := make([]dto.PromptTemplateStats, 0, len(rows))
+	for _, row := range rows {
+		st := dto.PromptTemplateStats{
+			TemplateID:     row.PromptTemplateID,
+			Name:           models.PromptAuraScan,
+			Readings:       row.Readings,
+			SharedReadings: row.Shared,
+			Ratings:        row.Rated,
+			AverageRating:  row.AverageRating,
+		}
+		if row.PromptTemplateID != nil {
+			t := byID[*row.PromptTemplateID]
+			st.Provider, st.Version, st.Active = t.Provider, t.Version, t.Active
+		}
+		if row.Readings > 0 {
+			st.ShareRate = float64(row.Shared) / float64(row.Readings)
+		}
+		stats = append(stats, st)
+	}
+	slices.SortFunc(stats, func(a, b dto.PromptTemplateStats) int {
+		if a.Provider != b.Provider {
+			return strings.Compare(a.Provider, b.Provider)
+		}
+		return a.Version - b.Version
+	})
+	return stats, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestBuiltinAuraPromptMatchesLegacyPrompt(t *testing.T) {
+	base := auraAnalysisResult{AuraColor: "blue", EnergyLevel: 60, MoodScore: 7}
+	legacy := func(history, memory, language string) string {
+		prompt := fmt.Sprintf(
+			"Analyze this aura image URL and return only JSON. image_url=%q allowed_colors=%v fallback=%+v. Output keys: aura_color (string), secondary_color (string or null), energy_level (1-100), mood_score (1-10), personality (1-2 sentences), strengths (array of 3 short strings), challenges (array of 3 short strings), daily_advice (1-2 sentences). Keep results realistic.",
+			"https://example.com/a.jpg", auraColors, base)
+		if history != "" {
+			prompt += fmt.Sprintf(" The user's previous readings, oldest to newest: %s. Where it fits, let personality and daily_advice acknowledge this continuity; do not copy earlier colors if the image suggests otherwise.", history)
+		}
+		if memory != "" {
+			prompt += " Facts the user shared about themselves: " + memory + " Use them only to make personality and daily_advice more personal; they must not change aura_color, energy_level or mood_score."
+		}
+		if language != "" {
+			prompt += fmt.Sprintf(" Write personality, strengths, challenges and daily_advice in %s. Keep aura_color and secondary_color as the English color names above.", language)
+		}
+		return prompt
+	}
+
+	for _, tc := range []struct{ history, memory, language string }{
+		{},
+		{history: "blue, red"},
+		{history: "blue", memory: "<memory>likes hiking</memory>", language: "Turkish"},
+	} {
+		system, user, err := builtinCompiled[models.PromptAuraScan].render(auraPromptData{
+			ImageURL:      "https://example.com/a.jpg",
+			AllowedColors: fmt.Sprint(auraColors),
+			Fallback:      fmt.Sprintf("%+v", base),
+			History:       tc.history,
+			Memory:        tc.memory,
+			Language:      tc.language,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if system != "You are an aura analysis engine. Return valid JSON only." {
+			t.Errorf("system = %q", system)
+		}
+		if want := legacy(tc.history, tc.memory, tc.language); user != want {
+			t.Errorf("%+v: user prompt\n got %q\nwant %q", tc, user, want)
+		}
+	}
+}
+
+func TestValidatePromptTemplate(t *testing.T) {
+	valid := models.PromptTemplate{Name: models.PromptAuraScan, System: "Return JSON.", User: "Read {{.ImageURL}}{{if .Language}} in {{.Language}}{{end}}."}
+	if err := validatePromptTemplate(valid); err != nil {
+		t.Fatalf("valid template refused: %v", err)
+	}
+
+	cases := map[string]func(*models.PromptTemplate){
+		"unknown name":     func(p *models.PromptTemplate) { p.Name = "horoscope" },
+		"unknown provider": func(p *models.PromptTemplate) { p.Provider = "llama" },
+		"parse error":      func(p *models.PromptTemplate) { p.User = "Read {{.ImageURL" },
+		"unknown field":    func(p *models.PromptTemplate) { p.User = "Read {{.Photo}}" },
+		"empty system":     func(p *models.PromptTemplate) { p.System = "{{if .History}}x{{end}}" },
+	}
+	for name, mutate := range cases {
+		tmpl := valid
+		mutate(&tmpl)
+		if err := validatePromptTemplate(tmpl); !errors.Is(err, ErrInvalidPromptTemplate) {
+			t.Errorf("%s: err = %v, want ErrInvalidPromptTemplate", name, err)
+		}
+	}
+}
+
+func TestResolvePromptTemplate(t *testing.T) {
+	var none *PromptTemplateService
+	if got := none.resolve(context.Background(), models.PromptAuraScan, "glm"); got != builtinCompiled[models.PromptAuraScan] {
+		t.Fatal("nil service should serve the built-in prompt")
+	}
+
+	compile := func(user string) *compiledPrompt {
+		p, err := compilePrompt(models.PromptTemplate{ID: uuid.New(), System: "s", User: user})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	shared, openai := compile("shared"), compile("openai")
+	s := &PromptTemplateService{
+		active: map[promptKey]*compiledPrompt{
+			{models.PromptAuraScan, ""}:       shared,
+			{models.PromptAuraScan, "openai"}: openai,
+		},
+		loadedAt: time.Now(),
+	}
+
+	if got := s.resolve(context.Background(), models.PromptAuraScan, "openai"); got != openai {
+		t.Error("the provider's override should win")
+	}
+	if got := s.resolve(context.Background(), models.PromptAuraScan, "glm"); got != shared {
+		t.Error("providers without an override should get the shared template")
+	}
+	if got := s.resolve(context.Background(), "unknown", "glm"); got != nil {
+		t.Error("unknown names have no prompt")
+	}
+	if shared.id == nil || *shared.id == uuid.Nil {
+		t.Error("stored templates should carry their ID for attribution")
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidDevice       = errors.New("platform must be ios or android and token must be set")
+	ErrInvalidDeviceToken  = errors.New("device token is no longer valid")
+	ErrNoNotificationRoute = errors.New("user has no destination on this channel")
+)
+
+// maxDevicesPerUser bounds fan-out; the least recently seen tokens go first.
+const maxDevicesPerUser = 10
+
+const pushSendTimeout = 10 * time.Second
+
+// PushDriver delivers one message to one device token through a provider.
+// It returns ErrInvalidDeviceToken when the provider says the token is dead.
+type PushDriver interface {
+	Provider() string
+	Send(ctx context.Context, token string, msg NotificationMessage) error
+}
+
+// NewPushSender builds the push channel from whichever providers are
+// configured. It returns nil when neither APNs nor FCM is set up.
+func NewPushSender(db *gorm.DB, cfg *config.Config) (NotificationSender, error) {
+	drivers := map[string]PushDriver{}
+	if cfg.APNsKeyFile != "" {
+		apns, err := newAPNsDriver(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("apns: %w", err)
+		}
+		drivers[apns.Provider()] = apns
+	}
+	if cfg.FCMCredentialsFile != "" {
+		fcm, err := newFCMDriver(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("fcm: %w", err)
+		}
+		drivers[fcm.Provider()] = fcm
+	}
+	if len(drivers) == 0 {
+		return nil, nil
+	}
+	return &pushSender{db: db, drivers: drivers}, nil
+}
+
+type pushSender struct {
+	db      *gorm.DB
+	drivers map[string]PushDriver
+}
+
+func (p *pushSender) Channel() string { return ChannelPush }
+
+// Send fans msg out to every registered device of the user. Tokens the
+// provider rejects as dead are removed. It succeeds if any device accepted.
+func (p *pushSender) Send(userID uuid.UUID, msg NotificationMessage) error {
+	providers := make([]string, 0, len(p.drivers))
+	for provider := range p.drivers {
+		providers = append(providers, provider)
+	}
+
+	var devices []models.DeviceToken
+	if err := p.db.Where("user_id = ? AND provider IN ?", userID, providers).Find(&devices).Error; err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return ErrNoNotificationRoute
+	}
+
+	var lastErr error
+	delivered := 0
+	for _, device := range devices {
+		ctx, cancel := context.WithTimeout(context.Background(), pushSendTimeout)
+		err := p.drivers[device.Provider].Send(ctx, device.Token, msg)
+		cancel()
+		switch {
+		case err == nil:
+			delivered++
+		case errors.Is(err, ErrInvalidDeviceToken):
+			p.db.Delete(&models.DeviceToken{}, "id = ?", device.ID)
+		default:
+			log.Printf("push: %s delivery to device %s failed: %v", device.Provider, device.ID, err)
+			lastErr = err
+		}
+	}
+
+	if delivered > 0 {
+		return nil
+	}
+	if lastErr == nil {
+		return ErrNoNotificationRoute
+	}
+	return lastErr
+}
+
+// pushProvider maps the client platform to the provider that serves it.
+func pushProvider(platform string) (string, bool) {
+	switch platform {
+	case "ios":
+		return models.PushProviderAPNs, true
+	case "android":
+		return models.PushProviderFCM, true
+	}
+	return "", false
+}
+
+// RegisterDevice stores a push token for the user. A token already known
+// for another account (shared device, re-login) moves to this user.
+func (s *NotificationService) RegisterDevice(userID uuid.UUID, token, platform, appVersion string) (*models.DeviceToken, error) {
+	token = strings.TrimSpace(token)
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	provider, ok := pushProvider(platform)
+	if token == "" || len(token) > 512 || !ok {
+		return nil, ErrInvalidDevice
+	}
+
+	now := time.Now()
+	device := models.DeviceToken{
+		UserID:     userID,
+		Token:      token,
+		Platform:   platform,
+		Provider:   provider,
+		AppVersion: truncateRunes(strings.TrimSpace(appVersion), 32),
+		LastSeenAt: now,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "token"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "provider", "app_version", "last_seen_at", "updated_at"}),
+		}).Create(&device).Error; err != nil {
+			return err
+		}
+
+		var stale []uuid.UUID
+		tx.Model(&models.DeviceToken{}).Where("user_id = ?", userID).
+			Order("last_seen_at DESC").Offset(maxDevicesPerUser).Pluck("id", &stale)
+		if len(stale) > 0 {
+			return tx.Where("id IN ?", stale).Delete(&models.DeviceToken{}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// UnregisterDevice removes a push token, e.g. on sign-out. Unknown tokens
+// are not an error so clients can retry safely.
+func (s *NotificationService) UnregisterDevice(userID uuid.UUID, token string) error {
+	return s.db.Where("user_id = ? AND token = ?", userID, strings.TrimSpace(token)).
+		Delete(&models.DeviceToken{}).Error
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	apnsProductionHost = "https://api.push.apple.com"
+	apnsSandboxHost    = "https://api.sandbox.push.apple.com"
+	// Apple rejects provider tokens older than an hour and throttles
+	// refreshing more often than every 20 minutes.
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// apnsDriver talks to APNs over HTTP/2 with token-based (.p8) auth.
+type apnsDriver struct {
+	host   string
+	topic  string
+	keyID  string
+	teamID string
+	key    *ecdsa.PrivateKey
+	client *http.Client
+
+	mu        sync.Mutex
+	token     string
+	tokenTime time.Time
+}
+
+func newAPNsDriver(cfg *config.Config) (*apnsDriver, error) {
+	if cfg.APNsKeyID == "" || cfg.APNsTeamID == "" || cfg.APNsTopic == "" {
+		return nil, errors.New("APNS_KEY_ID, APNS_TEAM_ID and APNS_TOPIC are required")
+	}
+	pem, err := os.ReadFile(cfg.APNsKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(pem)
+	if err != nil {
+		return nil, fmt.Errorf("parse key: %w", err)
+	}
+
+	host := apnsSandboxHost
+	if cfg.APNsProduction {
+		host = apnsProductionHost
+	}
+	return &apnsDriver{
+		host:   host,
+		topic:  cfg.APNsTopic,
+		keyID:  cfg.APNsKeyID,
+		teamID: cfg.APNsTeamID,
+		key:    key,
+		client: &http.Client{Timeout: pushSendTimeout},
+	}, nil
+}
+
+func (d *apnsDriver) Provider() string { return models.PushProviderAPNs }
+
+func (d *apnsDriver) providerToken() (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.token != "" && time.Since(d.tokenTime) < apnsTokenTTL {
+		return d.token, nil
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": d.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = d.keyID
+	signed, err := token.SignedString(d.key)
+	if err != nil {
+		return "", err
+	}
+	d.token, d.tokenTime = signed, now
+	return signed, nil
+}
+
+type apnsAlert struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func apnsPayload(msg NotificationMessage) ([]byte, error) {
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": apnsAlert{Title: msg.Title, Body: msg.Body},
+			"sound": "default",
+		},
+	}
+	for k, v := range msg.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+	return json.Marshal(payload)
+}
+
+func (d *apnsDriver) Send(ctx context.Context, deviceToken string, msg NotificationMessage) error {
+	body, err := apnsPayload(msg)
+	if err != nil {
+		return err
+	}
+	auth, err := d.providerToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.host+"/3/device/"+deviceToken, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+auth)
+	req.Header.Set("apns-topic", d.topic)
+	req.Header.Set("apns-push-type", "alert")
+	if msg.Priority == PriorityHigh {
+		req.Header.Set("apns-priority", "10")
+	} else {
+		req.Header.Set("apns-priority", "5")
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return apnsError(resp.StatusCode, respBody)
+}
+
+// apnsError maps an APNs failure to ErrInvalidDeviceToken when the token
+// should be dropped.
+func apnsError(status int, body []byte) error {
+	var result struct {
+		Reason string `json:"reason"`
+	}
+	json.Unmarshal(body, &result)
+
+	switch {
+	case status == http.StatusGone,
+		result.Reason == "BadDeviceToken",
+		result.Reason == "Unregistered",
+		result.Reason == "DeviceTokenNotForTopic":
+		return fmt.Errorf("%w: %s", ErrInvalidDeviceToken, result.Reason)
+	}
+	return fmt.Errorf("apns: status %d: %s", status, result.Reason)
+}
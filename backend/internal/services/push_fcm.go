@@ -0,0 +1,208 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	fcmScope    = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmTokenURI = "https://oauth2.googleapis.com/token"
+	fcmSendURL  = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+)
+
+// fcmDriver sends through the FCM HTTP v1 API, authenticating with a
+// service-account JWT exchanged for a short-lived OAuth access token.
+type fcmDriver struct {
+	projectID   string
+	clientEmail string
+	tokenURI    string
+	key         *rsa.PrivateKey
+	client      *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+type fcmServiceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+func newFCMDriver(cfg *config.Config) (*fcmDriver, error) {
+	raw, err := os.ReadFile(cfg.FCMCredentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	var account fcmServiceAccount
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("parse credentials: %w", err)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+
+	projectID := cfg.FCMProjectID
+	if projectID == "" {
+		projectID = account.ProjectID
+	}
+	if projectID == "" || account.ClientEmail == "" {
+		return nil, errors.New("credentials need client_email and a project id")
+	}
+	tokenURI := account.TokenURI
+	if tokenURI == "" {
+		tokenURI = fcmTokenURI
+	}
+	return &fcmDriver{
+		projectID:   projectID,
+		clientEmail: account.ClientEmail,
+		tokenURI:    tokenURI,
+		key:         key,
+		client:      &http.Client{Timeout: pushSendTimeout},
+	}, nil
+}
+
+func (d *fcmDriver) Provider() string { return models.PushProviderFCM }
+
+func (d *fcmDriver) oauthToken(ctx context.Context) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.accessToken != "" && time.Now().Before(d.expiresAt) {
+		return d.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   d.clientEmail,
+		"scope": fcmScope,
+		"aud":   d.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(d.key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("fcm token exchange: status %d: %s", resp.StatusCode, body)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	d.accessToken = result.AccessToken
+	// Refresh a minute early so in-flight sends never carry an expired token.
+	d.expiresAt = now.Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return d.accessToken, nil
+}
+
+func fcmPayload(token string, msg NotificationMessage) ([]byte, error) {
+	priority := "NORMAL"
+	if msg.Priority == PriorityHigh {
+		priority = "HIGH"
+	}
+	message := map[string]interface{}{
+		"token":        token,
+		"notification": map[string]string{"title": msg.Title, "body": msg.Body},
+		"android":      map[string]string{"priority": priority},
+	}
+	if len(msg.Data) > 0 {
+		message["data"] = msg.Data
+	}
+	return json.Marshal(map[string]interface{}{"message": message})
+}
+
+func (d *fcmDriver) Send(ctx context.Context, deviceToken string, msg NotificationMessage) error {
+	auth, err := d.oauthToken(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := fcmPayload(deviceToken, msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(fcmSendURL, d.projectID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+auth)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fcmError(resp.StatusCode, respBody)
+}
+
+// fcmError maps an FCM v1 failure to ErrInvalidDeviceToken when the token
+// is unregistered or unknown.
+func fcmError(status int, body []byte) error {
+	var result struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	json.Unmarshal(body, &result)
+
+	if result.Error.Status == "NOT_FOUND" {
+		return fmt.Errorf("%w: %s", ErrInvalidDeviceToken, result.Error.Message)
+	}
+	for _, detail := range result.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return fmt.Errorf("%w: %s", ErrInvalidDeviceToken, result.Error.Message)
+		}
+	}
+	return fmt.Errorf("fcm: status %d: %s", status, result.Error.Message)
+}
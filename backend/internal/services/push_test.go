@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAPNsErrorMarksDeadTokens(t *testing.T) {
+	cases := []struct {
+		status int
+		body   string
+		dead   bool
+	}{
+		{410, `{"reason":"Unregistered"}`, true},
+		{400, `{"reason":"BadDeviceToken"}`, true},
+		{400, `{"reason":"DeviceTokenNotForTopic"}`, true},
+		{429, `{"reason":"TooManyRequests"}`, false},
+		{500, ``, false},
+	}
+	for _, tc := range cases {
+		err := apnsError(tc.status, []byte(tc.body))
+		if errors.Is(err, ErrInvalidDeviceToken) != tc.dead {
+			t.Errorf("apnsError(%d, %s) = %v, dead want %v", tc.status, tc.body, err, tc.dead)
+		}
+	}
+}
+
+func TestFCMErrorMarksDeadTokens(t *testing.T) {
+	unregistered := `{"error":{"status":"INVALID_ARGUMENT","message":"gone","details":[{"errorCode":"UNREGISTERED"}]}}`
+	if err := fcmError(400, []byte(unregistered)); !errors.Is(err, ErrInvalidDeviceToken) {
+		t.Errorf("UNREGISTERED: got %v", err)
+	}
+	if err := fcmError(404, []byte(`{"error":{"status":"NOT_FOUND"}}`)); !errors.Is(err, ErrInvalidDeviceToken) {
+		t.Errorf("NOT_FOUND: got %v", err)
+	}
+	if err := fcmError(503, []byte(`{"error":{"status":"UNAVAILABLE"}}`)); errors.Is(err, ErrInvalidDeviceToken) {
+		t.Errorf("UNAVAILABLE should be retryable, got %v", err)
+	}
+}
+
+func TestReminderDueWindow(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2026, 3, 1, hour, 15, 0, 0, time.UTC) }
+	for hour, want := range map[int]bool{8: false, 9: true, 10: true, 11: false} {
+		if got := reminderDue(at(hour), 9); got != want {
+			t.Errorf("reminderDue(%02d:15, 9) = %v, want %v", hour, got, want)
+		}
+	}
+}
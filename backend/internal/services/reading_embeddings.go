@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// embeddingBatchSize caps readings embedded per worker tick, and per
+	// provider request.
+	embeddingBatchSize = 100
+	// nearDuplicateSimilarity marks readings whose texts say practically
+	// the same thing.
+	nearDuplicateSimilarity = 0.97
+	maxSimilarReadings      = 20
+)
+
+var (
+	ErrVectorSearchUnavailable = errors.New("similar readings are not available")
+	ErrReadingNotIndexed       = errors.New("reading is still being indexed, try again shortly")
+)
+
+// ReadingEmbeddingService keeps an embedding of every personal reading's
+// texts in pgvector. It is off unless VECTOR_SEARCH is set and an embedder
+// is configured, and turns itself off when the extension cannot be
+// installed; every caller degrades to working without it.
+type ReadingEmbeddingService struct {
+	db       *gorm.DB
+	embedder Embedder
+	enabled  bool
+}
+
+func NewReadingEmbeddingService(db *gorm.DB, cfg *config.Config, embedder Embedder) *ReadingEmbeddingService {
+	s := &ReadingEmbeddingService{db: db, embedder: embedder}
+	if !cfg.VectorSearch {
+		return s
+	}
+	if embedder == nil {
+		log.Printf("vector search: disabled, VECTOR_SEARCH needs EMBEDDINGS_DRIVER")
+		return s
+	}
+	if err := database.EnableVectorStore(db); err != nil {
+		log.Printf("vector search: disabled, pgvector unavailable: %v", err)
+		return s
+	}
+	s.enabled = true
+	return s
+}
+
+// Enabled reports whether embeddings are stored and searchable.
+func (s *ReadingEmbeddingService) Enabled() bool {
+	return s != nil && s.enabled
+}
+
+// RunEmbeddingWorker embeds new readings every interval until ctx is
+// cancelled.
+func (s *ReadingEmbeddingService) RunEmbeddingWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.EmbedPending(ctx)
+			if err != nil {
+				log.Printf("embedding worker: %v", err)
+			}
+			if n > 0 {
+				log.Printf("embedding worker: embedded %d readings", n)
+			}
+		}
+	}
+}
+
+// EmbedPending embeds up to embeddingBatchSize finished personal readings
+// that have no embedding yet.
+func (s *ReadingEmbeddingService) EmbedPending(ctx context.Context) (int, error) {
+	if !s.Enabled() {
+		return 0, nil
+	}
+	var readings []models.AuraReading
+	if err := s.db.WithContext(ctx).Scopes(personalReadings).Select("id", "user_id", "personality", "daily_advice").
+		Where("NOT synthetic AND status = ? AND personality <> ''", models.ReadingStatusReady).
+		Where("NOT EXISTS (SELECT 1 FROM reading_embeddings e WHERE e.reading_id = aura_readings.id)").
+		Order("created_at").Limit(embeddingBatchSize).Find(&readings).Error; err != nil {
+		return 0, err
+	}
+	if len(readings) == 0 {
+		return 0, nil
+	}
+
+	texts := make([]string, len(readings))
+	for i, r := range readings {
+		texts[i] = truncateRunes(r.Personality+"\n"+r.DailyAdvice, 2000)
+	}
+	vectors, err := s.embedder.Embed(ctx, texts)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for i, r := range readings {
+		if len(vectors[i]) != database.ReadingEmbeddingDims {
+			return stored, fmt.Errorf("%s returned %d dimensions, want %d", s.embedder.Name(), len(vectors[i]), database.ReadingEmbeddingDims)
+		}
+		if err := s.db.WithContext(ctx).Exec(`INSERT INTO reading_embeddings (reading_id, user_id, model, embedding)
+			VALUES (?, ?, ?, ?::vector) ON CONFLICT (reading_id) DO NOTHING`,
+			r.ID, r.UserID, s.embedder.Name(), vectorLiteral(vectors[i])).Error; err != nil {
+			return stored, err
+		}
+		stored++
+	}
+	return stored, nil
+}
+
+// Similar returns the user's readings closest in meaning to readingID,
+// most similar first.
+func (s *ReadingEmbeddingService) Similar(ctx context.Context, userID, readingID uuid.UUID, limit int) ([]dto.SimilarReading, error) {
+	if !s.Enabled() {
+		return nil, ErrVectorSearchUnavailable
+	}
+	if limit <= 0 || limit > maxSimilarReadings {
+		limit = maxSimilarReadings
+	}
+
+	var reading models.AuraReading
+	if err := s.db.WithContext(ctx).Scopes(personalReadings).Select("id").
+		Where("user_id = ? AND id = ?", userID, readingID).First(&reading).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReadingNotFound
+		}
+		return nil, err
+	}
+	var indexed int64
+	s.db.WithContext(ctx).Table("reading_embeddings").Where("reading_id = ?", readingID).Count(&indexed)
+	if indexed == 0 {
+		return nil, ErrReadingNotIndexed
+	}
+
+	var matches []struct {
+		ReadingID  uuid.UUID
+		Similarity float64
+	}
+	if err := s.db.WithContext(ctx).Raw(`SELECT e.reading_id, 1 - (e.embedding <=> q.embedding) AS similarity
+		FROM reading_embeddings e, (SELECT embedding FROM reading_embeddings WHERE reading_id = ?) q
+		WHERE e.user_id = ? AND e.reading_id <> ?
+		ORDER BY e.embedding <=> q.embedding LIMIT ?`, readingID, userID, readingID, limit*2).
+		Scan(&matches).Error; err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return []dto.SimilarReading{}, nil
+	}
+
+	ids := make([]uuid.UUID, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ReadingID
+	}
+	// Soft-deleted readings keep their embedding until purged; loading
+	// through the model drops them.
+	var readings []models.AuraReading
+	if err := s.db.WithContext(ctx).Where("id IN ?", ids).Find(&readings).Error; err != nil {
+		return nil, err
+	}
+	if err := readTraitsFromTable(s.db.WithContext(ctx), readings); err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]models.AuraReading, len(readings))
+	for _, r := range readings {
+		byID[r.ID] = r
+	}
+
+	similar := make([]dto.SimilarReading, 0, limit)
+	for _, m := range matches {
+		r, ok := byID[m.ReadingID]
+		if !ok {
+			continue
+		}
+		similar = append(similar, dto.SimilarReading{
+			Reading:       r,
+			Similarity:    m.Similarity,
+			NearDuplicate: m.Similarity >= nearDuplicateSimilarity,
+		})
+		if len(similar) == limit {
+			break
+		}
+	}
+	return similar, nil
+}
+
+// Vectors returns the stored embeddings of the given readings; readings not
+// embedded yet are missing from the map.
+func (s *ReadingEmbeddingService) Vectors(ctx context.Context, readingIDs []uuid.UUID) (map[uuid.UUID][]float64, error) {
+	if !s.Enabled() || len(readingIDs) == 0 {
+		return nil, nil
+	}
+	var rows []struct {
+		ReadingID uuid.UUID
+		Embedding string
+	}
+	if err := s.db.WithContext(ctx).Raw(`SELECT reading_id, embedding::text AS embedding FROM reading_embeddings WHERE reading_id IN ?`, readingIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	vectors := make(map[uuid.UUID][]float64, len(rows))
+	for _, row := range rows {
+		v, err := parseVector(row.Embedding)
+		if err != nil {
+			return nil, fmt.Errorf("embedding of %s: %w", row.ReadingID, err)
+		}
+		vectors[row.ReadingID] = v
+	}
+	return vectors, nil
+}
+
+// vectorLiteral formats v in pgvector's text form, e.g. "[0.1,-2,3e-05]".
+func vectorLiteral(v []float64) string {
+	parts := make([]string, len(v))
+	for i, f := range v {
+		parts[i] = strconv.FormatFloat(f, 'g', -1, 32)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+func parseVector(text string) ([]float64, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "[") || !strings.HasSuffix(text, "]") {
+		return nil, fmt.Errorf("not a vector: %.20q", text)
+	}
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "["), "]")
+	if text == "" {
+		return nil, nil
+	}
+	parts := strings.Split(text, ",")
+	v := make([]float64, len(parts))
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = f
+	}
+	return v, nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/google/uuid"
+)
+
+func TestVectorLiteralRoundTrip(t *testing.T) {
+	v := []float64{0.5, -2, 3e-05, 0}
+	literal := vectorLiteral(v)
+	if literal != "[0.5,-2,3e-05,0]" {
+		t.Fatalf("literal = %s", literal)
+	}
+	got, err := parseVector(literal)
+	if err != nil || !reflect.DeepEqual(got, v) {
+		t.Fatalf("parseVector(%s) = %v, %v", literal, got, err)
+	}
+	if _, err := parseVector("0.5,1"); err == nil {
+		t.Error("a literal without brackets should fail")
+	}
+}
+
+func TestReadingEmbeddingsDisabled(t *testing.T) {
+	// Without VECTOR_SEARCH the store never touches the database.
+	s := NewReadingEmbeddingService(nil, &config.Config{}, nil)
+	if s.Enabled() {
+		t.Fatal("enabled without VECTOR_SEARCH")
+	}
+	if _, err := s.Similar(context.Background(), uuid.New(), uuid.New(), 5); !errors.Is(err, ErrVectorSearchUnavailable) {
+		t.Errorf("Similar = %v, want ErrVectorSearchUnavailable", err)
+	}
+	if n, err := s.EmbedPending(context.Background()); n != 0 || err != nil {
+		t.Errorf("EmbedPending = %d, %v", n, err)
+	}
+
+	// An embedder is required even with the flag on.
+	s = NewReadingEmbeddingService(nil, &config.Config{VectorSearch: true}, nil)
+	if s.Enabled() {
+		t.Fatal("enabled without an embedder")
+	}
+}
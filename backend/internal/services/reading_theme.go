@@ -0,0 +1,76 @@
+package services
+
+import (
+	"image"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/imageproc"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// themeBlend is how far background stops move from the aura color toward
+// the photo tones: enough to feel like the photo, not enough to lose the aura.
+const themeBlend = 0.35
+
+// photoPalette extracts the stored palette from an uploaded photo. Readings
+// scanned from a URL have no bytes on the server and get no palette.
+func photoPalette(img image.Image) []string {
+	if img == nil {
+		return nil
+	}
+	swatches := imageproc.Palette(img, imageproc.PaletteSize)
+	palette := make([]string, len(swatches))
+	for i, s := range swatches {
+		palette[i] = s.Hex
+	}
+	return palette
+}
+
+// readingTheme blends the aura's brand color with the photo palette for
+// share cards and in-app backgrounds. Without a palette the theme is built
+// from the aura color alone.
+func readingTheme(reading models.AuraReading) dto.ReadingTheme {
+	accent := auraColorHex[reading.AuraColor]
+	if accent == "" {
+		accent = auraColorHex["violet"]
+	}
+
+	background := make([]string, 0, 3)
+	for i, tone := range reading.Palette {
+		if i == 3 {
+			break
+		}
+		background = append(background, imageproc.Blend(accent, tone, themeBlend))
+	}
+	if len(background) == 0 {
+		background = append(background, accent, imageproc.Blend(accent, "#000000", 0.3))
+	}
+
+	text := "#FFFFFF"
+	if imageproc.IsLight(background[0]) {
+		text = "#111827"
+	}
+
+	palette := reading.Palette
+	if palette == nil {
+		palette = []string{}
+	}
+	return dto.ReadingTheme{
+		AuraColor:  reading.AuraColor,
+		Accent:     accent,
+		Palette:    palette,
+		Background: background,
+		TextColor:  text,
+	}
+}
+
+// Theme returns the blended theme for one of the user's readings.
+func (s *AuraService) Theme(userID, readingID uuid.UUID) (*dto.ReadingTheme, error) {
+	var reading models.AuraReading
+	if err := s.db.Where("user_id = ? AND id = ?", userID, readingID).First(&reading).Error; err != nil {
+		return nil, ErrReadingNotFound
+	}
+	theme := readingTheme(reading)
+	return &theme, nil
+}
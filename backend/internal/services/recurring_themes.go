@@ -0,0 +1,245 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// minThemeReadings is how many readings must share a theme for it to
+	// count as recurring; users with fewer readings have no themes.
+	minThemeReadings = 3
+	maxThemes        = 5
+	maxThemeKeywords = 5
+	// themeKeywordOverlap is the share of a seed keyword's readings another
+	// keyword must also appear in to join its theme.
+	themeKeywordOverlap = 0.6
+	// themeSimilarity is the cosine similarity a reading's embedding needs
+	// to a cluster's centroid to join it.
+	themeSimilarity = 0.82
+)
+
+// themeDoc is the text of one reading considered for themes.
+type themeDoc struct {
+	ReadingID uuid.UUID
+	Text      string
+	CreatedAt time.Time
+}
+
+// detectedTheme is one recurring theme with the indexes of its readings.
+type detectedTheme struct {
+	Label    string
+	Keywords []string
+	Docs     []int
+}
+
+// themeStopwords are words too common, or too much part of every reading,
+// to say anything about a person: function words of each supported locale,
+// plus the app's own vocabulary and the aura colors.
+var themeStopwords = stringSet(strings.Fields(`
+	about above after again also always among another around because been before being below between both cannot could does doing down during each every from further have having here into itself just keep know like make more most much must only other over own same should some such than that their them then there these they this those through today together under until very want well what when where which while will with would your yours yourself others
+	aura auras energy energies color colors colour reading readings feel feeling feels life people thing things day days
+	red orange yellow green blue indigo violet white gold pink purple
+	bugün için gibi daha çok olan olarak ama veya ile ancak kadar sonra önce şimdi her şey bir biraz senin sana seni sen kendi kendini bunu şunu olabilir oluyor etmek olmak enerji enerjin enerjini aura auran renk gün günün
+	kırmızı turuncu sarı yeşil mavi çivit mor beyaz altın pembe
+	para pero como este esta estos estas sobre entre desde hasta cuando donde porque tiene tienes puede puedes hacia tambien también muy todo todos toda todas otro otros otra otras ser estar eres está hoy energía aura color colores día
+	rojo naranja amarillo verde azul índigo violeta blanco dorado rosa
+`))
+
+func stringSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// themeKeywords returns the distinct content words of text.
+func themeKeywords(text string) []string {
+	seen := map[string]bool{}
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if utf8.RuneCountInString(w) < 4 || themeStopwords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		words = append(words, w)
+	}
+	return words
+}
+
+// keywordDocs maps each keyword to the sorted indexes of the docs using it.
+func keywordDocs(docs []themeDoc, only []int) map[string][]int {
+	index := map[string][]int{}
+	for _, i := range only {
+		for _, w := range themeKeywords(docs[i].Text) {
+			index[w] = append(index[w], i)
+		}
+	}
+	return index
+}
+
+// rankedKeywords orders keywords by how many docs use them, then
+// alphabetically so results are stable.
+func rankedKeywords(index map[string][]int) []string {
+	words := make([]string, 0, len(index))
+	for w := range index {
+		words = append(words, w)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if len(index[words[i]]) != len(index[words[j]]) {
+			return len(index[words[i]]) > len(index[words[j]])
+		}
+		return words[i] < words[j]
+	})
+	return words
+}
+
+func allDocs(n int) []int {
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx
+}
+
+// keywordThemes groups frequent keywords that tend to appear in the same
+// readings. Each frequent keyword not yet taken seeds a theme, and joins
+// the keywords whose readings mostly overlap its own.
+func keywordThemes(docs []themeDoc) []detectedTheme {
+	if len(docs) < minThemeReadings {
+		return nil
+	}
+	index := keywordDocs(docs, allDocs(len(docs)))
+	taken := map[string]bool{}
+	var themes []detectedTheme
+	for _, seed := range rankedKeywords(index) {
+		if len(themes) == maxThemes || len(index[seed]) < minThemeReadings {
+			break
+		}
+		if taken[seed] {
+			continue
+		}
+		taken[seed] = true
+		theme := detectedTheme{Keywords: []string{seed}, Docs: index[seed]}
+		for _, w := range rankedKeywords(index) {
+			if len(theme.Keywords) == maxThemeKeywords || len(index[w]) < minThemeReadings {
+				break
+			}
+			if taken[w] {
+				continue
+			}
+			if float64(overlap(index[seed], index[w])) >= themeKeywordOverlap*float64(len(index[seed])) {
+				taken[w] = true
+				theme.Keywords = append(theme.Keywords, w)
+			}
+		}
+		theme.Label = themeLabel(theme.Keywords)
+		themes = append(themes, theme)
+	}
+	return themes
+}
+
+// embeddingThemes clusters readings whose embeddings are close, oldest
+// first, and names each cluster after the keywords most of its readings
+// share.
+func embeddingThemes(docs []themeDoc, vectors [][]float64) []detectedTheme {
+	if len(docs) < minThemeReadings || len(vectors) != len(docs) {
+		return nil
+	}
+	type cluster struct {
+		centroid []float64
+		docs     []int
+	}
+	var clusters []*cluster
+	for i, v := range vectors {
+		var best *cluster
+		bestSim := themeSimilarity
+		for _, c := range clusters {
+			if sim := cosineSimilarity(c.centroid, v); sim >= bestSim {
+				best, bestSim = c, sim
+			}
+		}
+		if best == nil {
+			clusters = append(clusters, &cluster{centroid: append([]float64(nil), v...), docs: []int{i}})
+			continue
+		}
+		n := float64(len(best.docs))
+		for j := range best.centroid {
+			best.centroid[j] = (best.centroid[j]*n + v[j]) / (n + 1)
+		}
+		best.docs = append(best.docs, i)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool { return len(clusters[i].docs) > len(clusters[j].docs) })
+	var themes []detectedTheme
+	used := map[string]bool{}
+	for _, c := range clusters {
+		if len(themes) == maxThemes || len(c.docs) < minThemeReadings {
+			break
+		}
+		index := keywordDocs(docs, c.docs)
+		var keywords []string
+		for _, w := range rankedKeywords(index) {
+			if len(keywords) == maxThemeKeywords || len(index[w]) < 2 {
+				break
+			}
+			keywords = append(keywords, w)
+		}
+		// A cluster that shares no wording, or only the wording of a bigger
+		// one, has nothing to show the user.
+		if len(keywords) == 0 || used[keywords[0]] {
+			continue
+		}
+		used[keywords[0]] = true
+		themes = append(themes, detectedTheme{Label: themeLabel(keywords), Keywords: keywords, Docs: c.docs})
+	}
+	return themes
+}
+
+func themeLabel(keywords []string) string {
+	if len(keywords) > 2 {
+		keywords = keywords[:2]
+	}
+	return strings.Join(keywords, " & ")
+}
+
+// overlap counts the indexes two sorted slices share.
+func overlap(a, b []int) int {
+	n, i, j := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			n++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return n
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
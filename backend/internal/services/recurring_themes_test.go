@@ -0,0 +1,84 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+)
+
+func themeDocs(texts ...string) []themeDoc {
+	docs := make([]themeDoc, len(texts))
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for i, text := range texts {
+		docs[i] = themeDoc{Text: text, CreatedAt: start.AddDate(0, 0, i)}
+	}
+	return docs
+}
+
+func TestKeywordThemes(t *testing.T) {
+	docs := themeDocs(
+		"Your creative spark wants an outlet. Protect your boundaries today.",
+		"A blue aura of calm. Creative projects flourish when boundaries hold.",
+		"You feel restless. Creative work and firm boundaries help.",
+		"Patience pays off at work this week.",
+		"Patience with others brings calm.",
+		"Patience and rest restore you.",
+	)
+	got := keywordThemes(docs)
+	if len(got) != 2 {
+		t.Fatalf("themes = %+v, want 2", got)
+	}
+	if got[0].Label != "boundaries & creative" || !reflect.DeepEqual(got[0].Docs, []int{0, 1, 2}) {
+		t.Errorf("first theme = %+v", got[0])
+	}
+	if got[1].Label != "patience" || !reflect.DeepEqual(got[1].Docs, []int{3, 4, 5}) {
+		t.Errorf("second theme = %+v", got[1])
+	}
+
+	if themes := keywordThemes(docs[:2]); themes != nil {
+		t.Errorf("two readings produced themes: %+v", themes)
+	}
+}
+
+func TestThemeKeywordsDropStopwords(t *testing.T) {
+	got := themeKeywords("Bugün enerjin yüksek, sabır ve sabır ile kırmızı auran parlıyor. Your energy is RED today!")
+	want := []string{"yüksek", "sabır", "parlıyor"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("keywords = %v, want %v", got, want)
+	}
+}
+
+func TestEmbeddingThemes(t *testing.T) {
+	docs := themeDocs(
+		"Trust your intuition about the new project.",
+		"Rest and recharge before the weekend.",
+		"Your intuition guides a bold project decision.",
+		"Intuition points the project forward.",
+		"Take time to rest and recharge slowly.",
+	)
+	vectors := [][]float64{{1, 0.1}, {0, 1}, {0.95, 0.2}, {1, 0}, {0.1, 1}}
+	got := embeddingThemes(docs, vectors)
+	if len(got) != 1 {
+		t.Fatalf("themes = %+v, want only the three-reading cluster", got)
+	}
+	if got[0].Label != "intuition & project" || !reflect.DeepEqual(got[0].Docs, []int{0, 2, 3}) {
+		t.Errorf("theme = %+v", got[0])
+	}
+}
+
+func TestNewEmbedder(t *testing.T) {
+	if e, err := NewEmbedder(&config.Config{}); e != nil || err != nil {
+		t.Errorf("no driver = %v, %v; want keyword fallback", e, err)
+	}
+	if _, err := NewEmbedder(&config.Config{EmbeddingsDriver: "openai"}); err == nil {
+		t.Error("openai without an API key should fail")
+	}
+	if _, err := NewEmbedder(&config.Config{EmbeddingsDriver: "word2vec"}); err == nil {
+		t.Error("unknown driver should fail")
+	}
+	if e, err := NewEmbedder(&config.Config{EmbeddingsDriver: "openai", OpenAIAPIKey: "sk-test"}); err != nil || e.Name() != "openai" {
+		t.Errorf("openai = %v, %v", e, err)
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrTokenReuse means an already-rotated refresh token was presented again,
+// which only happens if it was copied; the whole session is revoked.
+var ErrTokenReuse = errors.New("refresh token reuse detected; please sign in again")
+
+// Refresh rotates a refresh token: the presented token is revoked and a new
+// one is issued in the same family. Presenting a rotated token again
+// revokes the family so a stolen token stops working for both parties.
+func (s *AuthService) Refresh(req *dto.RefreshRequest, device dto.DeviceInfo) (*dto.AuthResponse, error) {
+	var stored models.RefreshToken
+	if err := s.db.Where("token_hash = ?", hashToken(req.RefreshToken)).First(&stored).Error; err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if stored.Revoked {
+		if stored.RevokedReason == models.RevokeReasonRotated {
+			s.reportReuse(&stored)
+			return nil, ErrTokenReuse
+		}
+		return nil, ErrInvalidToken
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		revokeRefreshTokens(s.db.Where("id = ?", stored.ID), "expired")
+		return nil, ErrInvalidToken
+	}
+
+	// Conditional revoke: of two concurrent refreshes with the same token
+	// only one wins; the loser is treated as reuse.
+	now := time.Now()
+	result := s.db.Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked = false", stored.ID).
+		Updates(map[string]interface{}{
+			"revoked":        true,
+			"revoked_reason": models.RevokeReasonRotated,
+			"revoked_at":     now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		s.reportReuse(&stored)
+		return nil, ErrTokenReuse
+	}
+
+	var user models.User
+	if err := s.db.First(&user, "id = ?", stored.UserID).Error; err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	familyID := stored.FamilyID
+	if familyID == uuid.Nil {
+		// Tokens issued before rotation families existed start one now.
+		familyID = uuid.New()
+	}
+	// Keep the device label from sign-in if the client doesn't resend it.
+	if device.Name == "" {
+		device.Name = stored.DeviceName
+	}
+	if device.Platform == "" {
+		device.Platform = stored.Platform
+	}
+	return s.issueTokenPair(&user, familyID, device)
+}
+
+// Logout ends the session the refresh token belongs to.
+func (s *AuthService) Logout(req *dto.LogoutRequest) error {
+	var stored models.RefreshToken
+	if err := s.db.Where("token_hash = ?", hashToken(req.RefreshToken)).First(&stored).Error; err != nil {
+		return nil
+	}
+	if stored.FamilyID == uuid.Nil {
+		return revokeRefreshTokens(s.db.Where("id = ?", stored.ID), models.RevokeReasonLogout)
+	}
+	return revokeRefreshTokens(s.db.Where("family_id = ?", stored.FamilyID), models.RevokeReasonLogout)
+}
+
+func (s *AuthService) reportReuse(stored *models.RefreshToken) {
+	log.Printf("auth: refresh token reuse for user %s, revoking session %s", stored.UserID, stored.FamilyID)
+	if stored.FamilyID == uuid.Nil {
+		return
+	}
+	if err := revokeRefreshTokens(s.db.Where("family_id = ?", stored.FamilyID), models.RevokeReasonReuseDetected); err != nil {
+		log.Printf("auth: failed to revoke session %s: %v", stored.FamilyID, err)
+	}
+}
+
+// startSession signs the user in on a new device session.
+func (s *AuthService) startSession(user *models.User, device dto.DeviceInfo) (*dto.AuthResponse, error) {
+	return s.issueTokenPair(user, uuid.New(), device)
+}
+
+func (s *AuthService) issueTokenPair(user *models.User, familyID uuid.UUID, device dto.DeviceInfo) (*dto.AuthResponse, error) {
+	accessToken, err := s.generateAccessToken(user, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := s.generateRefreshToken(user, familyID, device)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AuthResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		User:         *userResponse(user),
+	}, nil
+}
+
+func (s *AuthService) generateRefreshToken(user *models.User, familyID uuid.UUID, device dto.DeviceInfo) (string, error) {
+	rawBytes := make([]byte, 32)
+	if _, err := rand.Read(rawBytes); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	rawToken := base64.URLEncoding.EncodeToString(rawBytes)
+	now := time.Now()
+
+	record := models.RefreshToken{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		FamilyID:   familyID,
+		TokenHash:  hashToken(rawToken),
+		ExpiresAt:  now.Add(s.cfg.JWTRefreshExpiry),
+		DeviceName: truncateRunes(device.Name, 100),
+		Platform:   truncateRunes(device.Platform, 20),
+		AppVersion: truncateRunes(device.AppVersion, 30),
+		UserAgent:  truncateRunes(device.UserAgent, 255),
+		IPAddress:  truncateRunes(device.IP, 45),
+		LastUsedAt: &now,
+	}
+
+	if err := s.db.Create(&record).Error; err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return rawToken, nil
+}
+
+// revokeRefreshTokens revokes the still-active tokens matched by scope.
+func revokeRefreshTokens(scope *gorm.DB, reason string) error {
+	return scope.Model(&models.RefreshToken{}).
+		Where("revoked = false").
+		Updates(map[string]interface{}{
+			"revoked":        true,
+			"revoked_reason": reason,
+			"revoked_at":     time.Now(),
+		}).Error
+}
+
+// signOutEverywhere revokes all of the user's sessions and rejects access
+// tokens issued before now, so the sign-out takes effect immediately rather
+// than when the current access tokens expire. reason is recorded on the
+// revoked refresh tokens.
+func signOutEverywhere(db *gorm.DB, userID uuid.UUID, reason string) error {
+	if err := revokeRefreshTokens(db.Where("user_id = ?", userID), reason); err != nil {
+		return err
+	}
+	return db.Model(&models.User{}).Where("id = ?", userID).Update("sessions_revoked_at", time.Now()).Error
+}
+
+// AccessTokenValid reports whether an access token of sessionID issued at
+// issuedAt is still accepted: the account exists, is not deactivated or
+// deleted, its sessions were not revoked after the token was issued, and
+// the session itself was not signed out. A session is live while its
+// refresh token family has an unrevoked token; uuid.Nil (tokens from
+// before sessions were tracked) skips that check.
+func (s *AuthService) AccessTokenValid(userID, sessionID uuid.UUID, issuedAt time.Time) bool {
+	var user models.User
+	if err := s.db.Select("id", "sessions_revoked_at").First(&user, "id = ?", userID).Error; err != nil {
+		return false
+	}
+	if user.SessionsRevokedAt != nil && issuedAt.Before(user.SessionsRevokedAt.Truncate(time.Second)) {
+		return false
+	}
+	if sessionID == uuid.Nil {
+		return true
+	}
+	var live int64
+	if err := s.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND family_id = ? AND revoked = false", userID, sessionID).
+		Count(&live).Error; err != nil {
+		return false
+	}
+	return live > 0
+}
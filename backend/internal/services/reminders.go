@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ReminderDailyReading = "daily_reading"
+	ReminderStreakAtRisk = "streak_at_risk"
+)
+
+// A reminder missed by a worker outage still goes out if the worker is back
+// within this many hours of the configured local hour.
+const reminderWindowHours = 2
+
+// ReminderService sends the scheduled push nudges: the daily reading and
+// streak-at-risk reminders. Delivery rules (preferences, quiet hours, the
+// daily cap) are enforced by NotificationService.
+type ReminderService struct {
+	db            *gorm.DB
+	cfg           *config.Config
+	notifications *NotificationService
+}
+
+func NewReminderService(db *gorm.DB, cfg *config.Config, notifications *NotificationService) *ReminderService {
+	return &ReminderService{db: db, cfg: cfg, notifications: notifications}
+}
+
+// ReminderJob is the scheduled job that sends due reminders.
+func (s *ReminderService) ReminderJob(ctx context.Context) error {
+	sent, err := s.SendDue(time.Now())
+	if sent > 0 {
+		log.Printf("reminders: sent %d", sent)
+	}
+	return err
+}
+
+// SendDue sends every reminder due at now to users with a registered push
+// device. Each reminder goes out at most once per user and local day.
+func (s *ReminderService) SendDue(now time.Time) (int, error) {
+	var users []models.User
+	if err := s.db.Select("id", "timezone").
+		Where("id IN (SELECT user_id FROM device_tokens)").
+		Find(&users).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, user := range users {
+		loc, err := loadTimezone(user.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		local := now.In(loc)
+		dayStart, dayEnd := localDayBounds(now, loc)
+		day := local.Format("2006-01-02")
+
+		if reminderDue(local, s.cfg.ReminderDailyHour) && !s.scannedBetween(user.ID, dayStart, dayEnd) &&
+			s.claim(user.ID, ReminderDailyReading, day) {
+			s.send(user.ID, NotificationMessage{
+				Category: CategoryReminders,
+				Title:    "Your daily aura is ready ✨",
+				Body:     "Take a quick scan to see what today's energy looks like.",
+				Data:     map[string]string{"event": ReminderDailyReading},
+			})
+			sent++
+		}
+
+		if reminderDue(local, s.cfg.ReminderStreakHour) {
+			if streak, ok := s.streakAtRisk(user.ID, dayStart, loc); ok && s.claim(user.ID, ReminderStreakAtRisk, day) {
+				s.send(user.ID, NotificationMessage{
+					Category: CategoryReminders,
+					Title:    "Your streak is at risk 🔥",
+					Body:     streakAtRiskBody(streak),
+					Data:     map[string]string{"event": ReminderStreakAtRisk},
+				})
+				sent++
+			}
+		}
+	}
+
+	s.db.Where("created_at < ?", now.AddDate(0, 0, -7)).Delete(&models.ReminderLog{})
+	return sent, nil
+}
+
+func reminderDue(local time.Time, hour int) bool {
+	return local.Hour() >= hour && local.Hour() < hour+reminderWindowHours
+}
+
+func (s *ReminderService) scannedBetween(userID uuid.UUID, start, end time.Time) bool {
+	var count int64
+	s.db.Model(&models.AuraReading{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, start, end).
+		Count(&count)
+	return count > 0
+}
+
+// streakAtRisk returns the current streak when the last scan was yesterday,
+// i.e. the streak breaks at local midnight unless the user scans today.
+func (s *ReminderService) streakAtRisk(userID uuid.UUID, todayStart time.Time, loc *time.Location) (int, bool) {
+	var streak models.AuraStreak
+	if err := s.db.Where("user_id = ?", userID).First(&streak).Error; err != nil {
+		return 0, false
+	}
+	if streak.CurrentStreak <= 0 || streak.LastScanDate.IsZero() {
+		return 0, false
+	}
+	lastScan, _ := localDayBounds(streak.LastScanDate, loc)
+	return streak.CurrentStreak, lastScan.Equal(todayStart.AddDate(0, 0, -1))
+}
+
+func streakAtRiskBody(streak int) string {
+	if streak == 1 {
+		return "Scan today to keep your streak going."
+	}
+	return fmt.Sprintf("Scan today to keep your %d-day streak alive.", streak)
+}
+
+// claim records the reminder and reports whether this call was first.
+func (s *ReminderService) claim(userID uuid.UUID, kind, day string) bool {
+	return claimReminder(s.db, userID, kind, day)
+}
+
+// claimReminder records a scheduled send of kind for the user's local day
+// and reports whether this call was first.
+func claimReminder(db *gorm.DB, userID uuid.UUID, kind, day string) bool {
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.ReminderLog{UserID: userID, Kind: kind, Day: day})
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+func (s *ReminderService) send(userID uuid.UUID, msg NotificationMessage) {
+	if _, err := s.notifications.Notify(userID, msg); err != nil {
+		log.Printf("reminders: %s for %s: %v", msg.Data["event"], userID, err)
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/metrics"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	requestLogQueueSize = 1024
+	// requestLogBatchSize caps the rows written in one insert.
+	requestLogBatchSize = 100
+	requestLogFlush     = 2 * time.Second
+	maxRequestLogError  = 4000
+)
+
+var ErrRequestNotFound = errors.New("no failed request was recorded with this ID")
+
+// RequestLogService keeps failed requests for support lookups by request
+// ID. Requests are queued and written in batches by RunRequestLogWorker,
+// so an error storm never adds a write to every failing request; when the
+// queue is full entries are dropped.
+type RequestLogService struct {
+	db    *gorm.DB
+	cfg   *config.Config
+	queue chan models.RequestLog
+}
+
+func NewRequestLogService(db *gorm.DB, cfg *config.Config) *RequestLogService {
+	return &RequestLogService{db: db, cfg: cfg, queue: make(chan models.RequestLog, requestLogQueueSize)}
+}
+
+// Record queues a failed request without blocking.
+func (s *RequestLogService) Record(entry dto.RequestLogEntry) {
+	row := models.RequestLog{
+		RequestID:  entry.RequestID,
+		Traced:     entry.Traced,
+		UserID:     entry.UserID,
+		Method:     entry.Method,
+		Route:      truncateRunes(entry.Route, 255),
+		TargetID:   truncateRunes(entry.TargetID, 255),
+		Status:     entry.Status,
+		Code:       truncateRunes(entry.Code, 64),
+		Error:      truncateRunes(entry.Error, maxRequestLogError),
+		LatencyMs:  entry.LatencyMs,
+		Platform:   truncateRunes(entry.Platform, 20),
+		AppVersion: truncateRunes(entry.AppVersion, 32),
+		CreatedAt:  time.Now(),
+	}
+	select {
+	case s.queue <- row:
+	default:
+		metrics.RequestLogsDroppedTotal.Inc()
+	}
+}
+
+// RunRequestLogWorker writes queued requests every few seconds, and purges
+// those older than REQUEST_LOG_RETENTION every purgeInterval, until ctx is
+// cancelled.
+func (s *RequestLogService) RunRequestLogWorker(ctx context.Context, purgeInterval time.Duration) {
+	flush := time.NewTicker(requestLogFlush)
+	defer flush.Stop()
+	purge := time.NewTicker(purgeInterval)
+	defer purge.Stop()
+
+	var batch []models.RequestLog
+	write := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.db.CreateInBatches(batch, requestLogBatchSize).Error; err != nil {
+			log.Printf("request log worker: writing %d requests: %v", len(batch), err)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			write()
+			return
+		case row := <-s.queue:
+			if batch = append(batch, row); len(batch) == requestLogBatchSize {
+				write()
+			}
+		case <-flush.C:
+			write()
+		case <-purge.C:
+			n, err := s.Purge(time.Now())
+			if err != nil {
+				log.Printf("request log worker: %v", err)
+			}
+			if n > 0 {
+				log.Printf("request log worker: purged %d requests", n)
+			}
+		}
+	}
+}
+
+// Purge deletes requests recorded before the retention window.
+func (s *RequestLogService) Purge(now time.Time) (int64, error) {
+	result := s.db.Where("created_at < ?", now.Add(-s.cfg.RequestLogRetention)).Delete(&models.RequestLog{})
+	return result.RowsAffected, result.Error
+}
+
+// Lookup returns what was recorded for a request ID, with a link to its
+// trace when the request was traced and a trace UI is configured.
+func (s *RequestLogService) Lookup(ctx context.Context, requestID string) (*dto.RequestLookupResponse, error) {
+	requestID = strings.ToLower(strings.TrimSpace(requestID))
+	var requests []models.RequestLog
+	if err := s.db.WithContext(ctx).Where("request_id = ?", requestID).Order("created_at").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, ErrRequestNotFound
+	}
+	resp := &dto.RequestLookupResponse{RequestID: requestID, Requests: requests}
+	for _, r := range requests {
+		if r.Traced {
+			resp.TraceURL = traceURL(s.cfg.TraceURLTemplate, requestID)
+			break
+		}
+	}
+	return resp, nil
+}
+
+// traceURL fills a TRACE_URL_TEMPLATE; it is empty without a template.
+func traceURL(template, traceID string) string {
+	if template == "" {
+		return ""
+	}
+	return strings.ReplaceAll(template, "{trace_id}", traceID)
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// researchSchemaVersion is bumped whenever researchRow changes shape, so
+// consumers never mix incompatible files; each version has its own prefix.
+const researchSchemaVersion = 1
+
+// researchSchemaFields documents researchRow in the manifest. Photos, image
+// URLs and any generated or user-written text are never exported.
+var researchSchemaFields = []researchField{
+	{"participant", "string", "pseudonymous ID, stable across days for one schema version"},
+	{"aura_color", "string", "primary aura color"},
+	{"secondary_color", "string|null", "secondary aura color"},
+	{"energy_level", "integer", "1-100"},
+	{"mood_score", "integer", "1-10"},
+	{"scanned_at", "string", "RFC 3339, UTC, truncated to the hour"},
+	{"region", "string", "continent from the user's timezone; \"other\" for small groups, \"unknown\" when unset"},
+}
+
+var ErrObjectStoreDisabled = errors.New("no object store is configured")
+
+type researchField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// researchRow is one anonymized reading.
+type researchRow struct {
+	Participant    string  `json:"participant"`
+	AuraColor      string  `json:"aura_color"`
+	SecondaryColor *string `json:"secondary_color"`
+	EnergyLevel    int     `json:"energy_level"`
+	MoodScore      int     `json:"mood_score"`
+	ScannedAt      string  `json:"scanned_at"`
+	Region         string  `json:"region"`
+}
+
+type researchManifest struct {
+	SchemaVersion int             `json:"schema_version"`
+	Day           string          `json:"day"`
+	Rows          int             `json:"rows"`
+	Participants  int             `json:"participants"`
+	DataKey       string          `json:"data_key"`
+	Fields        []researchField `json:"fields"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+}
+
+// ResearchExportService writes the daily anonymized, consent-filtered
+// dataset used for internal research and global trends.
+type ResearchExportService struct {
+	db    *gorm.DB
+	cfg   *config.Config
+	store ObjectStore
+}
+
+func NewResearchExportService(db *gorm.DB, cfg *config.Config, store ObjectStore) *ResearchExportService {
+	return &ResearchExportService{db: db, cfg: cfg, store: store}
+}
+
+// ResearchExportJob is the scheduled job that exports each finished UTC
+// day once.
+func (s *ResearchExportService) ResearchExportJob(ctx context.Context) error {
+	export, err := s.ExportDue(ctx, time.Now())
+	if export != nil && export.Status == models.ResearchExportCompleted {
+		log.Printf("research export job: exported %s (%d rows)", export.Day, export.Rows)
+	}
+	return err
+}
+
+// ExportDue exports yesterday (UTC) unless it already completed for the
+// current schema version. It returns nil when there was nothing to do.
+func (s *ResearchExportService) ExportDue(ctx context.Context, now time.Time) (*models.ResearchExport, error) {
+	day := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	var done int64
+	s.db.WithContext(ctx).Model(&models.ResearchExport{}).
+		Where("schema_version = ? AND day = ? AND status = ?", researchSchemaVersion, day.Format("2006-01-02"), models.ResearchExportCompleted).
+		Count(&done)
+	if done > 0 {
+		return nil, nil
+	}
+	return s.ExportDay(ctx, day)
+}
+
+// ExportDay builds and uploads the dataset for one UTC day, replacing any
+// earlier run for that day and schema version.
+func (s *ResearchExportService) ExportDay(ctx context.Context, day time.Time) (*models.ResearchExport, error) {
+	ctx, span := tracer.Start(ctx, "ResearchExportService.ExportDay")
+	defer span.End()
+	if s.store == nil {
+		return nil, ErrObjectStoreDisabled
+	}
+
+	start := day.UTC().Truncate(24 * time.Hour)
+	export := &models.ResearchExport{SchemaVersion: researchSchemaVersion, Day: start.Format("2006-01-02")}
+	prefix := fmt.Sprintf("research/v%d/day=%s/", researchSchemaVersion, export.Day)
+
+	rows, participants, err := s.collect(ctx, start, start.Add(24*time.Hour))
+	if err == nil {
+		export.Rows, export.Participants = len(rows), participants
+		err = s.upload(ctx, prefix, export, rows)
+	}
+
+	now := time.Now()
+	if err != nil {
+		span.RecordError(err)
+		export.Status, export.Error = models.ResearchExportFailed, err.Error()
+	} else {
+		export.Status, export.ObjectKey, export.CompletedAt = models.ResearchExportCompleted, prefix+"readings.jsonl.gz", &now
+	}
+	if saveErr := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "schema_version"}, {Name: "day"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "object_key", "rows", "participants", "error", "completed_at", "updated_at"}),
+	}).Create(export).Error; saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return export, err
+}
+
+// ListExports returns recent export runs, newest day first.
+func (s *ResearchExportService) ListExports(limit int) ([]models.ResearchExport, error) {
+	if limit <= 0 || limit > 365 {
+		limit = 30
+	}
+	var exports []models.ResearchExport
+	err := s.db.Order("day DESC, schema_version DESC").Limit(limit).Find(&exports).Error
+	return exports, err
+}
+
+// collect loads the day's readings of active, consenting users. Group
+// readings are excluded: the people in a group photo did not consent.
+func (s *ResearchExportService) collect(ctx context.Context, from, to time.Time) ([]researchRow, int, error) {
+	var readings []struct {
+		UserID         uuid.UUID
+		AuraColor      string
+		SecondaryColor *string
+		EnergyLevel    int
+		MoodScore      int
+		CreatedAt      time.Time
+		Timezone       string
+	}
+	err := s.db.WithContext(ctx).Table("aura_readings AS r").
+		Select("r.user_id, r.aura_color, r.secondary_color, r.energy_level, r.mood_score, r.created_at, u.timezone").
+		Joins("JOIN users u ON u.id = r.user_id AND u.deleted_at IS NULL AND u.research_consent").
+		Where("r.deleted_at IS NULL AND r.group_reading_id IS NULL AND NOT r.synthetic AND r.status = ? AND r.created_at >= ? AND r.created_at < ?",
+			models.ReadingStatusReady, from, to).
+		Order("r.created_at").
+		Scan(&readings).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows := make([]researchRow, len(readings))
+	regionUsers := make(map[string]map[uuid.UUID]bool)
+	for i, r := range readings {
+		region := coarseRegion(r.Timezone)
+		if regionUsers[region] == nil {
+			regionUsers[region] = make(map[uuid.UUID]bool)
+		}
+		regionUsers[region][r.UserID] = true
+		rows[i] = researchRow{
+			Participant:    s.participantID(r.UserID),
+			AuraColor:      r.AuraColor,
+			SecondaryColor: r.SecondaryColor,
+			EnergyLevel:    r.EnergyLevel,
+			MoodScore:      r.MoodScore,
+			ScannedAt:      r.CreatedAt.UTC().Truncate(time.Hour).Format(time.RFC3339),
+			Region:         region,
+		}
+	}
+
+	participants := 0
+	for _, users := range regionUsers {
+		participants += len(users)
+	}
+	for i := range rows {
+		if len(regionUsers[rows[i].Region]) < s.cfg.ResearchMinRegionUsers {
+			rows[i].Region = "other"
+		}
+	}
+	return rows, participants, nil
+}
+
+func (s *ResearchExportService) upload(ctx context.Context, prefix string, export *models.ResearchExport, rows []researchRow) error {
+	var data bytes.Buffer
+	gz := gzip.NewWriter(&data)
+	enc := json.NewEncoder(gz)
+	for i := range rows {
+		if err := enc.Encode(&rows[i]); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	manifest, err := json.MarshalIndent(researchManifest{
+		SchemaVersion: export.SchemaVersion,
+		Day:           export.Day,
+		Rows:          export.Rows,
+		Participants:  export.Participants,
+		DataKey:       prefix + "readings.jsonl.gz",
+		Fields:        researchSchemaFields,
+		GeneratedAt:   time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Data first: a manifest only ever points at a complete file.
+	if err := s.store.Put(ctx, prefix+"readings.jsonl.gz", data.Bytes(), "application/gzip"); err != nil {
+		return err
+	}
+	return s.store.Put(ctx, prefix+"manifest.json", manifest, "application/json")
+}
+
+// participantID is a keyed hash of the user ID, so participants can be
+// followed across days without being linkable back to an account.
+func (s *ResearchExportService) participantID(userID uuid.UUID) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.ResearchIDSecret))
+	fmt.Fprintf(mac, "v%d:%s", researchSchemaVersion, userID)
+	return hex.EncodeToString(mac.Sum(nil))[:20]
+}
+
+// coarseRegion reduces an IANA timezone to its continent ("Europe/Istanbul"
+// becomes "europe").
+func coarseRegion(tz string) string {
+	area, _, found := strings.Cut(strings.TrimSpace(tz), "/")
+	if !found {
+		return "unknown"
+	}
+	switch area = strings.ToLower(area); area {
+	case "africa", "america", "antarctica", "asia", "atlantic", "australia", "europe", "indian", "pacific":
+		return area
+	}
+	return "unknown"
+}
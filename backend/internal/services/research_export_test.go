@@ -0,0 +1,94 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+type memoryObjectStore struct {
+	objects map[string][]byte
+	keys    []string
+}
+
+func (m *memoryObjectStore) Put(_ context.Context, key string, body []byte, _ string) error {
+	m.objects[key] = body
+	m.keys = append(m.keys, key)
+	return nil
+}
+
+func (m *memoryObjectStore) Name() string { return "memory" }
+
+func TestCoarseRegion(t *testing.T) {
+	cases := map[string]string{
+		"Europe/Istanbul":                "europe",
+		"America/Argentina/Buenos_Aires": "america",
+		"UTC":                            "unknown",
+		"Etc/GMT+3":                      "unknown",
+		"":                               "unknown",
+	}
+	for tz, want := range cases {
+		if got := coarseRegion(tz); got != want {
+			t.Errorf("coarseRegion(%q) = %q, want %q", tz, got, want)
+		}
+	}
+}
+
+func TestParticipantID(t *testing.T) {
+	userID := uuid.New()
+	a := &ResearchExportService{cfg: &config.Config{ResearchIDSecret: "one"}}
+	b := &ResearchExportService{cfg: &config.Config{ResearchIDSecret: "two"}}
+
+	if a.participantID(userID) != a.participantID(userID) {
+		t.Error("participant ID is not stable for one secret")
+	}
+	if a.participantID(userID) == b.participantID(userID) {
+		t.Error("participant ID does not depend on the secret")
+	}
+	if strings.Contains(a.participantID(userID), strings.ReplaceAll(userID.String(), "-", "")[:8]) {
+		t.Error("participant ID leaks the user ID")
+	}
+}
+
+func TestResearchUploadWritesDataThenManifest(t *testing.T) {
+	store := &memoryObjectStore{objects: map[string][]byte{}}
+	s := &ResearchExportService{cfg: &config.Config{}, store: store}
+	export := &models.ResearchExport{SchemaVersion: researchSchemaVersion, Day: "2026-10-16", Rows: 2, Participants: 1}
+	rows := []researchRow{
+		{Participant: "p1", AuraColor: "blue", EnergyLevel: 70, MoodScore: 8, ScannedAt: "2026-10-16T09:00:00Z", Region: "europe"},
+		{Participant: "p1", AuraColor: "gold", EnergyLevel: 60, MoodScore: 6, ScannedAt: "2026-10-16T18:00:00Z", Region: "europe"},
+	}
+
+	prefix := "research/v1/day=2026-10-16/"
+	if err := s.upload(context.Background(), prefix, export, rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.keys) != 2 || store.keys[0] != prefix+"readings.jsonl.gz" || store.keys[1] != prefix+"manifest.json" {
+		t.Fatalf("keys = %v", store.keys)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(store.objects[prefix+"readings.jsonl.gz"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(gz)
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 {
+		t.Errorf("data has %d lines, want 2", len(lines))
+	}
+
+	var manifest researchManifest
+	if err := json.Unmarshal(store.objects[prefix+"manifest.json"], &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.SchemaVersion != researchSchemaVersion || manifest.Rows != 2 || len(manifest.Fields) != len(researchSchemaFields) {
+		t.Errorf("manifest = %+v", manifest)
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrScanBatchNotFound = errors.New("scan batch not found")
+
+// Scan batch statuses: processing while any of its readings is pending.
+const (
+	ScanBatchProcessing = "processing"
+	ScanBatchDone       = "done"
+)
+
+// CreateBatch imports several photos, e.g. old ones from the camera roll, as
+// async scans. Each photo goes through CreateInstant's screening and queue
+// like a single async scan and uses one daily scan. A photo refused before
+// analysis is recorded on the batch and the others go ahead; clients follow
+// the whole import with GetBatch.
+func (s *AuraService) CreateBatch(ctx context.Context, userID uuid.UUID, req dto.CreateScanBatchRequest) (*models.ScanBatch, error) {
+	ctx, span := tracer.Start(ctx, "AuraService.CreateBatch")
+	defer span.End()
+	db := s.db.WithContext(ctx)
+
+	batch := &models.ScanBatch{UserID: userID, ImageCount: len(req.Images)}
+	if err := db.Create(batch).Error; err != nil {
+		return nil, err
+	}
+
+	for i, image := range req.Images {
+		reading, err := s.createInstant(ctx, userID, dto.CreateAuraRequest{
+			ImageURL:  image.ImageURL,
+			ImageData: image.ImageData,
+			Async:     true,
+			Locale:    req.Locale,
+		}, &batch.ID)
+		if err != nil {
+			batch.Rejected = append(batch.Rejected, batchRejection(i, err))
+			continue
+		}
+		batch.Readings = append(batch.Readings, *reading)
+	}
+
+	if len(batch.Rejected) > 0 {
+		if err := db.Model(&models.ScanBatch{ID: batch.ID}).Select("rejected").Updates(&models.ScanBatch{Rejected: batch.Rejected}).Error; err != nil {
+			log.Printf("scan batch %s: failed to store rejected photos: %v", batch.ID, err)
+		}
+	}
+	summarizeBatch(batch)
+	return batch, nil
+}
+
+// GetBatch returns one of the user's scan batches with its readings in the
+// order they were scanned.
+func (s *AuraService) GetBatch(ctx context.Context, userID, id uuid.UUID) (*models.ScanBatch, error) {
+	var batch models.ScanBatch
+	err := s.db.WithContext(ctx).
+		Preload("Readings", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Where("id = ? AND user_id = ?", id, userID).First(&batch).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrScanBatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	summarizeBatch(&batch)
+	return &batch, nil
+}
+
+// batchRejection records why a batch photo was not scanned, under the code
+// the same error gets from a single scan. Unexpected errors are logged and
+// reported generically.
+func batchRejection(index int, err error) models.ScanBatchRejection {
+	rejection := models.ScanBatchRejection{Index: index, Message: err.Error()}
+	switch {
+	case errors.Is(err, ErrImageRejected):
+		rejection.Code = "image_rejected"
+	case errors.Is(err, ErrNoFaceDetected):
+		rejection.Code = "no_face_detected"
+	case errors.Is(err, ErrImageModerationUnavailable):
+		rejection.Code = "image_moderation_unavailable"
+	default:
+		log.Printf("scan batch photo %d: %v", index, err)
+		rejection.Code = "scan_failed"
+		rejection.Message = "this photo could not be scanned; try it again on its own"
+	}
+	return rejection
+}
+
+// summarizeBatch counts the batch's pending and ready readings and sets its
+// status.
+func summarizeBatch(batch *models.ScanBatch) {
+	batch.Pending, batch.Ready = 0, 0
+	for _, r := range batch.Readings {
+		if r.Status == models.ReadingStatusPending {
+			batch.Pending++
+		} else {
+			batch.Ready++
+		}
+	}
+	batch.Status = ScanBatchDone
+	if batch.Pending > 0 {
+		batch.Status = ScanBatchProcessing
+	}
+	if batch.Rejected == nil {
+		batch.Rejected = []models.ScanBatchRejection{}
+	}
+	if batch.Readings == nil {
+		batch.Readings = []models.AuraReading{}
+	}
+}
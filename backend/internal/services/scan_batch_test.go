@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func TestBatchRejectionCodes(t *testing.T) {
+	cases := []struct {
+		err  error
+		code string
+	}{
+		{ErrNoFaceDetected, "no_face_detected"},
+		{fmt.Errorf("screening: %w", ErrImageRejected), "image_rejected"},
+		{ErrImageModerationUnavailable, "image_moderation_unavailable"},
+		{errors.New("pq: connection refused"), "scan_failed"},
+	}
+	for i, c := range cases {
+		r := batchRejection(i, c.err)
+		if r.Index != i || r.Code != c.code || r.Message == "" {
+			t.Errorf("%v: rejection = %+v, want code %s", c.err, r, c.code)
+		}
+	}
+	// Internal errors stay in the logs.
+	if r := batchRejection(0, errors.New("pq: connection refused")); r.Message == "pq: connection refused" {
+		t.Error("an internal error reached the client")
+	}
+}
+
+func TestSummarizeBatch(t *testing.T) {
+	batch := &models.ScanBatch{Readings: []models.AuraReading{
+		{Status: models.ReadingStatusReady},
+		{Status: models.ReadingStatusPending},
+		{Status: models.ReadingStatusReady},
+	}}
+	summarizeBatch(batch)
+	if batch.Status != ScanBatchProcessing || batch.Pending != 1 || batch.Ready != 2 {
+		t.Errorf("batch = %s pending=%d ready=%d", batch.Status, batch.Pending, batch.Ready)
+	}
+
+	batch.Readings[1].Status = models.ReadingStatusReady
+	summarizeBatch(batch)
+	if batch.Status != ScanBatchDone || batch.Pending != 0 || batch.Ready != 3 {
+		t.Errorf("batch = %s pending=%d ready=%d", batch.Status, batch.Pending, batch.Ready)
+	}
+
+	// A batch whose every photo was refused is done, with empty lists
+	// rather than nulls.
+	empty := &models.ScanBatch{}
+	summarizeBatch(empty)
+	if empty.Status != ScanBatchDone || empty.Readings == nil || empty.Rejected == nil {
+		t.Errorf("empty batch = %+v", empty)
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrScanJobNotFound = errors.New("scan job not found")
+
+// Scan job statuses reported to clients.
+const (
+	ScanJobQueued     = "queued"
+	ScanJobProcessing = "processing"
+	ScanJobDone       = "done"
+)
+
+const (
+	// scanJobDefaultDuration seeds the wait estimate until jobs have run.
+	scanJobDefaultDuration = 8 * time.Second
+	// scanJobDurationWeight is the weight of the latest job in the moving
+	// average of job durations.
+	scanJobDurationWeight = 0.2
+)
+
+type scanJob struct {
+	id        uuid.UUID
+	run       func()
+	startedAt time.Time
+	watchers  []chan dto.ScanJobPosition
+}
+
+// scanQueue runs the background analyses of async scans on a fixed pool of
+// workers, so under load jobs wait in line instead of all hitting the AI
+// providers at once. It knows each job's place in that line and pushes
+// position changes to watchers. Positions are per process: a job queued on
+// another instance is unknown here.
+type scanQueue struct {
+	mu      sync.Mutex
+	ready   *sync.Cond
+	workers int
+	waiting []*scanJob
+	jobs    map[uuid.UUID]*scanJob
+	avg     time.Duration
+}
+
+func newScanQueue(workers int) *scanQueue {
+	if workers <= 0 {
+		workers = 1
+	}
+	q := &scanQueue{
+		workers: workers,
+		jobs:    make(map[uuid.UUID]*scanJob),
+		avg:     scanJobDefaultDuration,
+	}
+	q.ready = sync.NewCond(&q.mu)
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+// enqueue adds a job to the back of the line.
+func (q *scanQueue) enqueue(id uuid.UUID, run func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job := &scanJob{id: id, run: run}
+	q.jobs[id] = job
+	q.waiting = append(q.waiting, job)
+	q.ready.Signal()
+}
+
+func (q *scanQueue) work() {
+	for {
+		q.mu.Lock()
+		for len(q.waiting) == 0 {
+			q.ready.Wait()
+		}
+		job := q.waiting[0]
+		q.waiting = q.waiting[1:]
+		job.startedAt = time.Now()
+		// Everyone behind the started job moved up one place.
+		q.notifyLocked(job)
+		for _, waiting := range q.waiting {
+			q.notifyLocked(waiting)
+		}
+		q.mu.Unlock()
+
+		job.run()
+
+		q.mu.Lock()
+		elapsed := time.Since(job.startedAt)
+		q.avg = time.Duration(scanJobDurationWeight*float64(elapsed) + (1-scanJobDurationWeight)*float64(q.avg))
+		delete(q.jobs, job.id)
+		for _, ch := range job.watchers {
+			sendLatest(ch, dto.ScanJobPosition{JobID: job.id.String(), Status: ScanJobDone})
+			close(ch)
+		}
+		job.watchers = nil
+		q.mu.Unlock()
+	}
+}
+
+// position reports where a job stands, and false when the queue does not
+// know it (finished, or queued elsewhere).
+func (q *scanQueue) position(id uuid.UUID) (dto.ScanJobPosition, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return dto.ScanJobPosition{}, false
+	}
+	return q.positionLocked(job), true
+}
+
+func (q *scanQueue) positionLocked(job *scanJob) dto.ScanJobPosition {
+	pos := dto.ScanJobPosition{JobID: job.id.String(), QueueLength: len(q.waiting)}
+	if !job.startedAt.IsZero() {
+		pos.Status = ScanJobProcessing
+		pos.EstimatedWaitSeconds = waitSeconds(q.avg - time.Since(job.startedAt))
+		return pos
+	}
+	for i, waiting := range q.waiting {
+		if waiting == job {
+			pos.Position = i + 1
+			break
+		}
+	}
+	pos.Status = ScanJobQueued
+	// Jobs start in rounds of q.workers; this one starts after the rounds
+	// ahead of it and then takes about one job's time.
+	rounds := (pos.Position-1)/q.workers + 1
+	pos.EstimatedWaitSeconds = waitSeconds(time.Duration(rounds) * q.avg)
+	return pos
+}
+
+// watch returns a channel carrying the job's position whenever it changes,
+// ending with a done update, and a func to stop watching. ok is false when
+// the queue does not know the job.
+func (q *scanQueue) watch(id uuid.UUID) (<-chan dto.ScanJobPosition, func(), bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, exists := q.jobs[id]
+	if !exists {
+		return nil, func() {}, false
+	}
+	ch := make(chan dto.ScanJobPosition, 1)
+	job.watchers = append(job.watchers, ch)
+	stop := func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		for i, w := range job.watchers {
+			if w == ch {
+				job.watchers = append(job.watchers[:i], job.watchers[i+1:]...)
+				close(ch)
+				return
+			}
+		}
+	}
+	return ch, stop, true
+}
+
+func (q *scanQueue) notifyLocked(job *scanJob) {
+	if len(job.watchers) == 0 {
+		return
+	}
+	pos := q.positionLocked(job)
+	for _, ch := range job.watchers {
+		sendLatest(ch, pos)
+	}
+}
+
+// sendLatest delivers the latest update without blocking the queue, replacing
+// an update the watcher has not read yet.
+func sendLatest(ch chan dto.ScanJobPosition, pos dto.ScanJobPosition) {
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- pos
+}
+
+// waitSeconds rounds a wait up to whole seconds, at least one.
+func waitSeconds(d time.Duration) int {
+	s := int((d + time.Second - 1) / time.Second)
+	if s < 1 {
+		return 1
+	}
+	return s
+}
+
+// ScanJobPosition reports where one of the user's async scans stands. A
+// pending scan this instance does not know is being analyzed by another
+// one, so it is reported as processing.
+func (s *AuraService) ScanJobPosition(ctx context.Context, userID, jobID uuid.UUID) (*dto.ScanJobPosition, error) {
+	var reading models.AuraReading
+	err := s.db.WithContext(ctx).Select("id", "status").
+		Where("user_id = ? AND id = ?", userID, jobID).
+		First(&reading).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrScanJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if reading.Status != models.ReadingStatusPending {
+		return &dto.ScanJobPosition{JobID: jobID.String(), Status: ScanJobDone}, nil
+	}
+	if pos, ok := s.scanQueue.position(jobID); ok {
+		return &pos, nil
+	}
+	return &dto.ScanJobPosition{
+		JobID:                jobID.String(),
+		Status:               ScanJobProcessing,
+		EstimatedWaitSeconds: waitSeconds(scanJobDefaultDuration),
+	}, nil
+}
+
+// WatchScanJob streams a job's position changes until it is done. ok is
+// false when this instance is not running the job.
+func (s *AuraService) WatchScanJob(jobID uuid.UUID) (updates <-chan dto.ScanJobPosition, stop func(), ok bool) {
+	return s.scanQueue.watch(jobID)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestScanQueuePositions(t *testing.T) {
+	q := newScanQueue(1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	q.enqueue(first, func() {
+		close(started)
+		<-release
+	})
+	<-started
+	q.enqueue(second, func() {})
+	q.enqueue(third, func() {})
+
+	if pos, _ := q.position(first); pos.Status != ScanJobProcessing || pos.Position != 0 {
+		t.Errorf("first = %+v, want processing", pos)
+	}
+	pos, ok := q.position(third)
+	if !ok || pos.Status != ScanJobQueued || pos.Position != 2 || pos.QueueLength != 2 {
+		t.Errorf("third = %+v, want queued at 2 of 2", pos)
+	}
+	if want := waitSeconds(2 * scanJobDefaultDuration); pos.EstimatedWaitSeconds != want {
+		t.Errorf("third wait = %d, want %d", pos.EstimatedWaitSeconds, want)
+	}
+
+	updates, stop, ok := q.watch(third)
+	if !ok {
+		t.Fatal("watch of a queued job failed")
+	}
+	defer stop()
+	close(release)
+
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case update, open := <-updates:
+			if !open {
+				t.Fatal("updates closed before done")
+			}
+			if update.Status == ScanJobDone {
+				if _, known := q.position(third); known {
+					t.Error("finished job still in the queue")
+				}
+				return
+			}
+		case <-deadline:
+			t.Fatal("no done update")
+		}
+	}
+}
+
+func TestWaitSeconds(t *testing.T) {
+	cases := map[time.Duration]int{
+		-time.Second:            1,
+		0:                       1,
+		1500 * time.Millisecond: 2,
+		8 * time.Second:         8,
+	}
+	for d, want := range cases {
+		if got := waitSeconds(d); got != want {
+			t.Errorf("waitSeconds(%s) = %d, want %d", d, got, want)
+		}
+	}
+}
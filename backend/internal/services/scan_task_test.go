@@ -0,0 +1,34 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/queue"
+	"github.com/google/uuid"
+)
+
+// A queued scan is finished by another process, so everything the analysis
+// uses must survive the trip through the task payload.
+func TestScanTaskSurvivesThePayload(t *testing.T) {
+	secondary := "teal"
+	want := scanTask{
+		UserID:    uuid.New(),
+		ReadingID: uuid.New(),
+		ImageURL:  "https://cdn.example.com/p.jpg",
+		Base:      auraAnalysisResult{AuraColor: "violet", SecondaryColor: &secondary, EnergyLevel: 72, MoodScore: 8},
+		Options:   auraAnalysisOptions{History: "blue, blue", Memory: "likes hiking", Language: "de"},
+	}
+	payload, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got scanTask
+	if err := queue.Decode(payload, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// ListSessions returns the user's signed-in devices. Each active session is
+// the one unrevoked, unexpired token of its family.
+func (s *AuthService) ListSessions(userID, currentSessionID uuid.UUID) ([]dto.SessionResponse, error) {
+	var tokens []models.RefreshToken
+	err := s.db.Where("user_id = ? AND revoked = false AND expires_at > ? AND family_id IS NOT NULL", userID, time.Now()).
+		Order("last_used_at DESC NULLS LAST").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return []dto.SessionResponse{}, nil
+	}
+
+	familyIDs := make([]uuid.UUID, 0, len(tokens))
+	for _, t := range tokens {
+		familyIDs = append(familyIDs, t.FamilyID)
+	}
+	var starts []struct {
+		FamilyID  uuid.UUID
+		StartedAt time.Time
+	}
+	s.db.Model(&models.RefreshToken{}).
+		Select("family_id, MIN(created_at) AS started_at").
+		Where("family_id IN ?", familyIDs).
+		Group("family_id").
+		Scan(&starts)
+	startedAt := make(map[uuid.UUID]time.Time, len(starts))
+	for _, st := range starts {
+		startedAt[st.FamilyID] = st.StartedAt
+	}
+
+	sessions := make([]dto.SessionResponse, 0, len(tokens))
+	for _, t := range tokens {
+		signedIn, ok := startedAt[t.FamilyID]
+		if !ok {
+			signedIn = t.CreatedAt
+		}
+		sessions = append(sessions, dto.SessionResponse{
+			ID:         t.FamilyID,
+			DeviceName: t.DeviceName,
+			Platform:   t.Platform,
+			AppVersion: t.AppVersion,
+			UserAgent:  t.UserAgent,
+			IPAddress:  t.IPAddress,
+			LastUsedAt: t.LastUsedAt,
+			SignedInAt: signedIn,
+			Current:    t.FamilyID == currentSessionID,
+		})
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one device out: its refresh token can no longer be
+// used and its current access token is rejected from the next request.
+func (s *AuthService) RevokeSession(userID, sessionID uuid.UUID) error {
+	result := s.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND family_id = ? AND revoked = false", userID, sessionID).
+		Updates(map[string]interface{}{
+			"revoked":        true,
+			"revoked_reason": models.RevokeReasonRevoked,
+			"revoked_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrShareTokenInvalid  = errors.New("invalid share link")
+	ErrShareTokenExpired  = errors.New("share link expired")
+	ErrReadingNotFound    = errors.New("reading not found")
+	ErrShareNotConfigured = errors.New("share links not configured")
+)
+
+const (
+	shareTokenPayloadLen = 16 + 8 // reading UUID + unix expiry
+	shareTokenSigLen     = 16
+)
+
+// auraColorHex maps aura colors to the brand palette used for share previews.
+var auraColorHex = map[string]string{
+	"red":    "#EF4444",
+	"orange": "#F97316",
+	"yellow": "#EAB308",
+	"green":  "#22C55E",
+	"blue":   "#3B82F6",
+	"indigo": "#6366F1",
+	"violet": "#8B5CF6",
+	"white":  "#F8FAFC",
+	"gold":   "#D4A017",
+	"pink":   "#EC4899",
+}
+
+type ShareService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewShareService(db *gorm.DB, cfg *config.Config) *ShareService {
+	return &ShareService{db: db, cfg: cfg}
+}
+
+// CreateLink issues a signed, expiring token for a reading owned by userID.
+// Tokens are stateless: the reading ID and expiry are embedded and HMAC-signed.
+func (s *ShareService) CreateLink(userID, readingID uuid.UUID, baseURL string) (*dto.ShareLinkResponse, error) {
+	if strings.TrimSpace(s.cfg.ShareSecret) == "" {
+		return nil, ErrShareNotConfigured
+	}
+
+	var reading models.AuraReading
+	if err := s.db.Select("id").Where("user_id = ? AND id = ?", userID, readingID).First(&reading).Error; err != nil {
+		return nil, ErrReadingNotFound
+	}
+	// Counted for prompt experiment share rates; failure must not block sharing.
+	s.db.Model(&models.AuraReading{}).Where("id = ?", reading.ID).UpdateColumn("share_count", gorm.Expr("share_count + 1"))
+
+	ttl := s.cfg.ShareLinkTTL
+	if ttl <= 0 {
+		ttl = 30 * 24 * time.Hour
+	}
+	expiresAt := time.Now().Add(ttl).UTC().Truncate(time.Second)
+	token := s.signToken(reading.ID, expiresAt)
+
+	if configured := strings.TrimSpace(s.cfg.PublicBaseURL); configured != "" {
+		baseURL = configured
+	}
+
+	return &dto.ShareLinkResponse{
+		Token:     token,
+		URL:       strings.TrimRight(baseURL, "/") + "/api/share/" + token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Resolve verifies a share token and returns the sanitized public view of the reading.
+func (s *ShareService) Resolve(token, shareURL string) (*dto.PublicReadingResponse, error) {
+	readingID, err := s.verifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var reading models.AuraReading
+	if err := s.db.Scopes(activeUsersOnly("user_id")).Where("id = ?", readingID).First(&reading).Error; err != nil {
+		return nil, ErrReadingNotFound
+	}
+
+	return &dto.PublicReadingResponse{
+		AuraColor:      reading.AuraColor,
+		SecondaryColor: reading.SecondaryColor,
+		EnergyLevel:    reading.EnergyLevel,
+		MoodScore:      reading.MoodScore,
+		Personality:    reading.Personality,
+		Strengths:      reading.Strengths,
+		Challenges:     reading.Challenges,
+		DailyAdvice:    reading.DailyAdvice,
+		AnalyzedAt:     reading.AnalyzedAt,
+		Theme:          readingTheme(reading),
+		OpenGraph:      shareOpenGraph(reading, shareURL),
+	}, nil
+}
+
+func (s *ShareService) signToken(readingID uuid.UUID, expiresAt time.Time) string {
+	buf := make([]byte, shareTokenPayloadLen, shareTokenPayloadLen+shareTokenSigLen)
+	copy(buf, readingID[:])
+	binary.BigEndian.PutUint64(buf[16:], uint64(expiresAt.Unix()))
+	buf = append(buf, s.signature(buf)...)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func (s *ShareService) verifyToken(token string) (uuid.UUID, error) {
+	if strings.TrimSpace(s.cfg.ShareSecret) == "" {
+		return uuid.Nil, ErrShareNotConfigured
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil || len(raw) != shareTokenPayloadLen+shareTokenSigLen {
+		return uuid.Nil, ErrShareTokenInvalid
+	}
+
+	payload, sig := raw[:shareTokenPayloadLen], raw[shareTokenPayloadLen:]
+	if !hmac.Equal(sig, s.signature(payload)) {
+		return uuid.Nil, ErrShareTokenInvalid
+	}
+
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0)
+	if time.Now().After(expiresAt) {
+		return uuid.Nil, ErrShareTokenExpired
+	}
+
+	readingID, err := uuid.FromBytes(payload[:16])
+	if err != nil {
+		return uuid.Nil, ErrShareTokenInvalid
+	}
+	return readingID, nil
+}
+
+func (s *ShareService) signature(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte("share:v1:"+s.cfg.ShareSecret))
+	mac.Write(payload)
+	return mac.Sum(nil)[:shareTokenSigLen]
+}
+
+func shareOpenGraph(reading models.AuraReading, shareURL string) map[string]string {
+	color := reading.AuraColor
+	title := fmt.Sprintf("My aura is %s ✨ | AuraSnap", titleCase(color))
+	description := reading.Personality
+	if description == "" {
+		description = "Discover the color of your aura with AuraSnap."
+	}
+	description = fmt.Sprintf("%s Energy %d/100 · Mood %d/10.", description, reading.EnergyLevel, reading.MoodScore)
+
+	themeColor := auraColorHex[color]
+	if themeColor == "" {
+		themeColor = auraColorHex["violet"]
+	}
+
+	return map[string]string{
+		"og:type":             "website",
+		"og:site_name":        "AuraSnap",
+		"og:title":            title,
+		"og:description":      description,
+		"og:url":              shareURL,
+		"twitter:card":        "summary",
+		"twitter:title":       title,
+		"twitter:description": description,
+		"theme-color":         themeColor,
+	}
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestShareTokenRoundTrip(t *testing.T) {
+	svc := NewShareService(nil, &config.Config{ShareSecret: "test-secret"})
+	readingID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+
+	token := svc.signToken(readingID, time.Now().Add(time.Hour))
+	got, err := svc.verifyToken(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != readingID {
+		t.Fatalf("expected %s, got %s", readingID, got)
+	}
+
+	other := NewShareService(nil, &config.Config{ShareSecret: "other-secret"})
+	if _, err := other.verifyToken(token); !errors.Is(err, ErrShareTokenInvalid) {
+		t.Fatalf("expected invalid token with different secret, got %v", err)
+	}
+
+	tampered := []byte(token)
+	tampered[3] ^= 1
+	if _, err := svc.verifyToken(string(tampered)); !errors.Is(err, ErrShareTokenInvalid) {
+		t.Fatalf("expected invalid token after tampering, got %v", err)
+	}
+}
+
+func TestShareTokenExpired(t *testing.T) {
+	svc := NewShareService(nil, &config.Config{ShareSecret: "test-secret"})
+	token := svc.signToken(uuid.New(), time.Now().Add(-time.Minute))
+
+	if _, err := svc.verifyToken(token); !errors.Is(err, ErrShareTokenExpired) {
+		t.Fatalf("expected expired token error, got %v", err)
+	}
+}
+
+func TestReadingThemeBlendsPhotoPalette(t *testing.T) {
+	plain := readingTheme(models.AuraReading{AuraColor: "blue"})
+	if plain.Accent != "#3B82F6" || len(plain.Background) != 2 || len(plain.Palette) != 0 {
+		t.Fatalf("theme without palette: %+v", plain)
+	}
+
+	themed := readingTheme(models.AuraReading{AuraColor: "blue", Palette: []string{"#f08c14", "#ffffff", "#000000", "#00ff00"}})
+	if len(themed.Background) != 3 {
+		t.Fatalf("expected three background stops, got %v", themed.Background)
+	}
+	if themed.Background[0] == themed.Accent || themed.Background[0] == "#f08c14" {
+		t.Fatalf("background should blend aura and photo, got %s", themed.Background[0])
+	}
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// discoveryMinAge is the age both sides of a similar-aura match must
+	// have reached.
+	discoveryMinAge = 18
+	// discoveryWindow is how far back a trajectory looks.
+	discoveryWindow = 90 * 24 * time.Hour
+	// discoveryCandidates caps the opted-in users compared per request,
+	// most recently active first.
+	discoveryCandidates  = 500
+	minDiscoveryReadings = 3
+	maxSimilarProfiles   = 20
+	// minProfileSimilarity drops matches too loose to be worth showing.
+	minProfileSimilarity = 0.7
+	// energyTrendDelta is the change in average energy, between the older
+	// and the newer half of the window, that counts as a trend.
+	energyTrendDelta = 5
+)
+
+const (
+	EnergyRising  = "rising"
+	EnergyFalling = "falling"
+	EnergySteady  = "steady"
+)
+
+var (
+	ErrInvalidBirthYear       = errors.New("birth year is not valid")
+	ErrBirthYearRequired      = errors.New("a birth year is required to opt into similar-aura discovery")
+	ErrDiscoveryAgeRestricted = errors.New("similar-aura discovery is only open to adults")
+	ErrDiscoveryOptInRequired = errors.New("opt into similar-aura discovery to see similar users")
+	ErrDiscoveryRegionUnknown = errors.New("set your timezone to find similar auras in your region")
+)
+
+// SimilarDiscoveryService matches opted-in adults in the same region whose
+// aura trajectories over the last months resemble each other. Trajectories
+// compare color mix, energy and mood in the older and the newer half of
+// the window; reading embeddings are blended in when the vector store is
+// on. Results are anonymized cards, meant to seed community features.
+type SimilarDiscoveryService struct {
+	db      *gorm.DB
+	cfg     *config.Config
+	vectors *ReadingEmbeddingService
+}
+
+func NewSimilarDiscoveryService(db *gorm.DB, cfg *config.Config, vectors *ReadingEmbeddingService) *SimilarDiscoveryService {
+	return &SimilarDiscoveryService{db: db, cfg: cfg, vectors: vectors}
+}
+
+// discoveryAdult reports whether someone born in birthYear is an adult for
+// sure: the age is counted as of the start of now's year.
+func discoveryAdult(birthYear *int, now time.Time) bool {
+	return birthYear != nil && now.Year()-*birthYear-1 >= discoveryMinAge
+}
+
+// discoveryName is the stable anonymous name a user is shown under in
+// discovery, "<color> <animal> #NNNN".
+func discoveryName(userID uuid.UUID) string {
+	sum := sha256.Sum256(append([]byte("discovery:"), userID[:]...))
+	return formatLeaderboardName(anonymousBase(userID), int(binary.BigEndian.Uint16(sum[:2]))%10000)
+}
+
+// UpdateSettings opts the user in or out of similar-aura discovery and
+// records their birth year. A user who is not certainly an adult can't be
+// opted in.
+func (s *SimilarDiscoveryService) UpdateSettings(ctx context.Context, userID uuid.UUID, req *dto.SimilarDiscoverySettingsRequest) (*dto.SimilarDiscoverySettingsResponse, error) {
+	now := time.Now()
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	updates := map[string]interface{}{}
+	if req.BirthYear != nil {
+		if *req.BirthYear < 1900 || *req.BirthYear > now.Year() {
+			return nil, ErrInvalidBirthYear
+		}
+		user.BirthYear = req.BirthYear
+		updates["birth_year"] = *req.BirthYear
+	}
+	if req.OptIn != nil {
+		user.SimilarDiscoveryOptIn = *req.OptIn
+		updates["similar_discovery_opt_in"] = *req.OptIn
+	}
+	if user.SimilarDiscoveryOptIn && !discoveryAdult(user.BirthYear, now) {
+		if user.BirthYear == nil {
+			return nil, ErrBirthYearRequired
+		}
+		return nil, ErrDiscoveryAgeRestricted
+	}
+	if len(updates) > 0 {
+		if err := s.db.WithContext(ctx).Model(&user).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &dto.SimilarDiscoverySettingsResponse{
+		OptIn:       user.SimilarDiscoveryOptIn,
+		BirthYear:   user.BirthYear,
+		DisplayName: discoveryName(user.ID),
+	}, nil
+}
+
+// Similar returns anonymized profiles of opted-in adults in the caller's
+// region whose aura trajectories are closest to theirs, most similar
+// first. Discovery is reciprocal: only opted-in users can search. Users
+// blocked in either direction, banned or shadow-banned never appear.
+func (s *SimilarDiscoveryService) Similar(ctx context.Context, userID uuid.UUID, limit int) ([]dto.SimilarProfile, error) {
+	ctx, span := tracer.Start(ctx, "SimilarDiscoveryService.Similar")
+	defer span.End()
+
+	if limit <= 0 || limit > maxSimilarProfiles {
+		limit = maxSimilarProfiles
+	}
+	now := time.Now()
+	var user models.User
+	if err := s.db.WithContext(ctx).Select("id", "timezone", "birth_year", "similar_discovery_opt_in").
+		First(&user, "id = ?", userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.SimilarDiscoveryOptIn {
+		return nil, ErrDiscoveryOptInRequired
+	}
+	if !discoveryAdult(user.BirthYear, now) {
+		return nil, ErrDiscoveryAgeRestricted
+	}
+	region := coarseRegion(user.Timezone)
+	if region == "unknown" {
+		return nil, ErrDiscoveryRegionUnknown
+	}
+
+	since := now.Add(-discoveryWindow)
+	var candidates []models.User
+	err := s.db.WithContext(ctx).Scopes(database.Replica).Select("users.id", "users.timezone").
+		Joins("JOIN user_aggregates ON user_aggregates.user_id = users.id").
+		Where("users.similar_discovery_opt_in = true AND users.birth_year <= ? AND users.id <> ?", now.Year()-1-discoveryMinAge, userID).
+		Where("LOWER(users.timezone) LIKE ?", region+"/%").
+		Where("user_aggregates.last_scan_at >= ?", since).
+		Where("users.id NOT IN (?)", s.db.Model(&models.Block{}).Select("blocked_id").Where("blocker_id = ?", userID)).
+		Where("users.id NOT IN (?)", s.db.Model(&models.Block{}).Select("blocker_id").Where("blocked_id = ?", userID)).
+		Where("users.id NOT IN (?)", s.db.Model(&models.ModerationAction{}).Scopes(activeActions).Select("user_id").Where("type IN ?", lockoutActions)).
+		Scopes(notShadowBanned("users.id")).
+		Order("user_aggregates.last_scan_at DESC").
+		Limit(discoveryCandidates).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return []dto.SimilarProfile{}, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(candidates)+1)
+	ids = append(ids, userID)
+	for _, c := range candidates {
+		ids = append(ids, c.ID)
+	}
+	points, err := s.trajectoryPoints(ctx, ids, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(points[userID]) < minDiscoveryReadings {
+		return []dto.SimilarProfile{}, nil
+	}
+	mid := since.Add(discoveryWindow / 2)
+	mine := trajectoryVector(points[userID], mid)
+	embeddings := s.meanEmbeddings(ctx, ids, since)
+
+	type match struct {
+		userID     uuid.UUID
+		similarity float64
+	}
+	var matches []match
+	for _, c := range candidates {
+		if len(points[c.ID]) < minDiscoveryReadings {
+			continue
+		}
+		sim := cosineSimilarity(mine, trajectoryVector(points[c.ID], mid))
+		if a, b := embeddings[userID], embeddings[c.ID]; a != nil && b != nil {
+			sim = (sim + cosineSimilarity(a, b)) / 2
+		}
+		if sim >= minProfileSimilarity {
+			matches = append(matches, match{c.ID, sim})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].similarity > matches[j].similarity })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	profiles := make([]dto.SimilarProfile, 0, len(matches))
+	for _, m := range matches {
+		p := points[m.userID]
+		profiles = append(profiles, dto.SimilarProfile{
+			DisplayName:   discoveryName(m.userID),
+			Region:        region,
+			DominantColor: dominantTrajectoryColor(p),
+			CurrentColor:  dominantTrajectoryColor(afterTime(p, mid)),
+			EnergyTrend:   energyTrend(p, mid),
+			Similarity:    math.Round(m.similarity*100) / 100,
+		})
+	}
+	return profiles, nil
+}
+
+// trajectoryPoint is one reading on a user's aura trajectory.
+type trajectoryPoint struct {
+	Color  string
+	Energy int
+	Mood   int
+	At     time.Time
+}
+
+// trajectoryPoints loads the finished personal readings of users since
+// since, oldest first.
+func (s *SimilarDiscoveryService) trajectoryPoints(ctx context.Context, userIDs []uuid.UUID, since time.Time) (map[uuid.UUID][]trajectoryPoint, error) {
+	var readings []models.AuraReading
+	if err := s.db.WithContext(ctx).Scopes(database.Replica, personalReadings).
+		Select("user_id", "aura_color", "energy_level", "mood_score", "created_at").
+		Where("user_id IN ? AND NOT synthetic AND status = ? AND created_at >= ?", userIDs, models.ReadingStatusReady, since).
+		Order("created_at").Find(&readings).Error; err != nil {
+		return nil, err
+	}
+	points := make(map[uuid.UUID][]trajectoryPoint, len(userIDs))
+	for _, r := range readings {
+		points[r.UserID] = append(points[r.UserID], trajectoryPoint{Color: r.AuraColor, Energy: r.EnergyLevel, Mood: r.MoodScore, At: r.CreatedAt})
+	}
+	return points, nil
+}
+
+// meanEmbeddings averages each user's reading embeddings since since. It
+// is empty when the vector store is off or fails; trajectories alone are
+// compared then.
+func (s *SimilarDiscoveryService) meanEmbeddings(ctx context.Context, userIDs []uuid.UUID, since time.Time) map[uuid.UUID][]float64 {
+	if !s.vectors.Enabled() {
+		return nil
+	}
+	var rows []struct {
+		UserID    uuid.UUID
+		Embedding string
+	}
+	if err := s.db.WithContext(ctx).Raw(`SELECT e.user_id, AVG(e.embedding)::text AS embedding
+		FROM reading_embeddings e JOIN aura_readings r ON r.id = e.reading_id
+		WHERE e.user_id IN ? AND r.created_at >= ? AND r.deleted_at IS NULL
+		GROUP BY e.user_id`, userIDs, since).Scan(&rows).Error; err != nil {
+		log.Printf("discovery: loading embeddings: %v", err)
+		return nil
+	}
+	means := make(map[uuid.UUID][]float64, len(rows))
+	for _, row := range rows {
+		v, err := parseVector(row.Embedding)
+		if err != nil {
+			log.Printf("discovery: embedding of %s: %v", row.UserID, err)
+			continue
+		}
+		means[row.UserID] = v
+	}
+	return means
+}
+
+// trajectoryVector describes a trajectory as the color mix, average energy
+// and average mood of its readings before mid, then of those after it. A
+// half without readings takes the other half's values, so a user who only
+// started scanning recently still compares on what they have.
+func trajectoryVector(points []trajectoryPoint, mid time.Time) []float64 {
+	early := trajectoryPhase(beforeTime(points, mid))
+	recent := trajectoryPhase(afterTime(points, mid))
+	if early == nil {
+		early = recent
+	}
+	if recent == nil {
+		recent = early
+	}
+	return append(append([]float64(nil), early...), recent...)
+}
+
+// trajectoryPhase is the color shares, energy (0-1) and mood (0-1) of
+// points, or nil when there are none.
+func trajectoryPhase(points []trajectoryPoint) []float64 {
+	if len(points) == 0 {
+		return nil
+	}
+	v := make([]float64, len(auraColors)+2)
+	n := float64(len(points))
+	for _, p := range points {
+		for i, c := range auraColors {
+			if p.Color == c {
+				v[i] += 1 / n
+			}
+		}
+		v[len(auraColors)] += float64(p.Energy) / 100 / n
+		v[len(auraColors)+1] += float64(p.Mood) / 10 / n
+	}
+	return v
+}
+
+func beforeTime(points []trajectoryPoint, t time.Time) []trajectoryPoint {
+	i := sort.Search(len(points), func(i int) bool { return !points[i].At.Before(t) })
+	return points[:i]
+}
+
+func afterTime(points []trajectoryPoint, t time.Time) []trajectoryPoint {
+	return points[len(beforeTime(points, t)):]
+}
+
+// dominantTrajectoryColor is the most frequent color of points; of colors
+// tied for it, the one that got there last wins.
+func dominantTrajectoryColor(points []trajectoryPoint) string {
+	counts := map[string]int{}
+	best := ""
+	for _, p := range points {
+		counts[p.Color]++
+		if counts[p.Color] >= counts[best] {
+			best = p.Color
+		}
+	}
+	return best
+}
+
+// energyTrend compares the average energy after mid with the one before.
+func energyTrend(points []trajectoryPoint, mid time.Time) string {
+	early, recent := beforeTime(points, mid), afterTime(points, mid)
+	if len(early) == 0 || len(recent) == 0 {
+		return EnergySteady
+	}
+	switch delta := averageEnergy(recent) - averageEnergy(early); {
+	case delta >= energyTrendDelta:
+		return EnergyRising
+	case delta <= -energyTrendDelta:
+		return EnergyFalling
+	}
+	return EnergySteady
+}
+
+func averageEnergy(points []trajectoryPoint) float64 {
+	var sum float64
+	for _, p := range points {
+		sum += float64(p.Energy)
+	}
+	return sum / float64(len(points))
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func trajectory(start time.Time, colors []string, energies ...int) []trajectoryPoint {
+	points := make([]trajectoryPoint, len(colors))
+	for i, c := range colors {
+		points[i] = trajectoryPoint{Color: c, Energy: energies[i], Mood: 6, At: start.AddDate(0, 0, i*10)}
+	}
+	return points
+}
+
+func TestTrajectorySimilarity(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	mid := start.AddDate(0, 0, 45)
+	calmToBright := trajectory(start, []string{"blue", "blue", "indigo", "blue", "blue", "gold", "yellow", "gold", "gold"}, 40, 45, 40, 50, 45, 70, 75, 80, 85)
+	alike := trajectory(start, []string{"blue", "indigo", "blue", "blue", "indigo", "gold", "gold", "yellow", "gold"}, 45, 40, 45, 45, 50, 75, 70, 80, 80)
+	reverse := trajectory(start, []string{"gold", "gold", "yellow", "gold", "gold", "blue", "indigo", "blue", "blue"}, 80, 85, 75, 80, 85, 40, 45, 40, 45)
+
+	mine := trajectoryVector(calmToBright, mid)
+	close := cosineSimilarity(mine, trajectoryVector(alike, mid))
+	far := cosineSimilarity(mine, trajectoryVector(reverse, mid))
+	if close < minProfileSimilarity || far >= close {
+		t.Errorf("similarity to alike = %.2f, to reverse = %.2f; want alike above %.2f and reverse lower", close, far, minProfileSimilarity)
+	}
+
+	if got := dominantTrajectoryColor(calmToBright); got != "blue" {
+		t.Errorf("dominant color = %q, want blue", got)
+	}
+	if got := dominantTrajectoryColor(afterTime(calmToBright, mid)); got != "gold" {
+		t.Errorf("current color = %q, want gold", got)
+	}
+	if got := energyTrend(calmToBright, mid); got != EnergyRising {
+		t.Errorf("energy trend = %q, want rising", got)
+	}
+	if got := energyTrend(reverse, mid); got != EnergyFalling {
+		t.Errorf("energy trend = %q, want falling", got)
+	}
+	if got := energyTrend(calmToBright[:3], mid); got != EnergySteady {
+		t.Errorf("energy trend of one half = %q, want steady", got)
+	}
+}
+
+func TestDiscoveryAdult(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	year := func(y int) *int { return &y }
+	for _, tc := range []struct {
+		birthYear *int
+		want      bool
+	}{
+		{nil, false},
+		{year(1990), true},
+		{year(2007), true},
+		// Could still be 17 until their birthday.
+		{year(2008), false},
+		{year(2012), false},
+	} {
+		if got := discoveryAdult(tc.birthYear, now); got != tc.want {
+			t.Errorf("discoveryAdult(%v) = %v, want %v", tc.birthYear, got, tc.want)
+		}
+	}
+}
+
+func TestDiscoveryNameIsStable(t *testing.T) {
+	id := uuid.New()
+	if a, b := discoveryName(id), discoveryName(id); a != b || leaderboardBase(a) != anonymousBase(id) {
+		t.Errorf("discoveryName = %q then %q, want a stable name on %q", a, b, anonymousBase(id))
+	}
+}
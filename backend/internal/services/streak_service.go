@@ -11,20 +11,21 @@ import (
 )
 
 type StreakService struct {
-	db *gorm.DB
+	db        *gorm.DB
+	analytics *Analytics
 }
 
-func NewStreakService(db *gorm.DB) *StreakService {
-	return &StreakService{db: db}
+func NewStreakService(db *gorm.DB, analytics *Analytics) *StreakService {
+	return &StreakService{db: db, analytics: analytics}
 }
 
 // Unlockable colors at specific streak milestones
 var streakUnlocks = map[int]string{
-	3:  "silver",   // 3-day streak
-	7:  "gold",     // 7-day streak
-	14: "white",    // 14-day streak
-	21: "rainbow",  // 21-day streak
-	30: "cosmic",   // 30-day streak
+	3:  "silver",    // 3-day streak
+	7:  "gold",      // 7-day streak
+	14: "white",     // 14-day streak
+	21: "rainbow",   // 21-day streak
+	30: "cosmic",    // 30-day streak
 	50: "celestial", // 50-day streak
 }
 
@@ -81,17 +82,21 @@ func (s *StreakService) Get(userID uuid.UUID) (*dto.StreakResponse, error) {
 	return response, nil
 }
 
+// Update records today's scan in the streak. Days are compared in the user's
+// profile timezone so a late-evening scan does not count as the next day.
 func (s *StreakService) Update(userID uuid.UUID) (*dto.StreakUpdateResponse, error) {
 	streak, err := s.GetOrCreate(userID)
 	if err != nil {
 		return nil, err
 	}
 
+	loc := profileLocation(s.db, userID)
 	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	lastScan := time.Date(streak.LastScanDate.Year(), streak.LastScanDate.Month(), streak.LastScanDate.Day(), 0, 0, 0, 0, streak.LastScanDate.Location())
+	today, _ := localDayBounds(now, loc)
+	lastScan, _ := localDayBounds(streak.LastScanDate, loc)
 
 	streakBroken := false
+	previousStreak := streak.CurrentStreak
 	message := ""
 	var newUnlock string
 
@@ -159,6 +164,9 @@ func (s *StreakService) Update(userID uuid.UUID) (*dto.StreakUpdateResponse, err
 	if err := s.db.Save(streak).Error; err != nil {
 		return nil, err
 	}
+	if streakBroken {
+		s.analytics.Emit(userID, EventStreakBroken, map[string]any{"previous_streak": previousStreak})
+	}
 
 	response := &dto.StreakUpdateResponse{
 		Streak: dto.StreakResponse{
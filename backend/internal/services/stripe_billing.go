@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStripeDisabled       = errors.New("web billing is not available")
+	ErrUnknownPrice         = errors.New("unknown price")
+	ErrAlreadySubscribed    = errors.New("you already have an active subscription")
+	ErrNoStripeCustomer     = errors.New("no web billing account found")
+	ErrInvalidStripeWebhook = errors.New("invalid stripe signature")
+)
+
+const (
+	stripeAPIBase = "https://api.stripe.com/v1"
+	// stripeSignatureTolerance rejects replayed webhook deliveries.
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+// StripeService creates Stripe Checkout and customer portal sessions for
+// web users. Subscription state comes back through the Stripe webhook,
+// which SubscriptionService applies to the same table RevenueCat writes.
+type StripeService struct {
+	db      *gorm.DB
+	cfg     *config.Config
+	client  *http.Client
+	apiBase string
+}
+
+func NewStripeService(db *gorm.DB, cfg *config.Config) *StripeService {
+	return &StripeService{
+		db:      db,
+		cfg:     cfg,
+		client:  &http.Client{Timeout: 15 * time.Second},
+		apiBase: stripeAPIBase,
+	}
+}
+
+func (s *StripeService) enabled() bool {
+	return strings.TrimSpace(s.cfg.StripeSecretKey) != ""
+}
+
+// priceIDs returns the configured prices; the first is the default.
+func (s *StripeService) priceIDs() []string {
+	var ids []string
+	for _, id := range strings.Split(s.cfg.StripePriceIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// CreateCheckoutSession starts a Stripe Checkout for a subscription to the
+// price (the default price when empty) and returns the hosted page URL.
+func (s *StripeService) CreateCheckoutSession(ctx context.Context, userID uuid.UUID, priceID string) (string, error) {
+	ctx, span := tracer.Start(ctx, "StripeService.CreateCheckoutSession")
+	defer span.End()
+
+	prices := s.priceIDs()
+	if !s.enabled() || len(prices) == 0 {
+		return "", ErrStripeDisabled
+	}
+	if priceID = strings.TrimSpace(priceID); priceID == "" {
+		priceID = prices[0]
+	} else if !containsString(prices, priceID) {
+		return "", ErrUnknownPrice
+	}
+	if hasActiveSubscription(s.db.WithContext(ctx), userID) {
+		return "", ErrAlreadySubscribed
+	}
+
+	customerID, err := s.ensureCustomer(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"mode":                                 {"subscription"},
+		"customer":                             {customerID},
+		"client_reference_id":                  {userID.String()},
+		"line_items[0][price]":                 {priceID},
+		"line_items[0][quantity]":              {"1"},
+		"success_url":                          {s.cfg.StripeSuccessURL},
+		"cancel_url":                           {s.cfg.StripeCancelURL},
+		"subscription_data[metadata][user_id]": {userID.String()},
+	}
+	var session struct {
+		URL string `json:"url"`
+	}
+	if err := s.post(ctx, "/checkout/sessions", form, &session); err != nil {
+		return "", err
+	}
+	return session.URL, nil
+}
+
+// PortalURL returns a Stripe customer portal link where the user manages or
+// cancels their web subscription.
+func (s *StripeService) PortalURL(ctx context.Context, userID uuid.UUID) (string, error) {
+	ctx, span := tracer.Start(ctx, "StripeService.PortalURL")
+	defer span.End()
+
+	if !s.enabled() {
+		return "", ErrStripeDisabled
+	}
+	var user models.User
+	if err := s.db.WithContext(ctx).Select("id", "stripe_customer_id").First(&user, "id = ?", userID).Error; err != nil {
+		return "", ErrUserNotFound
+	}
+	if user.StripeCustomerID == nil {
+		return "", ErrNoStripeCustomer
+	}
+
+	var session struct {
+		URL string `json:"url"`
+	}
+	form := url.Values{
+		"customer":   {*user.StripeCustomerID},
+		"return_url": {s.cfg.StripePortalReturn},
+	}
+	if err := s.post(ctx, "/billing_portal/sessions", form, &session); err != nil {
+		return "", err
+	}
+	return session.URL, nil
+}
+
+// ensureCustomer returns the user's Stripe customer, creating it on first
+// checkout.
+func (s *StripeService) ensureCustomer(ctx context.Context, userID uuid.UUID) (string, error) {
+	var user models.User
+	if err := s.db.WithContext(ctx).First(&user, "id = ?", userID).Error; err != nil {
+		return "", ErrUserNotFound
+	}
+	if user.StripeCustomerID != nil {
+		return *user.StripeCustomerID, nil
+	}
+
+	form := url.Values{"metadata[user_id]": {userID.String()}}
+	if !isGuestEmail(user.Email) {
+		form.Set("email", user.Email)
+	}
+	var customer struct {
+		ID string `json:"id"`
+	}
+	if err := s.post(ctx, "/customers", form, &customer); err != nil {
+		return "", err
+	}
+
+	// A concurrent checkout may have linked a customer first; use that one.
+	result := s.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND stripe_customer_id IS NULL", userID).
+		Update("stripe_customer_id", customer.ID)
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		if err := s.db.WithContext(ctx).Select("stripe_customer_id").First(&user, "id = ?", userID).Error; err != nil || user.StripeCustomerID == nil {
+			return "", ErrUserNotFound
+		}
+		return *user.StripeCustomerID, nil
+	}
+	return customer.ID, nil
+}
+
+func (s *StripeService) post(ctx context.Context, path string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.apiBase+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.cfg.StripeSecretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("stripe %s: %w", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(body, &apiErr)
+		return fmt.Errorf("stripe %s: status %d: %s", path, resp.StatusCode, apiErr.Error.Message)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// VerifyStripeSignature checks a Stripe-Signature header ("t=...,v1=...")
+// against the endpoint secret, rejecting timestamps outside the tolerance.
+func VerifyStripeSignature(payload []byte, header, secret string, now time.Time) error {
+	if secret == "" {
+		return ErrInvalidStripeWebhook
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidStripeWebhook
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return ErrInvalidStripeWebhook
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, sig := range signatures {
+		if decoded, err := hex.DecodeString(sig); err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidStripeWebhook
+}
+
+// stripeEvent is the part of a Stripe event the webhook uses.
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+type stripeSubscription struct {
+	ID                 string            `json:"id"`
+	Customer           string            `json:"customer"`
+	Status             string            `json:"status"`
+	CurrentPeriodStart int64             `json:"current_period_start"`
+	CurrentPeriodEnd   int64             `json:"current_period_end"`
+	Metadata           map[string]string `json:"metadata"`
+	Items              struct {
+		Data []struct {
+			// Newer API versions report the period per item.
+			CurrentPeriodStart int64 `json:"current_period_start"`
+			CurrentPeriodEnd   int64 `json:"current_period_end"`
+			Price              struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// stripeStatus maps a Stripe subscription status onto the statuses
+// RevenueCat rows use; only "active" unlocks premium.
+func stripeStatus(status string) string {
+	switch status {
+	case "active", "trialing":
+		return "active"
+	case "canceled", "incomplete_expired":
+		return "expired"
+	default: // past_due, unpaid, incomplete, paused
+		return "inactive"
+	}
+}
+
+// ReceiveStripeWebhook stores a verified Stripe event for processing, like
+// ReceiveRevenueCatWebhook.
+func (s *SubscriptionService) ReceiveStripeWebhook(payload []byte) (*models.WebhookEvent, bool, error) {
+	var event stripeEvent
+	if err := json.Unmarshal(payload, &event); err != nil || event.ID == "" {
+		return nil, false, ErrInvalidWebhookPayload
+	}
+	return s.storeWebhookEvent(models.WebhookProviderStripe, event.ID, event.Type, payload)
+}
+
+// applyStripeEvent upserts the subscription carried by customer.subscription.*
+// events; other event types are ignored.
+func (s *SubscriptionService) applyStripeEvent(payload []byte) error {
+	var event stripeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(event.Type, "customer.subscription.") {
+		return nil
+	}
+	var stripeSub stripeSubscription
+	if err := json.Unmarshal(event.Data.Object, &stripeSub); err != nil {
+		return err
+	}
+	if stripeSub.ID == "" {
+		return fmt.Errorf("stripe event %s has no subscription id", event.ID)
+	}
+
+	start, end := stripeSub.CurrentPeriodStart, stripeSub.CurrentPeriodEnd
+	productID := ""
+	if len(stripeSub.Items.Data) > 0 {
+		item := stripeSub.Items.Data[0]
+		productID = item.Price.ID
+		if end == 0 {
+			start, end = item.CurrentPeriodStart, item.CurrentPeriodEnd
+		}
+	}
+
+	sub := models.Subscription{
+		StripeSubscriptionID: &stripeSub.ID,
+		Source:               "stripe",
+		ProductID:            productID,
+		Status:               stripeStatus(stripeSub.Status),
+		CurrentPeriodStart:   time.Unix(start, 0),
+		CurrentPeriodEnd:     time.Unix(end, 0),
+		UserID:               s.stripeUserID(stripeSub),
+	}
+	if event.Type == "customer.subscription.deleted" {
+		sub.Status = "expired"
+	}
+
+	var existing models.Subscription
+	err := s.db.Where("stripe_subscription_id = ?", stripeSub.ID).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := s.db.Create(&sub).Error; err != nil {
+			return err
+		}
+		return auditSubscriptionChange(s.db, &sub, "")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lookup subscription: %w", err)
+	}
+	updates := map[string]interface{}{
+		"product_id":           sub.ProductID,
+		"status":               sub.Status,
+		"current_period_start": sub.CurrentPeriodStart,
+		"current_period_end":   sub.CurrentPeriodEnd,
+	}
+	if sub.UserID != nil {
+		updates["user_id"] = *sub.UserID
+	} else {
+		sub.UserID = existing.UserID
+	}
+	if err := s.db.Model(&existing).Updates(updates).Error; err != nil {
+		return err
+	}
+	sub.ID = existing.ID
+	return auditSubscriptionChange(s.db, &sub, existing.Status)
+}
+
+// stripeUserID finds the user from the checkout metadata, falling back to
+// the Stripe customer linked to the account.
+func (s *SubscriptionService) stripeUserID(sub stripeSubscription) *uuid.UUID {
+	if id := s.lookupUserID(sub.Metadata["user_id"], ""); id != nil {
+		return id
+	}
+	if sub.Customer == "" {
+		return nil
+	}
+	var user models.User
+	if err := s.db.Select("id").First(&user, "stripe_customer_id = ?", sub.Customer).Error; err != nil {
+		return nil
+	}
+	return &user.ID
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func signStripe(payload []byte, secret string, ts int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", ts)
+	mac.Write(payload)
+	return fmt.Sprintf("t=%d,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestVerifyStripeSignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"customer.subscription.updated"}`)
+	now := time.Unix(1_700_000_000, 0)
+
+	if err := VerifyStripeSignature(payload, signStripe(payload, "whsec_test", now.Unix()), "whsec_test", now); err != nil {
+		t.Errorf("valid signature rejected: %v", err)
+	}
+	// Stripe sends several v1 signatures while a secret is being rolled.
+	rolled := signStripe(payload, "whsec_test", now.Unix()) + ",v1=" + hex.EncodeToString([]byte("old"))
+	if err := VerifyStripeSignature(payload, rolled, "whsec_test", now); err != nil {
+		t.Errorf("signature among several rejected: %v", err)
+	}
+
+	for name, header := range map[string]string{
+		"wrong secret": signStripe(payload, "whsec_other", now.Unix()),
+		"too old":      signStripe(payload, "whsec_test", now.Add(-10*time.Minute).Unix()),
+		"no signature": fmt.Sprintf("t=%d", now.Unix()),
+		"empty":        "",
+	} {
+		if err := VerifyStripeSignature(payload, header, "whsec_test", now); err == nil {
+			t.Errorf("%s: signature accepted", name)
+		}
+	}
+	if err := VerifyStripeSignature([]byte(`{"id":"evt_2"}`), signStripe(payload, "whsec_test", now.Unix()), "whsec_test", now); err == nil {
+		t.Error("tampered payload accepted")
+	}
+}
+
+func TestStripeStatus(t *testing.T) {
+	for in, want := range map[string]string{
+		"active":             "active",
+		"trialing":           "active",
+		"past_due":           "inactive",
+		"canceled":           "expired",
+		"incomplete_expired": "expired",
+	} {
+		if got := stripeStatus(in); got != want {
+			t.Errorf("stripeStatus(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -20,6 +20,15 @@ func NewSubscriptionService(db *gorm.DB) *SubscriptionService {
 	return &SubscriptionService{db: db}
 }
 
+// hasActiveSubscription reports whether the user has a paid period running.
+func hasActiveSubscription(db *gorm.DB, userID uuid.UUID) bool {
+	var sub models.Subscription
+	err := db.Where("user_id = ? AND status = ? AND current_period_end > ?", userID, "active", time.Now()).
+		Order("current_period_end DESC").
+		First(&sub).Error
+	return err == nil
+}
+
 func (s *SubscriptionService) HandleWebhookEvent(event *dto.RevenueCatEvent) error {
 	status := ""
 	switch event.Type {
@@ -48,7 +57,7 @@ func (s *SubscriptionService) upsertSubscription(event *dto.RevenueCatEvent, sta
 	}
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		sub = models.Subscription{ID: uuid.New()}
+		sub = models.Subscription{ID: uuid.New(), Source: models.WebhookProviderRevenueCat}
 		sub.RevenueCatID = event.AppUserID
 		sub.ProductID = event.ProductID
 		sub.Status = status
@@ -68,12 +77,16 @@ func (s *SubscriptionService) upsertSubscription(event *dto.RevenueCatEvent, sta
 			sub.UserID = userID
 		}
 
-		return s.db.Create(&sub).Error
+		if err := s.db.Create(&sub).Error; err != nil {
+			return err
+		}
+		return auditSubscriptionChange(s.db, &sub, "")
 	}
 	if err != nil {
 		return fmt.Errorf("failed to lookup subscription: %w", err)
 	}
 
+	previousStatus := sub.Status
 	updates := map[string]interface{}{
 		"revenuecat_id":        event.AppUserID,
 		"product_id":           event.ProductID,
@@ -92,9 +105,30 @@ func (s *SubscriptionService) upsertSubscription(event *dto.RevenueCatEvent, sta
 	}
 	if userID := s.lookupUserID(event.AppUserID, event.OriginalAppUserID); userID != nil {
 		updates["user_id"] = *userID
+		sub.UserID = userID
 	}
 
-	return s.db.Model(&sub).Updates(updates).Error
+	if err := s.db.Model(&sub).Updates(updates).Error; err != nil {
+		return err
+	}
+	sub.ProductID, sub.Status = event.ProductID, status
+	return auditSubscriptionChange(s.db, &sub, previousStatus)
+}
+
+// auditSubscriptionChange records a subscription created (previousStatus
+// "") or updated by a billing provider event; the actor is the system.
+func auditSubscriptionChange(db *gorm.DB, sub *models.Subscription, previousStatus string) error {
+	action := "subscription.updated"
+	if previousStatus == "" {
+		action = "subscription.created"
+	}
+	return writeAudit(db, nil, action, auditTargetSubscription, sub.ID, map[string]any{
+		"user_id":         sub.UserID,
+		"source":          sub.Source,
+		"product_id":      sub.ProductID,
+		"status":          sub.Status,
+		"previous_status": previousStatus,
+	})
 }
 
 func (s *SubscriptionService) lookupUserID(appUserID, originalAppUserID string) *uuid.UUID {
@@ -0,0 +1,587 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSurveyNotFound        = errors.New("survey not found")
+	ErrSurveyNotEligible     = errors.New("this survey is not available to you")
+	ErrSurveyAlreadyAnswered = errors.New("survey already answered")
+)
+
+// SurveyValidationError explains why a survey definition or response was rejected.
+type SurveyValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *SurveyValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+func surveyInvalid(field, format string, args ...interface{}) error {
+	return &SurveyValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+const (
+	defaultSurveyTextLength = 500
+	maxSurveyTextLength     = 2000
+	maxSurveyQuestions      = 20
+)
+
+// SurveyService runs admin-defined in-app surveys and their reporting
+type SurveyService struct {
+	db *gorm.DB
+}
+
+func NewSurveyService(db *gorm.DB) *SurveyService {
+	return &SurveyService{db: db}
+}
+
+// CreateSurvey validates and stores a new survey definition.
+func (s *SurveyService) CreateSurvey(req *dto.SurveyRequest) (*models.Survey, error) {
+	survey := &models.Survey{}
+	if err := applySurveyRequest(survey, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.Create(survey).Error; err != nil {
+		return nil, err
+	}
+	return survey, nil
+}
+
+// UpdateSurvey replaces a survey definition. Questions can't change once
+// responses exist, so earlier answers stay comparable.
+func (s *SurveyService) UpdateSurvey(id uuid.UUID, req *dto.SurveyRequest) (*models.Survey, error) {
+	var survey models.Survey
+	if err := s.db.First(&survey, "id = ?", id).Error; err != nil {
+		return nil, ErrSurveyNotFound
+	}
+	before, _ := json.Marshal(survey.Questions)
+	if err := applySurveyRequest(&survey, req); err != nil {
+		return nil, err
+	}
+	if after, _ := json.Marshal(survey.Questions); string(after) != string(before) {
+		var count int64
+		s.db.Model(&models.SurveyResponse{}).Where("survey_id = ?", id).Count(&count)
+		if count > 0 {
+			return nil, surveyInvalid("questions", "can't be changed after responses have been collected; create a new survey")
+		}
+	}
+	if err := s.db.Save(&survey).Error; err != nil {
+		return nil, err
+	}
+	return &survey, nil
+}
+
+// ListSurveys returns every survey, newest first.
+func (s *SurveyService) ListSurveys() ([]models.Survey, error) {
+	var surveys []models.Survey
+	err := s.db.Order("created_at DESC").Find(&surveys).Error
+	return surveys, err
+}
+
+// Pending returns the active surveys targeted at the user that they haven't
+// answered yet. Accuracy surveys ask about the user's latest unrated reading.
+func (s *SurveyService) Pending(userID uuid.UUID) ([]dto.PendingSurvey, error) {
+	now := time.Now()
+	var surveys []models.Survey
+	if err := s.db.Where("active = true AND (starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?)", now, now).
+		Order("created_at ASC").
+		Find(&surveys).Error; err != nil {
+		return nil, err
+	}
+
+	out := []dto.PendingSurvey{}
+	if len(surveys) == 0 {
+		return out, nil
+	}
+
+	profile, err := s.segmentProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, survey := range surveys {
+		if !matchesSegment(survey.Segment, profile, now) {
+			continue
+		}
+		pending := dto.PendingSurvey{
+			ID:          survey.ID.String(),
+			Kind:        survey.Kind,
+			Title:       survey.Title,
+			Description: survey.Description,
+			Questions:   survey.Questions,
+		}
+		if survey.Kind == models.SurveyKindAccuracy {
+			var reading models.AuraReading
+			err := s.db.Select("id").
+				Where("user_id = ?", userID).
+				Where("id NOT IN (?)", s.db.Model(&models.SurveyResponse{}).Select("reading_id").
+					Where("survey_id = ? AND reading_id IS NOT NULL", survey.ID)).
+				Order("created_at DESC").
+				First(&reading).Error
+			if err != nil {
+				continue
+			}
+			pending.ReadingID = reading.ID.String()
+		} else {
+			var count int64
+			s.db.Model(&models.SurveyResponse{}).Where("survey_id = ? AND user_id = ?", survey.ID, userID).Count(&count)
+			if count > 0 {
+				continue
+			}
+		}
+		out = append(out, pending)
+	}
+	return out, nil
+}
+
+// Submit validates answers against the survey schema and stores them.
+func (s *SurveyService) Submit(userID, surveyID uuid.UUID, req *dto.SubmitSurveyRequest) (*models.SurveyResponse, error) {
+	var survey models.Survey
+	if err := s.db.First(&survey, "id = ?", surveyID).Error; err != nil {
+		return nil, ErrSurveyNotFound
+	}
+	now := time.Now()
+	if !survey.Active || (survey.StartsAt != nil && survey.StartsAt.After(now)) || (survey.EndsAt != nil && !survey.EndsAt.After(now)) {
+		return nil, ErrSurveyNotEligible
+	}
+	profile, err := s.segmentProfile(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !matchesSegment(survey.Segment, profile, now) {
+		return nil, ErrSurveyNotEligible
+	}
+
+	answers, score, err := validateSurveyAnswers(survey.Questions, req.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.SurveyResponse{SurveyID: survey.ID, UserID: userID, Answers: answers, Score: score}
+	dupes := s.db.Model(&models.SurveyResponse{}).Where("survey_id = ? AND user_id = ?", survey.ID, userID)
+
+	if survey.Kind == models.SurveyKindAccuracy {
+		readingID, err := uuid.Parse(req.ReadingID)
+		if err != nil {
+			return nil, surveyInvalid("reading_id", "a valid reading ID is required for accuracy surveys")
+		}
+		var reading models.AuraReading
+		if err := s.db.Select("id", "prompt_variant").Where("id = ? AND user_id = ?", readingID, userID).First(&reading).Error; err != nil {
+			return nil, ErrReadingNotFound
+		}
+		response.ReadingID = &reading.ID
+		response.PromptVariant = reading.PromptVariant
+		if response.PromptVariant == "" {
+			response.PromptVariant = promptVariantBaseline
+		}
+		dupes = dupes.Where("reading_id = ?", reading.ID)
+	}
+
+	var count int64
+	dupes.Count(&count)
+	if count > 0 {
+		return nil, ErrSurveyAlreadyAnswered
+	}
+
+	if err := s.db.Create(response).Error; err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Report aggregates a survey's responses per question and, for accuracy
+// surveys, per prompt variant.
+func (s *SurveyService) Report(surveyID uuid.UUID) (*dto.SurveyReport, error) {
+	var survey models.Survey
+	if err := s.db.First(&survey, "id = ?", surveyID).Error; err != nil {
+		return nil, ErrSurveyNotFound
+	}
+
+	agg := newSurveyAggregator(survey.Questions)
+	var batch []models.SurveyResponse
+	err := s.db.Select("id", "answers").Where("survey_id = ?", surveyID).
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for _, r := range batch {
+				agg.add(r.Answers)
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.SurveyReport{SurveyID: survey.ID.String(), Kind: survey.Kind, Responses: agg.responses, Questions: agg.report()}
+
+	if survey.Kind == models.SurveyKindAccuracy {
+		var rows []struct {
+			PromptVariant string
+			Responses     int64
+			AverageScore  float64
+		}
+		if err := s.db.Model(&models.SurveyResponse{}).
+			Select("prompt_variant, COUNT(*) AS responses, AVG(score) AS average_score").
+			Where("survey_id = ? AND score IS NOT NULL", surveyID).
+			Group("prompt_variant").
+			Order("prompt_variant").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			report.ByPromptVariant = append(report.ByPromptVariant, dto.SurveyVariantScore{
+				Variant: row.PromptVariant, Responses: row.Responses, AverageScore: row.AverageScore,
+			})
+		}
+	}
+	return report, nil
+}
+
+// surveyProfile holds the user attributes segments are matched against.
+type surveyProfile struct {
+	premium   bool
+	guest     bool
+	readings  int64
+	createdAt time.Time
+}
+
+func (s *SurveyService) segmentProfile(userID uuid.UUID) (surveyProfile, error) {
+	var user models.User
+	if err := s.db.Select("id", "email", "created_at").First(&user, "id = ?", userID).Error; err != nil {
+		return surveyProfile{}, ErrUserNotFound
+	}
+	p := surveyProfile{
+		premium:   hasActiveSubscription(s.db, userID),
+		guest:     isGuestEmail(user.Email),
+		createdAt: user.CreatedAt,
+	}
+	s.db.Model(&models.AuraReading{}).Where("user_id = ?", userID).Count(&p.readings)
+	return p, nil
+}
+
+func matchesSegment(seg models.SurveySegment, p surveyProfile, now time.Time) bool {
+	switch seg.Plan {
+	case "premium":
+		if !p.premium {
+			return false
+		}
+	case "free":
+		if p.premium {
+			return false
+		}
+	}
+	if seg.RegisteredOnly && p.guest {
+		return false
+	}
+	if p.readings < int64(seg.MinReadings) {
+		return false
+	}
+	if seg.MinAccountAgeDays > 0 && now.Sub(p.createdAt) < time.Duration(seg.MinAccountAgeDays)*24*time.Hour {
+		return false
+	}
+	return true
+}
+
+// applySurveyRequest validates a definition and copies it onto survey.
+func applySurveyRequest(survey *models.Survey, req *dto.SurveyRequest) error {
+	kind := strings.ToLower(strings.TrimSpace(req.Kind))
+	switch kind {
+	case models.SurveyKindNPS, models.SurveyKindAccuracy, models.SurveyKindCustom:
+	default:
+		return surveyInvalid("kind", "must be nps, accuracy or custom")
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" || utf8.RuneCountInString(title) > 120 {
+		return surveyInvalid("title", "is required and must be at most 120 characters")
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return surveyInvalid("ends_at", "must be after starts_at")
+	}
+	switch req.Segment.Plan {
+	case "", "free", "premium":
+	default:
+		return surveyInvalid("segment.plan", "must be free or premium")
+	}
+	questions, err := normalizeSurveyQuestions(req.Questions)
+	if err != nil {
+		return err
+	}
+
+	primary := ""
+	if q := primaryScoreQuestion(questions); q != nil {
+		primary = q.Type
+	}
+	if kind == models.SurveyKindNPS && primary != models.QuestionNPS {
+		return surveyInvalid("questions", "an nps survey must start its scored questions with an nps question")
+	}
+	if kind == models.SurveyKindAccuracy && primary != models.QuestionRating {
+		return surveyInvalid("questions", "an accuracy survey needs a rating question")
+	}
+
+	survey.Kind = kind
+	survey.Title = title
+	survey.Description = strings.TrimSpace(req.Description)
+	survey.Questions = questions
+	survey.Segment = req.Segment
+	survey.Active = req.Active
+	survey.StartsAt = req.StartsAt
+	survey.EndsAt = req.EndsAt
+	return nil
+}
+
+func normalizeSurveyQuestions(in []models.SurveyQuestion) ([]models.SurveyQuestion, error) {
+	if len(in) == 0 || len(in) > maxSurveyQuestions {
+		return nil, surveyInvalid("questions", "must contain 1 to %d questions", maxSurveyQuestions)
+	}
+	seen := map[string]bool{}
+	out := make([]models.SurveyQuestion, len(in))
+	for i, q := range in {
+		field := "questions[" + strconv.Itoa(i) + "]"
+		q.ID = strings.TrimSpace(q.ID)
+		if q.ID == "" || seen[q.ID] {
+			return nil, surveyInvalid(field+".id", "must be present and unique")
+		}
+		seen[q.ID] = true
+		if strings.TrimSpace(q.Prompt) == "" {
+			return nil, surveyInvalid(field+".prompt", "is required")
+		}
+		switch q.Type {
+		case models.QuestionNPS:
+			q.Min, q.Max = 0, 10
+		case models.QuestionRating:
+			if q.Min == 0 && q.Max == 0 {
+				q.Min, q.Max = 1, 5
+			}
+			if q.Min >= q.Max || q.Max-q.Min > 10 {
+				return nil, surveyInvalid(field, "rating range must be increasing and span at most 10")
+			}
+		case models.QuestionChoice, models.QuestionMultiChoice:
+			if len(q.Options) < 2 {
+				return nil, surveyInvalid(field+".options", "needs at least two options")
+			}
+		case models.QuestionText:
+			if q.MaxLength <= 0 {
+				q.MaxLength = defaultSurveyTextLength
+			}
+			if q.MaxLength > maxSurveyTextLength {
+				q.MaxLength = maxSurveyTextLength
+			}
+		default:
+			return nil, surveyInvalid(field+".type", "unknown question type %q", q.Type)
+		}
+		out[i] = q
+	}
+	return out, nil
+}
+
+// primaryScoreQuestion is the first NPS or rating question; its answer is
+// stored as the response score.
+func primaryScoreQuestion(questions []models.SurveyQuestion) *models.SurveyQuestion {
+	for i := range questions {
+		if questions[i].Type == models.QuestionNPS || questions[i].Type == models.QuestionRating {
+			return &questions[i]
+		}
+	}
+	return nil
+}
+
+// validateSurveyAnswers checks raw answers against the schema and returns the
+// normalized answers plus the primary score, if answered.
+func validateSurveyAnswers(questions []models.SurveyQuestion, raw map[string]json.RawMessage) (map[string]interface{}, *float64, error) {
+	known := make(map[string]bool, len(questions))
+	for _, q := range questions {
+		known[q.ID] = true
+	}
+	for id := range raw {
+		if !known[id] {
+			return nil, nil, surveyInvalid("answers."+id, "unknown question")
+		}
+	}
+
+	answers := make(map[string]interface{}, len(raw))
+	for _, q := range questions {
+		field := "answers." + q.ID
+		value, ok := raw[q.ID]
+		if !ok || string(value) == "null" {
+			if q.Required {
+				return nil, nil, surveyInvalid(field, "is required")
+			}
+			continue
+		}
+
+		switch q.Type {
+		case models.QuestionNPS, models.QuestionRating:
+			var n int
+			if err := json.Unmarshal(value, &n); err != nil || n < q.Min || n > q.Max {
+				return nil, nil, surveyInvalid(field, "must be an integer from %d to %d", q.Min, q.Max)
+			}
+			answers[q.ID] = n
+		case models.QuestionChoice:
+			var choice string
+			if err := json.Unmarshal(value, &choice); err != nil || !containsString(q.Options, choice) {
+				return nil, nil, surveyInvalid(field, "must be one of %s", strings.Join(q.Options, ", "))
+			}
+			answers[q.ID] = choice
+		case models.QuestionMultiChoice:
+			var choices []string
+			if err := json.Unmarshal(value, &choices); err != nil {
+				return nil, nil, surveyInvalid(field, "must be a list of options")
+			}
+			picked := map[string]bool{}
+			for _, c := range choices {
+				if !containsString(q.Options, c) {
+					return nil, nil, surveyInvalid(field, "%q is not an option", c)
+				}
+				picked[c] = true
+			}
+			if q.Required && len(picked) == 0 {
+				return nil, nil, surveyInvalid(field, "is required")
+			}
+			unique := make([]string, 0, len(picked))
+			for _, o := range q.Options {
+				if picked[o] {
+					unique = append(unique, o)
+				}
+			}
+			answers[q.ID] = unique
+		case models.QuestionText:
+			var text string
+			if err := json.Unmarshal(value, &text); err != nil {
+				return nil, nil, surveyInvalid(field, "must be text")
+			}
+			text = strings.TrimSpace(text)
+			if utf8.RuneCountInString(text) > q.MaxLength {
+				return nil, nil, surveyInvalid(field, "must be at most %d characters", q.MaxLength)
+			}
+			if text == "" {
+				if q.Required {
+					return nil, nil, surveyInvalid(field, "is required")
+				}
+				continue
+			}
+			answers[q.ID] = text
+		}
+	}
+
+	var score *float64
+	if q := primaryScoreQuestion(questions); q != nil {
+		if n, ok := answers[q.ID].(int); ok {
+			v := float64(n)
+			score = &v
+		}
+	}
+	return answers, score, nil
+}
+
+// surveyAggregator accumulates per-question statistics over stored answers.
+type surveyAggregator struct {
+	questions []models.SurveyQuestion
+	responses int64
+	counts    map[string]int64
+	sums      map[string]float64
+	dist      map[string]map[string]int64
+}
+
+func newSurveyAggregator(questions []models.SurveyQuestion) *surveyAggregator {
+	return &surveyAggregator{
+		questions: questions,
+		counts:    map[string]int64{},
+		sums:      map[string]float64{},
+		dist:      map[string]map[string]int64{},
+	}
+}
+
+func (a *surveyAggregator) bump(id, bucket string) {
+	if a.dist[id] == nil {
+		a.dist[id] = map[string]int64{}
+	}
+	a.dist[id][bucket]++
+}
+
+func (a *surveyAggregator) add(answers map[string]interface{}) {
+	a.responses++
+	for _, q := range a.questions {
+		v, ok := answers[q.ID]
+		if !ok {
+			continue
+		}
+		switch q.Type {
+		case models.QuestionNPS, models.QuestionRating:
+			// Stored answers come back from JSON as float64.
+			n, ok := v.(float64)
+			if !ok {
+				continue
+			}
+			a.counts[q.ID]++
+			a.sums[q.ID] += n
+			a.bump(q.ID, strconv.Itoa(int(n)))
+		case models.QuestionChoice:
+			if s, ok := v.(string); ok {
+				a.counts[q.ID]++
+				a.bump(q.ID, s)
+			}
+		case models.QuestionMultiChoice:
+			if list, ok := v.([]interface{}); ok {
+				a.counts[q.ID]++
+				for _, item := range list {
+					if s, ok := item.(string); ok {
+						a.bump(q.ID, s)
+					}
+				}
+			}
+		case models.QuestionText:
+			a.counts[q.ID]++
+		}
+	}
+}
+
+func (a *surveyAggregator) report() []dto.SurveyQuestionReport {
+	out := make([]dto.SurveyQuestionReport, 0, len(a.questions))
+	for _, q := range a.questions {
+		r := dto.SurveyQuestionReport{ID: q.ID, Type: q.Type, Answers: a.counts[q.ID], Distribution: a.dist[q.ID]}
+		if (q.Type == models.QuestionNPS || q.Type == models.QuestionRating) && r.Answers > 0 {
+			avg := a.sums[q.ID] / float64(r.Answers)
+			r.Average = &avg
+		}
+		if q.Type == models.QuestionNPS && r.Answers > 0 {
+			nps := npsScore(a.dist[q.ID], r.Answers)
+			r.NPS = &nps
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// npsScore is % promoters (9-10) minus % detractors (0-6).
+func npsScore(dist map[string]int64, total int64) float64 {
+	var promoters, detractors int64
+	for k, count := range dist {
+		n, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		switch {
+		case n >= 9:
+			promoters += count
+		case n <= 6:
+			detractors += count
+		}
+	}
+	return float64(promoters-detractors) * 100 / float64(total)
+}
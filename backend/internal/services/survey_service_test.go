@@ -0,0 +1,127 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+)
+
+func accuracySurveyQuestions(t *testing.T) []models.SurveyQuestion {
+	t.Helper()
+	questions, err := normalizeSurveyQuestions([]models.SurveyQuestion{
+		{ID: "stars", Type: models.QuestionRating, Prompt: "How accurate was this reading?", Required: true},
+		{ID: "tags", Type: models.QuestionMultiChoice, Prompt: "Anything off?", Options: []string{"too generic", "wrong color", "too negative"}},
+		{ID: "comment", Type: models.QuestionText, Prompt: "Tell us more", MaxLength: 20},
+	})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	return questions
+}
+
+func rawAnswers(t *testing.T, v map[string]interface{}) map[string]json.RawMessage {
+	t.Helper()
+	out := map[string]json.RawMessage{}
+	for k, val := range v {
+		b, err := json.Marshal(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[k] = b
+	}
+	return out
+}
+
+func TestValidateSurveyAnswers(t *testing.T) {
+	questions := accuracySurveyQuestions(t)
+
+	answers, score, err := validateSurveyAnswers(questions, rawAnswers(t, map[string]interface{}{
+		"stars": 4,
+		"tags":  []string{"wrong color", "too generic", "wrong color"},
+	}))
+	if err != nil {
+		t.Fatalf("valid answers rejected: %v", err)
+	}
+	if score == nil || *score != 4 {
+		t.Errorf("expected score 4, got %v", score)
+	}
+	if tags := answers["tags"].([]string); len(tags) != 2 || tags[0] != "too generic" {
+		t.Errorf("expected deduplicated tags in option order, got %v", tags)
+	}
+
+	bad := []map[string]interface{}{
+		{},                                    // missing required rating
+		{"stars": 6},                          // out of range
+		{"stars": 3.5},                        // not an integer
+		{"stars": 3, "tags": []string{"meh"}}, // not an option
+		{"stars": 3, "comment": "this comment is far too long"},
+		{"stars": 3, "extra": true}, // unknown question
+	}
+	for _, answers := range bad {
+		_, _, err := validateSurveyAnswers(questions, rawAnswers(t, answers))
+		var invalid *SurveyValidationError
+		if !errors.As(err, &invalid) {
+			t.Errorf("answers %v: expected validation error, got %v", answers, err)
+		}
+	}
+}
+
+func TestApplySurveyRequestKindRules(t *testing.T) {
+	npsWithoutNPS := &dto.SurveyRequest{
+		Kind: "nps", Title: "How are we doing?",
+		Questions: []models.SurveyQuestion{{ID: "q", Type: models.QuestionRating, Prompt: "Rate us"}},
+	}
+	if err := applySurveyRequest(&models.Survey{}, npsWithoutNPS); err == nil {
+		t.Error("nps survey without an nps question should be rejected")
+	}
+
+	var survey models.Survey
+	ok := &dto.SurveyRequest{
+		Kind: "NPS", Title: "How are we doing?",
+		Questions: []models.SurveyQuestion{{ID: "q", Type: models.QuestionNPS, Prompt: "Recommend us?"}},
+	}
+	if err := applySurveyRequest(&survey, ok); err != nil {
+		t.Fatalf("valid survey rejected: %v", err)
+	}
+	if survey.Kind != models.SurveyKindNPS || survey.Questions[0].Max != 10 {
+		t.Errorf("expected normalized nps survey, got %+v", survey)
+	}
+}
+
+func TestMatchesSegment(t *testing.T) {
+	now := time.Now()
+	profile := surveyProfile{premium: false, guest: true, readings: 3, createdAt: now.Add(-48 * time.Hour)}
+
+	cases := []struct {
+		seg  models.SurveySegment
+		want bool
+	}{
+		{models.SurveySegment{}, true},
+		{models.SurveySegment{Plan: "free", MinReadings: 3}, true},
+		{models.SurveySegment{Plan: "premium"}, false},
+		{models.SurveySegment{RegisteredOnly: true}, false},
+		{models.SurveySegment{MinReadings: 4}, false},
+		{models.SurveySegment{MinAccountAgeDays: 3}, false},
+	}
+	for _, tc := range cases {
+		if got := matchesSegment(tc.seg, profile, now); got != tc.want {
+			t.Errorf("segment %+v: got %v, want %v", tc.seg, got, tc.want)
+		}
+	}
+}
+
+func TestSurveyAggregatorNPS(t *testing.T) {
+	questions, _ := normalizeSurveyQuestions([]models.SurveyQuestion{{ID: "nps", Type: models.QuestionNPS, Prompt: "Recommend?"}})
+	agg := newSurveyAggregator(questions)
+	for _, v := range []float64{10, 9, 8, 3} {
+		agg.add(map[string]interface{}{"nps": v})
+	}
+	report := agg.report()[0]
+	if report.Answers != 4 || report.NPS == nil || *report.NPS != 25 {
+		t.Fatalf("expected 4 answers and NPS 25, got %+v (nps=%v)", report, report.NPS)
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTimezone  = errors.New("invalid timezone")
+	ErrTimezoneCooldown = errors.New("timezone was changed recently; try again later")
+)
+
+// loadTimezone resolves an IANA zone name such as "Europe/Istanbul".
+// "Local" is rejected so the server's own zone never leaks into user day math.
+func loadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// userLocation picks the zone for showing a user "today": a valid
+// per-request override (X-Timezone header) wins, then the profile timezone,
+// then UTC. Quota and streak days use profileLocation, which a client
+// cannot pick per request.
+func userLocation(db *gorm.DB, userID uuid.UUID, override string) *time.Location {
+	if loc, err := loadTimezone(override); err == nil {
+		return loc
+	}
+	return profileLocation(db, userID)
+}
+
+// profileLocation is the user's stored profile timezone, or UTC.
+func profileLocation(db *gorm.DB, userID uuid.UUID) *time.Location {
+	var user models.User
+	if err := db.Select("timezone").First(&user, "id = ?", userID).Error; err == nil {
+		if loc, err := loadTimezone(user.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// localDayBounds returns the start and end of the calendar day containing now
+// in loc. The end is computed by date rather than +24h so DST days are correct.
+func localDayBounds(now time.Time, loc *time.Location) (time.Time, time.Time) {
+	local := now.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+	return start, end
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocalDayBoundsUsesUserTimezone(t *testing.T) {
+	loc, err := loadTimezone("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 2026-03-08 03:00 UTC is still March 7th in Los Angeles.
+	start, end := localDayBounds(time.Date(2026, 3, 8, 3, 0, 0, 0, time.UTC), loc)
+	if start.In(loc).Day() != 7 || start.In(loc).Hour() != 0 {
+		t.Fatalf("unexpected start: %s", start)
+	}
+	if end.Sub(start) != 24*time.Hour {
+		t.Fatalf("expected 24h day, got %s", end.Sub(start))
+	}
+
+	// March 8th is the spring-forward day and only has 23 hours.
+	start, end = localDayBounds(time.Date(2026, 3, 8, 20, 0, 0, 0, time.UTC), loc)
+	if end.Sub(start) != 23*time.Hour {
+		t.Fatalf("expected 23h DST day, got %s", end.Sub(start))
+	}
+}
+
+func TestLoadTimezoneRejectsInvalid(t *testing.T) {
+	for _, name := range []string{"", "Local", "Mars/Olympus"} {
+		if _, err := loadTimezone(name); err != ErrInvalidTimezone {
+			t.Fatalf("expected ErrInvalidTimezone for %q, got %v", name, err)
+		}
+	}
+}
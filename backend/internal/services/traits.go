@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/database"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/i18n"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxTraitSlugBytes = 120
+
+// ErrTraitIndexNotReady is returned for trait stats and the trait filter
+// until the reading_traits migration has backfilled every reading.
+var ErrTraitIndexNotReady = errors.New("trait history is still being indexed; try again later")
+
+// traitIndexReady reports whether reading_traits covers every reading:
+// once the backfill is done, double-writes keep it complete.
+func traitIndexReady(db *gorm.DB) bool {
+	return migrationPhaseAtLeast(db, readingTraitsMigrationName, models.MigrationVerify)
+}
+
+// canonicalTraitName returns the English name of a trait the i18n tables
+// know in any language, or the trimmed text itself.
+func canonicalTraitName(kind, text string) string {
+	if name, ok := i18n.CanonicalTrait(kind, text); ok {
+		return name
+	}
+	return strings.TrimSpace(text)
+}
+
+// traitSlug is the stable key of a trait name: lower-case letters and
+// digits with single dashes between words, e.g. "anger-management".
+func traitSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			dash = b.Len() > 0
+			continue
+		}
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		if b.Len()+len(string(r)) > maxTraitSlugBytes {
+			break
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// filterTraitSlug turns a history filter value, a slug or a trait name in
+// any language, into a slug.
+func filterTraitSlug(v string) string {
+	for _, kind := range []string{models.TraitStrength, models.TraitChallenge} {
+		if name, ok := i18n.CanonicalTrait(kind, v); ok {
+			return traitSlug(name)
+		}
+	}
+	return traitSlug(v)
+}
+
+// resolveTraits returns the vocabulary ID of each text, creating the
+// traits it does not have yet. Texts without letters or digits map to nil.
+func resolveTraits(tx *gorm.DB, kind string, texts []string) ([]*uuid.UUID, error) {
+	slugs := make([]string, len(texts))
+	var rows []models.Trait
+	seen := map[string]bool{}
+	for i, text := range texts {
+		name := canonicalTraitName(kind, text)
+		slugs[i] = traitSlug(name)
+		if slugs[i] == "" || seen[slugs[i]] {
+			continue
+		}
+		seen[slugs[i]] = true
+		rows = append(rows, models.Trait{Kind: kind, Slug: slugs[i], Name: truncateRunes(name, 200)})
+	}
+	if len(rows) == 0 {
+		return make([]*uuid.UUID, len(texts)), nil
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kind"}, {Name: "slug"}},
+		DoNothing: true,
+	}).Create(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var existing []models.Trait
+	if err := tx.Select("id", "slug").Where("kind = ? AND slug IN ?", kind, mapKeys(seen)).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]uuid.UUID, len(existing))
+	for _, t := range existing {
+		bySlug[t.Slug] = t.ID
+	}
+	ids := make([]*uuid.UUID, len(texts))
+	for i, slug := range slugs {
+		if id, ok := bySlug[slug]; ok {
+			ids[i] = &id
+		}
+	}
+	return ids, nil
+}
+
+func mapKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// traitStatRow is one trait of a user's history with how often it came up.
+type traitStatRow struct {
+	ID       uuid.UUID
+	Kind     string
+	Slug     string
+	Name     string
+	Readings int64
+	LastSeen time.Time
+}
+
+// TraitStats returns the user's recurring strengths and challenges, most
+// frequent first, named in locale.
+func (s *AuraService) TraitStats(userID uuid.UUID, locale string, limit int) (*dto.TraitStatsResponse, error) {
+	if !traitIndexReady(s.db) {
+		return nil, ErrTraitIndexNotReady
+	}
+	locale = i18n.Resolve(locale)
+
+	history := s.db.Scopes(database.Replica).Table("reading_traits AS rt").
+		Joins("JOIN aura_readings AS r ON r.id = rt.reading_id").
+		Where("rt.user_id = ? AND r.group_reading_id IS NULL AND NOT r.synthetic AND r.deleted_at IS NULL", userID)
+
+	var total int64
+	if err := history.Session(&gorm.Session{}).Distinct("rt.reading_id").Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []traitStatRow
+	err := history.Session(&gorm.Session{}).
+		Joins("JOIN traits AS t ON t.id = rt.trait_id").
+		Select("t.id, t.kind, t.slug, t.name, COUNT(DISTINCT rt.reading_id) AS readings, MAX(r.created_at) AS last_seen").
+		Group("t.id, t.kind, t.slug, t.name").
+		Order("readings DESC, last_seen DESC, t.slug").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return buildTraitStats(rows, total, locale, limit), nil
+}
+
+func buildTraitStats(rows []traitStatRow, total int64, locale string, limit int) *dto.TraitStatsResponse {
+	resp := &dto.TraitStatsResponse{TotalReadings: total, Strengths: []dto.TraitStat{}, Challenges: []dto.TraitStat{}}
+	for _, row := range rows {
+		list := &resp.Strengths
+		if row.Kind == models.TraitChallenge {
+			list = &resp.Challenges
+		}
+		if len(*list) >= limit {
+			continue
+		}
+		stat := dto.TraitStat{
+			ID:         row.ID,
+			Slug:       row.Slug,
+			Name:       i18n.TraitName(locale, row.Kind, row.Name),
+			Readings:   row.Readings,
+			LastSeenAt: row.LastSeen,
+		}
+		if total > 0 {
+			stat.Share = float64(row.Readings) / float64(total)
+		}
+		*list = append(*list, stat)
+	}
+	return resp
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/i18n"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestTraitSlug(t *testing.T) {
+	cases := map[string]string{
+		"Anger Management":       "anger-management",
+		"  Over-thinking!! ":     "over-thinking",
+		"Öfke Kontrolü":          "öfke-kontrolü",
+		"...":                    "",
+		"Empathy & intuition 2x": "empathy-intuition-2x",
+	}
+	for in, want := range cases {
+		if got := traitSlug(in); got != want {
+			t.Errorf("traitSlug(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFilterTraitSlugCanonicalizes(t *testing.T) {
+	tr, _ := i18n.Traits("tr", "yellow")
+	en, _ := i18n.Traits("en", "yellow")
+	want := traitSlug(en.Challenges[1])
+	for _, v := range []string{tr.Challenges[1], en.Challenges[1], want} {
+		if got := filterTraitSlug(v); got != want {
+			t.Errorf("filterTraitSlug(%q) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestBuildTraitStats(t *testing.T) {
+	en, _ := i18n.Traits("en", "yellow")
+	tr, _ := i18n.Traits("tr", "yellow")
+	now := time.Now()
+	rows := []traitStatRow{
+		{ID: uuid.New(), Kind: models.TraitChallenge, Slug: traitSlug(en.Challenges[1]), Name: en.Challenges[1], Readings: 6, LastSeen: now},
+		{ID: uuid.New(), Kind: models.TraitStrength, Slug: "stargazing", Name: "Stargazing", Readings: 4, LastSeen: now},
+		{ID: uuid.New(), Kind: models.TraitChallenge, Slug: "procrastination", Name: "Procrastination", Readings: 2, LastSeen: now},
+	}
+
+	stats := buildTraitStats(rows, 8, "tr", 1)
+	if len(stats.Challenges) != 1 || len(stats.Strengths) != 1 {
+		t.Fatalf("limit not applied per kind: %+v", stats)
+	}
+	top := stats.Challenges[0]
+	if top.Name != tr.Challenges[1] || top.Share != 0.75 {
+		t.Errorf("top challenge = %q (share %v), want %q (0.75)", top.Name, top.Share, tr.Challenges[1])
+	}
+	if stats.Strengths[0].Name != "Stargazing" {
+		t.Errorf("free-form trait renamed to %q", stats.Strengths[0].Name)
+	}
+
+	if empty := buildTraitStats(nil, 0, "en", 10); empty.Strengths == nil || empty.Challenges == nil {
+		t.Error("empty stats must serialize as empty arrays")
+	}
+}
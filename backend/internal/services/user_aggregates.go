@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const aggregateReconcileBatch = 500
+
+// colorAggregateRow is one aura color's share of a user's readings.
+type colorAggregateRow struct {
+	AuraColor string
+	Readings  int64
+	Energy    int64
+	Mood      int64
+	LastScan  time.Time
+}
+
+// refreshUserAggregate recomputes the user's aggregate row from their
+// readings. Call it in the transaction that changed the readings: the row
+// lock serializes concurrent writers, so the last one to commit counts
+// everything the others wrote.
+func refreshUserAggregate(tx *gorm.DB, userID uuid.UUID) error {
+	_, err := recomputeUserAggregate(tx, userID)
+	return err
+}
+
+// recomputeUserAggregate rewrites the aggregate and reports whether the
+// stored row was out of date.
+func recomputeUserAggregate(tx *gorm.DB, userID uuid.UUID) (bool, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.UserAggregate{UserID: userID, ColorCounts: map[string]int{}}).Error; err != nil {
+		return false, err
+	}
+	var stored models.UserAggregate
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&stored, "user_id = ?", userID).Error; err != nil {
+		return false, err
+	}
+
+	var rows []colorAggregateRow
+	if err := tx.Model(&models.AuraReading{}).Scopes(personalReadings).
+		Select("aura_color, COUNT(*) AS readings, SUM(energy_level) AS energy, SUM(mood_score) AS mood, MAX(created_at) AS last_scan").
+		Where("user_id = ? AND NOT synthetic", userID).
+		Group("aura_color").
+		Scan(&rows).Error; err != nil {
+		return false, err
+	}
+
+	agg := buildUserAggregate(userID, rows)
+	if sameAggregate(&stored, agg) {
+		return false, nil
+	}
+	return true, tx.Model(&stored).Select("*").Omit("user_id").Updates(agg).Error
+}
+
+// buildUserAggregate folds per-color rows into the aggregate. The dominant
+// color is the most frequent one; ties go to the color scanned last.
+func buildUserAggregate(userID uuid.UUID, rows []colorAggregateRow) *models.UserAggregate {
+	agg := &models.UserAggregate{UserID: userID, ColorCounts: make(map[string]int, len(rows))}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Readings != rows[j].Readings {
+			return rows[i].Readings > rows[j].Readings
+		}
+		return rows[i].LastScan.After(rows[j].LastScan)
+	})
+
+	var energy, mood int64
+	for _, row := range rows {
+		agg.ColorCounts[row.AuraColor] = int(row.Readings)
+		agg.TotalReadings += row.Readings
+		energy += row.Energy
+		mood += row.Mood
+		if agg.LastScanAt == nil || row.LastScan.After(*agg.LastScanAt) {
+			last := row.LastScan
+			agg.LastScanAt = &last
+		}
+	}
+	if len(rows) > 0 {
+		agg.DominantColor = rows[0].AuraColor
+		agg.AverageEnergy = float64(energy) / float64(agg.TotalReadings)
+		agg.AverageMood = float64(mood) / float64(agg.TotalReadings)
+	}
+	return agg
+}
+
+func sameAggregate(a, b *models.UserAggregate) bool {
+	if a.TotalReadings != b.TotalReadings || a.DominantColor != b.DominantColor ||
+		a.AverageEnergy != b.AverageEnergy || a.AverageMood != b.AverageMood ||
+		len(a.ColorCounts) != len(b.ColorCounts) {
+		return false
+	}
+	if (a.LastScanAt == nil) != (b.LastScanAt == nil) ||
+		(a.LastScanAt != nil && !a.LastScanAt.Equal(*b.LastScanAt)) {
+		return false
+	}
+	for color, n := range a.ColorCounts {
+		if b.ColorCounts[color] != n {
+			return false
+		}
+	}
+	return true
+}
+
+// userAggregate returns the user's aggregate row, computing it on first use
+// for users whose readings predate the table.
+func userAggregate(db *gorm.DB, userID uuid.UUID) (*models.UserAggregate, error) {
+	var agg models.UserAggregate
+	err := db.First(&agg, "user_id = ?", userID).Error
+	if err == nil {
+		return &agg, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return refreshUserAggregate(tx, userID)
+	}); err != nil {
+		return nil, err
+	}
+	if err := db.First(&agg, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &agg, nil
+}
+
+// AggregateReconcileJob is the scheduled job that repairs drifted user
+// aggregates.
+func (s *AuraService) AggregateReconcileJob(ctx context.Context) error {
+	repaired, err := s.ReconcileAggregates(ctx)
+	if repaired > 0 {
+		log.Printf("aggregates: repaired %d drifted rows", repaired)
+	}
+	return err
+}
+
+// ReconcileAggregates recomputes the aggregate of every user with readings
+// or an aggregate row and returns how many rows had drifted, e.g. through
+// writes that bypassed the services or a failed refresh.
+func (s *AuraService) ReconcileAggregates(ctx context.Context) (int, error) {
+	repaired := 0
+	after := uuid.Nil
+	for {
+		var userIDs []uuid.UUID
+		if err := s.db.WithContext(ctx).Raw(`
+			SELECT user_id FROM (
+				SELECT user_id FROM aura_readings WHERE deleted_at IS NULL
+				UNION SELECT user_id FROM user_aggregates
+			) AS u WHERE user_id > ? ORDER BY user_id LIMIT ?`, after, aggregateReconcileBatch).
+			Scan(&userIDs).Error; err != nil {
+			return repaired, err
+		}
+		for _, userID := range userIDs {
+			if ctx.Err() != nil {
+				return repaired, ctx.Err()
+			}
+			var drifted bool
+			err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				var err error
+				drifted, err = recomputeUserAggregate(tx, userID)
+				return err
+			})
+			if err != nil {
+				log.Printf("aggregates: user %s: %v", userID, err)
+				continue
+			}
+			if drifted {
+				repaired++
+			}
+		}
+		if len(userIDs) < aggregateReconcileBatch {
+			return repaired, nil
+		}
+		after = userIDs[len(userIDs)-1]
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildUserAggregate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	agg := buildUserAggregate(uuid.New(), []colorAggregateRow{
+		{AuraColor: "blue", Readings: 2, Energy: 100, Mood: 10, LastScan: now.Add(-48 * time.Hour)},
+		{AuraColor: "violet", Readings: 2, Energy: 140, Mood: 14, LastScan: now},
+		{AuraColor: "red", Readings: 1, Energy: 90, Mood: 6, LastScan: now.Add(-time.Hour)},
+	})
+
+	if agg.TotalReadings != 5 {
+		t.Errorf("total = %d, want 5", agg.TotalReadings)
+	}
+	if agg.DominantColor != "violet" {
+		t.Errorf("dominant = %q, want violet (tie broken by latest scan)", agg.DominantColor)
+	}
+	if agg.AverageEnergy != 66 || agg.AverageMood != 6 {
+		t.Errorf("averages = %v/%v, want 66/6", agg.AverageEnergy, agg.AverageMood)
+	}
+	if agg.LastScanAt == nil || !agg.LastScanAt.Equal(now) {
+		t.Errorf("last scan = %v, want %v", agg.LastScanAt, now)
+	}
+	if agg.ColorCounts["blue"] != 2 || agg.ColorCounts["red"] != 1 {
+		t.Errorf("color counts = %v", agg.ColorCounts)
+	}
+
+	same := buildUserAggregate(agg.UserID, []colorAggregateRow{
+		{AuraColor: "red", Readings: 1, Energy: 90, Mood: 6, LastScan: now.Add(-time.Hour)},
+		{AuraColor: "violet", Readings: 2, Energy: 140, Mood: 14, LastScan: now},
+		{AuraColor: "blue", Readings: 2, Energy: 100, Mood: 10, LastScan: now.Add(-48 * time.Hour)},
+	})
+	if !sameAggregate(agg, same) {
+		t.Error("row order changed the aggregate")
+	}
+
+	empty := buildUserAggregate(agg.UserID, nil)
+	if empty.TotalReadings != 0 || empty.DominantColor != "" || empty.LastScanAt != nil {
+		t.Errorf("empty aggregate = %+v", empty)
+	}
+	if sameAggregate(agg, empty) {
+		t.Error("drift not detected")
+	}
+}
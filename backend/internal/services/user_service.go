@@ -0,0 +1,189 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/config"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const userSearchLimit = 20
+
+// UserService manages public identity (handles) and discovery settings.
+type UserService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewUserService(db *gorm.DB, cfg *config.Config) *UserService {
+	return &UserService{db: db, cfg: cfg}
+}
+
+// SetHandle claims, changes or (with an empty handle) clears the user's
+// handle. Changing an existing handle is limited by HANDLE_RENAME_COOLDOWN.
+func (s *UserService) SetHandle(userID uuid.UUID, raw string) (*dto.HandleResponse, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	handle := normalizeHandle(raw)
+	if handle == "" {
+		if err := s.db.Model(&user).Update("handle", nil).Error; err != nil {
+			return nil, err
+		}
+		return &dto.HandleResponse{}, nil
+	}
+
+	if user.Handle != nil && *user.Handle == handle {
+		return s.handleResponse(&user), nil
+	}
+	if err := validateHandle(handle); err != nil {
+		return nil, err
+	}
+	if user.Handle != nil && user.HandleChangedAt != nil &&
+		time.Since(*user.HandleChangedAt) < s.cfg.HandleRenameCooldown {
+		return nil, ErrHandleCooldown
+	}
+
+	var count int64
+	s.db.Model(&models.User{}).Unscoped().Where("handle = ? AND id <> ?", handle, userID).Count(&count)
+	if count > 0 {
+		return nil, ErrHandleTaken
+	}
+
+	now := time.Now()
+	err := s.db.Model(&user).Updates(map[string]interface{}{
+		"handle":            handle,
+		"handle_changed_at": now,
+	}).Error
+	if err != nil {
+		// Lost a race with another claim on the unique index.
+		if strings.Contains(err.Error(), "duplicate key") {
+			return nil, ErrHandleTaken
+		}
+		return nil, err
+	}
+
+	user.Handle = &handle
+	user.HandleChangedAt = &now
+	return s.handleResponse(&user), nil
+}
+
+func (s *UserService) handleResponse(user *models.User) *dto.HandleResponse {
+	resp := &dto.HandleResponse{}
+	if user.Handle != nil {
+		resp.Handle = *user.Handle
+	}
+	if user.HandleChangedAt != nil {
+		next := user.HandleChangedAt.Add(s.cfg.HandleRenameCooldown)
+		if next.After(time.Now()) {
+			resp.NextChangeAfter = &next
+		}
+	}
+	return resp
+}
+
+// UpdateDiscovery changes whether the user can be found by handle search
+// or by contact matching. Opting out of contacts drops the stored hashes.
+func (s *UserService) UpdateDiscovery(userID uuid.UUID, req *dto.DiscoverySettingsRequest) (*dto.DiscoverySettingsResponse, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	var phone string
+	if req.Phone != nil && strings.TrimSpace(*req.Phone) != "" {
+		normalized, err := normalizePhone(*req.Phone)
+		if err != nil {
+			return nil, err
+		}
+		phone = normalized
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{}
+		if req.DiscoverableByHandle != nil {
+			user.DiscoverableByHandle = *req.DiscoverableByHandle
+			updates["discoverable_by_handle"] = user.DiscoverableByHandle
+		}
+		if req.DiscoverableByContacts != nil {
+			user.DiscoverableByContacts = *req.DiscoverableByContacts
+			updates["discoverable_by_contacts"] = user.DiscoverableByContacts
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&user).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := syncEmailContactHash(tx, s.cfg.ContactDiscoverySalt, &user); err != nil {
+			return err
+		}
+		clearPhone := req.Phone != nil && phone == ""
+		if !user.DiscoverableByContacts || clearPhone {
+			return tx.Where("user_id = ? AND kind = ?", user.ID, models.ContactKindPhone).Delete(&models.ContactHash{}).Error
+		}
+		if phone != "" {
+			return putContactHash(tx, user.ID, models.ContactKindPhone, contactHash(s.cfg.ContactDiscoverySalt, models.ContactKindPhone, phone))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var phoneCount int64
+	s.db.Model(&models.ContactHash{}).Where("user_id = ? AND kind = ?", user.ID, models.ContactKindPhone).Count(&phoneCount)
+	return &dto.DiscoverySettingsResponse{
+		DiscoverableByHandle:   user.DiscoverableByHandle,
+		DiscoverableByContacts: user.DiscoverableByContacts,
+		PhoneRegistered:        phoneCount > 0,
+	}, nil
+}
+
+// SearchByHandle finds discoverable users whose handle starts with the
+// query, excluding the searcher, anyone blocked in either direction and
+// shadow-banned users.
+func (s *UserService) SearchByHandle(userID uuid.UUID, query string) ([]dto.UserSearchResult, error) {
+	prefix := normalizeHandle(query)
+	if len(prefix) < 2 {
+		return []dto.UserSearchResult{}, nil
+	}
+
+	var users []models.User
+	err := s.db.Select("id", "handle").
+		Where("handle LIKE ? AND discoverable_by_handle = true AND id <> ?", escapeLike(prefix)+"%", userID).
+		Where("id NOT IN (?)", s.db.Model(&models.Block{}).Select("blocked_id").Where("blocker_id = ?", userID)).
+		Where("id NOT IN (?)", s.db.Model(&models.Block{}).Select("blocker_id").Where("blocked_id = ?", userID)).
+		Scopes(notShadowBanned("id")).
+		Order("LENGTH(handle), handle").
+		Limit(userSearchLimit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]dto.UserSearchResult, 0, len(users))
+	for _, u := range users {
+		results = append(results, dto.UserSearchResult{ID: u.ID.String(), Handle: *u.Handle})
+	}
+	return results, nil
+}
+
+// SetResearchConsent opts the user in or out of anonymized research
+// datasets. Opting out applies to every export from the next day on.
+func (s *UserService) SetResearchConsent(userID uuid.UUID, enabled bool) error {
+	result := s.db.Model(&models.User{}).Where("id = ?", userID).Update("research_consent", enabled)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
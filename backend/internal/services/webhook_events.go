@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/dto"
+	"github.com/ahmetcoskunkizilkaya/aurasnap/backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+	ErrWebhookEventNotFound  = errors.New("webhook event not found")
+	ErrWebhookNotDead        = errors.New("only dead-lettered events can be replayed")
+)
+
+const (
+	// webhookMaxAttempts is how many times an event is tried before it is
+	// dead-lettered for an admin to replay.
+	webhookMaxAttempts = 8
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+	// webhookLease is how long a claimed event is hidden from other workers;
+	// if the process dies mid-event it becomes due again after this.
+	webhookLease = 5 * time.Minute
+	// webhookRetention is how long processed events are kept for auditing.
+	webhookRetention = 30 * 24 * time.Hour
+)
+
+// webhookBackoff is the delay before retry number attempt (1-based):
+// 30s, 1m, 2m, ... capped at webhookMaxBackoff.
+func webhookBackoff(attempt int) time.Duration {
+	delay := webhookBaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return delay
+}
+
+// ReceiveRevenueCatWebhook stores a RevenueCat delivery for processing. A
+// redelivery of an event already stored is ignored and reported as a
+// duplicate.
+func (s *SubscriptionService) ReceiveRevenueCatWebhook(payload []byte) (*models.WebhookEvent, bool, error) {
+	var webhook dto.RevenueCatWebhook
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil, false, ErrInvalidWebhookPayload
+	}
+
+	return s.storeWebhookEvent(models.WebhookProviderRevenueCat, webhook.Event.ID, webhook.Event.Type, payload)
+}
+
+func (s *SubscriptionService) storeWebhookEvent(provider, eventID, eventType string, payload []byte) (*models.WebhookEvent, bool, error) {
+	event := models.WebhookEvent{
+		Provider:      provider,
+		EventID:       truncateRunes(eventID, 255),
+		EventType:     truncateRunes(eventType, 100),
+		Payload:       string(payload),
+		Status:        models.WebhookPending,
+		NextAttemptAt: time.Now(),
+	}
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&event)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	return &event, result.RowsAffected == 0, nil
+}
+
+// ProcessWebhookEvent applies one stored event right away if it is still
+// pending; failures are left for the retry worker.
+func (s *SubscriptionService) ProcessWebhookEvent(id uuid.UUID) error {
+	_, err := s.processNextWebhook(func(db *gorm.DB) *gorm.DB {
+		return db.Where("id = ?", id)
+	})
+	return err
+}
+
+// RunWebhookWorker retries due webhook events and prunes old processed events
+// and markers every interval until ctx is cancelled.
+func (s *SubscriptionService) RunWebhookWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				processed, err := s.processNextWebhook(func(db *gorm.DB) *gorm.DB {
+					return db.Order("next_attempt_at ASC")
+				})
+				if err != nil {
+					log.Printf("webhook: %v", err)
+				}
+				if !processed {
+					break
+				}
+			}
+			if err := s.db.Where("status = ? AND processed_at < ?", models.WebhookProcessed, time.Now().Add(-webhookRetention)).
+				Delete(&models.WebhookEvent{}).Error; err != nil {
+				log.Printf("webhook: prune failed: %v", err)
+			}
+			if err := pruneProcessedEvents(s.db); err != nil {
+				log.Printf("webhook: prune processed events failed: %v", err)
+			}
+		}
+	}
+}
+
+// processNextWebhook claims one due pending event (SKIP LOCKED, leased for
+// webhookLease) and applies it. It reports whether an event was claimed.
+func (s *SubscriptionService) processNextWebhook(scope func(*gorm.DB) *gorm.DB) (bool, error) {
+	var event models.WebhookEvent
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Scopes(scope).
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookPending, time.Now()).
+			First(&event).Error; err != nil {
+			return err
+		}
+		event.Attempts++
+		return tx.Model(&event).Updates(map[string]interface{}{
+			"attempts":        event.Attempts,
+			"next_attempt_at": time.Now().Add(webhookLease),
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	applyErr := s.applyWebhookEvent(&event)
+	if applyErr == nil {
+		now := time.Now()
+		return true, s.db.Model(&event).Updates(map[string]interface{}{
+			"status":       models.WebhookProcessed,
+			"processed_at": now,
+			"last_error":   "",
+		}).Error
+	}
+
+	updates := map[string]interface{}{
+		"last_error":      truncateRunes(applyErr.Error(), 1000),
+		"next_attempt_at": time.Now().Add(webhookBackoff(event.Attempts)),
+	}
+	if event.Attempts >= webhookMaxAttempts || errors.Is(applyErr, ErrEventQuarantined) {
+		updates["status"] = models.WebhookDead
+	}
+	if err := s.db.Model(&event).Updates(updates).Error; err != nil {
+		return true, err
+	}
+	return true, fmt.Errorf("event %s attempt %d failed: %w", event.ID, event.Attempts, applyErr)
+}
+
+// applyWebhookEvent applies the event exactly once; see consumeOnce.
+func (s *SubscriptionService) applyWebhookEvent(event *models.WebhookEvent) error {
+	_, err := consumeOnce(s.db, event.Provider, event.EventID, event.Payload, func(tx *gorm.DB) error {
+		txs := &SubscriptionService{db: tx}
+		switch event.Provider {
+		case models.WebhookProviderRevenueCat:
+			var webhook dto.RevenueCatWebhook
+			if err := json.Unmarshal([]byte(event.Payload), &webhook); err != nil {
+				return err
+			}
+			return txs.HandleWebhookEvent(&webhook.Event)
+		case models.WebhookProviderStripe:
+			return txs.applyStripeEvent([]byte(event.Payload))
+		default:
+			return fmt.Errorf("unknown webhook provider %q", event.Provider)
+		}
+	})
+	return err
+}
+
+// ListWebhookEvents returns stored events, newest first.
+func (s *SubscriptionService) ListWebhookEvents(status string, limit, offset int) ([]models.WebhookEvent, int64, error) {
+	var events []models.WebhookEvent
+	var total int64
+
+	query := s.db.Model(&models.WebhookEvent{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&total)
+
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// ReplayWebhookEvent puts a dead-lettered event back in the queue with a
+// fresh set of attempts, releases it from quarantine and processes it
+// immediately.
+func (s *SubscriptionService) ReplayWebhookEvent(id uuid.UUID) (*models.WebhookEvent, error) {
+	var event models.WebhookEvent
+	if err := s.db.First(&event, "id = ?", id).Error; err != nil {
+		return nil, ErrWebhookEventNotFound
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.WebhookEvent{}).
+			Where("id = ? AND status = ?", id, models.WebhookDead).
+			Updates(map[string]interface{}{
+				"status":          models.WebhookPending,
+				"attempts":        0,
+				"next_attempt_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrWebhookNotDead
+		}
+		return releaseEvent(tx, event.Provider, event.EventID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ProcessWebhookEvent(id); err != nil {
+		log.Printf("webhook: replay: %v", err)
+	}
+
+	if err := s.db.First(&event, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
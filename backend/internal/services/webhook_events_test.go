@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebhookBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		7:  32 * time.Minute,
+		20: webhookMaxBackoff,
+	}
+	for attempt, want := range cases {
+		if got := webhookBackoff(attempt); got != want {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestReceiveRevenueCatWebhookRejectsInvalidJSON(t *testing.T) {
+	s := &SubscriptionService{}
+	if _, _, err := s.ReceiveRevenueCatWebhook([]byte("{not json")); err != ErrInvalidWebhookPayload {
+		t.Errorf("error = %v, want ErrInvalidWebhookPayload", err)
+	}
+}
@@ -127,10 +127,10 @@ export function AuthProvider({ children }: { children: React.ReactNode }) {
         // Check auth token
         const token = await getAccessToken();
         if (token) {
-          const { data } = await api.get('/health');
-          if (data.status === 'ok') {
-            const payload = JSON.parse(atob(token.split('.')[1]));
-            setUser({ id: payload.sub, email: payload.email });
+          // Tokens only carry the user ID; the profile has the email.
+          const { data } = await api.get('/auth/profile');
+          if (data.id) {
+            setUser({ id: data.id, email: data.email });
             // If user is authenticated, exit guest mode
             setIsGuest(false);
             setGuestDaysRemaining(null);
@@ -5,6 +5,7 @@ import axios, {
 import {
   getAccessToken,
   getRefreshToken,
+  setAccessToken,
   setTokens,
   clearTokens,
 } from './storage';
@@ -18,13 +19,23 @@ const api = axios.create({
   headers: { 'Content-Type': 'application/json' },
 });
 
-// Request interceptor: attach access token
+// requestNonce is a random value unique to one submission; the server
+// refuses scans that reuse one or send none.
+const requestNonce = () =>
+  Date.now().toString(36) +
+  Array.from({ length: 24 }, () => Math.floor(Math.random() * 36).toString(36)).join('');
+
+// Request interceptor: attach access token, and a nonce to submissions
 api.interceptors.request.use(
   async (config: InternalAxiosRequestConfig) => {
     const token = await getAccessToken();
     if (token) {
       config.headers.Authorization = `Bearer ${token}`;
     }
+    if (config.method?.toLowerCase() === 'post' && !config.headers['X-Request-Nonce']) {
+      config.headers['X-Request-Nonce'] = requestNonce();
+      config.headers['X-Request-Timestamp'] = String(Math.floor(Date.now() / 1000));
+    }
     return config;
   },
   (error) => Promise.reject(error)
@@ -49,7 +60,14 @@ const processQueue = (error: unknown, token: string | null = null) => {
 };
 
 api.interceptors.response.use(
-  (response) => response,
+  async (response) => {
+    // The server re-issues tokens signed with a rotated-out JWT secret.
+    const reissued = response.headers['x-access-token'];
+    if (typeof reissued === 'string' && reissued) {
+      await setAccessToken(reissued);
+    }
+    return response;
+  },
   async (error: AxiosError) => {
     const originalRequest = error.config as InternalAxiosRequestConfig & {
       _retry?: boolean;
@@ -9,6 +9,9 @@ export const getAccessToken = (): Promise<string | null> =>
 export const getRefreshToken = (): Promise<string | null> =>
   SecureStore.getItemAsync(REFRESH_TOKEN_KEY);
 
+export const setAccessToken = (accessToken: string): Promise<void> =>
+  SecureStore.setItemAsync(ACCESS_TOKEN_KEY, accessToken);
+
 export const setTokens = async (
   accessToken: string,
   refreshToken: string